@@ -0,0 +1,92 @@
+// DevKit Suite — watchbot, newsbot, devkit 与 API 服务合并为一个二进制
+//
+// 所有子命令共用同一份 devkit-suite.yaml 与同一个数据库连接。
+//
+// Usage:
+//
+//	devkit-suite serve               # API 服务 + WatchBot 守护进程 (单进程)
+//	devkit-suite api                 # 仅 API 服务
+//	devkit-suite watchbot <command>  # 同 watchbot
+//	devkit-suite newsbot <command>   # 同 newsbot
+//	devkit-suite devkit <command>    # 同 devkit
+//	devkit-suite config validate     # 校验 devkit-suite.yaml
+//	devkit-suite migrate status      # 应用/回滚/查看数据库迁移
+//	devkit-suite telemetry status    # 匿名使用统计 (默认关闭)
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	apicli "github.com/RobinCoderZhao/devkit-suite/internal/api/cli"
+	devkitcli "github.com/RobinCoderZhao/devkit-suite/internal/devkit/cli"
+	newsbotcli "github.com/RobinCoderZhao/devkit-suite/internal/newsbot/cli"
+	"github.com/RobinCoderZhao/devkit-suite/internal/suite"
+	watchbotcli "github.com/RobinCoderZhao/devkit-suite/internal/watchbot/cli"
+)
+
+func main() {
+	rootCmd := &cobra.Command{
+		Use:               "devkit-suite",
+		Short:             "DevKit Suite — watchbot, newsbot, devkit 与 API 服务",
+		Long:              "DevKit Suite 将 watchbot、newsbot、devkit 与 API 服务合并为一个二进制, 共用 devkit-suite.yaml 配置与数据库连接。",
+		SilenceUsage:      true,
+		PersistentPreRunE: suite.PreRun,
+	}
+
+	rootCmd.AddCommand(
+		serveCmd(),
+		apicli.Command(),
+		watchbotcli.Command(),
+		newsbotcli.Command(),
+		devkitcli.Command(),
+		devkitcli.ConfigCommand(),
+		suite.MigrateCommand(),
+		suite.TelemetryCommand(),
+	)
+
+	cmd, err := rootCmd.ExecuteC()
+	suite.Finish(cmd, err)
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// serveCmd runs the API server and the watchbot daemon in one process, on
+// one database handle, until either stops or a signal arrives.
+func serveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "API 服务 + WatchBot 守护进程 (单进程)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+			// restore default signal handling so a second signal exits now
+			context.AfterFunc(ctx, stop)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			var wg sync.WaitGroup
+			var apiErr error
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				defer cancel()
+				apiErr = apicli.Serve(ctx)
+			}()
+			go func() {
+				defer wg.Done()
+				defer cancel()
+				watchbotcli.Serve(ctx)
+			}()
+			wg.Wait()
+			return apiErr
+		},
+	}
+}
@@ -0,0 +1,25 @@
+// WatchBot MCP — 竞品监控的独立 MCP 服务, 同 watchbot mcp
+//
+// 工具: list_competitors, add_competitor, list_changes, get_timeline,
+// run_check, get_benchmark_report, compare_models, get_score
+//
+// Usage:
+//
+//	watchbot-mcp                     # stdio (Claude Desktop、Cursor 等本地 Agent)
+//	watchbot-mcp --http=:8090        # Streamable HTTP, 用 MCP_TOKEN 鉴权
+package main
+
+import (
+	"os"
+
+	"github.com/RobinCoderZhao/devkit-suite/internal/suite"
+	"github.com/RobinCoderZhao/devkit-suite/internal/watchbot/cli"
+)
+
+func main() {
+	cmd, err := cli.MCPCommand().ExecuteC()
+	suite.Finish(cmd, err)
+	if err != nil {
+		os.Exit(1)
+	}
+}
@@ -9,644 +9,31 @@
 //	watchbot unsubscribe             # 取消订阅
 //	watchbot subscribers             # 列出订阅者
 //	watchbot check                   # 运行一次全量检查
+//	watchbot benchmark-updates       # 订阅/退订定期 Benchmark 报告
+//	watchbot unmatched-models        # 列出未匹配的排行榜模型名及新模型候选
+//	watchbot quarantine              # 查看/放行被隔离的异常分数
 //	watchbot serve                   # 守护进程模式
+//	watchbot notifications           # 查看/重新发送失败的通知
+//	watchbot mcp                     # MCP 服务 (供 LLM Agent 调用)
+//	watchbot migrate                 # 应用/回滚/查看数据库迁移
+//	watchbot backup                  # 备份数据库与配置
+//	watchbot restore                 # 从备份恢复
+//	watchbot config validate         # 校验 devkit-suite.yaml
+//	watchbot telemetry status        # 匿名使用统计 (默认关闭)
 //	watchbot version                 # 显示版本
 package main
 
 import (
-	"bufio"
-	"context"
-	"fmt"
-	"log/slog"
 	"os"
-	"os/signal"
-	"strings"
-	"syscall"
-	"time"
 
-	_ "modernc.org/sqlite"
-
-	"github.com/RobinCoderZhao/devkit-suite/internal/watchbot"
-	"github.com/RobinCoderZhao/devkit-suite/pkg/benchmarks"
-	"github.com/RobinCoderZhao/devkit-suite/pkg/benchmarks/parsers"
-	"github.com/RobinCoderZhao/devkit-suite/pkg/llm"
-	"github.com/RobinCoderZhao/devkit-suite/pkg/notify"
-	"github.com/RobinCoderZhao/devkit-suite/pkg/scraper"
-	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
+	"github.com/RobinCoderZhao/devkit-suite/internal/suite"
+	"github.com/RobinCoderZhao/devkit-suite/internal/watchbot/cli"
 )
 
-var version = "2.0.0"
-
 func main() {
-	if len(os.Args) < 2 {
-		printUsage()
-		os.Exit(1)
-	}
-
-	switch os.Args[1] {
-	case "add":
-		cmdAdd()
-	case "remove":
-		cmdRemove()
-	case "list":
-		cmdList()
-	case "check":
-		cmdCheck()
-	case "benchmark":
-		cmdBenchmark()
-	case "serve":
-		cmdServe()
-	case "version":
-		fmt.Printf("watchbot %s\n", version)
-	default:
-		fmt.Printf("Unknown command: %s\n", os.Args[1])
-		printUsage()
-		os.Exit(1)
-	}
-}
-
-func printUsage() {
-	fmt.Println(`WatchBot — 竞品监控 (SaaS 后端引擎)
-
-Usage:
-  watchbot add <url-or-text>                     添加监控目标 (分配给本地默认用户)
-  watchbot remove --name=<name>                  删除竞品
-  watchbot list                                  列出所有竞品
-  watchbot check                                 运行一次全量检查
-  watchbot benchmark [--output=png|html|text]    模型 Benchmark 对比
-  watchbot serve                                 守护进程模式
-  watchbot version                               版本`)
-}
-
-// --- Database ---
-
-func openDB() (*storage.DB, *watchbot.Store) {
-	dbPath := getEnv("WATCHBOT_DB", "data/watchbot.db")
-	db, err := storage.Open(storage.Config{Driver: storage.SQLite, DSN: dbPath})
-	if err != nil {
-		slog.Error("open database failed", "path", dbPath, "error", err)
-		os.Exit(1)
-	}
-
-	// Auto-migrate schema on startup
-	schemaContent, err := os.ReadFile("pkg/storage/schema.sql")
-	if err == nil {
-		ctx := context.Background()
-		if err := db.Migrate(ctx, string(schemaContent)); err != nil {
-			slog.Error("schema migration failed", "error", err)
-			os.Exit(1)
-		}
-
-		// Ensure a default user exists for CLI operations
-		_, _ = db.ExecContext(ctx, `INSERT INTO users (id, email, password_hash, plan) VALUES (1, 'cli@local', 'cli_hash', 'pro') ON CONFLICT DO NOTHING`)
-	} else {
-		slog.Warn("schema.sql not found, skipping migration", "error", err)
-	}
-
-	store := watchbot.NewStore(db)
-
-	return db, store
-}
-
-// --- LLM ---
-
-func newLLMClient() llm.Client {
-	apiKey := os.Getenv("LLM_API_KEY")
-	if apiKey == "" {
-		return nil
-	}
-	cfg := llm.Config{
-		Provider:    llm.Provider(getEnv("LLM_PROVIDER", "openai")),
-		Model:       getEnv("LLM_MODEL", "gpt-4o-mini"),
-		APIKey:      apiKey,
-		MaxRetries:  3,
-		Timeout:     60 * time.Second,
-		Temperature: 0.3,
-	}
-	if cfg.Provider == "minimax" {
-		cfg.BaseURL = "https://api.minimax.io/v1"
-	}
-	client, err := llm.NewClient(cfg)
-	if err != nil {
-		slog.Warn("LLM client creation failed", "error", err)
-		return nil
-	}
-	return client
-}
-
-// --- Commands ---
-
-func cmdAdd() {
-	if len(os.Args) < 3 {
-		fmt.Println("Usage: watchbot add <url-or-natural-language>")
-		fmt.Println("Examples:")
-		fmt.Println("  watchbot add https://stripe.com/pricing")
-		fmt.Println(`  watchbot add "监控 Gemini API 文档变化"`)
-		os.Exit(1)
-	}
-
-	input := strings.Join(os.Args[2:], " ")
-	ctx := context.Background()
-	db, store := openDB()
-	defer db.Close()
-
-	if watchbot.IsURL(input) {
-		// Direct URL mode
-		fmt.Printf("🔍 验证 URL: %s\n", input)
-		vr := watchbot.ValidateURL(ctx, input)
-		if !vr.Valid {
-			fmt.Printf("❌ URL 无效: %s\n", vr.Error)
-			if vr.URL != "" {
-				fmt.Printf("   标准化后: %s\n", vr.URL)
-			}
-			os.Exit(1)
-		}
-		domain := watchbot.ExtractDomain(vr.URL)
-		pageType := watchbot.GuessPageType(vr.URL)
-		name := promptInput(fmt.Sprintf("竞品名称 (默认: %s): ", domain))
-		if name == "" {
-			name = domain
-		}
-		compID, _ := store.AddCompetitor(ctx, 1, name, domain) // Hardcode userID 1 for CLI
-		_, _ = store.AddPage(ctx, compID, vr.URL, pageType)
-		fmt.Printf("✅ 已添加: %s [%s] %s\n", name, pageType, vr.URL)
-	} else {
-		// Natural language mode
-		llmClient := newLLMClient()
-		if llmClient == nil {
-			fmt.Println("❌ 自然语言模式需要配置 LLM_API_KEY")
-			fmt.Println("   或者直接使用 URL: watchbot add https://...")
-			os.Exit(1)
-		}
-		defer llmClient.Close()
-
-		resolver := watchbot.NewResolver(llmClient, watchbot.ResolverConfig{
-			GoogleAPIKey: os.Getenv("GOOGLE_API_KEY"),
-			GoogleCX:     os.Getenv("GOOGLE_CX"),
-			BingAPIKey:   os.Getenv("BING_API_KEY"),
-		})
-
-		fmt.Printf("🤖 分析: \"%s\"\n", input)
-		result, err := resolver.Resolve(ctx, input)
-		if err != nil {
-			fmt.Printf("❌ 解析失败: %v\n", err)
-			os.Exit(1)
-		}
-
-		if result.Error != "" {
-			fmt.Printf("❌ %s\n", result.Error)
-			fmt.Println("   请提供具体信息，例如：")
-			fmt.Println(`   watchbot add "监控 OpenAI API 文档变化"`)
-			fmt.Println("   watchbot add https://openai.com/pricing")
-			os.Exit(1)
-		}
-
-		if len(result.URLs) == 0 {
-			fmt.Printf("🤔 识别到产品: %s，但无法确定 URL\n", result.Name)
-			fmt.Println("   请手动输入 URL：watchbot add <url>")
-			os.Exit(1)
-		}
-
-		// Show candidate and ask for confirmation
-		fmt.Printf("\n🤖 建议监控 (来源: %s)：\n", result.Source)
-		fmt.Printf("  [%s] %s\n", result.PageType, result.Name)
-		for _, u := range result.URLs {
-			fmt.Printf("  %s\n", u)
-		}
-		confirm := promptInput("\n确认添加？[Y/n]: ")
-		if confirm != "" && strings.ToLower(confirm) != "y" {
-			fmt.Println("已取消")
-			return
-		}
-
-		domain := watchbot.ExtractDomain(result.URLs[0])
-		compID, _ := store.AddCompetitor(ctx, 1, result.Name, domain) // Hardcode userID 1
-		for _, u := range result.URLs {
-			pageType := watchbot.GuessPageType(u)
-			_, _ = store.AddPage(ctx, compID, u, pageType)
-		}
-		fmt.Printf("✅ 已添加: %s (%d 个页面)\n", result.Name, len(result.URLs))
-	}
-}
-
-func cmdRemove() {
-	name := getFlag("--name")
-	if name == "" {
-		fmt.Println("Usage: watchbot remove --name=<competitor-name>")
-		os.Exit(1)
-	}
-	ctx := context.Background()
-	db, _ := openDB()
-	defer db.Close()
-
-	if _, err := db.ExecContext(ctx, `DELETE FROM competitors WHERE name = ? COLLATE NOCASE`, name); err != nil {
-		fmt.Printf("❌ 删除失败: %v\n", err)
-		os.Exit(1)
-	}
-	fmt.Printf("✅ 已删除: %s\n", name)
-}
-
-func cmdList() {
-	ctx := context.Background()
-	db, store := openDB()
-	defer db.Close()
-
-	competitors, err := store.ListCompetitorsByUser(ctx, 1) // Hardcode userID 1
-	if err != nil {
-		slog.Error("list failed", "error", err)
-		os.Exit(1)
-	}
-
-	if len(competitors) == 0 {
-		fmt.Println("暂无监控目标。使用 watchbot add <url> 添加。")
-		return
-	}
-
-	fmt.Printf("本地用户(ID=1) 的监控目标 (%d):\n\n", len(competitors))
-	for i, c := range competitors {
-		fmt.Printf("  %d. %s (%s)\n", i+1, c.Name, c.Domain)
-
-		// Query pages manually since we removed it from store.go
-		rows, _ := db.QueryContext(ctx, `SELECT url, page_type, last_checked_at FROM pages WHERE competitor_id = ?`, c.ID)
-		for rows.Next() {
-			var url, pageType string
-			var lastChecked *time.Time
-			_ = rows.Scan(&url, &pageType, &lastChecked)
-
-			checked := "未检查"
-			if lastChecked != nil {
-				checked = lastChecked.Format("2006-01-02 15:04")
-			}
-			fmt.Printf("     ✅ [%s] %s (最后检查: %s)\n", pageType, url, checked)
-		}
-		rows.Close()
-		fmt.Println()
-	}
-}
-
-func cmdCheck() {
-	ctx := context.Background()
-	db, store := openDB()
-	defer db.Close()
-
-	// Use Pro tier for change analysis (higher quality)
-	llmClient, err := llm.NewTieredClient(llm.TierPro)
-	if err != nil {
-		slog.Warn("LLM client not available", "error", err)
-	}
-	if llmClient != nil {
-		defer llmClient.Close()
-	}
-
-	fetcher := scraper.NewHTTPFetcher()
-	dispatcher := notify.NewDispatcher()
-
-	// Setup email
-	var channels []notify.Channel
-	emailCfg := loadEmailConfig()
-	if emailCfg.SMTPHost != "" {
-		dispatcher.SetEmailConfig(emailCfg)
-	}
-
-	// Setup Telegram
-	tgToken := os.Getenv("TELEGRAM_BOT_TOKEN")
-	if tgToken != "" {
-		dispatcher.Register(notify.NewTelegramNotifier(notify.TelegramConfig{
-			BotToken:  tgToken,
-			ChannelID: os.Getenv("TELEGRAM_CHANNEL_ID"),
-		}))
-		channels = append(channels, notify.ChannelTelegram)
-	}
-
-	pipeline := watchbot.NewGlobalPipeline(store, fetcher, llmClient, dispatcher, channels)
-	if err := pipeline.RunCheck(ctx); err != nil {
-		slog.Error("check failed", "error", err)
-		os.Exit(1)
-	}
-}
-
-func cmdServe() {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-	go func() {
-		<-sigCh
-		slog.Info("shutdown signal received")
-		cancel()
-	}()
-
-	db, _ := openDB()
-	defer db.Close()
-
-	// ---- Benchmark Tracker background thread ----
-	bStore, err := benchmarks.NewStore(db.DB)
-	if err == nil {
-		configPath := getEnv("BENCHMARK_CONFIG", "config/benchmark_models.yaml")
-		cfg, _ := benchmarks.LoadConfig(configPath)
-		if cfg == nil {
-			cfg = &benchmarks.Config{Models: benchmarks.DefaultModels}
-		}
-
-		fetcher := scraper.NewHTTPFetcher()
-		var bParsers []benchmarks.Parser
-		allModels := append(cfg.Models, benchmarks.FallbackModels...)
-		bParsers = append(bParsers, parsers.NewLLMStatsParser(fetcher, allModels))
-
-		// Seed data if empty
-		count, _ := bStore.ScoreCount(ctx)
-		if count == 0 {
-			seeds := benchmarks.SeedFromScreenshot()
-			seedScraper := benchmarks.NewScraper(bStore, benchmarks.NewManualParser(seeds))
-			n, _ := seedScraper.ScrapeAll(ctx)
-			slog.Info("benchmark seed loaded", "scores", n)
-		}
-
-		bScraper := benchmarks.NewScraper(bStore, bParsers...)
-		tracker := benchmarks.NewTracker(bStore, bScraper, 12*time.Hour)
-		tracker.OnUpdate = func(report *benchmarks.BenchmarkReport) {
-			slog.Info("benchmark data updated",
-				"models", len(report.Models),
-				"date", report.Date,
-			)
-			// Render PNG for potential notification
-			pngPath := "/tmp/benchmark_report.png"
-			renderer := benchmarks.NewImageRenderer()
-			if err := renderer.RenderPNG(report, pngPath); err != nil {
-				slog.Error("benchmark render", "error", err)
-			} else {
-				slog.Info("benchmark PNG rendered", "path", pngPath)
-			}
-		}
-
-		go tracker.Run(ctx, cfg.Models)
-		slog.Info("benchmark tracker started", "interval", "12h")
-	}
-
-	// ---- WatchBot check loop ----
-	interval := 6 * time.Hour
-	slog.Info("WatchBot serving", "interval", interval)
-
-	// Run immediately
-	cmdCheck()
-
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			cmdCheck()
-		}
-	}
-}
-
-func cmdBenchmark() {
-	ctx := context.Background()
-	db, _ := openDB()
-	defer db.Close()
-
-	// Init benchmark store
-	bStore, err := benchmarks.NewStore(db.DB)
+	cmd, err := cli.Command().ExecuteC()
+	suite.Finish(cmd, err)
 	if err != nil {
-		slog.Error("init benchmark store", "error", err)
 		os.Exit(1)
 	}
-
-	// Load model config
-	configPath := getEnv("BENCHMARK_CONFIG", "config/benchmark_models.yaml")
-	cfg, err := benchmarks.LoadConfig(configPath)
-	if err != nil {
-		slog.Warn("load benchmark config", "error", err)
-		cfg = &benchmarks.Config{Models: benchmarks.DefaultModels}
-	}
-
-	// CLI model override
-	if modelsFlag := getFlag("--models"); modelsFlag != "" {
-		cfg.Models = benchmarks.ParseModelsCLI(modelsFlag)
-	}
-	if addFlag := getFlag("--add-model"); addFlag != "" {
-		cfg.Models = benchmarks.AddModel(cfg.Models, addFlag)
-	}
-
-	// Seed data on first run or scrape from live sources
-	scrapeMode := getFlag("--scrape")
-	count, _ := bStore.ScoreCount(ctx)
-	if count == 0 || scrapeMode != "" {
-		// Always load seed data if DB is empty
-		if count == 0 || scrapeMode == "seed" || scrapeMode == "true" {
-			fmt.Println("📊 Loading seed benchmark data...")
-			seeds := benchmarks.SeedFromScreenshot()
-			s := benchmarks.NewScraper(bStore, benchmarks.NewManualParser(seeds))
-			n, err := s.ScrapeAll(ctx)
-			if err != nil {
-				slog.Warn("seed", "error", err)
-			}
-			fmt.Printf("   ✅ %d seed scores loaded\n", n)
-		}
-
-		// Live scrape from real sources
-		if scrapeMode == "true" || scrapeMode == "live" {
-			fmt.Println("🌐 Scraping live benchmark data...")
-			fetcher := scraper.NewHTTPFetcher()
-
-			var liveParsers []benchmarks.Parser
-			allModels := append(cfg.Models, benchmarks.FallbackModels...)
-			liveParsers = append(liveParsers, parsers.NewLLMStatsParser(fetcher, allModels))
-
-			// Add LLM extractor if LLM client is available
-			llmClient := newLLMClient()
-			if llmClient != nil {
-				liveParsers = append(liveParsers, parsers.NewLLMExtractor(llmClient, fetcher, cfg.Models))
-				defer llmClient.Close()
-			}
-
-			s := benchmarks.NewScraper(bStore, liveParsers...)
-			n, err := s.ScrapeAll(ctx)
-			if err != nil {
-				slog.Warn("live scrape", "error", err)
-			}
-			fmt.Printf("   ✅ %d live scores scraped\n", n)
-		}
-	}
-
-	// Build report
-	date := time.Now().Format("2006-01-02")
-	report, err := bStore.GetScoresForReport(ctx, cfg.Models, date)
-	if err != nil {
-		slog.Error("build report", "error", err)
-		os.Exit(1)
-	}
-
-	// Filter empty models (min 1 score, min 10 models)
-	report.FilterEmptyModels(3, 10)
-
-	fmt.Printf("📊 Benchmark Report: %d benchmarks × %d models\n\n", len(benchmarks.AllBenchmarks), len(report.Models))
-
-	// Output
-	output := getFlag("--output")
-	filePath := getFlag("--file")
-
-	switch output {
-	case "png":
-		if filePath == "" {
-			filePath = "benchmark_report.png"
-		}
-		renderer := benchmarks.NewImageRenderer()
-		if err := renderer.RenderPNG(report, filePath); err != nil {
-			slog.Error("render PNG", "error", err)
-			os.Exit(1)
-		}
-		fmt.Printf("✅ PNG saved: %s\n", filePath)
-
-	case "html":
-		renderer := benchmarks.NewHTMLRenderer()
-		htmlContent := renderer.RenderHTML(report)
-		if filePath == "" {
-			filePath = "benchmark_report.html"
-		}
-		if err := os.WriteFile(filePath, []byte(htmlContent), 0644); err != nil {
-			slog.Error("write HTML", "error", err)
-			os.Exit(1)
-		}
-		fmt.Printf("✅ HTML saved: %s\n", filePath)
-
-	default:
-		// Terminal table output
-		printTerminalTable(report)
-	}
-
-	// Send benchmark email if SMTP is configured and recipient specified
-	emailTo := getFlag("--email")
-	if emailTo == "" {
-		emailTo = os.Getenv("SMTP_TO")
-	}
-	emailCfg := notify.EmailConfig{
-		SMTPHost: os.Getenv("SMTP_HOST"),
-		SMTPPort: os.Getenv("SMTP_PORT"),
-		From:     os.Getenv("SMTP_FROM"),
-		Password: os.Getenv("SMTP_PASSWORD"),
-		To:       emailTo,
-	}
-	if emailTo != "" && emailCfg.Password != "" {
-		renderer := benchmarks.NewHTMLRenderer()
-		htmlTable := renderer.RenderHTML(report)
-		scoreCount, _ := bStore.ScoreCount(ctx)
-
-		data := notify.BenchmarkDigestData{
-			Report:     report,
-			HTMLTable:  htmlTable,
-			ScoreCount: scoreCount,
-			Date:       date,
-		}
-		formatter := notify.NewBenchmarkEmailFormatter()
-		msg := formatter.Format(data)
-
-		emailNotifier := notify.NewEmailNotifierForRecipient(emailCfg, emailTo)
-		if err := emailNotifier.Send(ctx, msg); err != nil {
-			slog.Error("benchmark email send failed", "email", emailTo, "error", err)
-		} else {
-			fmt.Printf("📧 Benchmark report emailed to %s\n", emailTo)
-		}
-	}
-}
-
-func printTerminalTable(report *benchmarks.BenchmarkReport) {
-	// Header
-	fmt.Printf("%-25s", "Benchmark")
-	for _, m := range report.Models {
-		name := m.Name
-		if len(name) > 16 {
-			name = name[:16]
-		}
-		fmt.Printf(" %16s", name)
-	}
-	fmt.Println()
-	fmt.Println(strings.Repeat("─", 25+17*len(report.Models)))
-
-	for _, cat := range benchmarks.Categories {
-		var benches []benchmarks.BenchmarkDef
-		for _, b := range benchmarks.AllBenchmarks {
-			if b.Category == cat.ID {
-				benches = append(benches, b)
-			}
-		}
-		if len(benches) == 0 {
-			continue
-		}
-		fmt.Printf("%s %s\n", cat.Emoji, cat.Label)
-
-		for _, bench := range benches {
-			variants := bench.Variants
-			if len(variants) == 0 {
-				variants = []string{""}
-			}
-			for _, v := range variants {
-				label := bench.Name
-				if v != "" {
-					label = fmt.Sprintf("  %s", v)
-				}
-				if len(label) > 24 {
-					label = label[:24]
-				}
-				fmt.Printf("%-25s", label)
-				for _, m := range report.Models {
-					score, exists := report.GetScore(bench.ID, v, m.Name)
-					if !exists {
-						fmt.Printf(" %16s", "—")
-					} else {
-						scoreStr := fmt.Sprintf("%.1f%%", score)
-						if bench.Unit == "Elo" {
-							scoreStr = fmt.Sprintf("%d", int(score))
-						}
-						if report.IsHighest(bench.ID, v, m.Name) {
-							scoreStr = "🔴" + scoreStr
-						}
-						fmt.Printf(" %16s", scoreStr)
-					}
-				}
-				fmt.Println()
-			}
-		}
-	}
-}
-
-// --- Helpers ---
-
-func loadEmailConfig() notify.EmailConfig {
-	return notify.EmailConfig{
-		SMTPHost: getEnv("SMTP_HOST", ""),
-		SMTPPort: getEnv("SMTP_PORT", "587"),
-		From:     os.Getenv("SMTP_FROM"),
-		Password: os.Getenv("SMTP_PASSWORD"),
-	}
-}
-
-func getEnv(key, fallback string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
-	}
-	return fallback
-}
-
-func getFlag(name string) string {
-	prefix := name + "="
-	for _, arg := range os.Args[2:] {
-		if strings.HasPrefix(arg, prefix) {
-			return strings.TrimPrefix(arg, prefix)
-		}
-	}
-	return ""
-}
-
-func promptInput(prompt string) string {
-	fmt.Print(prompt)
-	scanner := bufio.NewScanner(os.Stdin)
-	if scanner.Scan() {
-		return strings.TrimSpace(scanner.Text())
-	}
-	return ""
 }
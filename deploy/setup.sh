@@ -477,6 +477,8 @@ EnvironmentFile=${ENV_FILE}
 ExecStart=${APP_DIR}/bin/watchbot serve
 Restart=always
 RestartSec=30
+# SIGTERM drains the running check (WATCHBOT_DRAIN_TIMEOUT, 2m) before exit
+TimeoutStopSec=150
 StandardOutput=journal
 StandardError=journal
 SyslogIdentifier=watchbot
@@ -499,6 +501,8 @@ EnvironmentFile=${ENV_FILE}
 ExecStart=${APP_DIR}/bin/api
 Restart=always
 RestartSec=30
+# SIGTERM drains in-flight requests (API_SHUTDOWN_TIMEOUT, 30s) before exit
+TimeoutStopSec=45
 StandardOutput=journal
 StandardError=journal
 SyslogIdentifier=devkit-api
@@ -35,22 +35,8 @@ func (s *Server) generateToken(userID int) (string, error) {
 	return token.SignedString(s.jwtSecret)
 }
 
-// requireAuth is a middleware that verifies the JWT token.
-// Note: This wraps the entire mux or specific routes.
-// For finer control, we use requireAuthHandler on specific routes.
-func (s *Server) requireAuth(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Skip auth for public routes (a bit hacky, cleaner to attach middleware per route)
-		if strings.HasPrefix(r.URL.Path, "/api/auth/") {
-			next.ServeHTTP(w, r)
-			return
-		}
-
-		s.requireAuthHandler(next).ServeHTTP(w, r)
-	})
-}
-
-// requireAuthHandler applies auth check to a specific handler.
+// requireAuthHandler applies auth check to a specific handler. Routes wraps
+// every route not marked public with it.
 func (s *Server) requireAuthHandler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// 1. Check Authorization header (Bearer <token>)
@@ -0,0 +1,213 @@
+// Package cli implements the API server command. cmd/api runs it on its own
+// and cmd/devkit-suite as a subcommand.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/internal/api"
+	newsbotstore "github.com/RobinCoderZhao/devkit-suite/internal/newsbot/store"
+	"github.com/RobinCoderZhao/devkit-suite/internal/suite"
+	"github.com/RobinCoderZhao/devkit-suite/internal/user"
+	"github.com/RobinCoderZhao/devkit-suite/internal/watchbot"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/benchmarks"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/notify"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/ratelimit"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
+	"github.com/spf13/cobra"
+	_ "modernc.org/sqlite"
+)
+
+// Command builds the command that runs the REST API server.
+func Command() *cobra.Command {
+	return &cobra.Command{
+		Use:               "api",
+		Short:             "Run the REST API server",
+		Long:              "Run the REST API server on API_PORT (default 8080) until SIGINT or SIGTERM, then stop accepting connections and let in-flight requests finish for up to API_SHUTDOWN_TIMEOUT (default 30s). A second signal exits immediately. Prometheus metrics are served at /metrics; set METRICS_TOKEN to require it as a bearer token. Requests are rate limited per client IP (API_RATE_LIMIT_IP, default 600/m) and per signed-in user by plan (API_RATE_LIMIT_FREE, default 60/m; API_RATE_LIMIT_PRO, default 600/m); set API_TRUST_PROXY=true behind a reverse proxy.",
+		Args:              cobra.NoArgs,
+		SilenceUsage:      true,
+		PersistentPreRunE: suite.PreRun,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+			// restore default signal handling so a second signal exits now
+			context.AfterFunc(ctx, stop)
+			return Serve(ctx)
+		},
+	}
+}
+
+// Serve runs the REST API server on the shared database until ctx is done,
+// then shuts it down gracefully.
+func Serve(ctx context.Context) error {
+	port := getEnv("API_PORT", "8080")
+	jwtSecret := getEnv("JWT_SECRET", "super-secret-devkit-jwt-key")
+
+	db, err := suite.DB()
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+
+	// Initial Migration
+	if err := db.Migrate(ctx); err != nil {
+		return fmt.Errorf("schema migration: %w", err)
+	}
+
+	box, err := storage.SecretBoxFromEnv()
+	if err != nil {
+		return fmt.Errorf("load secret key: %w", err)
+	}
+	uStore := user.NewStore(db)
+	uStore.SetSecretBox(box)
+	wStore := watchbot.NewStore(db)
+	wStore.SetSecretBox(box)
+
+	server := api.NewServer(uStore, wStore, jwtSecret)
+	server.SetAdmins(parseIDs(os.Getenv("ADMIN_USER_IDS")))
+	server.SetDB(db)
+	server.SetFrontendURL(getEnv("FRONTEND_URL", "http://localhost:3000"))
+	server.SetMetricsToken(os.Getenv("METRICS_TOKEN"))
+	// Without the benchmark tables the benchmark endpoints answer 503
+	if bStore, err := benchmarks.NewStore(db); err != nil {
+		slog.Warn("benchmark endpoints disabled", "error", err)
+	} else {
+		server.SetBenchmarkStore(bStore)
+	}
+	// NewsBot keeps its subscribers in its own SQLite file; without it the
+	// subscription endpoints answer 503
+	if nStore, err := newsbotstore.New(getEnv("NEWSBOT_DB", "newsbot.db")); err != nil {
+		slog.Warn("newsbot endpoints disabled", "error", err)
+	} else {
+		defer nStore.Close()
+		server.SetNewsBotStore(nStore)
+	}
+	if err := setRateLimits(server); err != nil {
+		return err
+	}
+	if password := os.Getenv("SMTP_PASSWORD"); password != "" {
+		server.SetEmailConfig(notify.EmailConfig{
+			SMTPHost: getEnv("SMTP_HOST", ""),
+			SMTPPort: getEnv("SMTP_PORT", "587"),
+			From:     os.Getenv("SMTP_FROM"),
+			Password: password,
+		})
+	}
+	mux := server.Routes()
+
+	// Add CORS middleware
+	handler := corsMiddleware(mux)
+
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: handler,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		slog.Info("Starting REST API Server", "port", port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+		}
+	}()
+
+	// Graceful shutdown
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("server failed: %w", err)
+	case <-ctx.Done():
+	}
+	timeout := shutdownTimeout()
+	slog.Info("Shutting down server, draining in-flight requests", "timeout", timeout)
+
+	// Shutdown stops accepting connections at once and waits for handlers,
+	// including their LLM calls, to return.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Server forced to shutdown", "error", err)
+	}
+	return nil
+}
+
+// setRateLimits applies API_RATE_LIMIT_IP, API_RATE_LIMIT_FREE,
+// API_RATE_LIMIT_PRO and API_TRUST_PROXY over the defaults.
+func setRateLimits(server *api.Server) error {
+	perIP := api.DefaultIPRateLimit
+	if v := os.Getenv("API_RATE_LIMIT_IP"); v != "" {
+		l, err := ratelimit.ParseLimit(v)
+		if err != nil {
+			return fmt.Errorf("API_RATE_LIMIT_IP: %w", err)
+		}
+		perIP = l
+	}
+	perPlan := make(map[string]ratelimit.Limit, len(api.DefaultPlanRateLimits))
+	for plan, l := range api.DefaultPlanRateLimits {
+		env := "API_RATE_LIMIT_" + strings.ToUpper(plan)
+		if v := os.Getenv(env); v != "" {
+			var err error
+			if l, err = ratelimit.ParseLimit(v); err != nil {
+				return fmt.Errorf("%s: %w", env, err)
+			}
+		}
+		perPlan[plan] = l
+	}
+	server.SetRateLimits(perIP, perPlan)
+	server.SetTrustProxy(os.Getenv("API_TRUST_PROXY") == "true")
+	slog.Info("API rate limits", "ip", perIP, "free", perPlan["free"], "pro", perPlan["pro"])
+	return nil
+}
+
+// shutdownTimeout reads API_SHUTDOWN_TIMEOUT, default 30s.
+func shutdownTimeout() time.Duration {
+	if s := os.Getenv("API_SHUTDOWN_TIMEOUT"); s != "" {
+		if d, err := time.ParseDuration(s); err == nil && d > 0 {
+			return d
+		}
+		slog.Warn("invalid API_SHUTDOWN_TIMEOUT, using 30s", "value", s)
+	}
+	return 30 * time.Second
+}
+
+func getEnv(key, fallback string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return fallback
+}
+
+// corsMiddleware simple middleware to allow Dev Next.js local development
+func corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000") // Next.js default port
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parseIDs parses a comma-separated list of user IDs, skipping invalid entries.
+func parseIDs(s string) []int {
+	var ids []int
+	for _, part := range strings.Split(s, ",") {
+		if id, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
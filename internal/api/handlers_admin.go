@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
+)
+
+// DBStatsResponse is the database driver and its connection pool state.
+type DBStatsResponse struct {
+	Driver storage.Driver    `json:"driver"`
+	Pool   storage.PoolStats `json:"pool"`
+}
+
+// handleDBStats reports connection pool statistics (admin only), to help
+// diagnose "database is locked" errors and pool exhaustion.
+func (s *Server) handleDBStats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.isAdmin(getUserID(r)) {
+			respondError(w, http.StatusForbidden, "Admin access required")
+			return
+		}
+		if s.db == nil {
+			respondError(w, http.StatusServiceUnavailable, "Database stats unavailable")
+			return
+		}
+
+		respondJSON(w, http.StatusOK, DBStatsResponse{
+			Driver: s.db.DriverType(),
+			Pool:   s.db.PoolStats(),
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
+)
+
+// getAs runs handler for a GET request made by userID, as requireAuth
+// would after checking the token.
+func getAs(handler http.Handler, userID int, target string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, userID))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandleDBStats(t *testing.T) {
+	db, err := storage.Open(storage.Config{Driver: storage.SQLite, DSN: filepath.Join(t.TempDir(), "api.db"), MaxOpenConns: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.PingContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewServer(nil, nil, "secret")
+	s.SetAdmins([]int{1})
+	s.SetDB(db)
+
+	rec := getAs(s.handleDBStats(), 1, "/api/admin/db-stats")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(body["driver"]); got != `"sqlite"` {
+		t.Errorf("driver = %s, want \"sqlite\"", got)
+	}
+	var pool map[string]int64
+	if err := json.Unmarshal(body["pool"], &pool); err != nil {
+		t.Fatalf("pool %s: %v", body["pool"], err)
+	}
+	var keys []string
+	for k := range pool {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	want := []string{"idle", "in_use", "max_idle_closed", "max_lifetime_closed", "max_open", "open", "wait_count", "wait_duration_ns"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("pool fields = %v, want %v", keys, want)
+	}
+	if pool["max_open"] != 3 || pool["open"] < 1 || pool["open"] != pool["in_use"]+pool["idle"] {
+		t.Errorf("pool = %v", pool)
+	}
+
+	// Only admins see it, and only when the server has the database
+	if rec := getAs(s.handleDBStats(), 2, "/api/admin/db-stats"); rec.Code != http.StatusForbidden {
+		t.Errorf("non-admin status = %d, want 403", rec.Code)
+	}
+	s.SetDB(nil)
+	if rec := getAs(s.handleDBStats(), 1, "/api/admin/db-stats"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status without a database = %d, want 503", rec.Code)
+	}
+}
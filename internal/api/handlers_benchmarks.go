@@ -0,0 +1,94 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/benchmarks"
+)
+
+// handleBenchmarkTrend returns each model's daily score history on a
+// benchmark, as JSON or, with ?format=png, as a line chart.
+// Query: ?benchmark= (ID or name), ?variant= (default the first), ?model=
+// (repeatable or comma-separated; default every model with history).
+func (s *Server) handleBenchmarkTrend() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.benchStore == nil {
+			respondError(w, http.StatusServiceUnavailable, "Benchmarks unavailable")
+			return
+		}
+
+		q := r.URL.Query()
+		bench := benchmarks.LookupBenchmark(q.Get("benchmark"))
+		if bench == nil {
+			respondError(w, http.StatusBadRequest, "Unknown benchmark")
+			return
+		}
+		variant, ok := trendVariant(bench, q.Get("variant"))
+		if !ok {
+			respondError(w, http.StatusBadRequest, "Unknown benchmark variant")
+			return
+		}
+		format := q.Get("format")
+		if format != "" && format != "json" && format != "png" {
+			respondError(w, http.StatusBadRequest, "format must be json or png")
+			return
+		}
+
+		var models []string
+		for _, v := range q["model"] {
+			for _, name := range strings.Split(v, ",") {
+				name = strings.TrimSpace(name)
+				if alias, ok := benchmarks.ModelAlias(strings.ToLower(name)); ok {
+					name = alias
+				}
+				if name != "" {
+					models = append(models, name)
+				}
+			}
+		}
+
+		report, err := s.benchStore.Trend(r.Context(), bench.ID, variant, models...)
+		if err != nil {
+			s.logger.Error("failed to load benchmark trend", "benchmark", bench.ID, "error", err)
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		if len(report.Series) == 0 {
+			respondError(w, http.StatusNotFound, "No score history")
+			return
+		}
+
+		if format != "png" {
+			respondJSON(w, http.StatusOK, report)
+			return
+		}
+		// Render fully before writing, so a failure can still be an error
+		var buf bytes.Buffer
+		if err := report.RenderPNG(&buf); err != nil {
+			s.logger.Error("failed to render benchmark trend", "benchmark", bench.ID, "error", err)
+			respondError(w, http.StatusInternalServerError, "Failed to render chart")
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(buf.Bytes())
+	}
+}
+
+// trendVariant returns the variant of bench the trend is for: the named
+// one, ignoring case, or the first when none is named.
+func trendVariant(bench *benchmarks.BenchmarkDef, name string) (string, bool) {
+	if name == "" {
+		if len(bench.Variants) > 0 {
+			return bench.Variants[0], true
+		}
+		return "", true
+	}
+	for _, v := range bench.Variants {
+		if strings.EqualFold(v, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
@@ -11,6 +11,16 @@ import (
 	"github.com/stripe/stripe-go/v81/webhook"
 )
 
+// CheckoutRequest selects the Stripe price to subscribe to.
+type CheckoutRequest struct {
+	PriceID string `json:"price_id"`
+}
+
+// CheckoutResponse is the Stripe Checkout page to send the user to.
+type CheckoutResponse struct {
+	URL string `json:"url"`
+}
+
 // handleCreateCheckoutSession initiates a Stripe Checkout for a specific plan.
 func (s *Server) handleCreateCheckoutSession() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -28,9 +38,7 @@ func (s *Server) handleCreateCheckoutSession() http.HandlerFunc {
 			return
 		}
 
-		var req struct {
-			PriceID string `json:"price_id"`
-		}
+		var req CheckoutRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			respondError(w, http.StatusBadRequest, "Invalid request body")
 			return
@@ -51,9 +59,7 @@ func (s *Server) handleCreateCheckoutSession() http.HandlerFunc {
 			return
 		}
 
-		respondJSON(w, http.StatusOK, map[string]string{
-			"url": url,
-		})
+		respondJSON(w, http.StatusOK, CheckoutResponse{URL: url})
 	}
 }
 
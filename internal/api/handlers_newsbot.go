@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/internal/newsbot/store"
 )
 
 type NewsItem struct {
@@ -16,24 +18,56 @@ type NewsItem struct {
 	PublishedAt time.Time `json:"published_at"`
 }
 
+// NewsFeedResponse is the news feed and the user's NewsBot subscriptions.
+type NewsFeedResponse struct {
+	Feed              []NewsItem `json:"feed"`
+	IsSubscribed      bool       `json:"is_subscribed"`
+	SubscriptionLangs string     `json:"subscription_langs"`
+	Subscriptions     any        `json:"subscriptions"`
+}
+
+// NewsBotSubscribeRequest subscribes a target, an email address by default,
+// to the NewsBot digest.
+type NewsBotSubscribeRequest struct {
+	TargetType string `json:"target_type"`
+	TargetID   string `json:"target_id"`
+	Languages  string `json:"languages"`
+}
+
+// NewsBotSubscriptionsResponse lists the user's NewsBot subscriptions.
+type NewsBotSubscriptionsResponse struct {
+	Subscriptions []store.Subscriber `json:"subscriptions"`
+}
+
+// requireNewsBot answers 503 when no NewsBot store is configured.
+func (s *Server) requireNewsBot(w http.ResponseWriter) bool {
+	if s.newsbotStore == nil {
+		respondError(w, http.StatusServiceUnavailable, "NewsBot unavailable")
+		return false
+	}
+	return true
+}
+
 func (s *Server) handleNewsFeed() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := getUserID(r)
 		var isSubscribed bool
 		var subLangs string
 
-		subs, err := s.newsbotStore.GetUserSubscribers(r.Context(), userID)
-		if err == nil && len(subs) > 0 {
-			isSubscribed = true
-			subLangs = subs[0].Languages
-		} else if subs == nil {
-			// We cannot initialize with newsbot_store.Subscriber here because newsbot_store is not imported in this file directly
-			// It's fine to let subs remain nil, json.Marshal will marshal it to null, but we can also just use an empty slice of any.
+		// The feed is served without a NewsBot store, as unsubscribed
+		var subs []store.Subscriber
+		if s.newsbotStore != nil {
+			var err error
+			subs, err = s.newsbotStore.GetUserSubscribers(r.Context(), userID)
+			if err == nil && len(subs) > 0 {
+				isSubscribed = true
+				subLangs = subs[0].Languages
+			}
 		}
 
 		var subsResponse any = subs
 		if subs == nil {
-			subsResponse = []int{}
+			subsResponse = []store.Subscriber{}
 		}
 
 		// Mock Data for NewsBot Feed to get the UI built quickly for Phase 2 Aha Moment.
@@ -131,23 +165,21 @@ func (s *Server) handleNewsFeed() http.HandlerFunc {
 			}
 		}
 
-		respondJSON(w, http.StatusOK, map[string]interface{}{
-			"feed":               feed,
-			"is_subscribed":      isSubscribed,
-			"subscription_langs": subLangs,
-			"subscriptions":      subsResponse,
+		respondJSON(w, http.StatusOK, NewsFeedResponse{
+			Feed:              feed,
+			IsSubscribed:      isSubscribed,
+			SubscriptionLangs: subLangs,
+			Subscriptions:     subsResponse,
 		})
 	}
 }
 
 func (s *Server) handleNewsBotSubscribe() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var req struct {
-			TargetType string `json:"target_type"`
-			TargetID   string `json:"target_id"`
-			Languages  string `json:"languages"`
+		if !s.requireNewsBot(w) {
+			return
 		}
-
+		var req NewsBotSubscribeRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			respondError(w, http.StatusBadRequest, "Invalid request body")
 			return
@@ -181,12 +213,15 @@ func (s *Server) handleNewsBotSubscribe() http.HandlerFunc {
 			return
 		}
 
-		respondJSON(w, http.StatusOK, map[string]string{"message": "Subscribed successfully"})
+		respondJSON(w, http.StatusOK, MessageResponse{Message: "Subscribed successfully"})
 	}
 }
 
 func (s *Server) handleListNewsBotSubscriptions() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.requireNewsBot(w) {
+			return
+		}
 		userID := getUserID(r)
 		subs, err := s.newsbotStore.GetUserSubscribers(r.Context(), userID)
 		if err != nil {
@@ -195,14 +230,18 @@ func (s *Server) handleListNewsBotSubscriptions() http.HandlerFunc {
 			return
 		}
 
-		respondJSON(w, http.StatusOK, map[string]interface{}{
-			"subscriptions": subs,
-		})
+		if subs == nil {
+			subs = []store.Subscriber{}
+		}
+		respondJSON(w, http.StatusOK, NewsBotSubscriptionsResponse{Subscriptions: subs})
 	}
 }
 
 func (s *Server) handleDeleteNewsBotSubscription() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.requireNewsBot(w) {
+			return
+		}
 		userID := getUserID(r)
 		subIDStr := r.URL.Query().Get("id")
 		if subIDStr == "" {
@@ -222,6 +261,6 @@ func (s *Server) handleDeleteNewsBotSubscription() http.HandlerFunc {
 			return
 		}
 
-		respondJSON(w, http.StatusOK, map[string]string{"message": "Subscription deleted successfully"})
+		respondJSON(w, http.StatusOK, MessageResponse{Message: "Subscription deleted successfully"})
 	}
 }
@@ -6,7 +6,13 @@ import (
 )
 
 type OnboardingRequest struct {
-	Industry string `json:"industry"`
+	Industry string `json:"industry"` // "devtools", "llm" or anything else for SaaS
+}
+
+// OnboardingResponse reports how many template competitors were added.
+type OnboardingResponse struct {
+	Message          string `json:"message"`
+	ProvisionedCount int    `json:"provisioned_count"`
 }
 
 // handleOnboarding automatically provisions the user's dashboard with
@@ -73,9 +79,9 @@ func (s *Server) handleOnboarding() http.HandlerFunc {
 			}
 		}
 
-		respondJSON(w, http.StatusOK, map[string]interface{}{
-			"message":           "Onboarding complete. Competitors provisioned!",
-			"provisioned_count": len(templates),
+		respondJSON(w, http.StatusOK, OnboardingResponse{
+			Message:          "Onboarding complete. Competitors provisioned!",
+			ProvisionedCount: len(templates),
 		})
 	}
 }
@@ -0,0 +1,404 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/RobinCoderZhao/devkit-suite/internal/user"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/notify"
+)
+
+// inviteTokenPurpose scopes signed invite tokens; see notify.SignToken.
+const inviteTokenPurpose = "team-invite"
+
+// signInviteToken binds an invite to the address it was sent to.
+func (s *Server) signInviteToken(inv *user.Invite) string {
+	return notify.SignToken(s.jwtSecret, inviteTokenPurpose, strconv.Itoa(inv.ID)+":"+inv.Email)
+}
+
+// verifyInviteToken returns the invite ID and email a token was signed for.
+func (s *Server) verifyInviteToken(token string) (int, string, error) {
+	payload, err := notify.VerifyToken(s.jwtSecret, inviteTokenPurpose, token)
+	if err != nil {
+		return 0, "", err
+	}
+	idStr, email, ok := strings.Cut(payload, ":")
+	id, err := strconv.Atoi(idStr)
+	if !ok || err != nil {
+		return 0, "", notify.ErrInvalidToken
+	}
+	return id, email, nil
+}
+
+func (s *Server) inviteURL(token string) string {
+	return fmt.Sprintf("%s/invite?token=%s", strings.TrimRight(s.frontendURL, "/"), url.QueryEscape(token))
+}
+
+// teamFromPath reads the {id} path value and checks the user's role in that
+// team. With adminOnly, members who are not admins are refused. It writes
+// the error response and returns 0 on failure.
+func (s *Server) teamFromPath(w http.ResponseWriter, r *http.Request, adminOnly bool) int {
+	teamID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid team id")
+		return 0
+	}
+	role, err := s.userStore.GetMemberRole(r.Context(), teamID, getUserID(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Database error")
+		return 0
+	}
+	if role == "" || (adminOnly && role != user.RoleAdmin) {
+		respondError(w, http.StatusForbidden, "Access denied or team not found")
+		return 0
+	}
+	return teamID
+}
+
+// CreateTeamRequest names a new team.
+type CreateTeamRequest struct {
+	Name string `json:"name"`
+}
+
+// CreateTeamResponse is the new team; its creator is its admin.
+type CreateTeamResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+// TeamsResponse lists the teams the user belongs to.
+type TeamsResponse struct {
+	Teams []user.Team `json:"teams"`
+}
+
+// MembersResponse lists a team's members.
+type MembersResponse struct {
+	Members []user.TeamMember `json:"members"`
+}
+
+func (s *Server) handleCreateTeam() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req CreateTeamRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Name) == "" {
+			respondError(w, http.StatusBadRequest, "Team name is required")
+			return
+		}
+		id, err := s.userStore.CreateTeam(r.Context(), req.Name, getUserID(r))
+		if err != nil {
+			s.logger.Error("failed to create team", "error", err)
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		respondJSON(w, http.StatusCreated, CreateTeamResponse{
+			ID:   id,
+			Name: strings.TrimSpace(req.Name),
+			Role: user.RoleAdmin,
+		})
+	}
+}
+
+func (s *Server) handleListTeams() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teams, err := s.userStore.ListTeamsForUser(r.Context(), getUserID(r))
+		if err != nil {
+			s.logger.Error("failed to list teams", "error", err)
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		respondJSON(w, http.StatusOK, TeamsResponse{Teams: teams})
+	}
+}
+
+func (s *Server) handleListTeamMembers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID := s.teamFromPath(w, r, false)
+		if teamID == 0 {
+			return
+		}
+		members, err := s.userStore.ListMembers(r.Context(), teamID)
+		if err != nil {
+			s.logger.Error("failed to list team members", "error", err)
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		respondJSON(w, http.StatusOK, MembersResponse{Members: members})
+	}
+}
+
+// handleRemoveTeamMember removes a member. Admins may remove anyone; other
+// members may only remove themselves, to leave the team.
+func (s *Server) handleRemoveTeamMember() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		memberID, err := strconv.Atoi(r.PathValue("userID"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid user id")
+			return
+		}
+		teamID := s.teamFromPath(w, r, memberID != getUserID(r))
+		if teamID == 0 {
+			return
+		}
+		if err := s.userStore.RemoveMember(r.Context(), teamID, memberID); err != nil {
+			if errors.Is(err, user.ErrLastAdmin) {
+				respondError(w, http.StatusConflict, err.Error())
+				return
+			}
+			s.logger.Error("failed to remove team member", "error", err)
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		respondJSON(w, http.StatusOK, MessageResponse{Message: "Member removed"})
+	}
+}
+
+// InviteRequest invites an email address to a team.
+type InviteRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role"` // "member" (default) or "admin"
+}
+
+// CreateInviteResponse is a new invite and the link to accept it.
+type CreateInviteResponse struct {
+	Invite    *user.Invite `json:"invite"`
+	AcceptURL string       `json:"accept_url"`
+	Emailed   bool         `json:"emailed"` // the link was also emailed to the invitee
+}
+
+// InvitesResponse lists invites.
+type InvitesResponse struct {
+	Invites []user.Invite `json:"invites"`
+}
+
+// InviteWithToken is an invite sent to the user, with the token to accept it.
+type InviteWithToken struct {
+	user.Invite
+	Token string `json:"token"`
+}
+
+// MyInvitesResponse lists the invites sent to the user.
+type MyInvitesResponse struct {
+	Invites []InviteWithToken `json:"invites"`
+}
+
+// InviteInfoResponse describes the invite behind an invite link.
+type InviteInfoResponse struct {
+	Invite *user.Invite `json:"invite"`
+}
+
+// AcceptInviteRequest carries the token from an invite link.
+type AcceptInviteRequest struct {
+	Token string `json:"token"`
+}
+
+// AcceptInviteResponse is the team the user joined and their role in it.
+type AcceptInviteResponse struct {
+	Message string `json:"message"`
+	TeamID  int    `json:"team_id"`
+	Role    string `json:"role"`
+}
+
+// handleCreateInvite invites an address to the team and emails it the
+// accept link when email is configured. The link is also returned, so
+// admins can share it themselves.
+func (s *Server) handleCreateInvite() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID := s.teamFromPath(w, r, true)
+		if teamID == 0 {
+			return
+		}
+		var req InviteRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if addr, err := mail.ParseAddress(req.Email); err != nil || addr.Address != strings.TrimSpace(req.Email) {
+			respondError(w, http.StatusBadRequest, "A valid email is required")
+			return
+		}
+		if req.Role != "" && req.Role != user.RoleMember && req.Role != user.RoleAdmin {
+			respondError(w, http.StatusBadRequest, "Role must be member or admin")
+			return
+		}
+
+		inv, err := s.userStore.CreateInvite(r.Context(), teamID, req.Email, req.Role, getUserID(r))
+		if errors.Is(err, user.ErrAlreadyMember) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if err != nil {
+			s.logger.Error("failed to create invite", "error", err)
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+
+		acceptURL := s.inviteURL(s.signInviteToken(inv))
+		emailed := false
+		if s.emailCfg != nil {
+			if err := s.sendInviteEmail(r.Context(), inv, acceptURL); err != nil {
+				s.logger.Warn("invite email failed", "email", inv.Email, "error", err)
+			} else {
+				emailed = true
+			}
+		}
+		respondJSON(w, http.StatusCreated, CreateInviteResponse{
+			Invite:    inv,
+			AcceptURL: acceptURL,
+			Emailed:   emailed,
+		})
+	}
+}
+
+func (s *Server) sendInviteEmail(ctx context.Context, inv *user.Invite, acceptURL string) error {
+	title := fmt.Sprintf("%s invited you to join %s", inv.InvitedBy, inv.TeamName)
+	body := fmt.Sprintf("%s invited you to join the team \"%s\" on DevKit Suite.\n\nAccept the invitation: %s\n\nThe link expires on %s.",
+		inv.InvitedBy, inv.TeamName, acceptURL, inv.ExpiresAt.Format("2006-01-02"))
+	htmlBody := fmt.Sprintf(`<p>%s invited you to join the team <strong>%s</strong> on DevKit Suite.</p>`+
+		`<p><a href="%s">Accept the invitation</a></p><p style="color:#888;font-size:12px;">The link expires on %s.</p>`,
+		html.EscapeString(inv.InvitedBy), html.EscapeString(inv.TeamName),
+		html.EscapeString(acceptURL), inv.ExpiresAt.Format("2006-01-02"))
+	return notify.NewEmailNotifierForRecipient(*s.emailCfg, inv.Email).Send(ctx, notify.Message{
+		Title:    title,
+		Body:     body,
+		HTMLBody: htmlBody,
+		Format:   "html",
+	})
+}
+
+func (s *Server) handleListTeamInvites() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID := s.teamFromPath(w, r, true)
+		if teamID == 0 {
+			return
+		}
+		invites, err := s.userStore.ListPendingInvites(r.Context(), teamID)
+		if err != nil {
+			s.logger.Error("failed to list invites", "error", err)
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		respondJSON(w, http.StatusOK, InvitesResponse{Invites: invites})
+	}
+}
+
+func (s *Server) handleRevokeInvite() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		teamID := s.teamFromPath(w, r, true)
+		if teamID == 0 {
+			return
+		}
+		inviteID, err := strconv.Atoi(r.PathValue("inviteID"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid invite id")
+			return
+		}
+		if err := s.userStore.RevokeInvite(r.Context(), teamID, inviteID); err != nil {
+			if errors.Is(err, user.ErrInviteInvalid) {
+				respondError(w, http.StatusNotFound, "Invite not found or no longer pending")
+				return
+			}
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		respondJSON(w, http.StatusOK, MessageResponse{Message: "Invite revoked"})
+	}
+}
+
+// handleMyInvites lists the pending invites sent to the user's login email,
+// with tokens to accept them.
+func (s *Server) handleMyInvites() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u, err := s.userStore.GetUserByID(r.Context(), getUserID(r))
+		if err != nil || u == nil {
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		invites, err := s.userStore.ListInvitesForEmail(r.Context(), u.Email)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		result := make([]InviteWithToken, len(invites))
+		for i := range invites {
+			result[i] = InviteWithToken{Invite: invites[i], Token: s.signInviteToken(&invites[i])}
+		}
+		respondJSON(w, http.StatusOK, MyInvitesResponse{Invites: result})
+	}
+}
+
+// handleInviteInfo shows what an invite link is for before the invitee
+// signs in or registers.
+func (s *Server) handleInviteInfo() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		inviteID, _, err := s.verifyInviteToken(r.URL.Query().Get("token"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid invite link")
+			return
+		}
+		inv, err := s.userStore.GetInvite(r.Context(), inviteID)
+		if errors.Is(err, user.ErrInviteInvalid) {
+			respondError(w, http.StatusGone, err.Error())
+			return
+		}
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		respondJSON(w, http.StatusOK, InviteInfoResponse{Invite: inv})
+	}
+}
+
+// handleAcceptInvite joins the signed-in user to the invite's team. The
+// invite must have been sent to the user's login email.
+func (s *Server) handleAcceptInvite() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req AcceptInviteRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		inviteID, email, err := s.verifyInviteToken(req.Token)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid invite link")
+			return
+		}
+
+		u, err := s.userStore.GetUserByID(r.Context(), getUserID(r))
+		if err != nil || u == nil {
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		if !strings.EqualFold(u.Email, email) {
+			respondError(w, http.StatusForbidden, "This invite was sent to a different email address")
+			return
+		}
+
+		inv, err := s.userStore.GetInvite(r.Context(), inviteID)
+		if err == nil {
+			err = s.userStore.AcceptInvite(r.Context(), inviteID, u.ID)
+		}
+		if errors.Is(err, user.ErrInviteInvalid) {
+			respondError(w, http.StatusGone, err.Error())
+			return
+		}
+		if err != nil {
+			s.logger.Error("failed to accept invite", "error", err)
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		s.logger.Info("team invite accepted", "team", inv.TeamID, "user", u.ID)
+		respondJSON(w, http.StatusOK, AcceptInviteResponse{
+			Message: "Joined team",
+			TeamID:  inv.TeamID,
+			Role:    inv.Role,
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/internal/watchbot"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/notify"
+)
+
+// transparentGIF is a 1x1 transparent GIF served as the open-tracking pixel.
+var transparentGIF = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// handleTrackOpen records a digest open and serves the tracking pixel.
+// The pixel is always served so broken tokens never show as broken images.
+func (s *Server) handleTrackOpen() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if ev, err := notify.ParseTrackingToken(s.jwtSecret, "open", r.URL.Query().Get("t")); err == nil {
+			if err := s.watchbotStore.RecordEngagement(r.Context(), ev.DigestID, ev.UserID, ev.Kind, ""); err != nil {
+				s.logger.Warn("failed to record open", "digest", ev.DigestID, "error", err)
+			}
+		}
+
+		w.Header().Set("Content-Type", "image/gif")
+		w.Header().Set("Cache-Control", "no-store, max-age=0")
+		_, _ = w.Write(transparentGIF)
+	}
+}
+
+// handleTrackClick records a link click and redirects to the original URL.
+// Only signed targets are followed, so this cannot be abused as an open redirect.
+func (s *Server) handleTrackClick() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ev, err := notify.ParseTrackingToken(s.jwtSecret, "click", r.URL.Query().Get("t"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid link")
+			return
+		}
+
+		if err := s.watchbotStore.RecordEngagement(r.Context(), ev.DigestID, ev.UserID, ev.Kind, ev.URL); err != nil {
+			s.logger.Warn("failed to record click", "digest", ev.DigestID, "error", err)
+		}
+		http.Redirect(w, r, ev.URL, http.StatusFound)
+	}
+}
+
+// EngagementResponse is digest engagement per subscriber over a window.
+type EngagementResponse struct {
+	Days        int                       `json:"days"`
+	Subscribers []watchbot.EngagementStat `json:"subscribers"`
+}
+
+// handleEngagement summarizes per-subscriber digest engagement (admin only).
+// Query: ?days=N limits the window (default 90).
+func (s *Server) handleEngagement() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.isAdmin(getUserID(r)) {
+			respondError(w, http.StatusForbidden, "Admin access required")
+			return
+		}
+
+		days := 90
+		if d, err := strconv.Atoi(r.URL.Query().Get("days")); err == nil && d > 0 {
+			days = d
+		}
+
+		stats, err := s.watchbotStore.EngagementSummary(r.Context(), time.Now().AddDate(0, 0, -days))
+		if err != nil {
+			s.logger.Error("failed to load engagement", "error", err)
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+
+		respondJSON(w, http.StatusOK, EngagementResponse{
+			Days:        days,
+			Subscribers: stats,
+		})
+	}
+}
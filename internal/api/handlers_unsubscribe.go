@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/notify"
+)
+
+// UnsubscribeResponse is a mailing list and whether the user left it.
+type UnsubscribeResponse struct {
+	Message      string `json:"message,omitempty"`
+	List         string `json:"list"`
+	Unsubscribed bool   `json:"unsubscribed"`
+}
+
+// handleUnsubscribeInfo validates an unsubscribe token without acting on it.
+// Link scanners follow GET requests, so unsubscribing only happens on POST (RFC 8058).
+func (s *Server) handleUnsubscribeInfo() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, list, err := notify.VerifyUnsubscribeToken(s.jwtSecret, r.URL.Query().Get("token"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid unsubscribe link")
+			return
+		}
+
+		current, err := s.watchbotStore.GetUserSetting(r.Context(), userID, "unsubscribe."+list)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+
+		respondJSON(w, http.StatusOK, UnsubscribeResponse{
+			List:         list,
+			Unsubscribed: current == "true",
+		})
+	}
+}
+
+// handleUnsubscribe performs a one-click unsubscribe from a mailing list.
+func (s *Server) handleUnsubscribe() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, list, err := notify.VerifyUnsubscribeToken(s.jwtSecret, r.URL.Query().Get("token"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid unsubscribe link")
+			return
+		}
+
+		if err := s.watchbotStore.SetUserSetting(r.Context(), userID, "unsubscribe."+list, "true"); err != nil {
+			s.logger.Error("failed to unsubscribe", "user", userID, "list", list, "error", err)
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+
+		s.logger.Info("user unsubscribed", "user", userID, "list", list)
+		respondJSON(w, http.StatusOK, UnsubscribeResponse{
+			Message:      "Unsubscribed",
+			List:         list,
+			Unsubscribed: true,
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// BudgetRequest sets the monthly LLM spending cap.
+type BudgetRequest struct {
+	MonthlyBudget float64 `json:"monthly_budget"` // USD; 0 removes the cap
+}
+
+// usageUser returns whose usage the request is about: the caller, or for
+// admins the user in ?user_id=. It responds with an error and returns false
+// when the request may not proceed.
+func (s *Server) usageUser(w http.ResponseWriter, r *http.Request) (int, bool) {
+	userID := getUserID(r)
+	v := r.URL.Query().Get("user_id")
+	if v == "" {
+		return userID, true
+	}
+	if !s.isAdmin(userID) {
+		respondError(w, http.StatusForbidden, "Admin access required")
+		return 0, false
+	}
+	id, err := strconv.Atoi(v)
+	if err != nil || id <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid user id")
+		return 0, false
+	}
+	return id, true
+}
+
+// handleUsage reports the month's LLM spend by feature against the plan
+// quota and the user's budget. Admins may pass ?user_id=N.
+func (s *Server) handleUsage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := s.usageUser(w, r)
+		if !ok {
+			return
+		}
+		s.respondMonthUsage(w, r, userID)
+	}
+}
+
+// handleSetBudget sets the monthly LLM budget. Once the month's cost reaches
+// it, page changes are reported as plain diffs without LLM analysis.
+// Admins may pass ?user_id=N to set another user's budget.
+func (s *Server) handleSetBudget() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := s.usageUser(w, r)
+		if !ok {
+			return
+		}
+		var req BudgetRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.MonthlyBudget < 0 {
+			respondError(w, http.StatusBadRequest, "Budget must not be negative")
+			return
+		}
+		if err := s.userStore.SetLLMBudget(r.Context(), userID, req.MonthlyBudget); err != nil {
+			s.logger.Error("failed to set LLM budget", "user", userID, "error", err)
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		s.respondMonthUsage(w, r, userID)
+	}
+}
+
+func (s *Server) respondMonthUsage(w http.ResponseWriter, r *http.Request, userID int) {
+	usage, err := s.userStore.MonthUsage(r.Context(), userID)
+	if err != nil {
+		s.logger.Error("failed to load LLM usage", "user", userID, "error", err)
+		respondError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+	if usage == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	respondJSON(w, http.StatusOK, usage)
+}
+
+// handleLLMUsage reports the caller's LLM usage: daily token and cost
+// aggregates plus the month's total against the plan quota.
+// Query: ?days=N sets the window (default 30, at most 366); admins may pass
+// ?user_id=N to see another user's usage.
+func (s *Server) handleLLMUsage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := s.usageUser(w, r)
+		if !ok {
+			return
+		}
+
+		days := 30
+		if d, err := strconv.Atoi(r.URL.Query().Get("days")); err == nil && d > 0 {
+			days = min(d, 366)
+		}
+
+		report, err := s.userStore.LLMUsage(r.Context(), userID, days)
+		if err != nil {
+			s.logger.Error("failed to load LLM usage", "user", userID, "error", err)
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		respondJSON(w, http.StatusOK, report)
+	}
+}
@@ -1,10 +1,17 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
 
 	"golang.org/x/crypto/bcrypt"
+
+	"github.com/RobinCoderZhao/devkit-suite/internal/user"
+	"github.com/RobinCoderZhao/devkit-suite/internal/watchbot"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/notify"
 )
 
 type RegisterRequest struct {
@@ -12,6 +19,15 @@ type RegisterRequest struct {
 	Password string `json:"password"`
 }
 
+// AuthResponse is returned on registration and login. The token is also set
+// as the token cookie.
+type AuthResponse struct {
+	Message string `json:"message"`
+	UserID  int    `json:"user_id"`
+	Plan    string `json:"plan,omitempty"` // login only
+	Token   string `json:"token"`
+}
+
 func (s *Server) handleRegister() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req RegisterRequest
@@ -55,10 +71,10 @@ func (s *Server) handleRegister() http.HandlerFunc {
 			SameSite: http.SameSiteLaxMode,
 		})
 
-		respondJSON(w, http.StatusCreated, map[string]interface{}{
-			"message": "Registration successful",
-			"user_id": id,
-			"token":   token,
+		respondJSON(w, http.StatusCreated, AuthResponse{
+			Message: "Registration successful",
+			UserID:  id,
+			Token:   token,
 		})
 	}
 }
@@ -105,21 +121,184 @@ func (s *Server) handleLogin() http.HandlerFunc {
 			SameSite: http.SameSiteLaxMode,
 		})
 
-		respondJSON(w, http.StatusOK, map[string]interface{}{
-			"message": "Login successful",
-			"user_id": u.ID,
-			"plan":    u.Plan,
-			"token":   token,
+		respondJSON(w, http.StatusOK, AuthResponse{
+			Message: "Login successful",
+			UserID:  u.ID,
+			Plan:    u.Plan,
+			Token:   token,
 		})
 	}
 }
 
 func (s *Server) handleGetMe() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userID := getUserID(r)
-		// For simplicity, we just return the ID. A real implementation would fetch from DB.
-		respondJSON(w, http.StatusOK, map[string]interface{}{
-			"user_id": userID,
-		})
+		u, err := s.userStore.GetUserByID(r.Context(), getUserID(r))
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		if u == nil {
+			respondError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		resp, err := s.meResponse(r.Context(), u)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		respondJSON(w, http.StatusOK, resp)
+	}
+}
+
+// ProfileResponse is the signed-in user's account and profile.
+type ProfileResponse struct {
+	UserID int    `json:"user_id"`
+	Email  string `json:"email"`
+	Plan   string `json:"plan"`
+	user.Profile
+	EmailFormat string `json:"email_format"` // "html" or "plain"
+	QuietHours  string `json:"quiet_hours"`  // "22:00-07:00" in Timezone; empty disables
+	// Escalation is the minimum severity per channel, e.g. {"email":"minor",
+	// "telegram":"important","sms":"off"}; omitted when the server default applies.
+	Escalation notify.EscalationPolicy `json:"escalation,omitempty"`
+}
+
+// meResponse returns the user's profile with their WatchBot delivery
+// settings.
+func (s *Server) meResponse(ctx context.Context, u *user.User) (ProfileResponse, error) {
+	quiet, err := s.watchbotStore.GetUserSetting(ctx, u.ID, "quiet_hours")
+	if err != nil {
+		return ProfileResponse{}, err
+	}
+	escalation, err := s.watchbotStore.GetUserEscalation(ctx, u.ID)
+	if err != nil {
+		return ProfileResponse{}, err
+	}
+	format, err := s.watchbotStore.GetUserSetting(ctx, u.ID, "format")
+	if err != nil {
+		return ProfileResponse{}, err
+	}
+	if format == "" {
+		format = "html"
+	}
+	return ProfileResponse{
+		UserID:      u.ID,
+		Email:       u.Email,
+		Plan:        u.Plan,
+		Profile:     u.Profile,
+		EmailFormat: format,
+		QuietHours:  quiet,
+		Escalation:  escalation,
+	}, nil
+}
+
+// UpdateProfileRequest changes the fields that are set and leaves the rest.
+type UpdateProfileRequest struct {
+	Name              *string `json:"name"`
+	Phone             *string `json:"phone"`
+	Company           *string `json:"company"`
+	Timezone          *string `json:"timezone"`
+	Language          *string `json:"language"`
+	NotificationEmail *string `json:"notification_email"`
+	EmailFormat       *string `json:"email_format"` // "html" or "plain" (text-only digests)
+	QuietHours        *string `json:"quiet_hours"`  // "22:00-07:00"; "" disables
+	// Escalation replaces the per-channel minimum severities; {} restores the
+	// server default.
+	Escalation *notify.EscalationPolicy `json:"escalation"`
+}
+
+func (s *Server) handleUpdateProfile() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req UpdateProfileRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		u, err := s.userStore.GetUserByID(r.Context(), getUserID(r))
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		if u == nil {
+			respondError(w, http.StatusNotFound, "User not found")
+			return
+		}
+
+		p := u.Profile
+		setIfPresent(&p.Name, req.Name)
+		setIfPresent(&p.Phone, req.Phone)
+		setIfPresent(&p.Company, req.Company)
+		setIfPresent(&p.Timezone, req.Timezone)
+		setIfPresent(&p.Language, req.Language)
+		setIfPresent(&p.NotificationEmail, req.NotificationEmail)
+		if req.QuietHours != nil && *req.QuietHours != "" {
+			if _, err := watchbot.ParseQuietHours(*req.QuietHours); err != nil {
+				respondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		if req.EmailFormat != nil && *req.EmailFormat != "html" && *req.EmailFormat != "plain" {
+			respondError(w, http.StatusBadRequest, "Invalid email format")
+			return
+		}
+		if req.Escalation != nil {
+			if err := req.Escalation.Validate(); err != nil {
+				respondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		if err := s.userStore.UpdateProfile(r.Context(), u.ID, p); err != nil {
+			if errors.Is(err, user.ErrInvalidProfile) {
+				respondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			s.logger.Error("failed to update profile", "error", err)
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		if req.EmailFormat != nil {
+			if err := s.watchbotStore.SetUserEmailFormat(r.Context(), u.ID, *req.EmailFormat); err != nil {
+				s.logger.Error("failed to update email format", "error", err)
+				respondError(w, http.StatusInternalServerError, "Database error")
+				return
+			}
+		}
+		if req.QuietHours != nil {
+			if err := s.watchbotStore.SetUserQuietHours(r.Context(), u.ID, strings.TrimSpace(*req.QuietHours)); err != nil {
+				s.logger.Error("failed to update quiet hours", "error", err)
+				respondError(w, http.StatusInternalServerError, "Database error")
+				return
+			}
+		}
+		if req.Escalation != nil {
+			policy := *req.Escalation
+			if len(policy) == 0 {
+				policy = nil
+			}
+			if err := s.watchbotStore.SetUserEscalation(r.Context(), u.ID, policy); err != nil {
+				s.logger.Error("failed to update escalation policy", "error", err)
+				respondError(w, http.StatusInternalServerError, "Database error")
+				return
+			}
+		}
+		u, err = s.userStore.GetUserByID(r.Context(), u.ID)
+		if err != nil || u == nil {
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		resp, err := s.meResponse(r.Context(), u)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		respondJSON(w, http.StatusOK, resp)
+	}
+}
+
+func setIfPresent(field, value *string) {
+	if value != nil {
+		*field = *value
 	}
 }
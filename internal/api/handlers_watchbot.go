@@ -4,9 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/RobinCoderZhao/devkit-suite/internal/watchbot"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/differ"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/notify"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/scraper"
 )
 
 type DashboardResponse struct {
@@ -27,8 +32,8 @@ func (s *Server) handleDashboard() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := getUserID(r)
 
-		// 1. Get user's competitors
-		competitors, err := s.watchbotStore.ListCompetitorsByUser(r.Context(), userID)
+		// 1. Get the competitors of the user and their teammates
+		competitors, err := s.watchbotStore.ListVisibleCompetitors(r.Context(), userID)
 		if err != nil {
 			s.logger.Error("list competitors for dashboard", "error", err)
 			respondError(w, http.StatusInternalServerError, "Database error")
@@ -73,20 +78,41 @@ func (s *Server) handleDashboard() http.HandlerFunc {
 	}
 }
 
+// CompetitorsResponse lists the competitors of the user and their teammates.
+type CompetitorsResponse struct {
+	Competitors []watchbot.Competitor `json:"competitors"`
+}
+
+// TimelineResponse is a competitor and its detected changes.
+type TimelineResponse struct {
+	Competitor *watchbot.Competitor `json:"competitor"`
+	Timeline   []watchbot.Change    `json:"timeline"`
+}
+
+// ChangeDiffResponse is a change with its diff rendered as HTML.
+type ChangeDiffResponse struct {
+	ID         int           `json:"id"`
+	Competitor string        `json:"competitor"`
+	PageURL    string        `json:"page_url"`
+	Severity   string        `json:"severity"`
+	CreatedAt  time.Time     `json:"created_at"`
+	Stats      differ.Stats  `json:"stats"`
+	Layout     differ.Layout `json:"layout"`
+	HTML       string        `json:"html"`
+}
+
 func (s *Server) handleListCompetitors() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := getUserID(r)
 
-		competitors, err := s.watchbotStore.ListCompetitorsByUser(r.Context(), userID)
+		competitors, err := s.watchbotStore.ListVisibleCompetitors(r.Context(), userID)
 		if err != nil {
 			s.logger.Error("failed to list competitors", "error", err)
 			respondError(w, http.StatusInternalServerError, "Database error")
 			return
 		}
 
-		respondJSON(w, http.StatusOK, map[string]interface{}{
-			"competitors": competitors,
-		})
+		respondJSON(w, http.StatusOK, CompetitorsResponse{Competitors: competitors})
 	}
 }
 
@@ -98,8 +124,8 @@ func (s *Server) handleCompetitorTimeline() http.HandlerFunc {
 		var compID int
 		fmt.Sscanf(idStr, "%d", &compID)
 
-		// Verify the user owns this competitor
-		competitors, err := s.watchbotStore.ListCompetitorsByUser(r.Context(), userID)
+		// Verify the user or a teammate owns this competitor
+		competitors, err := s.watchbotStore.ListVisibleCompetitors(r.Context(), userID)
 		if err != nil {
 			respondError(w, http.StatusInternalServerError, "Database error")
 			return
@@ -125,9 +151,56 @@ func (s *Server) handleCompetitorTimeline() http.HandlerFunc {
 			return
 		}
 
-		respondJSON(w, http.StatusOK, map[string]interface{}{
-			"competitor": foundComp,
-			"timeline":   changes,
+		respondJSON(w, http.StatusOK, TimelineResponse{
+			Competitor: foundComp,
+			Timeline:   changes,
+		})
+	}
+}
+
+// handleChangeDiff renders a change's diff as HTML for the dashboard's diff
+// viewer. ?layout=inline selects the one-column layout; the default is
+// side by side.
+func (s *Server) handleChangeDiff() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserID(r)
+
+		changeID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid change id")
+			return
+		}
+		layout := differ.LayoutSideBySide
+		switch r.URL.Query().Get("layout") {
+		case "", string(differ.LayoutSideBySide):
+		case string(differ.LayoutInline):
+			layout = differ.LayoutInline
+		default:
+			respondError(w, http.StatusBadRequest, "layout must be side-by-side or inline")
+			return
+		}
+
+		change, err := s.watchbotStore.GetChangeForUser(r.Context(), changeID, userID)
+		if err != nil {
+			s.logger.Error("failed to get change", "error", err)
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		if change == nil {
+			respondError(w, http.StatusNotFound, "Change not found")
+			return
+		}
+
+		diff := differ.ParseUnified(change.DiffUnified)
+		respondJSON(w, http.StatusOK, ChangeDiffResponse{
+			ID:         change.ID,
+			Competitor: change.CompetitorName,
+			PageURL:    change.PageURL,
+			Severity:   change.Severity,
+			CreatedAt:  change.CreatedAt,
+			Stats:      diff.Stats,
+			Layout:     layout,
+			HTML:       differ.RenderHTML(diff, &differ.RenderOptions{Layout: layout}),
 		})
 	}
 }
@@ -137,6 +210,16 @@ type AddCompetitorRequest struct {
 	Domain   string `json:"domain"`
 	URL      string `json:"url"`
 	PageType string `json:"page_type"`
+	// CheckInterval is how often the page is checked, e.g. "1h"; empty uses
+	// the server default
+	CheckInterval string `json:"check_interval,omitempty"`
+	// Browser renders the page in a headless browser, waiting for
+	// WaitSelector if set, for pages that need JavaScript
+	Browser      bool   `json:"browser,omitempty"`
+	WaitSelector string `json:"wait_selector,omitempty"`
+	// Selector limits monitoring to the matching part of the page, as a CSS
+	// selector such as ".pricing-table" or an XPath expression
+	Selector string `json:"selector,omitempty"`
 }
 
 func (s *Server) handleAddCompetitor() http.HandlerFunc {
@@ -153,6 +236,21 @@ func (s *Server) handleAddCompetitor() http.HandlerFunc {
 			respondError(w, http.StatusBadRequest, "Name and URL are required")
 			return
 		}
+		var interval time.Duration
+		if req.CheckInterval != "" {
+			var err error
+			interval, err = time.ParseDuration(req.CheckInterval)
+			if err != nil || interval < watchbot.MinCheckInterval {
+				respondError(w, http.StatusBadRequest, "Invalid check interval")
+				return
+			}
+		}
+		if req.Selector != "" {
+			if _, err := scraper.ParseSelector(req.Selector); err != nil {
+				respondError(w, http.StatusBadRequest, "Invalid selector")
+				return
+			}
+		}
 
 		ctx := r.Context()
 		u, err := s.userStore.GetUserByID(ctx, userID)
@@ -191,18 +289,74 @@ func (s *Server) handleAddCompetitor() http.HandlerFunc {
 			pageType = "pricing"
 		}
 
-		_, err = s.watchbotStore.AddPage(ctx, compID, req.URL, pageType)
+		pageID, err := s.watchbotStore.AddPage(ctx, compID, req.URL, pageType)
 		if err != nil {
 			respondError(w, http.StatusInternalServerError, "Failed to add tracked page")
 			return
 		}
+		if interval > 0 {
+			if err := s.watchbotStore.SetCheckInterval(ctx, pageID, interval); err != nil {
+				s.logger.Error("failed to set check interval", "error", err)
+				respondError(w, http.StatusInternalServerError, "Failed to add tracked page")
+				return
+			}
+		}
+		if req.Browser || req.WaitSelector != "" {
+			if err := s.watchbotStore.SetBrowserRendering(ctx, pageID, true, req.WaitSelector); err != nil {
+				s.logger.Error("failed to enable browser rendering", "error", err)
+				respondError(w, http.StatusInternalServerError, "Failed to add tracked page")
+				return
+			}
+		}
+		if req.Selector != "" {
+			if err := s.watchbotStore.SetSelector(ctx, pageID, req.Selector); err != nil {
+				s.logger.Error("failed to set selector", "error", err)
+				respondError(w, http.StatusInternalServerError, "Failed to add tracked page")
+				return
+			}
+		}
 
-		respondJSON(w, http.StatusCreated, map[string]string{
-			"message": "Competitor added successfully",
-		})
+		respondJSON(w, http.StatusCreated, MessageResponse{Message: "Competitor added successfully"})
 	}
 }
 
+// SearchResponse is the snapshots and analyses matching a search, oldest
+// first.
+type SearchResponse struct {
+	Query   string               `json:"query"`
+	Results []watchbot.SearchHit `json:"results"`
+}
+
+func (s *Server) handleSearch() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserID(r)
+
+		query := strings.TrimSpace(r.URL.Query().Get("q"))
+		if query == "" {
+			respondError(w, http.StatusBadRequest, "Missing search query")
+			return
+		}
+		limit := 50
+		if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+			limit = min(l, watchbot.MaxSearchResults)
+		}
+
+		results, err := s.watchbotStore.Search(r.Context(), userID, query, limit)
+		if err != nil {
+			s.logger.Error("failed to search", "error", err)
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+
+		respondJSON(w, http.StatusOK, SearchResponse{Query: query, Results: results})
+	}
+}
+
+// AlertRulesResponse lists the user's alert rules.
+type AlertRulesResponse struct {
+	Rules []watchbot.AlertRule `json:"rules"`
+}
+
 func (s *Server) handleGetAlertRules() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := getUserID(r)
@@ -214,12 +368,12 @@ func (s *Server) handleGetAlertRules() http.HandlerFunc {
 			return
 		}
 
-		respondJSON(w, http.StatusOK, map[string]interface{}{
-			"rules": rules,
-		})
+		respondJSON(w, http.StatusOK, AlertRulesResponse{Rules: rules})
 	}
 }
 
+// AddAlertRuleRequest is a new alert rule. RuleType is severity, keyword,
+// regex or page_type; Action is notify, suppress or escalate:<channel>.
 type AddAlertRuleRequest struct {
 	CompetitorID *int   `json:"competitor_id"`
 	RuleType     string `json:"rule_type"`
@@ -227,6 +381,12 @@ type AddAlertRuleRequest struct {
 	Action       string `json:"action"`
 }
 
+// AddAlertRuleResponse is the ID of the new rule.
+type AddAlertRuleResponse struct {
+	Message string `json:"message"`
+	RuleID  int    `json:"rule_id"`
+}
+
 func (s *Server) handleAddAlertRule() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := getUserID(r)
@@ -241,6 +401,11 @@ func (s *Server) handleAddAlertRule() http.HandlerFunc {
 			respondError(w, http.StatusBadRequest, "Missing required fields")
 			return
 		}
+		rule := watchbot.AlertRule{RuleType: req.RuleType, RuleValue: req.RuleValue, Action: req.Action}
+		if err := rule.Validate(); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid alert rule")
+			return
+		}
 
 		id, err := s.watchbotStore.AddAlertRule(r.Context(), userID, req.CompetitorID, req.RuleType, req.RuleValue, req.Action)
 		if err != nil {
@@ -249,9 +414,63 @@ func (s *Server) handleAddAlertRule() http.HandlerFunc {
 			return
 		}
 
-		respondJSON(w, http.StatusCreated, map[string]interface{}{
-			"message": "Rule added",
-			"rule_id": id,
+		respondJSON(w, http.StatusCreated, AddAlertRuleResponse{
+			Message: "Rule added",
+			RuleID:  id,
 		})
 	}
 }
+
+// RoutesResponse is the user's digest delivery channels. No routes means
+// digests go to the notification email.
+type RoutesResponse struct {
+	Routes []notify.Route `json:"routes"`
+}
+
+func (s *Server) handleGetRoutes() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserID(r)
+
+		routes, err := s.watchbotStore.GetUserRoutes(r.Context(), userID)
+		if err != nil {
+			s.logger.Error("failed to get notification routes", "error", err)
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		if routes == nil {
+			routes = []notify.Route{}
+		}
+
+		respondJSON(w, http.StatusOK, RoutesResponse{Routes: routes})
+	}
+}
+
+// SetRoutesRequest replaces the user's digest delivery channels; an empty
+// list restores delivery to the notification email.
+type SetRoutesRequest struct {
+	Routes []notify.Route `json:"routes"`
+}
+
+func (s *Server) handleSetRoutes() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserID(r)
+
+		var req SetRoutesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if err := watchbot.ValidateRoutes(req.Routes); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid notification route")
+			return
+		}
+
+		if err := s.watchbotStore.SetUserRoutes(r.Context(), userID, req.Routes); err != nil {
+			s.logger.Error("failed to set notification routes", "error", err)
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+
+		respondJSON(w, http.StatusOK, MessageResponse{Message: "Routes updated"})
+	}
+}
@@ -0,0 +1,282 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// route is one endpoint: the handler registered on the mux and the
+// description it gets in the OpenAPI document.
+type route struct {
+	pattern string // "METHOD /path", as registered with http.ServeMux
+	public  bool   // served without a token
+	handler http.HandlerFunc
+	operation
+}
+
+// operation describes a route for the OpenAPI document.
+type operation struct {
+	id       string // operationId; also the method name in pkg/apiclient
+	tag      string
+	summary  string
+	query    []param
+	request  any    // zero value of the JSON request body; nil for none
+	response any    // zero value of the JSON success body; nil for none
+	status   int    // success status; 0 means 200
+	produces string // content type of a non-JSON success response
+}
+
+// param is a query parameter. Path parameters are taken from the pattern
+// and are always integer IDs.
+type param struct {
+	name        string
+	typ         string // "string" or "integer"
+	description string
+	required    bool
+	enum        []string
+}
+
+// ErrorResponse is the body of every error response.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// MessageResponse confirms an action that has nothing else to return.
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+var openAPIDoc = sync.OnceValue(func() []byte {
+	doc, err := json.Marshal(OpenAPI())
+	if err != nil {
+		panic(err) // the document is built from static types
+	}
+	return doc
+})
+
+// handleOpenAPI serves the OpenAPI document.
+func (s *Server) handleOpenAPI() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(openAPIDoc())
+	}
+}
+
+var pathParamRE = regexp.MustCompile(`\{(\w+)\}`)
+
+// OpenAPI returns the OpenAPI 3.1 document describing the API, built from
+// the route table so it cannot drift from what the server serves. It is
+// served at /openapi.json and is the input of the pkg/apiclient generator.
+func OpenAPI() map[string]any {
+	b := &schemaBuilder{schemas: map[string]any{}, names: map[reflect.Type]string{}}
+	errorSchema := b.schema(reflect.TypeOf(ErrorResponse{}))
+
+	paths := map[string]map[string]any{}
+	for _, rt := range (&Server{}).routes() {
+		method, p, _ := strings.Cut(rt.pattern, " ")
+		op := map[string]any{
+			"operationId": rt.id,
+			"summary":     rt.summary,
+			"tags":        []string{rt.tag},
+		}
+		if rt.public {
+			op["security"] = []any{}
+		}
+
+		var params []any
+		for _, m := range pathParamRE.FindAllStringSubmatch(p, -1) {
+			params = append(params, map[string]any{
+				"name": m[1], "in": "path", "required": true,
+				"schema": map[string]any{"type": "integer"},
+			})
+		}
+		for _, q := range rt.query {
+			schema := map[string]any{"type": q.typ}
+			if len(q.enum) > 0 {
+				schema["enum"] = q.enum
+			}
+			params = append(params, map[string]any{
+				"name": q.name, "in": "query", "required": q.required,
+				"description": q.description, "schema": schema,
+			})
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+
+		if rt.request != nil {
+			op["requestBody"] = map[string]any{
+				"required": true,
+				"content":  jsonContent(b.schema(reflect.TypeOf(rt.request))),
+			}
+		}
+
+		success := map[string]any{"description": http.StatusText(rt.statusCode())}
+		switch {
+		case rt.response != nil:
+			success["content"] = jsonContent(b.schema(reflect.TypeOf(rt.response)))
+		case rt.produces != "":
+			success["content"] = map[string]any{rt.produces: map[string]any{}}
+		}
+		op["responses"] = map[string]any{
+			strconv.Itoa(rt.statusCode()): success,
+			"default": map[string]any{
+				"description": "Error",
+				"content":     jsonContent(errorSchema),
+			},
+		}
+
+		if paths[p] == nil {
+			paths[p] = map[string]any{}
+		}
+		paths[p][strings.ToLower(method)] = op
+	}
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":       "DevKit Suite API",
+			"version":     "1.0.0",
+			"description": "REST API behind the DevKit Suite web frontend. Error messages are localized by Accept-Language. Requests over the rate limit get 429 with Retry-After.",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": b.schemas,
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+				"cookieAuth": map[string]any{"type": "apiKey", "in": "cookie", "name": "token"},
+			},
+		},
+		"security": []any{
+			map[string]any{"bearerAuth": []string{}},
+			map[string]any{"cookieAuth": []string{}},
+		},
+	}
+}
+
+func (o operation) statusCode() int {
+	if o.status == 0 {
+		return http.StatusOK
+	}
+	return o.status
+}
+
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// schemaBuilder derives JSON Schemas from Go types the way encoding/json
+// marshals them. Structs become named component schemas.
+type schemaBuilder struct {
+	schemas map[string]any
+	names   map[reflect.Type]string
+}
+
+func (b *schemaBuilder) schema(t reflect.Type) map[string]any {
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case durationType:
+		return map[string]any{"type": "integer", "format": "int64", "description": "nanoseconds"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(b.schema(t.Elem()))
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": b.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		return b.ref(t)
+	}
+	return map[string]any{} // interfaces: any JSON value
+}
+
+// ref returns a reference to the component schema of struct t, adding it
+// on first use.
+func (b *schemaBuilder) ref(t reflect.Type) map[string]any {
+	name, ok := b.names[t]
+	if !ok {
+		name = t.Name()
+		if _, taken := b.schemas[name]; taken {
+			// same name in another package, e.g. watchbot.Change
+			pkg := path.Base(t.PkgPath())
+			name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+		}
+		b.names[t] = name
+		b.schemas[name] = nil // reserve against recursion
+		props, required := map[string]any{}, []string{}
+		b.fields(t, props, &required)
+		s := map[string]any{"type": "object", "properties": props}
+		if len(required) > 0 {
+			s["required"] = required
+		}
+		b.schemas[name] = s
+	}
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+// fields adds the JSON properties of struct t, flattening embedded structs.
+func (b *schemaBuilder) fields(t reflect.Type, props map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			b.fields(f.Type, props, required)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = b.schema(f.Type)
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Pointer {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// nullable allows null in addition to s.
+func nullable(s map[string]any) map[string]any {
+	if typ, ok := s["type"].(string); ok {
+		n := make(map[string]any, len(s))
+		for k, v := range s {
+			n[k] = v
+		}
+		n["type"] = []string{typ, "null"}
+		return n
+	}
+	return map[string]any{"oneOf": []any{s, map[string]any{"type": "null"}}}
+}
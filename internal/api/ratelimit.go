@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/metrics"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/ratelimit"
+)
+
+// DefaultIPRateLimit limits every request by client IP, signed in or not,
+// so unauthenticated endpoints such as login cannot be hammered.
+var DefaultIPRateLimit = ratelimit.Limit{Rate: 10, Burst: 600}
+
+// DefaultPlanRateLimits limit signed-in users by plan. Plans not listed get
+// the free limit.
+var DefaultPlanRateLimits = map[string]ratelimit.Limit{
+	"free": {Rate: 1, Burst: 60},
+	"pro":  {Rate: 10, Burst: 600},
+}
+
+// planCacheTTL is how long a user's plan is trusted before it is read
+// again, so an upgrade lifts the limit within a minute.
+const planCacheTTL = time.Minute
+
+// rateLimits holds the limits Routes applies and the plans of recent users.
+type rateLimits struct {
+	limiter    *ratelimit.Limiter
+	perIP      ratelimit.Limit
+	perPlan    map[string]ratelimit.Limit
+	trustProxy bool
+
+	mu    sync.Mutex
+	plans map[int]cachedPlan
+}
+
+type cachedPlan struct {
+	plan string
+	at   time.Time
+}
+
+func newRateLimits() *rateLimits {
+	return &rateLimits{
+		limiter: ratelimit.New(),
+		perIP:   DefaultIPRateLimit,
+		perPlan: DefaultPlanRateLimits,
+		plans:   make(map[int]cachedPlan),
+	}
+}
+
+// SetRateLimits replaces the per-IP limit and the per-plan limits of
+// signed-in users. Plans missing from perPlan get its "free" limit; the
+// zero Limit is unlimited.
+func (s *Server) SetRateLimits(perIP ratelimit.Limit, perPlan map[string]ratelimit.Limit) {
+	s.limits.perIP, s.limits.perPlan = perIP, perPlan
+}
+
+// SetTrustProxy takes the client IP from the X-Forwarded-For entry the
+// reverse proxy in front of the API appended, instead of the connection's
+// address, which would be the proxy's. Only enable it behind a proxy, or
+// clients can pick their own IP.
+func (s *Server) SetTrustProxy(trust bool) {
+	s.limits.trustProxy = trust
+}
+
+// limitIP rejects requests over the per-IP limit with 429.
+func (s *Server) limitIP(pattern string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if wait, ok := s.limits.limiter.Allow("ip:"+s.limits.clientIP(r), s.limits.perIP); !ok {
+			tooManyRequests(w, pattern, "ip", wait)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limitUser rejects requests over the signed-in user's plan limit with
+// 429. It runs after requireAuthHandler.
+func (s *Server) limitUser(pattern string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := getUserID(r)
+		plan := s.userPlan(r.Context(), userID)
+		limit, ok := s.limits.perPlan[plan]
+		if !ok {
+			plan, limit = "free", s.limits.perPlan["free"]
+		}
+		if wait, ok := s.limits.limiter.Allow("user:"+strconv.Itoa(userID), limit); !ok {
+			tooManyRequests(w, pattern, plan, wait)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// userPlan returns a user's plan, cached for planCacheTTL. A user who
+// cannot be read is limited as free.
+func (s *Server) userPlan(ctx context.Context, userID int) string {
+	l := s.limits
+	l.mu.Lock()
+	c, ok := l.plans[userID]
+	l.mu.Unlock()
+	if ok && time.Since(c.at) < planCacheTTL {
+		return c.plan
+	}
+
+	plan := "free"
+	if u, err := s.userStore.GetUserByID(ctx, userID); err == nil && u.Plan != "" {
+		plan = u.Plan
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// Drop expired entries now and then so the cache stays bounded
+	if len(l.plans) > 10_000 {
+		for id, c := range l.plans {
+			if time.Since(c.at) >= planCacheTTL {
+				delete(l.plans, id)
+			}
+		}
+	}
+	l.plans[userID] = cachedPlan{plan: plan, at: time.Now()}
+	return plan
+}
+
+// clientIP returns the address rate limits are keyed by.
+func (l *rateLimits) clientIP(r *http.Request) string {
+	if l.trustProxy {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			// The proxy appends the address it saw; earlier entries come
+			// from the client
+			parts := strings.Split(fwd, ",")
+			if ip := strings.TrimSpace(parts[len(parts)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// tooManyRequests writes a 429 with Retry-After in whole seconds and counts
+// the rejection by route and limit ("ip" or the user's plan).
+func tooManyRequests(w http.ResponseWriter, pattern, limit string, wait time.Duration) {
+	metrics.RateLimited.Inc(pattern, limit)
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	respondError(w, http.StatusTooManyRequests, "rate limit exceeded")
+}
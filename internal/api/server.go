@@ -2,12 +2,21 @@
 package api
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"strings"
 
+	"github.com/RobinCoderZhao/devkit-suite/internal/newsbot/store"
 	"github.com/RobinCoderZhao/devkit-suite/internal/user"
 	"github.com/RobinCoderZhao/devkit-suite/internal/watchbot"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/benchmarks"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/differ"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/i18n"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/metrics"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/notify"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
 )
 
 // Server holds the dependencies for the API.
@@ -15,6 +24,14 @@ type Server struct {
 	userStore     *user.Store
 	watchbotStore *watchbot.Store
 	jwtSecret     []byte
+	adminIDs      map[int]bool
+	benchStore    *benchmarks.Store   // nil until SetBenchmarkStore
+	newsbotStore  *store.Store        // nil until SetNewsBotStore
+	db            *storage.DB         // for operator diagnostics; may be nil
+	frontendURL   string              // public site, for links in emails
+	emailCfg      *notify.EmailConfig // sends team invites; nil disables email
+	metricsToken  string              // required on /metrics when set
+	limits        *rateLimits
 	logger        *slog.Logger
 }
 
@@ -24,43 +41,259 @@ func NewServer(uStore *user.Store, wStore *watchbot.Store, jwtSecret string) *Se
 		userStore:     uStore,
 		watchbotStore: wStore,
 		jwtSecret:     []byte(jwtSecret),
+		limits:        newRateLimits(),
 		logger:        slog.Default(),
 	}
 }
 
+// SetAdmins grants the given users access to operator endpoints.
+func (s *Server) SetAdmins(userIDs []int) {
+	s.adminIDs = make(map[int]bool, len(userIDs))
+	for _, id := range userIDs {
+		s.adminIDs[id] = true
+	}
+}
+
+// SetDB gives operator endpoints access to the database the stores share.
+func (s *Server) SetDB(db *storage.DB) {
+	s.db = db
+}
+
+// SetBenchmarkStore enables the benchmark endpoints.
+func (s *Server) SetBenchmarkStore(store *benchmarks.Store) {
+	s.benchStore = store
+}
+
+// SetNewsBotStore enables the NewsBot subscription endpoints.
+func (s *Server) SetNewsBotStore(store *store.Store) {
+	s.newsbotStore = store
+}
+
+// SetFrontendURL sets the public site URL used in links the API sends out,
+// such as team invites.
+func (s *Server) SetFrontendURL(url string) {
+	s.frontendURL = url
+}
+
+// SetEmailConfig enables emailing team invites.
+func (s *Server) SetEmailConfig(cfg notify.EmailConfig) {
+	s.emailCfg = &cfg
+}
+
+// SetMetricsToken requires the bearer token on GET /metrics. Without one
+// the metrics are served to anyone who can reach the port.
+func (s *Server) SetMetricsToken(token string) {
+	s.metricsToken = token
+}
+
+func (s *Server) isAdmin(userID int) bool {
+	return s.adminIDs[userID]
+}
+
 // Routes returns the configured http.Handler (ServeMux) for the API.
+// Routes not marked public require a JWT. Every route is rate limited by
+// client IP and, once signed in, by the user's plan (see SetRateLimits).
+// Every route's latency is recorded in the metrics served at /metrics.
 func (s *Server) Routes() http.Handler {
 	mux := http.NewServeMux()
+	for _, rt := range s.routes() {
+		var h http.Handler = rt.handler
+		if !rt.public {
+			h = s.requireAuthHandler(s.limitUser(rt.pattern, h))
+		}
+		h = s.limitIP(rt.pattern, h)
+		mux.Handle(rt.pattern, metrics.InstrumentHandler(rt.pattern, h))
+	}
+	mux.HandleFunc("GET /openapi.json", s.handleOpenAPI())
+	mux.HandleFunc("GET /metrics", s.handleMetrics())
+	return localizeErrors(mux)
+}
+
+// handleMetrics serves the process's metrics for a Prometheus scrape.
+func (s *Server) handleMetrics() http.HandlerFunc {
+	serve := metrics.Handler()
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.metricsToken != "" {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				respondError(w, http.StatusUnauthorized, "missing authentication token")
+				return
+			}
+			if subtle.ConstantTimeCompare([]byte(token), []byte(s.metricsToken)) != 1 {
+				respondError(w, http.StatusUnauthorized, "invalid authentication token")
+				return
+			}
+		}
+		serve.ServeHTTP(w, r)
+	}
+}
+
+// routes lists the API's endpoints. The OpenAPI document and the generated
+// client in pkg/apiclient are built from it, so run go generate in
+// pkg/apiclient after changing a route or its request or response types.
+func (s *Server) routes() []route {
+	days := func(def string) param {
+		return param{name: "days", typ: "integer", description: "Window in days (default " + def + ")"}
+	}
+	token := param{name: "token", typ: "string", description: "Signed token from the link", required: true}
+	return []route{
+		// Auth
+		{pattern: "POST /api/auth/register", public: true, handler: s.handleRegister(), operation: operation{
+			id: "register", tag: "auth", summary: "Create an account and sign in",
+			request: RegisterRequest{}, response: AuthResponse{}, status: http.StatusCreated}},
+		{pattern: "POST /api/auth/login", public: true, handler: s.handleLogin(), operation: operation{
+			id: "login", tag: "auth", summary: "Sign in and get a token",
+			request: LoginRequest{}, response: AuthResponse{}}},
+
+		// User
+		{pattern: "GET /api/users/me", handler: s.handleGetMe(), operation: operation{
+			id: "getMe", tag: "user", summary: "Get the signed-in user's account and profile",
+			response: ProfileResponse{}}},
+		{pattern: "PUT /api/users/profile", handler: s.handleUpdateProfile(), operation: operation{
+			id: "updateProfile", tag: "user", summary: "Change profile fields; fields left out keep their value",
+			request: UpdateProfileRequest{}, response: ProfileResponse{}}},
+		{pattern: "POST /api/onboarding", handler: s.handleOnboarding(), operation: operation{
+			id: "onboard", tag: "user", summary: "Add template competitors for an industry",
+			request: OnboardingRequest{}, response: OnboardingResponse{}}},
+		{pattern: "GET /api/usage", handler: s.handleUsage(), operation: operation{
+			id: "getUsage", tag: "user", summary: "Get this month's LLM spend by feature against the quota and budget",
+			query:    []param{{name: "user_id", typ: "integer", description: "Another user's usage (admins only)"}},
+			response: user.MonthUsage{}}},
+		{pattern: "PUT /api/usage/budget", handler: s.handleSetBudget(), operation: operation{
+			id: "setLLMBudget", tag: "user", summary: "Set the monthly LLM budget; past it, changes are reported without analysis",
+			query:   []param{{name: "user_id", typ: "integer", description: "Another user's budget (admins only)"}},
+			request: BudgetRequest{}, response: user.MonthUsage{}}},
+		{pattern: "GET /api/usage/llm", handler: s.handleLLMUsage(), operation: operation{
+			id: "getLLMUsage", tag: "user", summary: "Get LLM token usage and the plan quota",
+			query:    []param{days("30, at most 366"), {name: "user_id", typ: "integer", description: "Another user's usage (admins only)"}},
+			response: user.UsageReport{}}},
 
-	// Auth routes (Public)
-	mux.HandleFunc("POST /api/auth/register", s.handleRegister())
-	mux.HandleFunc("POST /api/auth/login", s.handleLogin())
+		// Teams and invites
+		{pattern: "POST /api/teams", handler: s.handleCreateTeam(), operation: operation{
+			id: "createTeam", tag: "teams", summary: "Create a team with the user as admin",
+			request: CreateTeamRequest{}, response: CreateTeamResponse{}, status: http.StatusCreated}},
+		{pattern: "GET /api/teams", handler: s.handleListTeams(), operation: operation{
+			id: "listTeams", tag: "teams", summary: "List the user's teams",
+			response: TeamsResponse{}}},
+		{pattern: "GET /api/teams/{id}/members", handler: s.handleListTeamMembers(), operation: operation{
+			id: "listTeamMembers", tag: "teams", summary: "List a team's members",
+			response: MembersResponse{}}},
+		{pattern: "DELETE /api/teams/{id}/members/{userID}", handler: s.handleRemoveTeamMember(), operation: operation{
+			id: "removeTeamMember", tag: "teams", summary: "Remove a member, or leave the team",
+			response: MessageResponse{}}},
+		{pattern: "POST /api/teams/{id}/invites", handler: s.handleCreateInvite(), operation: operation{
+			id: "createInvite", tag: "teams", summary: "Invite an email address to a team (admins only)",
+			request: InviteRequest{}, response: CreateInviteResponse{}, status: http.StatusCreated}},
+		{pattern: "GET /api/teams/{id}/invites", handler: s.handleListTeamInvites(), operation: operation{
+			id: "listTeamInvites", tag: "teams", summary: "List a team's pending invites (admins only)",
+			response: InvitesResponse{}}},
+		{pattern: "DELETE /api/teams/{id}/invites/{inviteID}", handler: s.handleRevokeInvite(), operation: operation{
+			id: "revokeInvite", tag: "teams", summary: "Revoke a pending invite (admins only)",
+			response: MessageResponse{}}},
+		{pattern: "GET /api/invites", handler: s.handleMyInvites(), operation: operation{
+			id: "listMyInvites", tag: "teams", summary: "List invites sent to the user's email",
+			response: MyInvitesResponse{}}},
+		{pattern: "POST /api/invites/accept", handler: s.handleAcceptInvite(), operation: operation{
+			id: "acceptInvite", tag: "teams", summary: "Join the team of an invite",
+			request: AcceptInviteRequest{}, response: AcceptInviteResponse{}}},
+		{pattern: "GET /api/invites/info", public: true, handler: s.handleInviteInfo(), operation: operation{
+			id: "getInviteInfo", tag: "teams", summary: "Describe the invite behind an invite link",
+			query: []param{token}, response: InviteInfoResponse{}}},
 
-	// Protected routes (Require JWT)
-	protected := s.requireAuth(mux)
+		// WatchBot
+		{pattern: "GET /api/watchbot/dashboard", handler: s.handleDashboard(), operation: operation{
+			id: "getDashboard", tag: "watchbot", summary: "Get competitors with their latest change",
+			response: DashboardResponse{}}},
+		{pattern: "GET /api/watchbot/competitors", handler: s.handleListCompetitors(), operation: operation{
+			id: "listCompetitors", tag: "watchbot", summary: "List the competitors of the user and their teammates",
+			response: CompetitorsResponse{}}},
+		{pattern: "GET /api/watchbot/competitor/{id}", handler: s.handleCompetitorTimeline(), operation: operation{
+			id: "getCompetitorTimeline", tag: "watchbot", summary: "Get a competitor's change timeline",
+			response: TimelineResponse{}}},
+		{pattern: "POST /api/watchbot/competitors", handler: s.handleAddCompetitor(), operation: operation{
+			id: "addCompetitor", tag: "watchbot", summary: "Track a competitor page",
+			request: AddCompetitorRequest{}, response: MessageResponse{}, status: http.StatusCreated}},
+		{pattern: "GET /api/watchbot/changes/{id}/diff", handler: s.handleChangeDiff(), operation: operation{
+			id: "getChangeDiff", tag: "watchbot", summary: "Render a change's diff as HTML",
+			query: []param{{name: "layout", typ: "string", description: "Diff layout (default side-by-side)",
+				enum: []string{string(differ.LayoutSideBySide), string(differ.LayoutInline)}}},
+			response: ChangeDiffResponse{}}},
+		{pattern: "GET /api/watchbot/search", handler: s.handleSearch(), operation: operation{
+			id: "search", tag: "watchbot", summary: "Search page snapshots and change analyses, oldest first",
+			query: []param{{name: "q", typ: "string", description: "Words that must all appear", required: true},
+				{name: "limit", typ: "integer", description: "Maximum results (default 50, at most 200)"}},
+			response: SearchResponse{}}},
+		{pattern: "GET /api/watchbot/rules", handler: s.handleGetAlertRules(), operation: operation{
+			id: "listAlertRules", tag: "watchbot", summary: "List the user's alert rules",
+			response: AlertRulesResponse{}}},
+		{pattern: "POST /api/watchbot/rules", handler: s.handleAddAlertRule(), operation: operation{
+			id: "addAlertRule", tag: "watchbot", summary: "Add an alert rule",
+			request: AddAlertRuleRequest{}, response: AddAlertRuleResponse{}, status: http.StatusCreated}},
+		{pattern: "GET /api/watchbot/routes", handler: s.handleGetRoutes(), operation: operation{
+			id: "getRoutes", tag: "watchbot", summary: "Get the user's digest delivery channels",
+			response: RoutesResponse{}}},
+		{pattern: "PUT /api/watchbot/routes", handler: s.handleSetRoutes(), operation: operation{
+			id: "setRoutes", tag: "watchbot", summary: "Replace the user's digest delivery channels",
+			request: SetRoutesRequest{}, response: MessageResponse{}}},
+		{pattern: "GET /api/watchbot/engagement", handler: s.handleEngagement(), operation: operation{
+			id: "getEngagement", tag: "admin", summary: "Digest engagement per subscriber (admins only)",
+			query: []param{days("90")}, response: EngagementResponse{}}},
 
-	// User
-	mux.Handle("GET /api/users/me", s.requireAuthHandler(http.HandlerFunc(s.handleGetMe())))
-	mux.Handle("POST /api/onboarding", s.requireAuthHandler(http.HandlerFunc(s.handleOnboarding())))
+		// Admin
+		{pattern: "GET /api/admin/db-stats", handler: s.handleDBStats(), operation: operation{
+			id: "getDBStats", tag: "admin", summary: "Database connection pool statistics (admins only)",
+			response: DBStatsResponse{}}},
 
-	// WatchBot
-	mux.Handle("GET /api/watchbot/dashboard", s.requireAuthHandler(http.HandlerFunc(s.handleDashboard())))
-	mux.Handle("GET /api/watchbot/competitors", s.requireAuthHandler(http.HandlerFunc(s.handleListCompetitors())))
-	mux.Handle("GET /api/watchbot/competitor/{id}", s.requireAuthHandler(http.HandlerFunc(s.handleCompetitorTimeline())))
-	mux.Handle("POST /api/watchbot/competitors", s.requireAuthHandler(http.HandlerFunc(s.handleAddCompetitor())))
-	mux.Handle("GET /api/watchbot/rules", s.requireAuthHandler(http.HandlerFunc(s.handleGetAlertRules())))
-	mux.Handle("POST /api/watchbot/rules", s.requireAuthHandler(http.HandlerFunc(s.handleAddAlertRule())))
+		// NewsBot
+		{pattern: "GET /api/newsbot/feed", handler: s.handleNewsFeed(), operation: operation{
+			id: "getNewsFeed", tag: "newsbot", summary: "Get the news feed",
+			query:    []param{{name: "lang", typ: "string", description: "Feed language, e.g. zh"}},
+			response: NewsFeedResponse{}}},
+		{pattern: "POST /api/newsbot/subscribe", handler: s.handleNewsBotSubscribe(), operation: operation{
+			id: "subscribeNewsBot", tag: "newsbot", summary: "Subscribe a target to the NewsBot digest",
+			request: NewsBotSubscribeRequest{}, response: MessageResponse{}}},
+		{pattern: "GET /api/newsbot/subscriptions", handler: s.handleListNewsBotSubscriptions(), operation: operation{
+			id: "listNewsBotSubscriptions", tag: "newsbot", summary: "List your NewsBot subscriptions",
+			response: NewsBotSubscriptionsResponse{}}},
+		{pattern: "DELETE /api/newsbot/subscriptions", handler: s.handleDeleteNewsBotSubscription(), operation: operation{
+			id: "deleteNewsBotSubscription", tag: "newsbot", summary: "Delete a NewsBot subscription",
+			query:    []param{{name: "id", typ: "integer", description: "Subscription ID", required: true}},
+			response: MessageResponse{}}},
 
-	// NewsBot
-	mux.Handle("GET /api/newsbot/feed", s.requireAuthHandler(http.HandlerFunc(s.handleNewsFeed())))
+		// Benchmarks
+		{pattern: "GET /api/benchmarks/trend", handler: s.handleBenchmarkTrend(), operation: operation{
+			id: "getBenchmarkTrend", tag: "benchmarks", summary: "Get models' score history on a benchmark",
+			query: []param{{name: "benchmark", typ: "string", description: "Benchmark ID or name, e.g. gpqa_diamond", required: true},
+				{name: "variant", typ: "string", description: "Benchmark variant (default the first)"},
+				{name: "model", typ: "string", description: "Models, comma-separated (default every model with history)"},
+				{name: "format", typ: "string", description: "Response format; png is a line chart (default json)", enum: []string{"json", "png"}}},
+			response: benchmarks.TrendReport{}}},
 
-	// Billing (Protected)
-	mux.Handle("POST /api/billing/create-checkout-session", s.requireAuthHandler(http.HandlerFunc(s.handleCreateCheckoutSession())))
+		// Billing
+		{pattern: "POST /api/billing/create-checkout-session", handler: s.handleCreateCheckoutSession(), operation: operation{
+			id: "createCheckoutSession", tag: "billing", summary: "Start a Stripe Checkout for a plan",
+			request: CheckoutRequest{}, response: CheckoutResponse{}}},
 
-	// Webhooks (Public)
-	mux.HandleFunc("POST /api/webhooks/stripe", s.handleStripeWebhook())
+		// Unsubscribe (authenticated by signed token)
+		{pattern: "GET /api/unsubscribe", public: true, handler: s.handleUnsubscribeInfo(), operation: operation{
+			id: "getUnsubscribe", tag: "email", summary: "Check an unsubscribe link without acting on it",
+			query: []param{token}, response: UnsubscribeResponse{}}},
+		{pattern: "POST /api/unsubscribe", public: true, handler: s.handleUnsubscribe(), operation: operation{
+			id: "unsubscribe", tag: "email", summary: "Unsubscribe from a mailing list (RFC 8058 one-click)",
+			query: []param{token}, response: UnsubscribeResponse{}}},
 
-	return protected
+		// Email tracking (authenticated by signed token)
+		{pattern: "GET /api/t/open", public: true, handler: s.handleTrackOpen(), operation: operation{
+			id: "trackOpen", tag: "email", summary: "Record a digest open and serve the tracking pixel",
+			query: []param{{name: "t", typ: "string", required: true}}, produces: "image/gif"}},
+		{pattern: "GET /api/t/click", public: true, handler: s.handleTrackClick(), operation: operation{
+			id: "trackClick", tag: "email", summary: "Record a link click and redirect to its target",
+			query: []param{{name: "t", typ: "string", required: true}}, status: http.StatusFound}},
+
+		// Webhooks (authenticated by Stripe-Signature)
+		{pattern: "POST /api/webhooks/stripe", public: true, handler: s.handleStripeWebhook(), operation: operation{
+			id: "stripeWebhook", tag: "billing", summary: "Receive Stripe events"}},
+	}
 }
 
 // --- Helpers ---
@@ -73,6 +306,44 @@ func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	}
 }
 
+// respondError writes a JSON error. Messages are English; when the client
+// asked for another language (see localizeErrors) and the catalog has an
+// "api.<message>" entry, the translation is sent instead.
 func respondError(w http.ResponseWriter, status int, message string) {
-	respondJSON(w, status, map[string]string{"error": message})
+	// Route handlers get the localizedWriter wrapped by the metrics
+	// recorder
+	for inner := w; inner != nil; {
+		if lw, ok := inner.(*localizedWriter); ok {
+			if translated, found := i18n.Lookup(lw.lang, "api."+message); found {
+				message = translated
+			}
+			break
+		}
+		u, ok := inner.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			break
+		}
+		inner = u.Unwrap()
+	}
+	respondJSON(w, status, ErrorResponse{Error: message})
+}
+
+// localizedWriter carries the client's preferred language to respondError.
+type localizedWriter struct {
+	http.ResponseWriter
+	lang i18n.Language
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *localizedWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// localizeErrors translates error messages for clients whose Accept-Language
+// prefers a supported language other than English.
+func localizeErrors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if lang, ok := i18n.MatchLanguage(r.Header.Get("Accept-Language")); ok && lang != i18n.LangEN {
+			w = &localizedWriter{ResponseWriter: w, lang: lang}
+		}
+		next.ServeHTTP(w, r)
+	})
 }
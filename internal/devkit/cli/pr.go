@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	devkitcfg "github.com/RobinCoderZhao/devkit-suite/internal/devkit/config"
+	"github.com/RobinCoderZhao/devkit-suite/internal/devkit/forge"
+	"github.com/RobinCoderZhao/devkit-suite/internal/devkit/git"
+	"github.com/RobinCoderZhao/devkit-suite/internal/devkit/prompt"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/llm"
+)
+
+func prCmd() *cobra.Command {
+	var opts prOptions
+
+	cmd := &cobra.Command{
+		Use:   "pr",
+		Short: "AI 生成 Pull Request 标题与描述",
+		Long: "收集当前分支相对目标分支 (默认远端的默认分支) 的提交与 diff，使用 LLM 生成 PR 标题、描述和测试说明。" +
+			"加 --push 时推送分支并通过 GitHub (GITHUB_TOKEN) 或 GitLab (GITLAB_TOKEN) API 创建 PR。",
+		Example: "  devkit pr\n" +
+			"  devkit pr --base develop\n" +
+			"  devkit pr --push --draft",
+		Args: cobra.NoArgs,
+		// git, LLM and API failures are not usage errors
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPR(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.base, "base", "", "目标分支 (默认 .devkit.yaml 的 pr.base 或远端默认分支)")
+	cmd.Flags().BoolVar(&opts.push, "push", false, "推送当前分支并创建 PR")
+	cmd.Flags().BoolVar(&opts.draft, "draft", false, "创建为草稿 PR (需要 --push)")
+	cmd.Flags().BoolVarP(&opts.direct, "yes", "y", false, "不确认直接创建")
+	return cmd
+}
+
+type prOptions struct {
+	base   string
+	push   bool
+	draft  bool
+	direct bool
+}
+
+// PRDescription holds the generated pull request text.
+type PRDescription struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Testing     string `json:"testing"`
+}
+
+// Body renders the description and testing notes as the PR body.
+func (d PRDescription) Body() string {
+	body := strings.TrimSpace(d.Description)
+	if testing := strings.TrimSpace(d.Testing); testing != "" {
+		body += "\n\n## Testing\n\n" + testing
+	}
+	return body
+}
+
+func runPR(opts prOptions) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	cfg, err := devkitcfg.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	repo, err := git.OpenCurrent()
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	branch, err := repo.CurrentBranch(ctx)
+	if err != nil {
+		return err
+	}
+	if branch == "" {
+		return fmt.Errorf("❌ 当前不在任何分支上 (detached HEAD)")
+	}
+
+	remote := cfg.PR.Remote
+	base := opts.base
+	if base == "" {
+		base = cfg.PR.Base
+	}
+	if base == "" {
+		if base, err = repo.DefaultBranch(ctx, remote); err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+	}
+	if branch == base {
+		return fmt.Errorf("❌ 当前分支就是目标分支 %s，请先切换到功能分支", base)
+	}
+	baseRef, err := repo.BaseRef(ctx, remote, base)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	// Check the API settings before spending tokens on the description
+	var client *forge.Client
+	if opts.push {
+		if client, err = forgeClient(ctx, repo, remote, cfg.PR.Provider); err != nil {
+			return err
+		}
+	}
+
+	commits, err := repo.CommitsSince(ctx, baseRef)
+	if err != nil {
+		return fmt.Errorf("get commits: %w", err)
+	}
+	if strings.TrimSpace(commits) == "" {
+		fmt.Printf("⚠️  %s 相对 %s 没有新的提交。\n", branch, baseRef)
+		return nil
+	}
+
+	diff, err := repo.DiffSince(ctx, baseRef)
+	if err != nil {
+		return fmt.Errorf("get diff: %w", err)
+	}
+	if len(commits) > 5000 {
+		commits = commits[:5000] + "\n... (truncated)"
+	}
+	if len(diff) > 20000 {
+		diff = diff[:20000] + "\n... (truncated)"
+	}
+
+	stat, _ := repo.DiffStatSince(ctx, baseRef)
+	fmt.Printf("🔀 %s → %s\n%s\n", branch, base, strings.TrimRight(stat, "\n"))
+
+	fmt.Println("\n🤖 Generating PR description...")
+
+	if cfg.LLM.APIKey == "" {
+		return fmt.Errorf("❌ LLM API Key未设置。设置环境变量 LLM_API_KEY 或 OPENAI_API_KEY，或在 .devkit.yaml 中配置")
+	}
+
+	llmClient, err := llm.NewClient(cfg.LLM)
+	if err != nil {
+		return fmt.Errorf("create LLM client: %w", err)
+	}
+	defer llmClient.Close()
+
+	resp, err := llmClient.Generate(ctx, &llm.Request{
+		Messages: []llm.Message{
+			{Role: "user", Content: fmt.Sprintf(prompt.PRPrompt, commits, diff)},
+		},
+		JSONMode:    true,
+		Temperature: 0.3,
+	})
+	if err != nil {
+		return fmt.Errorf("LLM generation failed: %w", err)
+	}
+
+	var desc PRDescription
+	if err := json.Unmarshal([]byte(resp.Content), &desc); err != nil || strings.TrimSpace(desc.Title) == "" {
+		fmt.Println(resp.Content)
+		return fmt.Errorf("❌ LLM 返回的不是有效的 PR 描述")
+	}
+	desc.Title = strings.TrimSpace(desc.Title)
+
+	fmt.Printf("\n✨ %s\n\n%s\n\n", desc.Title, desc.Body())
+	fmt.Printf("📊 Tokens: %d in / %d out | Cost: $%.4f\n\n", resp.TokensIn, resp.TokensOut, resp.Cost)
+
+	if !opts.push {
+		fmt.Println("💡 使用 `devkit pr --push` 推送分支并创建 PR。")
+		return nil
+	}
+
+	if !opts.direct {
+		fmt.Printf("🚀 Push %s and open the PR into %s? [Y/n] ", branch, base)
+		var answer string
+		fmt.Scanln(&answer)
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "", "y", "yes":
+		default:
+			fmt.Println("❌ Cancelled.")
+			return nil
+		}
+	}
+
+	fmt.Printf("📤 Pushing %s to %s...\n", branch, remote)
+	if err := repo.Push(ctx, remote, branch); err != nil {
+		return fmt.Errorf("push: %w", err)
+	}
+
+	url, err := client.Create(ctx, forge.PullRequest{
+		Title: desc.Title,
+		Body:  desc.Body(),
+		Head:  branch,
+		Base:  base,
+		Draft: opts.draft,
+	})
+	if err != nil {
+		return fmt.Errorf("❌ create PR: %w", err)
+	}
+	fmt.Printf("✅ PR created: %s\n", url)
+	return nil
+}
+
+// forgeClient returns an API client for the repository behind remote,
+// authenticated with the token its service needs.
+func forgeClient(ctx context.Context, repo *git.Repo, remote, provider string) (*forge.Client, error) {
+	remoteURL, err := repo.RemoteURL(ctx, remote)
+	if err != nil {
+		return nil, fmt.Errorf("❌ 找不到远端 %s: %w", remote, err)
+	}
+	r, err := forge.ParseRemote(remoteURL, forge.Kind(strings.ToLower(provider)))
+	if err != nil {
+		return nil, fmt.Errorf("❌ %w", err)
+	}
+	token := os.Getenv(r.Kind.TokenEnv())
+	if token == "" {
+		return nil, fmt.Errorf("❌ %s 未设置，无法在 %s 上创建 PR", r.Kind.TokenEnv(), r.Host)
+	}
+	return forge.NewClient(r, token), nil
+}
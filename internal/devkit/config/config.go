@@ -17,6 +17,7 @@ type DevKitConfig struct {
 	} `yaml:"license"`
 	Commit CommitConfig `yaml:"commit"`
 	Review ReviewConfig `yaml:"review"`
+	PR     PRConfig     `yaml:"pr"`
 }
 
 // CommitConfig holds settings for the commit command.
@@ -32,6 +33,13 @@ type ReviewConfig struct {
 	OutputFormat string `yaml:"output_format"` // "text", "json"
 }
 
+// PRConfig holds settings for the pr command.
+type PRConfig struct {
+	Base     string `yaml:"base"`     // Target branch; defaults to the remote's default branch
+	Remote   string `yaml:"remote"`   // Remote to push to and open the PR on
+	Provider string `yaml:"provider"` // "github" or "gitlab", for hosts named neither
+}
+
 // DefaultConfig returns a DevKitConfig with sensible defaults.
 func DefaultConfig() DevKitConfig {
 	return DevKitConfig{
@@ -50,6 +58,9 @@ func DefaultConfig() DevKitConfig {
 		Review: ReviewConfig{
 			OutputFormat: "text",
 		},
+		PR: PRConfig{
+			Remote: "origin",
+		},
 	}
 }
 
@@ -57,6 +68,22 @@ func DefaultConfig() DevKitConfig {
 func Load() (DevKitConfig, error) {
 	cfg := DefaultConfig()
 
+	// Shared suite settings (devkit-suite.yaml and LLM_* env vars) come
+	// first; .devkit.yaml overrides them.
+	if _, err := appconfig.LoadSuiteEnv(); err != nil {
+		return cfg, err
+	}
+	if provider := os.Getenv("LLM_PROVIDER"); provider != "" {
+		cfg.LLM.Provider = llm.Provider(provider)
+	}
+	if model := os.Getenv("LLM_MODEL"); model != "" {
+		cfg.LLM.Model = model
+	}
+	cfg.LLM.APIKey = os.Getenv("LLM_API_KEY")
+	cfg.LLM.BaseURL = os.Getenv("LLM_BASE_URL")
+	cfg.LLM.Deployment = os.Getenv("LLM_DEPLOYMENT")
+	cfg.LLM.APIVersion = os.Getenv("LLM_API_VERSION")
+
 	// Check project-level config first
 	if _, err := os.Stat(".devkit.yaml"); err == nil {
 		if err := appconfig.Load(".devkit.yaml", &cfg); err != nil {
@@ -0,0 +1,220 @@
+// Package forge opens pull requests on GitHub and merge requests on GitLab
+// for the DevKit pr command.
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Kind is the hosting service a remote lives on.
+type Kind string
+
+const (
+	GitHub Kind = "github"
+	GitLab Kind = "gitlab"
+)
+
+// TokenEnv returns the environment variable holding the API token for k.
+func (k Kind) TokenEnv() string {
+	if k == GitLab {
+		return "GITLAB_TOKEN"
+	}
+	return "GITHUB_TOKEN"
+}
+
+// Remote is a repository on GitHub or GitLab, including self-hosted
+// instances.
+type Remote struct {
+	Kind    Kind
+	Host    string
+	Project string // owner/repo, or group/subgroup/repo on GitLab
+	scheme  string
+}
+
+// ParseRemote parses a git remote URL, such as git@github.com:owner/repo.git
+// or https://gitlab.example.com/group/repo.git. The service is told apart by
+// the host name; kind overrides it for hosts that name neither.
+func ParseRemote(rawURL string, kind Kind) (Remote, error) {
+	r := Remote{scheme: "https"}
+	s := strings.TrimSpace(rawURL)
+	if strings.Contains(s, "://") {
+		u, err := url.Parse(s)
+		if err != nil {
+			return Remote{}, fmt.Errorf("parse remote URL %q: %w", rawURL, err)
+		}
+		if u.Scheme == "http" {
+			r.scheme = "http"
+		}
+		r.Host = u.Host
+		if u.Scheme != "http" && u.Scheme != "https" {
+			// An ssh:// port is not the port the API listens on
+			r.Host = u.Hostname()
+		}
+		r.Project = u.Path
+	} else {
+		// scp-like syntax: [user@]host:path
+		host, path, ok := strings.Cut(s, ":")
+		if !ok {
+			return Remote{}, fmt.Errorf("unsupported remote URL %q", rawURL)
+		}
+		if _, h, found := strings.Cut(host, "@"); found {
+			host = h
+		}
+		r.Host, r.Project = host, path
+	}
+	r.Project = strings.TrimSuffix(strings.Trim(r.Project, "/"), ".git")
+	if r.Host == "" || !strings.Contains(r.Project, "/") {
+		return Remote{}, fmt.Errorf("unsupported remote URL %q", rawURL)
+	}
+
+	switch host := strings.ToLower(r.Host); {
+	case kind != "":
+		r.Kind = kind
+	case strings.Contains(host, "github"):
+		r.Kind = GitHub
+	case strings.Contains(host, "gitlab"):
+		r.Kind = GitLab
+	default:
+		return Remote{}, fmt.Errorf("cannot tell whether %s is GitHub or GitLab; set pr.provider in .devkit.yaml", r.Host)
+	}
+	if r.Kind != GitHub && r.Kind != GitLab {
+		return Remote{}, fmt.Errorf("unknown provider %q (github or gitlab)", r.Kind)
+	}
+	return r, nil
+}
+
+// PullRequest is what Create opens.
+type PullRequest struct {
+	Title string
+	Body  string
+	Head  string // branch with the changes
+	Base  string // branch to merge into
+	Draft bool
+}
+
+// Client creates pull requests through the GitHub or GitLab REST API.
+type Client struct {
+	remote Remote
+	token  string
+	http   *http.Client
+}
+
+// NewClient creates a Client for remote that authenticates with token.
+func NewClient(remote Remote, token string) *Client {
+	return &Client{
+		remote: remote,
+		token:  token,
+		http:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// apiURL returns the REST API root: api.github.com for github.com, /api/v3
+// for GitHub Enterprise and /api/v4 for GitLab.
+func (c *Client) apiURL() string {
+	if c.remote.Kind == GitLab {
+		return c.remote.scheme + "://" + c.remote.Host + "/api/v4"
+	}
+	if c.remote.Host == "github.com" {
+		return "https://api.github.com"
+	}
+	return c.remote.scheme + "://" + c.remote.Host + "/api/v3"
+}
+
+// Create opens the pull request and returns its web URL.
+func (c *Client) Create(ctx context.Context, pr PullRequest) (string, error) {
+	if c.remote.Kind == GitLab {
+		title := pr.Title
+		if pr.Draft {
+			title = "Draft: " + title
+		}
+		var created struct {
+			WebURL string `json:"web_url"`
+		}
+		err := c.post(ctx, "/projects/"+url.PathEscape(c.remote.Project)+"/merge_requests", map[string]any{
+			"title":         title,
+			"description":   pr.Body,
+			"source_branch": pr.Head,
+			"target_branch": pr.Base,
+		}, &created)
+		return created.WebURL, err
+	}
+
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+	err := c.post(ctx, "/repos/"+c.remote.Project+"/pulls", map[string]any{
+		"title": pr.Title,
+		"body":  pr.Body,
+		"head":  pr.Head,
+		"base":  pr.Base,
+		"draft": pr.Draft,
+	}, &created)
+	return created.HTMLURL, err
+}
+
+func (c *Client) post(ctx context.Context, path string, payload, out any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.apiURL()+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.remote.Kind == GitLab {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s API request: %w", c.remote.Kind, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s API error (%d): %s", c.remote.Kind, resp.StatusCode, apiError(respBody))
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("decode %s response: %w", c.remote.Kind, err)
+	}
+	return nil
+}
+
+// apiError extracts the message from a GitHub or GitLab error body, e.g.
+// GitHub's validation errors for a pull request that already exists.
+func apiError(body []byte) string {
+	var e struct {
+		Message any `json:"message"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if json.Unmarshal(body, &e) != nil || e.Message == nil {
+		return strings.TrimSpace(string(body))
+	}
+	msg, ok := e.Message.(string)
+	if !ok {
+		// GitLab reports validation errors as an object or a list
+		b, _ := json.Marshal(e.Message)
+		msg = string(b)
+	}
+	for _, item := range e.Errors {
+		if item.Message != "" {
+			msg += ": " + item.Message
+		}
+	}
+	return msg
+}
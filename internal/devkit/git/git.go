@@ -103,6 +103,63 @@ func (r *Repo) CurrentBranch(ctx context.Context) (string, error) {
 	return strings.TrimSpace(out), nil
 }
 
+// DefaultBranch returns the branch remote's HEAD points to, falling back to
+// main or master when the remote HEAD is not known locally.
+func (r *Repo) DefaultBranch(ctx context.Context, remote string) (string, error) {
+	if out, err := r.run(ctx, "symbolic-ref", "--short", "refs/remotes/"+remote+"/HEAD"); err == nil {
+		return strings.TrimPrefix(strings.TrimSpace(out), remote+"/"), nil
+	}
+	for _, branch := range []string{"main", "master"} {
+		if _, err := r.BaseRef(ctx, remote, branch); err == nil {
+			return branch, nil
+		}
+	}
+	return "", fmt.Errorf("cannot determine the default branch; pass --base")
+}
+
+// BaseRef returns the ref to compare a branch against: the remote-tracking
+// branch if it exists, since the local one may be stale, else the local one.
+func (r *Repo) BaseRef(ctx context.Context, remote, branch string) (string, error) {
+	for _, ref := range []string{remote + "/" + branch, branch} {
+		if _, err := r.run(ctx, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err == nil {
+			return ref, nil
+		}
+	}
+	return "", fmt.Errorf("branch %s not found", branch)
+}
+
+// CommitsSince returns the subject and body of each commit on HEAD that is
+// not on base, oldest first, skipping merges.
+func (r *Repo) CommitsSince(ctx context.Context, base string) (string, error) {
+	return r.run(ctx, "log", "--reverse", "--no-merges", "--format=%h %s%n%b", base+"..HEAD")
+}
+
+// DiffSince returns the diff of HEAD against its merge base with base,
+// which is what a pull request into base would show.
+func (r *Repo) DiffSince(ctx context.Context, base string) (string, error) {
+	return r.run(ctx, "diff", base+"...HEAD")
+}
+
+// DiffStatSince returns the --stat summary of DiffSince.
+func (r *Repo) DiffStatSince(ctx context.Context, base string) (string, error) {
+	return r.run(ctx, "diff", "--stat", base+"...HEAD")
+}
+
+// RemoteURL returns the fetch URL of a remote.
+func (r *Repo) RemoteURL(ctx context.Context, remote string) (string, error) {
+	out, err := r.run(ctx, "remote", "get-url", remote)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// Push pushes branch to remote and sets it as the upstream.
+func (r *Repo) Push(ctx context.Context, remote, branch string) error {
+	_, err := r.run(ctx, "push", "--set-upstream", remote, branch)
+	return err
+}
+
 // HasStagedChanges returns true if there are staged changes.
 func (r *Repo) HasStagedChanges(ctx context.Context) (bool, error) {
 	files, err := r.StagedFiles(ctx)
@@ -46,3 +46,28 @@ const ReviewPrompt = `你是一位资深代码审查员（Senior Code Reviewer
 
 Git diff:
 %s`
+
+// PRPrompt is used to generate a pull request title and description from
+// the commits and diff of a branch. The first argument is the commit log,
+// the second the diff.
+const PRPrompt = `你是一位高级软件工程师。根据下面分支相对目标分支的提交记录和 git diff，撰写一个 Pull Request。
+
+规则：
+1. title 用英文，简洁明了，不超过 72 个字符，不要加句号
+2. description 用 Markdown，先用一两句话说明改了什么、为什么改，再用列表列出主要变更，不要罗列文件清单
+3. testing 说明如何验证这些变更（需要运行的测试、手动检查的步骤），没有可说的就写明需要人工验证的地方
+4. description 和 testing 的语言与提交记录一致
+5. 只描述 diff 中实际存在的变更，不要编造
+
+输出 JSON 格式：
+{
+  "title": "PR 标题",
+  "description": "PR 描述 (Markdown)",
+  "testing": "测试说明 (Markdown)"
+}
+
+提交记录：
+%s
+
+Git diff:
+%s`
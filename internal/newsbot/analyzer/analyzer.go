@@ -34,7 +34,9 @@ type Headline struct {
 
 // Analyzer processes raw articles into a curated daily digest.
 type Analyzer struct {
-	client llm.Client
+	client    llm.Client
+	embedder  llm.Embedder // merges duplicate coverage when set
+	threshold float64
 }
 
 // NewAnalyzer creates a new article analyzer with the given LLM client.
@@ -51,21 +53,33 @@ func (a *Analyzer) Analyze(ctx context.Context, articles []sources.Article) (*Da
 		}, nil
 	}
 
+	// Merge reports of the same story so it reaches the LLM once
+	stories := a.groupStories(ctx, articles)
+
 	// Build article summaries for LLM input
 	var sb strings.Builder
-	for i, art := range articles {
+	for i, st := range stories {
 		if i >= 50 { // Limit to 50 articles to stay within context window
 			break
 		}
+		art := st.lead
 		content := art.Content
 		if len(content) > 500 {
 			content = content[:500] + "..."
 		}
-		sb.WriteString(fmt.Sprintf("---\n[%d] Title: %s\nSource: %s\nURL: %s\nContent: %s\n",
-			i+1, art.Title, art.Source, art.URL, content))
+		sb.WriteString(fmt.Sprintf("---\n[%d] Title: %s\nSource: %s\nURL: %s\n",
+			i+1, art.Title, art.Source, art.URL))
+		if len(st.also) > 0 {
+			others := make([]string, len(st.also))
+			for j, dup := range st.also {
+				others[j] = dup.Source
+			}
+			sb.WriteString(fmt.Sprintf("Also reported by: %s\n", strings.Join(others, ", ")))
+		}
+		sb.WriteString(fmt.Sprintf("Content: %s\n", content))
 	}
 
-	resp, err := a.client.Generate(ctx, &llm.Request{
+	resp, err := a.client.Generate(llm.ForUser(ctx, 0, "newsbot.analysis"), &llm.Request{
 		System: analyzerSystemPrompt,
 		Messages: []llm.Message{
 			{Role: "user", Content: fmt.Sprintf("今天是 %s。\n\n以下是今天收集到的 AI 相关新闻：\n\n%s", time.Now().Format("2006-01-02"), sb.String())},
@@ -126,7 +140,7 @@ const analyzerSystemPrompt = `你是一位 AI 领域的资深编辑，负责制
 
 你的任务：
 1. 从给定的新闻列表中，筛选出最重要的 5-10 条 AI 相关新闻
-2. 去重：相同事件的多篇报道合并为一条
+2. 去重：相同事件的多篇报道合并为一条（"Also reported by" 列出的是同一事件的其他来源，报道越多通常越重要）
 3. 按重要性排序（high > medium > low）
 4. 为每条新闻写一句话摘要（中文，30 字以内）
 5. 生成总结（summary 字段）
@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/RobinCoderZhao/devkit-suite/internal/newsbot/sources"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/llm"
+)
+
+// DefaultDedupThreshold is the cosine similarity above which two articles
+// are taken to cover the same story. Rewrites of one announcement score
+// about 0.85-0.95 with current embedding models; distinct stories on the
+// same topic stay below 0.8.
+const DefaultDedupThreshold = 0.85
+
+// dedupTextRunes is how much of an article is embedded: the title and the
+// opening, where the story is stated.
+const dedupTextRunes = 600
+
+// story is one news event: the article shown to the LLM and the other
+// articles covering the same event.
+type story struct {
+	lead sources.Article
+	also []sources.Article
+}
+
+// SetEmbedder enables merging duplicate coverage before summarization:
+// articles whose embeddings are at least threshold similar are sent to the
+// LLM once, with their other sources listed. A nil embedder turns it off.
+func (a *Analyzer) SetEmbedder(e llm.Embedder, threshold float64) {
+	if threshold <= 0 || threshold > 1 {
+		threshold = DefaultDedupThreshold
+	}
+	a.embedder, a.threshold = e, threshold
+}
+
+// groupStories merges articles covering the same story. Without an
+// embedder, or if embedding fails, every article is its own story.
+func (a *Analyzer) groupStories(ctx context.Context, articles []sources.Article) []story {
+	if a.embedder == nil || len(articles) < 2 {
+		return singleStories(articles)
+	}
+	texts := make([]string, len(articles))
+	for i, art := range articles {
+		texts[i] = dedupText(art)
+	}
+	vecs, err := a.embedder.Embed(ctx, texts)
+	if err != nil {
+		slog.Warn("embedding articles failed, skipping duplicate merge", "error", err)
+		return singleStories(articles)
+	}
+
+	groups := clusterBySimilarity(vecs, a.threshold)
+	stories := make([]story, 0, len(groups))
+	for _, g := range groups {
+		members := make([]sources.Article, len(g))
+		for i, idx := range g {
+			members[i] = articles[idx]
+		}
+		// Lead with the best-ranked, most detailed report
+		slices.SortStableFunc(members, func(x, y sources.Article) int {
+			if c := cmp.Compare(y.Score, x.Score); c != 0 {
+				return c
+			}
+			return cmp.Compare(len(y.Content), len(x.Content))
+		})
+		stories = append(stories, story{lead: members[0], also: members[1:]})
+	}
+	if merged := len(articles) - len(stories); merged > 0 {
+		slog.Info("merged duplicate coverage", "articles", len(articles), "stories", len(stories))
+	}
+	return stories
+}
+
+// clusterBySimilarity groups vectors greedily in order: each joins the
+// group whose first member it is most similar to, if at least threshold,
+// or starts a new group. Comparing with the first member only keeps a
+// chain of loosely related articles from collapsing into one group.
+func clusterBySimilarity(vecs [][]float32, threshold float64) [][]int {
+	var groups [][]int
+	for i, v := range vecs {
+		best, bestSim := -1, threshold
+		for g, members := range groups {
+			if sim := llm.CosineSimilarity(v, vecs[members[0]]); sim >= bestSim {
+				best, bestSim = g, sim
+			}
+		}
+		if best < 0 {
+			groups = append(groups, []int{i})
+		} else {
+			groups[best] = append(groups[best], i)
+		}
+	}
+	return groups
+}
+
+func singleStories(articles []sources.Article) []story {
+	stories := make([]story, len(articles))
+	for i, art := range articles {
+		stories[i] = story{lead: art}
+	}
+	return stories
+}
+
+func dedupText(art sources.Article) string {
+	text := art.Title + "\n" + art.Summary + "\n" + art.Content
+	if r := []rune(text); len(r) > dedupTextRunes {
+		text = string(r[:dedupTextRunes])
+	}
+	return text
+}
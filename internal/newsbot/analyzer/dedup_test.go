@@ -0,0 +1,132 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/RobinCoderZhao/devkit-suite/internal/newsbot/sources"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/llm"
+)
+
+// fakeEmbedder embeds each text as the vector vecs holds for its first
+// line, the article title, or as an empty vector.
+type fakeEmbedder struct {
+	vecs  map[string][]float32
+	err   error
+	calls int
+}
+
+func (f *fakeEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		title, _, _ := strings.Cut(text, "\n")
+		out[i] = f.vecs[title]
+	}
+	return out, nil
+}
+
+func (f *fakeEmbedder) Provider() llm.Provider { return llm.OpenAI }
+
+func TestGroupStories(t *testing.T) {
+	embedder := &fakeEmbedder{vecs: map[string][]float32{
+		// Two rewrites of one launch, cosine similarity about 0.99
+		"OpenAI launches GPT-6":          {1, 0.1, 0},
+		"GPT-6 is out, says OpenAI":      {0.98, 0.15, 0.02},
+		"Anthropic raises Series F":      {0, 1, 0.1},
+		"GPT-6 pricing undercuts rivals": {0.7, 0.7, 0}, // same topic, about 0.77
+		"Untitled":                       {},            // the provider returned no vector
+		"Untitled too":                   {0, 0, 0},
+	}}
+	a := NewAnalyzer(nil)
+	a.SetEmbedder(embedder, 0)
+
+	articles := []sources.Article{
+		{Title: "OpenAI launches GPT-6", Source: "hn", Score: 120, Content: "short"},
+		{Title: "Anthropic raises Series F", Source: "hn", Score: 80},
+		{Title: "GPT-6 is out, says OpenAI", Source: "reddit", Score: 300},
+		{Title: "GPT-6 pricing undercuts rivals", Source: "rss"},
+		{Title: "Untitled", Source: "rss"},
+		{Title: "Untitled too", Source: "rss"},
+		{Title: "Untitled", Source: "github"},
+	}
+	stories := a.groupStories(context.Background(), articles)
+
+	type grouped struct {
+		Lead string
+		Also []string
+	}
+	var got []grouped
+	for _, s := range stories {
+		g := grouped{Lead: s.lead.Source + ":" + s.lead.Title}
+		for _, art := range s.also {
+			g.Also = append(g.Also, art.Source+":"+art.Title)
+		}
+		got = append(got, g)
+	}
+	want := []grouped{
+		// The higher-scored duplicate leads
+		{Lead: "reddit:GPT-6 is out, says OpenAI", Also: []string{"hn:OpenAI launches GPT-6"}},
+		{Lead: "hn:Anthropic raises Series F"},
+		{Lead: "rss:GPT-6 pricing undercuts rivals"},
+		// Articles without an embedding are never merged, even with each other
+		{Lead: "rss:Untitled"},
+		{Lead: "rss:Untitled too"},
+		{Lead: "github:Untitled"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groupStories =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestGroupStoriesFallback(t *testing.T) {
+	articles := []sources.Article{{Title: "a"}, {Title: "a"}}
+	tests := []struct {
+		name     string
+		embedder *fakeEmbedder
+		articles []sources.Article
+		calls    int
+	}{
+		{name: "no embedder", articles: articles},
+		{name: "embedding fails", embedder: &fakeEmbedder{err: errors.New("quota")}, articles: articles, calls: 1},
+		{name: "one article", embedder: &fakeEmbedder{}, articles: articles[:1]},
+		{name: "no articles", embedder: &fakeEmbedder{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAnalyzer(nil)
+			if tt.embedder != nil {
+				a.SetEmbedder(tt.embedder, 0.5)
+			}
+			stories := a.groupStories(context.Background(), tt.articles)
+			if len(stories) != len(tt.articles) {
+				t.Fatalf("%d stories from %d articles, want one each", len(stories), len(tt.articles))
+			}
+			for _, s := range stories {
+				if len(s.also) != 0 {
+					t.Errorf("story %q merged %d articles", s.lead.Title, len(s.also))
+				}
+			}
+			if tt.embedder != nil && tt.embedder.calls != tt.calls {
+				t.Errorf("embedder called %d times, want %d", tt.embedder.calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestDedupText(t *testing.T) {
+	art := sources.Article{Title: "标题", Summary: "摘要", Content: strings.Repeat("新", dedupTextRunes)}
+	text := dedupText(art)
+	if n := len([]rune(text)); n != dedupTextRunes {
+		t.Errorf("dedupText is %d runes, want %d", n, dedupTextRunes)
+	}
+	if !strings.HasPrefix(text, "标题\n摘要\n新") {
+		t.Errorf("dedupText = %q..., want the title and summary first", text[:20])
+	}
+}
@@ -0,0 +1,457 @@
+// Package cli implements the newsbot command line. cmd/newsbot runs it on its
+// own and cmd/devkit-suite as a subcommand.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/internal/newsbot/analyzer"
+	"github.com/RobinCoderZhao/devkit-suite/internal/newsbot/i18n"
+	"github.com/RobinCoderZhao/devkit-suite/internal/newsbot/publisher"
+	"github.com/RobinCoderZhao/devkit-suite/internal/newsbot/store"
+	"github.com/RobinCoderZhao/devkit-suite/internal/suite"
+	"github.com/RobinCoderZhao/devkit-suite/internal/user"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/llm"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/notify"
+	"github.com/spf13/cobra"
+)
+
+// Command builds the newsbot command tree.
+func Command() *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:   "newsbot",
+		Short: "NewsBot - AI News Daily Digest",
+		Long: `NewsBot - AI News Daily Digest
+
+Configuration:
+  Settings are read from devkit-suite.yaml (or the file named by DEVKIT_CONFIG);
+  the environment variables below override it. Check it with 'devkit config validate'.
+
+Environment Variables:
+  LLM_PROVIDER     LLM provider: openai, azure, minimax, gemini, claude (default: openai)
+  LLM_API_KEY      API key for the LLM provider
+  LLM_MODEL        Model name (default: gpt-4o-mini)
+  LLM_BASE_URL     API base URL; the resource endpoint for azure
+  LLM_DEPLOYMENT   Azure deployment name (default: the model)
+  LLM_API_VERSION  Azure API version (default: 2024-10-21)
+  LLM_EMBEDDING_MODEL  Embedding model for merging duplicate stories; the Azure deployment for azure
+  NEWSBOT_DB       SQLite database path (default: newsbot.db)
+  NEWSBOT_SCHEDULE 'serve' schedule, cron or "@every <duration>" (default: "0 8,20 * * *")
+  NEWSBOT_SOURCES  Sources file (default: config/sources.yaml, else the built-in sources)
+  NEWSBOT_DEDUP_THRESHOLD  Similarity at which articles count as one story, 0 to 1 (default: 0.85; 0 disables)
+  SMTP_HOST        SMTP server host (default: smtp.gmail.com)
+  SMTP_PORT        SMTP port: 465 or 587 (default: 587)
+  SMTP_FROM        Sender email (default: robin254817@gmail.com)
+  SMTP_PASSWORD    SMTP app password
+  SMTP_TO          Legacy: default recipient (use 'subscribe' command instead)
+  DISCORD_WEBHOOK_URL  Discord webhook that also receives the English digest
+  MCP_TOKEN        Bearer token required by 'mcp --http'`,
+		SilenceUsage:      true,
+		PersistentPreRunE: suite.PreRun,
+	}
+
+	rootCmd.AddCommand(
+		suite.TelemetryCommand(),
+		&cobra.Command{
+			Use:   "run",
+			Short: "Fetch, analyze, and send daily digest",
+			Args:  cobra.NoArgs,
+			RunE:  func(cmd *cobra.Command, args []string) error { return runOnce(cmd.Context()) },
+		},
+		serveCmd(),
+		subscribeCmd(),
+		unsubscribeCmd(),
+		&cobra.Command{
+			Use:   "subscribers",
+			Short: "List all active subscribers",
+			Args:  cobra.NoArgs,
+			RunE:  func(cmd *cobra.Command, args []string) error { return cmdListSubscribers() },
+		},
+		sourcesCmd(),
+		mcpCmd(),
+	)
+
+	return rootCmd
+}
+
+func subscribeCmd() *cobra.Command {
+	var email, lang string
+	cmd := &cobra.Command{
+		Use:     "subscribe",
+		Short:   "Add email subscriber",
+		Example: "  newsbot subscribe --email=user@example.com --lang=zh,en",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmdSubscribe(email, lang)
+		},
+	}
+	cmd.Flags().StringVarP(&email, "email", "e", "", "Email address")
+	cmd.Flags().StringVarP(&lang, "lang", "l", "zh", "Language codes, comma-separated. Supported: zh, en, ja, ko, de, es")
+	cmd.MarkFlagRequired("email")
+	cmd.RegisterFlagCompletionFunc("lang", completeLanguages)
+	return cmd
+}
+
+func unsubscribeCmd() *cobra.Command {
+	var email string
+	cmd := &cobra.Command{
+		Use:   "unsubscribe",
+		Short: "Remove email subscriber",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmdUnsubscribe(email)
+		},
+	}
+	cmd.Flags().StringVarP(&email, "email", "e", "", "Email address")
+	cmd.MarkFlagRequired("email")
+	return cmd
+}
+
+func mcpCmd() *cobra.Command {
+	var addr string
+	cmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "Serve digests, article search and subscriptions as MCP tools for AI assistants",
+		Long: `Serve digests, article search and subscriptions as MCP tools for AI
+assistants, over stdio by default.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmdMCP(addr)
+		},
+	}
+	cmd.Flags().StringVar(&addr, "http", "", "Serve Streamable HTTP on addr instead, e.g. :8091")
+	return cmd
+}
+
+// completeLanguages completes the comma-separated --lang list.
+func completeLanguages(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	prefix := ""
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix = toComplete[:i+1]
+	}
+	var out []string
+	for _, l := range i18n.AllLanguages {
+		out = append(out, prefix+string(l)+"\t"+i18n.LanguageName(l))
+	}
+	return out, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// NewsBotConfig holds all configuration for NewsBot.
+type NewsBotConfig struct {
+	LLM     llm.Config
+	Email   notify.EmailConfig
+	Discord notify.DiscordConfig
+	DBPath  string
+}
+
+func loadConfig() NewsBotConfig {
+	return NewsBotConfig{
+		LLM: llm.Config{
+			Provider:    llm.Provider(getEnv("LLM_PROVIDER", "openai")),
+			Model:       getEnv("LLM_MODEL", "gpt-4o-mini"),
+			APIKey:      os.Getenv("LLM_API_KEY"),
+			BaseURL:     os.Getenv("LLM_BASE_URL"),
+			MaxRetries:  3,
+			Timeout:     120 * time.Second,
+			MaxTokens:   4096,
+			Temperature: 0.3,
+			Deployment:  os.Getenv("LLM_DEPLOYMENT"),
+			APIVersion:  os.Getenv("LLM_API_VERSION"),
+
+			EmbeddingModel: os.Getenv("LLM_EMBEDDING_MODEL"),
+		},
+		Email: notify.EmailConfig{
+			SMTPHost: getEnv("SMTP_HOST", "smtp.gmail.com"),
+			SMTPPort: getEnv("SMTP_PORT", "587"),
+			From:     getEnv("SMTP_FROM", "robin254817@gmail.com"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			To:       os.Getenv("SMTP_TO"),
+		},
+		Discord: notify.DiscordConfig{
+			WebhookURL: os.Getenv("DISCORD_WEBHOOK_URL"),
+		},
+		DBPath: getEnv("NEWSBOT_DB", "newsbot.db"),
+	}
+}
+
+// withUsage records the client's calls in the suite database's LLM usage
+// table. The digest runs without accounting if the database is unavailable.
+func withUsage(ctx context.Context, client llm.Client) llm.Client {
+	db, err := suite.DB()
+	if err == nil {
+		err = db.Migrate(ctx)
+	}
+	if err != nil {
+		slog.Warn("LLM usage will not be recorded", "error", err)
+		return client
+	}
+	return llm.WithUsage(client, user.NewStore(db))
+}
+
+// setEmbedder lets the analyzer merge duplicate coverage by embedding
+// similarity, unless NEWSBOT_DEDUP_THRESHOLD is 0 or the provider has no
+// embeddings API; the LLM then dedupes on its own.
+func setEmbedder(a *analyzer.Analyzer, cfg llm.Config) {
+	threshold := analyzer.DefaultDedupThreshold
+	if v := os.Getenv("NEWSBOT_DEDUP_THRESHOLD"); v != "" {
+		t, err := strconv.ParseFloat(v, 64)
+		if err != nil || t < 0 || t > 1 {
+			slog.Warn("invalid NEWSBOT_DEDUP_THRESHOLD, using default", "value", v, "default", threshold)
+		} else {
+			threshold = t
+		}
+	}
+	if threshold == 0 {
+		return
+	}
+	embedder, err := llm.NewEmbedder(cfg)
+	if err != nil {
+		slog.Info("embedding dedup disabled", "reason", err)
+		return
+	}
+	a.SetEmbedder(embedder, threshold)
+}
+
+func runOnce(ctx context.Context) error {
+	cfg := loadConfig()
+
+	slog.Info("starting NewsBot run")
+
+	// 1. Initialize data sources from sources.yaml
+	registry, err := loadSources()
+	if err != nil {
+		return err
+	}
+
+	// 2. Fetch articles
+	slog.Info("fetching articles from all sources")
+	articles, err := registry.FetchAll(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch articles: %w", err)
+	}
+	slog.Info("fetched articles", "count", len(articles))
+
+	// 3. Store articles (returns only NEW articles not previously in DB)
+	db, err := store.New(cfg.DBPath)
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer db.Close()
+
+	newArticles, err := db.SaveArticles(ctx, articles)
+	if err != nil {
+		slog.Warn("failed to save some articles", "error", err)
+	}
+	slog.Info("saved articles", "new", len(newArticles), "total", len(articles))
+
+	// Skip analysis if no new articles (avoid duplicate emails and wasted tokens)
+	if len(newArticles) == 0 {
+		slog.Info("no new articles since last run, skipping analysis")
+		return nil
+	}
+
+	// 4. Analyze ONLY new articles with LLM
+	if cfg.LLM.APIKey == "" {
+		slog.Warn("LLM API key not set, skipping analysis")
+		return nil
+	}
+
+	cfg.LLM.Cache = suite.LLMCache(ctx)
+	llmClient, err := llm.NewClient(cfg.LLM)
+	if err != nil {
+		return fmt.Errorf("create LLM client: %w", err)
+	}
+	llmClient = withUsage(ctx, llmClient)
+	defer llmClient.Close()
+
+	a := analyzer.NewAnalyzer(llmClient)
+	setEmbedder(a, cfg.LLM)
+	digest, err := a.Analyze(ctx, newArticles)
+	if err != nil {
+		return fmt.Errorf("analyze articles: %w", err)
+	}
+	slog.Info("analysis complete", "headlines", len(digest.Headlines), "tokens", digest.TokensUsed, "cost", digest.Cost)
+
+	// 5. Get subscribers and determine needed languages
+	subscribers, _ := db.GetActiveSubscribers(ctx)
+
+	// Add legacy SMTP_TO as a zh subscriber if no DB subscribers exist
+	if len(subscribers) == 0 && cfg.Email.To != "" {
+		subscribers = append(subscribers, store.Subscriber{
+			TargetType: "email",
+			TargetID:   cfg.Email.To,
+			Languages:  "zh",
+			Active:     true,
+		})
+	}
+
+	// Collect unique languages needed
+	langSet := map[i18n.Language]bool{i18n.LangEN: true} // default English
+	for _, sub := range subscribers {
+		for _, l := range sub.LanguageList() {
+			langSet[i18n.Language(l)] = true
+		}
+	}
+	var neededLangs []i18n.Language
+	for l := range langSet {
+		neededLangs = append(neededLangs, l)
+	}
+	slog.Info("languages needed", "langs", neededLangs, "subscribers", len(subscribers))
+
+	// 6. Translate to all needed languages
+	translator := i18n.NewTranslator(llmClient)
+	digests := translator.TranslateAll(ctx, digest, neededLangs)
+	slog.Info("translation complete", "languages", len(digests))
+
+	// 7. Save all language versions
+	for lang, d := range digests {
+		if err := db.SaveDigest(ctx, d, string(lang)); err != nil {
+			slog.Warn("failed to save digest", "lang", lang, "error", err)
+		}
+	}
+
+	// 8. Publish to subscribers
+	published := false
+	if len(subscribers) > 0 && cfg.Email.Password != "" {
+		dispatcher := notify.NewDispatcher()
+		dispatcher.SetEmailConfig(cfg.Email)
+		pub := publisher.NewPublisher(dispatcher)
+
+		sent := 0
+		for _, sub := range subscribers {
+			if sub.TargetType != "email" {
+				continue
+			}
+			for _, langStr := range sub.LanguageList() {
+				lang := i18n.Language(langStr)
+				d, ok := digests[lang]
+				if !ok {
+					d = digest // Fallback to Chinese
+				}
+				if err := pub.PublishToEmail(ctx, d, lang, sub.TargetID); err != nil {
+					slog.Error("email send failed", "email", sub.TargetID, "lang", lang, "error", err)
+				} else {
+					slog.Info("email sent", "email", sub.TargetID, "lang", lang)
+					sent++
+				}
+			}
+		}
+		slog.Info("digest published", "emails_sent", sent)
+		published = true
+	}
+
+	// 9. Post the English digest to Discord
+	if cfg.Discord.WebhookURL != "" {
+		d, ok := digests[i18n.LangEN]
+		if !ok {
+			d = digest
+		}
+		pub := publisher.NewPublisher(notify.NewDispatcher())
+		if err := pub.PublishToDiscord(ctx, d, i18n.LangEN, cfg.Discord); err != nil {
+			slog.Error("discord send failed", "error", err)
+		} else {
+			slog.Info("digest posted to discord")
+			published = true
+		}
+	}
+
+	if !published {
+		// Print to stdout if no subscribers/email/Discord configured
+		fmt.Println(publisher.FormatDigest(digest, i18n.LangEN))
+	}
+
+	return nil
+}
+
+// --- CLI Commands ---
+
+func cmdSubscribe(email, lang string) error {
+	// Validate languages
+	langs := i18n.ParseLanguages(lang)
+	langStrs := make([]string, len(langs))
+	for i, l := range langs {
+		langStrs[i] = string(l)
+	}
+	langCSV := strings.Join(langStrs, ",")
+
+	cfg := loadConfig()
+	db, err := store.New(cfg.DBPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// CLI subscriptions belong to no user account, as with the MCP subscribe tool
+	if err := db.AddSubscriber(context.Background(), 0, "email", email, langCSV); err != nil {
+		return fmt.Errorf("add subscriber: %w", err)
+	}
+
+	fmt.Printf("✅ Subscribed: %s (languages: %s)\n", email, langCSV)
+	for _, l := range langs {
+		fmt.Printf("   • %s — %s\n", l, i18n.LanguageName(l))
+	}
+	return nil
+}
+
+func cmdUnsubscribe(email string) error {
+	cfg := loadConfig()
+	db, err := store.New(cfg.DBPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := db.RemoveSubscriberByTarget(context.Background(), "email", email); err != nil {
+		return fmt.Errorf("remove subscriber: %w", err)
+	}
+
+	fmt.Printf("✅ Unsubscribed: %s\n", email)
+	return nil
+}
+
+func cmdListSubscribers() error {
+	cfg := loadConfig()
+	db, err := store.New(cfg.DBPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	subs, err := db.GetActiveSubscribers(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if len(subs) == 0 {
+		fmt.Println("No active subscribers.")
+		return nil
+	}
+
+	fmt.Printf("Active subscribers (%d):\n", len(subs))
+	for _, s := range subs {
+		langs := s.LanguageList()
+		var langNames []string
+		for _, l := range langs {
+			langNames = append(langNames, fmt.Sprintf("%s(%s)", l, i18n.LanguageName(i18n.Language(l))))
+		}
+		icon := "📧"
+		if s.TargetType != "email" {
+			icon = s.TargetType + ":"
+		}
+		fmt.Printf("  %s %s — %s\n", icon, s.TargetID, strings.Join(langNames, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/internal/newsbot/i18n"
+	"github.com/RobinCoderZhao/devkit-suite/internal/newsbot/store"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/mcpserver"
+)
+
+// cmdMCP serves the stored digests and articles over MCP, on stdio by
+// default or on --http=<addr>.
+func cmdMCP(addr string) error {
+	cfg := loadConfig()
+	db, err := store.New(cfg.DBPath)
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer db.Close()
+
+	server := mcpserver.New("newsbot", "1.0.0")
+	server.Use(mcpserver.RecoveryMiddleware())
+	server.Use(mcpserver.LoggingMiddleware(slog.Default()))
+	registerNewsTools(server, db)
+
+	if addr == "" {
+		return server.RunStdio()
+	}
+	if token := os.Getenv("MCP_TOKEN"); token != "" {
+		server.SetHTTPAuthToken(token)
+	}
+	server.Use(mcpserver.RateLimitMiddleware(5, 20))
+	slog.Info("NewsBot MCP serving", "addr", addr)
+	return server.RunHTTP(addr)
+}
+
+type digestArgs struct {
+	Lang string `json:"lang,omitempty" description:"Digest language" jsonschema:"enum=zh|en|ja|ko|de|es,default=en"`
+}
+
+type searchArticlesArgs struct {
+	Query     string `json:"query" description:"Text to find in article titles and content"`
+	SinceDays int    `json:"since_days,omitempty" description:"Only articles fetched in the last N days" jsonschema:"minimum=1"`
+	Limit     int    `json:"limit,omitempty" description:"Maximum articles to return" jsonschema:"default=20,minimum=1,maximum=100"`
+}
+
+type subscribeArgs struct {
+	Email string   `json:"email" description:"Address that receives the daily digest"`
+	Langs []string `json:"langs,omitempty" description:"Digest languages: zh, en, ja, ko, de or es (default en)"`
+}
+
+// mcpArticle is a stored article as search_articles returns it; content is
+// cut to keep responses small.
+type mcpArticle struct {
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Source      string    `json:"source"`
+	PublishedAt time.Time `json:"published_at,omitzero"`
+	Excerpt     string    `json:"excerpt,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+}
+
+const articleExcerptRunes = 300
+
+// registerNewsTools registers the NewsBot tools on server.
+func registerNewsTools(server *mcpserver.Server, db *store.Store) {
+	mcpserver.RegisterFunc(server, "get_latest_digest", "Get the most recent curated AI news digest: headlines with summaries, sources and importance",
+		func(ctx context.Context, args digestArgs) (*mcpserver.ToolCallResult, error) {
+			lang := args.Lang
+			if lang == "" {
+				lang = string(i18n.LangEN)
+			}
+			if !i18n.IsValidLanguage(lang) {
+				return nil, fmt.Errorf("unsupported language %q", lang)
+			}
+			digest, err := db.GetLatestDigest(ctx, lang)
+			if err != nil {
+				return nil, fmt.Errorf("get digest: %w", err)
+			}
+			if digest == nil {
+				return nil, fmt.Errorf("no %s digest yet", i18n.LanguageName(i18n.Language(lang)))
+			}
+			return mcpserver.SuccessResult(digest), nil
+		})
+
+	mcpserver.RegisterFunc(server, "search_articles", "Search the articles NewsBot has collected from its news sources, newest first",
+		func(ctx context.Context, args searchArticlesArgs) (*mcpserver.ToolCallResult, error) {
+			query := strings.TrimSpace(args.Query)
+			if query == "" {
+				return nil, errors.New("query is empty")
+			}
+			var since time.Time
+			if args.SinceDays > 0 {
+				since = time.Now().AddDate(0, 0, -args.SinceDays)
+			}
+			limit := args.Limit
+			if limit <= 0 {
+				limit = 20
+			}
+			articles, err := db.SearchArticles(ctx, query, since, min(limit, 100))
+			if err != nil {
+				return nil, fmt.Errorf("search articles: %w", err)
+			}
+			result := make([]mcpArticle, 0, len(articles))
+			for _, a := range articles {
+				excerpt := a.Summary
+				if excerpt == "" {
+					excerpt = a.Content
+				}
+				if r := []rune(excerpt); len(r) > articleExcerptRunes {
+					excerpt = string(r[:articleExcerptRunes]) + "…"
+				}
+				result = append(result, mcpArticle{
+					Title:       a.Title,
+					URL:         a.URL,
+					Source:      a.Source,
+					PublishedAt: a.PublishedAt,
+					Excerpt:     excerpt,
+					Tags:        a.Tags,
+				})
+			}
+			return mcpserver.SuccessResult(result), nil
+		})
+
+	mcpserver.RegisterFunc(server, "subscribe", "Subscribe an email address to the daily AI news digest",
+		func(ctx context.Context, args subscribeArgs) (*mcpserver.ToolCallResult, error) {
+			addr, err := mail.ParseAddress(args.Email)
+			if err != nil {
+				return nil, fmt.Errorf("invalid email %q: %w", args.Email, err)
+			}
+			langs := []string{}
+			for _, l := range args.Langs {
+				l = strings.ToLower(strings.TrimSpace(l))
+				if !i18n.IsValidLanguage(l) {
+					return nil, fmt.Errorf("unsupported language %q", l)
+				}
+				langs = append(langs, l)
+			}
+			if len(langs) == 0 {
+				langs = append(langs, string(i18n.LangEN))
+			}
+			langCSV := strings.Join(langs, ",")
+			if err := db.AddSubscriber(ctx, 0, "email", addr.Address, langCSV); err != nil {
+				return nil, fmt.Errorf("add subscriber: %w", err)
+			}
+			return mcpserver.SuccessResult(map[string]string{
+				"email":     addr.Address,
+				"languages": langCSV,
+			}), nil
+		})
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RobinCoderZhao/devkit-suite/internal/suite"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/jobs"
+)
+
+// jobRun is the job kind of a scheduled digest run.
+const jobRun = "newsbot.run"
+
+func serveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Run the digest on a schedule",
+		Long: `Run the digest on a schedule until SIGINT or SIGTERM.
+
+Runs are jobs on the persistent queue in the suite database, so a failed
+run is retried with backoff and a run missed while stopped happens on the
+next start.
+
+  NEWSBOT_SCHEDULE  cron expression or "@every <duration>" (default: "0 8,20 * * *")
+  METRICS_ADDR      serve Prometheus metrics at /metrics on this address, e.g. ":9090"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+			// restore default signal handling so a second signal exits now
+			context.AfterFunc(ctx, stop)
+			return Serve(ctx)
+		},
+	}
+}
+
+// Serve runs the digest on NEWSBOT_SCHEDULE until ctx is done, then waits
+// for a run under way to finish.
+func Serve(ctx context.Context) error {
+	db, err := suite.DB()
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	if err := db.Migrate(ctx); err != nil {
+		return fmt.Errorf("migrate database: %w", err)
+	}
+
+	queue := jobs.New(db)
+	queue.Handle(jobRun, jobs.HandlerOptions{Timeout: time.Hour, MaxAttempts: 3}, func(ctx context.Context, job jobs.Job) error {
+		return runOnce(ctx)
+	})
+	spec := getEnv("NEWSBOT_SCHEDULE", "0 8,20 * * *")
+	if err := queue.Schedule(ctx, jobs.Recurring{Name: jobRun, Kind: jobRun, Spec: spec}); err != nil {
+		return fmt.Errorf("NEWSBOT_SCHEDULE: %w", err)
+	}
+	slog.Info("NewsBot serving", "schedule", spec)
+	suite.ServeMetrics(ctx)
+
+	queue.Run(ctx)
+	return nil
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RobinCoderZhao/devkit-suite/internal/newsbot/sources"
+)
+
+// defaultSourcesPath is read when NEWSBOT_SOURCES is unset; without it the
+// built-in source list is used.
+const defaultSourcesPath = "config/sources.yaml"
+
+func sourcesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sources",
+		Short: "Show or create the sources.yaml the digest is fetched from",
+		Long: `Show or create the sources.yaml the digest is fetched from.
+
+Sources are read from NEWSBOT_SOURCES (default: config/sources.yaml). When
+that default file does not exist the built-in list is used; 'sources init'
+writes it out to edit. Types: rss, hackernews, reddit and arxiv, each with
+optional include/exclude, min_score and max_age filters.`,
+	}
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "list",
+			Short: "List the configured sources",
+			Args:  cobra.NoArgs,
+			RunE:  func(cmd *cobra.Command, args []string) error { return cmdListSources() },
+		},
+		&cobra.Command{
+			Use:     "init [path]",
+			Short:   "Write the built-in sources to a file to edit",
+			Example: "  newsbot sources init\n  newsbot sources init /etc/newsbot/sources.yaml",
+			Args:    cobra.MaximumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				path := getEnv("NEWSBOT_SOURCES", defaultSourcesPath)
+				if len(args) == 1 {
+					path = args[0]
+				}
+				return cmdInitSources(path)
+			},
+		},
+	)
+	return cmd
+}
+
+// loadSourcesConfig reads NEWSBOT_SOURCES, falling back to the built-in list
+// when it is unset and config/sources.yaml does not exist. It returns where
+// the sources came from.
+func loadSourcesConfig() (*sources.Config, string, error) {
+	path := os.Getenv("NEWSBOT_SOURCES")
+	explicit := path != ""
+	if !explicit {
+		path = defaultSourcesPath
+	}
+	cfg, err := sources.LoadConfig(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return sources.DefaultConfig(), "built-in", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("load sources: %w", err)
+	}
+	return cfg, path, nil
+}
+
+// loadSources builds the registry of enabled sources.
+func loadSources() (*sources.Registry, error) {
+	cfg, from, err := loadSourcesConfig()
+	if err != nil {
+		return nil, err
+	}
+	registry, err := sources.NewRegistryFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if registry.Len() == 0 {
+		return nil, fmt.Errorf("no enabled sources in %s", from)
+	}
+	return registry, nil
+}
+
+func cmdListSources() error {
+	cfg, from, err := loadSourcesConfig()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Sources (%d, from %s):\n", len(cfg.Sources), from)
+	for _, s := range cfg.Sources {
+		mark := "✅"
+		if s.Disabled {
+			mark = "⏸️"
+		}
+		target := s.URL
+		switch s.Type {
+		case "reddit":
+			target = "r/" + s.Subreddit
+		case "arxiv":
+			target = s.Query
+		}
+		fmt.Printf("  %s %-24s %-10s %s\n", mark, s.Name, s.Type, target)
+	}
+	return nil
+}
+
+func cmdInitSources(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(path, sources.DefaultConfigYAML(), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Wrote %s — edit it to add or remove sources\n", path)
+	return nil
+}
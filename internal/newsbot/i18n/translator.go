@@ -67,7 +67,7 @@ Rules:
 
 %s`, LanguageName(targetLang), string(targetLang), string(payloadJSON))
 
-	resp, err := t.client.Generate(ctx, &llm.Request{
+	resp, err := t.client.Generate(llm.ForUser(ctx, 0, "newsbot.translate"), &llm.Request{
 		System:      "You are a professional tech news translator. Output valid JSON only.",
 		Messages:    []llm.Message{{Role: "user", Content: prompt}},
 		MaxTokens:   8192,
@@ -26,15 +26,11 @@ func (p *Publisher) PublishToEmail(ctx context.Context, digest *analyzer.DailyDi
 	formatter := notify.NewNewsEmailFormatter()
 	data := toNewsDigestData(digest, lang)
 	msg := formatter.Format(data)
+	msg.Bulk = true
 
-	notifier := notify.NewEmailNotifier(notify.EmailConfig{
-		SMTPHost: p.dispatcher.EmailConfig().SMTPHost,
-		SMTPPort: p.dispatcher.EmailConfig().SMTPPort,
-		From:     p.dispatcher.EmailConfig().From,
-		Password: p.dispatcher.EmailConfig().Password,
-		To:       email,
-	})
-	return notifier.Send(ctx, msg)
+	cfg := p.dispatcher.EmailConfig()
+	cfg.To = email
+	return notify.NewEmailNotifier(cfg).Send(ctx, msg)
 }
 
 // PublishToTelegram sends a digest in the specified language via Telegram.
@@ -45,6 +41,14 @@ func (p *Publisher) PublishToTelegram(ctx context.Context, digest *analyzer.Dail
 	return p.dispatcher.Dispatch(ctx, channels, msg)
 }
 
+// PublishToDiscord posts a digest in the specified language to a Discord webhook.
+func (p *Publisher) PublishToDiscord(ctx context.Context, digest *analyzer.DailyDigest, lang i18n.Language, cfg notify.DiscordConfig) error {
+	formatter := notify.NewNewsDiscordFormatter()
+	data := toNewsDigestData(digest, lang)
+	msg := formatter.Format(data)
+	return notify.NewDiscordNotifier(cfg).Send(ctx, msg)
+}
+
 // Publish sends a digest via all configured channels (backward compat).
 func (p *Publisher) Publish(ctx context.Context, digest *analyzer.DailyDigest, lang i18n.Language, channels []notify.Channel) error {
 	formatter := notify.NewNewsEmailFormatter()
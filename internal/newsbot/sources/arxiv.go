@@ -0,0 +1,115 @@
+package sources
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ArxivSource fetches the newest papers matching an arXiv API query.
+type ArxivSource struct {
+	name     string
+	query    string
+	maxItems int
+	client   *http.Client
+	baseURL  string
+}
+
+// NewArxivSource creates a source for an arXiv search query such as
+// "cat:cs.AI OR cat:cs.CL", newest submissions first.
+func NewArxivSource(name, query string, maxItems int) (*ArxivSource, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("query is required, e.g. \"cat:cs.AI\"")
+	}
+	if maxItems <= 0 {
+		maxItems = 30
+	}
+	return &ArxivSource{
+		name:     name,
+		query:    query,
+		maxItems: maxItems,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		baseURL:  "https://export.arxiv.org/api/query",
+	}, nil
+}
+
+func (a *ArxivSource) Name() string { return a.name }
+
+type arxivFeed struct {
+	Entries []arxivEntry `xml:"entry"`
+}
+
+type arxivEntry struct {
+	ID        string `xml:"id"`
+	Title     string `xml:"title"`
+	Summary   string `xml:"summary"`
+	Published string `xml:"published"`
+	Authors   []struct {
+		Name string `xml:"name"`
+	} `xml:"author"`
+	Categories []struct {
+		Term string `xml:"term,attr"`
+	} `xml:"category"`
+}
+
+func (a *ArxivSource) Fetch(ctx context.Context) ([]Article, error) {
+	params := url.Values{
+		"search_query": {a.query},
+		"sortBy":       {"submittedDate"},
+		"sortOrder":    {"descending"},
+		"max_results":  {fmt.Sprint(a.maxItems)},
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", a.baseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "DevkitSuite-NewsBot/1.0")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch arXiv %s: %w", a.name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch arXiv %s: HTTP %d", a.name, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read arXiv feed: %w", err)
+	}
+	var feed arxivFeed
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		return nil, fmt.Errorf("parse arXiv feed: %w", err)
+	}
+
+	articles := make([]Article, 0, len(feed.Entries))
+	for _, e := range feed.Entries {
+		published, _ := time.Parse(time.RFC3339, e.Published)
+		authors := make([]string, 0, len(e.Authors))
+		for _, au := range e.Authors {
+			authors = append(authors, au.Name)
+		}
+		tags := []string{"arxiv"}
+		for _, c := range e.Categories {
+			tags = append(tags, c.Term)
+		}
+		articles = append(articles, Article{
+			// arXiv wraps titles and abstracts across lines
+			Title:       strings.Join(strings.Fields(e.Title), " "),
+			URL:         strings.Replace(e.ID, "http://", "https://", 1),
+			Source:      a.name,
+			Author:      strings.Join(authors, ", "),
+			Content:     strings.Join(strings.Fields(e.Summary), " "),
+			PublishedAt: published,
+			FetchedAt:   time.Now(),
+			Tags:        tags,
+		})
+	}
+	return articles, nil
+}
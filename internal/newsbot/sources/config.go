@@ -0,0 +1,253 @@
+package sources
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfig is the built-in source list, used when no sources.yaml is
+// configured. "newsbot sources init" writes it out as a starting point.
+//
+//go:embed sources.yaml
+var defaultConfig []byte
+
+// Config is a sources.yaml file: the sources the digest is built from.
+type Config struct {
+	Sources []SourceConfig `yaml:"sources"`
+}
+
+// SourceConfig configures one source. Which fields apply depends on Type.
+type SourceConfig struct {
+	Name      string `yaml:"name"`
+	Type      string `yaml:"type"`                // rss, hackernews, reddit or arxiv
+	URL       string `yaml:"url,omitempty"`       // rss: the RSS or Atom feed
+	Subreddit string `yaml:"subreddit,omitempty"` // reddit: e.g. LocalLLaMA
+	Sort      string `yaml:"sort,omitempty"`      // reddit: hot (default), new, top or rising
+	Query     string `yaml:"query,omitempty"`     // arxiv: search query, e.g. "cat:cs.AI OR cat:cs.CL"
+	MaxItems  int    `yaml:"max_items,omitempty"` // hackernews, reddit, arxiv (default 30)
+	Disabled  bool   `yaml:"disabled,omitempty"`
+	Filter    Filter `yaml:"filter,omitempty"`
+}
+
+// Filter drops a source's articles before they are stored and analyzed.
+type Filter struct {
+	// Include keeps only articles whose title or content contains one of
+	// the words, case-insensitively.
+	Include []string `yaml:"include,omitempty"`
+	// Exclude drops articles whose title or content contains one of the
+	// words, case-insensitively.
+	Exclude []string `yaml:"exclude,omitempty"`
+	// MinScore drops articles scored below it, for sources with scores
+	// (hackernews, reddit).
+	MinScore int `yaml:"min_score,omitempty"`
+	// MaxAge drops articles published longer ago, e.g. 48h.
+	MaxAge time.Duration `yaml:"max_age,omitempty"`
+}
+
+// Factory builds a source of one type from its configuration.
+type Factory func(cfg SourceConfig) (Source, error)
+
+// factories holds the source types a config can use.
+var factories = map[string]Factory{
+	"rss": func(cfg SourceConfig) (Source, error) {
+		if err := checkURL(cfg.URL); err != nil {
+			return nil, fmt.Errorf("url: %w", err)
+		}
+		return NewRSSSource(cfg.Name, cfg.URL), nil
+	},
+	"hackernews": func(cfg SourceConfig) (Source, error) {
+		return NewHackerNewsSource(cfg.MaxItems), nil
+	},
+	"reddit": func(cfg SourceConfig) (Source, error) {
+		return NewRedditSource(cfg.Name, cfg.Subreddit, cfg.Sort, cfg.MaxItems)
+	},
+	"arxiv": func(cfg SourceConfig) (Source, error) {
+		return NewArxivSource(cfg.Name, cfg.Query, cfg.MaxItems)
+	},
+}
+
+// RegisterType adds a source type that configs can use, replacing any
+// type of the same name. Call it from an init function.
+func RegisterType(name string, f Factory) {
+	factories[name] = f
+}
+
+// Types returns the names of the registered source types, sorted.
+func Types() []string {
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultConfig returns the built-in source list.
+func DefaultConfig() *Config {
+	cfg, err := ParseConfig(defaultConfig)
+	if err != nil {
+		panic("sources: invalid built-in sources.yaml: " + err.Error())
+	}
+	return cfg
+}
+
+// DefaultConfigYAML returns the built-in sources.yaml.
+func DefaultConfigYAML() []byte {
+	return bytes.Clone(defaultConfig)
+}
+
+// LoadConfig reads and validates a sources.yaml file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// ParseConfig parses and validates a sources.yaml document. Unknown keys
+// are errors, so typos do not silently drop a filter.
+func ParseConfig(data []byte) (*Config, error) {
+	var cfg Config
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("parse sources: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks every source and returns all problems found, joined.
+func (c *Config) Validate() error {
+	var errs []error
+	seen := make(map[string]bool)
+	for i, s := range c.Sources {
+		field := fmt.Sprintf("sources[%d]", i)
+		if s.Name == "" {
+			errs = append(errs, fmt.Errorf("%s: name is required", field))
+		} else {
+			field = fmt.Sprintf("sources[%d] (%s)", i, s.Name)
+			if seen[s.Name] {
+				errs = append(errs, fmt.Errorf("%s: duplicate name", field))
+			}
+			seen[s.Name] = true
+		}
+		if s.MaxItems < 0 || s.Filter.MinScore < 0 || s.Filter.MaxAge < 0 {
+			errs = append(errs, fmt.Errorf("%s: max_items, min_score and max_age must not be negative", field))
+		}
+		factory, ok := factories[s.Type]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: unknown type %q (one of %s)", field, s.Type, strings.Join(Types(), ", ")))
+			continue
+		}
+		if _, err := factory(s); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", field, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NewRegistryFromConfig creates a registry with the config's enabled
+// sources, each wrapped in its filter.
+func NewRegistryFromConfig(cfg *Config) (*Registry, error) {
+	r := NewRegistry()
+	for _, s := range cfg.Sources {
+		if s.Disabled {
+			continue
+		}
+		factory, ok := factories[s.Type]
+		if !ok {
+			return nil, fmt.Errorf("source %s: unknown type %q", s.Name, s.Type)
+		}
+		src, err := factory(s)
+		if err != nil {
+			return nil, fmt.Errorf("source %s: %w", s.Name, err)
+		}
+		if !s.Filter.empty() {
+			src = &filteredSource{Source: src, filter: s.Filter}
+		}
+		r.Register(src)
+	}
+	return r, nil
+}
+
+func (f Filter) empty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0 && f.MinScore == 0 && f.MaxAge == 0
+}
+
+// Keep reports whether the filter lets an article through at time now.
+func (f Filter) Keep(a Article, now time.Time) bool {
+	if f.MinScore > 0 && a.Score < f.MinScore {
+		return false
+	}
+	if f.MaxAge > 0 && !a.PublishedAt.IsZero() && now.Sub(a.PublishedAt) > f.MaxAge {
+		return false
+	}
+	if len(f.Include) == 0 && len(f.Exclude) == 0 {
+		return true
+	}
+	text := strings.ToLower(a.Title + "\n" + a.Summary + "\n" + a.Content)
+	for _, word := range f.Exclude {
+		if strings.Contains(text, strings.ToLower(word)) {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, word := range f.Include {
+		if strings.Contains(text, strings.ToLower(word)) {
+			return true
+		}
+	}
+	return false
+}
+
+// filteredSource applies a Filter to another source's articles.
+type filteredSource struct {
+	Source
+	filter Filter
+}
+
+func (f *filteredSource) Fetch(ctx context.Context) ([]Article, error) {
+	articles, err := f.Source.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	kept := articles[:0]
+	for _, a := range articles {
+		if f.filter.Keep(a, now) {
+			kept = append(kept, a)
+		}
+	}
+	return kept, nil
+}
+
+func checkURL(raw string) error {
+	if raw == "" {
+		return errors.New("required")
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an http(s) URL, got %q", raw)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package sources
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		// wantErrs are substrings the error must contain; none means valid
+		wantErrs []string
+	}{
+		{name: "valid", yaml: `
+sources:
+  - name: OpenAI Blog
+    type: rss
+    url: https://openai.com/blog/rss.xml
+  - name: HN
+    type: hackernews
+    max_items: 20
+    filter:
+      min_score: 50
+      max_age: 48h
+  - name: LocalLLaMA
+    type: reddit
+    subreddit: LocalLLaMA
+    sort: top
+    filter:
+      include: [llama, qwen]
+      exclude: [meme]
+  - name: arXiv AI
+    type: arxiv
+    query: "cat:cs.AI"
+    disabled: true
+`},
+		{name: "empty", yaml: `sources: []`},
+		{name: "missing name", yaml: `
+sources:
+  - type: hackernews
+`, wantErrs: []string{"sources[0]: name is required"}},
+		{name: "missing rss url", yaml: `
+sources:
+  - name: Blog
+    type: rss
+`, wantErrs: []string{"sources[0] (Blog): url: required"}},
+		{name: "rss url not http", yaml: `
+sources:
+  - name: Blog
+    type: rss
+    url: ftp://example.com/feed
+`, wantErrs: []string{"must be an http(s) URL"}},
+		{name: "missing subreddit", yaml: `
+sources:
+  - name: Reddit
+    type: reddit
+`, wantErrs: []string{`sources[0] (Reddit): invalid subreddit ""`}},
+		{name: "missing arxiv query", yaml: `
+sources:
+  - name: arXiv
+    type: arxiv
+`, wantErrs: []string{"sources[0] (arXiv): query is required"}},
+		{name: "unknown type", yaml: `
+sources:
+  - name: Mastodon
+    type: mastodon
+`, wantErrs: []string{`sources[0] (Mastodon): unknown type "mastodon" (one of arxiv, hackernews, reddit, rss)`}},
+		{name: "missing type", yaml: `
+sources:
+  - name: Blog
+    url: https://example.com/feed
+`, wantErrs: []string{`unknown type ""`}},
+		{name: "unknown key", yaml: `
+sources:
+  - name: HN
+    type: hackernews
+    filter:
+      min_scor: 50
+`, wantErrs: []string{"field min_scor not found"}},
+		{name: "every problem is reported", yaml: `
+sources:
+  - name: HN
+    type: hackernews
+    max_items: -1
+  - name: HN
+    type: hackernews
+  - name: Feed
+    type: atom
+`, wantErrs: []string{"must not be negative", "sources[1] (HN): duplicate name", `sources[2] (Feed): unknown type "atom"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ParseConfig([]byte(tt.yaml))
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("ParseConfig: %v", err)
+				}
+				if cfg == nil {
+					t.Fatal("ParseConfig returned no config")
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("ParseConfig succeeded, want errors %q", tt.wantErrs)
+			}
+			for _, want := range tt.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewRegistryFromConfig(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`
+sources:
+  - name: HN
+    type: hackernews
+    filter:
+      min_score: 50
+      max_age: 48h
+  - name: Blog
+    type: rss
+    url: https://example.com/feed
+  - name: arXiv AI
+    type: arxiv
+    query: "cat:cs.AI"
+    disabled: true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := cfg.Sources[0].Filter; f.MinScore != 50 || f.MaxAge != 48*time.Hour {
+		t.Errorf("HN filter = %+v", f)
+	}
+	r, err := NewRegistryFromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Len() != 2 {
+		t.Errorf("registry has %d sources, want the 2 enabled ones", r.Len())
+	}
+
+	// A config built in code skips Validate, so the registry checks types too
+	if _, err := NewRegistryFromConfig(&Config{Sources: []SourceConfig{{Name: "x", Type: "gopher"}}}); err == nil {
+		t.Error("registry accepted an unknown type")
+	}
+}
+
+func TestDefaultConfig(t *testing.T) {
+	if cfg := DefaultConfig(); len(cfg.Sources) == 0 {
+		t.Error("built-in sources.yaml has no sources")
+	}
+	path := filepath.Join(t.TempDir(), "sources.yaml")
+	if err := os.WriteFile(path, DefaultConfigYAML(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); err != nil {
+		t.Errorf("LoadConfig(built-in): %v", err)
+	}
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("LoadConfig of a missing file succeeded")
+	}
+}
+
+func TestFilterKeep(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	art := Article{Title: "Qwen 3 released", Content: "Open weights", Score: 80, PublishedAt: now.Add(-time.Hour)}
+	tests := []struct {
+		name   string
+		filter Filter
+		keep   bool
+	}{
+		{"no filter", Filter{}, true},
+		{"score high enough", Filter{MinScore: 80}, true},
+		{"score too low", Filter{MinScore: 81}, false},
+		{"recent enough", Filter{MaxAge: time.Hour}, true},
+		{"too old", Filter{MaxAge: time.Minute}, false},
+		{"included, case-insensitively", Filter{Include: []string{"llama", "QWEN"}}, true},
+		{"not included", Filter{Include: []string{"llama"}}, false},
+		{"excluded from the content", Filter{Exclude: []string{"open weights"}}, false},
+		{"exclude wins over include", Filter{Include: []string{"qwen"}, Exclude: []string{"released"}}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Keep(art, now); got != tt.keep {
+			t.Errorf("%s: Keep = %v, want %v", tt.name, got, tt.keep)
+		}
+	}
+}
@@ -134,6 +134,7 @@ func (h *HackerNewsSource) fetchStory(ctx context.Context, id int) (Article, err
 		Content:     story.Text,
 		PublishedAt: time.Unix(story.Time, 0),
 		FetchedAt:   time.Now(),
+		Score:       story.Score,
 		Tags:        []string{"hackernews"},
 	}, nil
 }
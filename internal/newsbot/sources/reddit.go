@@ -0,0 +1,129 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+// subredditName matches a subreddit name as Reddit allows it.
+var subredditName = regexp.MustCompile(`^[A-Za-z0-9_]{2,21}$`)
+
+// RedditSource fetches posts from a subreddit's JSON listing, which carries
+// the score the RSS feed leaves out.
+type RedditSource struct {
+	name      string
+	subreddit string
+	sort      string
+	maxItems  int
+	client    *http.Client
+	baseURL   string
+}
+
+// NewRedditSource creates a source for a subreddit listing. sort is hot
+// (the default), new, top or rising.
+func NewRedditSource(name, subreddit, sort string, maxItems int) (*RedditSource, error) {
+	if !subredditName.MatchString(subreddit) {
+		return nil, fmt.Errorf("invalid subreddit %q", subreddit)
+	}
+	switch sort {
+	case "":
+		sort = "hot"
+	case "hot", "new", "top", "rising":
+	default:
+		return nil, fmt.Errorf("unknown sort %q (hot, new, top or rising)", sort)
+	}
+	if maxItems <= 0 {
+		maxItems = 30
+	}
+	return &RedditSource{
+		name:      name,
+		subreddit: subreddit,
+		sort:      sort,
+		maxItems:  min(maxItems, 100),
+		client:    &http.Client{Timeout: 15 * time.Second},
+		baseURL:   "https://www.reddit.com",
+	}, nil
+}
+
+func (r *RedditSource) Name() string { return r.name }
+
+type redditListing struct {
+	Data struct {
+		Children []struct {
+			Data redditPost `json:"data"`
+		} `json:"children"`
+	} `json:"data"`
+}
+
+type redditPost struct {
+	Title      string  `json:"title"`
+	URL        string  `json:"url"`
+	Permalink  string  `json:"permalink"`
+	Author     string  `json:"author"`
+	Selftext   string  `json:"selftext"`
+	CreatedUTC float64 `json:"created_utc"`
+	Score      int     `json:"score"`
+	Stickied   bool    `json:"stickied"`
+}
+
+func (r *RedditSource) Fetch(ctx context.Context) ([]Article, error) {
+	endpoint := fmt.Sprintf("%s/r/%s/%s.json?limit=%d&raw_json=1", r.baseURL, r.subreddit, r.sort, r.maxItems)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	// Reddit throttles requests without a descriptive User-Agent
+	req.Header.Set("User-Agent", "DevkitSuite-NewsBot/1.0")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch r/%s: %w", r.subreddit, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch r/%s: HTTP %d", r.subreddit, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read r/%s: %w", r.subreddit, err)
+	}
+	var listing redditListing
+	if err := json.Unmarshal(body, &listing); err != nil {
+		return nil, fmt.Errorf("parse r/%s: %w", r.subreddit, err)
+	}
+
+	articles := make([]Article, 0, len(listing.Data.Children))
+	for _, child := range listing.Data.Children {
+		post := child.Data
+		if post.Stickied {
+			continue // moderator announcements, not news
+		}
+		link := post.URL
+		if u, err := url.Parse(link); err != nil || u.Host == "" {
+			link = r.baseURL + post.Permalink
+		}
+		var published time.Time
+		if post.CreatedUTC > 0 {
+			published = time.Unix(int64(post.CreatedUTC), 0)
+		}
+		articles = append(articles, Article{
+			Title:       post.Title,
+			URL:         link,
+			Source:      r.name,
+			Author:      post.Author,
+			Content:     post.Selftext,
+			PublishedAt: published,
+			FetchedAt:   time.Now(),
+			Score:       post.Score,
+			Tags:        []string{"reddit", "r/" + r.subreddit},
+		})
+	}
+	return articles, nil
+}
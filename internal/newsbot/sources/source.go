@@ -17,6 +17,7 @@ type Article struct {
 	Content     string    `json:"content"`
 	PublishedAt time.Time `json:"published_at"`
 	FetchedAt   time.Time `json:"fetched_at"`
+	Score       int       `json:"score,omitempty"` // upvotes, for sources that have them
 	Tags        []string  `json:"tags,omitempty"`
 }
 
@@ -44,6 +45,11 @@ func (r *Registry) Register(s Source) {
 	r.sources = append(r.sources, s)
 }
 
+// Len returns the number of registered sources.
+func (r *Registry) Len() int {
+	return len(r.sources)
+}
+
 // FetchAll fetches articles from all registered sources concurrently.
 func (r *Registry) FetchAll(ctx context.Context) ([]Article, error) {
 	type result struct {
@@ -182,6 +182,37 @@ func (s *Store) GetArticleCount(ctx context.Context) (int, error) {
 	return count, err
 }
 
+// SearchArticles returns up to limit stored articles whose title or content
+// contains query (case-insensitive), newest first. A zero since matches all.
+func (s *Store) SearchArticles(ctx context.Context, query string, since time.Time, limit int) ([]sources.Article, error) {
+	pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(query) + "%"
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT title, url, source, COALESCE(author, ''), COALESCE(content, ''), published_at, fetched_at, COALESCE(tags, '')
+		FROM articles
+		WHERE (title LIKE ? ESCAPE '\' OR content LIKE ? ESCAPE '\') AND fetched_at >= ?
+		ORDER BY COALESCE(published_at, fetched_at) DESC
+		LIMIT ?
+	`, pattern, pattern, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var articles []sources.Article
+	for rows.Next() {
+		var a sources.Article
+		var publishedAt sql.NullTime
+		var tags string
+		if err := rows.Scan(&a.Title, &a.URL, &a.Source, &a.Author, &a.Content, &publishedAt, &a.FetchedAt, &tags); err != nil {
+			return nil, err
+		}
+		a.PublishedAt = publishedAt.Time
+		json.Unmarshal([]byte(tags), &a.Tags)
+		articles = append(articles, a)
+	}
+	return articles, rows.Err()
+}
+
 // --- Subscriber Management ---
 
 // AddSubscriber adds or updates a subscriber.
@@ -0,0 +1,57 @@
+package suite
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/llm"
+)
+
+var (
+	llmCacheOnce sync.Once
+	llmCache     llm.Cache
+)
+
+// LLMCache returns the LLM response cache selected by LLM_CACHE: "memory"
+// for one per process, "db" for a table in the shared database, or nil
+// (the default, "off") for none. Responses are kept for LLM_CACHE_TTL,
+// default 24h. Only the first call reads the settings.
+func LLMCache(ctx context.Context) llm.Cache {
+	llmCacheOnce.Do(func() {
+		var ttl time.Duration
+		if s := os.Getenv("LLM_CACHE_TTL"); s != "" {
+			var err error
+			if ttl, err = time.ParseDuration(s); err != nil || ttl <= 0 {
+				slog.Warn("invalid LLM_CACHE_TTL, using 24h", "value", s)
+				ttl = 0
+			}
+		}
+		switch mode := os.Getenv("LLM_CACHE"); mode {
+		case "", "off":
+		case "memory":
+			llmCache = llm.NewMemoryCache(0, ttl)
+		case "db":
+			db, err := DB()
+			if err == nil {
+				err = db.Migrate(ctx)
+			}
+			if err != nil {
+				slog.Warn("LLM cache disabled", "error", err)
+				return
+			}
+			cache := llm.NewSQLCache(db, ttl)
+			if n, err := cache.Prune(ctx); err != nil {
+				slog.Warn("LLM cache prune failed", "error", err)
+			} else if n > 0 {
+				slog.Info("pruned expired LLM cache entries", "count", n)
+			}
+			llmCache = cache
+		default:
+			slog.Warn("unknown LLM_CACHE, caching disabled", "value", mode)
+		}
+	})
+	return llmCache
+}
@@ -0,0 +1,30 @@
+package suite
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/metrics"
+)
+
+var metricsOnce sync.Once
+
+// ServeMetrics serves /metrics on METRICS_ADDR (e.g. ":9090") until ctx is
+// done, for the pipelines that have no HTTP server of their own. It does
+// nothing when METRICS_ADDR is unset, and only the first call in a process
+// listens, so watchbot and newsbot can share one devkit-suite process.
+func ServeMetrics(ctx context.Context) {
+	addr := os.Getenv("METRICS_ADDR")
+	if addr == "" {
+		return
+	}
+	metricsOnce.Do(func() {
+		go func() {
+			if err := metrics.ListenAndServe(ctx, addr); err != nil {
+				slog.Error("metrics server failed", "addr", addr, "error", err)
+			}
+		}()
+	})
+}
@@ -0,0 +1,76 @@
+package suite
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// MigrateCommand builds the migrate command, which applies, reverts or
+// lists the schema migrations of the shared database. Servers apply
+// pending migrations on startup; the command is for deployments that
+// migrate ahead of a release or need to roll one back.
+func MigrateCommand() *cobra.Command {
+	var steps int
+	cmd := &cobra.Command{
+		Use:   "migrate [up|down|status]",
+		Short: "数据库迁移 (默认 up; 服务启动时也会自动 up)",
+		Long: `应用、回滚或查看共用数据库 (WATCHBOT_DB_DRIVER / WATCHBOT_DB) 的版本化迁移。
+迁移文件嵌入二进制, 已应用的版本记录在 schema_migrations 表中。
+
+  up      应用全部未执行的迁移 (默认)
+  down    回滚最近 --steps 个迁移
+  status  只列出各迁移状态`,
+		Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: []string{"up", "down", "status"},
+		// a failed migration is not a usage error
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			action := "up"
+			if len(args) == 1 {
+				action = args[0]
+			}
+			return runMigrate(cmd, action, steps)
+		},
+	}
+	cmd.Flags().IntVar(&steps, "steps", 1, "down 回滚的迁移数")
+	return cmd
+}
+
+// runMigrate applies pending migrations, reverts the latest ones, or only
+// lists them, then prints where each migration stands.
+func runMigrate(cmd *cobra.Command, action string, steps int) error {
+	ctx := cmd.Context()
+	db, err := DB()
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+
+	switch action {
+	case "up":
+		if err := db.Migrate(ctx); err != nil {
+			return fmt.Errorf("❌ 迁移失败: %w", err)
+		}
+	case "down":
+		if steps < 1 {
+			return fmt.Errorf("--steps 必须是正整数")
+		}
+		if err := db.MigrateDown(ctx, steps); err != nil {
+			return fmt.Errorf("❌ 回滚失败: %w", err)
+		}
+	}
+
+	status, err := db.MigrationStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("read migration status: %w", err)
+	}
+	fmt.Printf("🗄️  数据库迁移 (%s):\n\n", db.DriverType())
+	for _, m := range status {
+		state := "⏳ 未应用"
+		if m.Applied {
+			state = "✅ " + m.AppliedAt.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Printf("  %04d  %-30s %s\n", m.Version, m.Name, state)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Package suite holds the process-wide state shared by the watchbot, newsbot,
+// devkit and API commands, so that running several of them in one
+// devkit-suite process loads the config once and uses one database handle.
+package suite
+
+import (
+	"os"
+	"sync"
+
+	appconfig "github.com/RobinCoderZhao/devkit-suite/pkg/config"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
+	_ "github.com/lib/pq" // WATCHBOT_DB_DRIVER=postgres
+)
+
+var (
+	configOnce sync.Once
+	configErr  error
+
+	dbMu sync.Mutex
+	db   *storage.DB
+)
+
+// LoadConfig loads the suite config into the environment. Only the first
+// call reads the file; later calls return its result.
+func LoadConfig() error {
+	configOnce.Do(func() {
+		_, configErr = appconfig.LoadSuiteEnv()
+	})
+	return configErr
+}
+
+// DB returns the shared watchbot database, opening it from
+// WATCHBOT_DB_DRIVER and WATCHBOT_DB on first use. Callers must not close
+// it; Close does.
+func DB() (*storage.DB, error) {
+	dbMu.Lock()
+	defer dbMu.Unlock()
+	if db != nil {
+		return db, nil
+	}
+	driver := storage.Driver(getEnv("WATCHBOT_DB_DRIVER", string(storage.SQLite)))
+	dsn := getEnv("WATCHBOT_DB", "data/watchbot.db")
+	opened, err := storage.Open(storage.Config{Driver: driver, DSN: dsn}.WithPoolEnv())
+	if err != nil {
+		return nil, err
+	}
+	db = opened
+	return db, nil
+}
+
+// Close closes the shared database if it was opened.
+func Close() error {
+	dbMu.Lock()
+	defer dbMu.Unlock()
+	if db == nil {
+		return nil
+	}
+	err := db.Close()
+	db = nil
+	return err
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,138 @@
+package suite
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/telemetry"
+)
+
+var (
+	telemetryOnce   sync.Once
+	telemetryClient *telemetry.Client
+)
+
+// PreRun is the PersistentPreRunE of every command tree: it loads the suite
+// config and reports the command to telemetry, if the user opted in.
+func PreRun(cmd *cobra.Command, args []string) error {
+	if err := LoadConfig(); err != nil {
+		return err
+	}
+	client(cmd).RecordCommand(commandName(cmd))
+	return nil
+}
+
+// Finish is called by main after the command tree ran: it reports a failed
+// command's error class, waits briefly for telemetry to go out and closes
+// the shared database.
+func Finish(cmd *cobra.Command, err error) {
+	if cmd != nil {
+		c := client(cmd)
+		c.RecordError(commandName(cmd), err)
+		c.Flush(2 * time.Second)
+	}
+	Close()
+}
+
+func client(cmd *cobra.Command) *telemetry.Client {
+	telemetryOnce.Do(func() {
+		version := cmd.Root().Version
+		if version == "" {
+			version = "dev"
+		}
+		telemetryClient = telemetry.New(version)
+	})
+	return telemetryClient
+}
+
+// commandName is the command path without the binary, so "watchbot check"
+// and "devkit-suite watchbot check" report the same command.
+func commandName(cmd *cobra.Command) string {
+	path := cmd.CommandPath()
+	if root := cmd.Root().Name(); root == "devkit-suite" {
+		path = strings.TrimPrefix(strings.TrimPrefix(path, root), " ")
+	}
+	return path
+}
+
+// TelemetryCommand builds the telemetry status/enable/disable commands.
+func TelemetryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "telemetry",
+		Short: "匿名使用统计 (默认关闭)",
+		Long: `匿名使用统计, 需主动开启。只上报运行的命令、LLM 提供商类型、错误类别、版本与操作系统,
+不上报参数、URL、邮箱、提示词等任何内容。
+
+  DEVKIT_TELEMETRY           1/0, 覆盖 enable/disable 的选择
+  DEVKIT_TELEMETRY_ENDPOINT  上报地址, 未设置时不发送
+  DEVKIT_TELEMETRY_FILE      开关状态文件 (默认用户配置目录下 devkit-suite/telemetry.json)
+  DO_NOT_TRACK               设置后始终关闭`,
+		// status must work with a broken config; telemetry commands are
+		// not themselves reported
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			_ = LoadConfig()
+			return nil
+		},
+		SilenceUsage: true,
+	}
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "status",
+			Short: "查看是否开启及上报内容",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return printTelemetryStatus()
+			},
+		},
+		&cobra.Command{
+			Use:   "enable",
+			Short: "开启匿名使用统计",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				if _, err := telemetry.SetEnabled(true); err != nil {
+					return fmt.Errorf("开启失败: %w", err)
+				}
+				fmt.Println("✅ 已开启匿名使用统计, 感谢支持")
+				return printTelemetryStatus()
+			},
+		},
+		&cobra.Command{
+			Use:   "disable",
+			Short: "关闭匿名使用统计并删除匿名 ID",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				if _, err := telemetry.SetEnabled(false); err != nil {
+					return fmt.Errorf("关闭失败: %w", err)
+				}
+				fmt.Println("✅ 已关闭匿名使用统计")
+				return printTelemetryStatus()
+			},
+		},
+	)
+	return cmd
+}
+
+func printTelemetryStatus() error {
+	s, err := telemetry.CurrentStatus()
+	state := "关闭"
+	if s.Enabled {
+		state = "开启"
+	}
+	fmt.Printf("状态:     %s (%s)\n", state, s.Reason)
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = "(未设置 " + telemetry.EnvEndpoint + ", 不发送)"
+	}
+	fmt.Printf("上报地址: %s\n", endpoint)
+	if s.InstallID != "" {
+		fmt.Printf("匿名 ID:  %s\n", s.InstallID)
+	}
+	if path, perr := telemetry.StatePath(); perr == nil {
+		fmt.Printf("状态文件: %s\n", path)
+	}
+	return err
+}
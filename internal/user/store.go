@@ -4,15 +4,20 @@ package user
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"net/mail"
 	"strings"
+	"time"
 
+	"github.com/RobinCoderZhao/devkit-suite/pkg/i18n"
 	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
 )
 
 // Store provides persistence for Users and Organizations.
 type Store struct {
-	db *storage.DB
+	db      *storage.DB
+	secrets *storage.SecretBox // nil disables SetSecret and GetSecret
 }
 
 // NewStore creates a new user store.
@@ -20,6 +25,11 @@ func NewStore(db *storage.DB) *Store {
 	return &Store{db: db}
 }
 
+// SetSecretBox enables storing user secrets, encrypted with box.
+func (s *Store) SetSecretBox(box *storage.SecretBox) {
+	s.secrets = box
+}
+
 // User represents a tenant in the system.
 type User struct {
 	ID                   int
@@ -28,6 +38,56 @@ type User struct {
 	Plan                 string
 	StripeCustomerID     string
 	StripeSubscriptionID string
+	LLMBudget            float64 // monthly LLM spending cap in USD; 0 is none
+	Profile
+}
+
+// Profile holds the account settings a user edits themselves.
+type Profile struct {
+	Name              string `json:"name"`
+	Phone             string `json:"phone"` // E.164 number for critical SMS alerts
+	Company           string `json:"company"`
+	Timezone          string `json:"timezone"`           // IANA time zone; empty uses the server's
+	Language          string `json:"language"`           // notification language; empty uses the default
+	NotificationEmail string `json:"notification_email"` // empty sends to the login email
+}
+
+// ErrInvalidProfile is returned for profile values that fail Validate.
+var ErrInvalidProfile = errors.New("invalid profile")
+
+// Validate checks the time zone, language and notification email.
+func (p Profile) Validate() error {
+	if p.Timezone != "" {
+		if _, err := time.LoadLocation(p.Timezone); err != nil {
+			return fmt.Errorf("%w: unknown time zone %s", ErrInvalidProfile, p.Timezone)
+		}
+	}
+	if p.Language != "" && !i18n.IsValidLanguage(p.Language) {
+		return fmt.Errorf("%w: unsupported language %s", ErrInvalidProfile, p.Language)
+	}
+	if p.NotificationEmail != "" {
+		if addr, err := mail.ParseAddress(p.NotificationEmail); err != nil || addr.Address != p.NotificationEmail {
+			return fmt.Errorf("%w: invalid notification email %s", ErrInvalidProfile, p.NotificationEmail)
+		}
+	}
+	return nil
+}
+
+// userColumns are the columns scanned by scanUser.
+const userColumns = `id, email, password_hash, plan, COALESCE(stripe_customer_id, ''), COALESCE(stripe_subscription_id, ''), llm_budget,
+	COALESCE(name, ''), COALESCE(phone, ''), COALESCE(company, ''), timezone, language, notification_email`
+
+func scanUser(row *sql.Row) (*User, error) {
+	u := &User{}
+	err := row.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Plan, &u.StripeCustomerID, &u.StripeSubscriptionID, &u.LLMBudget,
+		&u.Name, &u.Phone, &u.Company, &u.Timezone, &u.Language, &u.NotificationEmail)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not found
+		}
+		return nil, err
+	}
+	return u, nil
 }
 
 // CreateUser inserts a new user.
@@ -36,43 +96,24 @@ func (s *Store) CreateUser(ctx context.Context, email, passwordHash, plan string
 	if plan == "" {
 		plan = "free"
 	}
-	res, err := s.db.ExecContext(ctx,
+	id, err := s.db.InsertID(ctx,
 		`INSERT INTO users (email, password_hash, plan, stripe_customer_id, stripe_subscription_id) VALUES (?, ?, ?, '', '')`,
 		email, passwordHash, plan)
 	if err != nil {
 		return 0, fmt.Errorf("create user: %w", err)
 	}
-	id, _ := res.LastInsertId()
 	return int(id), nil
 }
 
 // GetUserByEmail finds a user by their email address.
 func (s *Store) GetUserByEmail(ctx context.Context, email string) (*User, error) {
 	email = strings.TrimSpace(strings.ToLower(email))
-	row := s.db.QueryRowContext(ctx,
-		`SELECT id, email, password_hash, plan, COALESCE(stripe_customer_id, ''), COALESCE(stripe_subscription_id, '') FROM users WHERE email = ?`, email)
-	u := &User{}
-	if err := row.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Plan, &u.StripeCustomerID, &u.StripeSubscriptionID); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil // Not found
-		}
-		return nil, err
-	}
-	return u, nil
+	return scanUser(s.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE email = ?`, email))
 }
 
 // GetUserByID finds a user by their integer ID.
 func (s *Store) GetUserByID(ctx context.Context, id int) (*User, error) {
-	row := s.db.QueryRowContext(ctx,
-		`SELECT id, email, password_hash, plan, COALESCE(stripe_customer_id, ''), COALESCE(stripe_subscription_id, '') FROM users WHERE id = ?`, id)
-	u := &User{}
-	if err := row.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Plan, &u.StripeCustomerID, &u.StripeSubscriptionID); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil // Not found
-		}
-		return nil, err
-	}
-	return u, nil
+	return scanUser(s.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE id = ?`, id))
 }
 
 // UpdateStripeIDs updates the Stripe customer and subscription IDs, and the Plan.
@@ -82,3 +123,62 @@ func (s *Store) UpdateStripeIDs(ctx context.Context, id int, customerID, subID,
 		customerID, subID, plan, id)
 	return err
 }
+
+// UpdateProfile replaces a user's profile fields.
+func (s *Store) UpdateProfile(ctx context.Context, id int, p Profile) error {
+	p.NotificationEmail = strings.TrimSpace(strings.ToLower(p.NotificationEmail))
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE users SET name = ?, phone = ?, company = ?, timezone = ?, language = ?, notification_email = ? WHERE id = ?`,
+		strings.TrimSpace(p.Name), strings.TrimSpace(p.Phone), strings.TrimSpace(p.Company),
+		p.Timezone, p.Language, p.NotificationEmail, id)
+	return err
+}
+
+// --- Secrets ---
+
+// SetSecret stores a user-provided credential (an SMTP password, an API
+// token, ...) under name, encrypted, replacing any previous value.
+func (s *Store) SetSecret(ctx context.Context, userID int, name, value string) error {
+	if s.secrets == nil {
+		return storage.ErrNoSecretBox
+	}
+	sealed, err := s.secrets.Seal(ctx, value)
+	if err != nil {
+		return fmt.Errorf("seal secret %s: %w", name, err)
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO user_secrets (user_id, name, ciphertext) VALUES (?, ?, ?)
+		 ON CONFLICT(user_id, name) DO UPDATE SET ciphertext = excluded.ciphertext, updated_at = CURRENT_TIMESTAMP`,
+		userID, name, sealed)
+	return err
+}
+
+// GetSecret returns a user's decrypted secret, or "" when unset.
+func (s *Store) GetSecret(ctx context.Context, userID int, name string) (string, error) {
+	if s.secrets == nil {
+		return "", storage.ErrNoSecretBox
+	}
+	var sealed string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT ciphertext FROM user_secrets WHERE user_id = ? AND name = ?`, userID, name).Scan(&sealed)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	value, err := s.secrets.Open(ctx, sealed)
+	if err != nil {
+		return "", fmt.Errorf("secret %s: %w", name, err)
+	}
+	return value, nil
+}
+
+// DeleteSecret removes a user's secret.
+func (s *Store) DeleteSecret(ctx context.Context, userID int, name string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM user_secrets WHERE user_id = ? AND name = ?`, userID, name)
+	return err
+}
@@ -0,0 +1,310 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
+)
+
+// Team roles. Admins manage members and invites.
+const (
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+// InviteTTL is how long a team invite can be accepted.
+const InviteTTL = 7 * 24 * time.Hour
+
+var (
+	// ErrInviteInvalid is returned for invites that were accepted, revoked
+	// or have expired.
+	ErrInviteInvalid = errors.New("invite is no longer valid")
+	// ErrAlreadyMember is returned when inviting someone already in the team.
+	ErrAlreadyMember = errors.New("already a member of this team")
+	// ErrLastAdmin is returned when removing a team's only admin.
+	ErrLastAdmin = errors.New("a team needs at least one admin")
+)
+
+// Team is a shared workspace.
+type Team struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role,omitempty"` // the requesting user's role, when listed for a user
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TeamMember is a user in a team.
+type TeamMember struct {
+	UserID   int       `json:"user_id"`
+	Email    string    `json:"email"`
+	Name     string    `json:"name"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// Invite is an invitation to join a team.
+type Invite struct {
+	ID        int       `json:"id"`
+	TeamID    int       `json:"team_id"`
+	TeamName  string    `json:"team_name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	InvitedBy string    `json:"invited_by"` // inviter's email
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CreateTeam creates a team with the creator as its admin.
+func (s *Store) CreateTeam(ctx context.Context, name string, creatorID int) (int, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return 0, errors.New("team name is required")
+	}
+	var teamID int64
+	err := s.db.Transaction(ctx, func(tx *storage.Tx) error {
+		var err error
+		teamID, err = tx.InsertID(ctx, `INSERT INTO teams (name, created_by) VALUES (?, ?)`, name, creatorID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO team_members (team_id, user_id, role) VALUES (?, ?, ?)`, teamID, creatorID, RoleAdmin)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("create team: %w", err)
+	}
+	return int(teamID), nil
+}
+
+// ListTeamsForUser returns the teams a user belongs to, with their role.
+func (s *Store) ListTeamsForUser(ctx context.Context, userID int) ([]Team, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT t.id, t.name, m.role, t.created_at
+		 FROM teams t JOIN team_members m ON m.team_id = t.id
+		 WHERE m.user_id = ? ORDER BY t.name`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var teams []Team
+	for rows.Next() {
+		var t Team
+		if err := rows.Scan(&t.ID, &t.Name, &t.Role, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		teams = append(teams, t)
+	}
+	return teams, rows.Err()
+}
+
+// GetMemberRole returns a user's role in a team, or "" if they are not a
+// member.
+func (s *Store) GetMemberRole(ctx context.Context, teamID, userID int) (string, error) {
+	var role string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT role FROM team_members WHERE team_id = ? AND user_id = ?`, teamID, userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return role, err
+}
+
+// ListMembers returns a team's members, admins first.
+func (s *Store) ListMembers(ctx context.Context, teamID int) ([]TeamMember, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT u.id, u.email, COALESCE(u.name, ''), m.role, m.joined_at
+		 FROM team_members m JOIN users u ON u.id = m.user_id
+		 WHERE m.team_id = ?
+		 ORDER BY CASE WHEN m.role = 'admin' THEN 0 ELSE 1 END, m.joined_at`, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var members []TeamMember
+	for rows.Next() {
+		var m TeamMember
+		if err := rows.Scan(&m.UserID, &m.Email, &m.Name, &m.Role, &m.JoinedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+// RemoveMember removes a user from a team. The last admin cannot be removed.
+func (s *Store) RemoveMember(ctx context.Context, teamID, userID int) error {
+	return s.db.Transaction(ctx, func(tx *storage.Tx) error {
+		var role string
+		err := tx.QueryRowContext(ctx,
+			`SELECT role FROM team_members WHERE team_id = ? AND user_id = ?`, teamID, userID).Scan(&role)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if role == RoleAdmin {
+			var admins int
+			if err := tx.QueryRowContext(ctx,
+				`SELECT COUNT(*) FROM team_members WHERE team_id = ? AND role = ?`, teamID, RoleAdmin).Scan(&admins); err != nil {
+				return err
+			}
+			if admins <= 1 {
+				return ErrLastAdmin
+			}
+		}
+		_, err = tx.ExecContext(ctx, `DELETE FROM team_members WHERE team_id = ? AND user_id = ?`, teamID, userID)
+		return err
+	})
+}
+
+// CreateInvite invites an email address to a team, replacing any pending
+// invite for the same address.
+func (s *Store) CreateInvite(ctx context.Context, teamID int, email, role string, invitedBy int) (*Invite, error) {
+	email = strings.TrimSpace(strings.ToLower(email))
+	if role == "" {
+		role = RoleMember
+	}
+	if role != RoleAdmin && role != RoleMember {
+		return nil, fmt.Errorf("unknown team role: %s", role)
+	}
+
+	var member int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM team_members m JOIN users u ON u.id = m.user_id
+		 WHERE m.team_id = ? AND u.email = ?`, teamID, email).Scan(&member)
+	if err != nil {
+		return nil, err
+	}
+	if member > 0 {
+		return nil, ErrAlreadyMember
+	}
+
+	now := time.Now().UTC()
+	var id int64
+	err = s.db.Transaction(ctx, func(tx *storage.Tx) error {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE team_invites SET revoked_at = ?
+			 WHERE team_id = ? AND email = ? AND accepted_at IS NULL AND revoked_at IS NULL`,
+			now.Format(time.DateTime), teamID, email); err != nil {
+			return err
+		}
+		var err error
+		id, err = tx.InsertID(ctx,
+			`INSERT INTO team_invites (team_id, email, role, invited_by, expires_at) VALUES (?, ?, ?, ?, ?)`,
+			teamID, email, role, invitedBy, now.Add(InviteTTL).Format(time.DateTime))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create invite: %w", err)
+	}
+	return s.GetInvite(ctx, int(id))
+}
+
+// inviteColumns are the columns scanned by scanInvite.
+const inviteColumns = `i.id, i.team_id, t.name, i.email, i.role, COALESCE(u.email, ''), i.created_at, i.expires_at`
+
+// inviteFrom joins an invite with its team and inviter.
+const inviteFrom = `FROM team_invites i
+	JOIN teams t ON t.id = i.team_id
+	LEFT JOIN users u ON u.id = i.invited_by`
+
+// pendingInvite restricts a query to invites that can still be accepted.
+const pendingInvite = `i.accepted_at IS NULL AND i.revoked_at IS NULL AND i.expires_at > ?`
+
+func scanInvite(sc interface{ Scan(...any) error }) (*Invite, error) {
+	var inv Invite
+	if err := sc.Scan(&inv.ID, &inv.TeamID, &inv.TeamName, &inv.Email, &inv.Role, &inv.InvitedBy, &inv.CreatedAt, &inv.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// GetInvite returns a pending invite, or ErrInviteInvalid if it was
+// accepted, revoked or has expired.
+func (s *Store) GetInvite(ctx context.Context, id int) (*Invite, error) {
+	inv, err := scanInvite(s.db.QueryRowContext(ctx,
+		`SELECT `+inviteColumns+` `+inviteFrom+` WHERE i.id = ? AND `+pendingInvite,
+		id, time.Now().UTC().Format(time.DateTime)))
+	if err == sql.ErrNoRows {
+		return nil, ErrInviteInvalid
+	}
+	return inv, err
+}
+
+// ListPendingInvites returns a team's pending invites, newest first.
+func (s *Store) ListPendingInvites(ctx context.Context, teamID int) ([]Invite, error) {
+	return s.listInvites(ctx, `i.team_id = ?`, teamID)
+}
+
+// ListInvitesForEmail returns the pending invites sent to an address.
+func (s *Store) ListInvitesForEmail(ctx context.Context, email string) ([]Invite, error) {
+	return s.listInvites(ctx, `i.email = ?`, strings.TrimSpace(strings.ToLower(email)))
+}
+
+func (s *Store) listInvites(ctx context.Context, where string, arg any) ([]Invite, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+inviteColumns+` `+inviteFrom+` WHERE `+where+` AND `+pendingInvite+` ORDER BY i.created_at DESC`,
+		arg, time.Now().UTC().Format(time.DateTime))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var invites []Invite
+	for rows.Next() {
+		inv, err := scanInvite(rows)
+		if err != nil {
+			return nil, err
+		}
+		invites = append(invites, *inv)
+	}
+	return invites, rows.Err()
+}
+
+// RevokeInvite cancels a team's pending invite.
+func (s *Store) RevokeInvite(ctx context.Context, teamID, inviteID int) error {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE team_invites SET revoked_at = ?
+		 WHERE id = ? AND team_id = ? AND accepted_at IS NULL AND revoked_at IS NULL`,
+		time.Now().UTC().Format(time.DateTime), inviteID, teamID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrInviteInvalid
+	}
+	return nil
+}
+
+// AcceptInvite adds the user to the invite's team with the invited role and
+// marks the invite accepted.
+func (s *Store) AcceptInvite(ctx context.Context, inviteID, userID int) error {
+	now := time.Now().UTC().Format(time.DateTime)
+	return s.db.Transaction(ctx, func(tx *storage.Tx) error {
+		var teamID int
+		var role string
+		err := tx.QueryRowContext(ctx,
+			`SELECT i.team_id, i.role FROM team_invites i WHERE i.id = ? AND `+pendingInvite,
+			inviteID, now).Scan(&teamID, &role)
+		if err == sql.ErrNoRows {
+			return ErrInviteInvalid
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO team_members (team_id, user_id, role) VALUES (?, ?, ?)
+			 ON CONFLICT(team_id, user_id) DO NOTHING`, teamID, userID, role); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx,
+			`UPDATE team_invites SET accepted_at = ?, accepted_by = ? WHERE id = ?`, now, userID, inviteID)
+		return err
+	})
+}
@@ -0,0 +1,186 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
+)
+
+// newTestStore returns a store on a migrated SQLite database that is removed
+// with the test.
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	db, err := storage.Open(storage.Config{Driver: storage.SQLite, DSN: filepath.Join(t.TempDir(), "users.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return NewStore(db)
+}
+
+func createUser(t *testing.T, s *Store, email string) int {
+	t.Helper()
+	id, err := s.CreateUser(context.Background(), email, "hash", "free")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestAcceptInvite(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	admin := createUser(t, s, "admin@example.com")
+	bob := createUser(t, s, "bob@example.com")
+	teamID, err := s.CreateTeam(ctx, "Acme", admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inv, err := s.CreateInvite(ctx, teamID, " Bob@Example.com ", "", admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inv.Email != "bob@example.com" || inv.Role != RoleMember || inv.TeamName != "Acme" || inv.InvitedBy != "admin@example.com" {
+		t.Fatalf("invite = %+v", inv)
+	}
+	if got := inv.ExpiresAt.Sub(inv.CreatedAt); got != InviteTTL {
+		t.Errorf("invite valid for %s, want %s", got, InviteTTL)
+	}
+	if pending, _ := s.ListInvitesForEmail(ctx, "BOB@example.com"); len(pending) != 1 || pending[0].ID != inv.ID {
+		t.Errorf("pending invites for bob = %+v", pending)
+	}
+
+	// A new invite to the same address replaces the pending one
+	again, err := s.CreateInvite(ctx, teamID, "bob@example.com", RoleAdmin, admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AcceptInvite(ctx, inv.ID, bob); !errors.Is(err, ErrInviteInvalid) {
+		t.Errorf("accepting a replaced invite: %v, want ErrInviteInvalid", err)
+	}
+
+	if err := s.AcceptInvite(ctx, again.ID, bob); err != nil {
+		t.Fatal(err)
+	}
+	if role, _ := s.GetMemberRole(ctx, teamID, bob); role != RoleAdmin {
+		t.Errorf("bob's role = %q, want %q", role, RoleAdmin)
+	}
+	if teams, _ := s.ListTeamsForUser(ctx, bob); len(teams) != 1 || teams[0].ID != teamID || teams[0].Role != RoleAdmin {
+		t.Errorf("bob's teams = %+v", teams)
+	}
+
+	// An accepted invite is spent, and members cannot be invited again
+	if err := s.AcceptInvite(ctx, again.ID, bob); !errors.Is(err, ErrInviteInvalid) {
+		t.Errorf("accepting twice: %v, want ErrInviteInvalid", err)
+	}
+	if _, err := s.GetInvite(ctx, again.ID); !errors.Is(err, ErrInviteInvalid) {
+		t.Errorf("GetInvite after accepting: %v, want ErrInviteInvalid", err)
+	}
+	if _, err := s.CreateInvite(ctx, teamID, "bob@example.com", "", admin); !errors.Is(err, ErrAlreadyMember) {
+		t.Errorf("inviting a member: %v, want ErrAlreadyMember", err)
+	}
+	if _, err := s.CreateInvite(ctx, teamID, "carol@example.com", "owner", admin); err == nil {
+		t.Error("expected an unknown role to be refused")
+	}
+}
+
+func TestInviteExpiryAndRevoke(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	admin := createUser(t, s, "admin@example.com")
+	carol := createUser(t, s, "carol@example.com")
+	teamID, err := s.CreateTeam(ctx, "Acme", admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := s.CreateInvite(ctx, teamID, "carol@example.com", "", admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.db.ExecContext(ctx, `UPDATE team_invites SET expires_at = ? WHERE id = ?`,
+		time.Now().UTC().Add(-time.Minute).Format(time.DateTime), expired.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AcceptInvite(ctx, expired.ID, carol); !errors.Is(err, ErrInviteInvalid) {
+		t.Errorf("accepting an expired invite: %v, want ErrInviteInvalid", err)
+	}
+	if pending, _ := s.ListPendingInvites(ctx, teamID); len(pending) != 0 {
+		t.Errorf("expired invite still pending: %+v", pending)
+	}
+	if role, _ := s.GetMemberRole(ctx, teamID, carol); role != "" {
+		t.Errorf("carol joined through an expired invite as %q", role)
+	}
+
+	revoked, err := s.CreateInvite(ctx, teamID, "carol@example.com", "", admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RevokeInvite(ctx, teamID+1, revoked.ID); !errors.Is(err, ErrInviteInvalid) {
+		t.Errorf("revoking through another team: %v, want ErrInviteInvalid", err)
+	}
+	if err := s.RevokeInvite(ctx, teamID, revoked.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AcceptInvite(ctx, revoked.ID, carol); !errors.Is(err, ErrInviteInvalid) {
+		t.Errorf("accepting a revoked invite: %v, want ErrInviteInvalid", err)
+	}
+}
+
+func TestRemoveLastAdmin(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	admin := createUser(t, s, "admin@example.com")
+	dave := createUser(t, s, "dave@example.com")
+	teamID, err := s.CreateTeam(ctx, "Acme", admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inv, err := s.CreateInvite(ctx, teamID, "dave@example.com", "", admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AcceptInvite(ctx, inv.ID, dave); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.RemoveMember(ctx, teamID, admin); !errors.Is(err, ErrLastAdmin) {
+		t.Fatalf("removing the only admin: %v, want ErrLastAdmin", err)
+	}
+	if members, _ := s.ListMembers(ctx, teamID); len(members) != 2 || members[0].UserID != admin || members[0].Role != RoleAdmin {
+		t.Fatalf("members = %+v, want the admin first", members)
+	}
+
+	// With a second admin the first can leave
+	promoted, err := s.CreateInvite(ctx, teamID, "erin@example.com", RoleAdmin, admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	erin := createUser(t, s, "erin@example.com")
+	if err := s.AcceptInvite(ctx, promoted.ID, erin); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RemoveMember(ctx, teamID, admin); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RemoveMember(ctx, teamID, dave); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RemoveMember(ctx, teamID, dave); err != nil {
+		t.Errorf("removing a non-member: %v", err)
+	}
+	if members, _ := s.ListMembers(ctx, teamID); len(members) != 1 || members[0].UserID != erin {
+		t.Errorf("members = %+v, want erin alone", members)
+	}
+	if err := s.RemoveMember(ctx, teamID, erin); !errors.Is(err, ErrLastAdmin) {
+		t.Errorf("removing the new only admin: %v, want ErrLastAdmin", err)
+	}
+}
@@ -0,0 +1,222 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/llm"
+)
+
+// PlanTokenQuota is how many LLM tokens (in + out) a plan may use per
+// calendar month (UTC). Plans not listed get the free allowance; a quota of
+// 0 is unlimited.
+var PlanTokenQuota = map[string]int{
+	"free": 200_000,
+	"pro":  10_000_000,
+}
+
+// TokenQuota returns the monthly token allowance of a plan.
+func TokenQuota(plan string) int {
+	if q, ok := PlanTokenQuota[plan]; ok {
+		return q
+	}
+	return PlanTokenQuota["free"]
+}
+
+// UsageDay aggregates one UTC day of a user's LLM calls.
+type UsageDay struct {
+	Day       string  `json:"day"` // YYYY-MM-DD
+	Calls     int     `json:"calls"`
+	TokensIn  int     `json:"tokens_in"`
+	TokensOut int     `json:"tokens_out"`
+	Cost      float64 `json:"cost"`
+}
+
+// FeatureUsage totals one feature's LLM calls, e.g. "watchbot.analysis".
+type FeatureUsage struct {
+	Feature   string  `json:"feature"`
+	Calls     int     `json:"calls"`
+	TokensIn  int     `json:"tokens_in"`
+	TokensOut int     `json:"tokens_out"`
+	Cost      float64 `json:"cost"`
+}
+
+// MonthUsage is a user's LLM spend in the current UTC month by feature,
+// against the plan's token quota and the user's budget.
+type MonthUsage struct {
+	Month         string         `json:"month"` // YYYY-MM
+	Plan          string         `json:"plan"`
+	Features      []FeatureUsage `json:"features"` // most expensive first
+	Tokens        int            `json:"tokens"`
+	Cost          float64        `json:"cost"`
+	MonthlyQuota  int            `json:"monthly_quota"`  // tokens; 0 is unlimited
+	MonthlyBudget float64        `json:"monthly_budget"` // USD; 0 is none
+	// LLMDisabled is set once the quota or budget is used up. LLM calls for
+	// the user are refused until next month, and page changes are reported
+	// as plain diffs instead of analyses.
+	LLMDisabled bool `json:"llm_disabled"`
+}
+
+// UsageReport is a user's LLM usage: daily aggregates and where the current
+// month stands against the plan's quota.
+type UsageReport struct {
+	Plan         string     `json:"plan"`
+	Days         []UsageDay `json:"days"` // oldest first, days without calls omitted
+	MonthTokens  int        `json:"month_tokens"`
+	MonthCost    float64    `json:"month_cost"`
+	MonthlyQuota int        `json:"monthly_quota"` // tokens; 0 is unlimited
+}
+
+// RecordUsage stores one LLM call. It implements llm.UsageLedger.
+func (s *Store) RecordUsage(ctx context.Context, u llm.Usage) error {
+	if u.Time.IsZero() {
+		u.Time = time.Now()
+	}
+	var userID any
+	if u.UserID != 0 {
+		userID = u.UserID
+	}
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO llm_usage (user_id, feature, provider, model, tokens_in, tokens_out, cost, day, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		userID, u.Feature, string(u.Provider), u.Model, u.TokensIn, u.TokensOut, u.Cost,
+		u.Time.UTC().Format(time.DateOnly), u.Time.UTC().Format(time.DateTime))
+	if err != nil {
+		return fmt.Errorf("record LLM usage: %w", err)
+	}
+	return nil
+}
+
+// CheckQuota returns an error wrapping llm.ErrQuotaExceeded once the user
+// has used their plan's tokens or spent their budget for the month. It
+// implements llm.UsageLedger.
+func (s *Store) CheckQuota(ctx context.Context, userID int) error {
+	u, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("check LLM quota: %w", err)
+	}
+	if u == nil {
+		return nil // not a user account, nothing to enforce
+	}
+	quota := TokenQuota(u.Plan)
+	if quota == 0 && u.LLMBudget <= 0 {
+		return nil
+	}
+	used, cost, err := s.monthUsage(ctx, userID, time.Now())
+	if err != nil {
+		return fmt.Errorf("check LLM quota: %w", err)
+	}
+	if quota > 0 && used >= quota {
+		return fmt.Errorf("%w: %d of %d tokens used this month on the %s plan", llm.ErrQuotaExceeded, used, quota, u.Plan)
+	}
+	if u.LLMBudget > 0 && cost >= u.LLMBudget {
+		return fmt.Errorf("%w: $%.2f of the $%.2f monthly budget spent", llm.ErrQuotaExceeded, cost, u.LLMBudget)
+	}
+	return nil
+}
+
+// SetLLMBudget sets the user's monthly LLM spending cap in USD; 0 removes it.
+func (s *Store) SetLLMBudget(ctx context.Context, userID int, budget float64) error {
+	if budget < 0 {
+		return fmt.Errorf("LLM budget must not be negative")
+	}
+	_, err := s.db.ExecContext(ctx, `UPDATE users SET llm_budget = ? WHERE id = ?`, budget, userID)
+	return err
+}
+
+// MonthUsage reports the user's LLM spend by feature for the current UTC
+// month. It returns nil for an unknown user.
+func (s *Store) MonthUsage(ctx context.Context, userID int) (*MonthUsage, error) {
+	u, err := s.GetUserByID(ctx, userID)
+	if err != nil || u == nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	report := &MonthUsage{
+		Month:         start.Format("2006-01"),
+		Plan:          u.Plan,
+		Features:      []FeatureUsage{},
+		MonthlyQuota:  TokenQuota(u.Plan),
+		MonthlyBudget: u.LLMBudget,
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT feature, COUNT(*), SUM(tokens_in), SUM(tokens_out), SUM(cost)
+		 FROM llm_usage WHERE user_id = ? AND day >= ?
+		 GROUP BY feature ORDER BY SUM(cost) DESC, feature`, userID, start.Format(time.DateOnly))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var f FeatureUsage
+		if err := rows.Scan(&f.Feature, &f.Calls, &f.TokensIn, &f.TokensOut, &f.Cost); err != nil {
+			return nil, err
+		}
+		report.Features = append(report.Features, f)
+		report.Tokens += f.TokensIn + f.TokensOut
+		report.Cost += f.Cost
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	report.LLMDisabled = (report.MonthlyQuota > 0 && report.Tokens >= report.MonthlyQuota) ||
+		(report.MonthlyBudget > 0 && report.Cost >= report.MonthlyBudget)
+	return report, nil
+}
+
+// monthUsage sums the user's tokens and cost for the UTC month of now.
+func (s *Store) monthUsage(ctx context.Context, userID int, now time.Time) (tokens int, cost float64, err error) {
+	now = now.UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).Format(time.DateOnly)
+	var t sql.NullInt64
+	var c sql.NullFloat64
+	err = s.db.QueryRowContext(ctx,
+		`SELECT SUM(tokens_in + tokens_out), SUM(cost) FROM llm_usage WHERE user_id = ? AND day >= ?`,
+		userID, start).Scan(&t, &c)
+	return int(t.Int64), c.Float64, err
+}
+
+// LLMUsage reports the user's usage over the last days days, today included.
+func (s *Store) LLMUsage(ctx context.Context, userID, days int) (*UsageReport, error) {
+	u, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	report := &UsageReport{Days: []UsageDay{}}
+	if u != nil {
+		report.Plan = u.Plan
+		report.MonthlyQuota = TokenQuota(u.Plan)
+	}
+
+	now := time.Now().UTC()
+	since := now.AddDate(0, 0, 1-days).Format(time.DateOnly)
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT day, COUNT(*), SUM(tokens_in), SUM(tokens_out), SUM(cost)
+		 FROM llm_usage WHERE user_id = ? AND day >= ?
+		 GROUP BY day ORDER BY day`, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var d UsageDay
+		if err := rows.Scan(&d.Day, &d.Calls, &d.TokensIn, &d.TokensOut, &d.Cost); err != nil {
+			return nil, err
+		}
+		report.Days = append(report.Days, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	report.MonthTokens, report.MonthCost, err = s.monthUsage(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
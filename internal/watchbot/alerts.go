@@ -0,0 +1,316 @@
+package watchbot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/notify"
+)
+
+// Alert rule types: what a rule matches.
+const (
+	RuleSeverity = "severity"  // a threshold, e.g. "important", or a list: "critical|minor"
+	RuleKeyword  = "keyword"   // text in the analysis or diff, case-insensitive
+	RuleRegex    = "regex"     // RE2 pattern matched against the analysis and diff
+	RulePageType = "page_type" // page types, e.g. "pricing|changelog"
+)
+
+// Alert rule actions: what happens to the changes a rule matches.
+const (
+	ActionNotify   = "notify"   // include the change in the digest
+	ActionSuppress = "suppress" // leave the change out unless a notify or escalate rule matches it
+	ActionEscalate = "escalate" // "escalate:<channel>": also send the digest there
+)
+
+// AlertRule is one of a user's Smart Alert rules. Without rules a user is
+// notified of every change.
+type AlertRule struct {
+	ID           int       `json:"id"`
+	UserID       int       `json:"user_id"`
+	CompetitorID *int      `json:"competitor_id"` // nil applies to every competitor
+	RuleType     string    `json:"rule_type"`
+	RuleValue    string    `json:"rule_value"`
+	Action       string    `json:"action"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// escalationChannels are the channels a digest can be routed or escalated to. SMS
+// is left out: it carries critical alerts only, never a digest.
+var escalationChannels = map[notify.Channel]bool{
+	notify.ChannelEmail:      true,
+	notify.ChannelTelegram:   true,
+	notify.ChannelSlack:      true,
+	notify.ChannelDiscord:    true,
+	notify.ChannelWeChatWork: true,
+	notify.ChannelWebhook:    true,
+	notify.ChannelNtfy:       true,
+	notify.ChannelPushover:   true,
+}
+
+// Validate reports whether the rule's type, value and action are valid,
+// compiling regex rules.
+func (r AlertRule) Validate() error {
+	if _, err := r.matcher(); err != nil {
+		return err
+	}
+	_, _, err := r.action()
+	return err
+}
+
+// matcher returns the function that reports whether the rule matches a
+// change, ignoring the competitor the rule is scoped to. A single severity
+// is a threshold matching it and anything more severe, whatever the action.
+func (r AlertRule) matcher() (func(Change) bool, error) {
+	value := strings.TrimSpace(r.RuleValue)
+	if value == "" {
+		return nil, errors.New("empty rule value")
+	}
+	switch strings.ToLower(r.RuleType) {
+	case RuleSeverity:
+		levels := splitRuleList(value)
+		for _, l := range levels {
+			if l != "critical" && l != "important" && l != "minor" {
+				return nil, fmt.Errorf("unknown severity %q", l)
+			}
+		}
+		if len(levels) == 1 {
+			return func(c Change) bool { return notify.SeverityAtLeast(strings.ToLower(c.Severity), levels[0]) }, nil
+		}
+		return func(c Change) bool { return containsFold(levels, c.Severity) }, nil
+	case RuleKeyword:
+		keyword := strings.ToLower(value)
+		return func(c Change) bool {
+			return strings.Contains(strings.ToLower(c.Analysis), keyword) ||
+				strings.Contains(strings.ToLower(c.DiffUnified), keyword)
+		}, nil
+	case RuleRegex:
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex: %w", err)
+		}
+		return func(c Change) bool { return re.MatchString(c.Analysis) || re.MatchString(c.DiffUnified) }, nil
+	case RulePageType:
+		types := splitRuleList(value)
+		return func(c Change) bool { return containsFold(types, c.PageType) }, nil
+	default:
+		return nil, fmt.Errorf("unknown rule type %q", r.RuleType)
+	}
+}
+
+// action parses the rule's action into its kind and, for escalations, the
+// channel. A bare channel name, as older rules store, escalates to it.
+func (r AlertRule) action() (string, notify.Channel, error) {
+	kind, ch, _ := strings.Cut(strings.ToLower(strings.TrimSpace(r.Action)), ":")
+	switch kind {
+	case ActionNotify, ActionSuppress:
+		if ch != "" {
+			return "", "", fmt.Errorf("action %q takes no channel", kind)
+		}
+		return kind, "", nil
+	case ActionEscalate:
+	default:
+		if ch != "" {
+			return "", "", fmt.Errorf("unknown action %q", r.Action)
+		}
+		ch = kind
+	}
+	if !escalationChannels[notify.Channel(ch)] {
+		return "", "", fmt.Errorf("cannot escalate to channel %q", ch)
+	}
+	return ActionEscalate, notify.Channel(ch), nil
+}
+
+// splitRuleList splits a "a|b" or "a, b" rule value into lower-case items.
+func splitRuleList(value string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == '|' || r == ',' }) {
+		if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func containsFold(items []string, s string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// alertDecision is what a user's alert rules decide for a round's changes.
+type alertDecision struct {
+	changes  []Change         // the changes to notify about
+	escalate []notify.Channel // channels the digest also goes to, in rule order
+}
+
+// applyAlertRules evaluates a user's alert rules against their changes. A
+// change matched by a notify or escalate rule is kept, whatever suppress
+// rules match it. Otherwise it is left out when a suppress rule matches it
+// or, if notify or escalate rules apply to its competitor, in any case; with
+// only suppress rules applying every other change is kept. Escalate rules
+// that match a kept change add their channel to the digest. Invalid rules,
+// which the API refuses to store, never match.
+func applyAlertRules(changes []Change, rules []AlertRule) alertDecision {
+	if len(rules) == 0 {
+		return alertDecision{changes: changes} // Default: no rules means notify on everything
+	}
+
+	type compiled struct {
+		rule    AlertRule
+		matches func(Change) bool
+		kind    string
+		channel notify.Channel
+	}
+	var compiledRules []compiled
+	for _, r := range rules {
+		matches, err := r.matcher()
+		if err != nil {
+			continue
+		}
+		kind, ch, err := r.action()
+		if err != nil {
+			continue
+		}
+		compiledRules = append(compiledRules, compiled{rule: r, matches: matches, kind: kind, channel: ch})
+	}
+
+	var decision alertDecision
+	escalated := make(map[notify.Channel]bool)
+	for _, c := range changes {
+		// selective: whether the change must match a notify or escalate rule
+		suppressed, notified, selective := false, false, false
+		var channels []notify.Channel
+		for _, cr := range compiledRules {
+			// The rule may be scoped to one competitor
+			if cr.rule.CompetitorID != nil && *cr.rule.CompetitorID != c.CompetitorID {
+				continue
+			}
+			if cr.kind != ActionSuppress {
+				selective = true
+			}
+			if !cr.matches(c) {
+				continue
+			}
+			switch cr.kind {
+			case ActionSuppress:
+				suppressed = true
+			case ActionEscalate:
+				notified = true
+				channels = append(channels, cr.channel)
+			default:
+				notified = true
+			}
+		}
+		if !notified && (suppressed || selective) {
+			continue
+		}
+		decision.changes = append(decision.changes, c)
+		for _, ch := range channels {
+			if !escalated[ch] {
+				escalated[ch] = true
+				decision.escalate = append(decision.escalate, ch)
+			}
+		}
+	}
+	return decision
+}
+
+// escalationRoutes resolves the channels alert rules escalated a digest to
+// into routes: the user's own routes for the channel, their delivery email,
+// or else the channel's global notifier.
+func (gp *GlobalPipeline) escalationRoutes(ctx context.Context, u UserWithCompetitors, channels []notify.Channel) []notify.Route {
+	if gp.dispatcher == nil || len(channels) == 0 {
+		return nil
+	}
+	userRoutes, err := gp.store.GetUserRoutes(ctx, u.ID)
+	if err != nil {
+		gp.logger.Warn("failed to get notification routes", "user", u.Email, "error", err)
+	}
+
+	var routes []notify.Route
+	for _, ch := range channels {
+		if !gp.dispatcher.HasChannel(ch) {
+			gp.logger.Warn("alert rule escalates to an unconfigured channel", "user", u.Email, "channel", ch)
+			continue
+		}
+		if ch == notify.ChannelEmail {
+			if unsubscribed, _ := gp.store.GetUserSetting(ctx, u.ID, "unsubscribe."+UnsubscribeList); unsubscribed != "true" {
+				routes = append(routes, notify.Route{Channel: ch, Target: u.DeliveryEmail()})
+			}
+			continue
+		}
+		own := false
+		for _, r := range userRoutes {
+			if r.Channel == ch {
+				routes = append(routes, r)
+				own = true
+			}
+		}
+		if !own {
+			routes = append(routes, notify.Route{Channel: ch})
+		}
+	}
+	return routes
+}
+
+// dispatchDigest sends a digest along the recipient's routes the escalation
+// policy allows at severity, and along the routes alert rules escalated it
+// to whatever the policy says. Routes in both get it once.
+func (gp *GlobalPipeline) dispatchDigest(ctx context.Context, recipient notify.Recipient, escalated []notify.Route, severity string, msg notify.Message) error {
+	msg.Severity = severity
+	var err error
+	if len(recipient.Routes) > 0 {
+		err = gp.dispatcher.DispatchSeverity(ctx, recipient, severity, msg)
+	}
+	if len(escalated) > 0 {
+		if escErr := gp.dispatcher.DispatchTo(ctx, notify.Recipient{ID: recipient.ID, Routes: escalated}, msg); escErr != nil && err == nil {
+			err = escErr
+		}
+	}
+	return err
+}
+
+// GetUserAlertRules returns a user's active alert rules, oldest first.
+func (s *Store) GetUserAlertRules(ctx context.Context, userID int) ([]AlertRule, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, user_id, competitor_id, rule_type, rule_value, action, created_at
+		 FROM alert_rules WHERE user_id = ? AND is_active = ? ORDER BY id`, userID, true)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var rules []AlertRule
+	for rows.Next() {
+		var r AlertRule
+		var competitorID sql.NullInt64
+		if err := rows.Scan(&r.ID, &r.UserID, &competitorID, &r.RuleType, &r.RuleValue, &r.Action, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		if competitorID.Valid {
+			id := int(competitorID.Int64)
+			r.CompetitorID = &id
+		}
+		rules = append(rules, r)
+	}
+	return rules, rows.Err()
+}
+
+// AddAlertRule stores an alert rule for a user; a nil competitorID applies
+// it to all their competitors. Callers validate the rule first.
+func (s *Store) AddAlertRule(ctx context.Context, userID int, competitorID *int, ruleType, ruleValue, action string) (int, error) {
+	id, err := s.db.InsertID(ctx,
+		`INSERT INTO alert_rules (user_id, competitor_id, rule_type, rule_value, action) VALUES (?, ?, ?, ?, ?)`,
+		userID, competitorID, strings.ToLower(ruleType), ruleValue, strings.ToLower(action))
+	if err != nil {
+		return 0, fmt.Errorf("add alert rule: %w", err)
+	}
+	return int(id), nil
+}
@@ -0,0 +1,192 @@
+package watchbot
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/notify"
+)
+
+func TestAlertRuleMatcher(t *testing.T) {
+	minor := Change{Severity: "minor", Analysis: "Footer typo fixed", PageType: "blog"}
+	important := Change{Severity: "Important", Analysis: "Pro plan now $49/mo", PageType: "pricing"}
+	critical := Change{Severity: "critical", DiffUnified: "+ API v1 is deprecated", PageType: "changelog"}
+	changes := []Change{minor, important, critical}
+
+	tests := []struct {
+		rule AlertRule
+		want []bool // matches minor, important, critical
+	}{
+		{AlertRule{RuleType: RuleSeverity, RuleValue: "important", Action: ActionNotify}, []bool{false, true, true}},
+		{AlertRule{RuleType: RuleSeverity, RuleValue: "important", Action: "escalate:slack"}, []bool{false, true, true}},
+		{AlertRule{RuleType: RuleSeverity, RuleValue: "important", Action: ActionSuppress}, []bool{false, true, true}},
+		{AlertRule{RuleType: RuleSeverity, RuleValue: "critical", Action: ActionSuppress}, []bool{false, false, true}},
+		{AlertRule{RuleType: RuleSeverity, RuleValue: "minor", Action: ActionNotify}, []bool{true, true, true}},
+		{AlertRule{RuleType: "Severity", RuleValue: "critical|minor", Action: ActionNotify}, []bool{true, false, true}},
+		{AlertRule{RuleType: RuleSeverity, RuleValue: "important, minor", Action: ActionSuppress}, []bool{true, true, false}},
+		{AlertRule{RuleType: RuleKeyword, RuleValue: "PLAN", Action: ActionNotify}, []bool{false, true, false}},
+		{AlertRule{RuleType: RuleKeyword, RuleValue: "deprecated", Action: ActionNotify}, []bool{false, false, true}},
+		{AlertRule{RuleType: RuleRegex, RuleValue: `\$\d+/mo`, Action: ActionNotify}, []bool{false, true, false}},
+		{AlertRule{RuleType: RulePageType, RuleValue: "pricing|Changelog", Action: ActionNotify}, []bool{false, true, true}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s=%s/%s", tt.rule.RuleType, tt.rule.RuleValue, tt.rule.Action), func(t *testing.T) {
+			matches, err := tt.rule.matcher()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, c := range changes {
+				if got := matches(c); got != tt.want[i] {
+					t.Errorf("matches %s change = %v, want %v", c.Severity, got, tt.want[i])
+				}
+			}
+		})
+	}
+
+	for _, r := range []AlertRule{
+		{RuleType: RuleSeverity, RuleValue: " "},
+		{RuleType: RuleSeverity, RuleValue: "urgent"},
+		{RuleType: RuleSeverity, RuleValue: "critical|high"},
+		{RuleType: RuleRegex, RuleValue: "(unclosed"},
+		{RuleType: "sender", RuleValue: "acme"},
+	} {
+		if _, err := r.matcher(); err == nil {
+			t.Errorf("matcher for %s=%q succeeded, want an error", r.RuleType, r.RuleValue)
+		}
+	}
+}
+
+func TestAlertRuleAction(t *testing.T) {
+	tests := []struct {
+		action  string
+		kind    string
+		channel notify.Channel
+		wantErr bool
+	}{
+		{action: "notify", kind: ActionNotify},
+		{action: " Suppress ", kind: ActionSuppress},
+		{action: "escalate:slack", kind: ActionEscalate, channel: notify.ChannelSlack},
+		{action: "ESCALATE:Pushover", kind: ActionEscalate, channel: notify.ChannelPushover},
+		{action: "telegram", kind: ActionEscalate, channel: notify.ChannelTelegram}, // older rules
+		{action: "escalate:sms", wantErr: true},
+		{action: "escalate:", wantErr: true},
+		{action: "notify:email", wantErr: true},
+		{action: "page:slack", wantErr: true},
+		{action: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		kind, ch, err := AlertRule{Action: tt.action}.action()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("action(%q) error = %v, wantErr %v", tt.action, err, tt.wantErr)
+			continue
+		}
+		if kind != tt.kind || ch != tt.channel {
+			t.Errorf("action(%q) = %q, %q; want %q, %q", tt.action, kind, ch, tt.kind, tt.channel)
+		}
+	}
+}
+
+func TestApplyAlertRules(t *testing.T) {
+	acme, globex := 1, 2
+	changes := []Change{
+		{ID: 1, CompetitorID: acme, Severity: "minor", Analysis: "Footer typo fixed"},
+		{ID: 2, CompetitorID: acme, Severity: "important", Analysis: "New pricing tier", PageType: "pricing"},
+		{ID: 3, CompetitorID: globex, Severity: "critical", Analysis: "API v1 shut down"},
+		{ID: 4, CompetitorID: globex, Severity: "minor", Analysis: "Pricing FAQ reworded", PageType: "pricing"},
+	}
+	rule := func(ruleType, value, action string) AlertRule {
+		return AlertRule{RuleType: ruleType, RuleValue: value, Action: action}
+	}
+	scoped := func(r AlertRule, competitorID int) AlertRule {
+		r.CompetitorID = &competitorID
+		return r
+	}
+
+	tests := []struct {
+		name     string
+		rules    []AlertRule
+		want     []int // IDs of the changes kept
+		escalate []notify.Channel
+	}{
+		{name: "no rules", want: []int{1, 2, 3, 4}},
+		{
+			name:  "severity threshold",
+			rules: []AlertRule{rule(RuleSeverity, "important", ActionNotify)},
+			want:  []int{2, 3},
+		},
+		{
+			name:  "suppress alone keeps the rest",
+			rules: []AlertRule{rule(RuleKeyword, "typo", ActionSuppress)},
+			want:  []int{2, 3, 4},
+		},
+		{
+			name:  "suppress threshold",
+			rules: []AlertRule{rule(RuleSeverity, "important", ActionSuppress)},
+			want:  []int{1, 4},
+		},
+		{
+			name: "notify overrides suppress",
+			rules: []AlertRule{
+				rule(RuleSeverity, "minor", ActionSuppress),
+				rule(RulePageType, "pricing", ActionNotify),
+			},
+			want: []int{2, 4},
+		},
+		{
+			name: "escalate overrides suppress",
+			rules: []AlertRule{
+				rule(RuleKeyword, "api", ActionSuppress),
+				rule(RuleSeverity, "critical", "escalate:slack"),
+			},
+			want:     []int{3},
+			escalate: []notify.Channel{notify.ChannelSlack},
+		},
+		{
+			name: "escalations once each in rule order",
+			rules: []AlertRule{
+				rule(RuleSeverity, "important", "escalate:webhook"),
+				rule(RulePageType, "pricing", "escalate:slack"),
+				rule(RuleSeverity, "critical", "escalate:webhook"),
+				rule(RuleKeyword, "typo", ActionNotify),
+			},
+			want:     []int{1, 2, 3, 4},
+			escalate: []notify.Channel{notify.ChannelWebhook, notify.ChannelSlack},
+		},
+		{
+			name: "scoped to a competitor",
+			rules: []AlertRule{
+				scoped(rule(RuleSeverity, "minor", ActionNotify), acme),
+				scoped(rule(RuleSeverity, "minor", ActionSuppress), globex),
+			},
+			want: []int{1, 2},
+		},
+		{
+			name:  "notify scoped to a competitor leaves the others alone",
+			rules: []AlertRule{scoped(rule(RuleSeverity, "important", ActionNotify), acme)},
+			want:  []int{2, 3, 4},
+		},
+		{
+			name: "invalid rules never match",
+			rules: []AlertRule{
+				rule(RuleRegex, "(", ActionNotify),
+				rule(RuleKeyword, "typo", "escalate:sms"),
+			},
+			want: []int{1, 2, 3, 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := applyAlertRules(changes, tt.rules)
+			var got []int
+			for _, c := range decision.changes {
+				got = append(got, c.ID)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("kept %v, want %v", got, tt.want)
+			}
+			if fmt.Sprint(decision.escalate) != fmt.Sprint(tt.escalate) {
+				t.Errorf("escalated to %v, want %v", decision.escalate, tt.escalate)
+			}
+		})
+	}
+}
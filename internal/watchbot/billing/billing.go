@@ -0,0 +1,43 @@
+// Package billing starts Stripe Checkout sessions for WatchBot subscriptions.
+package billing
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/stripe/stripe-go/v81"
+	"github.com/stripe/stripe-go/v81/checkout/session"
+)
+
+// ErrNotConfigured is returned when STRIPE_SECRET_KEY is not set.
+var ErrNotConfigured = errors.New("billing: STRIPE_SECRET_KEY is not set")
+
+// CreateCheckoutSession creates a subscription Checkout session for priceID,
+// prefilled with the customer's email, and returns the URL of the page to
+// send the customer to.
+func CreateCheckoutSession(email, priceID, successURL, cancelURL string) (string, error) {
+	key := os.Getenv("STRIPE_SECRET_KEY")
+	if key == "" {
+		return "", ErrNotConfigured
+	}
+	if priceID == "" {
+		return "", errors.New("billing: price ID is required")
+	}
+
+	params := &stripe.CheckoutSessionParams{
+		Mode:          stripe.String(string(stripe.CheckoutSessionModeSubscription)),
+		CustomerEmail: stripe.String(email),
+		LineItems: []*stripe.CheckoutSessionLineItemParams{
+			{Price: stripe.String(priceID), Quantity: stripe.Int64(1)},
+		},
+		SuccessURL: stripe.String(successURL),
+		CancelURL:  stripe.String(cancelURL),
+	}
+	client := session.Client{B: stripe.GetBackend(stripe.APIBackend), Key: key}
+	s, err := client.New(params)
+	if err != nil {
+		return "", fmt.Errorf("billing: create checkout session: %w", err)
+	}
+	return s.URL, nil
+}
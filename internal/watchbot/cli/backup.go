@@ -0,0 +1,297 @@
+package cli
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	appconfig "github.com/RobinCoderZhao/devkit-suite/pkg/config"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
+)
+
+// A backup archive is a .tar.gz holding the database snapshot, the
+// benchmark and suite configs when there are any, and a manifest.
+const (
+	backupDBName       = "watchbot.db"
+	backupConfigName   = "benchmark_models.yaml"
+	backupSuiteName    = "devkit-suite.yaml"
+	backupManifestName = "manifest.json"
+)
+
+// backupConfigs maps the config files a backup carries to their paths.
+func backupConfigs() map[string]string {
+	return map[string]string{
+		backupConfigName: getEnv("BENCHMARK_CONFIG", "config/benchmark_models.yaml"),
+		backupSuiteName:  appconfig.SuitePath(),
+	}
+}
+
+type backupManifest struct {
+	Version   string    `json:"version"` // watchbot version that wrote the backup
+	CreatedAt time.Time `json:"created_at"`
+	Files     []string  `json:"files"`
+}
+
+// cmdBackup writes a backup archive to out, or to a timestamped file in the
+// working directory.
+func cmdBackup(out string) {
+	if out == "" {
+		out = "watchbot-backup-" + time.Now().Format("20060102-150405") + ".tar.gz"
+	}
+	db := openStorage()
+
+	if err := writeBackup(context.Background(), db, out); err != nil {
+		fmt.Printf("❌ 备份失败: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ 已备份到 %s\n", out)
+}
+
+// cmdRestore replaces the database, and the configs the archive has, with
+// the contents of a backup archive.
+func cmdRestore(in string, yes bool) {
+	if !yes {
+		answer := promptInput("⚠️  将覆盖当前数据库 (请先停止 serve / API 服务), 继续? (y/N): ")
+		if !strings.EqualFold(answer, "y") {
+			fmt.Println("已取消")
+			return
+		}
+	}
+
+	ctx := context.Background()
+	db := openStorage()
+
+	manifest, err := restoreBackup(ctx, db, in)
+	if err != nil {
+		fmt.Printf("❌ 恢复失败: %v\n", err)
+		os.Exit(1)
+	}
+	// The backup may predate migrations this binary has
+	if err := db.Migrate(ctx); err != nil {
+		fmt.Printf("❌ 迁移失败: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ 已从 %s 恢复 (备份时间 %s, 版本 %s)\n",
+		in, manifest.CreatedAt.Local().Format("2006-01-02 15:04"), manifest.Version)
+}
+
+// writeBackup snapshots the database into an archive at out. The archive
+// is written next to out and renamed into place, so a failed backup never
+// leaves a truncated file behind.
+func writeBackup(ctx context.Context, db *storage.DB, out string) error {
+	tmpDir, err := os.MkdirTemp("", "watchbot-backup-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	snapshot := filepath.Join(tmpDir, backupDBName)
+	if err := db.Backup(ctx, snapshot); err != nil {
+		return err
+	}
+	files := map[string]string{backupDBName: snapshot}
+	for name, path := range backupConfigs() {
+		if _, err := os.Stat(path); err == nil {
+			files[name] = path
+		}
+	}
+
+	manifest := backupManifest{Version: version, CreatedAt: time.Now().UTC()}
+	for name := range files {
+		manifest.Files = append(manifest.Files, name)
+	}
+	sort.Strings(manifest.Files)
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(out); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	f, err := os.CreateTemp(filepath.Dir(out), filepath.Base(out)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	err = writeArchive(tw, manifestJSON, manifest.Files, files)
+	if err := errors.Join(err, tw.Close(), gz.Close(), f.Close()); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+	return os.Rename(f.Name(), out)
+}
+
+// writeArchive adds the manifest and then each named file, read from its
+// path in paths.
+func writeArchive(tw *tar.Writer, manifestJSON []byte, names []string, paths map[string]string) error {
+	hdr := &tar.Header{Name: backupManifestName, Mode: 0o600, Size: int64(len(manifestJSON)), ModTime: time.Now()}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if _, err := tw.Write(manifestJSON); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := addTarFile(tw, name, paths[name]); err != nil {
+			return fmt.Errorf("add %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func addTarFile(tw *tar.Writer, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	hdr := &tar.Header{Name: name, Mode: 0o600, Size: info.Size(), ModTime: info.ModTime()}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// restoreBackup restores the database from the archive at in, and writes
+// its configs over the current ones, keeping those as .bak.
+func restoreBackup(ctx context.Context, db *storage.DB, in string) (*backupManifest, error) {
+	f, err := os.Open(in)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "watchbot-restore-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var manifest *backupManifest
+	extracted := make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read archive: %w", err)
+		}
+		switch hdr.Name {
+		case backupManifestName:
+			manifest = &backupManifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("read manifest: %w", err)
+			}
+		case backupDBName, backupConfigName, backupSuiteName:
+			path := filepath.Join(tmpDir, hdr.Name)
+			out, err := os.Create(path)
+			if err != nil {
+				return nil, err
+			}
+			_, err = io.Copy(out, tr)
+			if err = errors.Join(err, out.Close()); err != nil {
+				return nil, fmt.Errorf("extract %s: %w", hdr.Name, err)
+			}
+			extracted[hdr.Name] = path
+		}
+	}
+	if manifest == nil || extracted[backupDBName] == "" {
+		return nil, fmt.Errorf("%s is not a watchbot backup", in)
+	}
+
+	if err := db.Restore(ctx, extracted[backupDBName]); err != nil {
+		return nil, err
+	}
+	for name, configPath := range backupConfigs() {
+		if src := extracted[name]; src != "" {
+			if err := restoreConfig(src, configPath); err != nil {
+				return nil, fmt.Errorf("restore %s: %w", name, err)
+			}
+		}
+	}
+	return manifest, nil
+}
+
+func restoreConfig(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if old, err := os.ReadFile(dst); err == nil {
+		if err := os.WriteFile(dst+".bak", old, 0o644); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0o644)
+}
+
+// backupSchedule returns how often serve writes scheduled backups
+// (WATCHBOT_BACKUP_INTERVAL, off when unset), where to
+// (WATCHBOT_BACKUP_DIR) and how many to keep (WATCHBOT_BACKUP_KEEP).
+func backupSchedule() (interval time.Duration, dir string, keep int) {
+	interval = envDuration("WATCHBOT_BACKUP_INTERVAL", 0)
+	dir = getEnv("WATCHBOT_BACKUP_DIR", "data/backups")
+	keep, err := strconv.Atoi(getEnv("WATCHBOT_BACKUP_KEEP", "7"))
+	if err != nil || keep < 1 {
+		slog.Warn("invalid WATCHBOT_BACKUP_KEEP, keeping 7", "value", os.Getenv("WATCHBOT_BACKUP_KEEP"))
+		keep = 7
+	}
+	return interval, dir, keep
+}
+
+// runScheduledBackup writes one scheduled backup to dir and prunes all but
+// the newest keep.
+func runScheduledBackup(ctx context.Context, db *storage.DB, dir string, keep int) error {
+	out := filepath.Join(dir, "watchbot-"+time.Now().Format("20060102-150405")+".tar.gz")
+	if err := writeBackup(ctx, db, out); err != nil {
+		return fmt.Errorf("scheduled backup: %w", err)
+	}
+	slog.Info("scheduled backup written", "path", out)
+	pruneBackups(dir, keep)
+	return nil
+}
+
+// pruneBackups deletes all but the newest keep scheduled backups in dir.
+// Their timestamped names sort by age.
+func pruneBackups(dir string, keep int) {
+	names, err := filepath.Glob(filepath.Join(dir, "watchbot-*.tar.gz"))
+	if err != nil || len(names) <= keep {
+		return
+	}
+	sort.Strings(names)
+	for _, name := range names[:len(names)-keep] {
+		if err := os.Remove(name); err != nil {
+			slog.Warn("remove old backup", "path", name, "error", err)
+		}
+	}
+}
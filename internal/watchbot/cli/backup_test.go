@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
+)
+
+func TestBackupRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	benchConfig := filepath.Join(dir, "config", "benchmark_models.yaml")
+	suiteConfig := filepath.Join(dir, "devkit-suite.yaml")
+	t.Setenv("BENCHMARK_CONFIG", benchConfig)
+	t.Setenv("DEVKIT_CONFIG", suiteConfig)
+	writeFile := func(path, data string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	readFile := func(path string) string {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	db, err := storage.Open(storage.Config{Driver: storage.SQLite, DSN: filepath.Join(dir, "watchbot.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO users (email, password_hash) VALUES ('alice@example.com', '')`); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(benchConfig, "models: [backed-up]\n")
+	writeFile(suiteConfig, "llm:\n  provider: backed-up\n")
+
+	archive := filepath.Join(dir, "backups", "watchbot.tar.gz")
+	if err := writeBackup(ctx, db, archive); err != nil {
+		t.Fatal(err)
+	}
+
+	// Change everything the backup holds
+	if _, err := db.ExecContext(ctx, `DELETE FROM users`); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(benchConfig, "models: [edited]\n")
+	writeFile(suiteConfig, "llm:\n  provider: edited\n")
+
+	manifest, err := restoreBackup(ctx, db, archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Version != version || len(manifest.Files) != 3 {
+		t.Errorf("manifest = %+v, want version %s and 3 files", manifest, version)
+	}
+	var email string
+	if err := db.QueryRowContext(ctx, `SELECT email FROM users`).Scan(&email); err != nil || email != "alice@example.com" {
+		t.Errorf("restored user = %q, %v", email, err)
+	}
+	for path, want := range map[string]string{
+		benchConfig:          "models: [backed-up]\n",
+		benchConfig + ".bak": "models: [edited]\n",
+		suiteConfig:          "llm:\n  provider: backed-up\n",
+		suiteConfig + ".bak": "llm:\n  provider: edited\n",
+	} {
+		if got := readFile(path); got != want {
+			t.Errorf("%s = %q, want %q", filepath.Base(path), got, want)
+		}
+	}
+
+	// Only the database is backed up when there are no configs
+	os.Remove(benchConfig)
+	os.Remove(suiteConfig)
+	if err := writeBackup(ctx, db, archive); err != nil {
+		t.Fatal(err)
+	}
+	if manifest, err := restoreBackup(ctx, db, archive); err != nil || len(manifest.Files) != 1 {
+		t.Errorf("database-only restore = %+v, %v", manifest, err)
+	}
+	if _, err := os.Stat(suiteConfig); !os.IsNotExist(err) {
+		t.Errorf("restore wrote a suite config the backup does not have: %v", err)
+	}
+
+	writeFile(filepath.Join(dir, "not-a-backup.tar.gz"), "plain text")
+	if _, err := restoreBackup(ctx, db, filepath.Join(dir, "not-a-backup.tar.gz")); err == nil {
+		t.Error("restoring a non-archive succeeded")
+	}
+}
@@ -0,0 +1,397 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RobinCoderZhao/devkit-suite/internal/suite"
+	"github.com/RobinCoderZhao/devkit-suite/internal/watchbot"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/notify"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/scraper"
+)
+
+// Command builds the watchbot command tree. Every command except config
+// loads the suite config first.
+func Command() *cobra.Command {
+	root := &cobra.Command{
+		Use:               "watchbot",
+		Short:             "WatchBot — 竞品监控 (SaaS 后端引擎)",
+		Version:           version,
+		SilenceUsage:      true,
+		PersistentPreRunE: suite.PreRun,
+	}
+	root.AddCommand(
+		addCmd(),
+		removeCmd(),
+		&cobra.Command{
+			Use:   "list",
+			Short: "列出所有竞品",
+			Args:  cobra.NoArgs,
+			Run:   func(cmd *cobra.Command, args []string) { cmdList() },
+		},
+		checkCmd(),
+		searchCmd(),
+		benchmarkCmd(),
+		benchmarkUpdatesCmd(),
+		routesCmd(),
+		escalationCmd(),
+		&cobra.Command{
+			Use:   "unmatched-models",
+			Short: "列出新模型候选及最近抓取中未匹配的模型名 (用于添加 aliases)",
+			Args:  cobra.NoArgs,
+			Run:   func(cmd *cobra.Command, args []string) { cmdUnmatchedModels() },
+		},
+		quarantineCmd(),
+		&cobra.Command{
+			Use:   "serve",
+			Short: "守护进程模式",
+			Long:  "守护进程模式。检查、Benchmark 抓取、定时备份与 Webhook 推送都作为任务在数据库中的任务队列上运行, 失败自动重试, 重启后继续。\n每个页面按自己的检查间隔检查 (watchbot add --interval, 默认 WATCHBOT_CHECK_INTERVAL=6h); WATCHBOT_CHECK_SCHEDULE 设置查找到期页面的频率 (默认 @every 5m, 也可用 cron 表达式如 \"*/10 * * * *\"); BENCHMARK_INTERVAL 设置 Benchmark 抓取周期 (默认 168h); WATCHBOT_BACKUP_INTERVAL 开启定时备份。\n发送失败的通知按指数退避重试 (watchbot notifications 查看)。\n收到 SIGTERM 后不再检查新页面, 正在检查的页面在 WATCHBOT_DRAIN_TIMEOUT (默认 2m) 内完成, 未发送的摘要留到下一轮; 再次发送信号立即退出。\n设置 METRICS_ADDR (如 \":9090\") 后在该地址的 /metrics 提供 Prometheus 指标。",
+			Args:  cobra.NoArgs,
+			Run:   func(cmd *cobra.Command, args []string) { cmdServe() },
+		},
+		jobsCmd(),
+		notificationsCmd(),
+		mcpCmd(),
+		suite.MigrateCommand(),
+		backupCmd(),
+		restoreCmd(),
+		configCmd(),
+		suite.TelemetryCommand(),
+		&cobra.Command{
+			Use:   "version",
+			Short: "版本",
+			Args:  cobra.NoArgs,
+			Run:   func(cmd *cobra.Command, args []string) { fmt.Printf("watchbot %s\n", version) },
+		},
+	)
+	return root
+}
+
+func addCmd() *cobra.Command {
+	var interval time.Duration
+	var browser bool
+	var selector string
+	var opts addOptions
+	cmd := &cobra.Command{
+		Use:   "add <url-or-text>",
+		Short: "添加监控目标 (分配给本地默认用户)",
+		Example: `  watchbot add https://stripe.com/pricing
+  watchbot add https://openai.com/changelog --interval=1h
+  watchbot add https://app.example.com/pricing --browser --wait-for=".plan-card"
+  watchbot add https://stripe.com/pricing --selector=".pricing-table"
+  watchbot add "监控 Gemini API 文档变化"`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("interval") {
+				if interval != 0 && interval < watchbot.MinCheckInterval {
+					return fmt.Errorf("--interval 至少为 %s", watchbot.MinCheckInterval)
+				}
+				opts.interval = &interval
+			}
+			if opts.waitFor != "" {
+				browser = true
+			}
+			if cmd.Flags().Changed("browser") || opts.waitFor != "" {
+				opts.browser = &browser
+			}
+			if cmd.Flags().Changed("selector") {
+				if selector != "" {
+					if _, err := scraper.ParseSelector(selector); err != nil {
+						return fmt.Errorf("--selector: %w", err)
+					}
+				}
+				opts.selector = &selector
+			}
+			cmdAdd(strings.Join(args, " "), opts)
+			return nil
+		},
+	}
+	cmd.Flags().DurationVar(&interval, "interval", 0, "serve 检查该页面的间隔, 如 1h、24h (默认 WATCHBOT_CHECK_INTERVAL; 0 恢复默认)")
+	cmd.Flags().BoolVar(&browser, "browser", false, "用无头浏览器渲染页面 (JS 渲染的单页应用、需登录的页面)")
+	cmd.Flags().StringVar(&opts.waitFor, "wait-for", "", "浏览器读取页面前等待出现的 CSS 选择器 (隐含 --browser)")
+	cmd.Flags().StringVar(&selector, "selector", "", `只监控匹配的部分: CSS 选择器或以 / 开头的 XPath, 如 ".pricing-table" (空值恢复整页)`)
+	return cmd
+}
+
+func removeCmd() *cobra.Command {
+	var name string
+	cmd := &cobra.Command{
+		Use:   "remove",
+		Short: "删除竞品",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdRemove(name)
+		},
+	}
+	cmd.Flags().StringVarP(&name, "name", "n", "", "竞品名称")
+	cmd.MarkFlagRequired("name")
+	return cmd
+}
+
+func checkCmd() *cobra.Command {
+	var previewPath string
+	var since time.Duration
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "运行一次全量检查",
+		Example: `  watchbot check
+  watchbot check --preview-email=out.html --since=48h`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdCheck(previewPath, since)
+		},
+	}
+	cmd.Flags().StringVarP(&previewPath, "preview-email", "p", "", "渲染最近变化的邮件到文件 (不抓取、不发送)")
+	cmd.Flags().DurationVar(&since, "since", 7*24*time.Hour, "--preview-email 包含的变化时间范围")
+	return cmd
+}
+
+func searchCmd() *cobra.Command {
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "全文搜索页面快照与变化分析 (按时间从早到晚)",
+		Example: `  watchbot search SSO
+  watchbot search "audit logs" --limit=10`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdSearch(strings.Join(args, " "), limit)
+		},
+	}
+	cmd.Flags().IntVarP(&limit, "limit", "l", 20, fmt.Sprintf("最多显示的结果数 (最多 %d)", watchbot.MaxSearchResults))
+	return cmd
+}
+
+func benchmarkCmd() *cobra.Command {
+	var opts benchmarkOptions
+	cmd := &cobra.Command{
+		Use:   "benchmark",
+		Short: "模型 Benchmark 对比",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdBenchmark(opts)
+		},
+	}
+	f := cmd.Flags()
+	f.StringVarP(&opts.output, "output", "o", "", "输出格式: png|html|md|charts|csv|json|text")
+	f.StringVarP(&opts.file, "file", "f", "", "输出文件 (charts 时为输出目录)")
+	f.StringVar(&opts.mode, "mode", "", "best: 每个厂商最新模型 vs 上一代")
+	f.StringVar(&opts.scrape, "scrape", "", "抓取数据: seed|live|true (seed + live)")
+	f.Lookup("scrape").NoOptDefVal = "true"
+	f.StringVar(&opts.models, "models", "", "替换配置中的模型列表")
+	f.StringVar(&opts.addModel, "add-model", "", "在配置的模型之外追加一个模型")
+	f.StringVarP(&opts.email, "email", "e", "", "报告收件人 (默认 SMTP_TO)")
+	f.BoolVar(&opts.history, "history", false, "查看 Benchmark 抓取记录 (新增/更新分数、错误、数据新鲜度)")
+	cmd.RegisterFlagCompletionFunc("output", fixedCompletions("png", "html", "md", "charts", "csv", "json", "text"))
+	cmd.RegisterFlagCompletionFunc("mode", fixedCompletions("best"))
+	cmd.RegisterFlagCompletionFunc("scrape", fixedCompletions("seed", "live", "true"))
+	return cmd
+}
+
+func benchmarkUpdatesCmd() *cobra.Command {
+	var email string
+	var off bool
+	cmd := &cobra.Command{
+		Use:   "benchmark-updates",
+		Short: "订阅/退订定期 Benchmark 报告",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdBenchmarkUpdates(email, !off)
+		},
+	}
+	cmd.Flags().StringVarP(&email, "email", "e", "", "订阅者邮箱")
+	cmd.Flags().BoolVar(&off, "off", false, "退订")
+	cmd.MarkFlagRequired("email")
+	return cmd
+}
+
+func routesCmd() *cobra.Command {
+	var email string
+	var set []string
+	var clear bool
+	cmd := &cobra.Command{
+		Use:   "routes",
+		Short: "查看/设置用户的摘要通知渠道",
+		Long:  "查看/设置用户的摘要通知渠道。--set 替换全部渠道, 格式为 channel 或 channel=target (如 telegram=123456、webhook=https://...); 未设置渠道时摘要发往通知邮箱。",
+		Example: `  watchbot routes --email a@example.com
+  watchbot routes --email a@example.com --set email --set telegram=123456
+  watchbot routes --email a@example.com --clear`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if clear && len(set) > 0 {
+				return fmt.Errorf("--set 与 --clear 不能同时使用")
+			}
+			var routes []notify.Route
+			for _, s := range set {
+				ch, target, _ := strings.Cut(s, "=")
+				routes = append(routes, notify.Route{Channel: notify.Channel(strings.ToLower(strings.TrimSpace(ch))), Target: strings.TrimSpace(target)})
+			}
+			if err := watchbot.ValidateRoutes(routes); err != nil {
+				return err
+			}
+			cmdRoutes(email, routes, clear || len(set) > 0)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&email, "email", "e", "", "用户邮箱")
+	cmd.Flags().StringArrayVar(&set, "set", nil, "通知渠道 channel[=target], 可重复")
+	cmd.Flags().BoolVar(&clear, "clear", false, "清除渠道, 恢复发往通知邮箱")
+	cmd.MarkFlagRequired("email")
+	return cmd
+}
+
+func escalationCmd() *cobra.Command {
+	var email string
+	var set []string
+	var clear bool
+	cmd := &cobra.Command{
+		Use:   "escalation",
+		Short: "查看/设置用户按严重级别升级的通知渠道",
+		Long:  "查看/设置用户按严重级别升级的通知渠道。--set 替换整个策略, 格式为 channel=severity, severity 为 minor|important|critical|off; 未列出的渠道只用于 critical 变化。未设置时使用服务端默认 (WATCHBOT_ESCALATION)。",
+		Example: `  watchbot escalation --email a@example.com
+  watchbot escalation --email a@example.com --set email=minor --set telegram=important --set sms=off
+  watchbot escalation --email a@example.com --clear`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if clear && len(set) > 0 {
+				return fmt.Errorf("--set 与 --clear 不能同时使用")
+			}
+			var policy notify.EscalationPolicy
+			for _, s := range set {
+				ch, sev, ok := strings.Cut(s, "=")
+				if !ok {
+					return fmt.Errorf("无效的 --set %q, 应为 channel=severity", s)
+				}
+				if policy == nil {
+					policy = notify.EscalationPolicy{}
+				}
+				policy[notify.Channel(strings.ToLower(strings.TrimSpace(ch)))] = strings.ToLower(strings.TrimSpace(sev))
+			}
+			if err := policy.Validate(); err != nil {
+				return err
+			}
+			cmdEscalation(email, policy, clear || len(set) > 0)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&email, "email", "e", "", "用户邮箱")
+	cmd.Flags().StringArrayVar(&set, "set", nil, "channel=severity, 可重复")
+	cmd.Flags().BoolVar(&clear, "clear", false, "清除策略, 恢复服务端默认")
+	cmd.MarkFlagRequired("email")
+	return cmd
+}
+
+func quarantineCmd() *cobra.Command {
+	var release bool
+	var r quarantineRelease
+	cmd := &cobra.Command{
+		Use:   "quarantine",
+		Short: "查看/放行被隔离的异常分数",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !release {
+				cmdQuarantine(nil)
+				return nil
+			}
+			if r.bench == "" || r.model == "" {
+				return fmt.Errorf("--release 需要 --bench 和 --model")
+			}
+			cmdQuarantine(&r)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&release, "release", false, "放行一个被隔离的分数")
+	cmd.Flags().StringVar(&r.bench, "bench", "", "Benchmark ID")
+	cmd.Flags().StringVar(&r.model, "model", "", "模型名称")
+	cmd.Flags().StringVar(&r.variant, "variant", "", "Benchmark 变体")
+	return cmd
+}
+
+func mcpCmd() *cobra.Command {
+	var addr string
+	cmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "MCP 服务, 供 LLM Agent 管理监控",
+		Long:  "MCP 服务, 供 LLM Agent 管理监控。默认使用 stdio; --http 时用 MCP_TOKEN 鉴权。",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdMCP(addr)
+		},
+	}
+	cmd.Flags().StringVar(&addr, "http", "", "在该地址提供 Streamable HTTP 服务, 例如 :8090")
+	return cmd
+}
+
+// MCPCommand builds the root command of the standalone watchbot-mcp
+// binary: the mcp command with the suite config loaded first, for MCP
+// clients that launch a server by path alone.
+func MCPCommand() *cobra.Command {
+	cmd := mcpCmd()
+	cmd.Use = "watchbot-mcp"
+	cmd.Version = version
+	cmd.SilenceUsage = true
+	cmd.PersistentPreRunE = suite.PreRun
+	return cmd
+}
+
+func backupCmd() *cobra.Command {
+	var out string
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "备份数据库 (SQLite 在线备份)、Benchmark 配置与 devkit-suite.yaml",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdBackup(out)
+		},
+	}
+	cmd.Flags().StringVarP(&out, "out", "o", "", "备份文件 (默认 watchbot-backup-<时间>.tar.gz)")
+	return cmd
+}
+
+func restoreCmd() *cobra.Command {
+	var in string
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "从备份恢复 (先停止 serve / API 服务)",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdRestore(in, yes)
+		},
+	}
+	cmd.Flags().StringVarP(&in, "in", "i", "", "备份文件")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "不确认直接恢复")
+	cmd.MarkFlagRequired("in")
+	return cmd
+}
+
+func configCmd() *cobra.Command {
+	var path string
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "统一配置文件管理",
+		// validating must not require a valid config
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+	}
+	validate := &cobra.Command{
+		Use:   "validate",
+		Short: "校验统一配置文件 (默认 DEVKIT_CONFIG 或 devkit-suite.yaml, 环境变量优先于文件)",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdConfigValidate(path)
+		},
+	}
+	validate.Flags().StringVarP(&path, "file", "f", "", "配置文件")
+	cmd.AddCommand(validate)
+	return cmd
+}
+
+// fixedCompletions completes a flag from a fixed set of values.
+func fixedCompletions(values ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/jobs"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/notify"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
+)
+
+// Job kinds run by serve.
+const (
+	jobCheck     = "watchbot.check"
+	jobBenchmark = "benchmarks.scrape"
+	jobBackup    = "watchbot.backup"
+	jobWebhook   = "notify.webhook"
+	jobNotify    = "notify.retry"
+)
+
+// notifyJobs is the prefix of the notification job kinds, listed by
+// watchbot notifications.
+const notifyJobs = "notify."
+
+// notifyRetryAttempts is how many times serve resends a failed
+// notification: with the failed send, ten attempts over about four hours of
+// notify.RetryBackoff.
+const notifyRetryAttempts = 9
+
+// schedule registers a recurring job, exiting on an invalid spec since
+// serve cannot do its work without it.
+func schedule(ctx context.Context, queue *jobs.Queue, r jobs.Recurring) {
+	if err := queue.Schedule(ctx, r); err != nil {
+		slog.Error("schedule job failed", "name", r.Name, "spec", r.Spec, "error", err)
+		os.Exit(1)
+	}
+}
+
+// secretBox loads the key that seals queued deliveries, which carry
+// webhook URLs and message content.
+func secretBox() *storage.SecretBox {
+	box, err := storage.SecretBoxFromEnv()
+	if err != nil {
+		slog.Error("load secret key failed", "error", err)
+		os.Exit(1)
+	}
+	return box
+}
+
+// enqueueDelivery stores dl as a job of kind, sealed when box is set.
+func enqueueDelivery(ctx context.Context, queue *jobs.Queue, box *storage.SecretBox, job jobs.Job, dl notify.Delivery) error {
+	data, err := json.Marshal(dl)
+	if err != nil {
+		return err
+	}
+	job.Payload = string(data)
+	if box != nil {
+		if job.Payload, err = box.Seal(ctx, job.Payload); err != nil {
+			return fmt.Errorf("seal delivery: %w", err)
+		}
+	}
+	_, err = queue.Enqueue(ctx, job)
+	return err
+}
+
+// decodeDelivery opens and decodes a queued delivery.
+func decodeDelivery(ctx context.Context, box *storage.SecretBox, payload string) (notify.Delivery, error) {
+	var dl notify.Delivery
+	if storage.IsSealed(payload) {
+		if box == nil {
+			return dl, fmt.Errorf("delivery is sealed but %s is not set", storage.SecretKeyEnv)
+		}
+		var err error
+		if payload, err = box.Open(ctx, payload); err != nil {
+			return dl, err
+		}
+	}
+	if err := json.Unmarshal([]byte(payload), &dl); err != nil {
+		return dl, fmt.Errorf("decode delivery: %w", err)
+	}
+	return dl, nil
+}
+
+// retryOnQueue returns the notify.RetryFunc that queues a failed send as a
+// notify.retry job, first run after notify.RetryBackoff(1). Any process can
+// queue one; serve sends them, see handleNotifyJobs.
+func retryOnQueue(queue *jobs.Queue) notify.RetryFunc {
+	box := secretBox()
+	return func(ctx context.Context, dl notify.Delivery, sendErr error) error {
+		job := jobs.Job{
+			Kind:        jobNotify,
+			MaxAttempts: notifyRetryAttempts,
+			RunAt:       time.Now().Add(notify.RetryBackoff(1)),
+			LastError:   sendErr.Error(),
+		}
+		return enqueueDelivery(ctx, queue, box, job, dl)
+	}
+}
+
+// handleNotifyJobs registers the handlers that send queued notifications:
+// failed sends retried with notify.RetryBackoff, and webhook deliveries,
+// which dispatcher now queues so a slow or failing endpoint is retried in
+// the background instead of holding up the check round.
+func handleNotifyJobs(queue *jobs.Queue, dispatcher *notify.Dispatcher) {
+	box := secretBox()
+	deliver := func(ctx context.Context, job jobs.Job) error {
+		dl, err := decodeDelivery(ctx, box, job.Payload)
+		if err != nil {
+			return err
+		}
+		return dispatcher.Deliver(ctx, dl)
+	}
+
+	dispatcher.SetDeferred(notify.ChannelWebhook, func(ctx context.Context, dl notify.Delivery) error {
+		return enqueueDelivery(ctx, queue, box, jobs.Job{Kind: jobWebhook}, dl)
+	})
+	queue.Handle(jobWebhook, jobs.HandlerOptions{Workers: 4, Timeout: time.Minute, MaxAttempts: 8}, deliver)
+	queue.Handle(jobNotify, jobs.HandlerOptions{Workers: 2, Timeout: 2 * time.Minute, MaxAttempts: notifyRetryAttempts, Backoff: notify.RetryBackoff}, deliver)
+}
+
+func jobsCmd() *cobra.Command {
+	var status string
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "查看任务队列: 定时任务与最近的任务",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdJobs(status, limit)
+		},
+	}
+	cmd.Flags().StringVar(&status, "status", "", "只看该状态: pending|running|done|failed")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 20, "显示最近的任务数")
+	cmd.RegisterFlagCompletionFunc("status", fixedCompletions(jobs.StatusPending, jobs.StatusRunning, jobs.StatusDone, jobs.StatusFailed))
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "retry <id>",
+		Short: "重新执行一个失败的任务",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("无效的任务 ID: %s", args[0])
+			}
+			db, _ := openDB()
+			if err := jobs.New(db).Retry(cmd.Context(), id); err != nil {
+				return err
+			}
+			fmt.Printf("✅ 任务 %d 已重新排队, serve 将很快执行\n", id)
+			return nil
+		},
+	})
+	return cmd
+}
+
+func cmdJobs(status string, limit int) {
+	ctx := context.Background()
+	db, _ := openDB()
+	queue := jobs.New(db)
+
+	schedules, err := queue.Schedules(ctx)
+	if err != nil {
+		slog.Error("list schedules failed", "error", err)
+		os.Exit(1)
+	}
+	fmt.Printf("定时任务 (%d):\n", len(schedules))
+	for _, s := range schedules {
+		last := "从未运行"
+		if !s.LastRunAt.IsZero() {
+			last = s.LastRunAt.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Printf("  %-20s %-20s 下次: %s  上次: %s\n", s.Name, s.Spec, s.NextRunAt.Local().Format("2006-01-02 15:04"), last)
+	}
+
+	recent, err := queue.Recent(ctx, status, limit)
+	if err != nil {
+		slog.Error("list jobs failed", "error", err)
+		os.Exit(1)
+	}
+	fmt.Printf("\n最近的任务 (%d):\n", len(recent))
+	for _, j := range recent {
+		fmt.Printf("  #%-6d %-18s %-8s 尝试 %d/%d  %s\n", j.ID, j.Kind, j.Status, j.Attempts, j.MaxAttempts, j.RunAt.Local().Format("2006-01-02 15:04"))
+		if j.LastError != "" && j.Status != jobs.StatusDone {
+			fmt.Printf("          错误: %s\n", j.LastError)
+		}
+	}
+}
@@ -0,0 +1,505 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/internal/watchbot"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/benchmarks"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/i18n"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/jobs"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/llm"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/mcpserver"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/scraper"
+)
+
+// mcpUserID owns everything the MCP tools add or read: the local CLI user.
+const mcpUserID = 1
+
+// cmdMCP serves the monitoring tools over MCP, on stdio by default or on
+// addr, so LLM agents can add competitors, read changes, run checks
+// and query the tracked benchmark scores.
+func cmdMCP(addr string) {
+	db, store := openDB()
+
+	// Use Pro tier for change analysis, as in cmdCheck
+	llmClient, err := llm.NewTieredClient(llm.TierPro)
+	if err != nil {
+		slog.Warn("LLM client not available", "error", err)
+	}
+	if llmClient != nil {
+		defer llmClient.Close()
+	}
+
+	server := mcpserver.New("watchbot", version)
+	server.Use(mcpserver.RecoveryMiddleware())
+	server.Use(mcpserver.LoggingMiddleware(slog.Default()))
+	registerWatchTools(server, store, newPipeline(store, llmClient, newDispatcher(store, retryOnQueue(jobs.New(db)))))
+
+	bStore, err := benchmarks.NewStore(db)
+	if err != nil {
+		slog.Warn("benchmark tools disabled", "error", err)
+	} else {
+		configPath := getEnv("BENCHMARK_CONFIG", "config/benchmark_models.yaml")
+		cfg, err := benchmarks.LoadConfig(configPath)
+		if err != nil {
+			slog.Warn("load benchmark config", "error", err)
+			cfg = &benchmarks.Config{Models: benchmarks.DefaultModels}
+		}
+		registerBenchmarkTools(server, bStore, cfg.Models)
+	}
+
+	if addr == "" {
+		if err := server.RunStdio(); err != nil {
+			slog.Error("mcp server failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if token := os.Getenv("MCP_TOKEN"); token != "" {
+		server.SetHTTPAuthToken(token)
+	}
+	server.Use(mcpserver.RateLimitMiddleware(5, 20))
+	slog.Info("WatchBot MCP serving", "addr", addr)
+	if err := server.RunHTTP(addr); err != nil {
+		slog.Error("mcp server failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+type addCompetitorArgs struct {
+	URL      string `json:"url" description:"Page to monitor, e.g. https://stripe.com/pricing"`
+	Name     string `json:"name,omitempty" description:"Competitor name; defaults to the URL's domain"`
+	Interval string `json:"check_interval,omitempty" description:"How often to check the page, e.g. 1h for changelogs or 24h for pricing; defaults to the server's interval"`
+	Browser  bool   `json:"browser,omitempty" description:"Render the page in a headless browser, for single-page apps whose content needs JavaScript"`
+	Selector string `json:"selector,omitempty" description:"CSS selector or XPath of the part of the page to monitor, e.g. .pricing-table"`
+}
+
+type listChangesArgs struct {
+	SinceHours int    `json:"since_hours,omitempty" description:"Look back this many hours" jsonschema:"default=168,minimum=1"`
+	Competitor string `json:"competitor,omitempty" description:"Only changes for this competitor"`
+	Severity   string `json:"severity,omitempty" description:"Only changes of this severity" jsonschema:"enum=critical|important|minor"`
+	Diff       bool   `json:"include_diff,omitempty" description:"Include each change's unified diff"`
+}
+
+type timelineArgs struct {
+	Competitor string `json:"competitor" description:"Competitor name as shown by list_competitors"`
+	Limit      int    `json:"limit,omitempty" description:"Most recent changes to return" jsonschema:"default=20,minimum=1"`
+}
+
+// mcpChange is a detected change as the MCP tools return it.
+type mcpChange struct {
+	ID         int       `json:"id"`
+	Competitor string    `json:"competitor,omitempty"`
+	URL        string    `json:"url"`
+	PageType   string    `json:"page_type,omitempty"`
+	Severity   string    `json:"severity"`
+	Analysis   string    `json:"analysis"`
+	Diff       string    `json:"diff,omitempty"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+func toMCPChange(c watchbot.Change, withDiff bool) mcpChange {
+	mc := mcpChange{
+		ID:         c.ID,
+		Competitor: c.CompetitorName,
+		URL:        c.PageURL,
+		PageType:   c.PageType,
+		Severity:   c.Severity,
+		Analysis:   c.Analysis,
+		DetectedAt: c.CreatedAt,
+	}
+	if withDiff {
+		mc.Diff = c.DiffUnified
+	}
+	return mc
+}
+
+// registerWatchTools registers the WatchBot tools and prompts on server.
+func registerWatchTools(server *mcpserver.Server, store *watchbot.Store, pipeline *watchbot.GlobalPipeline) {
+	mcpserver.RegisterFunc(server, "list_competitors", "List monitored competitors and their pages",
+		func(ctx context.Context, _ struct{}) (*mcpserver.ToolCallResult, error) {
+			competitors, err := store.ListCompetitorsByUser(ctx, mcpUserID)
+			if err != nil {
+				return nil, fmt.Errorf("list competitors: %w", err)
+			}
+			type page struct {
+				URL         string     `json:"url"`
+				PageType    string     `json:"page_type"`
+				LastChecked *time.Time `json:"last_checked,omitempty"`
+				Interval    string     `json:"check_interval,omitempty"` // omitted for the server default
+				Selector    string     `json:"selector,omitempty"`
+			}
+			type competitor struct {
+				Name   string `json:"name"`
+				Domain string `json:"domain"`
+				Pages  []page `json:"pages"`
+			}
+			result := make([]competitor, 0, len(competitors))
+			for _, c := range competitors {
+				pages, err := store.GetPagesByCompetitor(ctx, c.ID)
+				if err != nil {
+					return nil, fmt.Errorf("list pages for %s: %w", c.Name, err)
+				}
+				entry := competitor{Name: c.Name, Domain: c.Domain, Pages: []page{}}
+				for _, p := range pages {
+					pg := page{URL: p.URL, PageType: p.PageType, LastChecked: p.LastCheckedAt, Selector: p.Selector}
+					if p.CheckInterval > 0 {
+						pg.Interval = p.CheckInterval.String()
+					}
+					entry.Pages = append(entry.Pages, pg)
+				}
+				result = append(result, entry)
+			}
+			return mcpserver.SuccessResult(result), nil
+		})
+
+	mcpserver.RegisterFunc(server, "add_competitor", "Start monitoring a competitor page; it is checked on the next run",
+		func(ctx context.Context, args addCompetitorArgs) (*mcpserver.ToolCallResult, error) {
+			vr := watchbot.ValidateURL(ctx, args.URL)
+			if !vr.Valid {
+				return nil, fmt.Errorf("invalid URL %s: %s", args.URL, vr.Localized(i18n.LangEN))
+			}
+			domain := watchbot.ExtractDomain(vr.URL)
+			name := strings.TrimSpace(args.Name)
+			if name == "" {
+				name = domain
+			}
+			var interval time.Duration
+			if args.Interval != "" {
+				var err error
+				if interval, err = time.ParseDuration(args.Interval); err != nil || interval < watchbot.MinCheckInterval {
+					return nil, fmt.Errorf("invalid check_interval %q: use a duration of at least %s", args.Interval, watchbot.MinCheckInterval)
+				}
+			}
+			if args.Selector != "" {
+				if _, err := scraper.ParseSelector(args.Selector); err != nil {
+					return nil, err
+				}
+			}
+			pageType := watchbot.GuessPageType(vr.URL)
+			compID, err := store.AddCompetitor(ctx, mcpUserID, name, domain)
+			if err != nil {
+				return nil, fmt.Errorf("add competitor: %w", err)
+			}
+			pageID, err := store.AddPage(ctx, compID, vr.URL, pageType)
+			if err != nil {
+				return nil, fmt.Errorf("add page: %w", err)
+			}
+			if interval > 0 {
+				if err := store.SetCheckInterval(ctx, pageID, interval); err != nil {
+					return nil, fmt.Errorf("set check interval: %w", err)
+				}
+			}
+			if args.Browser {
+				if err := store.SetBrowserRendering(ctx, pageID, true, ""); err != nil {
+					return nil, fmt.Errorf("enable browser rendering: %w", err)
+				}
+			}
+			if args.Selector != "" {
+				if err := store.SetSelector(ctx, pageID, args.Selector); err != nil {
+					return nil, fmt.Errorf("set selector: %w", err)
+				}
+			}
+			return mcpserver.SuccessResult(map[string]string{
+				"competitor": name,
+				"url":        vr.URL,
+				"page_type":  pageType,
+			}), nil
+		})
+
+	mcpserver.RegisterFunc(server, "list_changes", "List changes detected on monitored pages, newest first",
+		func(ctx context.Context, args listChangesArgs) (*mcpserver.ToolCallResult, error) {
+			hours := args.SinceHours
+			if hours <= 0 {
+				hours = 7 * 24
+			}
+			changes, err := store.GetRecentChanges(ctx, time.Now().Add(-time.Duration(hours)*time.Hour))
+			if err != nil {
+				return nil, fmt.Errorf("get changes: %w", err)
+			}
+			result := []mcpChange{}
+			for _, c := range changes {
+				if c.UserID != mcpUserID {
+					continue
+				}
+				if args.Competitor != "" && !strings.EqualFold(c.CompetitorName, args.Competitor) {
+					continue
+				}
+				if args.Severity != "" && c.Severity != args.Severity {
+					continue
+				}
+				result = append(result, toMCPChange(c, args.Diff))
+			}
+			return mcpserver.SuccessResult(result), nil
+		})
+
+	mcpserver.RegisterFunc(server, "get_timeline", "Get the change history of one competitor, newest first",
+		func(ctx context.Context, args timelineArgs) (*mcpserver.ToolCallResult, error) {
+			comp, err := store.GetCompetitor(ctx, mcpUserID, args.Competitor)
+			if err != nil {
+				return nil, fmt.Errorf("competitor %q not found", args.Competitor)
+			}
+			changes, err := store.GetTimelineByCompetitor(ctx, comp.ID)
+			if err != nil {
+				return nil, fmt.Errorf("get timeline: %w", err)
+			}
+			limit := args.Limit
+			if limit <= 0 {
+				limit = 20
+			}
+			changes = changes[:min(limit, len(changes))]
+			result := make([]mcpChange, 0, len(changes))
+			for _, c := range changes {
+				result = append(result, toMCPChange(c, false))
+			}
+			return mcpserver.SuccessResult(map[string]any{
+				"competitor": comp.Name,
+				"domain":     comp.Domain,
+				"changes":    result,
+			}), nil
+		})
+
+	// A round fetches every page and sends digests, so one runs at a time
+	var checkMu sync.Mutex
+	pipeline.SetProgress(func(ctx context.Context, checked, total int, url string) {
+		mcpserver.ReportProgress(ctx, float64(checked), float64(total), "checked "+url)
+	})
+	runCheck := mcpserver.NewFuncTool("run_check", "Run a full monitoring round now: fetch every page, analyze changes and send digests",
+		func(ctx context.Context, _ struct{}) (*mcpserver.ToolCallResult, error) {
+			if !checkMu.TryLock() {
+				return nil, errors.New("a check is already running")
+			}
+			defer checkMu.Unlock()
+
+			// created_at is stored at second precision
+			started := time.Now().Truncate(time.Second)
+			if err := pipeline.RunCheck(ctx); err != nil {
+				return nil, fmt.Errorf("check: %w", err)
+			}
+			changes, err := store.GetRecentChanges(ctx, started)
+			if err != nil {
+				return nil, fmt.Errorf("get changes: %w", err)
+			}
+			result := []mcpChange{}
+			for _, c := range changes {
+				if c.UserID == mcpUserID {
+					result = append(result, toMCPChange(c, false))
+				}
+			}
+			return mcpserver.SuccessResult(map[string]any{
+				"duration": time.Since(started).Round(time.Second).String(),
+				"changes":  result,
+			}), nil
+		})
+	runCheck.ToolTimeout = envDuration("WATCHBOT_MCP_CHECK_TIMEOUT", 30*time.Minute)
+	server.RegisterTool(runCheck)
+
+	brief, err := mcpserver.NewTemplatePrompt("competitor_brief",
+		"Summarize recent competitor moves using the WatchBot tools",
+		[]mcpserver.PromptArgument{
+			{Name: "competitor", Description: "Limit the brief to one competitor"},
+			{Name: "days", Description: "How many days to cover (default 7)"},
+		},
+		`Write a short briefing on what {{if .competitor}}{{.competitor}}{{else}}our competitors{{end}} changed in the last {{if .days}}{{.days}}{{else}}7{{end}} days.
+Use list_changes (and get_timeline for more history) to gather the changes. Group them by competitor, lead with critical and important ones, and say what each change likely means for us. Skip cosmetic edits.`)
+	if err != nil {
+		slog.Error("mcp prompt", "error", err)
+		os.Exit(1)
+	}
+	server.RegisterPrompt(brief)
+}
+
+type benchmarkReportArgs struct {
+	Mode     string `json:"mode,omitempty" description:"all: every configured model; best: each provider's latest model vs its previous generation" jsonschema:"enum=all|best,default=all"`
+	Category string `json:"category,omitempty" description:"Only scores in this category" jsonschema:"enum=reasoning|coding|agent|search|multimodal|knowledge|long_context|arena"`
+}
+
+type compareModelsArgs struct {
+	A string `json:"a" description:"First model, e.g. Gemini 3.1 Pro"`
+	B string `json:"b" description:"Second model"`
+}
+
+type getScoreArgs struct {
+	Benchmark string `json:"benchmark" description:"Benchmark ID or name, e.g. swe_bench_verified or GPQA Diamond"`
+	Model     string `json:"model" description:"Model name"`
+	Variant   string `json:"variant,omitempty" description:"Benchmark variant; all variants when empty"`
+}
+
+// mcpModel is a report column as get_benchmark_report returns it.
+type mcpModel struct {
+	Name      string                   `json:"name"`
+	Provider  string                   `json:"provider"`
+	Gen       string                   `json:"gen,omitempty"`
+	Baseline  string                   `json:"compared_with,omitempty"`
+	Composite *float64                 `json:"composite,omitempty"`
+	Pricing   *benchmarks.ModelPricing `json:"pricing,omitempty"`
+}
+
+// mcpScore is one variant's score as get_score returns it.
+type mcpScore struct {
+	Variant    string    `json:"variant,omitempty"`
+	Score      float64   `json:"score"`
+	Rank       int       `json:"rank"`
+	Of         int       `json:"of"`
+	Leader     string    `json:"leader"`
+	Change     *float64  `json:"change_since_last_scrape,omitempty"`
+	SourceType string    `json:"source_type,omitempty"`
+	SourceURL  string    `json:"source_url,omitempty"`
+	ScrapedAt  time.Time `json:"scraped_at,omitzero"`
+}
+
+// registerBenchmarkTools registers tools that read the tracked benchmark
+// scores for models, the configured report columns. Lookups cover the
+// fallback models as well.
+func registerBenchmarkTools(server *mcpserver.Server, bStore *benchmarks.Store, models []benchmarks.ModelConfig) {
+	allModels := slices.Clone(models)
+	for _, fb := range benchmarks.FallbackModels {
+		if !slices.ContainsFunc(allModels, func(m benchmarks.ModelConfig) bool { return m.Name == fb.Name }) {
+			allModels = append(allModels, fb)
+		}
+	}
+	today := func() string { return time.Now().Format("2006-01-02") }
+
+	mcpserver.RegisterFunc(server, "get_benchmark_report", "Get the current AI model benchmark report: models with composite scores and pricing, and every tracked score with its source",
+		func(ctx context.Context, args benchmarkReportArgs) (*mcpserver.ToolCallResult, error) {
+			report, err := bStore.GetScoresForReport(ctx, models, today())
+			if err != nil {
+				return nil, fmt.Errorf("build report: %w", err)
+			}
+			// Same columns as watchbot benchmark
+			report.FilterEmptyModels(3, 10)
+			if args.Mode == "best" {
+				report = report.BestOfProviders()
+			}
+			stored, err := bStore.GetAllScores(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("load scores: %w", err)
+			}
+
+			composite := report.CompositeScores(benchmarks.CategoryWeights)
+			cols := make([]mcpModel, 0, len(report.Models))
+			for _, m := range report.Models {
+				col := mcpModel{Name: m.Name, Provider: m.Provider, Gen: m.Gen, Baseline: report.Baselines[m.Name]}
+				if s, ok := composite[m.Name]; ok {
+					col.Composite = &s
+				}
+				if p, ok := report.Pricing[m.Name]; ok {
+					col.Pricing = &p
+				}
+				cols = append(cols, col)
+			}
+			records := []benchmarks.ExportRecord{}
+			for _, rec := range benchmarks.ExportRecords(report, stored) {
+				if args.Category == "" || rec.Category == args.Category {
+					records = append(records, rec)
+				}
+			}
+			return mcpserver.SuccessResult(map[string]any{
+				"date":   report.Date,
+				"models": cols,
+				"scores": records,
+			}), nil
+		})
+
+	mcpserver.RegisterFunc(server, "compare_models", "Compare two models head to head on every benchmark both have scores on, with composite, category and pricing differences",
+		func(ctx context.Context, args compareModelsArgs) (*mcpserver.ToolCallResult, error) {
+			report, err := bStore.GetScoresForReport(ctx, allModels, today())
+			if err != nil {
+				return nil, fmt.Errorf("build report: %w", err)
+			}
+			a, err := findReportModel(report, args.A)
+			if err != nil {
+				return nil, err
+			}
+			b, err := findReportModel(report, args.B)
+			if err != nil {
+				return nil, err
+			}
+			if a.Name == b.Name {
+				return nil, fmt.Errorf("%q and %q are the same model", args.A, args.B)
+			}
+			return mcpserver.SuccessResult(report.CompareModels(a.Name, b.Name)), nil
+		})
+
+	mcpserver.RegisterFunc(server, "get_score", "Get one model's score on a benchmark, with its rank, the leader and where the score came from",
+		func(ctx context.Context, args getScoreArgs) (*mcpserver.ToolCallResult, error) {
+			bench := benchmarks.LookupBenchmark(args.Benchmark)
+			if bench == nil {
+				ids := make([]string, 0, len(benchmarks.AllBenchmarks))
+				for _, b := range benchmarks.AllBenchmarks {
+					ids = append(ids, b.ID)
+				}
+				return nil, fmt.Errorf("unknown benchmark %q; tracked: %s", args.Benchmark, strings.Join(ids, ", "))
+			}
+			variants := bench.Variants
+			switch {
+			case args.Variant != "":
+				i := slices.IndexFunc(variants, func(v string) bool { return strings.EqualFold(v, args.Variant) })
+				if i < 0 {
+					return nil, fmt.Errorf("%s has no variant %q", bench.Name, args.Variant)
+				}
+				variants = variants[i : i+1]
+			case len(variants) == 0:
+				variants = []string{""}
+			}
+
+			report, err := bStore.GetScoresForReport(ctx, allModels, today())
+			if err != nil {
+				return nil, fmt.Errorf("build report: %w", err)
+			}
+			model, err := findReportModel(report, args.Model)
+			if err != nil {
+				return nil, err
+			}
+			scores := []mcpScore{}
+			for _, v := range variants {
+				score, ok := report.GetScore(bench.ID, v, model.Name)
+				if !ok {
+					continue
+				}
+				rank, of, _ := report.Rank(bench.ID, v, model.Name)
+				p := report.GetProvenance(bench.ID, v, model.Name)
+				sc := mcpScore{
+					Variant:    v,
+					Score:      score,
+					Rank:       rank,
+					Of:         of,
+					Leader:     report.HighestOf[benchmarks.ScoreKey(bench.ID, v)],
+					SourceType: p.SourceType,
+					SourceURL:  p.SourceURL,
+					ScrapedAt:  p.ScrapedAt,
+				}
+				if d, ok := report.Delta(bench.ID, v, model.Name); ok {
+					sc.Change = &d
+				}
+				scores = append(scores, sc)
+			}
+			if len(scores) == 0 {
+				return nil, fmt.Errorf("no %s score for %s", bench.Name, model.Name)
+			}
+			return mcpserver.SuccessResult(map[string]any{
+				"benchmark_id": bench.ID,
+				"benchmark":    bench.Name,
+				"unit":         bench.Unit,
+				"model":        model.Name,
+				"scores":       scores,
+			}), nil
+		})
+}
+
+// findReportModel resolves a model name an agent passed, listing the known
+// models when it matches none.
+func findReportModel(report *benchmarks.BenchmarkReport, name string) (benchmarks.ModelConfig, error) {
+	if m, ok := report.FindModel(name); ok {
+		return m, nil
+	}
+	return benchmarks.ModelConfig{}, fmt.Errorf("unknown model %q; models with scores: %s", name, strings.Join(report.ModelsWithScores(), ", "))
+}
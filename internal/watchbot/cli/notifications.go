@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/jobs"
+)
+
+func notificationsCmd() *cobra.Command {
+	var failed bool
+	var status string
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "notifications",
+		Short: "查看发送失败、等待重试的通知",
+		Long:  "查看通知重试队列。发送失败的邮件、Telegram 等通知作为 notify.retry 任务存入任务队列, 由 serve 按指数退避重试 (1m 起翻倍, 最长 6h), 共 10 次仍失败则标记为 failed, 可用 requeue 重新排队。Webhook 推送 (notify.webhook) 也在此列出。",
+		Example: "  watchbot notifications --failed\n" +
+			"  watchbot notifications requeue 42\n" +
+			"  watchbot notifications requeue --all",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if failed {
+				status = jobs.StatusFailed
+			}
+			cmdNotifications(status, limit)
+		},
+	}
+	cmd.Flags().BoolVar(&failed, "failed", false, "只看已放弃的通知 (同 --status=failed)")
+	cmd.Flags().StringVar(&status, "status", "", "只看该状态: pending|running|done|failed")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 20, "显示最近的通知数")
+	cmd.RegisterFlagCompletionFunc("status", fixedCompletions(jobs.StatusPending, jobs.StatusRunning, jobs.StatusDone, jobs.StatusFailed))
+
+	var all bool
+	requeue := &cobra.Command{
+		Use:   "requeue [id]",
+		Short: "重新发送失败的通知",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if all != (len(args) == 0) {
+				return fmt.Errorf("请指定通知 ID 或 --all")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, _ := openDB()
+			queue := jobs.New(db)
+			if all {
+				n, err := queue.RetryKinds(cmd.Context(), notifyJobs)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("✅ %d 条通知已重新排队, serve 将很快发送\n", n)
+				return nil
+			}
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("无效的通知 ID: %s", args[0])
+			}
+			if err := queue.Retry(cmd.Context(), id); err != nil {
+				return err
+			}
+			fmt.Printf("✅ 通知 %d 已重新排队, serve 将很快发送\n", id)
+			return nil
+		},
+	}
+	requeue.Flags().BoolVar(&all, "all", false, "重新排队全部失败的通知")
+	cmd.AddCommand(requeue)
+	return cmd
+}
+
+func cmdNotifications(status string, limit int) {
+	ctx := context.Background()
+	db, _ := openDB()
+
+	entries, err := jobs.New(db).RecentKinds(ctx, notifyJobs, status, limit)
+	if err != nil {
+		slog.Error("list notifications failed", "error", err)
+		os.Exit(1)
+	}
+	if len(entries) == 0 {
+		fmt.Println("ℹ️  没有符合条件的通知")
+		return
+	}
+	box := secretBox()
+	fmt.Printf("通知 (%d):\n", len(entries))
+	for _, j := range entries {
+		when := "下次: " + j.RunAt.Local().Format("2006-01-02 15:04")
+		if j.Status != jobs.StatusPending {
+			when = "创建于: " + j.CreatedAt.Local().Format("2006-01-02 15:04")
+		}
+		what := "(无法读取: 需要 DEVKIT_SECRET_KEY)"
+		if dl, err := decodeDelivery(ctx, box, j.Payload); err == nil {
+			what = fmt.Sprintf("%s %s → %s", dl.Route.Channel, dl.Title, dl.Recipient)
+		}
+		fmt.Printf("  #%-6d %-8s 尝试 %d/%d  %s  %s\n", j.ID, j.Status, j.Attempts, j.MaxAttempts, when, what)
+		if j.LastError != "" && j.Status != jobs.StatusDone {
+			fmt.Printf("          错误: %s\n", j.LastError)
+		}
+	}
+}
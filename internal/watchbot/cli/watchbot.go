@@ -0,0 +1,1441 @@
+// Package cli implements the watchbot command line. cmd/watchbot runs it on
+// its own and cmd/devkit-suite as a subcommand.
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/RobinCoderZhao/devkit-suite/internal/suite"
+	"github.com/RobinCoderZhao/devkit-suite/internal/user"
+	"github.com/RobinCoderZhao/devkit-suite/internal/watchbot"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/benchmarks"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/benchmarks/parsers"
+	appconfig "github.com/RobinCoderZhao/devkit-suite/pkg/config"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/jobs"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/llm"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/notify"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/scraper"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
+)
+
+var version = "2.0.0"
+
+// --- Database ---
+
+// openStorage returns the shared database without migrating it.
+func openStorage() *storage.DB {
+	db, err := suite.DB()
+	if err != nil {
+		slog.Error("open database failed", "error", err)
+		os.Exit(1)
+	}
+	return db
+}
+
+func openDB() (*storage.DB, *watchbot.Store) {
+	db := openStorage()
+
+	// Auto-migrate schema on startup
+	ctx := context.Background()
+	if err := db.Migrate(ctx); err != nil {
+		slog.Error("schema migration failed", "error", err)
+		os.Exit(1)
+	}
+
+	// Ensure a default user exists for CLI operations
+	_, _ = db.ExecContext(ctx, `INSERT INTO users (id, email, password_hash, plan) VALUES (1, 'cli@local', 'cli_hash', 'pro') ON CONFLICT DO NOTHING`)
+	if db.DriverType() == storage.Postgres {
+		// The explicit id leaves the users sequence behind
+		_, _ = db.ExecContext(ctx, `SELECT setval(pg_get_serial_sequence('users', 'id'), (SELECT MAX(id) FROM users))`)
+	}
+
+	box, err := storage.SecretBoxFromEnv()
+	if err != nil {
+		slog.Error("load secret key failed", "error", err)
+		os.Exit(1)
+	}
+	store := watchbot.NewStore(db)
+	store.SetSecretBox(box)
+
+	return db, store
+}
+
+// --- Config ---
+
+func cmdConfigValidate(path string) {
+	if path == "" {
+		path = appconfig.SuitePath()
+	}
+	if err := appconfig.ValidateSuite(path); err != nil {
+		fmt.Printf("❌ %s 校验失败:\n%v\n", path, err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ %s 校验通过\n", path)
+}
+
+// --- LLM ---
+
+func newLLMClient() llm.Client {
+	apiKey := os.Getenv("LLM_API_KEY")
+	if apiKey == "" {
+		return nil
+	}
+	cfg := llm.Config{
+		Provider:    llm.Provider(getEnv("LLM_PROVIDER", "openai")),
+		Model:       getEnv("LLM_MODEL", "gpt-4o-mini"),
+		APIKey:      apiKey,
+		BaseURL:     os.Getenv("LLM_BASE_URL"),
+		MaxRetries:  3,
+		Timeout:     60 * time.Second,
+		Temperature: 0.3,
+		Deployment:  os.Getenv("LLM_DEPLOYMENT"),
+		APIVersion:  os.Getenv("LLM_API_VERSION"),
+		Cache:       suite.LLMCache(context.Background()),
+	}
+	if cfg.Provider == "minimax" && cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.minimax.io/v1"
+	}
+	client, err := llm.NewClient(cfg)
+	if err != nil {
+		slog.Warn("LLM client creation failed", "error", err)
+		return nil
+	}
+	return withUsage(client)
+}
+
+// withUsage records the client's calls per user and enforces their plan's
+// token quota.
+func withUsage(client llm.Client) llm.Client {
+	return llm.WithUsage(client, user.NewStore(openStorage()))
+}
+
+// --- Commands ---
+
+// addOptions are the flags of the add command. Nil fields leave the
+// setting of pages that already exist unchanged.
+type addOptions struct {
+	interval *time.Duration // how often serve checks the pages
+	browser  *bool          // render the pages in a headless browser
+	waitFor  string         // CSS selector the browser waits for
+	selector *string        // part of the pages to monitor
+}
+
+// cmdAdd adds the pages described by input for the local user.
+func cmdAdd(input string, opts addOptions) {
+	ctx := context.Background()
+	_, store := openDB()
+
+	addPage := func(compID int, url, pageType string) {
+		pageID, err := store.AddPage(ctx, compID, url, pageType)
+		if err != nil {
+			fmt.Printf("❌ 添加页面失败: %v\n", err)
+			os.Exit(1)
+		}
+		if opts.interval != nil {
+			if err := store.SetCheckInterval(ctx, pageID, *opts.interval); err != nil {
+				fmt.Printf("❌ 设置检查间隔失败: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if opts.browser != nil {
+			if err := store.SetBrowserRendering(ctx, pageID, *opts.browser, opts.waitFor); err != nil {
+				fmt.Printf("❌ 设置浏览器渲染失败: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if opts.selector != nil {
+			if err := store.SetSelector(ctx, pageID, *opts.selector); err != nil {
+				fmt.Printf("❌ 设置选择器失败: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	if watchbot.IsURL(input) {
+		// Direct URL mode
+		fmt.Printf("🔍 验证 URL: %s\n", input)
+		vr := watchbot.ValidateURL(ctx, input)
+		if !vr.Valid {
+			fmt.Printf("❌ URL 无效: %s\n", vr.Error)
+			if vr.URL != "" {
+				fmt.Printf("   标准化后: %s\n", vr.URL)
+			}
+			os.Exit(1)
+		}
+		domain := watchbot.ExtractDomain(vr.URL)
+		pageType := watchbot.GuessPageType(vr.URL)
+		name := promptInput(fmt.Sprintf("竞品名称 (默认: %s): ", domain))
+		if name == "" {
+			name = domain
+		}
+		compID, _ := store.AddCompetitor(ctx, 1, name, domain) // Hardcode userID 1 for CLI
+		addPage(compID, vr.URL, pageType)
+		fmt.Printf("✅ 已添加: %s [%s] %s\n", name, pageType, vr.URL)
+	} else {
+		// Natural language mode
+		llmClient := newLLMClient()
+		if llmClient == nil {
+			fmt.Println("❌ 自然语言模式需要配置 LLM_API_KEY")
+			fmt.Println("   或者直接使用 URL: watchbot add https://...")
+			os.Exit(1)
+		}
+		defer llmClient.Close()
+
+		providers, err := watchbot.ParseSearchProviders(os.Getenv("WATCHBOT_SEARCH_PROVIDERS"), watchbot.SearchConfig{
+			GoogleAPIKey: os.Getenv("GOOGLE_API_KEY"),
+			GoogleCX:     os.Getenv("GOOGLE_CX"),
+			BingAPIKey:   os.Getenv("BING_API_KEY"),
+			BraveAPIKey:  os.Getenv("BRAVE_API_KEY"),
+			SerpAPIKey:   os.Getenv("SERPAPI_API_KEY"),
+		})
+		if err != nil {
+			fmt.Printf("❌ WATCHBOT_SEARCH_PROVIDERS 无效: %v\n", err)
+			os.Exit(1)
+		}
+		resolver := watchbot.NewResolver(llmClient, watchbot.ResolverConfig{Providers: providers})
+
+		fmt.Printf("🤖 分析: \"%s\"\n", input)
+		result, err := resolver.Resolve(llm.ForUser(ctx, 1, "watchbot.resolve"), input)
+		if err != nil {
+			fmt.Printf("❌ 解析失败: %v\n", err)
+			os.Exit(1)
+		}
+
+		if result.Error != "" {
+			fmt.Printf("❌ %s\n", result.Error)
+			fmt.Println("   请提供具体信息，例如：")
+			fmt.Println(`   watchbot add "监控 OpenAI API 文档变化"`)
+			fmt.Println("   watchbot add https://openai.com/pricing")
+			os.Exit(1)
+		}
+
+		if len(result.URLs) == 0 {
+			fmt.Printf("🤔 识别到产品: %s，但无法确定 URL\n", result.Name)
+			fmt.Println("   请手动输入 URL：watchbot add <url>")
+			os.Exit(1)
+		}
+
+		// Show candidate and ask for confirmation
+		fmt.Printf("\n🤖 建议监控 (来源: %s)：\n", result.Source)
+		fmt.Printf("  [%s] %s\n", result.PageType, result.Name)
+		for _, u := range result.URLs {
+			fmt.Printf("  %s\n", u)
+		}
+		confirm := promptInput("\n确认添加？[Y/n]: ")
+		if confirm != "" && strings.ToLower(confirm) != "y" {
+			fmt.Println("已取消")
+			return
+		}
+
+		domain := watchbot.ExtractDomain(result.URLs[0])
+		compID, _ := store.AddCompetitor(ctx, 1, result.Name, domain) // Hardcode userID 1
+		for _, u := range result.URLs {
+			pageType := watchbot.GuessPageType(u)
+			addPage(compID, u, pageType)
+		}
+		fmt.Printf("✅ 已添加: %s (%d 个页面)\n", result.Name, len(result.URLs))
+	}
+}
+
+func cmdRemove(name string) {
+	ctx := context.Background()
+	db, _ := openDB()
+
+	if _, err := db.ExecContext(ctx, `DELETE FROM competitors WHERE LOWER(name) = LOWER(?)`, name); err != nil {
+		fmt.Printf("❌ 删除失败: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ 已删除: %s\n", name)
+}
+
+func cmdBenchmarkUpdates(email string, enabled bool) {
+	ctx := context.Background()
+	_, store := openDB()
+
+	if err := store.SetBenchmarkUpdatesByEmail(ctx, email, enabled); err != nil {
+		fmt.Printf("❌ 设置失败: %v\n", err)
+		os.Exit(1)
+	}
+	if enabled {
+		fmt.Printf("✅ %s 已订阅 Benchmark 报告\n", email)
+	} else {
+		fmt.Printf("✅ %s 已退订 Benchmark 报告\n", email)
+	}
+}
+
+func cmdRoutes(email string, routes []notify.Route, replace bool) {
+	ctx := context.Background()
+	_, store := openDB()
+
+	if replace {
+		if err := store.SetUserRoutesByEmail(ctx, email, routes); err != nil {
+			fmt.Printf("❌ 设置失败: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	routes, err := store.GetUserRoutesByEmail(ctx, email)
+	if err != nil {
+		fmt.Printf("❌ 查询失败: %v\n", err)
+		os.Exit(1)
+	}
+	if len(routes) == 0 {
+		fmt.Printf("📭 %s 未设置通知渠道, 摘要发往通知邮箱\n", email)
+		return
+	}
+	fmt.Printf("📬 %s 的通知渠道:\n", email)
+	for _, r := range routes {
+		if r.Target == "" {
+			fmt.Printf("  • %s\n", r.Channel)
+		} else {
+			fmt.Printf("  • %s → %s\n", r.Channel, r.Target)
+		}
+	}
+}
+
+func cmdEscalation(email string, policy notify.EscalationPolicy, replace bool) {
+	ctx := context.Background()
+	_, store := openDB()
+
+	if replace {
+		if err := store.SetUserEscalationByEmail(ctx, email, policy); err != nil {
+			fmt.Printf("❌ 设置失败: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	policy, err := store.GetUserEscalationByEmail(ctx, email)
+	if err != nil {
+		fmt.Printf("❌ 查询失败: %v\n", err)
+		os.Exit(1)
+	}
+	if len(policy) == 0 {
+		fmt.Printf("📭 %s 未设置升级策略, 使用服务端默认\n", email)
+		return
+	}
+	channels := make([]string, 0, len(policy))
+	for ch := range policy {
+		channels = append(channels, string(ch))
+	}
+	sort.Strings(channels)
+	fmt.Printf("📶 %s 的升级策略 (未列出的渠道只用于 critical):\n", email)
+	for _, ch := range channels {
+		if sev := policy[notify.Channel(ch)]; sev == notify.SeverityOff {
+			fmt.Printf("  • %s: 关闭\n", ch)
+		} else {
+			fmt.Printf("  • %s: %s 及以上\n", ch, sev)
+		}
+	}
+}
+
+func cmdUnmatchedModels() {
+	ctx := context.Background()
+	db, _ := openDB()
+
+	bStore, err := benchmarks.NewStore(db)
+	if err != nil {
+		slog.Error("init benchmark store", "error", err)
+		os.Exit(1)
+	}
+	candidates, err := bStore.GetCandidates(ctx)
+	if err != nil {
+		slog.Error("list model candidates", "error", err)
+		os.Exit(1)
+	}
+	if len(candidates) > 0 {
+		fmt.Printf("🆕 %d 个未匹配模型在多个榜单达到已跟踪模型的中位数 (可能是新发布的模型):\n\n", len(candidates))
+		for _, c := range candidates {
+			fmt.Printf("  %q (%s, 首次发现 %s)\n    %s\n", c.RawName, c.Source, c.FirstSeen.Format("2006-01-02"), c.Summary)
+		}
+		fmt.Println()
+	}
+
+	unmatched, err := bStore.GetUnmatched(ctx)
+	if err != nil {
+		slog.Error("list unmatched models", "error", err)
+		os.Exit(1)
+	}
+	if len(unmatched) == 0 {
+		fmt.Println("暂无未匹配的模型名。使用 watchbot benchmark --scrape=live 抓取后再查看。")
+		return
+	}
+
+	fmt.Printf("🔍 %d 个排行榜模型名未匹配 (在配置的 aliases 中添加映射):\n", len(unmatched))
+	source := ""
+	for _, u := range unmatched {
+		if u.Source != source {
+			source = u.Source
+			fmt.Printf("\n  %s (%s)\n", source, u.LastSeen.Format("2006-01-02 15:04"))
+		}
+		fmt.Printf("    %q\n", strings.ToLower(u.RawName))
+	}
+}
+
+// quarantineRelease names a quarantined score to release.
+type quarantineRelease struct {
+	bench, variant, model string
+}
+
+// cmdQuarantine lists quarantined scores, or releases one when release is set.
+func cmdQuarantine(release *quarantineRelease) {
+	ctx := context.Background()
+	db, _ := openDB()
+
+	bStore, err := benchmarks.NewStore(db)
+	if err != nil {
+		slog.Error("init benchmark store", "error", err)
+		os.Exit(1)
+	}
+
+	if release != nil {
+		bench, model := release.bench, release.model
+		released, err := bStore.ReleaseQuarantined(ctx, bench, release.variant, model)
+		if err != nil {
+			fmt.Printf("❌ 放行失败: %v\n", err)
+			os.Exit(1)
+		}
+		if !released {
+			fmt.Printf("未找到被隔离的分数: %s / %s\n", bench, model)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ 已放行: %s / %s\n", bench, model)
+		return
+	}
+
+	quarantined, err := bStore.GetQuarantined(ctx)
+	if err != nil {
+		slog.Error("list quarantine", "error", err)
+		os.Exit(1)
+	}
+	if len(quarantined) == 0 {
+		fmt.Println("暂无被隔离的分数。")
+		return
+	}
+	fmt.Printf("🚧 %d 个分数被隔离:\n\n", len(quarantined))
+	for _, q := range quarantined {
+		name := q.BenchmarkID
+		if q.Variant != "" {
+			name += " / " + q.Variant
+		}
+		fmt.Printf("  %-40s %-20s %8.1f  %s\n", name, q.ModelName, q.Score, q.Reason)
+		if q.SourceURL != "" {
+			fmt.Printf("  %-40s %s\n", "", q.SourceURL)
+		}
+	}
+}
+
+func cmdList() {
+	ctx := context.Background()
+	_, store := openDB()
+	defaultInterval := envDuration("WATCHBOT_CHECK_INTERVAL", 6*time.Hour)
+
+	competitors, err := store.ListCompetitorsByUser(ctx, 1) // Hardcode userID 1
+	if err != nil {
+		slog.Error("list failed", "error", err)
+		os.Exit(1)
+	}
+
+	if len(competitors) == 0 {
+		fmt.Println("暂无监控目标。使用 watchbot add <url> 添加。")
+		return
+	}
+
+	fmt.Printf("本地用户(ID=1) 的监控目标 (%d):\n\n", len(competitors))
+	for i, c := range competitors {
+		fmt.Printf("  %d. %s (%s)\n", i+1, c.Name, c.Domain)
+
+		pages, _ := store.GetPagesByCompetitor(ctx, c.ID)
+		for _, p := range pages {
+			checked := "未检查"
+			if p.LastCheckedAt != nil {
+				checked = p.LastCheckedAt.Local().Format("2006-01-02 15:04")
+			}
+			every := fmt.Sprintf("每 %s (默认)", defaultInterval)
+			if p.CheckInterval > 0 {
+				every = fmt.Sprintf("每 %s", p.CheckInterval)
+			}
+			if p.Browser {
+				every += ", 浏览器渲染"
+			}
+			if p.Selector != "" {
+				every += ", 选择器 " + p.Selector
+			}
+			fmt.Printf("     ✅ [%s] %s (%s, 最后检查: %s)\n", p.PageType, p.URL, every, checked)
+		}
+		fmt.Println()
+	}
+}
+
+func cmdSearch(query string, limit int) {
+	ctx := context.Background()
+	_, store := openDB()
+
+	results, err := store.Search(ctx, 1, query, limit) // Hardcode userID 1
+	if err != nil {
+		slog.Error("search failed", "error", err)
+		os.Exit(1)
+	}
+	if len(results) == 0 {
+		fmt.Printf("没有找到包含「%s」的快照或变化。\n", query)
+		return
+	}
+
+	fmt.Printf("「%s」的搜索结果 (%d, 按时间从早到晚):\n\n", query, len(results))
+	for i, r := range results {
+		kind := "快照"
+		if r.Kind == watchbot.SearchAnalysis {
+			kind = fmt.Sprintf("变化 #%d, %s", r.ID, r.Severity)
+		}
+		fmt.Printf("  %d. %s  %s [%s] %s (%s)\n", i+1, r.CreatedAt.Local().Format("2006-01-02 15:04"), r.CompetitorName, r.PageType, r.PageURL, kind)
+		fmt.Printf("     %s\n", strings.Join(strings.Fields(r.Snippet), " "))
+	}
+}
+
+// cmdCheck runs a full check. With previewPath set it instead renders the
+// digests of the changes seen within since to that file.
+func cmdCheck(previewPath string, since time.Duration) {
+	ctx := context.Background()
+	db, store := openDB()
+
+	// Use Pro tier for change analysis (higher quality)
+	llmClient, err := llm.NewTieredClient(llm.TierPro)
+	if err != nil {
+		slog.Warn("LLM client not available", "error", err)
+	}
+	if llmClient != nil {
+		defer llmClient.Close()
+	}
+
+	// Failed sends are queued for serve, which this one-off run is not
+	retry := retryOnQueue(jobs.New(db))
+	queued := 0
+	pipeline := newPipeline(store, llmClient, newDispatcher(store, func(ctx context.Context, dl notify.Delivery, err error) error {
+		if err := retry(ctx, dl, err); err != nil {
+			return err
+		}
+		queued++
+		return nil
+	}))
+
+	// Preview mode: render recent digests to disk, no fetching or sending
+	if previewPath != "" {
+		n, err := pipeline.Preview(ctx, previewPath, time.Now().Add(-since))
+		if err != nil {
+			slog.Error("preview failed", "error", err)
+			os.Exit(1)
+		}
+		if n == 0 {
+			fmt.Printf("ℹ️  最近 %s 内没有可预览的变化\n", since)
+			return
+		}
+		fmt.Printf("✅ 已生成 %d 份预览: %s\n", n, previewPath)
+		return
+	}
+	err = pipeline.RunCheck(ctx)
+	if queued > 0 {
+		fmt.Printf("⚠️  %d 条通知发送失败, 已加入任务队列, 由 watchbot serve 重试 (watchbot notifications 查看)\n", queued)
+	}
+	if err != nil {
+		slog.Error("check failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+// runCheck runs one check round for serve. When ctx is done the round
+// drains: the page under way finishes within WATCHBOT_DRAIN_TIMEOUT
+// (default 2m) and unsent digests are held for the next round.
+func runCheck(ctx context.Context, store *watchbot.Store, dispatcher *notify.Dispatcher) error {
+	llmClient, err := llm.NewTieredClient(llm.TierPro)
+	if err != nil {
+		slog.Warn("LLM client not available", "error", err)
+	}
+	if llmClient != nil {
+		defer llmClient.Close()
+	}
+
+	pipeline := newPipeline(store, llmClient, dispatcher)
+	pipeline.SetDrainTimeout(envDuration("WATCHBOT_DRAIN_TIMEOUT", 2*time.Minute))
+	pipeline.SetCheckInterval(envDuration("WATCHBOT_CHECK_INTERVAL", 6*time.Hour))
+	return pipeline.RunCheck(ctx)
+}
+
+// newDispatcher builds a dispatcher with every notification channel
+// configured from the environment. Failed sends go to retry, see
+// retryOnQueue.
+func newDispatcher(store *watchbot.Store, retry notify.RetryFunc) *notify.Dispatcher {
+	dispatcher := notify.NewDispatcher()
+	dispatcher.SetDeliveryLog(store)
+	dispatcher.SetRetry(retry)
+
+	// Setup email
+	emailCfg := loadEmailConfig()
+	if emailCfg.SMTPHost != "" {
+		dispatcher.SetEmailConfig(emailCfg)
+	}
+
+	// Setup Telegram (per-user chat IDs come from notification routes)
+	tgToken := os.Getenv("TELEGRAM_BOT_TOKEN")
+	if tgToken != "" {
+		dispatcher.Register(notify.NewTelegramNotifier(notify.TelegramConfig{
+			BotToken:  tgToken,
+			ChannelID: os.Getenv("TELEGRAM_CHANNEL_ID"),
+		}))
+		dispatcher.RegisterFactory(notify.ChannelTelegram, func(chatID string) notify.Notifier {
+			return notify.NewTelegramNotifier(notify.TelegramConfig{BotToken: tgToken, ChannelID: chatID})
+		})
+	}
+
+	// Setup SMS (critical alerts only, per-user phone numbers)
+	twilioCfg := notify.TwilioConfig{
+		AccountSID: os.Getenv("TWILIO_ACCOUNT_SID"),
+		AuthToken:  os.Getenv("TWILIO_AUTH_TOKEN"),
+		From:       os.Getenv("TWILIO_FROM"),
+	}
+	if twilioCfg.AccountSID != "" {
+		dispatcher.RegisterFactory(notify.ChannelSMS, func(phone string) notify.Notifier {
+			return notify.NewSMSNotifierForRecipient(twilioCfg, phone)
+		})
+	}
+
+	// Setup push (ntfy topics / Pushover user keys; per-user targets via routes)
+	ntfyCfg := notify.NtfyConfig{
+		Server: os.Getenv("NTFY_SERVER"),
+		Topic:  os.Getenv("NTFY_TOPIC"),
+		Token:  os.Getenv("NTFY_TOKEN"),
+	}
+	if ntfyCfg.Topic != "" {
+		dispatcher.Register(notify.NewNtfyNotifier(ntfyCfg))
+	}
+	dispatcher.RegisterFactory(notify.ChannelNtfy, func(topic string) notify.Notifier {
+		cfg := ntfyCfg
+		cfg.Topic = topic
+		return notify.NewNtfyNotifier(cfg)
+	})
+	if appToken := os.Getenv("PUSHOVER_APP_TOKEN"); appToken != "" {
+		if userKey := os.Getenv("PUSHOVER_USER_KEY"); userKey != "" {
+			dispatcher.Register(notify.NewPushoverNotifier(notify.PushoverConfig{AppToken: appToken, UserKey: userKey}))
+		}
+		dispatcher.RegisterFactory(notify.ChannelPushover, func(userKey string) notify.Notifier {
+			return notify.NewPushoverNotifier(notify.PushoverConfig{AppToken: appToken, UserKey: userKey})
+		})
+	}
+
+	// Setup Discord (a channel-wide webhook; routes may name their own)
+	if url := os.Getenv("DISCORD_WEBHOOK_URL"); url != "" {
+		dispatcher.Register(notify.NewDiscordNotifier(notify.DiscordConfig{WebhookURL: url}))
+	}
+	dispatcher.RegisterFactory(notify.ChannelDiscord, func(url string) notify.Notifier {
+		return notify.NewDiscordNotifier(notify.DiscordConfig{WebhookURL: url})
+	})
+
+	// Setup WeChat Work (a group robot for everyone; routes may name their own
+	// robot by webhook URL or key)
+	wecomType := os.Getenv("WECHATWORK_MSG_TYPE")
+	if url := os.Getenv("WECHATWORK_WEBHOOK_URL"); url != "" {
+		dispatcher.Register(notify.NewWeChatWorkNotifier(notify.WeChatWorkConfig{WebhookURL: url, MsgType: wecomType}))
+	}
+	dispatcher.RegisterFactory(notify.ChannelWeChatWork, func(url string) notify.Notifier {
+		return notify.NewWeChatWorkNotifier(notify.WeChatWorkConfig{WebhookURL: url, MsgType: wecomType})
+	})
+
+	// Webhooks need no global setup; each route carries its own URL
+	dispatcher.RegisterFactory(notify.ChannelWebhook, func(url string) notify.Notifier {
+		return notify.NewWebhookNotifier(notify.WebhookConfig{URL: url})
+	})
+
+	// Severity escalation: minor → email, important → +Telegram, critical → all channels
+	if os.Getenv("WATCHBOT_ESCALATION") == "true" {
+		dispatcher.SetEscalation(notify.DefaultEscalation)
+	}
+	return dispatcher
+}
+
+// newPipeline builds the check pipeline with every digest option configured
+// from the environment.
+func newPipeline(store *watchbot.Store, llmClient llm.Client, dispatcher *notify.Dispatcher) *watchbot.GlobalPipeline {
+	pipeline := watchbot.NewGlobalPipeline(store, newFetcher(), withUsage(llmClient), dispatcher)
+	pipeline.SetBrowserFetcher(newBrowserFetcher())
+	pipeline.SetExtractMode(scraper.ExtractMode(os.Getenv("WATCHBOT_EXTRACT_MODE")))
+	pipeline.SetStructureDiff(os.Getenv("WATCHBOT_STRUCTURE_DIFF") == "true")
+	pipeline.SetSemanticDiff(os.Getenv("WATCHBOT_SEMANTIC_DIFF") == "true")
+	pipeline.SetAttachDiffs(os.Getenv("WATCHBOT_ATTACH_DIFFS") == "true")
+	pipeline.SetInlineDiffs(os.Getenv("WATCHBOT_INLINE_DIFFS") == "true")
+	if os.Getenv("WATCHBOT_ATTACH_SCREENSHOTS") == "true" {
+		pipeline.SetScreenshots(newScreenshotter())
+	}
+	if os.Getenv("WATCHBOT_VISUAL_DIFF") == "true" {
+		var threshold float64
+		if s := os.Getenv("WATCHBOT_VISUAL_THRESHOLD"); s != "" {
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil || v < 0 || v > 1 {
+				slog.Error("invalid WATCHBOT_VISUAL_THRESHOLD, expected a share between 0 and 1", "value", s)
+				os.Exit(1)
+			}
+			threshold = v
+		}
+		pipeline.SetVisualDiff(newScreenshotter(), threshold)
+	}
+	if path := os.Getenv("WEBHOOK_TEMPLATE"); path != "" {
+		f, err := notify.LoadTemplateFormatter[notify.WatchDigestData](path)
+		if err != nil {
+			slog.Error("load webhook template failed", "error", err)
+			os.Exit(1)
+		}
+		pipeline.SetWebhookFormatter(f)
+	}
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		pipeline.SetUnsubscribe(os.Getenv("FRONTEND_URL"), []byte(secret))
+		if os.Getenv("WATCHBOT_TRACKING") == "true" {
+			pipeline.SetTracking(os.Getenv("FRONTEND_URL"), []byte(secret))
+		}
+	}
+	return pipeline
+}
+
+func cmdServe() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 2)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		slog.Info("shutdown signal received, draining (send again to exit now)")
+		cancel()
+		<-sigCh
+		slog.Warn("second shutdown signal, exiting without draining")
+		os.Exit(1)
+	}()
+
+	Serve(ctx)
+}
+
+// Serve runs the check rounds, benchmark scrapes, scheduled backups and
+// webhook deliveries as jobs on the persistent queue until ctx is done. It
+// returns once the running jobs have drained and the alias watcher has
+// stopped, so the database can be closed.
+func Serve(ctx context.Context) {
+	db, store := openDB()
+	queue := jobs.New(db)
+
+	dispatcher := newDispatcher(store, retryOnQueue(queue))
+	handleNotifyJobs(queue, dispatcher)
+
+	queue.Handle(jobCheck, jobs.HandlerOptions{Timeout: 3 * time.Hour, MaxAttempts: 3}, func(ctx context.Context, job jobs.Job) error {
+		return runCheck(ctx, store, dispatcher)
+	})
+	// Each run checks the pages whose interval has passed
+	checkSpec := getEnv("WATCHBOT_CHECK_SCHEDULE", "@every 5m")
+	schedule(ctx, queue, jobs.Recurring{Name: jobCheck, Kind: jobCheck, Spec: checkSpec, RunOnStart: true})
+	slog.Info("WatchBot serving", "schedule", checkSpec)
+	suite.ServeMetrics(ctx)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	// ---- Benchmark tracker ----
+	bStore, err := benchmarks.NewStore(db)
+	if err == nil {
+		configPath := getEnv("BENCHMARK_CONFIG", "config/benchmark_models.yaml")
+		cfg, _ := benchmarks.LoadConfig(configPath)
+		if cfg == nil {
+			cfg = &benchmarks.Config{Models: benchmarks.DefaultModels}
+		}
+
+		fetcher := newFetcher()
+		var bParsers []benchmarks.Parser
+		allModels := append(cfg.Models, benchmarks.FallbackModels...)
+		bParsers = append(bParsers, parsers.NewLLMStatsParser(fetcher, allModels))
+		bParsers = append(bParsers, parsers.NewOpenRouterParser(allModels))
+
+		// Seed data if empty
+		count, _ := bStore.ScoreCount(ctx)
+		if count == 0 {
+			seeds := benchmarks.SeedFromScreenshot()
+			seedScraper := benchmarks.NewScraper(bStore, benchmarks.NewManualParser(seeds))
+			n, _ := seedScraper.ScrapeAll(ctx)
+			slog.Info("benchmark seed loaded", "scores", n)
+		}
+
+		bInterval := 7 * 24 * time.Hour
+		if s := os.Getenv("BENCHMARK_INTERVAL"); s != "" {
+			d, err := time.ParseDuration(s)
+			if err != nil || d <= 0 {
+				slog.Error("invalid BENCHMARK_INTERVAL", "value", s)
+				os.Exit(1)
+			}
+			bInterval = d
+		}
+
+		bScraper := benchmarks.NewScraper(bStore, bParsers...)
+		tracker := benchmarks.NewTracker(bStore, bScraper, bInterval)
+		tracker.OnUpdate = func(report *benchmarks.BenchmarkReport) {
+			slog.Info("benchmark data updated",
+				"models", len(report.Models),
+				"date", report.Date,
+			)
+			// Render PNG for potential notification
+			pngPath := "/tmp/benchmark_report.png"
+			renderer := benchmarks.NewImageRenderer()
+			if err := renderer.RenderPNG(report, pngPath); err != nil {
+				slog.Error("benchmark render", "error", err)
+			} else {
+				slog.Info("benchmark PNG rendered", "path", pngPath)
+			}
+		}
+		tracker.OnChanges = func(report *benchmarks.BenchmarkReport, changes []benchmarks.ScoreChange) {
+			sendBenchmarkMovement(ctx, bStore, report, changes, "/tmp/benchmark_report.png")
+		}
+		tracker.OnReport = func(report *benchmarks.BenchmarkReport) {
+			sendBenchmarkReport(ctx, store, bStore, report)
+		}
+		tracker.OnCandidates = func(candidates []benchmarks.ModelCandidate) {
+			sendModelCandidates(ctx, candidates)
+		}
+
+		queue.Handle(jobBenchmark, jobs.HandlerOptions{Timeout: time.Hour, MaxAttempts: 3}, func(ctx context.Context, job jobs.Job) error {
+			return tracker.RunOnce(ctx, cfg.Models)
+		})
+		// Run now only if the last scrape is an interval old, so restarts
+		// keep the schedule
+		next, err := tracker.NextRun(ctx)
+		if err != nil {
+			slog.Warn("benchmark last run", "error", err)
+		}
+		schedule(ctx, queue, jobs.Recurring{
+			Name:       jobBenchmark,
+			Kind:       jobBenchmark,
+			Spec:       "@every " + bInterval.String(),
+			RunOnStart: !next.After(time.Now()),
+		})
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			benchmarks.WatchAliases(ctx, configPath, time.Minute)
+		}()
+		slog.Info("benchmark tracker scheduled", "interval", bInterval, "next", next.Format(time.RFC3339))
+	}
+
+	// ---- Scheduled backups ----
+	if interval, dir, keep := backupSchedule(); interval > 0 {
+		queue.Handle(jobBackup, jobs.HandlerOptions{Timeout: 30 * time.Minute}, func(ctx context.Context, job jobs.Job) error {
+			return runScheduledBackup(ctx, db, dir, keep)
+		})
+		schedule(ctx, queue, jobs.Recurring{Name: jobBackup, Kind: jobBackup, Spec: "@every " + interval.String()})
+		slog.Info("scheduled backups enabled", "interval", interval, "dir", dir, "keep", keep)
+	} else if err := queue.Unschedule(ctx, jobBackup); err != nil {
+		slog.Warn("unschedule backups", "error", err)
+	}
+
+	queue.Run(ctx)
+}
+
+// benchmarkOptions are the flags of the benchmark command.
+type benchmarkOptions struct {
+	history  bool
+	models   string // replaces the configured models
+	addModel string
+	scrape   string // seed, live or true (both)
+	mode     string // "best" compares each provider's latest models
+	output   string
+	file     string
+	email    string
+}
+
+func cmdBenchmark(opts benchmarkOptions) {
+	ctx := context.Background()
+	db, _ := openDB()
+
+	// Init benchmark store
+	bStore, err := benchmarks.NewStore(db)
+	if err != nil {
+		slog.Error("init benchmark store", "error", err)
+		os.Exit(1)
+	}
+
+	if opts.history {
+		printScrapeHistory(ctx, bStore)
+		return
+	}
+
+	// Load model config
+	configPath := getEnv("BENCHMARK_CONFIG", "config/benchmark_models.yaml")
+	cfg, err := benchmarks.LoadConfig(configPath)
+	if err != nil {
+		slog.Warn("load benchmark config", "error", err)
+		cfg = &benchmarks.Config{Models: benchmarks.DefaultModels}
+	}
+
+	// CLI model override
+	if opts.models != "" {
+		cfg.Models = benchmarks.ParseModelsCLI(opts.models)
+	}
+	if opts.addModel != "" {
+		cfg.Models = benchmarks.AddModel(cfg.Models, opts.addModel)
+	}
+
+	// Seed data on first run or scrape from live sources
+	scrapeMode := opts.scrape
+	count, _ := bStore.ScoreCount(ctx)
+	if count == 0 || scrapeMode != "" {
+		// Always load seed data if DB is empty
+		if count == 0 || scrapeMode == "seed" || scrapeMode == "true" {
+			fmt.Println("📊 Loading seed benchmark data...")
+			seeds := benchmarks.SeedFromScreenshot()
+			s := benchmarks.NewScraper(bStore, benchmarks.NewManualParser(seeds))
+			n, err := s.ScrapeAll(ctx)
+			if err != nil {
+				slog.Warn("seed", "error", err)
+			}
+			fmt.Printf("   ✅ %d seed scores loaded\n", n)
+		}
+
+		// Live scrape from real sources
+		if scrapeMode == "true" || scrapeMode == "live" {
+			fmt.Println("🌐 Scraping live benchmark data...")
+			fetcher := newFetcher()
+
+			var liveParsers []benchmarks.Parser
+			allModels := append(cfg.Models, benchmarks.FallbackModels...)
+			liveParsers = append(liveParsers, parsers.NewLLMStatsParser(fetcher, allModels))
+			liveParsers = append(liveParsers, parsers.NewLMArenaParser(fetcher, allModels))
+			liveParsers = append(liveParsers, parsers.NewHFLeaderboardParser(allModels))
+			liveParsers = append(liveParsers, parsers.NewOpenRouterParser(allModels))
+
+			// Add LLM extractor if LLM client is available
+			llmClient := newLLMClient()
+			if llmClient != nil {
+				liveParsers = append(liveParsers, parsers.NewLLMExtractor(llmClient, fetcher, cfg.Models))
+				defer llmClient.Close()
+			}
+
+			s := benchmarks.NewScraper(bStore, liveParsers...)
+			summary := s.Scrape(ctx)
+			for _, ps := range summary.Parsers {
+				if ps.Err != nil {
+					fmt.Printf("   ⚠️  %-36s %4d scores  %6v  %v\n", ps.Parser, ps.Scores, ps.Duration.Round(time.Millisecond), ps.Err)
+				} else {
+					fmt.Printf("   ✔  %-36s %4d scores  %6v\n", ps.Parser, ps.Scores, ps.Duration.Round(time.Millisecond))
+				}
+				if ps.Quarantined > 0 {
+					fmt.Printf("       %d scores quarantined (watchbot quarantine)\n", ps.Quarantined)
+				}
+			}
+			fmt.Printf("   ✅ %d live scores scraped in %v\n", summary.Total(), summary.Duration.Round(time.Millisecond))
+		}
+	}
+
+	// Build report
+	date := time.Now().Format("2006-01-02")
+	report, err := bStore.GetScoresForReport(ctx, cfg.Models, date)
+	if err != nil {
+		slog.Error("build report", "error", err)
+		os.Exit(1)
+	}
+
+	// Filter empty models (min 1 score, min 10 models)
+	report.FilterEmptyModels(3, 10)
+
+	// Best-of mode: one column per provider, compared with its previous generation
+	if opts.mode == "best" {
+		report = report.BestOfProviders()
+	}
+
+	fmt.Printf("📊 Benchmark Report: %d benchmarks × %d models\n\n", len(benchmarks.AllBenchmarks), len(report.Models))
+
+	// Output
+	output := opts.output
+	filePath := opts.file
+
+	switch output {
+	case "png":
+		if filePath == "" {
+			filePath = "benchmark_report.png"
+		}
+		renderer := benchmarks.NewImageRenderer()
+		if err := renderer.RenderPNG(report, filePath); err != nil {
+			slog.Error("render PNG", "error", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ PNG saved: %s\n", filePath)
+
+	case "html":
+		renderer := benchmarks.NewHTMLRenderer()
+		htmlContent := renderer.RenderPage(report)
+		if filePath == "" {
+			filePath = "benchmark_report.html"
+		}
+		if err := os.WriteFile(filePath, []byte(htmlContent), 0644); err != nil {
+			slog.Error("write HTML", "error", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ HTML saved: %s\n", filePath)
+
+	case "md", "markdown":
+		md := benchmarks.NewMarkdownRenderer().RenderMarkdown(report)
+		if filePath == "" {
+			filePath = "benchmark_report.md"
+		}
+		if err := os.WriteFile(filePath, []byte(md), 0644); err != nil {
+			slog.Error("write Markdown", "error", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Markdown saved: %s\n", filePath)
+
+	case "charts":
+		if filePath == "" {
+			filePath = "benchmark_charts"
+		}
+		paths, err := benchmarks.NewChartRenderer().RenderAll(report, filePath)
+		if err != nil {
+			slog.Error("render charts", "error", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ %d charts saved in %s\n", len(paths), filePath)
+
+	case "csv", "json":
+		if filePath == "" {
+			filePath = "benchmark_report." + output
+		}
+		stored, err := bStore.GetAllScores(ctx)
+		if err != nil {
+			slog.Error("load scores", "error", err)
+			os.Exit(1)
+		}
+		records := benchmarks.ExportRecords(report, stored)
+		f, err := os.Create(filePath)
+		if err != nil {
+			slog.Error("create export", "error", err)
+			os.Exit(1)
+		}
+		if output == "csv" {
+			err = benchmarks.WriteCSV(f, records)
+		} else {
+			err = benchmarks.WriteJSON(f, records)
+		}
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			slog.Error("write export", "format", output, "error", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ %d scores exported: %s\n", len(records), filePath)
+
+	default:
+		// Terminal table output
+		printTerminalTable(report)
+	}
+
+	// Send benchmark email if SMTP is configured and recipient specified
+	emailTo := opts.email
+	if emailTo == "" {
+		emailTo = os.Getenv("SMTP_TO")
+	}
+	emailCfg := notify.EmailConfig{
+		SMTPHost: os.Getenv("SMTP_HOST"),
+		SMTPPort: os.Getenv("SMTP_PORT"),
+		From:     os.Getenv("SMTP_FROM"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		To:       emailTo,
+	}
+	if emailTo != "" && emailCfg.Password != "" {
+		renderer := benchmarks.NewHTMLRenderer()
+		htmlTable := renderer.RenderHTML(report)
+		scoreCount, _ := bStore.ScoreCount(ctx)
+
+		data := notify.BenchmarkDigestData{
+			Report:     report,
+			HTMLTable:  htmlTable,
+			ScoreCount: scoreCount,
+			Date:       date,
+		}
+		if output == "png" {
+			data.PNGPath = filePath
+		}
+		formatter := notify.NewBenchmarkEmailFormatter()
+		msg := formatter.Format(data)
+
+		emailNotifier := notify.NewEmailNotifierForRecipient(emailCfg, emailTo)
+		if err := emailNotifier.Send(ctx, msg); err != nil {
+			slog.Error("benchmark email send failed", "email", emailTo, "error", err)
+		} else {
+			fmt.Printf("📧 Benchmark report emailed to %s\n", emailTo)
+		}
+	}
+}
+
+func printTerminalTable(report *benchmarks.BenchmarkReport) {
+	// Header
+	fmt.Printf("%-25s", "Benchmark")
+	for _, m := range report.Models {
+		name := m.Name
+		if len(name) > 16 {
+			name = name[:16]
+		}
+		fmt.Printf(" %16s", name)
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("─", 25+17*len(report.Models)))
+
+	for _, cat := range benchmarks.Categories {
+		var benches []benchmarks.BenchmarkDef
+		for _, b := range benchmarks.AllBenchmarks {
+			if b.Category == cat.ID {
+				benches = append(benches, b)
+			}
+		}
+		if len(benches) == 0 {
+			continue
+		}
+		fmt.Printf("%s %s\n", cat.Emoji, cat.Label)
+
+		for _, bench := range benches {
+			variants := bench.Variants
+			if len(variants) == 0 {
+				variants = []string{""}
+			}
+			for _, v := range variants {
+				label := bench.Name
+				if v != "" {
+					label = fmt.Sprintf("  %s", v)
+				}
+				if len(label) > 24 {
+					label = label[:24]
+				}
+				fmt.Printf("%-25s", label)
+				for _, m := range report.Models {
+					score, exists := report.GetScore(bench.ID, v, m.Name)
+					if !exists {
+						fmt.Printf(" %16s", "—")
+					} else {
+						scoreStr := fmt.Sprintf("%.1f%%", score)
+						if bench.Unit == "Elo" {
+							scoreStr = fmt.Sprintf("%d", int(score))
+						}
+						if report.IsHighest(bench.ID, v, m.Name) {
+							scoreStr = "🔴" + scoreStr
+						}
+						if delta, ok := report.Delta(bench.ID, v, m.Name); ok && delta != 0 {
+							scoreStr += fmt.Sprintf(" %+.1f", delta)
+						}
+						if delta, ok := report.GenDelta(bench.ID, v, m.Name); ok {
+							scoreStr += fmt.Sprintf(" (%+.1f)", delta)
+						}
+						fmt.Printf(" %16s", scoreStr)
+					}
+				}
+				fmt.Println()
+			}
+		}
+	}
+}
+
+// --- Helpers ---
+
+// printScrapeHistory lists recent scrapes per parser and when each parser
+// last succeeded.
+func printScrapeHistory(ctx context.Context, bStore *benchmarks.Store) {
+	history, err := bStore.GetScrapeHistory(ctx, 50)
+	if err != nil {
+		slog.Error("load scrape history", "error", err)
+		os.Exit(1)
+	}
+	if len(history) == 0 {
+		fmt.Println("暂无抓取记录。使用 watchbot benchmark --scrape=live 抓取后再查看。")
+		return
+	}
+	lastOK, err := bStore.LastSuccessfulScrapes(ctx)
+	if err != nil {
+		slog.Error("load scrape history", "error", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("🕒 数据新鲜度 (各解析器最近一次成功抓取):")
+	seen := make(map[string]bool)
+	for _, r := range history {
+		if seen[r.Parser] {
+			continue
+		}
+		seen[r.Parser] = true
+		if t, ok := lastOK[r.Parser]; ok {
+			fmt.Printf("   %-36s %s (%s 前)\n", r.Parser, t.Format("2006-01-02 15:04"), time.Since(t).Round(time.Minute))
+		} else {
+			fmt.Printf("   %-36s 从未成功\n", r.Parser)
+		}
+	}
+
+	fmt.Printf("\n📜 最近 %d 条抓取记录:\n", len(history))
+	for _, r := range history {
+		status := "✔ "
+		if r.Error != "" {
+			status = "⚠️"
+		}
+		fmt.Printf("   %s %s  %-36s +%-4d ~%-4d 隔离 %-3d %6v\n", status, r.StartedAt.Format("2006-01-02 15:04"),
+			r.Parser, r.Added, r.Updated, r.Quarantined, r.Duration.Round(time.Millisecond))
+		if r.Error != "" {
+			fmt.Printf("      %s\n", r.Error)
+		}
+	}
+}
+
+// sendBenchmarkMovement notifies SMTP_TO and TELEGRAM_CHANNEL_ID, when
+// configured, of scores that are new or changed since the previous scrape.
+func sendBenchmarkMovement(ctx context.Context, bStore *benchmarks.Store, report *benchmarks.BenchmarkReport, changes []benchmarks.ScoreChange, pngPath string) {
+	scoreCount, _ := bStore.ScoreCount(ctx)
+	newScores := 0
+	for _, c := range changes {
+		if c.New {
+			newScores++
+		}
+	}
+	data := notify.BenchmarkDigestData{
+		Report:     report,
+		HTMLTable:  benchmarks.NewHTMLRenderer().RenderHTML(report),
+		ScoreCount: scoreCount,
+		NewScores:  newScores,
+		Date:       report.Date,
+		Changes:    changes,
+	}
+	if _, err := os.Stat(pngPath); err == nil {
+		data.PNGPath = pngPath
+	}
+
+	emailCfg := loadEmailConfig()
+	emailCfg.To = os.Getenv("SMTP_TO")
+	if emailCfg.To != "" && emailCfg.Password != "" {
+		msg := notify.NewBenchmarkEmailFormatter().Format(data)
+		if err := notify.NewEmailNotifierForRecipient(emailCfg, emailCfg.To).Send(ctx, msg); err != nil {
+			slog.Error("benchmark movement email failed", "error", err)
+		}
+	}
+	if token, chatID := os.Getenv("TELEGRAM_BOT_TOKEN"), os.Getenv("TELEGRAM_CHANNEL_ID"); token != "" && chatID != "" {
+		msg := notify.NewBenchmarkTelegramFormatter().Format(data)
+		tg := notify.NewTelegramNotifier(notify.TelegramConfig{BotToken: token, ChannelID: chatID})
+		if err := tg.Send(ctx, msg); err != nil {
+			slog.Error("benchmark movement telegram failed", "error", err)
+		}
+	}
+	slog.Info("benchmark movement", "changes", len(changes), "new", newScores)
+}
+
+// sendModelCandidates notifies SMTP_TO and TELEGRAM_CHANNEL_ID, when
+// configured, of unmatched leaderboard models that look like new releases.
+func sendModelCandidates(ctx context.Context, candidates []benchmarks.ModelCandidate) {
+	emailCfg := loadEmailConfig()
+	emailCfg.To = os.Getenv("SMTP_TO")
+	if emailCfg.To != "" && emailCfg.Password != "" {
+		msg := notify.NewBenchmarkEmailFormatter().FormatCandidates(candidates)
+		if err := notify.NewEmailNotifierForRecipient(emailCfg, emailCfg.To).Send(ctx, msg); err != nil {
+			slog.Error("model candidates email failed", "error", err)
+		}
+	}
+	if token, chatID := os.Getenv("TELEGRAM_BOT_TOKEN"), os.Getenv("TELEGRAM_CHANNEL_ID"); token != "" && chatID != "" {
+		msg := notify.NewBenchmarkTelegramFormatter().FormatCandidates(candidates)
+		tg := notify.NewTelegramNotifier(notify.TelegramConfig{BotToken: token, ChannelID: chatID})
+		if err := tg.Send(ctx, msg); err != nil {
+			slog.Error("model candidates telegram failed", "error", err)
+		}
+	}
+	slog.Info("model candidates", "count", len(candidates))
+}
+
+// sendBenchmarkReport emails the scheduled benchmark report to every user who
+// opted in with `watchbot benchmark-updates`.
+func sendBenchmarkReport(ctx context.Context, store *watchbot.Store, bStore *benchmarks.Store, report *benchmarks.BenchmarkReport) {
+	subscribers, err := store.GetBenchmarkSubscribers(ctx)
+	if err != nil {
+		slog.Error("benchmark subscribers", "error", err)
+		return
+	}
+	emailCfg := loadEmailConfig()
+	if len(subscribers) == 0 || emailCfg.Password == "" {
+		return
+	}
+
+	scoreCount, _ := bStore.ScoreCount(ctx)
+	data := notify.BenchmarkDigestData{
+		Report:     report,
+		HTMLTable:  benchmarks.NewHTMLRenderer().RenderHTML(report),
+		ScoreCount: scoreCount,
+		Date:       report.Date,
+	}
+	pngPath := "/tmp/benchmark_report.png"
+	if err := benchmarks.NewImageRenderer().RenderPNG(report, pngPath); err != nil {
+		slog.Error("benchmark render", "error", err)
+	} else {
+		data.PNGPath = pngPath
+	}
+
+	secret := os.Getenv("JWT_SECRET")
+	sent := 0
+	for _, u := range subscribers {
+		msg := notify.NewBenchmarkEmailFormatter().Format(data)
+		msg.Bulk = true
+		if secret != "" {
+			token := notify.SignUnsubscribeToken([]byte(secret), u.ID, watchbot.BenchmarkUnsubscribeList)
+			msg.UnsubscribeURL = notify.UnsubscribeURL(os.Getenv("FRONTEND_URL"), token)
+		}
+		if err := notify.NewEmailNotifierForRecipient(emailCfg, u.DeliveryEmail()).Send(ctx, msg); err != nil {
+			slog.Error("benchmark report email failed", "email", u.Email, "error", err)
+			continue
+		}
+		sent++
+	}
+	slog.Info("benchmark report sent", "subscribers", len(subscribers), "sent", sent)
+}
+
+func loadEmailConfig() notify.EmailConfig {
+	return notify.EmailConfig{
+		SMTPHost:          getEnv("SMTP_HOST", ""),
+		SMTPPort:          getEnv("SMTP_PORT", "587"),
+		From:              os.Getenv("SMTP_FROM"),
+		Password:          os.Getenv("SMTP_PASSWORD"),
+		UnsubscribeMailto: os.Getenv("SMTP_UNSUBSCRIBE_MAILTO"),
+	}
+}
+
+// newFetcher returns the HTTP fetcher behind a per-host rate limiter and
+// circuit breaker (SCRAPER_HOST_INTERVAL, SCRAPER_BREAKER_COOLDOWN), wrapped in
+// a disk cache when SCRAPER_CACHE_DIR is set. SCRAPER_CACHE_TTL (e.g. "1h")
+// serves cached pages without revalidation; the default of 0 always
+// revalidates via ETag/Last-Modified.
+// cookieJar loads SCRAPER_COOKIE_FILE once, so every fetcher shares one
+// jar and none overwrites the cookies another saved. Nil when unset.
+var cookieJar = sync.OnceValue(func() *scraper.PersistentJar {
+	path := os.Getenv("SCRAPER_COOKIE_FILE")
+	if path == "" {
+		return nil
+	}
+	jar, err := scraper.NewPersistentJar(path)
+	if err != nil {
+		slog.Error("failed to load cookie jar", "error", err)
+		os.Exit(1)
+	}
+	return jar
+})
+
+func newFetcher() scraper.Fetcher {
+	httpFetcher := scraper.NewHTTPFetcher()
+	if jar := cookieJar(); jar != nil {
+		httpFetcher.SetCookieJar(jar)
+	}
+	if spec, ok := os.LookupEnv("SCRAPER_FALLBACKS"); ok {
+		readers, err := scraper.ParseFallbacks(spec, scraper.FallbackConfig{
+			JinaAPIKey:        os.Getenv("JINA_API_KEY"),
+			ScrapingBeeAPIKey: os.Getenv("SCRAPINGBEE_API_KEY"),
+			BrowserPath:       os.Getenv("SCRAPER_BROWSER_PATH"),
+		})
+		if err != nil {
+			slog.Error("invalid SCRAPER_FALLBACKS", "error", err)
+			os.Exit(1)
+		}
+		httpFetcher.SetFallbacks(readers...)
+	}
+	if path := os.Getenv("SCRAPER_AUTH_CONFIG"); path != "" {
+		auths, err := scraper.LoadAuthConfig(path)
+		if err != nil {
+			slog.Error("failed to load auth config", "error", err)
+			os.Exit(1)
+		}
+		for host, a := range auths {
+			httpFetcher.SetAuth(host, a)
+		}
+	}
+
+	perHost, err := strconv.Atoi(getEnv("SCRAPER_HOST_CONCURRENCY", "2"))
+	if err != nil || perHost < 0 {
+		slog.Warn("invalid SCRAPER_HOST_CONCURRENCY, using 2", "value", os.Getenv("SCRAPER_HOST_CONCURRENCY"))
+		perHost = 2
+	}
+	httpFetcher.SetPoliteness(scraper.Politeness{
+		IgnoreRobots: os.Getenv("SCRAPER_IGNORE_ROBOTS") == "true",
+		MaxPerHost:   perHost,
+		RobotsTTL:    envDuration("SCRAPER_ROBOTS_TTL", scraper.DefaultRobotsTTL),
+	})
+
+	var fetcher scraper.Fetcher = scraper.NewHostLimiter(httpFetcher,
+		envDuration("SCRAPER_HOST_INTERVAL", time.Second), 5,
+		envDuration("SCRAPER_BREAKER_COOLDOWN", 10*time.Minute))
+
+	if dir := os.Getenv("SCRAPER_CACHE_DIR"); dir != "" {
+		fetcher = scraper.NewCachingFetcher(fetcher, dir, envDuration("SCRAPER_CACHE_TTL", 0))
+	}
+	return fetcher
+}
+
+// newBrowserFetcher renders the pages opted into browser rendering, in the
+// browser at SCRAPER_BROWSER_ENDPOINT or else a local one.
+func newBrowserFetcher() scraper.Fetcher {
+	browser := &scraper.BrowserFetcher{
+		Endpoint: os.Getenv("SCRAPER_BROWSER_ENDPOINT"),
+		Path:     os.Getenv("SCRAPER_BROWSER_PATH"),
+	}
+	if vp := os.Getenv("SCRAPER_BROWSER_VIEWPORT"); vp != "" {
+		if _, err := fmt.Sscanf(vp, "%dx%d", &browser.ViewportWidth, &browser.ViewportHeight); err != nil {
+			slog.Error("invalid SCRAPER_BROWSER_VIEWPORT, expected WIDTHxHEIGHT", "value", vp)
+			os.Exit(1)
+		}
+	}
+	if jar := cookieJar(); jar != nil {
+		browser.Jar = jar
+	}
+	return scraper.NewHostLimiter(browser,
+		envDuration("SCRAPER_HOST_INTERVAL", time.Second), 5,
+		envDuration("SCRAPER_BREAKER_COOLDOWN", 10*time.Minute))
+}
+
+// newScreenshotter returns the screenshot capture: the render service in
+// SCRAPER_SCREENSHOT_API when set, else the local browser.
+func newScreenshotter() func(ctx context.Context, url string) ([]byte, error) {
+	if api := os.Getenv("SCRAPER_SCREENSHOT_API"); api != "" {
+		if !strings.Contains(api, "{url}") {
+			slog.Error("invalid SCRAPER_SCREENSHOT_API, expected {url} where the page URL goes")
+			os.Exit(1)
+		}
+		return (&scraper.ScreenshotAPI{URL: api}).Screenshot
+	}
+	return (&scraper.BrowserReader{Path: os.Getenv("SCRAPER_BROWSER_PATH")}).Screenshot
+}
+
+// envDuration parses a duration env var, falling back on absence or error.
+func envDuration(key string, fallback time.Duration) time.Duration {
+	s := os.Getenv(key)
+	if s == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		slog.Warn("invalid duration, using default", "key", key, "value", s, "default", fallback)
+		return fallback
+	}
+	return d
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func promptInput(prompt string) string {
+	fmt.Print(prompt)
+	scanner := bufio.NewScanner(os.Stdin)
+	if scanner.Scan() {
+		return strings.TrimSpace(scanner.Text())
+	}
+	return ""
+}
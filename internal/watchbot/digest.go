@@ -1,14 +1,17 @@
 package watchbot
 
 import (
+	"fmt"
+	"regexp"
 	"strings"
 
+	"github.com/RobinCoderZhao/devkit-suite/pkg/i18n"
 	"github.com/RobinCoderZhao/devkit-suite/pkg/notify"
 )
 
 // ComposeDigest creates a single aggregated notification for a user.
 // Changes are grouped by competitor for better readability.
-func ComposeDigest(changes []Change, user UserWithCompetitors, formatter *notify.WatchEmailFormatter) notify.Message {
+func ComposeDigest(changes []Change, user UserWithCompetitors, formatter notify.WatchFormatter) notify.Message {
 	if len(changes) == 0 {
 		return notify.Message{}
 	}
@@ -24,6 +27,7 @@ func ComposeDigest(changes []Change, user UserWithCompetitors, formatter *notify
 			Analysis:       c.Analysis,
 			Additions:      c.Additions,
 			Deletions:      c.Deletions,
+			DiffUnified:    c.DiffUnified,
 		}
 	}
 
@@ -50,6 +54,68 @@ func ComposeDigest(changes []Change, user UserWithCompetitors, formatter *notify
 		Unchanged:   unchanged,
 		Date:        strings.Split(changes[0].CreatedAt.String(), " ")[0],
 	}
+	if user.Language != "" {
+		data.Labels = watchLabels(i18n.Language(user.Language))
+	}
 
 	return formatter.Format(data)
 }
+
+// applyEmailFormat switches a digest to plain text for users who opted out of
+// HTML email, e.g. because their mail gateway strips it.
+func applyEmailFormat(msg *notify.Message, user UserWithCompetitors) {
+	if user.EmailFormat == "plain" {
+		msg.HTMLBody = ""
+		msg.Format = "plain"
+	}
+}
+
+// watchLabels converts the shared i18n labels to the formatter label model.
+func watchLabels(lang i18n.Language) notify.WatchLabels {
+	labels := i18n.GetWatchLabels(lang)
+	return notify.WatchLabels{
+		DigestTitle:        labels.DigestTitle,
+		CompetitorsChanged: labels.CompetitorsChanged,
+		SubjectChanged:     labels.SubjectChanged,
+		PagesChanged:       labels.PagesChanged,
+		DiffLines:          labels.DiffLines,
+		ViewDiff:           labels.ViewDiff,
+		ViewPage:           labels.ViewPage,
+		Unchanged:          labels.Unchanged,
+		Tagline:            labels.Tagline,
+		Critical:           labels.Critical,
+		Important:          labels.Important,
+		Minor:              labels.Minor,
+		ListSeparator:      labels.ListSeparator,
+		Plural: func(key string, n int) string {
+			return i18n.Plural(lang, pluralKeys[key], n)
+		},
+	}
+}
+
+// pluralKeys maps the formatter's count labels to their catalog keys.
+var pluralKeys = map[string]string{
+	"CompetitorsChanged": "watch.competitors_changed",
+	"SubjectChanged":     "watch.subject_changed",
+	"PagesChanged":       "watch.pages_changed",
+}
+
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// DiffAttachments packages each change's unified diff as a .diff file.
+func DiffAttachments(changes []Change) []notify.Attachment {
+	var result []notify.Attachment
+	for _, c := range changes {
+		if c.DiffUnified == "" {
+			continue
+		}
+		name := fmt.Sprintf("%s-%s-%d.diff", c.CompetitorName, c.PageType, c.ID)
+		name = unsafeFilenameChars.ReplaceAllString(name, "_")
+		result = append(result, notify.Attachment{
+			Filename:    name,
+			ContentType: "text/x-diff; charset=UTF-8",
+			Data:        []byte(c.DiffUnified),
+		})
+	}
+	return result
+}
@@ -0,0 +1,174 @@
+package watchbot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
+)
+
+// QuietHours is a daily window, in the user's local time, during which
+// non-critical notifications are held. The window may wrap past midnight.
+type QuietHours struct {
+	Start time.Duration // offset from local midnight
+	End   time.Duration
+}
+
+// ParseQuietHours parses a "22:00-07:00" style window.
+func ParseQuietHours(s string) (QuietHours, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours %q: expected HH:MM-HH:MM", s)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours %q: %w", s, err)
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours %q: %w", s, err)
+	}
+	if start == end {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours %q: empty window", s)
+	}
+	return QuietHours{Start: start, End: end}, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Until reports whether now falls inside the quiet window and, if so, when the
+// window ends (the start of the next delivery window). Times are wall-clock
+// times in now's location, so a window ending at 07:00 ends at 07:00 on the
+// days clocks change too.
+func (q QuietHours) Until(now time.Time) (bool, time.Time) {
+	offset := time.Duration(now.Hour())*time.Hour + time.Duration(now.Minute())*time.Minute +
+		time.Duration(now.Second())*time.Second + time.Duration(now.Nanosecond())
+	// end returns the window's end, days after now's date
+	end := func(days int) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day()+days,
+			int(q.End/time.Hour), int(q.End%time.Hour/time.Minute), 0, 0, now.Location())
+	}
+
+	if q.Start < q.End {
+		if offset >= q.Start && offset < q.End {
+			return true, end(0)
+		}
+		return false, time.Time{}
+	}
+
+	// Window wraps midnight, e.g. 22:00-07:00
+	switch {
+	case offset >= q.Start:
+		return true, end(1)
+	case offset < q.End:
+		return true, end(0)
+	}
+	return false, time.Time{}
+}
+
+// quietUntil reports whether a user is in their quiet hours at now and when
+// delivery may resume. Invalid settings disable quiet hours.
+func (gp *GlobalPipeline) quietUntil(u UserWithCompetitors, now time.Time) (bool, time.Time) {
+	if u.QuietHours == "" {
+		return false, time.Time{}
+	}
+	q, err := ParseQuietHours(u.QuietHours)
+	if err != nil {
+		gp.logger.Warn("ignoring quiet hours", "user", u.Email, "error", err)
+		return false, time.Time{}
+	}
+	loc := time.Local
+	if u.Timezone != "" {
+		if l, err := time.LoadLocation(u.Timezone); err == nil {
+			loc = l
+		} else {
+			gp.logger.Warn("unknown time zone, using server time", "user", u.Email, "timezone", u.Timezone)
+		}
+	}
+	return q.Until(now.In(loc))
+}
+
+// --- Store: quiet-hours settings and held changes ---
+
+// SetUserTimezone sets the IANA time zone used to interpret a user's quiet hours.
+func (s *Store) SetUserTimezone(ctx context.Context, userID int, tz string) error {
+	if _, err := time.LoadLocation(tz); err != nil {
+		return fmt.Errorf("unknown time zone: %s", tz)
+	}
+	_, err := s.db.ExecContext(ctx, `UPDATE users SET timezone = ? WHERE id = ?`, tz, userID)
+	return err
+}
+
+// SetUserQuietHours sets a user's quiet hours ("22:00-07:00"); "" disables them.
+func (s *Store) SetUserQuietHours(ctx context.Context, userID int, window string) error {
+	if window != "" {
+		if _, err := ParseQuietHours(window); err != nil {
+			return err
+		}
+	}
+	return s.SetUserSetting(ctx, userID, "quiet_hours", window)
+}
+
+// HoldChanges defers delivery of changes to a user until releaseAt, along
+// with the digest ID each change was already sent under, if any. Changes
+// already held are rescheduled.
+func (s *Store) HoldChanges(ctx context.Context, userID int, changes []Change, releaseAt time.Time) error {
+	release := releaseAt.UTC().Format("2006-01-02 15:04:05")
+	return s.db.Transaction(ctx, func(tx *storage.Tx) error {
+		for _, c := range changes {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO held_changes (user_id, analysis_id, release_at, digest_id) VALUES (?, ?, ?, ?)
+				 ON CONFLICT(user_id, analysis_id) DO UPDATE SET release_at = excluded.release_at, digest_id = excluded.digest_id`,
+				userID, c.ID, release, c.DigestID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetDueHeldChanges returns a user's held changes whose release time has passed.
+func (s *Store) GetDueHeldChanges(ctx context.Context, userID int, now time.Time) ([]Change, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT a.id, a.page_id, a.old_snapshot_id, a.new_snapshot_id, a.severity, a.summary, a.raw_diff, a.created_at,
+		        p.url, p.page_type, c.id, c.name, c.user_id, h.digest_id
+		 FROM held_changes h
+		 JOIN analyses a ON a.id = h.analysis_id
+		 JOIN pages p ON a.page_id = p.id
+		 JOIN competitors c ON p.competitor_id = c.id
+		 WHERE h.user_id = ? AND h.release_at <= ?
+		 ORDER BY a.created_at`, userID, now.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanDigestChanges(rows)
+}
+
+// CountDueHeldChanges returns how many held changes are ready for delivery.
+func (s *Store) CountDueHeldChanges(ctx context.Context, now time.Time) (int, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM held_changes WHERE release_at <= ?`,
+		now.UTC().Format("2006-01-02 15:04:05")).Scan(&n)
+	return n, err
+}
+
+// ReleaseHeldChanges removes delivered changes from a user's hold queue.
+func (s *Store) ReleaseHeldChanges(ctx context.Context, userID int, changes []Change) error {
+	for _, c := range changes {
+		if _, err := s.db.ExecContext(ctx,
+			`DELETE FROM held_changes WHERE user_id = ? AND analysis_id = ?`, userID, c.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
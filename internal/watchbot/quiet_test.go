@@ -0,0 +1,137 @@
+package watchbot
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestParseQuietHours(t *testing.T) {
+	tests := []struct {
+		in         string
+		start, end time.Duration
+		wantErr    bool
+	}{
+		{in: "22:00-07:00", start: 22 * time.Hour, end: 7 * time.Hour},
+		{in: " 09:30 - 17:45 ", start: 9*time.Hour + 30*time.Minute, end: 17*time.Hour + 45*time.Minute},
+		{in: "00:00-23:59", start: 0, end: 23*time.Hour + 59*time.Minute},
+		{in: "23:59-00:00", start: 23*time.Hour + 59*time.Minute, end: 0},
+		{in: "", wantErr: true},
+		{in: "22:00", wantErr: true},
+		{in: "22:00-", wantErr: true},
+		{in: "-07:00", wantErr: true},
+		{in: "22-07", wantErr: true},
+		{in: "24:00-07:00", wantErr: true},
+		{in: "22:60-07:00", wantErr: true},
+		{in: "10pm-7am", wantErr: true},
+		{in: "22:00-07:00-08:00", wantErr: true},
+		{in: "08:00-08:00", wantErr: true}, // empty window
+	}
+	for _, tt := range tests {
+		q, err := ParseQuietHours(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseQuietHours(%q) = %+v, want an error", tt.in, q)
+			}
+			continue
+		}
+		if err != nil || q.Start != tt.start || q.End != tt.end {
+			t.Errorf("ParseQuietHours(%q) = %+v, %v; want %v-%v", tt.in, q, err, tt.start, tt.end)
+		}
+	}
+}
+
+func TestQuietHoursUntil(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone data:", err)
+	}
+	at := func(loc *time.Location, month time.Month, day, hour, min int) time.Time {
+		return time.Date(2026, month, day, hour, min, 0, 0, loc)
+	}
+	utc := time.UTC
+
+	tests := []struct {
+		name   string
+		window string
+		now    time.Time
+		quiet  bool
+		until  time.Time
+	}{
+		{"wrap, evening", "22:00-07:00", at(utc, 3, 14, 23, 30), true, at(utc, 3, 15, 7, 0)},
+		{"wrap, at the start", "22:00-07:00", at(utc, 3, 14, 22, 0), true, at(utc, 3, 15, 7, 0)},
+		{"wrap, last minute before the start", "22:00-07:00", at(utc, 3, 14, 21, 59), false, time.Time{}},
+		{"wrap, after midnight", "22:00-07:00", at(utc, 3, 15, 0, 0), true, at(utc, 3, 15, 7, 0)},
+		{"wrap, last minute", "22:00-07:00", at(utc, 3, 15, 6, 59), true, at(utc, 3, 15, 7, 0)},
+		{"wrap, at the end", "22:00-07:00", at(utc, 3, 15, 7, 0), false, time.Time{}},
+		{"wrap, midday", "22:00-07:00", at(utc, 3, 15, 12, 0), false, time.Time{}},
+		{"wrap, across the month", "22:00-07:00", at(utc, 3, 31, 22, 30), true, at(utc, 4, 1, 7, 0)},
+		{"same day, inside", "09:00-17:30", at(utc, 3, 14, 12, 0), true, at(utc, 3, 14, 17, 30)},
+		{"same day, at the start", "09:00-17:30", at(utc, 3, 14, 9, 0), true, at(utc, 3, 14, 17, 30)},
+		{"same day, before", "09:00-17:30", at(utc, 3, 14, 8, 59), false, time.Time{}},
+		{"same day, at the end", "09:00-17:30", at(utc, 3, 14, 17, 30), false, time.Time{}},
+		// US clocks go forward on 8 March 2026 and back on 1 November
+		{"wrap into spring forward", "22:00-07:00", at(ny, 3, 7, 23, 0), true, at(ny, 3, 8, 7, 0)},
+		{"wrap into fall back", "22:00-07:00", at(ny, 10, 31, 23, 0), true, at(ny, 11, 1, 7, 0)},
+		{"same day, after spring forward", "01:00-09:00", at(ny, 3, 8, 8, 30), true, at(ny, 3, 8, 9, 0)},
+		{"same day, over after spring forward", "01:00-09:00", at(ny, 3, 8, 9, 30), false, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := ParseQuietHours(tt.window)
+			if err != nil {
+				t.Fatal(err)
+			}
+			quiet, until := q.Until(tt.now)
+			if quiet != tt.quiet || !until.Equal(tt.until) {
+				t.Errorf("Until(%s) = %v, %s; want %v, %s", tt.now, quiet, until, tt.quiet, tt.until)
+			}
+			if quiet && until.Location() != tt.now.Location() {
+				t.Errorf("until in %s, want %s", until.Location(), tt.now.Location())
+			}
+		})
+	}
+}
+
+func TestQuietUntilHeldRelease(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	gp := &GlobalPipeline{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	u, pageID := testPage(t, s, "alice@example.com")
+	u.QuietHours, u.Timezone = "22:00-07:00", "Asia/Shanghai"
+
+	// 15:30 UTC is 23:30 in Shanghai, so changes wait until 07:00 there
+	now := time.Date(2026, 3, 14, 15, 30, 0, 0, time.UTC)
+	quiet, until := gp.quietUntil(u, now)
+	if want := time.Date(2026, 3, 14, 23, 0, 0, 0, time.UTC); !quiet || !until.Equal(want) {
+		t.Fatalf("quietUntil = %v, %s; want release at %s", quiet, until, want)
+	}
+
+	change := testChange(t, s, pageID, "pricing")
+	if err := s.HoldChanges(ctx, u.ID, []Change{change}, until); err != nil {
+		t.Fatal(err)
+	}
+	if due, err := s.GetDueHeldChanges(ctx, u.ID, until.Add(-time.Minute)); err != nil || len(due) != 0 {
+		t.Errorf("due before release: %d, %v", len(due), err)
+	}
+	due, err := s.GetDueHeldChanges(ctx, u.ID, until)
+	if err != nil || len(due) != 1 || due[0].ID != change.ID {
+		t.Fatalf("due at release = %+v, %v", due, err)
+	}
+	if n, _ := s.CountDueHeldChanges(ctx, until); n != 1 {
+		t.Errorf("CountDueHeldChanges = %d, want 1", n)
+	}
+	if err := s.ReleaseHeldChanges(ctx, u.ID, due); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := s.CountDueHeldChanges(ctx, until); n != 0 {
+		t.Errorf("%d changes still held after release", n)
+	}
+
+	// Invalid hours turn quiet hours off rather than holding changes
+	if quiet, _ := gp.quietUntil(UserWithCompetitors{Email: "b@example.com", QuietHours: "late"}, now); quiet {
+		t.Error("quiet with invalid hours")
+	}
+}
@@ -4,41 +4,37 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log/slog"
-	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/RobinCoderZhao/devkit-suite/pkg/i18n"
 	"github.com/RobinCoderZhao/devkit-suite/pkg/llm"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/scraper"
 )
 
 // Resolver resolves natural language input to monitoring URLs.
-// Three-layer fallback: LLM recall → Google Custom Search → Bing Web Search.
+// Layered fallback: LLM recall, then each configured search provider in turn.
 type Resolver struct {
-	llmClient    llm.Client
-	googleAPIKey string
-	googleCX     string // Custom Search Engine ID
-	bingAPIKey   string
-	logger       *slog.Logger
+	llmClient llm.Client
+	providers []SearchProvider
+	logger    *slog.Logger
 }
 
-// ResolverConfig holds search API credentials.
+// ResolverConfig holds the search providers to fall back on.
 type ResolverConfig struct {
-	GoogleAPIKey string
-	GoogleCX     string
-	BingAPIKey   string
+	// Providers are tried in order; see ParseSearchProviders.
+	Providers []SearchProvider
 }
 
 // NewResolver creates a new URL resolver.
 func NewResolver(llmClient llm.Client, cfg ResolverConfig) *Resolver {
 	return &Resolver{
-		llmClient:    llmClient,
-		googleAPIKey: cfg.GoogleAPIKey,
-		googleCX:     cfg.GoogleCX,
-		bingAPIKey:   cfg.BingAPIKey,
-		logger:       slog.Default(),
+		llmClient: llmClient,
+		providers: cfg.Providers,
+		logger:    slog.Default(),
 	}
 }
 
@@ -48,7 +44,7 @@ type ResolveResult struct {
 	URLs       []string `json:"urls"`
 	PageType   string   `json:"page_type"`
 	Confidence string   `json:"confidence"` // "high" or "low"
-	Source     string   // "llm", "google", "bing"
+	Source     string   // "llm" or the search provider's name
 	Error      string   `json:"error,omitempty"`
 }
 
@@ -61,7 +57,7 @@ type TargetSuggestion struct {
 	Reasoning  string `json:"reasoning"`
 }
 
-// SearchResult holds Bing Web Search raw results.
+// SearchResult is one web search hit.
 type SearchResult struct {
 	URL     string `json:"url"`
 	Name    string `json:"name"`
@@ -96,40 +92,16 @@ func (r *Resolver) Resolve(ctx context.Context, input string) (*ResolveResult, e
 		return result, nil
 	}
 
-	// Layer 2: Google Custom Search
-	if r.googleAPIKey != "" && r.googleCX != "" {
-		r.logger.Info("resolving via Google", "product", productName)
-		searchURL, err := r.searchGoogle(ctx, productName)
-		if err == nil && searchURL != "" {
-			return &ResolveResult{
-				Name:       productName,
-				URLs:       []string{searchURL},
-				PageType:   GuessPageType(searchURL),
-				Confidence: "high",
-				Source:     "google",
-			}, nil
-		}
-		if err != nil {
-			r.logger.Warn("Google search failed", "error", err)
-		}
-	}
-
-	// Layer 3: Bing Web Search
-	if r.bingAPIKey != "" {
-		r.logger.Info("resolving via Bing", "product", productName)
-		searchURL, err := r.searchBing(ctx, productName)
-		if err == nil && searchURL != "" {
-			return &ResolveResult{
-				Name:       productName,
-				URLs:       []string{searchURL},
-				PageType:   GuessPageType(searchURL),
-				Confidence: "high",
-				Source:     "bing",
-			}, nil
-		}
-		if err != nil {
-			r.logger.Warn("Bing search failed", "error", err)
-		}
+	// Following layers: search providers
+	query := fmt.Sprintf("%s official documentation site", productName)
+	if results, source := r.search(ctx, query, 3); len(results) > 0 {
+		return &ResolveResult{
+			Name:       productName,
+			URLs:       []string{results[0].URL},
+			PageType:   GuessPageType(results[0].URL),
+			Confidence: "high",
+			Source:     source,
+		}, nil
 	}
 
 	// All layers failed
@@ -140,7 +112,7 @@ func (r *Resolver) Resolve(ctx context.Context, input string) (*ResolveResult, e
 	}
 
 	return &ResolveResult{
-		Error: "无法识别监控目标",
+		Error: i18n.T(i18n.LangZH, "watch.error.unrecognized_target"),
 	}, nil
 }
 
@@ -194,136 +166,24 @@ func (r *Resolver) resolveLLM(ctx context.Context, input string) (*ResolveResult
 	return &result, nil
 }
 
-// searchGoogle uses Google Custom Search API to find a URL.
-func (r *Resolver) searchGoogle(ctx context.Context, productName string) (string, error) {
-	query := fmt.Sprintf("%s official documentation site", productName)
-	apiURL := fmt.Sprintf("https://www.googleapis.com/customsearch/v1?q=%s&key=%s&cx=%s&num=3",
-		url.QueryEscape(query), r.googleAPIKey, r.googleCX)
-
-	return r.fetchSearchResult(ctx, apiURL, "google")
-}
-
-// searchBing uses Bing Web Search API to find a URL.
-func (r *Resolver) searchBing(ctx context.Context, productName string) (string, error) {
-	query := fmt.Sprintf("%s official documentation site", productName)
-	apiURL := fmt.Sprintf("https://api.bing.microsoft.com/v7.0/search?q=%s&count=3",
-		url.QueryEscape(query))
-
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("Ocp-Apim-Subscription-Key", r.bingAPIKey)
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("bing API returned %d", resp.StatusCode)
-	}
-
-	body, _ := io.ReadAll(resp.Body)
-	var bingResp struct {
-		WebPages struct {
-			Value []struct {
-				URL string `json:"url"`
-			} `json:"value"`
-		} `json:"webPages"`
-	}
-	if err := json.Unmarshal(body, &bingResp); err != nil {
-		return "", err
-	}
-	if len(bingResp.WebPages.Value) > 0 {
-		return bingResp.WebPages.Value[0].URL, nil
-	}
-	return "", nil
-}
-
-// fetchSearchResult performs a GET request and extracts the first URL from Google API response.
-func (r *Resolver) fetchSearchResult(ctx context.Context, apiURL, source string) (string, error) {
-	client := &http.Client{Timeout: 10 * time.Second}
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-	if err != nil {
-		return "", err
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("%s API returned %d", source, resp.StatusCode)
-	}
-
-	body, _ := io.ReadAll(resp.Body)
-	var googleResp struct {
-		Items []struct {
-			Link string `json:"link"`
-		} `json:"items"`
-	}
-	if err := json.Unmarshal(body, &googleResp); err != nil {
-		return "", err
-	}
-	if len(googleResp.Items) > 0 {
-		return googleResp.Items[0].Link, nil
-	}
-	return "", nil
-}
-
-// searchBingAdvanced is a robust helper for the discovery agent generating structured results.
-func (r *Resolver) searchBingAdvanced(ctx context.Context, query string, count int) ([]SearchResult, error) {
-	apiURL := fmt.Sprintf("https://api.bing.microsoft.com/v7.0/search?q=%s&count=%d",
-		url.QueryEscape(query), count)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Ocp-Apim-Subscription-Key", r.bingAPIKey)
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("bing API returned %d", resp.StatusCode)
-	}
-
-	body, _ := io.ReadAll(resp.Body)
-	var bingResp struct {
-		WebPages struct {
-			Value []struct {
-				URL     string `json:"url"`
-				Name    string `json:"name"`
-				Snippet string `json:"snippet"`
-			} `json:"value"`
-		} `json:"webPages"`
-	}
-	if err := json.Unmarshal(body, &bingResp); err != nil {
-		return nil, err
-	}
-
-	var results []SearchResult
-	for _, v := range bingResp.WebPages.Value {
-		results = append(results, SearchResult{
-			URL:     v.URL,
-			Name:    v.Name,
-			Snippet: v.Snippet,
-		})
+// search runs query on each provider in turn and returns the first
+// non-empty results with the name of the provider that found them.
+func (r *Resolver) search(ctx context.Context, query string, count int) ([]SearchResult, string) {
+	for _, p := range r.providers {
+		r.logger.Info("searching", "provider", p.Name(), "query", query)
+		results, err := p.Search(ctx, query, count)
+		if err != nil {
+			r.logger.Warn("search failed", "provider", p.Name(), "error", err)
+			continue
+		}
+		if len(results) > 0 {
+			return results, p.Name()
+		}
 	}
-	return results, nil
+	return nil, ""
 }
 
-// DiscoverDomainTargets accepts a raw domain name, concurrently spins out multiple precise Bing queries,
+// DiscoverDomainTargets accepts a raw domain name, concurrently spins out multiple precise site: queries,
 // aggregates results, and evaluates commercial value tightly against an LLM.
 func (r *Resolver) DiscoverDomainTargets(ctx context.Context, domain string) ([]TargetSuggestion, error) {
 	// Sanitize domain
@@ -334,9 +194,14 @@ func (r *Resolver) DiscoverDomainTargets(ctx context.Context, domain string) ([]
 		domain = domain[:idx]
 	}
 
-	if r.bingAPIKey == "" {
-		r.logger.Warn("Bing API key not found, falling back to pure LLM URL guessing for discovery", "domain", domain)
-		return r.fallbackLLMDiscovery(ctx, domain)
+	if len(r.providers) == 0 {
+		candidates := crawlCandidates(ctx, domain)
+		if len(candidates) == 0 {
+			r.logger.Warn("no search provider configured and crawl found nothing, falling back to pure LLM URL guessing for discovery", "domain", domain)
+			return r.fallbackLLMDiscovery(ctx, domain)
+		}
+		r.logger.Info("no search provider configured, discovering via site crawl", "domain", domain, "candidates", len(candidates))
+		return r.rankCandidates(ctx, domain, candidates)
 	}
 
 	queries := []string{
@@ -345,17 +210,11 @@ func (r *Resolver) DiscoverDomainTargets(ctx context.Context, domain string) ([]
 		fmt.Sprintf("site:%s API参考 OR 开发者文档 OR API reference", domain),
 	}
 
-	type searchRes struct {
-		Results []SearchResult
-		Err     error
-	}
-
-	resChan := make(chan searchRes, len(queries))
+	resChan := make(chan []SearchResult, len(queries))
 	for _, q := range queries {
 		go func(query string) {
-			r.logger.Info("discovery agent searching bing", "query", query)
-			res, err := r.searchBingAdvanced(ctx, query, 4)
-			resChan <- searchRes{Results: res, Err: err}
+			res, _ := r.search(ctx, query, 4)
+			resChan <- res
 		}(q)
 	}
 
@@ -363,12 +222,7 @@ func (r *Resolver) DiscoverDomainTargets(ctx context.Context, domain string) ([]
 	seenURLs := make(map[string]bool)
 
 	for i := 0; i < len(queries); i++ {
-		sr := <-resChan
-		if sr.Err != nil {
-			r.logger.Warn("bing search error in discovery", "error", sr.Err)
-			continue
-		}
-		for _, res := range sr.Results {
+		for _, res := range <-resChan {
 			cleanURL := strings.Split(res.URL, "?")[0]
 			cleanURL = strings.Split(cleanURL, "#")[0]
 
@@ -382,7 +236,34 @@ func (r *Resolver) DiscoverDomainTargets(ctx context.Context, domain string) ([]
 	if len(allResults) == 0 {
 		return nil, fmt.Errorf("no results found for domain %s", domain)
 	}
+	return r.rankCandidates(ctx, domain, allResults)
+}
+
+// discoveryPaths matches URLs worth monitoring when crawling a site for candidates.
+var discoveryPaths = regexp.MustCompile(`(?i)pric|plans|changelog|release|whats-new|updates|api|docs|developer`)
+
+// crawlCandidates crawls the domain's homepage and sitemap for pricing,
+// changelog and docs pages, for discovery without a search API.
+func crawlCandidates(ctx context.Context, domain string) []SearchResult {
+	crawler := &scraper.Crawler{MaxPages: 20, Delay: 200 * time.Millisecond}
+	pages, err := crawler.Crawl(ctx, "https://"+domain+"/", 2, func(u *url.URL) bool {
+		return discoveryPaths.MatchString(u.Path)
+	})
+	if err != nil {
+		return nil
+	}
+	var results []SearchResult
+	for _, p := range pages {
+		if p.Depth == 0 {
+			continue
+		}
+		results = append(results, SearchResult{URL: p.URL, Name: p.Title})
+	}
+	return results
+}
 
+// rankCandidates asks the LLM to pick the candidates worth monitoring.
+func (r *Resolver) rankCandidates(ctx context.Context, domain string, allResults []SearchResult) ([]TargetSuggestion, error) {
 	// Limit to top 15 candidates to save LLM tokens and keep context manageable
 	if len(allResults) > 15 {
 		allResults = allResults[:15]
@@ -0,0 +1,204 @@
+package watchbot
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
+)
+
+// Kinds of search result.
+const (
+	SearchSnapshot = "snapshot" // page content as fetched
+	SearchAnalysis = "analysis" // a change's LLM analysis and diff
+)
+
+// SearchHit is a snapshot or change analysis that matches a search, on a
+// page of one of the user's or their teammates' competitors.
+type SearchHit struct {
+	Kind           string    `json:"kind"`
+	ID             int       `json:"id"` // snapshot or change ID
+	PageID         int       `json:"page_id"`
+	PageURL        string    `json:"page_url"`
+	PageType       string    `json:"page_type"`
+	CompetitorID   int       `json:"competitor_id"`
+	CompetitorName string    `json:"competitor_name"`
+	Severity       string    `json:"severity,omitempty"` // analyses only
+	Snippet        string    `json:"snippet"`            // matched terms marked with **
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// MaxSearchResults caps the results of one search.
+const MaxSearchResults = 200
+
+// searchJoins scopes a search to the competitors the user can see; it
+// follows a FROM that names the snapshot or analysis x.
+const searchJoins = `
+JOIN pages p ON x.page_id = p.id
+JOIN competitors c ON p.competitor_id = c.id
+WHERE ` + teamScope
+
+// analysisText is the text of an analysis that is searched, the expression
+// the Postgres index of migration 0018 is built on.
+const analysisText = `(COALESCE(x.summary, '') || ' ' || COALESCE(x.raw_diff, ''))`
+
+// likeEscaper escapes a term for a LIKE pattern with ESCAPE '\'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Search finds the snapshots and change analyses on the pages of the user
+// and their teammates that contain every word of query, oldest first, so
+// the first result shows when a competitor first mentioned it. Words match
+// anywhere in the text, case-insensitively, so text without spaces such as
+// Chinese can be searched. limit is capped at MaxSearchResults.
+func (s *Store) Search(ctx context.Context, userID int, query string, limit int) ([]SearchHit, error) {
+	if limit <= 0 || limit > MaxSearchResults {
+		limit = MaxSearchResults
+	}
+	terms := strings.Fields(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	stmt, args := s.searchQuery(userID, terms, limit)
+	rows, err := s.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []SearchHit
+	for rows.Next() {
+		var r SearchHit
+		var text sql.NullString
+		if err := rows.Scan(&r.Kind, &r.ID, &r.PageID, &r.PageURL, &r.PageType, &r.CompetitorID, &r.CompetitorName,
+			&r.Severity, &text, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		r.Snippet = snippet(text.String, terms)
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+// searchQuery builds the statement finding the snapshots and analyses that
+// contain every term. SQLite looks up terms of three or more characters in
+// the trigram FTS5 tables of migration 0018 and matches shorter ones, which
+// trigrams cannot find, with LIKE; Postgres matches every term with ILIKE,
+// which the pg_trgm indexes of migration 0018 serve.
+func (s *Store) searchQuery(userID int, terms []string, limit int) (string, []any) {
+	postgres := s.db.DriverType() == storage.Postgres
+	var phrases, patterns []string
+	for _, t := range terms {
+		if !postgres && utf8.RuneCountInString(t) >= 3 {
+			phrases = append(phrases, ftsPhrase(t))
+		} else {
+			patterns = append(patterns, "%"+likeEscaper.Replace(t)+"%")
+		}
+	}
+	like := "LIKE"
+	if postgres {
+		like = "ILIKE"
+	}
+
+	var args []any
+	where := func(fts, text string) string {
+		args = append(args, userID, userID)
+		var b strings.Builder
+		if len(phrases) > 0 {
+			b.WriteString(" AND " + fts + " MATCH ?")
+			args = append(args, strings.Join(phrases, " "))
+		}
+		for _, p := range patterns {
+			b.WriteString(" AND " + text + " " + like + ` ? ESCAPE '\'`)
+			args = append(args, p)
+		}
+		return b.String()
+	}
+
+	snapshots, analyses := "FROM snapshots x", "FROM analyses x"
+	if !postgres {
+		snapshots = "FROM snapshots_fts JOIN snapshots x ON x.id = snapshots_fts.rowid"
+		analyses = "FROM analyses_fts JOIN analyses x ON x.id = analyses_fts.rowid"
+	}
+	stmt := `
+SELECT 'snapshot', x.id, p.id, p.url, p.page_type, c.id, c.name, '', x.content, x.captured_at
+` + snapshots + searchJoins + where("snapshots_fts", "x.content") + `
+UNION ALL
+SELECT 'analysis', x.id, p.id, p.url, p.page_type, c.id, c.name, COALESCE(x.severity, ''), ` + analysisText + `, x.created_at
+` + analyses + searchJoins + where("analyses_fts", analysisText) + `
+ORDER BY 10, 1, 2
+LIMIT ?`
+	return stmt, append(args, limit)
+}
+
+// ftsPhrase quotes a term as an FTS5 phrase so that characters such as $, -
+// or " in prices and product names are not read as query syntax.
+func ftsPhrase(term string) string {
+	return `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+}
+
+// snippetContext is how many characters a snippet keeps before the first
+// match; it keeps three times as many after it.
+const snippetContext = 30
+
+// snippet returns the part of text around the first of terms it contains,
+// whitespace collapsed, with every occurrence of a term marked **.
+func snippet(text string, terms []string) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	var needles [][]rune
+	for _, t := range terms {
+		needle := []rune(t)
+		for i, r := range needle {
+			needle[i] = unicode.ToLower(r)
+		}
+		needles = append(needles, needle)
+	}
+	// Prefer the longest term matching at a position
+	sort.Slice(needles, func(i, j int) bool { return len(needles[i]) > len(needles[j]) })
+	matchAt := func(i int) int {
+		for _, n := range needles {
+			if i+len(n) <= len(lower) && string(lower[i:i+len(n)]) == string(n) {
+				return len(n)
+			}
+		}
+		return 0
+	}
+
+	first := 0
+	for i := range lower {
+		if matchAt(i) > 0 {
+			first = i
+			break
+		}
+	}
+	start := max(0, first-snippetContext)
+	end := min(len(runes), first+4*snippetContext)
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	for i := start; i < end; {
+		if n := matchAt(i); n > 0 {
+			b.WriteString("**" + string(runes[i:i+n]) + "**")
+			i += n
+			end = max(end, i)
+			continue
+		}
+		b.WriteRune(runes[i])
+		i++
+	}
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
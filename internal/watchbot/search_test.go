@@ -0,0 +1,89 @@
+package watchbot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	u, pageID := testPage(t, s, "search@example.com")
+	_, otherPage := testPage(t, s, "other@example.com")
+
+	save := func(pageID int, content string) int {
+		t.Helper()
+		id, err := s.SaveSnapshot(ctx, pageID, "", content, "", content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	pricing := save(pageID, "企业版价格上调至每月 $49/mo，新增审计日志。")
+	audit := save(pageID, "Audit Logs are now generally available for 100% of Team plans.")
+	save(otherPage, "竞品价格不变，Audit Logs 仍在测试。")
+	change, err := s.SaveChange(ctx, pageID, pricing, audit, "important", "定价页：企业版价格上调", "+ 企业版 ¥349/月", 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query string
+		want  []string // kind:id of the hits; saved in the same second, analyses sort first
+	}{
+		{query: "价格", want: []string{"analysis:" + fmt.Sprint(change), "snapshot:" + fmt.Sprint(pricing)}},
+		{query: "企业版", want: []string{"analysis:" + fmt.Sprint(change), "snapshot:" + fmt.Sprint(pricing)}},
+		{query: "审计日志", want: []string{"snapshot:" + fmt.Sprint(pricing)}},
+		{query: "价格 ¥349", want: []string{"analysis:" + fmt.Sprint(change)}},
+		{query: "audit logs", want: []string{"snapshot:" + fmt.Sprint(audit)}},
+		{query: "$49", want: []string{"snapshot:" + fmt.Sprint(pricing)}},
+		{query: "100%", want: []string{"snapshot:" + fmt.Sprint(audit)}},
+		{query: "10%"},
+		{query: `"Team`},
+		{query: "价格 audit"},
+		{query: "  "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			hits, err := s.Search(ctx, u.ID, tt.query, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, h := range hits {
+				got = append(got, fmt.Sprintf("%s:%d", h.Kind, h.ID))
+				if h.CompetitorName != "Acme" || h.PageID != pageID {
+					t.Errorf("hit %+v is not on the user's page", h)
+				}
+				if !strings.Contains(h.Snippet, "**") {
+					t.Errorf("snippet %q marks no match", h.Snippet)
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("hits = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	long := strings.Repeat("前文", 40) + "价格上调" + strings.Repeat("后文", 80)
+	tests := []struct {
+		text  string
+		terms []string
+		want  string
+	}{
+		{"企业版价格上调至每月 $49", []string{"价格"}, "企业版**价格**上调至每月 $49"},
+		{"Audit logs\n\nand audit   trails", []string{"AUDIT", "logs"}, "**Audit** **logs** and **audit** trails"},
+		{"$49/mo or $490/yr", []string{"$49", "$49/mo"}, "**$49/mo** or **$49**0/yr"},
+		{long, []string{"价格"}, "…" + strings.Repeat("前文", 15) + "**价格**上调" + strings.Repeat("后文", 58) + "…"},
+		{"no match here", []string{"价格"}, "no match here"},
+	}
+	for _, tt := range tests {
+		if got := snippet(tt.text, tt.terms); got != tt.want {
+			t.Errorf("snippet(%.20q, %q) = %q, want %q", tt.text, tt.terms, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,384 @@
+package watchbot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// SearchProvider is a web search API the Resolver falls back on when LLM
+// recall fails, and that domain discovery runs site: queries against.
+type SearchProvider interface {
+	// Name identifies the provider in config and in ResolveResult.Source.
+	Name() string
+	// Search returns up to count results for query, best first.
+	Search(ctx context.Context, query string, count int) ([]SearchResult, error)
+}
+
+// SearchConfig holds credentials for ParseSearchProviders.
+type SearchConfig struct {
+	GoogleAPIKey string
+	GoogleCX     string // Custom Search Engine ID
+	BingAPIKey   string
+	BraveAPIKey  string
+	SerpAPIKey   string
+}
+
+// ParseSearchProviders builds the providers to try, in order, from a
+// comma-separated spec such as "brave,serpapi,duckduckgo". Naming a provider
+// without its credentials is an error. An empty spec uses every provider
+// whose credentials are set, in the order google, bing, brave, serpapi;
+// "none" disables search.
+func ParseSearchProviders(spec string, cfg SearchConfig) ([]SearchProvider, error) {
+	if strings.TrimSpace(spec) == "" {
+		var providers []SearchProvider
+		if cfg.GoogleAPIKey != "" && cfg.GoogleCX != "" {
+			providers = append(providers, &GoogleSearch{APIKey: cfg.GoogleAPIKey, CX: cfg.GoogleCX})
+		}
+		if cfg.BingAPIKey != "" {
+			providers = append(providers, &BingSearch{APIKey: cfg.BingAPIKey})
+		}
+		if cfg.BraveAPIKey != "" {
+			providers = append(providers, &BraveSearch{APIKey: cfg.BraveAPIKey})
+		}
+		if cfg.SerpAPIKey != "" {
+			providers = append(providers, &SerpAPISearch{APIKey: cfg.SerpAPIKey})
+		}
+		return providers, nil
+	}
+
+	var providers []SearchProvider
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		switch name {
+		case "", "none":
+			continue
+		case "google":
+			if cfg.GoogleAPIKey == "" || cfg.GoogleCX == "" {
+				return nil, fmt.Errorf("search provider google: missing API key or engine ID")
+			}
+			providers = append(providers, &GoogleSearch{APIKey: cfg.GoogleAPIKey, CX: cfg.GoogleCX})
+		case "bing":
+			if cfg.BingAPIKey == "" {
+				return nil, fmt.Errorf("search provider bing: missing API key")
+			}
+			providers = append(providers, &BingSearch{APIKey: cfg.BingAPIKey})
+		case "brave":
+			if cfg.BraveAPIKey == "" {
+				return nil, fmt.Errorf("search provider brave: missing API key")
+			}
+			providers = append(providers, &BraveSearch{APIKey: cfg.BraveAPIKey})
+		case "serpapi":
+			if cfg.SerpAPIKey == "" {
+				return nil, fmt.Errorf("search provider serpapi: missing API key")
+			}
+			providers = append(providers, &SerpAPISearch{APIKey: cfg.SerpAPIKey})
+		case "duckduckgo":
+			providers = append(providers, &DuckDuckGoSearch{})
+		default:
+			return nil, fmt.Errorf("unknown search provider %q", name)
+		}
+	}
+	return providers, nil
+}
+
+var searchClient = &http.Client{Timeout: 10 * time.Second}
+
+// getSearchJSON sends req and decodes a 200 response into v.
+func getSearchJSON(req *http.Request, provider string, v any) error {
+	resp, err := searchClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s API returned %d: %s", provider, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 5<<20)).Decode(v)
+}
+
+// GoogleSearch queries the Google Custom Search JSON API.
+type GoogleSearch struct {
+	APIKey string
+	CX     string // Custom Search Engine ID
+}
+
+func (g *GoogleSearch) Name() string { return "google" }
+
+func (g *GoogleSearch) Search(ctx context.Context, query string, count int) ([]SearchResult, error) {
+	params := url.Values{
+		"q":   {query},
+		"key": {g.APIKey},
+		"cx":  {g.CX},
+		"num": {strconv.Itoa(min(count, 10))}, // the API's maximum
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://www.googleapis.com/customsearch/v1?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var googleResp struct {
+		Items []struct {
+			Link    string `json:"link"`
+			Title   string `json:"title"`
+			Snippet string `json:"snippet"`
+		} `json:"items"`
+	}
+	if err := getSearchJSON(req, "google", &googleResp); err != nil {
+		return nil, err
+	}
+	var results []SearchResult
+	for _, v := range googleResp.Items {
+		results = append(results, SearchResult{URL: v.Link, Name: v.Title, Snippet: v.Snippet})
+	}
+	return results, nil
+}
+
+// BingSearch queries the Bing Web Search API.
+type BingSearch struct {
+	APIKey string
+}
+
+func (b *BingSearch) Name() string { return "bing" }
+
+func (b *BingSearch) Search(ctx context.Context, query string, count int) ([]SearchResult, error) {
+	apiURL := fmt.Sprintf("https://api.bing.microsoft.com/v7.0/search?q=%s&count=%d", url.QueryEscape(query), count)
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Ocp-Apim-Subscription-Key", b.APIKey)
+
+	var bingResp struct {
+		WebPages struct {
+			Value []struct {
+				URL     string `json:"url"`
+				Name    string `json:"name"`
+				Snippet string `json:"snippet"`
+			} `json:"value"`
+		} `json:"webPages"`
+	}
+	if err := getSearchJSON(req, "bing", &bingResp); err != nil {
+		return nil, err
+	}
+	var results []SearchResult
+	for _, v := range bingResp.WebPages.Value {
+		results = append(results, SearchResult{URL: v.URL, Name: v.Name, Snippet: v.Snippet})
+	}
+	return results, nil
+}
+
+// BraveSearch queries the Brave Search web search API.
+type BraveSearch struct {
+	APIKey string
+}
+
+func (b *BraveSearch) Name() string { return "brave" }
+
+func (b *BraveSearch) Search(ctx context.Context, query string, count int) ([]SearchResult, error) {
+	apiURL := fmt.Sprintf("https://api.search.brave.com/res/v1/web/search?q=%s&count=%d",
+		url.QueryEscape(query), min(count, 20)) // the API's maximum
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Subscription-Token", b.APIKey)
+
+	var braveResp struct {
+		Web struct {
+			Results []struct {
+				URL         string `json:"url"`
+				Title       string `json:"title"`
+				Description string `json:"description"`
+			} `json:"results"`
+		} `json:"web"`
+	}
+	if err := getSearchJSON(req, "brave", &braveResp); err != nil {
+		return nil, err
+	}
+	var results []SearchResult
+	for _, v := range braveResp.Web.Results {
+		// Descriptions highlight query terms with <strong>
+		results = append(results, SearchResult{URL: v.URL, Name: v.Title, Snippet: stripTags(v.Description)})
+	}
+	return results, nil
+}
+
+// SerpAPISearch queries Google results through SerpAPI.
+type SerpAPISearch struct {
+	APIKey string
+}
+
+func (s *SerpAPISearch) Name() string { return "serpapi" }
+
+func (s *SerpAPISearch) Search(ctx context.Context, query string, count int) ([]SearchResult, error) {
+	params := url.Values{
+		"engine":  {"google"},
+		"q":       {query},
+		"num":     {strconv.Itoa(count)},
+		"api_key": {s.APIKey},
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://serpapi.com/search.json?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var serpResp struct {
+		Error          string `json:"error"`
+		OrganicResults []struct {
+			Link    string `json:"link"`
+			Title   string `json:"title"`
+			Snippet string `json:"snippet"`
+		} `json:"organic_results"`
+	}
+	if err := getSearchJSON(req, "serpapi", &serpResp); err != nil {
+		return nil, err
+	}
+	// A search with no results comes back as 200 with this error
+	if serpResp.Error != "" && !strings.Contains(serpResp.Error, "hasn't returned any results") {
+		return nil, fmt.Errorf("serpapi: %s", serpResp.Error)
+	}
+	var results []SearchResult
+	for _, v := range serpResp.OrganicResults[:min(len(serpResp.OrganicResults), count)] {
+		results = append(results, SearchResult{URL: v.Link, Name: v.Title, Snippet: v.Snippet})
+	}
+	return results, nil
+}
+
+// DuckDuckGoSearch reads DuckDuckGo's HTML results page. It needs no API key,
+// but is rate limited and may change without notice, so it is best listed
+// after a keyed provider.
+type DuckDuckGoSearch struct{}
+
+func (d *DuckDuckGoSearch) Name() string { return "duckduckgo" }
+
+func (d *DuckDuckGoSearch) Search(ctx context.Context, query string, count int) ([]SearchResult, error) {
+	form := url.Values{"q": {query}}
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://html.duckduckgo.com/html/", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; WatchBot/1.0)")
+
+	resp, err := searchClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("duckduckgo returned %d", resp.StatusCode)
+	}
+	doc, err := html.Parse(io.LimitReader(resp.Body, 5<<20))
+	if err != nil {
+		return nil, fmt.Errorf("parse duckduckgo results: %w", err)
+	}
+	return parseDuckDuckGo(doc, count), nil
+}
+
+// parseDuckDuckGo extracts results from the HTML results page: each result
+// is a "result__a" link, followed by a "result__snippet" element.
+func parseDuckDuckGo(doc *html.Node, count int) []SearchResult {
+	var results []SearchResult
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if len(results) > count {
+			return
+		}
+		if n.Type == html.ElementNode {
+			switch class := htmlAttr(n, "class"); {
+			case hasClass(class, "result__a"):
+				if u := duckDuckGoTarget(htmlAttr(n, "href")); u != "" {
+					results = append(results, SearchResult{URL: u, Name: nodeText(n)})
+				}
+				return
+			case hasClass(class, "result__snippet"):
+				if len(results) > 0 && results[len(results)-1].Snippet == "" {
+					results[len(results)-1].Snippet = nodeText(n)
+				}
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return results[:min(len(results), count)]
+}
+
+// duckDuckGoTarget returns the result URL behind a link, which DuckDuckGo
+// wraps in a //duckduckgo.com/l/?uddg=<url> redirect. Ads are skipped.
+func duckDuckGoTarget(href string) string {
+	u, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	if target := u.Query().Get("uddg"); target != "" {
+		return target
+	}
+	if u.Scheme == "http" || u.Scheme == "https" {
+		if strings.HasSuffix(u.Host, "duckduckgo.com") {
+			return "" // ad click-through
+		}
+		return u.String()
+	}
+	return ""
+}
+
+func htmlAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasClass(class, name string) bool {
+	for _, c := range strings.Fields(class) {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeText returns the whitespace-collapsed text under n.
+func nodeText(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// stripTags returns the text of an HTML fragment.
+func stripTags(s string) string {
+	if !strings.Contains(s, "<") {
+		return s
+	}
+	doc, err := html.Parse(strings.NewReader(s))
+	if err != nil {
+		return s
+	}
+	return nodeText(doc)
+}
@@ -0,0 +1,229 @@
+package watchbot
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestParseSearchProviders(t *testing.T) {
+	full := SearchConfig{GoogleAPIKey: "g", GoogleCX: "cx", BingAPIKey: "b", BraveAPIKey: "br", SerpAPIKey: "s"}
+	tests := []struct {
+		spec    string
+		cfg     SearchConfig
+		want    []string
+		wantErr bool
+	}{
+		{spec: "", cfg: full, want: []string{"google", "bing", "brave", "serpapi"}},
+		{spec: "", cfg: SearchConfig{BraveAPIKey: "br", GoogleAPIKey: "g"}, want: []string{"brave"}}, // google needs the engine ID too
+		{spec: "  ", cfg: SearchConfig{}},
+		{spec: "none", cfg: full},
+		{spec: "serpapi, duckduckgo,brave", cfg: full, want: []string{"serpapi", "duckduckgo", "brave"}},
+		{spec: "duckduckgo", cfg: SearchConfig{}, want: []string{"duckduckgo"}},
+		{spec: "brave,,none", cfg: full, want: []string{"brave"}},
+		{spec: "google", cfg: SearchConfig{GoogleAPIKey: "g"}, wantErr: true},
+		{spec: "bing", cfg: SearchConfig{}, wantErr: true},
+		{spec: "brave", cfg: SearchConfig{}, wantErr: true},
+		{spec: "duckduckgo,serpapi", cfg: SearchConfig{}, wantErr: true},
+		{spec: "yahoo", cfg: full, wantErr: true},
+		{spec: "Brave", cfg: full, wantErr: true},
+	}
+	for _, tt := range tests {
+		providers, err := ParseSearchProviders(tt.spec, tt.cfg)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseSearchProviders(%q) succeeded, want an error", tt.spec)
+			}
+			continue
+		}
+		var got []string
+		for _, p := range providers {
+			got = append(got, p.Name())
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSearchProviders(%q) = %v, %v; want %v", tt.spec, got, err, tt.want)
+		}
+	}
+}
+
+func TestDuckDuckGoTarget(t *testing.T) {
+	tests := []struct{ href, want string }{
+		{"//duckduckgo.com/l/?uddg=https%3A%2F%2Fwww.notion.com%2Fpricing&rut=6e3f", "https://www.notion.com/pricing"},
+		{"https://duckduckgo.com/l/?uddg=https%3A%2F%2Fexample.com%2Fa%3Fb%3Dc", "https://example.com/a?b=c"},
+		{"https://www.notion.com/pricing", "https://www.notion.com/pricing"},
+		{"https://duckduckgo.com/y.js?ad_domain=clickup.com&ad_type=txad", ""},
+		{"/html/?q=notion", ""},
+		{"javascript:void(0)", ""},
+		{"%zz", ""},
+	}
+	for _, tt := range tests {
+		if got := duckDuckGoTarget(tt.href); got != tt.want {
+			t.Errorf("duckDuckGoTarget(%q) = %q, want %q", tt.href, got, tt.want)
+		}
+	}
+}
+
+// wantSearchResults are the results saved in the testdata search fixtures.
+var wantSearchResults = []SearchResult{
+	{
+		URL:     "https://www.notion.com/pricing",
+		Name:    "Pricing Plans for Every Team | Notion",
+		Snippet: "Notion pricing: Free for individuals, Plus from $10 per seat/month.",
+	},
+	{
+		URL:     "https://en.wikipedia.org/wiki/Notion_(productivity_software)",
+		Name:    "Notion (productivity software) - Wikipedia",
+		Snippet: "Notion is a productivity and note-taking web application.",
+	},
+}
+
+func TestParseDuckDuckGo(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "duckduckgo.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	doc, err := html.Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The ad is skipped and each snippet goes with the link before it
+	got := parseDuckDuckGo(doc, 10)
+	want := []SearchResult{
+		{
+			URL:     "https://www.notion.com/pricing",
+			Name:    "Pricing Plans for Every Team | Notion",
+			Snippet: "Notion pricing: Free for individuals, Plus from $10 per seat/month, Business and Enterprise plans.",
+		},
+		{
+			URL:     "https://www.notion.com/help/upgrade-or-downgrade-your-plan?ref=ddg",
+			Name:    "Upgrade or downgrade your plan – Notion Help Center",
+			Snippet: "Change your Notion plan at any time from Settings.",
+		},
+		{
+			URL:     "https://en.wikipedia.org/wiki/Notion_(productivity_software)",
+			Name:    "Notion (productivity software) - Wikipedia",
+			Snippet: "Notion is a productivity and note-taking web application developed by Notion Labs Inc.",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDuckDuckGo =\n%+v\nwant\n%+v", got, want)
+	}
+	if got := parseDuckDuckGo(doc, 2); !reflect.DeepEqual(got, want[:2]) {
+		t.Errorf("parseDuckDuckGo(count 2) = %+v", got)
+	}
+}
+
+// fixtureTransport answers search API requests with the testdata file
+// mapped to the request host and records the requests it served.
+type fixtureTransport struct {
+	files    map[string]string
+	requests []*http.Request
+}
+
+func (ft *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ft.requests = append(ft.requests, req)
+	name, ok := ft.files[req.URL.Host]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: http.NoBody, Request: req}, nil
+	}
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: f, Request: req}, nil
+}
+
+func TestSearchProviders(t *testing.T) {
+	ft := &fixtureTransport{files: map[string]string{
+		"www.googleapis.com":     "google.json",
+		"api.bing.microsoft.com": "bing.json",
+		"api.search.brave.com":   "brave.json",
+		"serpapi.com":            "serpapi.json",
+		"html.duckduckgo.com":    "duckduckgo.html",
+	}}
+	saved := searchClient
+	searchClient = &http.Client{Transport: ft}
+	t.Cleanup(func() { searchClient = saved })
+
+	tests := []struct {
+		provider SearchProvider
+		// check reports what is wrong with the request the provider sent
+		check func(*http.Request) string
+	}{
+		{&GoogleSearch{APIKey: "gkey", CX: "engine"}, func(r *http.Request) string {
+			if q := r.URL.Query(); q.Get("key") != "gkey" || q.Get("cx") != "engine" || q.Get("num") != "2" {
+				return "query " + r.URL.RawQuery
+			}
+			return ""
+		}},
+		{&BingSearch{APIKey: "bkey"}, func(r *http.Request) string {
+			if r.Header.Get("Ocp-Apim-Subscription-Key") != "bkey" || r.URL.Query().Get("count") != "2" {
+				return "key or count missing"
+			}
+			return ""
+		}},
+		{&BraveSearch{APIKey: "brkey"}, func(r *http.Request) string {
+			if r.Header.Get("X-Subscription-Token") != "brkey" || r.URL.Query().Get("q") != "notion pricing" {
+				return "token or query missing"
+			}
+			return ""
+		}},
+		{&SerpAPISearch{APIKey: "skey"}, func(r *http.Request) string {
+			if q := r.URL.Query(); q.Get("api_key") != "skey" || q.Get("engine") != "google" {
+				return "query " + r.URL.RawQuery
+			}
+			return ""
+		}},
+		{&DuckDuckGoSearch{}, func(r *http.Request) string {
+			if r.Method != "POST" || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+				return r.Method + " " + r.Header.Get("Content-Type")
+			}
+			return ""
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider.Name(), func(t *testing.T) {
+			ft.requests = nil
+			got, err := tt.provider.Search(context.Background(), "notion pricing", 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ft.requests) != 1 {
+				t.Fatalf("%d requests, want 1", len(ft.requests))
+			}
+			if problem := tt.check(ft.requests[0]); problem != "" {
+				t.Errorf("request %s: %s", ft.requests[0].URL, problem)
+			}
+			want := wantSearchResults
+			if tt.provider.Name() == "duckduckgo" {
+				// The HTML page ranks a help page second, see TestParseDuckDuckGo
+				if len(got) != 2 || got[0].URL != want[0].URL {
+					t.Errorf("Search = %+v", got)
+				}
+				return
+			}
+			// SerpAPI returns a third result, cut to the count asked for
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Search =\n%+v\nwant\n%+v", got, want)
+			}
+		})
+	}
+
+	// SerpAPI reports an empty result page as an error in a 200 response
+	ft.files["serpapi.com"] = "serpapi_empty.json"
+	if got, err := (&SerpAPISearch{APIKey: "skey"}).Search(context.Background(), "site:acme.invalid pricing", 5); err != nil || len(got) != 0 {
+		t.Errorf("empty SerpAPI search = %+v, %v", got, err)
+	}
+	delete(ft.files, "api.search.brave.com")
+	if _, err := (&BraveSearch{APIKey: "brkey"}).Search(context.Background(), "q", 5); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Brave error = %v, want the status", err)
+	}
+}
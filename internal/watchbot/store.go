@@ -4,16 +4,25 @@ package watchbot
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/mail"
+	"net/url"
 	"strings"
 	"time"
 
+	"github.com/RobinCoderZhao/devkit-suite/pkg/differ"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/i18n"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/notify"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/scraper"
 	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
 )
 
 // Store provides persistence for WatchBot using the common storage layer.
 type Store struct {
-	db *storage.DB
+	db      *storage.DB
+	secrets *storage.SecretBox // encrypts webhook route targets; nil stores them as-is
 }
 
 // NewStore creates a new store with the given storage database.
@@ -21,6 +30,12 @@ func NewStore(db *storage.DB) *Store {
 	return &Store{db: db}
 }
 
+// SetSecretBox encrypts webhook URLs, which usually embed a token, when
+// routes are saved. Routes saved before remain readable.
+func (s *Store) SetSecretBox(box *storage.SecretBox) {
+	s.secrets = box
+}
+
 // --- Competitors ---
 
 // Competitor represents a monitored competitor for a specific user.
@@ -36,30 +51,21 @@ type Competitor struct {
 func (s *Store) AddCompetitor(ctx context.Context, userID int, name, domain string) (int, error) {
 	domain = strings.TrimSpace(strings.ToLower(domain))
 
-	// PostgreSQL and SQLite have different upsert syntaxes if returning ID.
-	// For simplicity, handle insert and then fallback to select.
-	res, err := s.db.ExecContext(ctx,
+	// RETURNING yields the row's id whether it was inserted or updated
+	id, err := s.db.InsertID(ctx,
 		`INSERT INTO competitors (user_id, name, domain) VALUES (?, ?, ?) 
 		 ON CONFLICT(user_id, domain) DO UPDATE SET name=excluded.name`,
 		userID, name, domain)
 	if err != nil {
 		return 0, fmt.Errorf("add competitor: %w", err)
 	}
-
-	id, _ := res.LastInsertId()
-	if id == 0 {
-		row := s.db.QueryRowContext(ctx, `SELECT id FROM competitors WHERE user_id = ? AND domain = ?`, userID, domain)
-		if err := row.Scan(&id); err != nil {
-			return 0, err
-		}
-	}
 	return int(id), nil
 }
 
 // GetCompetitor returns a competitor for a user by name.
 func (s *Store) GetCompetitor(ctx context.Context, userID int, name string) (*Competitor, error) {
 	row := s.db.QueryRowContext(ctx,
-		`SELECT id, user_id, name, domain, created_at FROM competitors WHERE user_id = ? AND name = ? COLLATE NOCASE`, userID, name)
+		`SELECT id, user_id, name, domain, created_at FROM competitors WHERE user_id = ? AND LOWER(name) = LOWER(?)`, userID, name)
 	c := &Competitor{}
 	if err := row.Scan(&c.ID, &c.UserID, &c.Name, &c.Domain, &c.CreatedAt); err != nil {
 		if err == sql.ErrNoRows {
@@ -72,8 +78,25 @@ func (s *Store) GetCompetitor(ctx context.Context, userID int, name string) (*Co
 
 // ListCompetitorsByUser returns all competitors for a specific user.
 func (s *Store) ListCompetitorsByUser(ctx context.Context, userID int) ([]Competitor, error) {
+	return s.listCompetitors(ctx, `c.user_id = ?`, userID)
+}
+
+// teamScope restricts a query on competitors c to those a user can see:
+// their own and those of everyone they share a team with. It takes the
+// user ID twice.
+const teamScope = `(c.user_id = ? OR c.user_id IN (
+	SELECT o.user_id FROM team_members m JOIN team_members o ON o.team_id = m.team_id WHERE m.user_id = ?))`
+
+// ListVisibleCompetitors returns the competitors a user can see: their own
+// and their teammates', as the dashboard shows them. Each keeps its owner
+// in UserID.
+func (s *Store) ListVisibleCompetitors(ctx context.Context, userID int) ([]Competitor, error) {
+	return s.listCompetitors(ctx, teamScope, userID, userID)
+}
+
+func (s *Store) listCompetitors(ctx context.Context, where string, args ...any) ([]Competitor, error) {
 	rows, err := s.db.QueryContext(ctx,
-		`SELECT id, user_id, name, domain, created_at FROM competitors WHERE user_id = ? ORDER BY name`, userID)
+		`SELECT c.id, c.user_id, c.name, c.domain, c.created_at FROM competitors c WHERE `+where+` ORDER BY c.name, c.id`, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -98,6 +121,10 @@ type Page struct {
 	URL           string
 	PageType      string
 	LastCheckedAt *time.Time
+	CheckInterval time.Duration // time between checks in serve; 0 uses the default
+	Browser       bool          // rendered in a headless browser instead of fetched over HTTP
+	WaitSelector  string        // CSS selector the browser waits for; "" waits for the load event
+	Selector      string        // CSS selector or XPath of the part to monitor; "" monitors the whole page
 	CreatedAt     time.Time
 }
 
@@ -112,7 +139,7 @@ type PageWithMeta struct {
 
 // AddPage inserts a page for a competitor.
 func (s *Store) AddPage(ctx context.Context, competitorID int, url, pageType string) (int, error) {
-	res, err := s.db.ExecContext(ctx,
+	id, err := s.db.InsertID(ctx,
 		`INSERT INTO pages (competitor_id, url, page_type) VALUES (?, ?, ?)`,
 		competitorID, url, pageType)
 	if err != nil {
@@ -128,14 +155,13 @@ func (s *Store) AddPage(ctx context.Context, competitorID int, url, pageType str
 		}
 		return 0, fmt.Errorf("add page: %w", err)
 	}
-	id, _ := res.LastInsertId()
 	return int(id), nil
 }
 
 // GetPagesByCompetitor retrieves all pages tracked for a specific competitor.
 func (s *Store) GetPagesByCompetitor(ctx context.Context, competitorID int) ([]Page, error) {
 	rows, err := s.db.QueryContext(ctx,
-		`SELECT id, competitor_id, url, page_type, last_checked_at, created_at FROM pages WHERE competitor_id = ?`,
+		`SELECT id, competitor_id, url, page_type, last_checked_at, check_interval, browser, wait_selector, selector, created_at FROM pages WHERE competitor_id = ?`,
 		competitorID)
 	if err != nil {
 		return nil, err
@@ -145,9 +171,11 @@ func (s *Store) GetPagesByCompetitor(ctx context.Context, competitorID int) ([]P
 	var result []Page
 	for rows.Next() {
 		var p Page
-		if err := rows.Scan(&p.ID, &p.CompetitorID, &p.URL, &p.PageType, &p.LastCheckedAt, &p.CreatedAt); err != nil {
+		var interval int64
+		if err := rows.Scan(&p.ID, &p.CompetitorID, &p.URL, &p.PageType, &p.LastCheckedAt, &interval, &p.Browser, &p.WaitSelector, &p.Selector, &p.CreatedAt); err != nil {
 			return nil, err
 		}
+		p.CheckInterval = time.Duration(interval) * time.Second
 		result = append(result, p)
 	}
 	return result, nil
@@ -157,7 +185,7 @@ func (s *Store) GetPagesByCompetitor(ctx context.Context, competitorID int) ([]P
 // This is used by the global pipeline to fetch all URLs that need checking.
 func (s *Store) GetAllActivePages(ctx context.Context) ([]PageWithMeta, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT p.id, p.competitor_id, p.url, p.page_type, p.last_checked_at, p.created_at,
+		SELECT p.id, p.competitor_id, p.url, p.page_type, p.last_checked_at, p.check_interval, p.browser, p.wait_selector, p.selector, p.created_at,
 		       c.name, c.domain, c.user_id,
 		       u.email
 		FROM pages p
@@ -171,18 +199,57 @@ func (s *Store) GetAllActivePages(ctx context.Context) ([]PageWithMeta, error) {
 	var result []PageWithMeta
 	for rows.Next() {
 		var pm PageWithMeta
+		var interval int64
 		if err := rows.Scan(
-			&pm.ID, &pm.CompetitorID, &pm.URL, &pm.PageType, &pm.LastCheckedAt, &pm.CreatedAt,
+			&pm.ID, &pm.CompetitorID, &pm.URL, &pm.PageType, &pm.LastCheckedAt, &interval, &pm.Browser, &pm.WaitSelector, &pm.Selector, &pm.CreatedAt,
 			&pm.CompetitorName, &pm.CompetitorDomain, &pm.UserID,
 			&pm.UserEmail,
 		); err != nil {
 			return nil, err
 		}
+		pm.CheckInterval = time.Duration(interval) * time.Second
 		result = append(result, pm)
 	}
 	return result, nil
 }
 
+// SetCheckInterval sets how often serve checks a page, rounded to whole
+// seconds. Zero restores the default interval; otherwise it must be at least
+// MinCheckInterval.
+func (s *Store) SetCheckInterval(ctx context.Context, pageID int, interval time.Duration) error {
+	if interval < 0 || (interval > 0 && interval < MinCheckInterval) {
+		return fmt.Errorf("check interval must be at least %s", MinCheckInterval)
+	}
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE pages SET check_interval = ? WHERE id = ?`, int64(interval/time.Second), pageID)
+	return err
+}
+
+// SetBrowserRendering opts a page in or out of headless-browser rendering,
+// for pages that only show their content after JavaScript runs. The browser
+// waits for waitSelector, if set, before reading the page.
+func (s *Store) SetBrowserRendering(ctx context.Context, pageID int, enabled bool, waitSelector string) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE pages SET browser = ?, wait_selector = ? WHERE id = ?`, enabled, waitSelector, pageID)
+	return err
+}
+
+// SetSelector limits monitoring of a page to the parts matching selector, a
+// CSS selector or XPath expression (see scraper.ParseSelector), so cookie
+// banners and footers elsewhere on the page are not diffed. An empty
+// selector monitors the whole page.
+func (s *Store) SetSelector(ctx context.Context, pageID int, selector string) error {
+	selector = strings.TrimSpace(selector)
+	if selector != "" {
+		if _, err := scraper.ParseSelector(selector); err != nil {
+			return err
+		}
+	}
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE pages SET selector = ? WHERE id = ?`, selector, pageID)
+	return err
+}
+
 // UpdateLastChecked updates the last_checked_at timestamp for a page.
 func (s *Store) UpdateLastChecked(ctx context.Context, pageID int) error {
 	_, err := s.db.ExecContext(ctx,
@@ -192,30 +259,65 @@ func (s *Store) UpdateLastChecked(ctx context.Context, pageID int) error {
 
 // --- Snapshots ---
 
-// SaveSnapshot stores a new content snapshot.
-func (s *Store) SaveSnapshot(ctx context.Context, pageID int, content, checksum string) (int, error) {
-	res, err := s.db.ExecContext(ctx,
-		`INSERT INTO snapshots (page_id, content, checksum) VALUES (?, ?, ?)`,
-		pageID, content, checksum)
+// SaveSnapshot stores a new content snapshot, taken with the page's
+// selector. outline is the page's structural outline as JSON, or empty when
+// structure diffs are off.
+func (s *Store) SaveSnapshot(ctx context.Context, pageID int, selector, content, outline, checksum string) (int, error) {
+	id, err := s.db.InsertID(ctx,
+		`INSERT INTO snapshots (page_id, selector, content, outline, checksum) VALUES (?, ?, ?, ?, ?)`,
+		pageID, selector, content, outline, checksum)
 	if err != nil {
 		return 0, err
 	}
-	id, _ := res.LastInsertId()
 	return int(id), nil
 }
 
-// GetLatestSnapshot returns the most recent snapshot for a page.
-func (s *Store) GetLatestSnapshot(ctx context.Context, pageID int) (id int, content, checksum string, err error) {
+// GetLatestSnapshot returns the most recent snapshot for a page and the
+// selector it was taken with.
+func (s *Store) GetLatestSnapshot(ctx context.Context, pageID int) (id int, selector, content, checksum string, err error) {
 	row := s.db.QueryRowContext(ctx,
-		`SELECT id, content, checksum FROM snapshots WHERE page_id = ? ORDER BY captured_at DESC LIMIT 1`,
+		`SELECT id, selector, content, checksum FROM snapshots WHERE page_id = ? ORDER BY captured_at DESC LIMIT 1`,
 		pageID)
-	err = row.Scan(&id, &content, &checksum)
+	err = row.Scan(&id, &selector, &content, &checksum)
 	if err == sql.ErrNoRows {
-		return 0, "", "", nil
+		return 0, "", "", "", nil
 	}
 	return
 }
 
+// keepScreenshots is how many of a page's latest snapshots keep their
+// screenshot; older ones are deleted as new ones are saved.
+const keepScreenshots = 5
+
+// SaveScreenshot stores the PNG taken with a snapshot, replacing any it
+// has, and deletes the screenshots of the page's older snapshots.
+func (s *Store) SaveScreenshot(ctx context.Context, pageID, snapshotID int, png []byte) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM snapshot_screenshots WHERE snapshot_id = ?`, snapshotID); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO snapshot_screenshots (snapshot_id, png) VALUES (?, ?)`, snapshotID, png); err != nil {
+		return err
+	}
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM snapshot_screenshots WHERE snapshot_id IN (
+			SELECT id FROM snapshots WHERE page_id = ? AND id NOT IN (
+				SELECT id FROM snapshots WHERE page_id = ? ORDER BY id DESC LIMIT ?))`,
+		pageID, pageID, keepScreenshots)
+	return err
+}
+
+// GetScreenshot returns the PNG taken with a snapshot, or nil if it has none.
+func (s *Store) GetScreenshot(ctx context.Context, snapshotID int) ([]byte, error) {
+	var png []byte
+	err := s.db.QueryRowContext(ctx,
+		`SELECT png FROM snapshot_screenshots WHERE snapshot_id = ?`, snapshotID).Scan(&png)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return png, err
+}
+
 // --- Analyses (formerly Changes) ---
 
 // Change represents a detected change record (mapped to analyses table).
@@ -239,6 +341,11 @@ type Change struct {
 	PageURL        string
 	PageType       string
 	UserID         int
+
+	// DigestID is the digest a held change already went out in when that
+	// delivery failed, so its retry reuses the ID; empty for changes not
+	// yet sent
+	DigestID string
 }
 
 // SaveChange records a detected change.
@@ -248,14 +355,13 @@ func (s *Store) SaveChange(ctx context.Context, pageID, oldSnapID, newSnapID int
 		oldSnap = oldSnapID
 	}
 
-	res, err := s.db.ExecContext(ctx,
+	id, err := s.db.InsertID(ctx,
 		`INSERT INTO analyses (page_id, old_snapshot_id, new_snapshot_id, severity, summary, raw_diff)
 		 VALUES (?, ?, ?, ?, ?, ?)`,
 		pageID, oldSnap, newSnapID, severity, analysis, diffUnified)
 	if err != nil {
 		return 0, err
 	}
-	id, _ := res.LastInsertId()
 	return int(id), nil
 }
 
@@ -281,6 +387,32 @@ func (s *Store) GetLatestChange(ctx context.Context, pageID int) (*Change, error
 	return &c, nil
 }
 
+// GetChangeForUser fetches one change on a page the user or a teammate
+// monitors, or nil if there is none.
+func (s *Store) GetChangeForUser(ctx context.Context, changeID, userID int) (*Change, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT a.id, a.page_id, a.old_snapshot_id, a.new_snapshot_id, a.severity, a.summary, a.raw_diff, a.created_at,
+		        p.url, p.page_type, c.id, c.name, c.user_id
+		 FROM analyses a
+		 JOIN pages p ON a.page_id = p.id
+		 JOIN competitors c ON p.competitor_id = c.id
+		 WHERE a.id = ? AND `+teamScope, changeID, userID, userID)
+
+	var c Change
+	var summary, diffUnified sql.NullString
+	err := row.Scan(&c.ID, &c.PageID, &c.OldSnapshotID, &c.NewSnapshotID, &c.Severity, &summary, &diffUnified, &c.CreatedAt,
+		&c.PageURL, &c.PageType, &c.CompetitorID, &c.CompetitorName, &c.UserID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.Analysis = summary.String
+	c.DiffUnified = diffUnified.String
+	return &c, nil
+}
+
 // GetTimelineByCompetitor returns all historical changes for a specific competitor's pages.
 func (s *Store) GetTimelineByCompetitor(ctx context.Context, competitorID int) ([]Change, error) {
 	rows, err := s.db.QueryContext(ctx,
@@ -308,51 +440,127 @@ func (s *Store) GetTimelineByCompetitor(ctx context.Context, competitorID int) (
 	return result, nil
 }
 
+// GetRecentChanges returns all changes recorded since the given time, joined
+// with page and competitor info for digest rendering.
+func (s *Store) GetRecentChanges(ctx context.Context, since time.Time) ([]Change, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT a.id, a.page_id, a.old_snapshot_id, a.new_snapshot_id, a.severity, a.summary, a.raw_diff, a.created_at,
+		        p.url, p.page_type, c.id, c.name, c.user_id, ''
+		 FROM analyses a
+		 JOIN pages p ON a.page_id = p.id
+		 JOIN competitors c ON p.competitor_id = c.id
+		 WHERE a.created_at >= ?
+		 ORDER BY a.created_at DESC`, since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanDigestChanges(rows)
+}
+
+// scanDigestChanges scans rows of analyses joined with page and competitor info:
+// a.id, a.page_id, a.old_snapshot_id, a.new_snapshot_id, a.severity, a.summary,
+// a.raw_diff, a.created_at, p.url, p.page_type, c.id, c.name, c.user_id and
+// the digest ID of held changes.
+func scanDigestChanges(rows *sql.Rows) ([]Change, error) {
+	var result []Change
+	for rows.Next() {
+		var c Change
+		var severity, summary, diffUnified sql.NullString
+		if err := rows.Scan(&c.ID, &c.PageID, &c.OldSnapshotID, &c.NewSnapshotID, &severity, &summary, &diffUnified, &c.CreatedAt,
+			&c.PageURL, &c.PageType, &c.CompetitorID, &c.CompetitorName, &c.UserID, &c.DigestID); err != nil {
+			return nil, err
+		}
+		c.Severity = severity.String
+		c.Analysis = summary.String
+		c.DiffUnified = diffUnified.String
+		stats := differ.UnifiedStats(c.DiffUnified)
+		c.Additions, c.Deletions = stats.Additions, stats.Deletions
+		result = append(result, c)
+	}
+	return result, rows.Err()
+}
+
 // --- Users (formerly Subscribers) ---
 
 // User represents a tenant.
 type User struct {
-	ID    int
-	Email string
-	Plan  string
+	ID                int
+	Email             string
+	Plan              string
+	NotificationEmail string // overrides Email as the delivery address when set
+}
+
+// DeliveryEmail returns the address the user's email notifications go to.
+func (u User) DeliveryEmail() string {
+	if u.NotificationEmail != "" {
+		return u.NotificationEmail
+	}
+	return u.Email
 }
 
 // ensureUser is a helper for testing/CLI to make sure a user exists.
 func (s *Store) ensureUser(ctx context.Context, email string) (int, error) {
 	email = strings.TrimSpace(strings.ToLower(email))
-	res, err := s.db.ExecContext(ctx,
+	id, err := s.db.InsertID(ctx,
 		`INSERT INTO users (email, password_hash, plan) VALUES (?, 'dummy_hash', 'free') 
          ON CONFLICT(email) DO NOTHING`, email)
+	if errors.Is(err, sql.ErrNoRows) { // already exists
+		err = s.db.QueryRowContext(ctx, `SELECT id FROM users WHERE email = ?`, email).Scan(&id)
+	}
 	if err != nil {
 		return 0, err
 	}
-	id, _ := res.LastInsertId()
-	if id == 0 {
-		row := s.db.QueryRowContext(ctx, `SELECT id FROM users WHERE email = ?`, email)
-		if err := row.Scan(&id); err != nil {
-			return 0, err
-		}
-	}
 	return int(id), nil
 }
 
+// userIDByEmail looks up a user for CLI use, returning 0 for unknown users.
+func (s *Store) userIDByEmail(ctx context.Context, email string) (int, error) {
+	var id int
+	err := s.db.QueryRowContext(ctx, `SELECT id FROM users WHERE email = ?`,
+		strings.TrimSpace(strings.ToLower(email))).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return id, err
+}
+
 // UserWithCompetitors holds user info with their competitors.
 type UserWithCompetitors struct {
-	ID              int
-	Email           string
-	CompetitorIDs   []int
-	CompetitorNames []string
+	ID                int
+	Email             string
+	NotificationEmail string // overrides Email as the delivery address when set
+	Phone             string // E.164 number for critical SMS alerts; empty disables SMS
+	Language          string // notification language ("zh", "en", ...); empty uses the default template
+	Timezone          string // IANA time zone for quiet hours; empty uses the server's local zone
+	QuietHours        string // "22:00-07:00" local time; empty disables quiet hours
+	EmailFormat       string // "plain" sends text-only email; empty or "html" sends HTML
+	CompetitorIDs     []int
+	CompetitorNames   []string
+}
+
+// DeliveryEmail returns the address the user's email notifications go to.
+func (u UserWithCompetitors) DeliveryEmail() string {
+	if u.NotificationEmail != "" {
+		return u.NotificationEmail
+	}
+	return u.Email
 }
 
 // GetUsersWithCompetitors returns all users along with their monitored competitors.
 // This replaces the old GetActiveSubscribers logic.
 func (s *Store) GetUsersWithCompetitors(ctx context.Context) ([]UserWithCompetitors, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT u.id, u.email, GROUP_CONCAT(c.id) as comp_ids,
-		       GROUP_CONCAT(c.name) as comp_names
+		SELECT u.id, u.email, u.notification_email, COALESCE(u.phone, '') as phone, u.language,
+		       u.timezone, COALESCE(quiet.value, '') as quiet_hours,
+		       COALESCE(emailfmt.value, '') as email_format,
+		       `+s.db.GroupConcat("c.id", ",")+` as comp_ids,
+		       `+s.db.GroupConcat("c.name", ",")+` as comp_names
 		FROM users u
 		JOIN competitors c ON c.user_id = u.id
-		GROUP BY u.id, u.email`)
+		LEFT JOIN user_settings quiet ON quiet.user_id = u.id AND quiet.key = 'quiet_hours'
+		LEFT JOIN user_settings emailfmt ON emailfmt.user_id = u.id AND emailfmt.key = 'format'
+		GROUP BY u.id, u.email, u.notification_email, u.phone, u.language, u.timezone, quiet.value, emailfmt.value`)
 	if err != nil {
 		return nil, err
 	}
@@ -361,7 +569,7 @@ func (s *Store) GetUsersWithCompetitors(ctx context.Context) ([]UserWithCompetit
 	for rows.Next() {
 		var uw UserWithCompetitors
 		var compIDs, compNames string
-		if err := rows.Scan(&uw.ID, &uw.Email, &compIDs, &compNames); err != nil {
+		if err := rows.Scan(&uw.ID, &uw.Email, &uw.NotificationEmail, &uw.Phone, &uw.Language, &uw.Timezone, &uw.QuietHours, &uw.EmailFormat, &compIDs, &compNames); err != nil {
 			return nil, err
 		}
 		for _, idStr := range strings.Split(compIDs, ",") {
@@ -380,6 +588,274 @@ func (s *Store) GetUsersWithCompetitors(ctx context.Context) ([]UserWithCompetit
 	return result, nil
 }
 
+// --- Notification Routes ---
+
+// GetUserRoutes returns the delivery channels a user has opted into.
+// An empty result means the user has no explicit preferences.
+func (s *Store) GetUserRoutes(ctx context.Context, userID int) ([]notify.Route, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT channel, COALESCE(target, '') FROM notification_routes WHERE user_id = ? ORDER BY id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []notify.Route
+	for rows.Next() {
+		var r notify.Route
+		var ch string
+		if err := rows.Scan(&ch, &r.Target); err != nil {
+			return nil, err
+		}
+		r.Channel = notify.Channel(ch)
+		if storage.IsSealed(r.Target) {
+			if s.secrets == nil {
+				return nil, fmt.Errorf("%s route: %w", ch, storage.ErrNoSecretBox)
+			}
+			if r.Target, err = s.secrets.Open(ctx, r.Target); err != nil {
+				return nil, fmt.Errorf("%s route: %w", ch, err)
+			}
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// SetUserRoutes replaces a user's delivery channels.
+func (s *Store) SetUserRoutes(ctx context.Context, userID int, routes []notify.Route) error {
+	// Sealed targets differ on every write, so duplicates are dropped here
+	// rather than by the unique constraint
+	seen := make(map[notify.Route]bool)
+	var targets []string
+	var unique []notify.Route
+	for _, r := range routes {
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+		target := r.Target
+		if r.Channel == notify.ChannelWebhook && s.secrets != nil {
+			sealed, err := s.secrets.Seal(ctx, target)
+			if err != nil {
+				return fmt.Errorf("seal webhook route: %w", err)
+			}
+			target = sealed
+		}
+		unique = append(unique, r)
+		targets = append(targets, target)
+	}
+
+	return s.db.Transaction(ctx, func(tx *storage.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM notification_routes WHERE user_id = ?`, userID); err != nil {
+			return fmt.Errorf("clear routes: %w", err)
+		}
+		for i, r := range unique {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO notification_routes (user_id, channel, target) VALUES (?, ?, ?)
+				 ON CONFLICT(user_id, channel, target) DO NOTHING`,
+				userID, string(r.Channel), targets[i]); err != nil {
+				return fmt.Errorf("insert route: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetUserRoutesByEmail is GetUserRoutes for CLI use; unknown users have no routes.
+func (s *Store) GetUserRoutesByEmail(ctx context.Context, email string) ([]notify.Route, error) {
+	userID, err := s.userIDByEmail(ctx, email)
+	if err != nil || userID == 0 {
+		return nil, err
+	}
+	return s.GetUserRoutes(ctx, userID)
+}
+
+// SetUserRoutesByEmail is SetUserRoutes for CLI use, creating the user if needed.
+func (s *Store) SetUserRoutesByEmail(ctx context.Context, email string, routes []notify.Route) error {
+	userID, err := s.ensureUser(ctx, email)
+	if err != nil {
+		return err
+	}
+	return s.SetUserRoutes(ctx, userID, routes)
+}
+
+// ValidateRoutes reports whether routes can carry a digest: each channel must
+// be one a digest is sent on, email targets must be addresses and webhook
+// targets http(s) URLs. SMS is left to the phone number, see SetUserPhone.
+func ValidateRoutes(routes []notify.Route) error {
+	for _, r := range routes {
+		if !escalationChannels[r.Channel] {
+			return fmt.Errorf("cannot route digests to channel %q", r.Channel)
+		}
+		if r.Target == "" {
+			continue
+		}
+		switch r.Channel {
+		case notify.ChannelEmail:
+			if _, err := mail.ParseAddress(r.Target); err != nil {
+				return fmt.Errorf("email route: %w", err)
+			}
+		case notify.ChannelWebhook:
+			if u, err := url.Parse(r.Target); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("webhook route: %q is not an http(s) URL", r.Target)
+			}
+		}
+	}
+	return nil
+}
+
+// SetUserPhone stores the phone number used for critical SMS alerts.
+func (s *Store) SetUserPhone(ctx context.Context, userID int, phone string) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE users SET phone = ? WHERE id = ?`, strings.TrimSpace(phone), userID)
+	return err
+}
+
+// --- User Settings ---
+
+// GetUserSetting returns a single per-user setting, or "" when unset.
+func (s *Store) GetUserSetting(ctx context.Context, userID int, key string) (string, error) {
+	var value string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT value FROM user_settings WHERE user_id = ? AND key = ?`, userID, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return value, err
+}
+
+// SetUserSetting stores a per-user setting, replacing any previous value.
+func (s *Store) SetUserSetting(ctx context.Context, userID int, key, value string) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO user_settings (user_id, key, value) VALUES (?, ?, ?)
+		 ON CONFLICT(user_id, key) DO UPDATE SET value = excluded.value, updated_at = CURRENT_TIMESTAMP`,
+		userID, key, value)
+	return err
+}
+
+// GetUserEscalation returns the user's severity escalation policy, or nil to use
+// the global default.
+func (s *Store) GetUserEscalation(ctx context.Context, userID int) (notify.EscalationPolicy, error) {
+	raw, err := s.GetUserSetting(ctx, userID, "escalation")
+	if err != nil || raw == "" {
+		return nil, err
+	}
+	return notify.ParseEscalationPolicy(raw)
+}
+
+// SetUserEscalation stores the user's severity escalation policy; nil clears it.
+func (s *Store) SetUserEscalation(ctx context.Context, userID int, policy notify.EscalationPolicy) error {
+	if policy == nil {
+		_, err := s.db.ExecContext(ctx, `DELETE FROM user_settings WHERE user_id = ? AND key = 'escalation'`, userID)
+		return err
+	}
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	return s.SetUserSetting(ctx, userID, "escalation", string(raw))
+}
+
+// GetUserEscalationByEmail is GetUserEscalation for CLI use; unknown users
+// have no policy.
+func (s *Store) GetUserEscalationByEmail(ctx context.Context, email string) (notify.EscalationPolicy, error) {
+	userID, err := s.userIDByEmail(ctx, email)
+	if err != nil || userID == 0 {
+		return nil, err
+	}
+	return s.GetUserEscalation(ctx, userID)
+}
+
+// SetUserEscalationByEmail is SetUserEscalation for CLI use, creating the
+// user if needed.
+func (s *Store) SetUserEscalationByEmail(ctx context.Context, email string, policy notify.EscalationPolicy) error {
+	userID, err := s.ensureUser(ctx, email)
+	if err != nil {
+		return err
+	}
+	return s.SetUserEscalation(ctx, userID, policy)
+}
+
+// SetUserLanguage sets the language used for a user's WatchBot notifications.
+func (s *Store) SetUserLanguage(ctx context.Context, userID int, lang string) error {
+	if !i18n.IsValidLanguage(lang) {
+		return fmt.Errorf("unsupported language: %s", lang)
+	}
+	_, err := s.db.ExecContext(ctx, `UPDATE users SET language = ? WHERE id = ?`, lang, userID)
+	return err
+}
+
+// SetUserEmailFormat sets whether a user's digest emails are sent as "html" or "plain" text.
+func (s *Store) SetUserEmailFormat(ctx context.Context, userID int, format string) error {
+	if format != "html" && format != "plain" {
+		return fmt.Errorf("unsupported email format: %s", format)
+	}
+	return s.SetUserSetting(ctx, userID, "format", format)
+}
+
+// --- Benchmark Reports ---
+
+// BenchmarkUnsubscribeList is the mailing-list name used in benchmark report
+// unsubscribe tokens.
+const BenchmarkUnsubscribeList = "benchmarks"
+
+// SetUserBenchmarkUpdates opts a user into or out of scheduled benchmark
+// reports. Opting in again clears an earlier one-click unsubscribe.
+func (s *Store) SetUserBenchmarkUpdates(ctx context.Context, userID int, enabled bool) error {
+	if !enabled {
+		_, err := s.db.ExecContext(ctx, `DELETE FROM user_settings WHERE user_id = ? AND key = 'benchmark_updates'`, userID)
+		return err
+	}
+	return s.db.Transaction(ctx, func(tx *storage.Tx) error {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO user_settings (user_id, key, value) VALUES (?, 'benchmark_updates', 'true')
+			 ON CONFLICT(user_id, key) DO UPDATE SET value = excluded.value, updated_at = CURRENT_TIMESTAMP`,
+			userID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM user_settings WHERE user_id = ? AND key = ?`,
+			userID, "unsubscribe."+BenchmarkUnsubscribeList)
+		return err
+	})
+}
+
+// SetBenchmarkUpdatesByEmail is SetUserBenchmarkUpdates for CLI use, creating
+// the user if needed.
+func (s *Store) SetBenchmarkUpdatesByEmail(ctx context.Context, email string, enabled bool) error {
+	userID, err := s.ensureUser(ctx, email)
+	if err != nil {
+		return err
+	}
+	return s.SetUserBenchmarkUpdates(ctx, userID, enabled)
+}
+
+// GetBenchmarkSubscribers returns the users who opted into benchmark reports
+// and have not unsubscribed from them since.
+func (s *Store) GetBenchmarkSubscribers(ctx context.Context) ([]User, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT u.id, u.email, COALESCE(u.plan, ''), u.notification_email
+		FROM users u
+		JOIN user_settings opt ON opt.user_id = u.id AND opt.key = 'benchmark_updates' AND opt.value = 'true'
+		LEFT JOIN user_settings unsub ON unsub.user_id = u.id AND unsub.key = ?
+		WHERE COALESCE(unsub.value, '') != 'true'
+		ORDER BY u.id`, "unsubscribe."+BenchmarkUnsubscribeList)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []User
+	for rows.Next() {
+		var u User
+		if err := rows.Scan(&u.ID, &u.Email, &u.Plan, &u.NotificationEmail); err != nil {
+			return nil, err
+		}
+		result = append(result, u)
+	}
+	return result, rows.Err()
+}
+
 func (s *Store) InitMetadata(ctx context.Context) error {
 	_, err := s.db.ExecContext(ctx, `
 		CREATE TABLE IF NOT EXISTS metadata (
@@ -0,0 +1,137 @@
+package watchbot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/notify"
+)
+
+// EngagementStat summarizes how a subscriber interacts with their digests.
+type EngagementStat struct {
+	UserID      int        `json:"user_id"`
+	Email       string     `json:"email"`
+	Deliveries  int        `json:"deliveries"`
+	Opened      int        `json:"opened"`  // digests opened at least once
+	Clicked     int        `json:"clicked"` // digests with at least one click
+	LastSentAt  *time.Time `json:"last_sent_at,omitempty"`
+	LastEventAt *time.Time `json:"last_event_at,omitempty"`
+}
+
+// digestKey derives a digest ID from the user and the changes it contains.
+// A failed digest's changes are held with the ID, so its retry reuses it even
+// when new changes arrive meanwhile, and the dispatcher's delivery log can
+// skip channels that already received it.
+func digestKey(userID int, changes []Change) string {
+	ids := make([]int, len(changes))
+	for i, c := range changes {
+		ids[i] = c.ID
+	}
+	sort.Ints(ids)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%d", userID)
+	for _, id := range ids {
+		fmt.Fprintf(h, ":%d", id)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:12])
+}
+
+// RecordDelivery records that a digest was sent to a user. Recording the same
+// digest again is a no-op.
+func (s *Store) RecordDelivery(ctx context.Context, digestID string, userID, changes int) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO digest_deliveries (id, user_id, changes) VALUES (?, ?, ?)
+		 ON CONFLICT(id) DO NOTHING`,
+		digestID, userID, changes)
+	return err
+}
+
+// RecordEngagement records an open or click on a delivered digest.
+// Events for unknown digests are ignored.
+func (s *Store) RecordEngagement(ctx context.Context, digestID string, userID int, kind, url string) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO engagement_events (digest_id, user_id, kind, url)
+		 SELECT ?, ?, ?, ? WHERE EXISTS (SELECT 1 FROM digest_deliveries WHERE id = ? AND user_id = ?)`,
+		digestID, userID, kind, url, digestID, userID)
+	return err
+}
+
+// EngagementSummary returns per-subscriber engagement for digests sent since the
+// given time, least engaged first. Opens are a lower bound: many clients block
+// tracking pixels, so a click also counts as an open.
+func (s *Store) EngagementSummary(ctx context.Context, since time.Time) ([]EngagementStat, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT d.user_id, u.email, COUNT(*),
+		       SUM(CASE WHEN EXISTS (SELECT 1 FROM engagement_events e WHERE e.digest_id = d.id) THEN 1 ELSE 0 END),
+		       SUM(CASE WHEN EXISTS (SELECT 1 FROM engagement_events e WHERE e.digest_id = d.id AND e.kind = 'click') THEN 1 ELSE 0 END),
+		       MAX(d.sent_at),
+		       (SELECT MAX(e.created_at) FROM engagement_events e WHERE e.user_id = d.user_id)
+		FROM digest_deliveries d
+		JOIN users u ON u.id = d.user_id
+		WHERE d.sent_at >= ?
+		GROUP BY d.user_id, u.email
+		ORDER BY 4 ASC, 5 ASC, d.user_id`, since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []EngagementStat
+	for rows.Next() {
+		var st EngagementStat
+		var lastSent, lastEvent *string
+		if err := rows.Scan(&st.UserID, &st.Email, &st.Deliveries, &st.Opened, &st.Clicked, &lastSent, &lastEvent); err != nil {
+			return nil, err
+		}
+		st.LastSentAt = parseSQLiteTime(lastSent)
+		st.LastEventAt = parseSQLiteTime(lastEvent)
+		stats = append(stats, st)
+	}
+	return stats, rows.Err()
+}
+
+// parseSQLiteTime parses a CURRENT_TIMESTAMP value, returning nil if absent.
+func parseSQLiteTime(s *string) *time.Time {
+	if s == nil || *s == "" {
+		return nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", time.RFC3339Nano} {
+		if t, err := time.Parse(layout, *s); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// WasDelivered implements notify.DeliveryLog.
+func (s *Store) WasDelivered(ctx context.Context, key string, route notify.Route) (bool, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM notification_deliveries WHERE idempotency_key = ? AND channel = ? AND target = ?`,
+		key, string(route.Channel), deliveryTarget(route)).Scan(&n)
+	return n > 0, err
+}
+
+// MarkDelivered implements notify.DeliveryLog.
+func (s *Store) MarkDelivered(ctx context.Context, key string, route notify.Route) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO notification_deliveries (idempotency_key, channel, target) VALUES (?, ?, ?)
+		 ON CONFLICT(idempotency_key, channel, target) DO NOTHING`,
+		key, string(route.Channel), deliveryTarget(route))
+	return err
+}
+
+// deliveryTarget is the target recorded in the delivery log. Webhook URLs
+// usually embed a token, so only their hash is kept.
+func deliveryTarget(route notify.Route) string {
+	if route.Channel != notify.ChannelWebhook || route.Target == "" {
+		return route.Target
+	}
+	sum := sha256.Sum256([]byte(route.Target))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -2,26 +2,49 @@ package watchbot
 
 import (
 	"context"
-	"fmt"
 	"net"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/i18n"
 )
 
 // ValidateResult holds the result of URL validation.
 type ValidateResult struct {
 	URL   string // normalized URL
 	Valid bool
-	Error string
+	Error string // in Chinese, the CLI's language; see Localized
+
+	errKey  string
+	errArgs []any
+}
+
+// validateError returns a result carrying the catalog message key.
+func validateError(url string, valid bool, key string, args ...any) ValidateResult {
+	return ValidateResult{
+		URL:     url,
+		Valid:   valid,
+		Error:   i18n.T(i18n.LangZH, key, args...),
+		errKey:  key,
+		errArgs: args,
+	}
+}
+
+// Localized returns the validation error in lang, or "" if there is none.
+func (r ValidateResult) Localized(lang i18n.Language) string {
+	if r.errKey == "" {
+		return r.Error
+	}
+	return i18n.T(lang, r.errKey, r.errArgs...)
 }
 
 // ValidateURL normalizes and validates a URL.
 func ValidateURL(ctx context.Context, rawURL string) ValidateResult {
 	rawURL = strings.TrimSpace(rawURL)
 	if rawURL == "" {
-		return ValidateResult{Error: "URL 不能为空"}
+		return validateError("", false, "watch.error.url_empty")
 	}
 
 	// Auto-add scheme
@@ -32,17 +55,17 @@ func ValidateURL(ctx context.Context, rawURL string) ValidateResult {
 	// Parse
 	u, err := url.Parse(rawURL)
 	if err != nil {
-		return ValidateResult{Error: fmt.Sprintf("URL 格式错误: %v", err)}
+		return validateError("", false, "watch.error.url_invalid", err)
 	}
 
 	// Protocol check
 	if u.Scheme != "http" && u.Scheme != "https" {
-		return ValidateResult{Error: fmt.Sprintf("不支持的协议: %s（仅支持 http/https）", u.Scheme)}
+		return validateError("", false, "watch.error.unsupported_scheme", u.Scheme)
 	}
 
 	// Must have host
 	if u.Host == "" {
-		return ValidateResult{Error: "URL 缺少域名"}
+		return validateError("", false, "watch.error.url_no_host")
 	}
 
 	// Remove fragment
@@ -58,7 +81,7 @@ func ValidateURL(ctx context.Context, rawURL string) ValidateResult {
 	// DNS check
 	host := u.Hostname()
 	if _, err := net.LookupHost(host); err != nil {
-		return ValidateResult{URL: normalized, Error: fmt.Sprintf("域名无法解析: %s", host)}
+		return validateError(normalized, false, "watch.error.host_unresolved", host)
 	}
 
 	// HTTP check (HEAD request with timeout) — soft validation, warn but allow
@@ -66,7 +89,7 @@ func ValidateURL(ctx context.Context, rawURL string) ValidateResult {
 	req, err := http.NewRequestWithContext(ctx, "HEAD", normalized, nil)
 	if err != nil {
 		// Can't even build request — still allow (DNS resolved)
-		return ValidateResult{URL: normalized, Valid: true, Error: fmt.Sprintf("⚠️ 请求构建失败: %v（已添加，请确认 URL 正确）", err)}
+		return validateError(normalized, true, "watch.error.request_failed", err)
 	}
 	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; WatchBot/1.0)")
 
@@ -77,13 +100,13 @@ func ValidateURL(ctx context.Context, rawURL string) ValidateResult {
 		resp, err = client.Do(req)
 		if err != nil {
 			// Network error but DNS resolves — allow with warning
-			return ValidateResult{URL: normalized, Valid: true, Error: fmt.Sprintf("⚠️ 暂时无法访问（已添加，将在检查时重试）")}
+			return validateError(normalized, true, "watch.error.unreachable")
 		}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return ValidateResult{URL: normalized, Valid: true, Error: fmt.Sprintf("⚠️ HTTP %d（已添加，请确认 URL 正确）", resp.StatusCode)}
+		return validateError(normalized, true, "watch.error.http_status", resp.StatusCode)
 	}
 
 	return ValidateResult{URL: normalized, Valid: true}
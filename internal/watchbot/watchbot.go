@@ -8,13 +8,18 @@ import (
 	"context"
 	"crypto/sha256"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/RobinCoderZhao/devkit-suite/pkg/differ"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/i18n"
 	"github.com/RobinCoderZhao/devkit-suite/pkg/llm"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/metrics"
 	"github.com/RobinCoderZhao/devkit-suite/pkg/notify"
 	"github.com/RobinCoderZhao/devkit-suite/pkg/scraper"
 )
@@ -36,113 +41,375 @@ type Target struct {
 type GlobalPipeline struct {
 	store      *Store
 	fetcher    scraper.Fetcher
+	browser    scraper.Fetcher // renders pages opted into browser rendering; nil fetches them over HTTP
 	llmClient  llm.Client
 	dispatcher *notify.Dispatcher
-	channels   []notify.Channel
 	logger     *slog.Logger
+
+	extractMode   scraper.ExtractMode // how page text is extracted before diffing
+	structureDiff bool                // also diff headings, tables and lists of the HTML
+	semanticDiff  bool                // diff by sentence and section instead of by line
+
+	attachDiffs bool // attach raw unified diffs to digest emails
+	inlineDiffs bool // render collapsible diffs inside digest emails
+
+	screenshot func(ctx context.Context, url string) ([]byte, error) // captures changed pages for digests; nil disables
+
+	visual          func(ctx context.Context, url string) ([]byte, error) // captures pages for visual diffs; nil disables
+	visualThreshold float64                                               // share of a page that must change to count
+
+	unsubscribeBaseURL string // public site URL serving /api/unsubscribe
+	unsubscribeSecret  []byte // HMAC key shared with the API server
+
+	tracker *notify.Tracker // open/click tracking for digest emails; nil disables
+
+	webhookFormatter notify.WatchFormatter // renders custom webhook payloads; nil uses the default
+
+	progress func(ctx context.Context, checked, total int, url string) // called after each page in RunCheck; nil disables
+
+	drainTimeout time.Duration // how long in-flight work may finish after RunCheck's ctx is done
+
+	checkInterval time.Duration // default time between checks of a page; 0 checks every page each round
 }
 
+// UnsubscribeList is the mailing-list name used in WatchBot unsubscribe tokens.
+const UnsubscribeList = "watchbot"
+
+// Failed digests are re-dispatched after digestRetryDelay, for changes
+// detected within the last digestRetryWindow.
+const (
+	digestRetryDelay  = 30 * time.Minute
+	digestRetryWindow = 24 * time.Hour
+)
+
+// MinCheckInterval is the shortest check interval a page may have. serve
+// looks for due pages this often by default.
+const MinCheckInterval = 5 * time.Minute
+
+// dueSlack lets a page be checked slightly before its interval has passed,
+// so a page checked late in one round is not pushed back to the round after
+// the one it is due in.
+const dueSlack = 2 * time.Minute
+
 // NewGlobalPipeline creates a new global monitoring pipeline.
+// Delivery channels are resolved per user from their notification routes.
 func NewGlobalPipeline(
 	store *Store,
 	fetcher scraper.Fetcher,
 	llmClient llm.Client,
 	dispatcher *notify.Dispatcher,
-	channels []notify.Channel,
 ) *GlobalPipeline {
 	return &GlobalPipeline{
 		store:      store,
 		fetcher:    fetcher,
 		llmClient:  llmClient,
 		dispatcher: dispatcher,
-		channels:   channels,
 		logger:     slog.Default(),
 	}
 }
 
+// SetExtractMode selects the text extraction used before diffing. Readability
+// mode keeps only the main content, so banners and related links stop
+// producing spurious changes. Switching modes changes every page's baseline.
+func (gp *GlobalPipeline) SetExtractMode(mode scraper.ExtractMode) {
+	gp.extractMode = mode
+}
+
+// SetBrowserFetcher sets the fetcher, typically a scraper.BrowserFetcher,
+// used for pages opted into browser rendering with Store.SetBrowserRendering.
+func (gp *GlobalPipeline) SetBrowserFetcher(f scraper.Fetcher) {
+	gp.browser = f
+}
+
+// SetStructureDiff enables structure-aware diffs: each snapshot keeps an
+// outline of the page's headings, tables and lists, and changes to them
+// ("row added to pricing table") are passed to the LLM analysis alongside
+// the text diff.
+func (gp *GlobalPipeline) SetStructureDiff(enabled bool) {
+	gp.structureDiff = enabled
+}
+
+// SetSemanticDiff diffs page text sentence by sentence with
+// differ.SemanticDiff instead of line by line: re-wrapped paragraphs and
+// reordered items are no longer changes, and text moved between sections is
+// reported as a move, so the LLM analysis gets smaller diffs.
+func (gp *GlobalPipeline) SetSemanticDiff(enabled bool) {
+	gp.semanticDiff = enabled
+}
+
+// SetAttachDiffs enables attaching each change's unified diff to digests.
+func (gp *GlobalPipeline) SetAttachDiffs(enabled bool) {
+	gp.attachDiffs = enabled
+}
+
+// SetInlineDiffs enables a collapsed, colorized diff under each change in digest emails.
+func (gp *GlobalPipeline) SetInlineDiffs(enabled bool) {
+	gp.inlineDiffs = enabled
+}
+
+// SetScreenshots attaches a PNG of each changed page to digests, captured
+// with capture (typically scraper.FetchScreenshot). Nil disables.
+func (gp *GlobalPipeline) SetScreenshots(capture func(ctx context.Context, url string) ([]byte, error)) {
+	gp.screenshot = capture
+}
+
+// DefaultVisualThreshold is the share of a page that must change between
+// screenshots for SetVisualDiff to report it.
+const DefaultVisualThreshold = 0.02
+
+// SetVisualDiff stores a screenshot with each snapshot and compares it with
+// the previous one, so layout and styling changes that leave the text as it
+// was are reported too. Pages rendered by the browser fetcher are captured
+// with the same render; others with capture (scraper.FetchScreenshot or a
+// scraper.ScreenshotAPI). A page counts as changed when at least threshold
+// of it differs; zero uses DefaultVisualThreshold. Digests then attach
+// before and after images of visual changes instead of the screenshots of
+// SetScreenshots. Pages with a selector are compared by text only. Nil
+// capture disables.
+func (gp *GlobalPipeline) SetVisualDiff(capture func(ctx context.Context, url string) ([]byte, error), threshold float64) {
+	if threshold <= 0 {
+		threshold = DefaultVisualThreshold
+	}
+	gp.visual, gp.visualThreshold = capture, threshold
+}
+
+// SetUnsubscribe enables one-click List-Unsubscribe links in digest emails.
+// The secret must match the one the API server uses to verify tokens.
+func (gp *GlobalPipeline) SetUnsubscribe(baseURL string, secret []byte) {
+	gp.unsubscribeBaseURL = baseURL
+	gp.unsubscribeSecret = secret
+}
+
+// SetWebhookFormatter renders webhook deliveries with a custom formatter,
+// typically a notify.TemplateFormatter matching a downstream schema.
+func (gp *GlobalPipeline) SetWebhookFormatter(f notify.WatchFormatter) {
+	gp.webhookFormatter = f
+}
+
+// SetTracking enables open and click tracking in digest emails. Links are routed
+// through the API's /api/t/ endpoints, signed with the shared secret.
+func (gp *GlobalPipeline) SetTracking(baseURL string, secret []byte) {
+	gp.tracker = notify.NewTracker(baseURL, secret)
+}
+
+// SetDrainTimeout lets in-flight page checks and LLM calls run for up to d
+// after RunCheck's ctx is done, instead of being cut off. No new pages are
+// started and unsent digests are held for the next round either way.
+func (gp *GlobalPipeline) SetDrainTimeout(d time.Duration) {
+	gp.drainTimeout = d
+}
+
+// SetCheckInterval enables per-page scheduling: RunCheck then checks only
+// the pages whose own check interval, or d for pages without one, has passed
+// since their last check. Rounds must run at least as often as the shortest
+// interval. Zero, the default, checks every page each round.
+func (gp *GlobalPipeline) SetCheckInterval(d time.Duration) {
+	gp.checkInterval = d
+}
+
+// duePages returns the pages due for a check at now, see SetCheckInterval.
+func (gp *GlobalPipeline) duePages(pages []PageWithMeta, now time.Time) []PageWithMeta {
+	if gp.checkInterval <= 0 {
+		return pages
+	}
+	var due []PageWithMeta
+	for _, p := range pages {
+		interval := p.CheckInterval
+		if interval <= 0 {
+			interval = gp.checkInterval
+		}
+		if p.LastCheckedAt == nil || now.Sub(*p.LastCheckedAt) >= interval-dueSlack {
+			due = append(due, p)
+		}
+	}
+	return due
+}
+
+// SetProgress registers fn to be called after each page RunCheck checks, with
+// the RunCheck context, so callers can report progress on long rounds.
+func (gp *GlobalPipeline) SetProgress(fn func(ctx context.Context, checked, total int, url string)) {
+	gp.progress = fn
+}
+
+// unsubscribeURL returns the user's signed unsubscribe link, or "" if disabled.
+func (gp *GlobalPipeline) unsubscribeURL(userID int) string {
+	if gp.unsubscribeBaseURL == "" || len(gp.unsubscribeSecret) == 0 {
+		return ""
+	}
+	token := notify.SignUnsubscribeToken(gp.unsubscribeSecret, userID, UnsubscribeList)
+	return notify.UnsubscribeURL(gp.unsubscribeBaseURL, token)
+}
+
+// interruptedRoundKey is the metadata key of the last round cut short by
+// shutdown, see roundState.
+const interruptedRoundKey = "interrupted_round"
+
+// roundState records how far an interrupted round got. The round's pages
+// are all checked again by the next one; its unsent digests are held and go
+// out with the next round.
+type roundState struct {
+	InterruptedAt time.Time `json:"interrupted_at"`
+	PagesChecked  int       `json:"pages_checked"`
+	PagesTotal    int       `json:"pages_total"`
+	DigestsHeld   int       `json:"digests_held"`
+}
+
 // RunCheck executes a full monitoring round: fetch all pages, diff, analyze, notify.
+// When ctx is done the round stops starting new work: the page being checked
+// finishes (see SetDrainTimeout), the digests not yet sent are held for the
+// next round and the round's progress is recorded.
 func (gp *GlobalPipeline) RunCheck(ctx context.Context) error {
+	work, stopWork := gp.workContext(ctx)
+	defer stopWork()
+	persist := context.WithoutCancel(ctx) // saves state after shutdown starts
+
 	// Ensure metadata table exists
-	_ = gp.store.InitMetadata(ctx)
+	_ = gp.store.InitMetadata(work)
+	gp.resumeInterrupted(work)
 
 	// Phase 1: Global fetch (per URL, deduplicated)
-	pages, err := gp.store.GetAllActivePages(ctx)
+	all, err := gp.store.GetAllActivePages(work)
 	if err != nil {
 		return fmt.Errorf("get pages: %w", err)
 	}
+	pages := gp.duePages(all, time.Now())
+
+	gp.logger.Info("starting check", "pages", len(pages), "not_due", len(all)-len(pages))
 
-	gp.logger.Info("starting check", "pages", len(pages))
+	state := roundState{PagesTotal: len(pages)}
+	defer func() {
+		if ctx.Err() != nil {
+			gp.recordInterrupted(persist, state)
+		}
+	}()
 
 	var changesThisRound []Change
-	for _, page := range pages {
-		change, err := gp.checkPage(ctx, page)
+	for i, page := range pages {
+		if ctx.Err() != nil {
+			gp.logger.Warn("check round interrupted", "pages_checked", i, "pages", len(pages))
+			break
+		}
+		change, err := gp.checkPage(work, page)
+		state.PagesChecked = i + 1
+		if gp.progress != nil {
+			gp.progress(work, i+1, len(pages), page.URL)
+		}
 		if err != nil {
+			metrics.PagesChecked.Inc("error")
 			gp.logger.Error("check page failed", "page", page.URL, "error", err)
+			// A failing page waits for its next interval like any other
+			_ = gp.store.UpdateLastChecked(work, page.ID)
 			continue
 		}
 		if change != nil {
+			metrics.PagesChecked.Inc("changed")
 			changesThisRound = append(changesThisRound, *change)
+		} else {
+			metrics.PagesChecked.Inc("unchanged")
 		}
 	}
 
-	gp.logger.Info("phase 1 complete", "pages_checked", len(pages), "changes_detected", len(changesThisRound))
+	gp.logger.Info("phase 1 complete", "pages_checked", state.PagesChecked, "changes_detected", len(changesThisRound))
+	if ctx.Err() != nil {
+		// only holding the round's digests is left to do
+		work = persist
+	}
 
+	now := time.Now()
 	if len(changesThisRound) == 0 {
 		gp.logger.Info("no changes detected")
 		// Check if we should send a weekly heartbeat
-		gp.maybeHeartbeat(ctx)
-		return nil
+		if ctx.Err() == nil {
+			gp.maybeHeartbeat(work)
+		}
+		// Changes held during quiet hours may still be due for delivery
+		if due, _ := gp.store.CountDueHeldChanges(work, now); due == 0 {
+			return nil
+		}
+	} else {
+		// Record that we detected changes (for heartbeat tracking)
+		_ = gp.store.SetMeta(work, "last_change_at", now.Format(time.RFC3339))
 	}
 
-	// Record that we detected changes (for heartbeat tracking)
-	_ = gp.store.SetMeta(ctx, "last_change_at", time.Now().Format(time.RFC3339))
-
 	// Phase 2: Per-user aggregated notifications
-	users, err := gp.store.GetUsersWithCompetitors(ctx)
+	users, err := gp.store.GetUsersWithCompetitors(work)
 	if err != nil {
 		return fmt.Errorf("get users: %w", err)
 	}
 
+	screenshots := make(map[string][]byte) // page URL → PNG, shared across users
+
 	for _, u := range users {
+		// After shutdown starts only holding digests is left to do; it must
+		// succeed even once the drain timeout has passed.
+		uctx := work
+		if ctx.Err() != nil {
+			uctx = persist
+		}
+
 		// 1. Filter changes for this user's competitors
 		userChanges := filterByUser(changesThisRound, u)
-		if len(userChanges) == 0 {
-			continue
+
+		// 2. Apply Smart Alerts: keep, suppress or escalate each change
+		var filteredUserChanges []Change
+		var escalate []notify.Channel
+		if len(userChanges) > 0 {
+			rules, err := gp.store.GetUserAlertRules(uctx, u.ID)
+			if err != nil {
+				gp.logger.Error("failed to get alert rules", "user", u.Email, "error", err)
+				continue
+			}
+			decision := applyAlertRules(userChanges, rules)
+			filteredUserChanges, escalate = decision.changes, decision.escalate
+			if len(filteredUserChanges) == 0 {
+				gp.logger.Info("changes filtered out by smart alerts", "email", u.Email)
+			}
 		}
 
-		// 2. Apply Smart Alerts Filtering
-		rules, err := gp.store.GetUserAlertRules(ctx, u.ID)
+		// 3. Merge changes held from earlier quiet hours that are now due.
+		// Inside quiet hours, take everything held so a critical digest flushes them too.
+		quiet, releaseAt := gp.quietUntil(u, now)
+		cutoff := now
+		if quiet {
+			cutoff = releaseAt
+		}
+		held, err := gp.store.GetDueHeldChanges(uctx, u.ID, cutoff)
 		if err != nil {
-			gp.logger.Error("failed to get alert rules", "user", u.Email, "error", err)
-			continue
+			gp.logger.Warn("failed to get held changes", "user", u.Email, "error", err)
 		}
-
-		filteredUserChanges := filterByAlertRules(userChanges, rules)
+		filteredUserChanges = append(filteredUserChanges, held...)
 		if len(filteredUserChanges) == 0 {
-			gp.logger.Info("changes filtered out by smart alerts", "email", u.Email)
 			continue
 		}
 
-		// Compose one digest message (use WatchBot email formatter)
-		formatter := notify.NewWatchEmailFormatter()
-		msg := ComposeDigest(filteredUserChanges, u, formatter)
-
-		// Send via email
-		if gp.dispatcher != nil && gp.dispatcher.EmailConfig().SMTPHost != "" {
-			emailNotifier := notify.NewEmailNotifierForRecipient(gp.dispatcher.EmailConfig(), u.Email)
-			if err := emailNotifier.Send(ctx, msg); err != nil {
-				gp.logger.Error("email send failed", "email", u.Email, "error", err)
+		// 4. Hold non-critical digests during the user's quiet hours, unless
+		// an alert rule escalated them
+		if quiet && maxSeverity(filteredUserChanges) != "critical" && len(escalate) == 0 {
+			if err := gp.store.HoldChanges(uctx, u.ID, filteredUserChanges, releaseAt); err != nil {
+				gp.logger.Error("failed to hold changes", "user", u.Email, "error", err)
 			} else {
-				gp.logger.Info("digest sent", "email", u.Email, "changes", len(filteredUserChanges))
+				gp.logger.Info("digest held for quiet hours", "email", u.Email, "until", releaseAt)
 			}
-		} else if len(gp.channels) > 0 {
-			// Fallback to dispatcher channels (Telegram)
-			if err := gp.dispatcher.Dispatch(ctx, gp.channels, msg); err != nil {
-				gp.logger.Error("notify failed", "email", u.Email, "error", err)
+			continue
+		}
+
+		// 5. Shutting down: hold the digest so the next round sends it
+		if ctx.Err() != nil {
+			if err := gp.store.HoldChanges(uctx, u.ID, filteredUserChanges, now); err != nil {
+				gp.logger.Error("failed to hold digest at shutdown", "user", u.Email, "error", err)
+			} else {
+				state.DigestsHeld++
 			}
-		} else {
-			// stdout fallback
-			fmt.Printf("\n📧 → %s\n%s\n", u.Email, msg.Body)
+			continue
+		}
+
+		// 6. Send the digests. Changes retried after a failed delivery go out
+		// under the digest ID they were first sent with, so channels that
+		// already received them skip them; the rest form a new digest.
+		recipient := gp.recipientFor(uctx, u)
+		escalated := gp.escalationRoutes(uctx, u, escalate)
+		for _, d := range splitDigests(u.ID, filteredUserChanges) {
+			gp.sendDigest(uctx, u, recipient, escalated, d, held, now, screenshots)
 		}
 	}
 
@@ -150,6 +417,224 @@ func (gp *GlobalPipeline) RunCheck(ctx context.Context) error {
 	return nil
 }
 
+// pendingDigest is one digest about to be sent to a user.
+type pendingDigest struct {
+	id      string
+	changes []Change
+}
+
+// splitDigests groups a user's changes into digests: one per digest ID the
+// held retries were first sent under, in order of appearance, then one for
+// the changes never sent, keyed by digestKey.
+func splitDigests(userID int, changes []Change) []pendingDigest {
+	var digests []pendingDigest
+	index := make(map[string]int)
+	var fresh []Change
+	for _, c := range changes {
+		if c.DigestID == "" {
+			fresh = append(fresh, c)
+			continue
+		}
+		i, ok := index[c.DigestID]
+		if !ok {
+			i = len(digests)
+			index[c.DigestID] = i
+			digests = append(digests, pendingDigest{id: c.DigestID})
+		}
+		digests[i].changes = append(digests[i].changes, c)
+	}
+	if len(fresh) > 0 {
+		digests = append(digests, pendingDigest{id: digestKey(userID, fresh), changes: fresh})
+	}
+	return digests
+}
+
+// sendDigest composes and dispatches one digest. A failed digest is held for
+// a retry under the same ID; a delivered one releases its held changes.
+func (gp *GlobalPipeline) sendDigest(ctx context.Context, u UserWithCompetitors, recipient notify.Recipient, escalated []notify.Route,
+	d pendingDigest, held []Change, now time.Time, screenshots map[string][]byte) {
+	// Compose one digest message (use WatchBot email formatter)
+	formatter := notify.NewWatchEmailFormatter()
+	formatter.InlineDiff = gp.inlineDiffs
+	msg := ComposeDigest(d.changes, u, formatter)
+	applyEmailFormat(&msg, u)
+	if gp.attachDiffs {
+		msg.Attachments = DiffAttachments(d.changes)
+	}
+	if gp.visual != nil {
+		msg.Attachments = append(msg.Attachments, gp.visualAttachments(ctx, d.changes)...)
+	} else if gp.screenshot != nil {
+		msg.Attachments = append(msg.Attachments, gp.screenshotAttachments(ctx, d.changes, screenshots)...)
+	}
+	msg.UnsubscribeURL = gp.unsubscribeURL(u.ID)
+	msg.Bulk = true
+	if gp.webhookFormatter != nil {
+		msg.Payload = ComposeDigest(d.changes, u, gp.webhookFormatter).Payload
+	}
+	if gp.dispatcher.HasChannel(notify.ChannelDiscord) {
+		msg.Embeds = ComposeDigest(d.changes, u, notify.NewWatchDiscordFormatter()).Embeds
+	}
+	if gp.dispatcher.HasChannel(notify.ChannelWeChatWork) {
+		msg.WeChatWork = ComposeDigest(d.changes, u, notify.NewWatchWeChatWorkFormatter()).WeChatWork
+	}
+
+	// The digest ID is shared by every channel
+	msg.IdempotencyKey = d.id
+	if gp.tracker.Enabled() {
+		msg = gp.tracker.Instrument(msg, d.id, u.ID)
+	}
+
+	// Send via the user's preferred channels
+	if len(recipient.Routes) == 0 && len(escalated) == 0 {
+		// stdout fallback: printed counts as delivered
+		fmt.Printf("\n📧 → %s\n%s\n", u.Email, msg.Body)
+		gp.releaseHeld(ctx, u, heldAmong(d.changes, held))
+		return
+	}
+	if err := gp.dispatchDigest(ctx, recipient, escalated, maxSeverity(d.changes), msg); err != nil {
+		gp.logger.Error("notify failed", "email", u.Email, "error", err)
+		// Retry next round with the same digest ID; channels that already
+		// succeeded are skipped by the dispatcher's delivery log.
+		// Changes older than digestRetryWindow are dropped so a broken channel
+		// cannot keep stale changes in every future digest.
+		var retry, stale []Change
+		for _, c := range d.changes {
+			c.DigestID = d.id
+			if now.Sub(c.CreatedAt) < digestRetryWindow {
+				retry = append(retry, c)
+			} else {
+				stale = append(stale, c)
+			}
+		}
+		if err := gp.store.HoldChanges(ctx, u.ID, retry, now.Add(digestRetryDelay)); err != nil {
+			gp.logger.Error("failed to queue digest retry", "user", u.Email, "error", err)
+		}
+		gp.releaseHeld(ctx, u, heldAmong(stale, held))
+	} else {
+		gp.logger.Info("digest sent", "email", u.Email, "changes", len(d.changes))
+		if gp.tracker.Enabled() {
+			if err := gp.store.RecordDelivery(ctx, d.id, u.ID, len(d.changes)); err != nil {
+				gp.logger.Warn("failed to record delivery", "email", u.Email, "error", err)
+			}
+		}
+		gp.releaseHeld(ctx, u, heldAmong(d.changes, held))
+	}
+
+	// Page critical changes by SMS on top of the regular digest
+	gp.maybeSendSMS(ctx, u, recipient.Escalation, d)
+}
+
+// heldAmong returns the changes that are also in held.
+func heldAmong(changes, held []Change) []Change {
+	var result []Change
+	for _, c := range changes {
+		for _, h := range held {
+			if h.ID == c.ID {
+				result = append(result, c)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// releaseHeld removes changes from the user's hold queue.
+func (gp *GlobalPipeline) releaseHeld(ctx context.Context, u UserWithCompetitors, changes []Change) {
+	if len(changes) == 0 {
+		return
+	}
+	if err := gp.store.ReleaseHeldChanges(ctx, u.ID, changes); err != nil {
+		gp.logger.Warn("failed to release held changes", "user", u.Email, "error", err)
+	}
+}
+
+// workContext returns the context for the round's in-flight work. It
+// outlives ctx by the drain timeout, so a page check or LLM call under way
+// at shutdown can finish.
+func (gp *GlobalPipeline) workContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if gp.drainTimeout <= 0 {
+		return ctx, func() {}
+	}
+	work, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		gp.logger.Info("draining check round", "timeout", gp.drainTimeout)
+		time.AfterFunc(gp.drainTimeout, cancel)
+	})
+	return work, func() {
+		stop()
+		cancel()
+	}
+}
+
+// recordInterrupted saves the state of a round cut short by shutdown.
+func (gp *GlobalPipeline) recordInterrupted(ctx context.Context, state roundState) {
+	state.InterruptedAt = time.Now().UTC()
+	data, _ := json.Marshal(state)
+	if err := gp.store.SetMeta(ctx, interruptedRoundKey, string(data)); err != nil {
+		gp.logger.Error("failed to record interrupted round", "error", err)
+		return
+	}
+	gp.logger.Warn("check round interrupted, state saved",
+		"pages_checked", state.PagesChecked, "pages", state.PagesTotal, "digests_held", state.DigestsHeld)
+}
+
+// resumeInterrupted logs and clears the state of an interrupted round. The
+// current round re-checks every page and sends the held digests.
+func (gp *GlobalPipeline) resumeInterrupted(ctx context.Context) {
+	raw, err := gp.store.GetMeta(ctx, interruptedRoundKey)
+	if err != nil || raw == "" {
+		return
+	}
+	var state roundState
+	if err := json.Unmarshal([]byte(raw), &state); err == nil {
+		gp.logger.Info("resuming interrupted check round",
+			"interrupted_at", state.InterruptedAt, "pages_checked", state.PagesChecked,
+			"pages", state.PagesTotal, "digests_held", state.DigestsHeld)
+	}
+	_ = gp.store.SetMeta(ctx, interruptedRoundKey, "")
+}
+
+// Preview renders each user's digest of changes recorded since the given time
+// to disk instead of sending it. Nothing is fetched and no snapshots are written,
+// so it is safe to run against a production database.
+// With several users, the recipient is appended to each file name.
+func (gp *GlobalPipeline) Preview(ctx context.Context, path string, since time.Time) (int, error) {
+	changes, err := gp.store.GetRecentChanges(ctx, since)
+	if err != nil {
+		return 0, fmt.Errorf("get recent changes: %w", err)
+	}
+	users, err := gp.store.GetUsersWithCompetitors(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get users: %w", err)
+	}
+
+	written := 0
+	for _, u := range users {
+		userChanges := filterByUser(changes, u)
+		if rules, err := gp.store.GetUserAlertRules(ctx, u.ID); err == nil {
+			userChanges = applyAlertRules(userChanges, rules).changes
+		}
+		if len(userChanges) == 0 {
+			continue
+		}
+
+		formatter := notify.NewWatchEmailFormatter()
+		formatter.InlineDiff = gp.inlineDiffs
+		msg := ComposeDigest(userChanges, u, formatter)
+		applyEmailFormat(&msg, u)
+		out := path
+		if len(users) > 1 {
+			out = notify.PreviewPath(path, u.Email)
+		}
+		if err := notify.WritePreview(msg, out); err != nil {
+			return written, err
+		}
+		gp.logger.Info("digest preview written", "email", u.Email, "changes", len(userChanges), "path", out)
+		written++
+	}
+	return written, nil
+}
+
 // maybeHeartbeat sends a weekly "service is running, no changes detected" email
 // if no changes have been detected for 7 days.
 func (gp *GlobalPipeline) maybeHeartbeat(ctx context.Context) {
@@ -194,28 +679,30 @@ func (gp *GlobalPipeline) maybeHeartbeat(ctx context.Context) {
 		if len(u.CompetitorNames) == 0 {
 			continue
 		}
+		lang := i18n.LangZH
+		if u.Language != "" {
+			lang = i18n.Language(u.Language)
+		}
+		labels := i18n.GetWatchLabels(lang)
 		msg := notify.Message{
-			Title: fmt.Sprintf("🔍 WatchBot 周报 — %s", now.Format("2006-01-02")),
-			Body: fmt.Sprintf(
-				"📋 竞品监控服务运行正常\n\n"+
-					"最近一周（%s ~ %s），您所监控的竞品网站没有检测到变化：\n\n"+
-					"监控对象：%s\n\n"+
-					"✅ 服务运行正常，WatchBot 每天 3 次（00:00 / 08:00 / 16:00）自动检查以上竞品页面。\n"+
-					"一旦检测到任何变化（定价调整、功能更新、API 变更等），将立即发送详细变更报告到您的邮箱。\n\n"+
-					"— DevKit Suite WatchBot",
-				now.AddDate(0, 0, -7).Format("01月02日"),
-				now.Format("01月02日"),
-				strings.Join(u.CompetitorNames, "、"),
+			Title: "🔍 " + fmt.Sprintf(labels.HeartbeatTitle, now.Format("2006-01-02")),
+			Body: fmt.Sprintf(labels.HeartbeatBody,
+				now.AddDate(0, 0, -7).Format(labels.DateFormat),
+				now.Format(labels.DateFormat),
+				strings.Join(u.CompetitorNames, labels.ListSeparator),
 			),
+			UnsubscribeURL: gp.unsubscribeURL(u.ID),
+			Bulk:           true,
 		}
 
-		if gp.dispatcher != nil && gp.dispatcher.EmailConfig().SMTPHost != "" {
-			emailNotifier := notify.NewEmailNotifierForRecipient(gp.dispatcher.EmailConfig(), u.Email)
-			if err := emailNotifier.Send(ctx, msg); err != nil {
-				gp.logger.Error("heartbeat send failed", "email", u.Email, "error", err)
-			} else {
-				gp.logger.Info("weekly heartbeat sent", "email", u.Email)
-			}
+		recipient := gp.recipientFor(ctx, u)
+		if len(recipient.Routes) == 0 {
+			continue
+		}
+		if err := gp.dispatcher.DispatchTo(ctx, recipient, msg); err != nil {
+			gp.logger.Error("heartbeat send failed", "email", u.Email, "error", err)
+		} else {
+			gp.logger.Info("weekly heartbeat sent", "email", u.Email)
 		}
 	}
 
@@ -224,44 +711,246 @@ func (gp *GlobalPipeline) maybeHeartbeat(ctx context.Context) {
 	gp.logger.Info("weekly heartbeat complete", "users", len(users))
 }
 
+// maxScreenshots caps the PNGs attached to one digest.
+const maxScreenshots = 5
+
+// screenshotAttachments captures each changed page once per round, most severe
+// changes first. Failed captures are logged and skipped.
+func (gp *GlobalPipeline) screenshotAttachments(ctx context.Context, changes []Change, cache map[string][]byte) []notify.Attachment {
+	var result []notify.Attachment
+	seen := make(map[string]bool)
+	for _, c := range bySeverity(changes) {
+		if len(result) >= maxScreenshots {
+			break
+		}
+		if c.PageURL == "" || seen[c.PageURL] {
+			continue
+		}
+		seen[c.PageURL] = true
+
+		png, ok := cache[c.PageURL]
+		if !ok {
+			var err error
+			if png, err = gp.screenshot(ctx, c.PageURL); err != nil {
+				gp.logger.Warn("screenshot failed", "page", c.PageURL, "error", err)
+			}
+			cache[c.PageURL] = png
+		}
+		if png == nil {
+			continue
+		}
+		name := fmt.Sprintf("%s-%s-%d.png", c.CompetitorName, c.PageType, c.ID)
+		result = append(result, notify.Attachment{
+			Filename:    unsafeFilenameChars.ReplaceAllString(name, "_"),
+			ContentType: "image/png",
+			Data:        png,
+		})
+	}
+	return result
+}
+
+// visualAttachments attaches the screenshots before and after each change
+// whose page visibly changed, most severe changes first, for up to
+// maxScreenshots images.
+func (gp *GlobalPipeline) visualAttachments(ctx context.Context, changes []Change) []notify.Attachment {
+	var result []notify.Attachment
+	for _, c := range bySeverity(changes) {
+		if len(result)+2 > maxScreenshots {
+			break
+		}
+		if !c.OldSnapshotID.Valid {
+			continue
+		}
+		before, err := gp.store.GetScreenshot(ctx, int(c.OldSnapshotID.Int64))
+		if err != nil || before == nil {
+			continue
+		}
+		after, err := gp.store.GetScreenshot(ctx, c.NewSnapshotID)
+		if err != nil || after == nil {
+			continue
+		}
+		if v, err := differ.VisualDiff(before, after); err != nil || !v.Changed(gp.visualThreshold) {
+			continue
+		}
+		name := unsafeFilenameChars.ReplaceAllString(fmt.Sprintf("%s-%s-%d", c.CompetitorName, c.PageType, c.ID), "_")
+		result = append(result,
+			notify.Attachment{Filename: name + "-before.png", ContentType: "image/png", Data: before},
+			notify.Attachment{Filename: name + "-after.png", ContentType: "image/png", Data: after},
+		)
+	}
+	return result
+}
+
+// bySeverity returns a copy of changes, most severe first.
+func bySeverity(changes []Change) []Change {
+	sorted := make([]Change, len(changes))
+	copy(sorted, changes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].Severity, sorted[j].Severity
+		return a != b && notify.MaxSeverity(b, a) == a
+	})
+	return sorted
+}
+
+// maybeSendSMS sends a short SMS alert when a user with a phone number has critical changes.
+// The user's escalation policy can disable SMS.
+func (gp *GlobalPipeline) maybeSendSMS(ctx context.Context, u UserWithCompetitors, policy notify.EscalationPolicy, d pendingDigest) {
+	if u.Phone == "" || gp.dispatcher == nil || !gp.dispatcher.HasChannel(notify.ChannelSMS) {
+		return
+	}
+	msg := ComposeDigest(d.changes, u, notify.NewWatchSMSFormatter())
+	if msg.Title == "" {
+		return
+	}
+	msg.IdempotencyKey = d.id
+	recipient := notify.Recipient{
+		ID:         u.Email,
+		Routes:     []notify.Route{{Channel: notify.ChannelSMS, Target: u.Phone}},
+		Escalation: policy,
+	}
+	if err := gp.dispatcher.DispatchSeverity(ctx, recipient, "critical", msg); err != nil {
+		gp.logger.Error("sms alert failed", "email", u.Email, "error", err)
+	}
+}
+
+// recipientFor builds the delivery profile for a user from their notification routes.
+// Users without explicit routes get their notification email, or every globally
+// registered channel when email is not configured.
+func (gp *GlobalPipeline) recipientFor(ctx context.Context, u UserWithCompetitors) notify.Recipient {
+	recipient := notify.Recipient{ID: u.Email}
+	if gp.dispatcher == nil {
+		return recipient
+	}
+
+	routes, err := gp.store.GetUserRoutes(ctx, u.ID)
+	if err != nil {
+		gp.logger.Warn("failed to get notification routes", "user", u.Email, "error", err)
+	}
+	unsubscribed, _ := gp.store.GetUserSetting(ctx, u.ID, "unsubscribe."+UnsubscribeList)
+	emailAllowed := unsubscribed != "true"
+
+	if policy, err := gp.store.GetUserEscalation(ctx, u.ID); err != nil {
+		gp.logger.Warn("invalid escalation policy", "user", u.Email, "error", err)
+	} else {
+		recipient.Escalation = policy
+	}
+
+	if len(routes) > 0 {
+		for _, r := range routes {
+			if r.Channel == notify.ChannelEmail && !emailAllowed {
+				continue
+			}
+			if r.Channel == notify.ChannelEmail && r.Target == "" {
+				r.Target = u.DeliveryEmail()
+			}
+			// SMS is reserved for critical alerts, never full digests
+			if r.Channel == notify.ChannelSMS {
+				continue
+			}
+			if gp.dispatcher.HasChannel(r.Channel) {
+				recipient.Routes = append(recipient.Routes, r)
+			}
+		}
+		return recipient
+	}
+
+	if gp.dispatcher.HasChannel(notify.ChannelEmail) {
+		if emailAllowed {
+			recipient.Routes = []notify.Route{{Channel: notify.ChannelEmail, Target: u.DeliveryEmail()}}
+		}
+		return recipient
+	}
+	for _, ch := range gp.dispatcher.Channels() {
+		if ch == notify.ChannelSMS || (ch == notify.ChannelEmail && !emailAllowed) {
+			continue
+		}
+		recipient.Routes = append(recipient.Routes, notify.Route{Channel: ch})
+	}
+	return recipient
+}
+
 // checkPage fetches a page, diffs against latest snapshot, and returns a Change if detected.
 func (gp *GlobalPipeline) checkPage(ctx context.Context, page PageWithMeta) (*Change, error) {
 	// Fetch
-	result, err := gp.fetcher.Fetch(ctx, page.URL, nil)
+	var opts *scraper.FetchOptions
+	if gp.extractMode != scraper.ExtractModeTags {
+		opts = scraper.DefaultFetchOptions()
+		opts.ExtractMode = gp.extractMode
+	}
+	fetcher, fetcherName := gp.fetcher, "http"
+	if page.Browser {
+		if gp.browser != nil {
+			fetcher, fetcherName = gp.browser, "browser"
+			if opts == nil {
+				opts = scraper.DefaultFetchOptions()
+			}
+			opts.WaitSelector = page.WaitSelector
+			opts.Screenshot = gp.visual != nil && page.Selector == ""
+		} else {
+			gp.logger.Warn("browser rendering not configured, fetching over HTTP", "page", page.URL)
+		}
+	}
+	start := time.Now()
+	result, err := fetcher.Fetch(ctx, page.URL, opts)
 	if err != nil {
+		metrics.FetchDuration.Observe(time.Since(start).Seconds(), fetcherName, "error")
 		return nil, fmt.Errorf("fetch %s: %w", page.URL, err)
 	}
+	metrics.FetchDuration.Observe(time.Since(start).Seconds(), fetcherName, "ok")
+	if result.Truncated {
+		gp.logger.Warn("page body truncated at size limit; diff covers the kept part only", "page", page.URL)
+	}
 
-	currentContent := result.CleanText
+	currentContent, rawHTML := result.CleanText, result.RawHTML
+	if page.Selector != "" && result.DocumentType == scraper.DocumentHTML {
+		// Diff only the selected parts, so banners and footers elsewhere
+		// cannot cause false positives
+		rawHTML, err = scraper.SelectHTML(result.RawHTML, page.Selector)
+		if err != nil {
+			if result.Fallback != "" {
+				err = fmt.Errorf("%w (page is rendered by JavaScript; try browser rendering)", err)
+			}
+			return nil, fmt.Errorf("select %s: %w", page.URL, err)
+		}
+		currentContent = scraper.ExtractTextMode(rawHTML, gp.extractMode)
+	}
 	checksum := fmt.Sprintf("%x", sha256.Sum256([]byte(currentContent)))
+	currentOutline := gp.outline(page, rawHTML)
 
 	// Update last checked
 	_ = gp.store.UpdateLastChecked(ctx, page.ID)
 
 	// Get latest snapshot
-	_, _, prevChecksum, err := gp.store.GetLatestSnapshot(ctx, page.ID)
+	baseSnapID, prevSelector, _, prevChecksum, err := gp.store.GetLatestSnapshot(ctx, page.ID)
 	if err != nil {
 		return nil, err
 	}
+	shot := gp.captureScreenshot(ctx, page, result)
 
-	// First snapshot
-	if prevChecksum == "" {
-		gp.logger.Info("first snapshot", "page", page.CompetitorName, "url", page.URL, "size", len(currentContent))
-		_, _ = gp.store.SaveSnapshot(ctx, page.ID, currentContent, checksum)
+	// First snapshot, or the first since the selector changed
+	if prevChecksum == "" || prevSelector != page.Selector {
+		gp.logger.Info("first snapshot", "page", page.CompetitorName, "url", page.URL, "selector", page.Selector, "size", len(currentContent))
+		snapID, _ := gp.store.SaveSnapshot(ctx, page.ID, page.Selector, currentContent, currentOutline, checksum)
+		gp.saveScreenshot(ctx, page, snapID, shot)
 		return nil, nil
 	}
+	visual := gp.visualDiff(ctx, page, baseSnapID, shot)
 
 	// No changes
-	if checksum == prevChecksum {
+	if checksum == prevChecksum && visual == nil {
 		gp.logger.Info("no changes", "page", page.CompetitorName)
 		return nil, nil
 	}
 
 	// Save new snapshot
-	newSnapID, _ := gp.store.SaveSnapshot(ctx, page.ID, currentContent, checksum)
+	newSnapID, _ := gp.store.SaveSnapshot(ctx, page.ID, page.Selector, currentContent, currentOutline, checksum)
+	gp.saveScreenshot(ctx, page, newSnapID, shot)
+	if checksum == prevChecksum {
+		return gp.saveVisualChange(ctx, page, baseSnapID, newSnapID, visual), nil
+	}
 
 	// Get previous content for diff
-	prevSnapID, prevContent, _, _ := gp.store.GetLatestSnapshot(ctx, page.ID)
+	prevSnapID, _, prevContent, _, _ := gp.store.GetLatestSnapshot(ctx, page.ID)
 	// Note: after saving new, "latest" is the new one. We need the one before.
 	// Actually we should get prev before saving. Let me fix the logic:
 	// We already checked prevChecksum != "" and checksum != prevChecksum.
@@ -278,20 +967,43 @@ func (gp *GlobalPipeline) checkPage(ctx context.Context, page PageWithMeta) (*Ch
 	// Actually let's fix: we should diff the old content with current content.
 	// Let me query the second-to-last snapshot.
 	row := gp.store.db.QueryRowContext(ctx,
-		`SELECT id, content FROM snapshots WHERE page_id = ? ORDER BY captured_at DESC LIMIT 1 OFFSET 1`,
+		`SELECT id, content, outline FROM snapshots WHERE page_id = ? ORDER BY captured_at DESC LIMIT 1 OFFSET 1`,
 		page.ID)
 	var oldSnapID int
-	var oldContent string
-	if err := row.Scan(&oldSnapID, &oldContent); err != nil {
+	var oldContent, oldOutline string
+	if err := row.Scan(&oldSnapID, &oldContent, &oldOutline); err != nil {
 		// Can't find old snapshot, skip
 		return nil, nil
 	}
 
 	// Diff
-	diff := differ.TextDiff(oldContent, currentContent)
+	textDiff := differ.TextDiff
+	if gp.semanticDiff {
+		textDiff = differ.SemanticDiff
+	}
+	var diff differ.DiffResult
+	if result.DocumentType == scraper.DocumentJSON {
+		// API responses are compared field by field; the old snapshot may
+		// predate the endpoint returning JSON
+		if diff, err = differ.JSONDiff([]byte(oldContent), []byte(currentContent)); err != nil {
+			gp.logger.Warn("JSON diff failed, comparing as text", "page", page.URL, "error", err)
+			diff = textDiff(oldContent, currentContent)
+		}
+	} else {
+		diff = textDiff(oldContent, currentContent)
+	}
 	if !diff.HasChanges {
+		if visual != nil {
+			return gp.saveVisualChange(ctx, page, oldSnapID, newSnapID, visual), nil
+		}
 		return nil, nil
 	}
+	if oldOutline != "" && currentOutline != "" {
+		var oldBlocks, newBlocks []differ.Block
+		if json.Unmarshal([]byte(oldOutline), &oldBlocks) == nil && json.Unmarshal([]byte(currentOutline), &newBlocks) == nil {
+			diff.Structure = differ.StructureDiff(oldBlocks, newBlocks)
+		}
+	}
 
 	gp.logger.Info("changes detected",
 		"page", page.CompetitorName,
@@ -325,19 +1037,146 @@ func (gp *GlobalPipeline) checkPage(ctx context.Context, page PageWithMeta) (*Ch
 	}, nil
 }
 
+// captureScreenshot returns a PNG of the page for visual diffs: the browser
+// fetcher's render when it took one, else a new capture. It returns nil when
+// visual diffs are off, the page has a selector or the capture failed.
+func (gp *GlobalPipeline) captureScreenshot(ctx context.Context, page PageWithMeta, result *scraper.FetchResult) []byte {
+	if gp.visual == nil || page.Selector != "" {
+		return nil
+	}
+	if result.Screenshot != nil {
+		return result.Screenshot
+	}
+	png, err := gp.visual(ctx, page.URL)
+	if err != nil {
+		gp.logger.Warn("screenshot failed", "page", page.URL, "error", err)
+		return nil
+	}
+	return png
+}
+
+func (gp *GlobalPipeline) saveScreenshot(ctx context.Context, page PageWithMeta, snapID int, png []byte) {
+	if png == nil || snapID == 0 {
+		return
+	}
+	if err := gp.store.SaveScreenshot(ctx, page.ID, snapID, png); err != nil {
+		gp.logger.Warn("failed to save screenshot", "page", page.URL, "error", err)
+	}
+}
+
+// visualDiff compares shot with the screenshot of the page's latest
+// snapshot and returns the difference when it reaches the threshold. A
+// snapshot taken before visual diffs were enabled gets shot as its baseline.
+func (gp *GlobalPipeline) visualDiff(ctx context.Context, page PageWithMeta, baseSnapID int, shot []byte) *differ.VisualResult {
+	if shot == nil {
+		return nil
+	}
+	base, err := gp.store.GetScreenshot(ctx, baseSnapID)
+	if err != nil {
+		gp.logger.Warn("failed to load screenshot", "page", page.URL, "error", err)
+		return nil
+	}
+	if base == nil {
+		gp.saveScreenshot(ctx, page, baseSnapID, shot)
+		return nil
+	}
+	v, err := differ.VisualDiff(base, shot)
+	if err != nil {
+		gp.logger.Warn("visual diff failed", "page", page.URL, "error", err)
+		return nil
+	}
+	if !v.Changed(gp.visualThreshold) {
+		return nil
+	}
+	return v
+}
+
+// saveVisualChange records a change of the page's look alone. Without text
+// to analyze it is minor; alert rules can escalate it.
+func (gp *GlobalPipeline) saveVisualChange(ctx context.Context, page PageWithMeta, oldSnapID, newSnapID int, visual *differ.VisualResult) *Change {
+	gp.logger.Info("visual change detected", "page", page.CompetitorName, "url", page.URL, "ratio", visual.Ratio)
+	analysis := visual.Summary()
+	changeID, _ := gp.store.SaveChange(ctx, page.ID, oldSnapID, newSnapID, "minor", analysis, "", 0, 0)
+	return &Change{
+		ID:             changeID,
+		PageID:         page.ID,
+		OldSnapshotID:  sql.NullInt64{Int64: int64(oldSnapID), Valid: oldSnapID > 0},
+		NewSnapshotID:  newSnapID,
+		Severity:       "minor",
+		Analysis:       analysis,
+		CreatedAt:      time.Now(),
+		CompetitorID:   page.CompetitorID,
+		CompetitorName: page.CompetitorName,
+		PageURL:        page.URL,
+		PageType:       page.PageType,
+		UserID:         page.UserID,
+	}
+}
+
+// outline returns the JSON outline of a page's HTML, or "" when structure
+// diffs are off or the page is not HTML.
+func (gp *GlobalPipeline) outline(page PageWithMeta, rawHTML string) string {
+	if !gp.structureDiff || rawHTML == "" {
+		return ""
+	}
+	blocks, err := differ.Outline(rawHTML)
+	if err != nil {
+		gp.logger.Warn("outline failed", "page", page.URL, "error", err)
+		return ""
+	}
+	data, err := json.Marshal(blocks)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
 // analyzeDiff uses LLM to analyze a change.
 func (gp *GlobalPipeline) analyzeDiff(ctx context.Context, page PageWithMeta, diff differ.DiffResult) (string, string) {
 	if gp.llmClient == nil {
 		return diff.Summary(), "important"
 	}
 
+	// Word-level edits make small changes such as prices stand out; JSON
+	// changes already say old → new per field
+	var inline strings.Builder
+	if len(diff.JSON) > 0 {
+		inline.WriteString("\nJSON 字段变化（路径: 旧值 → 新值）：\n")
+		for _, c := range diff.JSON[:min(len(diff.JSON), 40)] {
+			inline.WriteString(c.Describe() + "\n")
+		}
+	} else if len(diff.Changes) > 0 {
+		inline.WriteString("\n行内变化（[-删除-]{+新增+}）：\n")
+		for _, c := range diff.Changes[:min(len(diff.Changes), 20)] {
+			inline.WriteString(c.Inline() + "\n")
+		}
+	}
+	// Structural changes say where on the page the edits are
+	if len(diff.Structure) > 0 {
+		inline.WriteString("\n结构变化：\n")
+		for _, c := range diff.Structure[:min(len(diff.Structure), 20)] {
+			inline.WriteString(c.Describe() + "\n")
+		}
+	}
+	// Moved text is not in the diff above; only moves between sections
+	// matter
+	var moved []string
+	for _, m := range diff.Moved {
+		if m.Crossed() && len(moved) < 20 {
+			moved = append(moved, m.Describe())
+		}
+	}
+	if len(moved) > 0 {
+		inline.WriteString("\n移动的内容：\n" + strings.Join(moved, "\n") + "\n")
+	}
+
 	prompt := fmt.Sprintf(`分析 "%s"（%s 页面）的变更，直接列出核心变化。
 
 变更统计：+%d / -%d 行
 
 Diff：
 %s
-
+%s
 【严格格式要求】
 1. 禁止写任何前缀、开场白、总结语（如"分析如下""总结"等），第一个字必须是"•"
 2. 用 2-5 个 • 要点列出最重要的具体变化
@@ -347,13 +1186,19 @@ Diff：
 		page.CompetitorName, page.PageType,
 		diff.Stats.Additions, diff.Stats.Deletions,
 		truncate(diff.Unified, 4000),
+		truncate(inline.String(), 3000),
 	)
 
-	resp, err := gp.llmClient.Generate(ctx, &llm.Request{
+	resp, err := gp.llmClient.Generate(llm.ForUser(ctx, page.UserID, "watchbot.analysis"), &llm.Request{
 		Messages:    []llm.Message{{Role: "user", Content: prompt}},
 		MaxTokens:   8192,
 		Temperature: 0.3,
 	})
+	if errors.Is(err, llm.ErrQuotaExceeded) {
+		// Over quota or budget: report the plain diff until next month
+		gp.logger.Info("LLM analysis skipped", "page", page.CompetitorName, "user", page.UserID, "reason", err)
+		return diff.Summary(), "important"
+	}
 	if err != nil {
 		gp.logger.Warn("LLM analysis failed", "error", err)
 		return diff.Summary(), "important"
@@ -408,54 +1253,18 @@ func filterByUser(changes []Change, user UserWithCompetitors) []Change {
 	return result
 }
 
+// maxSeverity returns the highest severity among changes.
+func maxSeverity(changes []Change) string {
+	levels := make([]string, len(changes))
+	for i, c := range changes {
+		levels[i] = c.Severity
+	}
+	return notify.MaxSeverity(levels...)
+}
+
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
 		return s
 	}
 	return s[:maxLen] + "\n... (truncated)"
 }
-
-// filterByAlertRules evaluates whether changes should trigger a notification based on user-defined Smart Alerts.
-// If the user has no active rules, we apply the default behavior: alert on everything.
-// If rules are defined, a change is included only if it matches at least one active rule.
-func filterByAlertRules(changes []Change, rules []AlertRule) []Change {
-	if len(rules) == 0 {
-		return changes // Default: no rules means notify on everything
-	}
-
-	var filtered []Change
-	for _, c := range changes {
-		matched := false
-		for _, r := range rules {
-			// Step 1: Check if rule applies to this competitor (if specified)
-			if r.CompetitorID != nil && *r.CompetitorID != c.CompetitorID {
-				continue // Skip: rule is for another competitor
-			}
-
-			// Step 2: Check rule logic
-			switch strings.ToLower(r.RuleType) {
-			case "severity":
-				// E.g. RuleValue == "critical|important" or exact match
-				targetSev := strings.ToLower(r.RuleValue)
-				changeSev := strings.ToLower(c.Severity)
-				if strings.Contains(targetSev, changeSev) || targetSev == changeSev {
-					matched = true
-				}
-			case "keyword":
-				keyword := strings.ToLower(r.RuleValue)
-				if strings.Contains(strings.ToLower(c.Analysis), keyword) ||
-					strings.Contains(strings.ToLower(c.DiffUnified), keyword) {
-					matched = true
-				}
-			}
-
-			if matched {
-				break // One matching rule is sufficient to trigger the alert for this change
-			}
-		}
-		if matched {
-			filtered = append(filtered, c)
-		}
-	}
-	return filtered
-}
@@ -0,0 +1,392 @@
+package watchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/notify"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
+)
+
+// newTestStore returns a store on a migrated SQLite database that is removed
+// with the test.
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	db, err := storage.Open(storage.Config{Driver: storage.SQLite, DSN: filepath.Join(t.TempDir(), "watchbot.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return NewStore(db)
+}
+
+// testPage adds a user with one competitor page and returns the user, as
+// the pipeline sees them, and the page ID.
+func testPage(t *testing.T, s *Store, email string) (UserWithCompetitors, int) {
+	t.Helper()
+	ctx := context.Background()
+	userID, err := s.ensureUser(ctx, email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compID, err := s.AddCompetitor(ctx, userID, "Acme", "acme.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pageID, err := s.AddPage(ctx, compID, "https://acme.example/pricing", "pricing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := UserWithCompetitors{ID: userID, Email: email, CompetitorIDs: []int{compID}, CompetitorNames: []string{"Acme"}}
+	return u, pageID
+}
+
+// testChange records a change on the page and returns it as the digest
+// sees it.
+func testChange(t *testing.T, s *Store, pageID int, summary string) Change {
+	t.Helper()
+	ctx := context.Background()
+	oldSnap, err := s.SaveSnapshot(ctx, pageID, "", "old "+summary, "", "old-"+summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newSnap, err := s.SaveSnapshot(ctx, pageID, "", "new "+summary, "", "new-"+summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := s.SaveChange(ctx, pageID, oldSnap, newSnap, "major", summary, "", 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := s.GetRecentChanges(ctx, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range changes {
+		if c.ID == id {
+			return c
+		}
+	}
+	t.Fatalf("change %d not found", id)
+	return Change{}
+}
+
+// recordingNotifier records the digest IDs it is sent and fails while fail
+// is set.
+type recordingNotifier struct {
+	fail bool
+	keys []string
+}
+
+func (n *recordingNotifier) Channel() notify.Channel { return notify.ChannelTelegram }
+
+func (n *recordingNotifier) Send(_ context.Context, msg notify.Message) error {
+	if n.fail {
+		return errors.New("channel down")
+	}
+	n.keys = append(n.keys, msg.IdempotencyKey)
+	return nil
+}
+
+func TestDigestRetryWithNewChange(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	u, pageID := testPage(t, s, "retry@example.com")
+
+	notifier := &recordingNotifier{fail: true}
+	d := notify.NewDispatcher()
+	d.Register(notifier)
+	d.SetDeliveryLog(s)
+	gp := NewGlobalPipeline(s, nil, nil, d)
+	recipient := notify.Recipient{ID: u.Email, Routes: []notify.Route{{Channel: notify.ChannelTelegram}}}
+	now := time.Now()
+
+	// The first digest fails and is held for a retry under its ID
+	first := testChange(t, s, pageID, "price raised")
+	digests := splitDigests(u.ID, []Change{first})
+	if len(digests) != 1 || digests[0].id != digestKey(u.ID, []Change{first}) {
+		t.Fatalf("first round digests = %+v", digests)
+	}
+	firstID := digests[0].id
+	gp.sendDigest(ctx, u, recipient, nil, digests[0], nil, now, nil)
+
+	held, err := s.GetDueHeldChanges(ctx, u.ID, now.Add(digestRetryDelay))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(held) != 1 || held[0].ID != first.ID || held[0].DigestID != firstID {
+		t.Fatalf("held = %+v, want change %d under %s", held, first.ID, firstID)
+	}
+
+	// The retry keeps its ID; a change detected meanwhile gets its own
+	second := testChange(t, s, pageID, "plan renamed")
+	digests = splitDigests(u.ID, append([]Change{second}, held...))
+	if len(digests) != 2 {
+		t.Fatalf("retry round digests = %+v", digests)
+	}
+	if digests[0].id != firstID || len(digests[0].changes) != 1 || digests[0].changes[0].ID != first.ID {
+		t.Errorf("retried digest = %+v, want change %d under %s", digests[0], first.ID, firstID)
+	}
+	secondID := digestKey(u.ID, []Change{second})
+	if digests[1].id != secondID || len(digests[1].changes) != 1 || digests[1].changes[0].ID != second.ID {
+		t.Errorf("new digest = %+v, want change %d under %s", digests[1], second.ID, secondID)
+	}
+
+	notifier.fail = false
+	for _, dg := range digests {
+		gp.sendDigest(ctx, u, recipient, nil, dg, held, now.Add(digestRetryDelay), nil)
+	}
+	if len(notifier.keys) != 2 || notifier.keys[0] != firstID || notifier.keys[1] != secondID {
+		t.Errorf("sent digest IDs = %v, want [%s %s]", notifier.keys, firstID, secondID)
+	}
+	if held, _ := s.GetDueHeldChanges(ctx, u.ID, now.Add(24*time.Hour)); len(held) != 0 {
+		t.Errorf("held after delivery = %+v, want none", held)
+	}
+
+	// Sending the retried digest again reaches no channel twice
+	gp.sendDigest(ctx, u, recipient, nil, digests[0], nil, now, nil)
+	if len(notifier.keys) != 2 {
+		t.Errorf("redelivered: %v", notifier.keys)
+	}
+}
+
+func TestRecipientFor(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	u, _ := testPage(t, s, "routes@example.com")
+	u.NotificationEmail = "alerts@example.com"
+
+	withEmail := notify.NewDispatcher()
+	withEmail.Register(&recordingNotifier{})
+	withEmail.SetEmailConfig(notify.EmailConfig{})
+	withoutEmail := notify.NewDispatcher()
+	withoutEmail.Register(&recordingNotifier{})
+	withoutEmail.Register(notify.NewSMSNotifier(notify.TwilioConfig{}))
+
+	email := notify.Route{Channel: notify.ChannelEmail, Target: "alerts@example.com"}
+	telegram := notify.Route{Channel: notify.ChannelTelegram}
+	tests := []struct {
+		name         string
+		d            *notify.Dispatcher
+		routes       []notify.Route
+		unsubscribed bool
+		want         []notify.Route
+	}{
+		{
+			name: "explicit routes",
+			d:    withEmail,
+			routes: []notify.Route{
+				telegram,
+				{Channel: notify.ChannelEmail},
+				{Channel: notify.ChannelSMS, Target: "+15550100"},
+				{Channel: notify.ChannelSlack},
+			},
+			want: []notify.Route{telegram, email},
+		},
+		{
+			name:         "explicit routes, unsubscribed",
+			d:            withEmail,
+			routes:       []notify.Route{telegram, {Channel: notify.ChannelEmail, Target: "other@example.com"}},
+			unsubscribed: true,
+			want:         []notify.Route{telegram},
+		},
+		{name: "notification email", d: withEmail, want: []notify.Route{email}},
+		{name: "notification email, unsubscribed", d: withEmail, unsubscribed: true},
+		{name: "no email configured", d: withoutEmail, want: []notify.Route{telegram}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.SetUserRoutes(ctx, u.ID, tt.routes); err != nil {
+				t.Fatal(err)
+			}
+			unsubscribed := "false"
+			if tt.unsubscribed {
+				unsubscribed = "true"
+			}
+			if err := s.SetUserSetting(ctx, u.ID, "unsubscribe."+UnsubscribeList, unsubscribed); err != nil {
+				t.Fatal(err)
+			}
+
+			gp := NewGlobalPipeline(s, nil, nil, tt.d)
+			got := gp.recipientFor(ctx, u)
+			if got.ID != u.Email || fmt.Sprint(got.Routes) != fmt.Sprint(tt.want) {
+				t.Errorf("recipient = %+v, want routes %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTeamScope(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	alice, alicePage := testPage(t, s, "alice@example.com")
+	bob, bobPage := testPage(t, s, "bob@example.com")
+	carol, carolPage := testPage(t, s, "carol@example.com")
+
+	// Alice and Bob share a team; Carol is in a team of her own
+	for _, team := range [][]int{{alice.ID, bob.ID}, {carol.ID}} {
+		teamID, err := s.db.InsertID(ctx, `INSERT INTO teams (name, created_by) VALUES (?, ?)`, "team", team[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, userID := range team {
+			if _, err := s.db.ExecContext(ctx, `INSERT INTO team_members (team_id, user_id, role) VALUES (?, ?, 'member')`, teamID, userID); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	bobChange := testChange(t, s, bobPage, "bob pricing")
+	carolChange := testChange(t, s, carolPage, "carol pricing")
+	testChange(t, s, alicePage, "alice pricing")
+
+	visible, err := s.ListVisibleCompetitors(ctx, alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var owners []int
+	for _, c := range visible {
+		owners = append(owners, c.UserID)
+	}
+	if fmt.Sprint(owners) != fmt.Sprint([]int{alice.ID, bob.ID}) {
+		t.Errorf("alice sees competitors of %v, want %v", owners, []int{alice.ID, bob.ID})
+	}
+	if own, _ := s.ListCompetitorsByUser(ctx, alice.ID); len(own) != 1 || own[0].UserID != alice.ID {
+		t.Errorf("alice's own competitors = %+v", own)
+	}
+	if carols, _ := s.ListVisibleCompetitors(ctx, carol.ID); len(carols) != 1 || carols[0].UserID != carol.ID {
+		t.Errorf("carol sees %+v, want her own competitor only", carols)
+	}
+
+	if c, err := s.GetChangeForUser(ctx, bobChange.ID, alice.ID); err != nil || c == nil || c.UserID != bob.ID {
+		t.Errorf("alice reading bob's change = %+v, %v; want it, owned by bob", c, err)
+	}
+	if c, err := s.GetChangeForUser(ctx, carolChange.ID, alice.ID); err != nil || c != nil {
+		t.Errorf("alice reading carol's change = %+v, %v; want nothing", c, err)
+	}
+
+	hits, err := s.Search(ctx, alice.ID, "pricing", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages := map[int]bool{}
+	for _, h := range hits {
+		pages[h.PageID] = true
+	}
+	if !pages[alicePage] || !pages[bobPage] || pages[carolPage] {
+		t.Errorf("alice's search found pages %v, want %d and %d only", pages, alicePage, bobPage)
+	}
+}
+
+func TestUserRoutesSealed(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	kek, err := storage.NewAESKeyWrapper(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetSecretBox(storage.NewSecretBox(kek))
+	userID, err := s.ensureUser(ctx, "alice@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A webhook target stored before sealing was enabled is read as it is
+	const legacy = "https://hooks.example.com/legacy"
+	if _, err := s.db.ExecContext(ctx, `INSERT INTO notification_routes (user_id, channel, target) VALUES (?, 'webhook', ?)`, userID, legacy); err != nil {
+		t.Fatal(err)
+	}
+	routes, err := s.GetUserRoutes(ctx, userID)
+	if err != nil || len(routes) != 1 || routes[0].Target != legacy {
+		t.Fatalf("legacy route = %+v, %v", routes, err)
+	}
+
+	// Webhook targets are sealed on write; other channels are not
+	want := []notify.Route{
+		{Channel: notify.ChannelWebhook, Target: "https://hooks.example.com/secret"},
+		{Channel: notify.ChannelEmail, Target: "alice@example.com"},
+	}
+	if err := s.SetUserRoutes(ctx, userID, want); err != nil {
+		t.Fatal(err)
+	}
+	stored := map[string]string{}
+	rows, err := s.db.QueryContext(ctx, `SELECT channel, target FROM notification_routes WHERE user_id = ?`, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+		var ch, target string
+		if err := rows.Scan(&ch, &target); err != nil {
+			t.Fatal(err)
+		}
+		stored[ch] = target
+	}
+	rows.Close()
+	if !storage.IsSealed(stored["webhook"]) || stored["email"] != "alice@example.com" {
+		t.Errorf("stored targets = %v, want the webhook sealed and the email plain", stored)
+	}
+	routes, err = s.GetUserRoutes(ctx, userID)
+	if err != nil || fmt.Sprint(routes) != fmt.Sprint(want) {
+		t.Errorf("routes = %+v, %v; want %+v", routes, err, want)
+	}
+
+	// Without the key sealed targets cannot be read
+	s.SetSecretBox(nil)
+	if _, err := s.GetUserRoutes(ctx, userID); !errors.Is(err, storage.ErrNoSecretBox) {
+		t.Errorf("reading without a key: %v, want ErrNoSecretBox", err)
+	}
+}
+
+func TestDuePages(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	page := func(id int, checkedAgo, interval time.Duration) PageWithMeta {
+		p := PageWithMeta{Page: Page{ID: id, CheckInterval: interval}}
+		if checkedAgo >= 0 {
+			last := now.Add(-checkedAgo)
+			p.LastCheckedAt = &last
+		}
+		return p
+	}
+	const never = -1
+
+	tests := []struct {
+		name     string
+		interval time.Duration // pipeline default
+		page     PageWithMeta
+		due      bool
+	}{
+		{"never checked", time.Hour, page(1, never, 0), true},
+		{"just checked", time.Hour, page(1, time.Minute, 0), false},
+		{"interval passed", time.Hour, page(1, 2*time.Hour, 0), true},
+		{"within slack of the interval", time.Hour, page(1, time.Hour-dueSlack, 0), true},
+		{"just outside the slack", time.Hour, page(1, time.Hour-dueSlack-time.Second, 0), false},
+		{"custom interval not yet passed", time.Hour, page(1, 2*time.Hour, 6*time.Hour), false},
+		{"custom interval passed", time.Hour, page(1, 6*time.Hour, 6*time.Hour), true},
+		{"custom interval shorter than the default", time.Hour, page(1, 10*time.Minute, 10*time.Minute), true},
+		{"scheduling off checks every page", 0, page(1, time.Minute, 6*time.Hour), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gp := &GlobalPipeline{}
+			gp.SetCheckInterval(tt.interval)
+			due := gp.duePages([]PageWithMeta{tt.page}, now)
+			if got := len(due) == 1; got != tt.due {
+				t.Errorf("due = %v, want %v", got, tt.due)
+			}
+		})
+	}
+
+	// Pages keep their order and only the due ones are returned
+	gp := &GlobalPipeline{}
+	gp.SetCheckInterval(time.Hour)
+	due := gp.duePages([]PageWithMeta{page(1, never, 0), page(2, time.Minute, 0), page(3, 3*time.Hour, 0)}, now)
+	if len(due) != 2 || due[0].ID != 1 || due[1].ID != 3 {
+		t.Errorf("duePages = %+v, want pages 1 and 3", due)
+	}
+}
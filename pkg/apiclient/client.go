@@ -0,0 +1,122 @@
+// Package apiclient is a typed Go client for the DevKit Suite REST API.
+//
+// The request and response types and one Client method per endpoint are
+// generated into client_gen.go from the OpenAPI document the API server
+// serves at /openapi.json. Run go generate in this directory after changing
+// a route in internal/api.
+package apiclient
+
+//go:generate go run gen.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client calls the API on behalf of one user.
+type Client struct {
+	baseURL  string
+	token    string
+	language string
+	http     *http.Client
+}
+
+// New creates a client for the API at baseURL, e.g. "http://localhost:8080".
+func New(baseURL string) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SetToken sets the JWT sent as a bearer token, as returned by Login and
+// Register.
+func (c *Client) SetToken(token string) {
+	c.token = token
+}
+
+// SetLanguage sets the Accept-Language sent with requests, which selects
+// the language of error messages.
+func (c *Client) SetLanguage(lang string) {
+	c.language = lang
+}
+
+// SetHTTPClient replaces the HTTP client, e.g. to change the timeout.
+func (c *Client) SetHTTPClient(hc *http.Client) {
+	c.http = hc
+}
+
+// Error is an error response from the API.
+type Error struct {
+	StatusCode int
+	Message    string
+	// RetryAfter is how long to wait before retrying a rate-limited (429)
+	// request, from the Retry-After header.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("api: %d %s", e.StatusCode, e.Message)
+}
+
+// do sends a request with an optional JSON body and decodes the JSON
+// response into out.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.language != "" {
+		req.Header.Set("Accept-Language", c.language)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var e ErrorResponse
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&e)
+		if e.Error == "" {
+			e.Error = http.StatusText(resp.StatusCode)
+		}
+		apiErr := &Error{StatusCode: resp.StatusCode, Message: e.Error}
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			apiErr.RetryAfter = time.Duration(secs) * time.Second
+		}
+		return apiErr
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s %s: %w", method, path, err)
+	}
+	return nil
+}
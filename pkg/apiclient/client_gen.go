@@ -0,0 +1,1015 @@
+// Code generated by gen.go from the API's OpenAPI document; DO NOT EDIT.
+
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type AcceptInviteRequest struct {
+	Token string `json:"token"`
+}
+
+type AcceptInviteResponse struct {
+	Message string `json:"message"`
+	Role    string `json:"role"`
+	TeamID  int    `json:"team_id"`
+}
+
+type AddAlertRuleRequest struct {
+	Action       string `json:"action"`
+	CompetitorID *int   `json:"competitor_id"`
+	RuleType     string `json:"rule_type"`
+	RuleValue    string `json:"rule_value"`
+}
+
+type AddAlertRuleResponse struct {
+	Message string `json:"message"`
+	RuleID  int    `json:"rule_id"`
+}
+
+type AddCompetitorRequest struct {
+	Browser       bool   `json:"browser,omitempty"`
+	CheckInterval string `json:"check_interval,omitempty"`
+	Domain        string `json:"domain"`
+	Name          string `json:"name"`
+	PageType      string `json:"page_type"`
+	Selector      string `json:"selector,omitempty"`
+	URL           string `json:"url"`
+	WaitSelector  string `json:"wait_selector,omitempty"`
+}
+
+type AlertRule struct {
+	Action       string    `json:"action"`
+	CompetitorID *int      `json:"competitor_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	ID           int       `json:"id"`
+	RuleType     string    `json:"rule_type"`
+	RuleValue    string    `json:"rule_value"`
+	UserID       int       `json:"user_id"`
+}
+
+type AlertRulesResponse struct {
+	Rules []AlertRule `json:"rules"`
+}
+
+type AuthResponse struct {
+	Message string `json:"message"`
+	Plan    string `json:"plan,omitempty"`
+	Token   string `json:"token"`
+	UserID  int    `json:"user_id"`
+}
+
+type BudgetRequest struct {
+	MonthlyBudget float64 `json:"monthly_budget"`
+}
+
+type Change struct {
+	Additions      int       `json:"Additions"`
+	Analysis       string    `json:"Analysis"`
+	CompetitorID   int       `json:"CompetitorID"`
+	CompetitorName string    `json:"CompetitorName"`
+	CreatedAt      time.Time `json:"CreatedAt"`
+	Deletions      int       `json:"Deletions"`
+	DiffUnified    string    `json:"DiffUnified"`
+	DigestID       string    `json:"DigestID"`
+	ID             int       `json:"ID"`
+	NewSnapshotID  int       `json:"NewSnapshotID"`
+	OldSnapshotID  NullInt64 `json:"OldSnapshotID"`
+	PageID         int       `json:"PageID"`
+	PageType       string    `json:"PageType"`
+	PageURL        string    `json:"PageURL"`
+	Severity       string    `json:"Severity"`
+	UserID         int       `json:"UserID"`
+}
+
+type ChangeDiffResponse struct {
+	Competitor string    `json:"competitor"`
+	CreatedAt  time.Time `json:"created_at"`
+	HTML       string    `json:"html"`
+	ID         int       `json:"id"`
+	Layout     string    `json:"layout"`
+	PageURL    string    `json:"page_url"`
+	Severity   string    `json:"severity"`
+	Stats      Stats     `json:"stats"`
+}
+
+type CheckoutRequest struct {
+	PriceID string `json:"price_id"`
+}
+
+type CheckoutResponse struct {
+	URL string `json:"url"`
+}
+
+type Competitor struct {
+	CreatedAt time.Time `json:"CreatedAt"`
+	Domain    string    `json:"Domain"`
+	ID        int       `json:"ID"`
+	Name      string    `json:"Name"`
+	UserID    int       `json:"UserID"`
+}
+
+type CompetitorsResponse struct {
+	Competitors []Competitor `json:"competitors"`
+}
+
+type CreateInviteResponse struct {
+	AcceptURL string  `json:"accept_url"`
+	Emailed   bool    `json:"emailed"`
+	Invite    *Invite `json:"invite"`
+}
+
+type CreateTeamRequest struct {
+	Name string `json:"name"`
+}
+
+type CreateTeamResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+type DBStatsResponse struct {
+	Driver string    `json:"driver"`
+	Pool   PoolStats `json:"pool"`
+}
+
+type DashboardCompetitor struct {
+	Domain             string     `json:"domain"`
+	ID                 int        `json:"id"`
+	LatestChangeTime   *time.Time `json:"latest_change_time"`
+	LatestSeverity     string     `json:"latest_severity"`
+	Name               string     `json:"name"`
+	PagesTracked       int        `json:"pages_tracked"`
+	RecentAlertSnippet string     `json:"recent_alert_snippet"`
+}
+
+type DashboardResponse struct {
+	Competitors []DashboardCompetitor `json:"competitors"`
+}
+
+type EngagementResponse struct {
+	Days        int              `json:"days"`
+	Subscribers []EngagementStat `json:"subscribers"`
+}
+
+type EngagementStat struct {
+	Clicked     int        `json:"clicked"`
+	Deliveries  int        `json:"deliveries"`
+	Email       string     `json:"email"`
+	LastEventAt *time.Time `json:"last_event_at"`
+	LastSentAt  *time.Time `json:"last_sent_at"`
+	Opened      int        `json:"opened"`
+	UserID      int        `json:"user_id"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+type FeatureUsage struct {
+	Calls     int     `json:"calls"`
+	Cost      float64 `json:"cost"`
+	Feature   string  `json:"feature"`
+	TokensIn  int     `json:"tokens_in"`
+	TokensOut int     `json:"tokens_out"`
+}
+
+type Invite struct {
+	CreatedAt time.Time `json:"created_at"`
+	Email     string    `json:"email"`
+	ExpiresAt time.Time `json:"expires_at"`
+	ID        int       `json:"id"`
+	InvitedBy string    `json:"invited_by"`
+	Role      string    `json:"role"`
+	TeamID    int       `json:"team_id"`
+	TeamName  string    `json:"team_name"`
+}
+
+type InviteInfoResponse struct {
+	Invite *Invite `json:"invite"`
+}
+
+type InviteRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type InviteWithToken struct {
+	CreatedAt time.Time `json:"created_at"`
+	Email     string    `json:"email"`
+	ExpiresAt time.Time `json:"expires_at"`
+	ID        int       `json:"id"`
+	InvitedBy string    `json:"invited_by"`
+	Role      string    `json:"role"`
+	TeamID    int       `json:"team_id"`
+	TeamName  string    `json:"team_name"`
+	Token     string    `json:"token"`
+}
+
+type InvitesResponse struct {
+	Invites []Invite `json:"invites"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type MembersResponse struct {
+	Members []TeamMember `json:"members"`
+}
+
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+type MonthUsage struct {
+	Cost          float64        `json:"cost"`
+	Features      []FeatureUsage `json:"features"`
+	LLMDisabled   bool           `json:"llm_disabled"`
+	Month         string         `json:"month"`
+	MonthlyBudget float64        `json:"monthly_budget"`
+	MonthlyQuota  int            `json:"monthly_quota"`
+	Plan          string         `json:"plan"`
+	Tokens        int            `json:"tokens"`
+}
+
+type MyInvitesResponse struct {
+	Invites []InviteWithToken `json:"invites"`
+}
+
+type NewsBotSubscribeRequest struct {
+	Languages  string `json:"languages"`
+	TargetID   string `json:"target_id"`
+	TargetType string `json:"target_type"`
+}
+
+type NewsBotSubscriptionsResponse struct {
+	Subscriptions []Subscriber `json:"subscriptions"`
+}
+
+type NewsFeedResponse struct {
+	Feed              []NewsItem `json:"feed"`
+	IsSubscribed      bool       `json:"is_subscribed"`
+	SubscriptionLangs string     `json:"subscription_langs"`
+	Subscriptions     any        `json:"subscriptions"`
+}
+
+type NewsItem struct {
+	ID          int       `json:"id"`
+	PublishedAt time.Time `json:"published_at"`
+	Source      string    `json:"source"`
+	Summary     string    `json:"summary"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+}
+
+type NullInt64 struct {
+	Int64 int64 `json:"Int64"`
+	Valid bool  `json:"Valid"`
+}
+
+type OnboardingRequest struct {
+	Industry string `json:"industry"`
+}
+
+type OnboardingResponse struct {
+	Message          string `json:"message"`
+	ProvisionedCount int    `json:"provisioned_count"`
+}
+
+type PoolStats struct {
+	Idle              int   `json:"idle"`
+	InUse             int   `json:"in_use"`
+	MaxIdleClosed     int64 `json:"max_idle_closed"`
+	MaxLifetimeClosed int64 `json:"max_lifetime_closed"`
+	MaxOpen           int   `json:"max_open"`
+	Open              int   `json:"open"`
+	WaitCount         int64 `json:"wait_count"`
+	WaitDurationNs    int64 `json:"wait_duration_ns"`
+}
+
+type ProfileResponse struct {
+	Company           string            `json:"company"`
+	Email             string            `json:"email"`
+	EmailFormat       string            `json:"email_format"`
+	Escalation        map[string]string `json:"escalation,omitempty"`
+	Language          string            `json:"language"`
+	Name              string            `json:"name"`
+	NotificationEmail string            `json:"notification_email"`
+	Phone             string            `json:"phone"`
+	Plan              string            `json:"plan"`
+	QuietHours        string            `json:"quiet_hours"`
+	Timezone          string            `json:"timezone"`
+	UserID            int               `json:"user_id"`
+}
+
+type RegisterRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type Route struct {
+	Channel string `json:"channel"`
+	Target  string `json:"target,omitempty"`
+}
+
+type RoutesResponse struct {
+	Routes []Route `json:"routes"`
+}
+
+type SearchHit struct {
+	CompetitorID   int       `json:"competitor_id"`
+	CompetitorName string    `json:"competitor_name"`
+	CreatedAt      time.Time `json:"created_at"`
+	ID             int       `json:"id"`
+	Kind           string    `json:"kind"`
+	PageID         int       `json:"page_id"`
+	PageType       string    `json:"page_type"`
+	PageURL        string    `json:"page_url"`
+	Severity       string    `json:"severity,omitempty"`
+	Snippet        string    `json:"snippet"`
+}
+
+type SearchResponse struct {
+	Query   string      `json:"query"`
+	Results []SearchHit `json:"results"`
+}
+
+type SetRoutesRequest struct {
+	Routes []Route `json:"routes"`
+}
+
+type Stats struct {
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+	Moves     int `json:"moves,omitempty"`
+}
+
+type Subscriber struct {
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	ID         int       `json:"id"`
+	Languages  string    `json:"languages"`
+	TargetID   string    `json:"target_id"`
+	TargetType string    `json:"target_type"`
+	UserID     int       `json:"user_id"`
+}
+
+type Team struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role,omitempty"`
+}
+
+type TeamMember struct {
+	Email    string    `json:"email"`
+	JoinedAt time.Time `json:"joined_at"`
+	Name     string    `json:"name"`
+	Role     string    `json:"role"`
+	UserID   int       `json:"user_id"`
+}
+
+type TeamsResponse struct {
+	Teams []Team `json:"teams"`
+}
+
+type TimelineResponse struct {
+	Competitor *Competitor `json:"competitor"`
+	Timeline   []Change    `json:"timeline"`
+}
+
+type TrendPoint struct {
+	Date  string  `json:"date"`
+	Score float64 `json:"score"`
+}
+
+type TrendReport struct {
+	Benchmark   string        `json:"benchmark"`
+	BenchmarkID string        `json:"benchmark_id"`
+	Series      []TrendSeries `json:"series"`
+	Unit        string        `json:"unit"`
+	Variant     string        `json:"variant,omitempty"`
+}
+
+type TrendSeries struct {
+	Model    string       `json:"model"`
+	Points   []TrendPoint `json:"points"`
+	Provider string       `json:"provider"`
+}
+
+type UnsubscribeResponse struct {
+	List         string `json:"list"`
+	Message      string `json:"message,omitempty"`
+	Unsubscribed bool   `json:"unsubscribed"`
+}
+
+type UpdateProfileRequest struct {
+	Company           *string            `json:"company"`
+	EmailFormat       *string            `json:"email_format"`
+	Escalation        *map[string]string `json:"escalation"`
+	Language          *string            `json:"language"`
+	Name              *string            `json:"name"`
+	NotificationEmail *string            `json:"notification_email"`
+	Phone             *string            `json:"phone"`
+	QuietHours        *string            `json:"quiet_hours"`
+	Timezone          *string            `json:"timezone"`
+}
+
+type UsageDay struct {
+	Calls     int     `json:"calls"`
+	Cost      float64 `json:"cost"`
+	Day       string  `json:"day"`
+	TokensIn  int     `json:"tokens_in"`
+	TokensOut int     `json:"tokens_out"`
+}
+
+type UsageReport struct {
+	Days         []UsageDay `json:"days"`
+	MonthCost    float64    `json:"month_cost"`
+	MonthTokens  int        `json:"month_tokens"`
+	MonthlyQuota int        `json:"monthly_quota"`
+	Plan         string     `json:"plan"`
+}
+
+// AcceptInvite calls POST /api/invites/accept: Join the team of an invite.
+func (c *Client) AcceptInvite(ctx context.Context, req *AcceptInviteRequest) (*AcceptInviteResponse, error) {
+	path := "/api/invites/accept"
+	query := url.Values{}
+	var out AcceptInviteResponse
+	if err := c.do(ctx, "POST", path, query, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AddAlertRule calls POST /api/watchbot/rules: Add an alert rule.
+func (c *Client) AddAlertRule(ctx context.Context, req *AddAlertRuleRequest) (*AddAlertRuleResponse, error) {
+	path := "/api/watchbot/rules"
+	query := url.Values{}
+	var out AddAlertRuleResponse
+	if err := c.do(ctx, "POST", path, query, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AddCompetitor calls POST /api/watchbot/competitors: Track a competitor page.
+func (c *Client) AddCompetitor(ctx context.Context, req *AddCompetitorRequest) (*MessageResponse, error) {
+	path := "/api/watchbot/competitors"
+	query := url.Values{}
+	var out MessageResponse
+	if err := c.do(ctx, "POST", path, query, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateCheckoutSession calls POST /api/billing/create-checkout-session: Start a Stripe Checkout for a plan.
+func (c *Client) CreateCheckoutSession(ctx context.Context, req *CheckoutRequest) (*CheckoutResponse, error) {
+	path := "/api/billing/create-checkout-session"
+	query := url.Values{}
+	var out CheckoutResponse
+	if err := c.do(ctx, "POST", path, query, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateInvite calls POST /api/teams/{id}/invites: Invite an email address to a team (admins only).
+func (c *Client) CreateInvite(ctx context.Context, id int, req *InviteRequest) (*CreateInviteResponse, error) {
+	path := fmt.Sprintf("/api/teams/%d/invites", id)
+	query := url.Values{}
+	var out CreateInviteResponse
+	if err := c.do(ctx, "POST", path, query, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateTeam calls POST /api/teams: Create a team with the user as admin.
+func (c *Client) CreateTeam(ctx context.Context, req *CreateTeamRequest) (*CreateTeamResponse, error) {
+	path := "/api/teams"
+	query := url.Values{}
+	var out CreateTeamResponse
+	if err := c.do(ctx, "POST", path, query, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteNewsBotSubscriptionParams are the query parameters of DeleteNewsBotSubscription.
+type DeleteNewsBotSubscriptionParams struct {
+	// Subscription ID
+	ID int
+}
+
+// DeleteNewsBotSubscription calls DELETE /api/newsbot/subscriptions: Delete a NewsBot subscription.
+func (c *Client) DeleteNewsBotSubscription(ctx context.Context, params *DeleteNewsBotSubscriptionParams) (*MessageResponse, error) {
+	path := "/api/newsbot/subscriptions"
+	query := url.Values{}
+	if params != nil {
+		if params.ID != 0 {
+			query.Set("id", strconv.Itoa(params.ID))
+		}
+	}
+	var out MessageResponse
+	if err := c.do(ctx, "DELETE", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBenchmarkTrendParams are the query parameters of GetBenchmarkTrend.
+type GetBenchmarkTrendParams struct {
+	// Benchmark ID or name, e.g. gpqa_diamond
+	Benchmark string
+	// Benchmark variant (default the first)
+	Variant string
+	// Models, comma-separated (default every model with history)
+	Model string
+	// Response format; png is a line chart (default json): json, png
+	Format string
+}
+
+// GetBenchmarkTrend calls GET /api/benchmarks/trend: Get models' score history on a benchmark.
+func (c *Client) GetBenchmarkTrend(ctx context.Context, params *GetBenchmarkTrendParams) (*TrendReport, error) {
+	path := "/api/benchmarks/trend"
+	query := url.Values{}
+	if params != nil {
+		if params.Benchmark != "" {
+			query.Set("benchmark", params.Benchmark)
+		}
+		if params.Variant != "" {
+			query.Set("variant", params.Variant)
+		}
+		if params.Model != "" {
+			query.Set("model", params.Model)
+		}
+		if params.Format != "" {
+			query.Set("format", params.Format)
+		}
+	}
+	var out TrendReport
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetChangeDiffParams are the query parameters of GetChangeDiff.
+type GetChangeDiffParams struct {
+	// Diff layout (default side-by-side): side-by-side, inline
+	Layout string
+}
+
+// GetChangeDiff calls GET /api/watchbot/changes/{id}/diff: Render a change's diff as HTML.
+func (c *Client) GetChangeDiff(ctx context.Context, id int, params *GetChangeDiffParams) (*ChangeDiffResponse, error) {
+	path := fmt.Sprintf("/api/watchbot/changes/%d/diff", id)
+	query := url.Values{}
+	if params != nil {
+		if params.Layout != "" {
+			query.Set("layout", params.Layout)
+		}
+	}
+	var out ChangeDiffResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCompetitorTimeline calls GET /api/watchbot/competitor/{id}: Get a competitor's change timeline.
+func (c *Client) GetCompetitorTimeline(ctx context.Context, id int) (*TimelineResponse, error) {
+	path := fmt.Sprintf("/api/watchbot/competitor/%d", id)
+	query := url.Values{}
+	var out TimelineResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDBStats calls GET /api/admin/db-stats: Database connection pool statistics (admins only).
+func (c *Client) GetDBStats(ctx context.Context) (*DBStatsResponse, error) {
+	path := "/api/admin/db-stats"
+	query := url.Values{}
+	var out DBStatsResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDashboard calls GET /api/watchbot/dashboard: Get competitors with their latest change.
+func (c *Client) GetDashboard(ctx context.Context) (*DashboardResponse, error) {
+	path := "/api/watchbot/dashboard"
+	query := url.Values{}
+	var out DashboardResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetEngagementParams are the query parameters of GetEngagement.
+type GetEngagementParams struct {
+	// Window in days (default 90)
+	Days int
+}
+
+// GetEngagement calls GET /api/watchbot/engagement: Digest engagement per subscriber (admins only).
+func (c *Client) GetEngagement(ctx context.Context, params *GetEngagementParams) (*EngagementResponse, error) {
+	path := "/api/watchbot/engagement"
+	query := url.Values{}
+	if params != nil {
+		if params.Days != 0 {
+			query.Set("days", strconv.Itoa(params.Days))
+		}
+	}
+	var out EngagementResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetInviteInfoParams are the query parameters of GetInviteInfo.
+type GetInviteInfoParams struct {
+	// Signed token from the link
+	Token string
+}
+
+// GetInviteInfo calls GET /api/invites/info: Describe the invite behind an invite link.
+func (c *Client) GetInviteInfo(ctx context.Context, params *GetInviteInfoParams) (*InviteInfoResponse, error) {
+	path := "/api/invites/info"
+	query := url.Values{}
+	if params != nil {
+		if params.Token != "" {
+			query.Set("token", params.Token)
+		}
+	}
+	var out InviteInfoResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetLLMUsageParams are the query parameters of GetLLMUsage.
+type GetLLMUsageParams struct {
+	// Window in days (default 30, at most 366)
+	Days int
+	// Another user's usage (admins only)
+	UserID int
+}
+
+// GetLLMUsage calls GET /api/usage/llm: Get LLM token usage and the plan quota.
+func (c *Client) GetLLMUsage(ctx context.Context, params *GetLLMUsageParams) (*UsageReport, error) {
+	path := "/api/usage/llm"
+	query := url.Values{}
+	if params != nil {
+		if params.Days != 0 {
+			query.Set("days", strconv.Itoa(params.Days))
+		}
+		if params.UserID != 0 {
+			query.Set("user_id", strconv.Itoa(params.UserID))
+		}
+	}
+	var out UsageReport
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMe calls GET /api/users/me: Get the signed-in user's account and profile.
+func (c *Client) GetMe(ctx context.Context) (*ProfileResponse, error) {
+	path := "/api/users/me"
+	query := url.Values{}
+	var out ProfileResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetNewsFeedParams are the query parameters of GetNewsFeed.
+type GetNewsFeedParams struct {
+	// Feed language, e.g. zh
+	Lang string
+}
+
+// GetNewsFeed calls GET /api/newsbot/feed: Get the news feed.
+func (c *Client) GetNewsFeed(ctx context.Context, params *GetNewsFeedParams) (*NewsFeedResponse, error) {
+	path := "/api/newsbot/feed"
+	query := url.Values{}
+	if params != nil {
+		if params.Lang != "" {
+			query.Set("lang", params.Lang)
+		}
+	}
+	var out NewsFeedResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetRoutes calls GET /api/watchbot/routes: Get the user's digest delivery channels.
+func (c *Client) GetRoutes(ctx context.Context) (*RoutesResponse, error) {
+	path := "/api/watchbot/routes"
+	query := url.Values{}
+	var out RoutesResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUnsubscribeParams are the query parameters of GetUnsubscribe.
+type GetUnsubscribeParams struct {
+	// Signed token from the link
+	Token string
+}
+
+// GetUnsubscribe calls GET /api/unsubscribe: Check an unsubscribe link without acting on it.
+func (c *Client) GetUnsubscribe(ctx context.Context, params *GetUnsubscribeParams) (*UnsubscribeResponse, error) {
+	path := "/api/unsubscribe"
+	query := url.Values{}
+	if params != nil {
+		if params.Token != "" {
+			query.Set("token", params.Token)
+		}
+	}
+	var out UnsubscribeResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUsageParams are the query parameters of GetUsage.
+type GetUsageParams struct {
+	// Another user's usage (admins only)
+	UserID int
+}
+
+// GetUsage calls GET /api/usage: Get this month's LLM spend by feature against the quota and budget.
+func (c *Client) GetUsage(ctx context.Context, params *GetUsageParams) (*MonthUsage, error) {
+	path := "/api/usage"
+	query := url.Values{}
+	if params != nil {
+		if params.UserID != 0 {
+			query.Set("user_id", strconv.Itoa(params.UserID))
+		}
+	}
+	var out MonthUsage
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAlertRules calls GET /api/watchbot/rules: List the user's alert rules.
+func (c *Client) ListAlertRules(ctx context.Context) (*AlertRulesResponse, error) {
+	path := "/api/watchbot/rules"
+	query := url.Values{}
+	var out AlertRulesResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListCompetitors calls GET /api/watchbot/competitors: List the competitors of the user and their teammates.
+func (c *Client) ListCompetitors(ctx context.Context) (*CompetitorsResponse, error) {
+	path := "/api/watchbot/competitors"
+	query := url.Values{}
+	var out CompetitorsResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListMyInvites calls GET /api/invites: List invites sent to the user's email.
+func (c *Client) ListMyInvites(ctx context.Context) (*MyInvitesResponse, error) {
+	path := "/api/invites"
+	query := url.Values{}
+	var out MyInvitesResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListNewsBotSubscriptions calls GET /api/newsbot/subscriptions: List your NewsBot subscriptions.
+func (c *Client) ListNewsBotSubscriptions(ctx context.Context) (*NewsBotSubscriptionsResponse, error) {
+	path := "/api/newsbot/subscriptions"
+	query := url.Values{}
+	var out NewsBotSubscriptionsResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListTeamInvites calls GET /api/teams/{id}/invites: List a team's pending invites (admins only).
+func (c *Client) ListTeamInvites(ctx context.Context, id int) (*InvitesResponse, error) {
+	path := fmt.Sprintf("/api/teams/%d/invites", id)
+	query := url.Values{}
+	var out InvitesResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListTeamMembers calls GET /api/teams/{id}/members: List a team's members.
+func (c *Client) ListTeamMembers(ctx context.Context, id int) (*MembersResponse, error) {
+	path := fmt.Sprintf("/api/teams/%d/members", id)
+	query := url.Values{}
+	var out MembersResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListTeams calls GET /api/teams: List the user's teams.
+func (c *Client) ListTeams(ctx context.Context) (*TeamsResponse, error) {
+	path := "/api/teams"
+	query := url.Values{}
+	var out TeamsResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Login calls POST /api/auth/login: Sign in and get a token.
+func (c *Client) Login(ctx context.Context, req *LoginRequest) (*AuthResponse, error) {
+	path := "/api/auth/login"
+	query := url.Values{}
+	var out AuthResponse
+	if err := c.do(ctx, "POST", path, query, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Onboard calls POST /api/onboarding: Add template competitors for an industry.
+func (c *Client) Onboard(ctx context.Context, req *OnboardingRequest) (*OnboardingResponse, error) {
+	path := "/api/onboarding"
+	query := url.Values{}
+	var out OnboardingResponse
+	if err := c.do(ctx, "POST", path, query, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Register calls POST /api/auth/register: Create an account and sign in.
+func (c *Client) Register(ctx context.Context, req *RegisterRequest) (*AuthResponse, error) {
+	path := "/api/auth/register"
+	query := url.Values{}
+	var out AuthResponse
+	if err := c.do(ctx, "POST", path, query, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RemoveTeamMember calls DELETE /api/teams/{id}/members/{userID}: Remove a member, or leave the team.
+func (c *Client) RemoveTeamMember(ctx context.Context, id int, userID int) (*MessageResponse, error) {
+	path := fmt.Sprintf("/api/teams/%d/members/%d", id, userID)
+	query := url.Values{}
+	var out MessageResponse
+	if err := c.do(ctx, "DELETE", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RevokeInvite calls DELETE /api/teams/{id}/invites/{inviteID}: Revoke a pending invite (admins only).
+func (c *Client) RevokeInvite(ctx context.Context, id int, inviteID int) (*MessageResponse, error) {
+	path := fmt.Sprintf("/api/teams/%d/invites/%d", id, inviteID)
+	query := url.Values{}
+	var out MessageResponse
+	if err := c.do(ctx, "DELETE", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SearchParams are the query parameters of Search.
+type SearchParams struct {
+	// Words that must all appear
+	Q string
+	// Maximum results (default 50, at most 200)
+	Limit int
+}
+
+// Search calls GET /api/watchbot/search: Search page snapshots and change analyses, oldest first.
+func (c *Client) Search(ctx context.Context, params *SearchParams) (*SearchResponse, error) {
+	path := "/api/watchbot/search"
+	query := url.Values{}
+	if params != nil {
+		if params.Q != "" {
+			query.Set("q", params.Q)
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+	}
+	var out SearchResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetLLMBudgetParams are the query parameters of SetLLMBudget.
+type SetLLMBudgetParams struct {
+	// Another user's budget (admins only)
+	UserID int
+}
+
+// SetLLMBudget calls PUT /api/usage/budget: Set the monthly LLM budget; past it, changes are reported without analysis.
+func (c *Client) SetLLMBudget(ctx context.Context, params *SetLLMBudgetParams, req *BudgetRequest) (*MonthUsage, error) {
+	path := "/api/usage/budget"
+	query := url.Values{}
+	if params != nil {
+		if params.UserID != 0 {
+			query.Set("user_id", strconv.Itoa(params.UserID))
+		}
+	}
+	var out MonthUsage
+	if err := c.do(ctx, "PUT", path, query, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetRoutes calls PUT /api/watchbot/routes: Replace the user's digest delivery channels.
+func (c *Client) SetRoutes(ctx context.Context, req *SetRoutesRequest) (*MessageResponse, error) {
+	path := "/api/watchbot/routes"
+	query := url.Values{}
+	var out MessageResponse
+	if err := c.do(ctx, "PUT", path, query, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SubscribeNewsBot calls POST /api/newsbot/subscribe: Subscribe a target to the NewsBot digest.
+func (c *Client) SubscribeNewsBot(ctx context.Context, req *NewsBotSubscribeRequest) (*MessageResponse, error) {
+	path := "/api/newsbot/subscribe"
+	query := url.Values{}
+	var out MessageResponse
+	if err := c.do(ctx, "POST", path, query, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UnsubscribeParams are the query parameters of Unsubscribe.
+type UnsubscribeParams struct {
+	// Signed token from the link
+	Token string
+}
+
+// Unsubscribe calls POST /api/unsubscribe: Unsubscribe from a mailing list (RFC 8058 one-click).
+func (c *Client) Unsubscribe(ctx context.Context, params *UnsubscribeParams) (*UnsubscribeResponse, error) {
+	path := "/api/unsubscribe"
+	query := url.Values{}
+	if params != nil {
+		if params.Token != "" {
+			query.Set("token", params.Token)
+		}
+	}
+	var out UnsubscribeResponse
+	if err := c.do(ctx, "POST", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateProfile calls PUT /api/users/profile: Change profile fields; fields left out keep their value.
+func (c *Client) UpdateProfile(ctx context.Context, req *UpdateProfileRequest) (*ProfileResponse, error) {
+	path := "/api/users/profile"
+	query := url.Values{}
+	var out ProfileResponse
+	if err := c.do(ctx, "PUT", path, query, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,305 @@
+//go:build ignore
+
+// gen writes client_gen.go from the API server's OpenAPI document: a Go type
+// for every component schema and a Client method for every operation with a
+// JSON response.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/RobinCoderZhao/devkit-suite/internal/api"
+)
+
+type document struct {
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components struct {
+		Schemas map[string]*schema `json:"schemas"`
+	} `json:"components"`
+}
+
+type operation struct {
+	OperationID string `json:"operationId"`
+	Summary     string `json:"summary"`
+	Parameters  []struct {
+		Name        string  `json:"name"`
+		In          string  `json:"in"`
+		Description string  `json:"description"`
+		Schema      *schema `json:"schema"`
+	} `json:"parameters"`
+	RequestBody *struct {
+		Content map[string]struct {
+			Schema *schema `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]struct {
+		Content map[string]struct {
+			Schema *schema `json:"schema"`
+		} `json:"content"`
+	} `json:"responses"`
+
+	method, path string
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 any                `json:"type"` // "string" or ["string", "null"]
+	Format               string             `json:"format"`
+	Items                *schema            `json:"items"`
+	AdditionalProperties *schema            `json:"additionalProperties"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	OneOf                []*schema          `json:"oneOf"`
+	Enum                 []string           `json:"enum"`
+}
+
+func main() {
+	raw, err := json.Marshal(api.OpenAPI())
+	if err != nil {
+		log.Fatal(err)
+	}
+	var doc document
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		log.Fatal(err)
+	}
+
+	g := &generator{}
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		g.structType(name, doc.Components.Schemas[name])
+	}
+
+	var ops []*operation
+	for p, methods := range doc.Paths {
+		for m, op := range methods {
+			op.method, op.path = strings.ToUpper(m), p
+			ops = append(ops, op)
+		}
+	}
+	slices.SortFunc(ops, func(a, b *operation) int { return strings.Compare(a.OperationID, b.OperationID) })
+	for _, op := range ops {
+		g.method(op)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by gen.go from the API's OpenAPI document; DO NOT EDIT.\n\npackage apiclient\n\nimport (\n")
+	for _, pkg := range []string{"context", "fmt", "net/url", "strconv", "time"} {
+		if bytes.Contains(g.buf.Bytes(), []byte(pkg[strings.LastIndex(pkg, "/")+1:]+".")) {
+			fmt.Fprintf(&out, "%q\n", pkg)
+		}
+	}
+	out.WriteString(")\n\n")
+	out.Write(g.buf.Bytes())
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		log.Fatalf("format: %v\n%s", err, out.Bytes())
+	}
+	if err := os.WriteFile("client_gen.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type generator struct {
+	buf bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) structType(name string, s *schema) {
+	g.printf("type %s struct {\n", name)
+	props := make([]string, 0, len(s.Properties))
+	for p := range s.Properties {
+		props = append(props, p)
+	}
+	slices.Sort(props)
+	for _, p := range props {
+		typ := goType(s.Properties[p])
+		tag := p
+		if !slices.Contains(s.Required, p) && !strings.HasPrefix(typ, "*") {
+			tag += ",omitempty"
+		}
+		g.printf("%s %s `json:%q`\n", ident(p), typ, tag)
+	}
+	g.printf("}\n\n")
+}
+
+func (g *generator) method(op *operation) {
+	var status, out string
+	for code, resp := range op.Responses {
+		if c, ok := resp.Content["application/json"]; ok && code != "default" {
+			status, out = code, goType(c.Schema)
+		}
+	}
+	if status == "" {
+		return // not a JSON endpoint: pixels, redirects, webhooks
+	}
+	name := ident(op.OperationID)
+
+	args := []string{"ctx context.Context"}
+	pathFmt := op.path
+	var pathArgs []string
+	type queryParam struct{ name, field, typ, doc string }
+	var query []queryParam
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "path":
+			args = append(args, p.Name+" int")
+			pathFmt = strings.Replace(pathFmt, "{"+p.Name+"}", "%d", 1)
+			pathArgs = append(pathArgs, p.Name)
+		case "query":
+			doc := p.Description
+			if len(p.Schema.Enum) > 0 {
+				doc += ": " + strings.Join(p.Schema.Enum, ", ")
+			}
+			query = append(query, queryParam{p.Name, ident(p.Name), goType(p.Schema), doc})
+		}
+	}
+	if len(query) > 0 {
+		g.printf("// %sParams are the query parameters of %s.\ntype %[1]sParams struct {\n", name, name)
+		for _, q := range query {
+			if q.doc != "" {
+				g.printf("// %s\n", q.doc)
+			}
+			g.printf("%s %s\n", q.field, q.typ)
+		}
+		g.printf("}\n\n")
+		args = append(args, "params *"+name+"Params")
+	}
+	body := "nil"
+	if op.RequestBody != nil {
+		args = append(args, "req *"+goType(op.RequestBody.Content["application/json"].Schema))
+		body = "req"
+	}
+
+	g.printf("// %s calls %s %s: %s.\n", name, op.method, op.path, op.Summary)
+	g.printf("func (c *Client) %s(%s) (*%s, error) {\n", name, strings.Join(args, ", "), out)
+	if len(pathArgs) > 0 {
+		g.printf("path := fmt.Sprintf(%q, %s)\n", pathFmt, strings.Join(pathArgs, ", "))
+	} else {
+		g.printf("path := %q\n", op.path)
+	}
+	g.printf("query := url.Values{}\n")
+	if len(query) > 0 {
+		g.printf("if params != nil {\n")
+		for _, q := range query {
+			switch q.typ {
+			case "int":
+				g.printf("if params.%s != 0 {\nquery.Set(%q, strconv.Itoa(params.%[1]s))\n}\n", q.field, q.name)
+			default:
+				g.printf("if params.%s != \"\" {\nquery.Set(%q, params.%[1]s)\n}\n", q.field, q.name)
+			}
+		}
+		g.printf("}\n")
+	}
+	g.printf("var out %s\n", out)
+	g.printf("if err := c.do(ctx, %q, path, query, %s, &out); err != nil {\nreturn nil, err\n}\n", op.method, body)
+	g.printf("return &out, nil\n}\n\n")
+}
+
+// goType maps a schema to the Go type encoding/json decodes it into.
+func goType(s *schema) string {
+	if s.Ref != "" {
+		return s.Ref[strings.LastIndex(s.Ref, "/")+1:]
+	}
+	for _, alt := range s.OneOf {
+		if alt.Ref != "" {
+			return "*" + goType(alt)
+		}
+	}
+	typ, nullable := "", false
+	switch t := s.Type.(type) {
+	case string:
+		typ = t
+	case []any:
+		for _, v := range t {
+			if v == "null" {
+				nullable = true
+			} else {
+				typ, _ = v.(string)
+			}
+		}
+	}
+	var gt string
+	switch {
+	case typ == "string" && s.Format == "date-time":
+		gt = "time.Time"
+	case typ == "string" && s.Format == "byte":
+		gt = "[]byte"
+	case typ == "string":
+		gt = "string"
+	case typ == "integer" && s.Format == "int64":
+		gt = "int64"
+	case typ == "integer":
+		gt = "int"
+	case typ == "number":
+		gt = "float64"
+	case typ == "boolean":
+		gt = "bool"
+	case typ == "array":
+		gt = "[]" + goType(s.Items)
+	case typ == "object" && s.AdditionalProperties != nil:
+		gt = "map[string]" + goType(s.AdditionalProperties)
+	default:
+		return "any"
+	}
+	if nullable {
+		return "*" + gt
+	}
+	return gt
+}
+
+var initialisms = map[string]string{"id": "ID", "url": "URL", "html": "HTML", "llm": "LLM", "db": "DB", "api": "API"}
+
+// ident turns a JSON name or operationId into an exported Go identifier:
+// "user_id" → "UserID", "getLLMUsage" → "GetLLMUsage".
+func ident(name string) string {
+	var b strings.Builder
+	for _, w := range words(name) {
+		if up, ok := initialisms[strings.ToLower(w)]; ok {
+			b.WriteString(up)
+			continue
+		}
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return b.String()
+}
+
+// words splits snake_case, kebab-case and camelCase names into words,
+// keeping runs of capitals together: "getLLMUsage" → get, LLM, Usage.
+func words(name string) []string {
+	var out []string
+	r := []rune(name)
+	start := 0
+	for i := 0; i <= len(r); i++ {
+		split := i == len(r) || r[i] == '_' || r[i] == '-'
+		if !split && i > start && unicode.IsUpper(r[i]) {
+			lowerBefore := !unicode.IsUpper(r[i-1])
+			lowerAfter := i+1 < len(r) && unicode.IsLower(r[i+1])
+			if lowerBefore || lowerAfter {
+				out = append(out, string(r[start:i]))
+				start = i
+			}
+		}
+		if split {
+			if i > start {
+				out = append(out, string(r[start:i]))
+			}
+			start = i + 1
+		}
+	}
+	return out
+}
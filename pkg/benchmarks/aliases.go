@@ -0,0 +1,215 @@
+package benchmarks
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The alias table maps model names as leaderboards write them (lowercase)
+// to tracked model names. It comes from the config's aliases section and
+// may be swapped at runtime by WatchAliases while parsers read it.
+var (
+	aliasMu      sync.RWMutex
+	modelAliases = map[string]string{}
+)
+
+// SetModelAliases replaces the alias table. Keys are matched case-insensitively.
+func SetModelAliases(aliases map[string]string) {
+	table := make(map[string]string, len(aliases))
+	for raw, name := range aliases {
+		table[strings.ToLower(strings.TrimSpace(raw))] = name
+	}
+	aliasMu.Lock()
+	modelAliases = table
+	aliasMu.Unlock()
+}
+
+// ModelAlias returns the tracked model name for a lowercase leaderboard name.
+func ModelAlias(rawLower string) (string, bool) {
+	aliasMu.RLock()
+	defer aliasMu.RUnlock()
+	name, ok := modelAliases[rawLower]
+	return name, ok
+}
+
+// ReloadAliases re-reads only the aliases section of the config at path.
+func ReloadAliases(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config %s: %w", path, err)
+	}
+	var cfg struct {
+		Aliases map[string]string `yaml:"aliases"`
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("parse config %s: %w", path, err)
+	}
+	SetModelAliases(cfg.Aliases)
+	return nil
+}
+
+// WatchAliases polls the config file every interval and reloads the alias
+// table when the file changes, until ctx is cancelled.
+func WatchAliases(ctx context.Context, path string, interval time.Duration) {
+	var lastMod time.Time
+	if info, err := os.Stat(path); err == nil {
+		lastMod = info.ModTime()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			if err != nil || !info.ModTime().After(lastMod) {
+				continue
+			}
+			lastMod = info.ModTime()
+			if err := ReloadAliases(path); err != nil {
+				log.Printf("[benchmark-aliases] Reload error: %v", err)
+				continue
+			}
+			log.Printf("[benchmark-aliases] Reloaded from %s", path)
+		}
+	}
+}
+
+// Leaderboard names seen during a scrape that matched no tracked model, with
+// any scores they had, by parser name. The Scraper stores them after each
+// parser runs.
+var (
+	unmatchedMu sync.Mutex
+	unmatched   = map[string]map[string][]CandidateScore{}
+)
+
+// NoteUnmatched records a leaderboard model name that source could not match.
+func NoteUnmatched(source, rawName string) {
+	noteUnmatched(source, rawName, nil)
+}
+
+// NoteUnmatchedScore records an unmatched model name with one of its scores,
+// so the Scraper can spot newly released models worth tracking.
+func NoteUnmatchedScore(source, rawName, benchmarkID, variant string, score float64) {
+	noteUnmatched(source, rawName, &CandidateScore{BenchmarkID: benchmarkID, Variant: variant, Score: score})
+}
+
+func noteUnmatched(source, rawName string, score *CandidateScore) {
+	rawName = strings.TrimSpace(rawName)
+	if rawName == "" {
+		return
+	}
+	unmatchedMu.Lock()
+	defer unmatchedMu.Unlock()
+	if unmatched[source] == nil {
+		unmatched[source] = make(map[string][]CandidateScore)
+	}
+	scores := unmatched[source][rawName]
+	if score != nil {
+		scores = append(scores, *score)
+	}
+	unmatched[source][rawName] = scores
+}
+
+// takeUnmatched returns and clears the names recorded for source, sorted,
+// along with the scores noted for them.
+func takeUnmatched(source string) ([]string, map[string][]CandidateScore) {
+	unmatchedMu.Lock()
+	noted := unmatched[source]
+	delete(unmatched, source)
+	unmatchedMu.Unlock()
+
+	names := make([]string, 0, len(noted))
+	for name := range noted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, noted
+}
+
+// UnmatchedModel is a leaderboard model name a parser could not match on its
+// latest successful run.
+type UnmatchedModel struct {
+	Source   string
+	RawName  string
+	LastSeen time.Time
+}
+
+// CandidateScore is a score an unmatched model posted on a leaderboard.
+type CandidateScore struct {
+	BenchmarkID string
+	Variant     string
+	Score       float64
+}
+
+// ModelCandidate is an unmatched leaderboard model that scored at least the
+// median tracked score on several benchmarks: likely a new release to add
+// to the config or alias table.
+type ModelCandidate struct {
+	Source     string
+	RawName    string
+	HighScores int
+	Summary    string // e.g. "GPQA Diamond 93.1%, LMArena (Text) · Overall 1480"
+	FirstSeen  time.Time
+}
+
+// minCandidateScores is how many high scores make an unmatched model a candidate.
+const minCandidateScores = 2
+
+// findCandidates picks the unmatched models from one parser whose scores
+// reach the median of the stored scores on at least minCandidateScores
+// benchmarks.
+func findCandidates(source string, noted map[string][]CandidateScore, stored map[string]float64) []ModelCandidate {
+	medians := make(map[string]float64)
+	byKey := make(map[string][]float64)
+	for key, score := range stored {
+		scoreKey, _, _ := strings.Cut(key, "|")
+		byKey[scoreKey] = append(byKey[scoreKey], score)
+	}
+	for key, scores := range byKey {
+		sort.Float64s(scores)
+		medians[key] = scores[len(scores)/2]
+	}
+
+	var candidates []ModelCandidate
+	for raw, scores := range noted {
+		var high []string
+		counted := make(map[string]bool)
+		for _, sc := range scores {
+			key := ScoreKey(sc.BenchmarkID, sc.Variant)
+			median, ok := medians[key]
+			if !ok || sc.Score < median || counted[key] {
+				continue
+			}
+			counted[key] = true
+			name, unit := sc.BenchmarkID, "%"
+			if b := FindBenchmark(sc.BenchmarkID); b != nil {
+				name, unit = b.Name, b.Unit
+			}
+			if sc.Variant != "" {
+				name += " · " + sc.Variant
+			}
+			high = append(high, name+" "+formatScore(sc.Score, unit))
+		}
+		if len(high) >= minCandidateScores {
+			candidates = append(candidates, ModelCandidate{
+				Source:     source,
+				RawName:    raw,
+				HighScores: len(high),
+				Summary:    strings.Join(high, ", "),
+			})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].RawName < candidates[j].RawName })
+	return candidates
+}
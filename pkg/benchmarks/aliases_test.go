@@ -0,0 +1,50 @@
+package benchmarks
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+)
+
+// noteParser reports one score and one unmatched leaderboard name.
+type noteParser struct{}
+
+func (noteParser) Name() string { return "test-board" }
+
+func (noteParser) Parse(ctx context.Context, client *http.Client) ([]BenchmarkScore, error) {
+	NoteUnmatched("test-board", "Mystery-Model 9")
+	return []BenchmarkScore{{BenchmarkID: "gpqa_diamond", ModelName: "Opus 4.6", ModelProvider: "anthropic", Score: 91.3}}, nil
+}
+
+func TestModelAliases(t *testing.T) {
+	defer SetModelAliases(nil)
+
+	path := t.TempDir() + "/models.yaml"
+	if err := os.WriteFile(path, []byte("aliases:\n  \"Claude-Opus-4-6 \": \"Opus 4.6\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReloadAliases(path); err != nil {
+		t.Fatal(err)
+	}
+	if name, ok := ModelAlias("claude-opus-4-6"); !ok || name != "Opus 4.6" {
+		t.Errorf("ModelAlias = %q, %v", name, ok)
+	}
+
+	db := openTestDB(t)
+	store, err := NewStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := NewScraper(store, noteParser{}).ScrapeAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	unmatched, err := store.GetUnmatched(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unmatched) != 1 || unmatched[0].Source != "test-board" || unmatched[0].RawName != "Mystery-Model 9" {
+		t.Errorf("unmatched = %+v", unmatched)
+	}
+}
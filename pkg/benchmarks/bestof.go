@@ -0,0 +1,123 @@
+package benchmarks
+
+import (
+	"fmt"
+	"html"
+	"math"
+)
+
+// BestOfProviders returns a report with one column per provider: its best
+// current-generation model (Gen "latest"; the highest composite score when a
+// provider has several), compared against the provider's best
+// previous-generation model when the config has one. Score history is left
+// out; GenDelta takes the place of the scrape-over-scrape Delta.
+func (r *BenchmarkReport) BestOfProviders() *BenchmarkReport {
+	composite := r.CompositeScores(CategoryWeights)
+
+	var providers []string
+	byProvider := make(map[string][]ModelConfig)
+	for _, m := range r.Models {
+		if _, ok := byProvider[m.Provider]; !ok {
+			providers = append(providers, m.Provider)
+		}
+		byProvider[m.Provider] = append(byProvider[m.Provider], m)
+	}
+
+	best := NewReport(nil, r.Date)
+	for _, p := range providers {
+		models := byProvider[p]
+		current, ok := bestOfGen(models, "latest", composite)
+		if !ok {
+			current = models[0]
+		}
+		best.Models = append(best.Models, current)
+		if prev, ok := bestOfGen(models, "previous", composite); ok && prev.Name != current.Name {
+			best.Baselines[current.Name] = prev.Name
+		}
+		if pricing, ok := r.Pricing[current.Name]; ok {
+			best.Pricing[current.Name] = pricing
+		}
+	}
+
+	for _, b := range AllBenchmarks {
+		variants := b.Variants
+		if len(variants) == 0 {
+			variants = []string{""}
+		}
+		for _, v := range variants {
+			key := ScoreKey(b.ID, v)
+			for _, m := range best.Models {
+				score, ok := r.GetScore(b.ID, v, m.Name)
+				if !ok {
+					continue
+				}
+				best.SetScore(b.ID, v, m.Name, score)
+				best.SetProvenance(b.ID, v, m.Name, r.GetProvenance(b.ID, v, m.Name))
+				if prev, ok := r.GetScore(b.ID, v, best.Baselines[m.Name]); ok {
+					if best.BaselineScores[key] == nil {
+						best.BaselineScores[key] = make(map[string]float64)
+					}
+					best.BaselineScores[key][m.Name] = prev
+				}
+			}
+		}
+	}
+	return best
+}
+
+// bestOfGen returns the model of generation gen with the highest composite
+// score, or the first one in display order if none has a score.
+func bestOfGen(models []ModelConfig, gen string, composite map[string]float64) (ModelConfig, bool) {
+	var best ModelConfig
+	found := false
+	for _, m := range models {
+		if m.Gen != gen {
+			continue
+		}
+		if !found || composite[m.Name] > composite[best.Name] {
+			best, found = m, true
+		}
+	}
+	return best, found
+}
+
+// GenDelta returns how much a model's score changed from its previous
+// generation (see BestOfProviders), and whether both scores exist.
+func (r *BenchmarkReport) GenDelta(benchmarkID, variant, modelName string) (float64, bool) {
+	score, ok := r.GetScore(benchmarkID, variant, modelName)
+	if !ok {
+		return 0, false
+	}
+	prev, ok := r.BaselineScores[ScoreKey(benchmarkID, variant)][modelName]
+	if !ok {
+		return 0, false
+	}
+	return score - prev, true
+}
+
+// genDeltaText formats GenDelta in the benchmark's unit, e.g. "+2.4", or ""
+// when there is no previous-generation score.
+func genDeltaText(report *BenchmarkReport, bench BenchmarkDef, variant, modelName string) string {
+	delta, ok := report.GenDelta(bench.ID, variant, modelName)
+	if !ok {
+		return ""
+	}
+	if bench.Unit == "Elo" {
+		return fmt.Sprintf("%+d", int(math.Round(delta)))
+	}
+	return fmt.Sprintf("%+.1f", delta)
+}
+
+// htmlGenDelta renders GenDelta below a score, or nothing without a baseline.
+func htmlGenDelta(report *BenchmarkReport, bench BenchmarkDef, variant, modelName string) string {
+	text := genDeltaText(report, bench, variant, modelName)
+	if text == "" {
+		return ""
+	}
+	color := "#2ecc71"
+	if delta, _ := report.GenDelta(bench.ID, variant, modelName); delta < 0 {
+		color = "#e74c3c"
+	}
+	return fmt.Sprintf(`<br><span style="color:%s;font-size:10px;" title="vs %s">%s</span>`,
+		color, html.EscapeString(report.Baselines[modelName]), text)
+}
@@ -0,0 +1,40 @@
+package benchmarks
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestBestOfProviders(t *testing.T) {
+	// Gemini 3.1 Pro / Gemini 3 Pro and Opus 4.6 / Sonnet 4.6: latest and previous per provider
+	report := NewReport(DefaultModels[:4], "2026-02-20")
+	report.SetScore("gpqa_diamond", "", "Gemini 3.1 Pro", 94.3)
+	report.SetScore("gpqa_diamond", "", "Gemini 3 Pro", 91.9)
+	report.SetScore("gpqa_diamond", "", "Opus 4.6", 91.3)
+	report.SetScore("gpqa_diamond", "", "Sonnet 4.6", 92.0)
+	report.SetScore("lmarena", "Overall", "Opus 4.6", 1460)
+
+	best := report.BestOfProviders()
+	if len(best.Models) != 2 || best.Models[0].Name != "Gemini 3.1 Pro" || best.Models[1].Name != "Opus 4.6" {
+		t.Fatalf("best models = %+v", best.Models)
+	}
+	if best.Baselines["Opus 4.6"] != "Sonnet 4.6" {
+		t.Errorf("Opus baseline = %q", best.Baselines["Opus 4.6"])
+	}
+	if delta, ok := best.GenDelta("gpqa_diamond", "", "Gemini 3.1 Pro"); !ok || math.Abs(delta-2.4) > 1e-9 {
+		t.Errorf("Gemini delta = %v, %v", delta, ok)
+	}
+	if _, ok := best.GenDelta("lmarena", "Overall", "Opus 4.6"); ok {
+		t.Error("delta without a previous-generation score")
+	}
+	// The previous generation no longer competes for the highest score
+	if !best.IsHighest("gpqa_diamond", "", "Gemini 3.1 Pro") {
+		t.Error("Gemini 3.1 Pro should lead GPQA")
+	}
+
+	md := NewMarkdownRenderer().RenderMarkdown(best)
+	if !strings.Contains(md, "Opus 4.6 <sub>Max</sub> <sub>vs Sonnet 4.6</sub>") || !strings.Contains(md, "| 91.3% <sub>-0.7</sub> |") {
+		t.Errorf("Markdown missing generation deltas:\n%s", md)
+	}
+}
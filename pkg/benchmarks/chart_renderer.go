@@ -0,0 +1,276 @@
+package benchmarks
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/fogleman/gg"
+)
+
+// ChartRenderer renders a BenchmarkReport as charts: one grouped bar chart
+// per category and one radar chart per model.
+type ChartRenderer struct {
+	Width     float64
+	Height    float64
+	Pad       float64
+	FontSize  float64
+	TitleSize float64
+	inner     *ImageRenderer // shared background and font helpers
+}
+
+// NewChartRenderer creates a renderer producing 1600×900 charts.
+func NewChartRenderer() *ChartRenderer {
+	return &ChartRenderer{
+		Width:     1600,
+		Height:    900,
+		Pad:       80,
+		FontSize:  18,
+		TitleSize: 30,
+		inner:     &ImageRenderer{Width: 1600},
+	}
+}
+
+var unsafeChartName = regexp.MustCompile(`[^a-z0-9]+`)
+
+// RenderAll writes bar_<category>.png and radar_<model>.png into dir and
+// returns the written paths. Categories and models without scores are skipped.
+func (c *ChartRenderer) RenderAll(report *BenchmarkReport, dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, cat := range Categories {
+		path := filepath.Join(dir, "bar_"+chartFileName(cat.ID)+".png")
+		ok, err := c.RenderCategoryBars(report, cat, path)
+		if err != nil {
+			return paths, fmt.Errorf("render %s bars: %w", cat.ID, err)
+		}
+		if ok {
+			paths = append(paths, path)
+		}
+	}
+	for _, m := range report.Models {
+		path := filepath.Join(dir, "radar_"+chartFileName(m.Name)+".png")
+		ok, err := c.RenderRadar(report, m, path)
+		if err != nil {
+			return paths, fmt.Errorf("render %s radar: %w", m.Name, err)
+		}
+		if ok {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+func chartFileName(s string) string {
+	return strings.Trim(unsafeChartName.ReplaceAllString(strings.ToLower(s), "_"), "_")
+}
+
+// chartRow is one benchmark (or variant) in a bar chart.
+type chartRow struct {
+	bench   BenchmarkDef
+	variant string
+}
+
+func (row chartRow) label() string {
+	if row.variant == "" {
+		return row.bench.Name
+	}
+	return row.bench.Name + " · " + row.variant
+}
+
+// RenderCategoryBars draws a grouped bar chart of every benchmark in the
+// category, one bar per model in its provider color. Elo benchmarks are
+// scaled to the row's best score so they share the 0–100 axis. It reports
+// false without writing anything when the category has no scores.
+func (c *ChartRenderer) RenderCategoryBars(report *BenchmarkReport, cat CategoryMeta, path string) (bool, error) {
+	var rows []chartRow
+	for _, b := range benchmarksForCategory(cat.ID) {
+		variants := b.Variants
+		if len(variants) == 0 {
+			variants = []string{""}
+		}
+		for _, v := range variants {
+			if len(report.Scores[ScoreKey(b.ID, v)]) > 0 {
+				rows = append(rows, chartRow{bench: b, variant: v})
+			}
+		}
+	}
+	if len(rows) == 0 {
+		return false, nil
+	}
+
+	dc := gg.NewContext(int(c.Width), int(c.Height))
+	c.inner.drawBackground(dc, c.Height)
+	c.drawTitle(dc, fmt.Sprintf("%s %s · %s", cat.Emoji, cat.Label, report.Date), cat.Color)
+
+	left, right := c.Pad, c.Width-c.Pad
+	top, bottom := c.Pad+70, c.Height-c.Pad-60
+	c.drawGrid(dc, left, right, top, bottom)
+
+	groupW := (right - left) / float64(len(rows))
+	barW := math.Min(48, groupW*0.8/float64(len(report.Models)))
+	for i, row := range rows {
+		groupX := left + float64(i)*groupW
+		startX := groupX + (groupW-barW*float64(len(report.Models)))/2
+		for j, m := range report.Models {
+			value, ok := normalizedScore(report, row.bench, row.variant, m.Name)
+			if !ok {
+				continue
+			}
+			h := (bottom - top) * value / 100
+			x := startX + float64(j)*barW
+			dc.SetColor(hexColor(ProviderColor(m.Provider)))
+			dc.DrawRectangle(x+2, bottom-h, barW-4, h)
+			dc.Fill()
+
+			score, _ := report.GetScore(row.bench.ID, row.variant, m.Name)
+			c.inner.loadFont(dc, c.FontSize-6, false)
+			dc.SetColor(hexColor("#c0c0d0"))
+			dc.DrawStringAnchored(formatScore(score, row.bench.Unit), x+barW/2, bottom-h-8, 0.5, 0)
+		}
+		c.inner.loadFont(dc, c.FontSize, false)
+		dc.SetColor(hexColor("#aaaacc"))
+		dc.DrawStringWrapped(row.label(), groupX+groupW/2, bottom+14, 0.5, 0, groupW-10, 1.2, gg.AlignCenter)
+	}
+
+	c.drawLegend(dc, report.Models, c.Height-30)
+	return true, dc.SavePNG(path)
+}
+
+// RenderRadar draws one model's average normalized score per category as a
+// radar chart. It reports false without writing anything when the model has
+// no scores.
+func (c *ChartRenderer) RenderRadar(report *BenchmarkReport, model ModelConfig, path string) (bool, error) {
+	values := make([]float64, len(Categories))
+	scored := false
+	for i, cat := range Categories {
+		var sum float64
+		var n int
+		for _, b := range benchmarksForCategory(cat.ID) {
+			variants := b.Variants
+			if len(variants) == 0 {
+				variants = []string{""}
+			}
+			for _, v := range variants {
+				if value, ok := normalizedScore(report, b, v, model.Name); ok {
+					sum += value
+					n++
+				}
+			}
+		}
+		if n > 0 {
+			values[i] = sum / float64(n)
+			scored = true
+		}
+	}
+	if !scored {
+		return false, nil
+	}
+
+	// Radar charts are square
+	size := c.Height
+	dc := gg.NewContext(int(size), int(size))
+	c.inner.drawBackground(dc, size)
+	c.drawTitle(dc, fmt.Sprintf("%s · %s", model.Name, report.Date), ProviderColor(model.Provider))
+
+	cx, cy := size/2, size/2+30
+	radius := size/2 - c.Pad - 40
+	angle := func(i int) float64 {
+		return -math.Pi/2 + 2*math.Pi*float64(i)/float64(len(Categories))
+	}
+
+	// Concentric guides at 25/50/75/100
+	dc.SetColor(hexColor("#2a2a4e"))
+	dc.SetLineWidth(1)
+	for _, level := range []float64{0.25, 0.5, 0.75, 1} {
+		for i := range Categories {
+			a := angle(i)
+			dc.LineTo(cx+radius*level*math.Cos(a), cy+radius*level*math.Sin(a))
+		}
+		dc.ClosePath()
+		dc.Stroke()
+	}
+
+	c.inner.loadFont(dc, c.FontSize, false)
+	for i, cat := range Categories {
+		a := angle(i)
+		dc.SetColor(hexColor("#2a2a4e"))
+		dc.DrawLine(cx, cy, cx+radius*math.Cos(a), cy+radius*math.Sin(a))
+		dc.Stroke()
+		dc.SetColor(hexColor(cat.Color))
+		dc.DrawStringAnchored(cat.Label, cx+(radius+36)*math.Cos(a), cy+(radius+36)*math.Sin(a), 0.5, 0.5)
+	}
+
+	for i, v := range values {
+		a := angle(i)
+		dc.LineTo(cx+radius*v/100*math.Cos(a), cy+radius*v/100*math.Sin(a))
+	}
+	dc.ClosePath()
+	provider := hexColor(ProviderColor(model.Provider)).(color.RGBA)
+	dc.SetColor(color.RGBA{provider.R, provider.G, provider.B, 90})
+	dc.FillPreserve()
+	dc.SetColor(provider)
+	dc.SetLineWidth(3)
+	dc.Stroke()
+
+	return true, dc.SavePNG(path)
+}
+
+// normalizedScore returns a score on a 0–100 scale: percentages as-is, Elo
+// relative to the best score on the same benchmark.
+func normalizedScore(report *BenchmarkReport, bench BenchmarkDef, variant, modelName string) (float64, bool) {
+	score, ok := report.GetScore(bench.ID, variant, modelName)
+	if !ok {
+		return 0, false
+	}
+	if bench.Unit != "Elo" {
+		return math.Max(0, math.Min(100, score)), true
+	}
+	best, _ := report.GetScore(bench.ID, variant, report.HighestOf[ScoreKey(bench.ID, variant)])
+	if best <= 0 {
+		return 0, true
+	}
+	return score / best * 100, true
+}
+
+func (c *ChartRenderer) drawTitle(dc *gg.Context, title, accent string) {
+	dc.SetColor(hexColor(accent))
+	dc.DrawRectangle(c.Pad, 30, 6, 44)
+	dc.Fill()
+	c.inner.loadFont(dc, c.TitleSize, true)
+	dc.SetColor(color.White)
+	dc.DrawStringAnchored(title, c.Pad+24, 52, 0, 0.5)
+}
+
+func (c *ChartRenderer) drawGrid(dc *gg.Context, left, right, top, bottom float64) {
+	c.inner.loadFont(dc, c.FontSize-4, false)
+	dc.SetLineWidth(1)
+	for _, level := range []float64{0, 25, 50, 75, 100} {
+		y := bottom - (bottom-top)*level/100
+		dc.SetColor(hexColor("#1e1e3a"))
+		dc.DrawLine(left, y, right, y)
+		dc.Stroke()
+		dc.SetColor(hexColor("#555570"))
+		dc.DrawStringAnchored(fmt.Sprintf("%.0f", level), left-12, y, 1, 0.5)
+	}
+}
+
+func (c *ChartRenderer) drawLegend(dc *gg.Context, models []ModelConfig, y float64) {
+	c.inner.loadFont(dc, c.FontSize-4, false)
+	x := c.Pad
+	for _, m := range models {
+		dc.SetColor(hexColor(ProviderColor(m.Provider)))
+		dc.DrawRectangle(x, y-8, 14, 14)
+		dc.Fill()
+		dc.SetColor(hexColor("#aaaacc"))
+		dc.DrawStringAnchored(m.Name, x+20, y, 0, 0.5)
+		w, _ := dc.MeasureString(m.Name)
+		x += w + 48
+	}
+}
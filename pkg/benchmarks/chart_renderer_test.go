@@ -0,0 +1,40 @@
+package benchmarks
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRenderCharts(t *testing.T) {
+	report := NewReport(DefaultModels[:3], "2026-02-20")
+	report.SetScore("gpqa_diamond", "", "Gemini 3.1 Pro", 94.3)
+	report.SetScore("gpqa_diamond", "", "Opus 4.6", 91.3)
+	report.SetScore("arc_agi_2", "", "Gemini 3.1 Pro", 77.1)
+	report.SetScore("swe_bench_verified", "", "Opus 4.6", 80.8)
+
+	dir := t.TempDir()
+	paths, err := NewChartRenderer().RenderAll(report, dir)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	// Two categories with scores plus radars for the two scored models
+	want := map[string]bool{
+		"bar_reasoning.png":        true,
+		"bar_coding.png":           true,
+		"radar_gemini_3_1_pro.png": true,
+		"radar_opus_4_6.png":       true,
+	}
+	if len(paths) != len(want) {
+		t.Fatalf("got %d charts %v, want %d", len(paths), paths, len(want))
+	}
+	for _, p := range paths {
+		name := p[len(dir)+1:]
+		if !want[name] {
+			t.Errorf("unexpected chart %s", name)
+		}
+		if info, err := os.Stat(p); err != nil || info.Size() == 0 {
+			t.Errorf("chart %s missing or empty: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package benchmarks
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// FindModel returns the report model called name, ignoring case, or the one
+// the alias table maps name to.
+func (r *BenchmarkReport) FindModel(name string) (ModelConfig, bool) {
+	name = strings.TrimSpace(name)
+	if alias, ok := ModelAlias(strings.ToLower(name)); ok {
+		name = alias
+	}
+	for _, m := range r.Models {
+		if strings.EqualFold(m.Name, name) {
+			return m, true
+		}
+	}
+	return ModelConfig{}, false
+}
+
+// LookupBenchmark returns the benchmark whose ID or display name is idOrName,
+// ignoring case, or nil.
+func LookupBenchmark(idOrName string) *BenchmarkDef {
+	idOrName = strings.TrimSpace(idOrName)
+	for i := range AllBenchmarks {
+		if strings.EqualFold(AllBenchmarks[i].ID, idOrName) || strings.EqualFold(AllBenchmarks[i].Name, idOrName) {
+			return &AllBenchmarks[i]
+		}
+	}
+	return nil
+}
+
+// Rank returns a model's position on a benchmark among the report's models
+// with a score (1 = best) and how many there are, or false without a score.
+func (r *BenchmarkReport) Rank(benchmarkID, variant, modelName string) (rank, of int, ok bool) {
+	scores := r.Scores[ScoreKey(benchmarkID, variant)]
+	score, ok := scores[modelName]
+	if !ok {
+		return 0, 0, false
+	}
+	rank = 1
+	for _, m := range r.Models {
+		if s, has := scores[m.Name]; has {
+			of++
+			if s > score {
+				rank++
+			}
+		}
+	}
+	return rank, of, true
+}
+
+// ScoreComparison is one benchmark both compared models have a score on.
+type ScoreComparison struct {
+	BenchmarkID string  `json:"benchmark_id"`
+	Benchmark   string  `json:"benchmark"`
+	Variant     string  `json:"variant,omitempty"`
+	Unit        string  `json:"unit"`
+	A           float64 `json:"a"`
+	B           float64 `json:"b"`
+	Diff        float64 `json:"diff"`             // A − B
+	Winner      string  `json:"winner,omitempty"` // empty on a tie
+}
+
+// ModelComparison is a head-to-head of two models over the benchmarks in a
+// report.
+type ModelComparison struct {
+	A          string             `json:"a"`
+	B          string             `json:"b"`
+	Scores     []ScoreComparison  `json:"scores"`
+	WinsA      int                `json:"wins_a"`
+	WinsB      int                `json:"wins_b"`
+	Ties       int                `json:"ties"`
+	CompositeA *float64           `json:"composite_a,omitempty"`
+	CompositeB *float64           `json:"composite_b,omitempty"`
+	PricingA   *ModelPricing      `json:"pricing_a,omitempty"`
+	PricingB   *ModelPricing      `json:"pricing_b,omitempty"`
+	OnlyA      []string           `json:"only_a,omitempty"` // benchmarks only A has a score on
+	OnlyB      []string           `json:"only_b,omitempty"`
+	Categories map[string]float64 `json:"category_diff,omitempty"` // category ID → A − B category score
+}
+
+// CompareModels compares models a and b, as named in the report, on every
+// benchmark in display order. Composite and category scores use
+// CategoryWeights.
+func (r *BenchmarkReport) CompareModels(a, b string) ModelComparison {
+	cmp := ModelComparison{A: a, B: b, Scores: []ScoreComparison{}}
+	for _, bench := range r.Benchmarks {
+		variants := bench.Variants
+		if len(variants) == 0 {
+			variants = []string{""}
+		}
+		for _, v := range variants {
+			name := bench.Name
+			if v != "" {
+				name += " · " + v
+			}
+			sa, okA := r.GetScore(bench.ID, v, a)
+			sb, okB := r.GetScore(bench.ID, v, b)
+			switch {
+			case okA && !okB:
+				cmp.OnlyA = append(cmp.OnlyA, name)
+				continue
+			case okB && !okA:
+				cmp.OnlyB = append(cmp.OnlyB, name)
+				continue
+			case !okA && !okB:
+				continue
+			}
+			sc := ScoreComparison{
+				BenchmarkID: bench.ID,
+				Benchmark:   bench.Name,
+				Variant:     v,
+				Unit:        bench.Unit,
+				A:           sa,
+				B:           sb,
+				Diff:        sa - sb,
+			}
+			switch {
+			case sa > sb:
+				sc.Winner = a
+				cmp.WinsA++
+			case sb > sa:
+				sc.Winner = b
+				cmp.WinsB++
+			default:
+				cmp.Ties++
+			}
+			cmp.Scores = append(cmp.Scores, sc)
+		}
+	}
+
+	composite := r.CompositeScores(CategoryWeights)
+	if s, ok := composite[a]; ok {
+		cmp.CompositeA = &s
+	}
+	if s, ok := composite[b]; ok {
+		cmp.CompositeB = &s
+	}
+	if p, ok := r.Pricing[a]; ok {
+		cmp.PricingA = &p
+	}
+	if p, ok := r.Pricing[b]; ok {
+		cmp.PricingB = &p
+	}
+
+	for _, cat := range Categories {
+		ca, okA := r.CategoryScore(cat.ID, a)
+		cb, okB := r.CategoryScore(cat.ID, b)
+		if okA && okB {
+			if cmp.Categories == nil {
+				cmp.Categories = make(map[string]float64)
+			}
+			cmp.Categories[cat.ID] = ca - cb
+		}
+	}
+	return cmp
+}
+
+// ModelsWithScores returns the names of the report's models that have at
+// least one score, sorted.
+func (r *BenchmarkReport) ModelsWithScores() []string {
+	var names []string
+	for _, m := range r.Models {
+		if r.ModelScoreCount(m.Name) > 0 && !slices.Contains(names, m.Name) {
+			names = append(names, m.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,52 @@
+package benchmarks
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCompareModels(t *testing.T) {
+	report := NewReport(DefaultModels[:3], "2026-02-20")
+	report.SetScore("gpqa_diamond", "", "Gemini 3.1 Pro", 94.3)
+	report.SetScore("gpqa_diamond", "", "Opus 4.6", 91.3)
+	report.SetScore("gpqa_diamond", "", "Gemini 3 Pro", 95)
+	report.SetScore("hle", "No tools", "Gemini 3.1 Pro", 40)
+	report.SetScore("hle", "No tools", "Opus 4.6", 40)
+	report.SetScore("swe_bench_verified", "", "Opus 4.6", 80.8)
+
+	cmp := report.CompareModels("Gemini 3.1 Pro", "Opus 4.6")
+	if cmp.WinsA != 1 || cmp.WinsB != 0 || cmp.Ties != 1 || len(cmp.Scores) != 2 {
+		t.Fatalf("comparison = %+v", cmp)
+	}
+	if sc := cmp.Scores[0]; sc.BenchmarkID != "hle" || sc.Variant != "No tools" || sc.Winner != "" {
+		t.Errorf("first score = %+v", sc)
+	}
+	if sc := cmp.Scores[1]; sc.Winner != "Gemini 3.1 Pro" || math.Abs(sc.Diff-3) > 1e-9 {
+		t.Errorf("GPQA = %+v", sc)
+	}
+	if len(cmp.OnlyB) != 1 || cmp.OnlyB[0] != "SWE-Bench Verified" || len(cmp.OnlyA) != 0 {
+		t.Errorf("only = %v / %v", cmp.OnlyA, cmp.OnlyB)
+	}
+	if cmp.CompositeA == nil || cmp.CompositeB == nil {
+		t.Error("missing composite scores")
+	}
+
+	if rank, of, ok := report.Rank("gpqa_diamond", "", "Gemini 3.1 Pro"); !ok || rank != 2 || of != 3 {
+		t.Errorf("Rank = %d of %d, %v", rank, of, ok)
+	}
+	if _, _, ok := report.Rank("swe_bench_verified", "", "Gemini 3.1 Pro"); ok {
+		t.Error("rank without a score")
+	}
+
+	SetModelAliases(map[string]string{"claude-opus-4-6": "Opus 4.6"})
+	defer SetModelAliases(nil)
+	if m, ok := report.FindModel("claude-opus-4-6"); !ok || m.Name != "Opus 4.6" {
+		t.Errorf("FindModel by alias = %+v, %v", m, ok)
+	}
+	if m, ok := report.FindModel("gemini 3.1 pro"); !ok || m.Name != "Gemini 3.1 Pro" {
+		t.Errorf("FindModel ignoring case = %+v, %v", m, ok)
+	}
+	if b := LookupBenchmark("gpqa diamond"); b == nil || b.ID != "gpqa_diamond" {
+		t.Errorf("LookupBenchmark by name = %+v", b)
+	}
+}
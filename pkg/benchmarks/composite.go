@@ -0,0 +1,105 @@
+package benchmarks
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CategoryWeights weights each category in the composite score, by category
+// ID. Categories missing from the map weigh 1; nil weighs all equally.
+// LoadConfig sets it from the config's weights section.
+var CategoryWeights map[string]float64
+
+// SetCategoryWeights validates and installs the composite score weights.
+func SetCategoryWeights(weights map[string]float64) error {
+	for id, w := range weights {
+		if !knownCategory(id) {
+			return fmt.Errorf("weight for unknown category %q", id)
+		}
+		if w < 0 {
+			return fmt.Errorf("category %s: negative weight %g", id, w)
+		}
+	}
+	CategoryWeights = weights
+	return nil
+}
+
+// CompositeCategory styles the composite score row of rendered reports.
+var CompositeCategory = CategoryMeta{"overall", "Overall", "🏆", "#ff7043"}
+
+// CategoryScore returns a model's mean normalized score (0–100) across the
+// benchmarks of one category.
+func (r *BenchmarkReport) CategoryScore(catID, modelName string) (float64, bool) {
+	var sum float64
+	var n int
+	for _, b := range r.Benchmarks {
+		if b.Category != catID {
+			continue
+		}
+		variants := b.Variants
+		if len(variants) == 0 {
+			variants = []string{""}
+		}
+		for _, v := range variants {
+			if value, ok := normalizedScore(r, b, v, modelName); ok {
+				sum += value
+				n++
+			}
+		}
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return sum / float64(n), true
+}
+
+// CompositeScores computes each model's weighted composite score: the mean
+// of its category scores weighted by weights (see CategoryWeights for the
+// defaults). Categories a model has no scores in are left out of its mean
+// rather than counted as zero. Models without any weighted score are omitted.
+func (r *BenchmarkReport) CompositeScores(weights map[string]float64) map[string]float64 {
+	scores := make(map[string]float64)
+	for _, m := range r.Models {
+		var sum, total float64
+		for _, cat := range Categories {
+			w, ok := weights[cat.ID]
+			if !ok {
+				w = 1
+			}
+			if w == 0 {
+				continue
+			}
+			if s, ok := r.CategoryScore(cat.ID, m.Name); ok {
+				sum += w * s
+				total += w
+			}
+		}
+		if total > 0 {
+			scores[m.Name] = sum / total
+		}
+	}
+	return scores
+}
+
+// CompositeRow returns each model's composite score and rank under weights,
+// or false when no model has a score. Renderers use CategoryWeights.
+func (r *BenchmarkReport) CompositeRow(weights map[string]float64) (SummaryRow, bool) {
+	scores := r.CompositeScores(weights)
+	if len(scores) == 0 {
+		return SummaryRow{}, false
+	}
+
+	ranking := make([]string, 0, len(scores))
+	for _, m := range r.Models {
+		if _, ok := scores[m.Name]; ok {
+			ranking = append(ranking, m.Name)
+		}
+	}
+	sort.SliceStable(ranking, func(i, j int) bool { return scores[ranking[i]] > scores[ranking[j]] })
+
+	row := SummaryRow{Label: "Composite", Cells: make(map[string]SummaryCell, len(scores)), Best: ranking[0]}
+	for i, name := range ranking {
+		row.Cells[name] = SummaryCell{scores[name], fmt.Sprintf("%.1f (#%d)", scores[name], i+1)}
+	}
+	return row, true
+}
@@ -0,0 +1,42 @@
+package benchmarks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompositeScores(t *testing.T) {
+	report := NewReport(DefaultModels[:3], "2026-02-20")
+	report.SetScore("gpqa_diamond", "", "Gemini 3.1 Pro", 94.3)
+	report.SetScore("arc_agi_2", "", "Gemini 3.1 Pro", 77.1)
+	report.SetScore("gpqa_diamond", "", "Opus 4.6", 91.3)
+	report.SetScore("swe_bench_verified", "", "Opus 4.6", 60)
+
+	// Equal weights: Gemini reasoning 85.7; Opus (91.3 + 60) / 2 = 75.65
+	row, ok := report.CompositeRow(nil)
+	if !ok {
+		t.Fatal("no composite row")
+	}
+	if row.Best != "Gemini 3.1 Pro" || row.Cells["Opus 4.6"].Text != "75.7 (#2)" {
+		t.Errorf("equal weights row = %+v", row)
+	}
+	if _, ok := row.Cells["Gemini 3 Pro"]; ok {
+		t.Error("model without scores should have no composite")
+	}
+
+	// Ignoring coding puts Opus ahead
+	scores := report.CompositeScores(map[string]float64{"coding": 0})
+	if scores["Opus 4.6"] != 91.3 || scores["Gemini 3.1 Pro"] <= 85 {
+		t.Errorf("custom weights = %v", scores)
+	}
+
+	if err := SetCategoryWeights(map[string]float64{"nope": 1}); err == nil {
+		t.Error("unknown category weight accepted")
+	}
+	if err := SetCategoryWeights(map[string]float64{"coding": -1}); err == nil {
+		t.Error("negative weight accepted")
+	}
+	if html := NewHTMLRenderer().RenderHTML(report); !strings.Contains(html, ">85.7 (#1)</td>") {
+		t.Error("HTML missing composite row")
+	}
+}
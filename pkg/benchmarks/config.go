@@ -11,10 +11,16 @@ import (
 // Config holds the benchmark tracker configuration.
 type Config struct {
 	Models []ModelConfig `yaml:"models"`
+	// Benchmarks adds to (or overrides) the built-in AllBenchmarks.
+	Benchmarks []BenchmarkDef `yaml:"benchmarks,omitempty"`
+	// Weights sets the composite score weight of each category by ID.
+	Weights map[string]float64 `yaml:"weights,omitempty"`
+	// Aliases maps leaderboard model names to tracked model names.
+	Aliases map[string]string `yaml:"aliases,omitempty"`
 }
 
-// LoadConfig loads model configuration from a YAML file.
-// Falls back to DefaultModels if the file doesn't exist.
+// LoadConfig loads model configuration from a YAML file and registers any
+// user-defined benchmarks, weights and model aliases. Falls back to DefaultModels if the file doesn't exist.
 func LoadConfig(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -32,6 +38,13 @@ func LoadConfig(path string) (*Config, error) {
 	if len(cfg.Models) == 0 {
 		cfg.Models = DefaultModels
 	}
+	if err := RegisterBenchmarks(cfg.Benchmarks); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	if err := SetCategoryWeights(cfg.Weights); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	SetModelAliases(cfg.Aliases)
 
 	// Assign display order if not set
 	for i := range cfg.Models {
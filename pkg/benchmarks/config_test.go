@@ -0,0 +1,60 @@
+package benchmarks
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigBenchmarks(t *testing.T) {
+	builtins := append([]BenchmarkDef(nil), AllBenchmarks...)
+	categories := append([]CategoryMeta(nil), Categories...)
+	t.Cleanup(func() { AllBenchmarks, Categories = builtins, categories })
+
+	path := t.TempDir() + "/benchmarks.yaml"
+	if err := os.WriteFile(path, []byte(`
+benchmarks:
+  - id: aime_2026
+    name: AIME 2026
+    category: reasoning
+  - id: internal_eval
+    name: Internal Eval
+    category: internal
+    variants: [v1, v2]
+  - id: gpqa_diamond
+    name: GPQA Diamond (strict)
+    category: reasoning
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Models) != len(DefaultModels) {
+		t.Errorf("models = %d, want defaults", len(cfg.Models))
+	}
+	if b := FindBenchmark("aime_2026"); b == nil || b.Unit != "%" {
+		t.Errorf("aime_2026 = %+v", b)
+	}
+	if b := FindBenchmark("gpqa_diamond"); b == nil || b.Name != "GPQA Diamond (strict)" {
+		t.Errorf("gpqa_diamond not overridden: %+v", b)
+	}
+	if len(AllBenchmarks) != len(builtins)+2 {
+		t.Errorf("benchmarks = %d, want %d", len(AllBenchmarks), len(builtins)+2)
+	}
+
+	report := NewReport(DefaultModels[:2], "2026-02-20")
+	report.SetScore("internal_eval", "v2", "Gemini 3.1 Pro", 71.5)
+	html := NewHTMLRenderer().RenderHTML(report)
+	if !strings.Contains(html, "internal") || !strings.Contains(html, "71.5%") {
+		t.Error("custom benchmark missing from HTML report")
+	}
+
+	if err := os.WriteFile(path, []byte("benchmarks:\n  - id: bad\n    name: Bad\n    category: coding\n    unit: pts\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("expected error for invalid unit")
+	}
+}
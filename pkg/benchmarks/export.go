@@ -0,0 +1,102 @@
+package benchmarks
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ExportRecord is one score in a flat export of a report.
+type ExportRecord struct {
+	BenchmarkID string    `json:"benchmark_id"`
+	Benchmark   string    `json:"benchmark"`
+	Category    string    `json:"category"`
+	Variant     string    `json:"variant"`
+	Model       string    `json:"model"`
+	Provider    string    `json:"provider"`
+	Score       float64   `json:"score"`
+	Unit        string    `json:"unit"`
+	SourceURL   string    `json:"source_url"`
+	SourceType  string    `json:"source_type"`
+	Confidence  float64   `json:"confidence"`
+	ScrapedAt   time.Time `json:"scraped_at"`
+}
+
+// ExportRecords flattens the report into one record per score, in display
+// order. Source details and scrape time come from the matching stored scores.
+func ExportRecords(report *BenchmarkReport, stored []BenchmarkScore) []ExportRecord {
+	source := make(map[string]BenchmarkScore, len(stored))
+	for _, sc := range stored {
+		source[ScoreKey(sc.BenchmarkID, sc.Variant)+"|"+sc.ModelName] = sc
+	}
+
+	var records []ExportRecord
+	for _, bench := range report.Benchmarks {
+		variants := bench.Variants
+		if len(variants) == 0 {
+			variants = []string{""}
+		}
+		for _, v := range variants {
+			for _, m := range report.Models {
+				score, ok := report.GetScore(bench.ID, v, m.Name)
+				if !ok {
+					continue
+				}
+				sc := source[ScoreKey(bench.ID, v)+"|"+m.Name]
+				records = append(records, ExportRecord{
+					BenchmarkID: bench.ID,
+					Benchmark:   bench.Name,
+					Category:    bench.Category,
+					Variant:     v,
+					Model:       m.Name,
+					Provider:    m.Provider,
+					Score:       score,
+					Unit:        bench.Unit,
+					SourceURL:   sc.SourceURL,
+					SourceType:  sc.SourceType,
+					Confidence:  sc.Confidence,
+					ScrapedAt:   sc.ScrapedAt,
+				})
+			}
+		}
+	}
+	return records
+}
+
+var csvHeader = []string{"benchmark_id", "benchmark", "category", "variant", "model", "provider", "score", "unit", "source_url", "source_type", "confidence", "scraped_at"}
+
+// WriteCSV writes records as CSV with a header row. Times are RFC 3339 and
+// empty when unknown.
+func WriteCSV(w io.Writer, records []ExportRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, r := range records {
+		scrapedAt := ""
+		if !r.ScrapedAt.IsZero() {
+			scrapedAt = r.ScrapedAt.UTC().Format(time.RFC3339)
+		}
+		if err := cw.Write([]string{
+			r.BenchmarkID, r.Benchmark, r.Category, r.Variant, r.Model, r.Provider,
+			strconv.FormatFloat(r.Score, 'f', -1, 64), r.Unit, r.SourceURL,
+			r.SourceType, strconv.FormatFloat(r.Confidence, 'f', -1, 64), scrapedAt,
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes records as an indented JSON array.
+func WriteJSON(w io.Writer, records []ExportRecord) error {
+	if records == nil {
+		records = []ExportRecord{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(records)
+}
@@ -0,0 +1,46 @@
+package benchmarks
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportRecords(t *testing.T) {
+	report := NewReport(DefaultModels[:3], "2026-02-20")
+	report.SetScore("hle", "No tools", "Opus 4.6", 40)
+	report.SetScore("gpqa_diamond", "", "Gemini 3.1 Pro", 94.3)
+	scrapedAt := time.Date(2026, 2, 19, 8, 0, 0, 0, time.UTC)
+	stored := []BenchmarkScore{
+		{BenchmarkID: "gpqa_diamond", ModelName: "Gemini 3.1 Pro", Score: 94.3, SourceURL: "https://example.com/gemini",
+			SourceType: SourceVendor, Confidence: 1, ScrapedAt: scrapedAt},
+	}
+
+	records := ExportRecords(report, stored)
+	if len(records) != 2 || records[0].BenchmarkID != "hle" || records[0].Variant != "No tools" {
+		t.Fatalf("records = %+v", records)
+	}
+
+	var csvOut strings.Builder
+	if err := WriteCSV(&csvOut, records); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	want := "gpqa_diamond,GPQA Diamond,reasoning,,Gemini 3.1 Pro,google,94.3,%,https://example.com/gemini,vendor,1,2026-02-19T08:00:00Z"
+	if len(lines) != 3 || lines[2] != want {
+		t.Errorf("csv = %q, want last line %q", lines, want)
+	}
+
+	var jsonOut strings.Builder
+	if err := WriteJSON(&jsonOut, records); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []ExportRecord
+	if err := json.Unmarshal([]byte(jsonOut.String()), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 || !decoded[1].ScrapedAt.Equal(scrapedAt) || decoded[0].SourceURL != "" {
+		t.Errorf("json = %+v", decoded)
+	}
+}
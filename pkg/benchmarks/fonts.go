@@ -0,0 +1,136 @@
+package benchmarks
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+)
+
+// embeddedFonts holds optional font files bundled at build time (see
+// fonts/README.md).
+//
+//go:embed fonts
+var embeddedFonts embed.FS
+
+// systemCJKFonts lists well-known CJK font locations as regular/bold pairs.
+var systemCJKFonts = [][2]string{
+	{"/usr/share/fonts/opentype/noto/NotoSansCJK-Regular.ttc", "/usr/share/fonts/opentype/noto/NotoSansCJK-Bold.ttc"},
+	{"/usr/share/fonts/noto-cjk/NotoSansCJK-Regular.ttc", "/usr/share/fonts/noto-cjk/NotoSansCJK-Bold.ttc"},
+	{"/usr/share/fonts/google-noto-cjk/NotoSansCJK-Regular.ttc", "/usr/share/fonts/google-noto-cjk/NotoSansCJK-Bold.ttc"},
+	{"/usr/share/fonts/truetype/wqy/wqy-microhei.ttc", ""},
+	{"/usr/share/fonts/wenquanyi/wqy-microhei/wqy-microhei.ttc", ""},
+	{"/System/Library/Fonts/PingFang.ttc", ""},
+}
+
+var (
+	fontsOnce   sync.Once
+	regularFont *opentype.Font
+	boldFont    *opentype.Font
+
+	faceMu sync.Mutex
+	faces  = make(map[faceKey]font.Face)
+)
+
+type faceKey struct {
+	size float64
+	bold bool
+}
+
+// fontFace returns a cached face of the given size, loading fonts on first
+// use: embedded fonts first, then system CJK fonts, then the Go fonts.
+func fontFace(size float64, bold bool) (font.Face, error) {
+	fontsOnce.Do(loadFonts)
+
+	faceMu.Lock()
+	defer faceMu.Unlock()
+	key := faceKey{size, bold}
+	if f, ok := faces[key]; ok {
+		return f, nil
+	}
+	f := regularFont
+	if bold {
+		f = boldFont
+	}
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, err
+	}
+	faces[key] = face
+	return face, nil
+}
+
+func loadFonts() {
+	regularFont, boldFont = embeddedFontPair()
+	if regularFont == nil {
+		for _, pair := range systemCJKFonts {
+			if regularFont = loadFontFile(os.ReadFile, pair[0]); regularFont != nil {
+				boldFont = loadFontFile(os.ReadFile, pair[1])
+				break
+			}
+		}
+	}
+	if regularFont == nil {
+		regularFont, _ = opentype.Parse(goregular.TTF)
+		boldFont, _ = opentype.Parse(gobold.TTF)
+	}
+	if boldFont == nil {
+		boldFont = regularFont
+	}
+}
+
+// embeddedFontPair returns the bundled regular and bold fonts, if any.
+func embeddedFontPair() (regular, bold *opentype.Font) {
+	entries, _ := fs.ReadDir(embeddedFonts, "fonts")
+	for _, e := range entries {
+		switch strings.ToLower(path.Ext(e.Name())) {
+		case ".ttf", ".otf", ".ttc", ".otc":
+		default:
+			continue
+		}
+		f := loadFontFile(func(name string) ([]byte, error) {
+			return embeddedFonts.ReadFile(name)
+		}, "fonts/"+e.Name())
+		if f == nil {
+			continue
+		}
+		if strings.Contains(strings.ToLower(e.Name()), "bold") {
+			if bold == nil {
+				bold = f
+			}
+		} else if regular == nil {
+			regular = f
+		}
+	}
+	if regular == nil {
+		regular = bold
+	}
+	return regular, bold
+}
+
+// loadFontFile parses the first font of a font file or collection.
+func loadFontFile(read func(string) ([]byte, error), name string) *opentype.Font {
+	if name == "" {
+		return nil
+	}
+	data, err := read(name)
+	if err != nil {
+		return nil
+	}
+	c, err := opentype.ParseCollection(data)
+	if err != nil || c.NumFonts() == 0 {
+		return nil
+	}
+	f, err := c.Font(0)
+	if err != nil {
+		return nil
+	}
+	return f
+}
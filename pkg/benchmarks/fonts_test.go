@@ -0,0 +1,20 @@
+package benchmarks
+
+import (
+	"testing"
+)
+
+func TestFontFace(t *testing.T) {
+	for _, bold := range []bool{false, true} {
+		face, err := fontFace(18, bold)
+		if err != nil {
+			t.Fatalf("fontFace(bold=%v): %v", bold, err)
+		}
+		if _, ok := face.GlyphAdvance('A'); !ok {
+			t.Errorf("bold=%v face has no glyph for 'A'", bold)
+		}
+		if again, _ := fontFace(18, bold); again != face {
+			t.Error("faces are not cached")
+		}
+	}
+}
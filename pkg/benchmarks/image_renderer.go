@@ -43,8 +43,16 @@ func NewImageRenderer() *ImageRenderer {
 func (r *ImageRenderer) RenderPNG(report *BenchmarkReport, outputPath string) error {
 	// Calculate dimensions
 	totalRows := r.countRows(report)
+	costRows := report.CostRows()
 	height := r.HeaderH + float64(len(Categories))*r.GroupH + float64(totalRows)*r.RowHeight +
 		r.RowHeight + r.FooterH + 60 // +60 for model header row + padding
+	if len(costRows) > 0 {
+		height += r.GroupH + float64(len(costRows))*r.RowHeight
+	}
+	composite, hasComposite := report.CompositeRow(CategoryWeights)
+	if hasComposite {
+		height += r.RowHeight
+	}
 
 	dc := gg.NewContext(int(r.Width), int(height))
 
@@ -57,6 +65,11 @@ func (r *ImageRenderer) RenderPNG(report *BenchmarkReport, outputPath string) er
 	// Model header row
 	y = r.drawModelHeaders(dc, report, y)
 
+	// Composite ranking
+	if hasComposite {
+		y = r.drawSummaryRow(dc, report, composite, CompositeCategory.Color, y)
+	}
+
 	// Benchmark rows by category
 	for _, cat := range Categories {
 		benchmarks := r.benchmarksForCategory(cat.ID)
@@ -75,6 +88,14 @@ func (r *ImageRenderer) RenderPNG(report *BenchmarkReport, outputPath string) er
 		}
 	}
 
+	// Cost & speed
+	if len(costRows) > 0 {
+		y = r.drawCategoryHeader(dc, CostCategory, y)
+		for _, row := range costRows {
+			y = r.drawSummaryRow(dc, report, row, CostCategory.Color, y)
+		}
+	}
+
 	// Footer
 	r.drawFooter(dc, y, report)
 
@@ -262,8 +283,9 @@ func (r *ImageRenderer) drawScoreRow(dc *gg.Context, report *BenchmarkReport, be
 			tw, _ := dc.MeasureString("—")
 			dc.DrawString("—", cellCenter-tw/2, y+r.RowHeight/2+7)
 		} else {
-			// Format score
-			scoreStr := formatScore(score, bench.Unit)
+			// Format score; vendor-reported and extracted numbers carry a marker
+			mark := report.ProvenanceMark(bench.ID, variant, m.Name)
+			scoreStr := formatScore(score, bench.Unit) + mark
 
 			if isTop {
 				// Highlight: red glow background + red text
@@ -284,7 +306,11 @@ func (r *ImageRenderer) drawScoreRow(dc *gg.Context, report *BenchmarkReport, be
 				dc.DrawString(scoreStr, cellCenter-tw/2, y+r.RowHeight/2+7)
 				r.loadFont(dc, r.FontSize, false)
 			} else {
-				dc.SetColor(hexColor("#e0e0e0"))
+				if mark != "" {
+					dc.SetColor(hexColor("#9a9ab0"))
+				} else {
+					dc.SetColor(hexColor("#e0e0e0"))
+				}
 				tw, _ := dc.MeasureString(scoreStr)
 				dc.DrawString(scoreStr, cellCenter-tw/2, y+r.RowHeight/2+7)
 			}
@@ -302,6 +328,47 @@ func (r *ImageRenderer) drawScoreRow(dc *gg.Context, report *BenchmarkReport, be
 	return y + r.RowHeight
 }
 
+// drawSummaryRow draws a computed row, highlighting its best cell in accent.
+func (r *ImageRenderer) drawSummaryRow(dc *gg.Context, report *BenchmarkReport, row SummaryRow, accent string, y float64) float64 {
+	colWidth := (r.Width - r.PadLeft - r.PadRight - 280) / float64(len(report.Models))
+
+	dc.SetColor(hexColor("#0f0f20"))
+	dc.DrawRectangle(r.PadLeft, y, r.Width-r.PadLeft-r.PadRight, r.RowHeight)
+	dc.Fill()
+
+	r.loadFont(dc, r.SmallSize, false)
+	dc.SetColor(hexColor("#c0c0d0"))
+	dc.DrawString(row.Label, r.PadLeft+20, y+r.RowHeight/2+6)
+
+	x := r.PadLeft + 280
+	for _, m := range report.Models {
+		cellCenter := x + colWidth/2
+		cell, ok := row.Cells[m.Name]
+		switch {
+		case !ok:
+			r.loadFont(dc, r.FontSize, false)
+			dc.SetColor(hexColor("#404050"))
+			cell.Text = "—"
+		case m.Name == row.Best:
+			r.loadFont(dc, r.FontSize, true)
+			dc.SetColor(hexColor(accent))
+		default:
+			r.loadFont(dc, r.FontSize, false)
+			dc.SetColor(hexColor("#e0e0e0"))
+		}
+		tw, _ := dc.MeasureString(cell.Text)
+		dc.DrawString(cell.Text, cellCenter-tw/2, y+r.RowHeight/2+7)
+		x += colWidth
+	}
+
+	dc.SetColor(hexColor("#1a1a3e30"))
+	dc.SetLineWidth(0.5)
+	dc.DrawLine(r.PadLeft+280, y+r.RowHeight, r.Width-r.PadRight, y+r.RowHeight)
+	dc.Stroke()
+
+	return y + r.RowHeight
+}
+
 func (r *ImageRenderer) drawFooter(dc *gg.Context, y float64, report *BenchmarkReport) {
 	y += 16
 
@@ -312,19 +379,18 @@ func (r *ImageRenderer) drawFooter(dc *gg.Context, y float64, report *BenchmarkR
 
 	r.loadFont(dc, 16, false)
 	dc.SetColor(hexColor("#444460"))
-	footer := fmt.Sprintf("WatchBot Benchmark Tracker · Data scraped %s · Red = highest score per benchmark",
-		report.Date)
+	footer := fmt.Sprintf("WatchBot Benchmark Tracker · Data scraped %s · Red = highest score per benchmark · %s",
+		report.Date, ProvenanceLegend)
 	dc.DrawStringAnchored(footer, r.Width/2, y+r.FooterH/2+4, 0.5, 0.5)
 }
 
 // ---- Helpers ----
 
 func (r *ImageRenderer) loadFont(dc *gg.Context, size float64, bold bool) {
-	// gg uses system fonts; we use the default if custom font is not found
-	// In production, embed Noto Sans CJK for Chinese support
-	if err := dc.LoadFontFace("/System/Library/Fonts/Helvetica.ttc", size); err != nil {
-		// Fallback — gg will use a basic built-in font
-		_ = dc.LoadFontFace("/System/Library/Fonts/SFNSMono.ttf", size)
+	// Fonts load from memory (see fonts.go), so rendering works on any host;
+	// on failure gg keeps its basic built-in face
+	if face, err := fontFace(size, bold); err == nil {
+		dc.SetFontFace(face)
 	}
 }
 
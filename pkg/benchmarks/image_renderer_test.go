@@ -2,6 +2,7 @@ package benchmarks
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -124,7 +125,7 @@ func TestRenderImage(t *testing.T) {
 	report.SetScore("mrcr_v2", "1M (pointwise)", "Gemini 3 Pro", 26.3)
 
 	// Render
-	outPath := "test_benchmark_report.png"
+	outPath := filepath.Join(t.TempDir(), "benchmark_report.png")
 	err := NewImageRenderer().RenderPNG(report, outPath)
 	if err != nil {
 		t.Fatalf("render failed: %v", err)
@@ -135,7 +136,4 @@ func TestRenderImage(t *testing.T) {
 		t.Fatalf("output file not found: %v", err)
 	}
 	t.Logf("Generated %s (%.1f KB)", outPath, float64(info.Size())/1024)
-
-	// Cleanup
-	// os.Remove(outPath) // Comment out to inspect the image
 }
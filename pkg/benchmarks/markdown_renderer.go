@@ -0,0 +1,122 @@
+package benchmarks
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MarkdownRenderer produces a GitHub-flavored Markdown table for pasting into
+// issues, pull requests and READMEs.
+type MarkdownRenderer struct{}
+
+func NewMarkdownRenderer() *MarkdownRenderer { return &MarkdownRenderer{} }
+
+// RenderMarkdown generates the report as a Markdown table with the leader of
+// each benchmark in bold.
+func (r *MarkdownRenderer) RenderMarkdown(report *BenchmarkReport) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## 📊 AI Benchmark Report — %s\n\n", report.Date))
+
+	// Header
+	sb.WriteString("| Benchmark |")
+	for _, m := range report.Models {
+		name := mdEscape(m.Name)
+		if m.Thinking != "" {
+			name += " <sub>" + mdEscape(m.Thinking) + "</sub>"
+		}
+		if prev := report.Baselines[m.Name]; prev != "" {
+			name += " <sub>vs " + mdEscape(prev) + "</sub>"
+		}
+		sb.WriteString(" " + name + " |")
+	}
+	sb.WriteString("\n|:---|")
+	sb.WriteString(strings.Repeat(":---:|", len(report.Models)))
+	sb.WriteString("\n")
+
+	if row, ok := report.CompositeRow(CategoryWeights); ok {
+		r.writeSummaryRow(&sb, report, CompositeCategory.Emoji+" "+row.Label, row)
+	}
+
+	for _, cat := range Categories {
+		benches := benchmarksForCategory(cat.ID)
+		if len(benches) == 0 {
+			continue
+		}
+		r.writeCategoryRow(&sb, report, cat)
+		for _, bench := range benches {
+			if len(bench.Variants) == 0 {
+				r.writeScoreRow(&sb, report, bench, "")
+				continue
+			}
+			for _, v := range bench.Variants {
+				r.writeScoreRow(&sb, report, bench, v)
+			}
+		}
+	}
+
+	if rows := report.CostRows(); len(rows) > 0 {
+		r.writeCategoryRow(&sb, report, CostCategory)
+		for _, row := range rows {
+			r.writeSummaryRow(&sb, report, row.Label, row)
+		}
+	}
+
+	sb.WriteString("\n<sub>**Bold** = highest score per benchmark · " + mdEscape(ProvenanceLegend) + "</sub>\n")
+	return sb.String()
+}
+
+func (r *MarkdownRenderer) writeCategoryRow(sb *strings.Builder, report *BenchmarkReport, cat CategoryMeta) {
+	sb.WriteString(fmt.Sprintf("| **%s %s** |", cat.Emoji, mdEscape(cat.Label)))
+	sb.WriteString(strings.Repeat(" |", len(report.Models)))
+	sb.WriteString("\n")
+}
+
+func (r *MarkdownRenderer) writeScoreRow(sb *strings.Builder, report *BenchmarkReport, bench BenchmarkDef, variant string) {
+	name := bench.Name
+	if variant != "" {
+		name += " · " + variant
+	}
+	sb.WriteString(fmt.Sprintf("| %s <sub>%s</sub> |", mdEscape(name), bench.Unit))
+
+	for _, m := range report.Models {
+		score, exists := report.GetScore(bench.ID, variant, m.Name)
+		if !exists {
+			sb.WriteString(" — |")
+			continue
+		}
+		cell := formatScore(score, bench.Unit)
+		if report.IsHighest(bench.ID, variant, m.Name) {
+			cell = "**" + cell + "**"
+		}
+		// "*" would start emphasis, so marks are escaped
+		cell += mdEscape(report.ProvenanceMark(bench.ID, variant, m.Name))
+		if delta := genDeltaText(report, bench, variant, m.Name); delta != "" {
+			cell += " <sub>" + delta + "</sub>"
+		}
+		sb.WriteString(" " + cell + " |")
+	}
+	sb.WriteString("\n")
+}
+
+func (r *MarkdownRenderer) writeSummaryRow(sb *strings.Builder, report *BenchmarkReport, label string, row SummaryRow) {
+	sb.WriteString(fmt.Sprintf("| **%s** |", mdEscape(label)))
+	for _, m := range report.Models {
+		cell, ok := row.Cells[m.Name]
+		switch {
+		case !ok:
+			sb.WriteString(" — |")
+		case m.Name == row.Best:
+			sb.WriteString(" **" + mdEscape(cell.Text) + "** |")
+		default:
+			sb.WriteString(" " + mdEscape(cell.Text) + " |")
+		}
+	}
+	sb.WriteString("\n")
+}
+
+var mdEscaper = strings.NewReplacer(`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`", "<", "&lt;")
+
+// mdEscape escapes text so it renders literally inside a table cell.
+func mdEscape(s string) string {
+	return mdEscaper.Replace(s)
+}
@@ -0,0 +1,29 @@
+package benchmarks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	report := NewReport(DefaultModels[:2], "2026-02-20")
+	report.SetScore("gpqa_diamond", "", "Gemini 3.1 Pro", 94.3)
+	report.SetScore("gpqa_diamond", "", "Gemini 3 Pro", 91.9)
+	report.SetProvenance("gpqa_diamond", "", "Gemini 3 Pro", Provenance{SourceType: SourceVendor})
+	report.SetScore("hle", "No tools", "Gemini 3 Pro", 37.5)
+
+	md := NewMarkdownRenderer().RenderMarkdown(report)
+	for _, want := range []string{
+		"| Benchmark | Gemini 3.1 Pro <sub>High</sub> | Gemini 3 Pro <sub>High</sub> |\n|:---|:---:|:---:|\n",
+		"| GPQA Diamond <sub>%</sub> | **94.3%** | 91.9%\\* |",
+		"| Humanity's Last Exam · No tools <sub>%</sub> | — | **37.5%** |",
+		"| **🏆 Composite** |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown missing %q\n%s", want, md)
+		}
+	}
+	if got := mdEscape("a|b*c"); got != `a\|b\*c` {
+		t.Errorf("mdEscape = %q", got)
+	}
+}
@@ -1,11 +1,14 @@
 // Package benchmarks provides shared benchmark tracking for AI model evaluation.
 //
-// It defines 16 standardized benchmarks across 7 capability categories,
+// It defines 18 standardized benchmarks across 8 capability categories,
 // supports structured data storage, multi-source scraping, and dual rendering
 // (HTML table + PNG image).
 package benchmarks
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // ---- Capability Categories ----
 
@@ -17,6 +20,7 @@ const (
 	CatMultimodal  = "multimodal"
 	CatKnowledge   = "knowledge"
 	CatLongContext = "long_context"
+	CatArena       = "arena"
 )
 
 // CategoryMeta holds display info for each category.
@@ -36,20 +40,21 @@ var Categories = []CategoryMeta{
 	{CatMultimodal, "Multimodal", "🖼", "#ef5350"},
 	{CatKnowledge, "Knowledge", "📚", "#26c6da"},
 	{CatLongContext, "Long Context", "🧾", "#78909c"},
+	{CatArena, "Arena", "🏟", "#ffca28"},
 }
 
 // ---- Benchmark Definitions ----
 
 // BenchmarkDef defines a benchmark standard test.
 type BenchmarkDef struct {
-	ID       string   // unique key, e.g. "swe_bench_verified"
-	Name     string   // display name, e.g. "SWE-Bench Verified"
-	Category string   // one of Cat* constants
-	Unit     string   // "%" or "Elo"
-	Variants []string // sub-tests, e.g. ["No tools", "Search+Code"]
+	ID       string   `yaml:"id"`                 // unique key, e.g. "swe_bench_verified"
+	Name     string   `yaml:"name"`               // display name, e.g. "SWE-Bench Verified"
+	Category string   `yaml:"category"`           // one of Cat* constants, or a new category ID
+	Unit     string   `yaml:"unit"`               // "%" or "Elo"
+	Variants []string `yaml:"variants,omitempty"` // sub-tests, e.g. ["No tools", "Search+Code"]
 }
 
-// AllBenchmarks defines all 16 tracked benchmarks in display order.
+// AllBenchmarks defines all 18 tracked benchmarks in display order.
 var AllBenchmarks = []BenchmarkDef{
 	// 🧠 Reasoning
 	{
@@ -125,6 +130,11 @@ var AllBenchmarks = []BenchmarkDef{
 		ID: "mmmlu", Name: "MMMLU",
 		Category: CatKnowledge, Unit: "%",
 	},
+	{
+		ID: "open_llm_leaderboard", Name: "Open LLM Leaderboard",
+		Category: CatKnowledge, Unit: "%",
+		Variants: []string{"Average", "IFEval", "BBH", "MATH Lvl 5", "GPQA", "MuSR", "MMLU-Pro"},
+	},
 
 	// 🧾 Long Context
 	{
@@ -132,6 +142,13 @@ var AllBenchmarks = []BenchmarkDef{
 		Category: CatLongContext, Unit: "%",
 		Variants: []string{"128k (avg)", "1M (pointwise)"},
 	},
+
+	// 🏟 Arena
+	{
+		ID: "lmarena", Name: "LMArena (Text)",
+		Category: CatArena, Unit: "Elo",
+		Variants: []string{"Overall", "Hard Prompts", "Coding", "Math", "Creative Writing"},
+	},
 }
 
 // BenchmarksByCategory returns benchmarks grouped by category in display order.
@@ -153,6 +170,45 @@ func FindBenchmark(id string) *BenchmarkDef {
 	return nil
 }
 
+// RegisterBenchmarks merges user-defined benchmarks into AllBenchmarks. A
+// definition with a built-in ID replaces it in place; new ones are appended.
+// Unknown categories are added to Categories with a neutral style.
+func RegisterBenchmarks(defs []BenchmarkDef) error {
+	for _, def := range defs {
+		if def.ID == "" || def.Name == "" {
+			return fmt.Errorf("benchmark %q: id and name are required", def.ID)
+		}
+		if def.Unit == "" {
+			def.Unit = "%"
+		}
+		if def.Unit != "%" && def.Unit != "Elo" {
+			return fmt.Errorf("benchmark %s: unit must be %q or %q, got %q", def.ID, "%", "Elo", def.Unit)
+		}
+		if def.Category == "" {
+			return fmt.Errorf("benchmark %s: category is required", def.ID)
+		}
+		if !knownCategory(def.Category) {
+			Categories = append(Categories, CategoryMeta{def.Category, def.Category, "📌", "#9e9e9e"})
+		}
+
+		if existing := FindBenchmark(def.ID); existing != nil {
+			*existing = def
+		} else {
+			AllBenchmarks = append(AllBenchmarks, def)
+		}
+	}
+	return nil
+}
+
+func knownCategory(id string) bool {
+	for _, c := range Categories {
+		if c.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
 // ---- Score Data ----
 
 // BenchmarkScore holds one benchmark result for a specific model.
@@ -165,6 +221,25 @@ type BenchmarkScore struct {
 	Score         float64   `json:"score"`
 	SourceURL     string    `json:"source_url"`
 	ScrapedAt     time.Time `json:"scraped_at"`
+	SourceType    string    `json:"source_type,omitempty"` // one of the Source* constants; empty if unknown
+	Confidence    float64   `json:"confidence,omitempty"`  // extraction confidence in [0, 1]; 0 if unknown
+	Notes         string    `json:"notes,omitempty"`
+}
+
+// Score source types, from least to most independent.
+const (
+	SourceVendor       = "vendor"        // self-reported by the model's developer
+	SourceLLMExtracted = "llm_extracted" // pulled from a page by an LLM; may be misread
+	SourceThirdParty   = "third_party"   // measured by an independent leaderboard
+)
+
+// Provenance describes where a report score came from.
+type Provenance struct {
+	SourceType string
+	Confidence float64
+	Notes      string
+	SourceURL  string
+	ScrapedAt  time.Time
 }
 
 // ---- Model Configuration ----
@@ -176,6 +251,8 @@ type ModelConfig struct {
 	Thinking     string `json:"thinking,omitempty" yaml:"thinking"` // "High" / "Max"
 	Gen          string `json:"gen,omitempty" yaml:"gen"`           // "latest" / "previous"
 	DisplayOrder int    `json:"display_order" yaml:"display_order"`
+	// Pricing overrides scraped pricing field by field.
+	Pricing *ModelPricing `json:"pricing,omitempty" yaml:"pricing,omitempty"`
 }
 
 // DefaultModels returns the default 12 model columns.
@@ -220,11 +297,21 @@ func ProviderColor(provider string) string {
 type BenchmarkReport struct {
 	Models     []ModelConfig
 	Benchmarks []BenchmarkDef
-	Scores     map[string]map[string]float64 // [benchmarkID+variant][modelName] → score
-	HighestOf  map[string]string             // [benchmarkID+variant] → modelName (highest scorer)
+	Scores     map[string]map[string]float64    // [benchmarkID+variant][modelName] → score
+	HighestOf  map[string]string                // [benchmarkID+variant] → modelName (highest scorer)
+	History    map[string]map[string][]float64  // [benchmarkID+variant][modelName] → daily scores, oldest first
+	Provenance map[string]map[string]Provenance // [benchmarkID+variant][modelName] → score source
+	Pricing    map[string]ModelPricing          // [modelName] → price and speed
 	Date       string
+
+	// Set by BestOfProviders only
+	Baselines      map[string]string             // [modelName] → previous-generation model it is compared with
+	BaselineScores map[string]map[string]float64 // [benchmarkID+variant][modelName] → previous generation's score
 }
 
+// HistoryPoints is the number of scrape days kept per score in report history.
+const HistoryPoints = 8
+
 // ScoreKey builds a lookup key for the Scores map.
 func ScoreKey(benchmarkID, variant string) string {
 	if variant == "" {
@@ -240,7 +327,13 @@ func NewReport(models []ModelConfig, date string) *BenchmarkReport {
 		Benchmarks: AllBenchmarks,
 		Scores:     make(map[string]map[string]float64),
 		HighestOf:  make(map[string]string),
+		History:    make(map[string]map[string][]float64),
+		Provenance: make(map[string]map[string]Provenance),
+		Pricing:    make(map[string]ModelPricing),
 		Date:       date,
+
+		Baselines:      make(map[string]string),
+		BaselineScores: make(map[string]map[string]float64),
 	}
 }
 
@@ -268,6 +361,84 @@ func (r *BenchmarkReport) GetScore(benchmarkID, variant, modelName string) (floa
 	return 0, false
 }
 
+// AddHistory appends a past score (in chronological order), keeping the last HistoryPoints.
+func (r *BenchmarkReport) AddHistory(benchmarkID, variant, modelName string, score float64) {
+	key := ScoreKey(benchmarkID, variant)
+	if r.History[key] == nil {
+		r.History[key] = make(map[string][]float64)
+	}
+	h := append(r.History[key][modelName], score)
+	if len(h) > HistoryPoints {
+		h = h[len(h)-HistoryPoints:]
+	}
+	r.History[key][modelName] = h
+}
+
+// Trend returns a model's recent scores on a benchmark, oldest first.
+func (r *BenchmarkReport) Trend(benchmarkID, variant, modelName string) []float64 {
+	return r.History[ScoreKey(benchmarkID, variant)][modelName]
+}
+
+// Delta returns the change since the previous scrape, and whether there was one.
+func (r *BenchmarkReport) Delta(benchmarkID, variant, modelName string) (float64, bool) {
+	h := r.Trend(benchmarkID, variant, modelName)
+	if len(h) < 2 {
+		return 0, false
+	}
+	return h[len(h)-1] - h[len(h)-2], true
+}
+
+// Sparkline renders values as a compact block-character chart, e.g. "▁▃▅█".
+func Sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	const bars = "▁▂▃▄▅▆▇█"
+	blocks := []rune(bars)
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+	out := make([]rune, len(values))
+	for i, v := range values {
+		idx := len(blocks) / 2
+		if hi > lo {
+			idx = int((v - lo) / (hi - lo) * float64(len(blocks)-1))
+		}
+		out[i] = blocks[idx]
+	}
+	return string(out)
+}
+
+// SetProvenance records where a model's score on a benchmark came from.
+func (r *BenchmarkReport) SetProvenance(benchmarkID, variant, modelName string, p Provenance) {
+	key := ScoreKey(benchmarkID, variant)
+	if r.Provenance[key] == nil {
+		r.Provenance[key] = make(map[string]Provenance)
+	}
+	r.Provenance[key][modelName] = p
+}
+
+// GetProvenance returns where a score came from; the zero value if unknown.
+func (r *BenchmarkReport) GetProvenance(benchmarkID, variant, modelName string) Provenance {
+	return r.Provenance[ScoreKey(benchmarkID, variant)][modelName]
+}
+
+// ProvenanceMark returns the marker renderers append to a score: "*" for
+// vendor-reported numbers, "~" for LLM-extracted ones, and nothing for
+// independent or unknown sources.
+func (r *BenchmarkReport) ProvenanceMark(benchmarkID, variant, modelName string) string {
+	switch r.GetProvenance(benchmarkID, variant, modelName).SourceType {
+	case SourceVendor:
+		return "*"
+	case SourceLLMExtracted:
+		return "~"
+	default:
+		return ""
+	}
+}
+
 // IsHighest checks if a model has the highest score for a benchmark.
 func (r *BenchmarkReport) IsHighest(benchmarkID, variant, modelName string) bool {
 	key := ScoreKey(benchmarkID, variant)
@@ -0,0 +1,255 @@
+package benchmarks
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// RenderPage generates a self-contained interactive HTML page: click a
+// column header to sort, toggle model columns and categories, and hover a
+// score for its source and scrape date. Unlike RenderHTML, which must survive
+// email clients, the page relies on inline JavaScript.
+func (r *HTMLRenderer) RenderPage(report *BenchmarkReport) string {
+	var sb strings.Builder
+
+	sb.WriteString(`<!DOCTYPE html>
+<html lang="en"><head><meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>`)
+	sb.WriteString(html.EscapeString("AI Benchmark Report — " + report.Date))
+	sb.WriteString(`</title>
+<style>` + pageCSS + `</style>
+</head><body>
+`)
+	sb.WriteString(fmt.Sprintf(`<h1>📊 AI Benchmark Report <span class="sub">%s · %d models</span></h1>
+`, html.EscapeString(report.Date), len(report.Models)))
+
+	// Controls
+	sb.WriteString(`<div class="controls"><div><b>Categories</b>`)
+	for _, cat := range Categories {
+		if len(benchmarksForCategory(cat.ID)) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf(`<label><input type="checkbox" data-toggle-category="%s" checked> %s %s</label>`,
+			html.EscapeString(cat.ID), cat.Emoji, html.EscapeString(cat.Label)))
+	}
+	if len(report.CostRows()) > 0 {
+		sb.WriteString(fmt.Sprintf(`<label><input type="checkbox" data-toggle-category="%s" checked> %s %s</label>`,
+			CostCategory.ID, CostCategory.Emoji, html.EscapeString(CostCategory.Label)))
+	}
+	sb.WriteString(`</div><div><b>Models</b>`)
+	for i, m := range report.Models {
+		sb.WriteString(fmt.Sprintf(`<label><input type="checkbox" data-toggle-model="%d" checked> <span class="dot" style="background:%s"></span>%s</label>`,
+			i, ProviderColor(m.Provider), html.EscapeString(m.Name)))
+	}
+	sb.WriteString(`</div></div>
+`)
+
+	// Table
+	sb.WriteString(`<table id="report"><thead><tr><th data-sort="name">Benchmark</th>`)
+	for i, m := range report.Models {
+		baseline := ""
+		if prev := report.Baselines[m.Name]; prev != "" {
+			baseline = `<br><span class="unit">vs ` + html.EscapeString(prev) + `</span>`
+		}
+		sb.WriteString(fmt.Sprintf(`<th data-sort="%d" data-model="%d"><span class="dot" style="background:%s"></span>%s%s</th>`,
+			i, i, ProviderColor(m.Provider), html.EscapeString(m.Name), baseline))
+	}
+	sb.WriteString("</tr></thead>\n")
+
+	if row, ok := report.CompositeRow(CategoryWeights); ok {
+		sb.WriteString(`<tbody data-category="overall">`)
+		writePageSummaryRow(&sb, report, row, CompositeCategory.Color)
+		sb.WriteString("</tbody>\n")
+	}
+
+	for _, cat := range Categories {
+		benches := benchmarksForCategory(cat.ID)
+		if len(benches) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf(`<tbody data-category="%s"><tr class="cat"><td colspan="%d" style="border-left:4px solid %s;color:%s">%s %s</td></tr>
+`, html.EscapeString(cat.ID), len(report.Models)+1, cat.Color, cat.Color, cat.Emoji, html.EscapeString(cat.Label)))
+		for _, bench := range benches {
+			variants := bench.Variants
+			if len(variants) == 0 {
+				variants = []string{""}
+			}
+			for _, v := range variants {
+				r.writePageRow(&sb, report, bench, v)
+			}
+		}
+		sb.WriteString("</tbody>\n")
+	}
+	if rows := report.CostRows(); len(rows) > 0 {
+		cat := CostCategory
+		sb.WriteString(fmt.Sprintf(`<tbody data-category="%s"><tr class="cat"><td colspan="%d" style="border-left:4px solid %s;color:%s">%s %s</td></tr>
+`, cat.ID, len(report.Models)+1, cat.Color, cat.Color, cat.Emoji, html.EscapeString(cat.Label)))
+		for _, row := range rows {
+			writePageSummaryRow(&sb, report, row, cat.Color)
+		}
+		sb.WriteString("</tbody>\n")
+	}
+	sb.WriteString(fmt.Sprintf(`</table>
+<p class="legend">Red = highest score per benchmark · %s</p>
+<script>%s</script>
+</body></html>
+`, html.EscapeString(ProvenanceLegend), pageJS))
+	return sb.String()
+}
+
+func (r *HTMLRenderer) writePageRow(sb *strings.Builder, report *BenchmarkReport, bench BenchmarkDef, variant string) {
+	name := bench.Name
+	if variant != "" {
+		name += " · " + variant
+	}
+	sb.WriteString(fmt.Sprintf(`<tr class="row"><td data-value="%s">%s <span class="unit">%s</span></td>`,
+		html.EscapeString(strings.ToLower(name)), html.EscapeString(name), bench.Unit))
+
+	for i, m := range report.Models {
+		score, exists := report.GetScore(bench.ID, variant, m.Name)
+		if !exists {
+			sb.WriteString(fmt.Sprintf(`<td data-model="%d" class="missing">—</td>`, i))
+			continue
+		}
+		class := "score"
+		if report.IsHighest(bench.ID, variant, m.Name) {
+			class += " top"
+		}
+		if report.ProvenanceMark(bench.ID, variant, m.Name) != "" {
+			class += " reported"
+		}
+		sb.WriteString(fmt.Sprintf(`<td data-model="%d" data-value="%g" class="%s" title="%s">%s%s%s</td>`,
+			i, score, class, html.EscapeString(scoreTooltip(report, bench, variant, m.Name)),
+			htmlFormatScore(score, bench.Unit), html.EscapeString(report.ProvenanceMark(bench.ID, variant, m.Name)),
+			htmlGenDelta(report, bench, variant, m.Name)))
+	}
+	sb.WriteString("</tr>\n")
+}
+
+// writePageSummaryRow writes a computed row; it sorts with the benchmark rows
+// of its section and highlights its best cell in accent.
+func writePageSummaryRow(sb *strings.Builder, report *BenchmarkReport, row SummaryRow, accent string) {
+	sb.WriteString(fmt.Sprintf(`<tr class="row"><td data-value="%s">%s</td>`,
+		html.EscapeString(strings.ToLower(row.Label)), html.EscapeString(row.Label)))
+	for i, m := range report.Models {
+		cell, ok := row.Cells[m.Name]
+		if !ok {
+			sb.WriteString(fmt.Sprintf(`<td data-model="%d" class="missing">—</td>`, i))
+			continue
+		}
+		style := ""
+		if m.Name == row.Best {
+			style = fmt.Sprintf(` style="color:%s;font-weight:700"`, accent)
+		}
+		sb.WriteString(fmt.Sprintf(`<td data-model="%d" data-value="%g"%s>%s</td>`,
+			i, cell.Value, style, html.EscapeString(cell.Text)))
+	}
+	sb.WriteString("</tr>\n")
+}
+
+// scoreTooltip describes where a score came from and when it was scraped.
+func scoreTooltip(report *BenchmarkReport, bench BenchmarkDef, variant, modelName string) string {
+	p := report.GetProvenance(bench.ID, variant, modelName)
+	var lines []string
+	if p.SourceURL != "" {
+		lines = append(lines, "Source: "+p.SourceURL)
+	}
+	if !p.ScrapedAt.IsZero() {
+		lines = append(lines, "Scraped: "+p.ScrapedAt.Format("2006-01-02 15:04"))
+	}
+	switch p.SourceType {
+	case SourceVendor:
+		lines = append(lines, "Vendor-reported")
+	case SourceLLMExtracted:
+		lines = append(lines, fmt.Sprintf("LLM-extracted (confidence %.0f%%)", p.Confidence*100))
+	case SourceThirdParty:
+		lines = append(lines, "Independent leaderboard")
+	}
+	if p.Notes != "" {
+		lines = append(lines, p.Notes)
+	}
+	if delta, ok := report.Delta(bench.ID, variant, modelName); ok && delta != 0 {
+		lines = append(lines, fmt.Sprintf("Change since last scrape: %+.1f", delta))
+	}
+	if text := genDeltaText(report, bench, variant, modelName); text != "" {
+		lines = append(lines, fmt.Sprintf("vs %s: %s", report.Baselines[modelName], text))
+	}
+	return strings.Join(lines, "\n")
+}
+
+const pageCSS = `
+body{margin:0;padding:24px;background:#0a0a1a;color:#e0e0e0;font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,'Noto Sans CJK SC',sans-serif;font-size:14px}
+h1{font-size:22px;margin:0 0 16px}
+h1 .sub{font-size:14px;color:#808090;font-weight:400;margin-left:8px}
+.controls{display:flex;flex-wrap:wrap;gap:16px;margin-bottom:16px;padding:12px;background:#141428;border-radius:8px}
+.controls div{display:flex;flex-wrap:wrap;gap:10px;align-items:center}
+.controls label{cursor:pointer;color:#aaaacc;white-space:nowrap}
+.dot{display:inline-block;width:8px;height:8px;border-radius:50%;margin-right:4px}
+table{border-collapse:collapse;width:100%;background:#0f0f23}
+th{position:sticky;top:0;background:#141428;color:#aaaacc;padding:10px 8px;cursor:pointer;user-select:none;border-bottom:2px solid #1a1a3e}
+th:first-child{text-align:left}
+th.asc::after{content:" ▲"}th.desc::after{content:" ▼"}
+td{padding:8px;text-align:center;border-bottom:1px solid rgba(255,255,255,.04)}
+td:first-child{text-align:left;color:#c0c0d0}
+tr.cat td{background:#0d0d1f;font-weight:700;text-align:left;padding:10px 12px}
+.unit{color:#444460;font-size:11px}
+.missing{color:#404050}
+.score{cursor:help}
+.reported{color:#9a9ab0}
+.top{color:#ff4757;font-weight:700;background:rgba(255,45,85,.12)}
+.hidden{display:none}
+.legend{color:#555570;font-size:12px}
+`
+
+const pageJS = `
+(function () {
+  var table = document.getElementById('report');
+  var state = {col: null, dir: 1};
+
+  // Sort benchmark rows within each category by the clicked column
+  table.querySelectorAll('th[data-sort]').forEach(function (th) {
+    th.addEventListener('click', function () {
+      var col = th.getAttribute('data-sort');
+      state.dir = state.col === col ? -state.dir : (col === 'name' ? 1 : -1);
+      state.col = col;
+      table.querySelectorAll('th').forEach(function (h) { h.classList.remove('asc', 'desc'); });
+      th.classList.add(state.dir > 0 ? 'asc' : 'desc');
+      table.querySelectorAll('tbody').forEach(function (body) {
+        var rows = Array.prototype.slice.call(body.querySelectorAll('tr.row'));
+        rows.sort(function (a, b) {
+          var va = value(a, col), vb = value(b, col);
+          if (va === null) return 1;
+          if (vb === null) return -1;
+          return (va < vb ? -1 : va > vb ? 1 : 0) * state.dir;
+        });
+        rows.forEach(function (row) { body.appendChild(row); });
+      });
+    });
+  });
+
+  function value(row, col) {
+    var cell = col === 'name' ? row.cells[0] : row.querySelector('td[data-model="' + col + '"]');
+    var v = cell && cell.getAttribute('data-value');
+    if (v === null || v === undefined) return null;
+    return col === 'name' ? v : parseFloat(v);
+  }
+
+  document.querySelectorAll('[data-toggle-model]').forEach(function (box) {
+    box.addEventListener('change', function () {
+      var i = box.getAttribute('data-toggle-model');
+      table.querySelectorAll('[data-model="' + i + '"]').forEach(function (el) {
+        el.classList.toggle('hidden', !box.checked);
+      });
+    });
+  });
+
+  document.querySelectorAll('[data-toggle-category]').forEach(function (box) {
+    box.addEventListener('change', function () {
+      var body = table.querySelector('tbody[data-category="' + box.getAttribute('data-toggle-category') + '"]');
+      if (body) body.classList.toggle('hidden', !box.checked);
+    });
+  });
+})();
+`
@@ -0,0 +1,32 @@
+package benchmarks
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderPage(t *testing.T) {
+	report := NewReport(DefaultModels[:2], "2026-02-20")
+	report.SetScore("gpqa_diamond", "", "Gemini 3.1 Pro", 94.3)
+	report.SetScore("gpqa_diamond", "", "Gemini 3 Pro", 91.9)
+	report.SetProvenance("gpqa_diamond", "", "Gemini 3.1 Pro", Provenance{
+		SourceType: SourceVendor,
+		SourceURL:  "https://example.com/evals?a=1&b=2",
+		ScrapedAt:  time.Date(2026, 2, 19, 8, 30, 0, 0, time.UTC),
+	})
+
+	page := NewHTMLRenderer().RenderPage(report)
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		`<th data-sort="1" data-model="1">`,
+		`<tbody data-category="reasoning">`,
+		`data-toggle-category="coding"`,
+		"data-model=\"0\" data-value=\"94.3\" class=\"score top reported\" title=\"Source: https://example.com/evals?a=1&amp;b=2\nScraped: 2026-02-19 08:30\nVendor-reported\">94.3%*</td>",
+		"<script>",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page missing %q", want)
+		}
+	}
+}
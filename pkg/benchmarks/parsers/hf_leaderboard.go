@@ -0,0 +1,132 @@
+package parsers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/benchmarks"
+)
+
+// HFLeaderboardParser fetches open-weight model scores from the Hugging Face
+// Open LLM Leaderboard through the datasets-server API. Only models from
+// official providers are read; community fine-tunes would otherwise match
+// tracked base models by name.
+type HFLeaderboardParser struct {
+	models  []benchmarks.ModelConfig
+	baseURL string
+}
+
+const (
+	hfDatasetsServer = "https://datasets-server.huggingface.co"
+	hfLeaderboardURL = "https://huggingface.co/spaces/open-llm-leaderboard/open_llm_leaderboard"
+	hfPageSize       = 100
+	hfMaxRows        = 2000
+)
+
+// Leaderboard column → open_llm_leaderboard variant
+var hfColumns = map[string]string{
+	"Average ⬆️": "Average",
+	"IFEval":     "IFEval",
+	"BBH":        "BBH",
+	"MATH Lvl 5": "MATH Lvl 5",
+	"GPQA":       "GPQA",
+	"MUSR":       "MuSR",
+	"MMLU-PRO":   "MMLU-Pro",
+}
+
+// NewHFLeaderboardParser creates a parser for the Open LLM Leaderboard.
+func NewHFLeaderboardParser(models []benchmarks.ModelConfig) *HFLeaderboardParser {
+	return &HFLeaderboardParser{models: models, baseURL: hfDatasetsServer}
+}
+
+func (p *HFLeaderboardParser) Name() string { return "huggingface-open-llm-leaderboard" }
+
+// hfRows is a page of the datasets-server /filter response.
+type hfRows struct {
+	Rows []struct {
+		Row map[string]any `json:"row"`
+	} `json:"rows"`
+	NumRowsTotal int `json:"num_rows_total"`
+}
+
+func (p *HFLeaderboardParser) Parse(ctx context.Context, client *http.Client) ([]benchmarks.BenchmarkScore, error) {
+	// Best entry (highest average) per tracked model
+	best := make(map[string]map[string]any)
+	var matched []*benchmarks.ModelConfig
+
+	for offset := 0; offset < hfMaxRows; offset += hfPageSize {
+		q := url.Values{
+			"dataset": {"open-llm-leaderboard/contents"},
+			"config":  {"default"},
+			"split":   {"train"},
+			"where":   {`"Official Providers"=true`},
+			"offset":  {fmt.Sprint(offset)},
+			"length":  {fmt.Sprint(hfPageSize)},
+		}
+		body, err := benchmarks.FetchURL(ctx, client, p.baseURL+"/filter?"+q.Encode())
+		if err != nil {
+			if len(best) > 0 {
+				break // keep what earlier pages returned
+			}
+			return nil, err
+		}
+		var page hfRows
+		if err := json.Unmarshal([]byte(body), &page); err != nil {
+			return nil, fmt.Errorf("decode leaderboard: %w", err)
+		}
+
+		for _, r := range page.Rows {
+			fullname, _ := r.Row["fullname"].(string)
+			// "Qwen/Qwen3-235B-A22B" → match on the repo name
+			_, repo, ok := strings.Cut(fullname, "/")
+			if !ok {
+				repo = fullname
+			}
+			model, found := matchModel(p.Name(), repo, p.models)
+			if !found {
+				continue
+			}
+			avg, _ := r.Row["Average ⬆️"].(float64)
+			if prev, ok := best[model.Name]; ok {
+				if prevAvg, _ := prev["Average ⬆️"].(float64); prevAvg >= avg {
+					continue
+				}
+			} else {
+				matched = append(matched, model)
+			}
+			best[model.Name] = r.Row
+		}
+
+		if len(page.Rows) < hfPageSize || offset+hfPageSize >= page.NumRowsTotal {
+			break
+		}
+	}
+
+	var scores []benchmarks.BenchmarkScore
+	for _, model := range matched {
+		row := best[model.Name]
+		fullname, _ := row["fullname"].(string)
+		source := hfLeaderboardURL + "#/?search=" + url.QueryEscape(fullname)
+		for col, variant := range hfColumns {
+			score, ok := row[col].(float64)
+			if !ok {
+				continue
+			}
+			scores = append(scores, benchmarks.BenchmarkScore{
+				BenchmarkID:   "open_llm_leaderboard",
+				ModelName:     model.Name,
+				ModelProvider: model.Provider,
+				Variant:       variant,
+				Score:         score,
+				SourceURL:     source,
+				SourceType:    benchmarks.SourceThirdParty,
+				Confidence:    1,
+			})
+		}
+	}
+	return scores, nil
+}
@@ -0,0 +1,141 @@
+package parsers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/benchmarks"
+)
+
+// hfServer serves the rows of testdata/hf_leaderboard.json through a fake
+// datasets-server /filter endpoint. Community rows that match no tracked
+// model are put after the third row, so the fixture spans two pages; the
+// page at failOffset, if any, fails.
+func hfServer(t *testing.T, failOffset int) *httptest.Server {
+	t.Helper()
+	data, err := os.ReadFile("testdata/hf_leaderboard.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fixture struct {
+		Rows []json.RawMessage `json:"rows"`
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		t.Fatal(err)
+	}
+	rows := append([]json.RawMessage{}, fixture.Rows[:3]...)
+	for i := range 110 {
+		rows = append(rows, json.RawMessage(fmt.Sprintf(`{"row": {"fullname": "community/merge-%d", "Average ⬆️": 50}}`, i)))
+	}
+	rows = append(rows, fixture.Rows[3:]...)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/filter" || q.Get("dataset") != "open-llm-leaderboard/contents" || q.Get("where") != `"Official Providers"=true` {
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+			return
+		}
+		offset, _ := strconv.Atoi(q.Get("offset"))
+		length, _ := strconv.Atoi(q.Get("length"))
+		if offset == failOffset {
+			http.Error(w, "rate limited", http.StatusTooManyRequests)
+			return
+		}
+		page := rows[min(offset, len(rows)):min(offset+length, len(rows))]
+		json.NewEncoder(w).Encode(map[string]any{"rows": page, "num_rows_total": len(rows)})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// hfScores runs the parser against srv and returns its scores by model and
+// variant, with the source URL of each model.
+func hfScores(t *testing.T, srv *httptest.Server) (map[string]float64, map[string]string, error) {
+	t.Helper()
+	p := NewHFLeaderboardParser(benchmarks.DefaultModels)
+	p.baseURL = srv.URL
+	scores, err := p.Parse(context.Background(), srv.Client())
+	got, sources := map[string]float64{}, map[string]string{}
+	for _, s := range scores {
+		if s.BenchmarkID != "open_llm_leaderboard" || s.SourceType != benchmarks.SourceThirdParty {
+			t.Errorf("score %+v", s)
+		}
+		got[s.ModelName+"/"+s.Variant] = s.Score
+		sources[s.ModelName] = s.SourceURL
+	}
+	return got, sources, err
+}
+
+func TestHFLeaderboardParser(t *testing.T) {
+	got, sources, err := hfScores(t, hfServer(t, -1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]float64{
+		// The Instruct-2507 row on the second page has the higher average
+		"Qwen3-235B/Average":    44.73,
+		"Qwen3-235B/IFEval":     84.05,
+		"Qwen3-235B/BBH":        60.31,
+		"Qwen3-235B/MATH Lvl 5": 52.87,
+		"Qwen3-235B/GPQA":       18.46,
+		"Qwen3-235B/MuSR":       13.22,
+		"Qwen3-235B/MMLU-Pro":   39.47,
+		// The V3 row beats V3-Base; its missing MATH score is left out
+		"DeepSeek-V3/Average":   41.47,
+		"DeepSeek-V3/IFEval":    76.40,
+		"DeepSeek-V3/BBH":       58.90,
+		"DeepSeek-V3/GPQA":      16.20,
+		"DeepSeek-V3/MuSR":      14.75,
+		"DeepSeek-V3/MMLU-Pro":  41.10,
+		"MiniMax-M1/Average":    35.06,
+		"MiniMax-M1/IFEval":     62.77,
+		"MiniMax-M1/BBH":        49.13,
+		"MiniMax-M1/MATH Lvl 5": 33.61,
+		"MiniMax-M1/GPQA":       12.08,
+		"MiniMax-M1/MuSR":       11.94,
+		"MiniMax-M1/MMLU-Pro":   40.83,
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d scores, want %d: %v", len(got), len(want), got)
+	}
+	for key, score := range want {
+		if got[key] != score {
+			t.Errorf("%s = %v, want %v", key, got[key], score)
+		}
+	}
+	if want := hfLeaderboardURL + "#/?search=" + url.QueryEscape("Qwen/Qwen3-235B-A22B-Instruct-2507"); sources["Qwen3-235B"] != want {
+		t.Errorf("source = %s, want %s", sources["Qwen3-235B"], want)
+	}
+}
+
+func TestHFLeaderboardParserErrors(t *testing.T) {
+	// A failing later page keeps what the first one returned
+	got, _, err := hfScores(t, hfServer(t, hfPageSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["Qwen3-235B/Average"] != 38.21 || got["DeepSeek-V3/Average"] != 41.47 || got["MiniMax-M1/Average"] != 0 {
+		t.Errorf("scores from the first page = %v", got)
+	}
+
+	if _, _, err := hfScores(t, hfServer(t, 0)); err == nil || !strings.Contains(err.Error(), "status 429") {
+		t.Errorf("first page failing: %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>maintenance</html>"))
+	}))
+	defer srv.Close()
+	if _, _, err := hfScores(t, srv); err == nil || !strings.Contains(err.Error(), "decode leaderboard") {
+		t.Errorf("non-JSON page: %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/RobinCoderZhao/devkit-suite/pkg/benchmarks"
 	"github.com/RobinCoderZhao/devkit-suite/pkg/llm"
@@ -49,11 +50,24 @@ func (e *LLMExtractor) Parse(ctx context.Context, client *http.Client) ([]benchm
 		return nil, fmt.Errorf("LLM client required for vendor page extraction")
 	}
 
+	// Fetch page content via Jina Reader
+	pages := DefaultVendorPages()
+	urls := make([]string, len(pages))
+	for i, page := range pages {
+		urls[i] = page.URL
+	}
+	results := scraper.FetchAll(ctx, e.fetcher, urls, &scraper.BatchOptions{
+		Fetch: &scraper.FetchOptions{Timeout: 30 * time.Second},
+	})
+
 	var allScores []benchmarks.BenchmarkScore
 	var errors []string
-
-	for _, page := range DefaultVendorPages() {
-		scores, err := e.extractFromPage(ctx, page)
+	for i, page := range pages {
+		if results[i].Err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", page.URL, results[i].Err))
+			continue
+		}
+		scores, err := e.extractFromPage(ctx, page, results[i].Result)
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("%s: %v", page.URL, err))
 			continue
@@ -67,15 +81,7 @@ func (e *LLMExtractor) Parse(ctx context.Context, client *http.Client) ([]benchm
 	return allScores, nil
 }
 
-func (e *LLMExtractor) extractFromPage(ctx context.Context, page VendorPage) ([]benchmarks.BenchmarkScore, error) {
-	// Fetch page content via Jina Reader
-	result, err := e.fetcher.Fetch(ctx, page.URL, &scraper.FetchOptions{
-		Timeout: 30 * 1e9,
-	})
-	if err != nil {
-		return nil, err
-	}
-
+func (e *LLMExtractor) extractFromPage(ctx context.Context, page VendorPage, result *scraper.FetchResult) ([]benchmarks.BenchmarkScore, error) {
 	// Truncate to avoid excessive LLM cost
 	content := result.CleanText
 	if len(content) > 15000 {
@@ -99,6 +105,7 @@ Output a JSON array of objects with these fields:
 - "model": model name as written in the article
 - "score": numeric score value
 - "unit": "%%" or "Elo"
+- "confidence": how sure you are the number was read correctly, from 0 to 1
 
 If you cannot find any benchmark data, output an empty array [].
 Only output valid JSON, no explanation.
@@ -106,7 +113,7 @@ Only output valid JSON, no explanation.
 Article content:
 %s`, strings.Join(benchNames, ", "), content)
 
-	resp, err := e.llmClient.Generate(ctx, &llm.Request{
+	resp, err := e.llmClient.Generate(llm.ForUser(ctx, 0, "benchmarks.extract"), &llm.Request{
 		System:      "You are a data extraction assistant. Extract structured benchmark data from articles. Output valid JSON only.",
 		Messages:    []llm.Message{{Role: "user", Content: prompt}},
 		MaxTokens:   4096,
@@ -119,11 +126,12 @@ Article content:
 	// Parse LLM response
 	jsonStr := extractJSONArray(resp.Content)
 	var extracted []struct {
-		Benchmark string  `json:"benchmark"`
-		Variant   string  `json:"variant"`
-		Model     string  `json:"model"`
-		Score     float64 `json:"score"`
-		Unit      string  `json:"unit"`
+		Benchmark  string  `json:"benchmark"`
+		Variant    string  `json:"variant"`
+		Model      string  `json:"model"`
+		Score      float64 `json:"score"`
+		Unit       string  `json:"unit"`
+		Confidence float64 `json:"confidence"`
 	}
 	if err := json.Unmarshal([]byte(jsonStr), &extracted); err != nil {
 		return nil, fmt.Errorf("parse LLM response: %w", err)
@@ -138,6 +146,10 @@ Article content:
 			continue
 		}
 
+		confidence := item.Confidence
+		if confidence <= 0 || confidence > 1 {
+			confidence = defaultExtractConfidence
+		}
 		scores = append(scores, benchmarks.BenchmarkScore{
 			BenchmarkID:   benchID,
 			ModelName:     item.Model,
@@ -145,12 +157,18 @@ Article content:
 			Variant:       item.Variant,
 			Score:         item.Score,
 			SourceURL:     page.URL,
+			SourceType:    benchmarks.SourceLLMExtracted,
+			Confidence:    confidence,
+			Notes:         page.Provider + " evaluation page",
 		})
 	}
 
 	return scores, nil
 }
 
+// defaultExtractConfidence is used when the LLM omits or garbles its confidence.
+const defaultExtractConfidence = 0.7
+
 // findBenchmarkID finds the benchmark ID from its display name.
 func findBenchmarkID(name string) string {
 	nameLower := strings.ToLower(strings.TrimSpace(name))
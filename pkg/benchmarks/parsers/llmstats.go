@@ -3,8 +3,11 @@ package parsers
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/RobinCoderZhao/devkit-suite/pkg/benchmarks"
 	"github.com/RobinCoderZhao/devkit-suite/pkg/scraper"
@@ -35,13 +38,22 @@ func NewLLMStatsParser(fetcher scraper.Fetcher, models []benchmarks.ModelConfig)
 func (p *LLMStatsParser) Name() string { return "llm-stats.com" }
 
 func (p *LLMStatsParser) Parse(ctx context.Context, client *http.Client) ([]benchmarks.BenchmarkScore, error) {
+	benchIDs := slices.Sorted(maps.Keys(llmStatsURLs))
+	urls := make([]string, len(benchIDs))
+	for i, benchID := range benchIDs {
+		urls[i] = llmStatsURLs[benchID]
+	}
+	// Fetch via Jina Reader (JS-rendered pages)
+	results := scraper.FetchAll(ctx, p.fetcher, urls, &scraper.BatchOptions{
+		Fetch: &scraper.FetchOptions{Timeout: 30 * time.Second},
+	})
+
 	var allScores []benchmarks.BenchmarkScore
 	var errors []string
-
-	for benchID, url := range llmStatsURLs {
-		scores, err := p.parseBenchmarkPage(ctx, benchID, url)
+	for i, r := range results {
+		scores, err := p.parseBenchmarkPage(benchIDs[i], r)
 		if err != nil {
-			errors = append(errors, fmt.Sprintf("%s: %v", benchID, err))
+			errors = append(errors, fmt.Sprintf("%s: %v", benchIDs[i], err))
 			continue
 		}
 		allScores = append(allScores, scores...)
@@ -53,16 +65,12 @@ func (p *LLMStatsParser) Parse(ctx context.Context, client *http.Client) ([]benc
 	return allScores, nil
 }
 
-func (p *LLMStatsParser) parseBenchmarkPage(ctx context.Context, benchID, url string) ([]benchmarks.BenchmarkScore, error) {
-	// Fetch via Jina Reader (JS-rendered pages)
-	result, err := p.fetcher.Fetch(ctx, url, &scraper.FetchOptions{
-		Timeout: 30 * 1e9, // 30 seconds
-	})
-	if err != nil {
-		return nil, err
+func (p *LLMStatsParser) parseBenchmarkPage(benchID string, r scraper.BatchResult) ([]benchmarks.BenchmarkScore, error) {
+	if r.Err != nil {
+		return nil, r.Err
 	}
-
-	content := result.CleanText
+	url := r.URL
+	content := r.Result.CleanText
 	if content == "" {
 		return nil, fmt.Errorf("empty content from %s", url)
 	}
@@ -91,23 +99,27 @@ func (p *LLMStatsParser) parseBenchmarkPage(ctx context.Context, benchID, url st
 			continue
 		}
 
-		rawModelName := row[modelCol]
-		model, found := MatchModelName(rawModelName, p.models)
-		if !found {
-			continue // Skip models not in our tracking list
-		}
-
 		score, ok := ParseScore(row[scoreCol])
 		if !ok {
 			continue
 		}
 
+		rawModelName := row[modelCol]
+		model, found := matchScoredModel(p.Name(), rawModelName, p.models, benchID, "", score)
+		if !found {
+			continue // Skip models not in our tracking list
+		}
+
 		scores = append(scores, benchmarks.BenchmarkScore{
 			BenchmarkID:   benchID,
 			ModelName:     model.Name,
 			ModelProvider: model.Provider,
 			Score:         score,
 			SourceURL:     url,
+			// llm-stats.com aggregates numbers published by the model developers
+			SourceType: benchmarks.SourceVendor,
+			Confidence: 1,
+			Notes:      "via llm-stats.com",
 		})
 	}
 
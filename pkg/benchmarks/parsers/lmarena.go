@@ -0,0 +1,149 @@
+package parsers
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/benchmarks"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/scraper"
+)
+
+// LMArenaParser fetches Elo ratings from the LMArena (formerly Chatbot Arena)
+// text leaderboard. Each category breakdown has its own page and becomes a
+// variant of the "lmarena" benchmark.
+type LMArenaParser struct {
+	fetcher scraper.Fetcher
+	models  []benchmarks.ModelConfig
+}
+
+// LMArena leaderboard pages by variant
+var lmArenaURLs = map[string]string{
+	"Overall":          "https://lmarena.ai/leaderboard/text",
+	"Hard Prompts":     "https://lmarena.ai/leaderboard/text/hard-prompts",
+	"Coding":           "https://lmarena.ai/leaderboard/text/coding",
+	"Math":             "https://lmarena.ai/leaderboard/text/math",
+	"Creative Writing": "https://lmarena.ai/leaderboard/text/creative-writing",
+}
+
+// Arena model IDs often carry a release date, e.g. "claude-opus-4-6-20260205"
+var arenaDateSuffix = regexp.MustCompile(`[-_ ]\d{8}$`)
+
+// NewLMArenaParser creates a parser for the LMArena leaderboard.
+func NewLMArenaParser(fetcher scraper.Fetcher, models []benchmarks.ModelConfig) *LMArenaParser {
+	return &LMArenaParser{fetcher: fetcher, models: models}
+}
+
+const lmArenaSource = "lmarena.ai"
+
+func (p *LMArenaParser) Name() string { return lmArenaSource }
+
+func (p *LMArenaParser) Parse(ctx context.Context, client *http.Client) ([]benchmarks.BenchmarkScore, error) {
+	variants := slices.Sorted(maps.Keys(lmArenaURLs))
+	urls := make([]string, len(variants))
+	for i, variant := range variants {
+		urls[i] = lmArenaURLs[variant]
+	}
+	// The leaderboard is JS-rendered; the fetcher's fallback chain renders it
+	results := scraper.FetchAll(ctx, p.fetcher, urls, &scraper.BatchOptions{
+		Fetch: &scraper.FetchOptions{Timeout: 30 * time.Second},
+	})
+
+	var allScores []benchmarks.BenchmarkScore
+	var errors []string
+	for i, r := range results {
+		scores, err := p.parseLeaderboard(variants[i], r)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", variants[i], err))
+			continue
+		}
+		allScores = append(allScores, scores...)
+	}
+
+	if len(errors) > 0 && len(allScores) == 0 {
+		return nil, fmt.Errorf("all pages failed: %s", strings.Join(errors, "; "))
+	}
+	return allScores, nil
+}
+
+func (p *LMArenaParser) parseLeaderboard(variant string, r scraper.BatchResult) ([]benchmarks.BenchmarkScore, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	if r.Result.CleanText == "" {
+		return nil, fmt.Errorf("empty content from %s", r.URL)
+	}
+
+	rows := ExtractMarkdownTable(r.Result.CleanText)
+	if len(rows) < 2 {
+		return nil, fmt.Errorf("no table found in %s", r.URL)
+	}
+	scores := parseArenaTable(rows, variant, p.models)
+	for i := range scores {
+		scores[i].SourceURL = r.URL
+	}
+	return scores, nil
+}
+
+// parseArenaTable reads a leaderboard table. Rows before the header are
+// skipped: reader output starts with a "Title: Text Arena | LMArena" line
+// that splits like a table row. Rows are in rank order, so when several
+// entries map to one tracked model (e.g. thinking and non-thinking modes)
+// the best-ranked one is kept.
+func parseArenaTable(rows [][]string, variant string, models []benchmarks.ModelConfig) []benchmarks.BenchmarkScore {
+	modelCol, scoreCol := -1, -1
+	for len(rows) > 0 && (modelCol < 0 || scoreCol < 0) {
+		modelCol = findModelColumn(rows[0])
+		scoreCol = findArenaScoreColumn(rows[0])
+		rows = rows[1:]
+	}
+	if modelCol < 0 || scoreCol < 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var scores []benchmarks.BenchmarkScore
+	for _, row := range rows {
+		if modelCol >= len(row) || scoreCol >= len(row) {
+			continue
+		}
+		score, ok := ParseScore(row[scoreCol])
+		if !ok || score < 100 { // Elo ratings are in the hundreds or more
+			continue
+		}
+		raw := arenaDateSuffix.ReplaceAllString(cleanModelName(row[modelCol]), "")
+		model, found := matchScoredModel(lmArenaSource, raw, models, "lmarena", variant, score)
+		if !found || seen[model.Name] {
+			continue
+		}
+		seen[model.Name] = true
+		scores = append(scores, benchmarks.BenchmarkScore{
+			BenchmarkID:   "lmarena",
+			ModelName:     model.Name,
+			ModelProvider: model.Provider,
+			Variant:       variant,
+			Score:         score,
+			SourceType:    benchmarks.SourceThirdParty,
+			Confidence:    1,
+		})
+	}
+	return scores
+}
+
+// findArenaScoreColumn prefers the rating column over "95% CI" and "Votes",
+// which findScoreColumn's "%" keyword would otherwise pick up.
+func findArenaScoreColumn(header []string) int {
+	for _, kw := range []string{"score", "elo", "rating"} {
+		for i, h := range header {
+			if strings.Contains(strings.ToLower(h), kw) {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,162 @@
+package parsers
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/benchmarks"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/scraper"
+)
+
+// fixtureFetcher serves the testdata file mapped to each URL as the page
+// text, and fails for any other URL.
+type fixtureFetcher map[string]string
+
+func (f fixtureFetcher) Fetch(ctx context.Context, url string, opts *scraper.FetchOptions) (*scraper.FetchResult, error) {
+	name, ok := f[url]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		return nil, err
+	}
+	return &scraper.FetchResult{URL: url, CleanText: string(data)}, nil
+}
+
+func TestLMArenaParser(t *testing.T) {
+	overall, coding := lmArenaURLs["Overall"], lmArenaURLs["Coding"]
+	p := NewLMArenaParser(fixtureFetcher{overall: "lmarena_text.md", coding: "lmarena_text.md"}, benchmarks.DefaultModels)
+
+	// The other category pages fail; the two that load are kept
+	scores, err := p.Parse(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]benchmarks.BenchmarkScore{}
+	for _, s := range scores {
+		if s.BenchmarkID != "lmarena" || s.SourceType != benchmarks.SourceThirdParty {
+			t.Errorf("score %+v", s)
+		}
+		got[s.Variant+"/"+s.ModelName] = s
+	}
+	want := map[string]float64{
+		"Gemini 3.1 Pro": 1502,
+		"Opus 4.6":       1498, // the thinking entry outranks the dated one
+		"GPT-5.2":        1475,
+		"DeepSeek-V3":    1412,
+		"MiniMax-M2.5":   1398,
+	}
+	if len(got) != 2*len(want) {
+		t.Errorf("got %d scores, want %d: %v", len(got), 2*len(want), got)
+	}
+	for variant, url := range map[string]string{"Overall": overall, "Coding": coding} {
+		for model, score := range want {
+			s, ok := got[variant+"/"+model]
+			if !ok {
+				t.Errorf("%s: no score for %s", variant, model)
+				continue
+			}
+			if s.Score != score || s.SourceURL != url {
+				t.Errorf("%s: %s = %v from %s, want %v from %s", variant, model, s.Score, s.SourceURL, score, url)
+			}
+		}
+	}
+}
+
+func TestLMArenaParserErrors(t *testing.T) {
+	ctx := context.Background()
+	if _, err := NewLMArenaParser(fixtureFetcher{}, benchmarks.DefaultModels).Parse(ctx, nil); err == nil || !strings.Contains(err.Error(), "all pages failed") {
+		t.Errorf("all pages failing: %v", err)
+	}
+
+	for text, want := range map[string]string{
+		"": "empty content",
+		"Text Arena\n\nThe leaderboard is loading…": "no table found",
+	} {
+		p := NewLMArenaParser(textFetcher(text), benchmarks.DefaultModels)
+		if _, err := p.Parse(ctx, nil); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("pages without a leaderboard: %v, want %q", err, want)
+		}
+	}
+}
+
+// textFetcher returns the same text for every URL.
+type textFetcher string
+
+func (f textFetcher) Fetch(ctx context.Context, url string, opts *scraper.FetchOptions) (*scraper.FetchResult, error) {
+	return &scraper.FetchResult{URL: url, CleanText: string(f)}, nil
+}
+
+func TestParseArenaTable(t *testing.T) {
+	models := benchmarks.DefaultModels
+	tests := []struct {
+		name string
+		rows [][]string
+		want map[string]float64
+	}{
+		{
+			name: "rating column named Arena Elo",
+			rows: [][]string{
+				{"Rank", "Model", "95% CI", "Arena Elo", "Votes"},
+				{"1", "claude-opus-4-6", "+5/-5", "1480", "9000"},
+			},
+			want: map[string]float64{"Opus 4.6": 1480},
+		},
+		{
+			name: "scores that are not ratings",
+			rows: [][]string{
+				{"Model", "Score"},
+				{"claude-opus-4-6", "87.5"},
+				{"gpt-5.2", "N/A"},
+			},
+			want: map[string]float64{},
+		},
+		{
+			name: "title line before the header",
+			rows: [][]string{
+				{"Title: Text Arena", "LMArena"},
+				{"Model", "Score"},
+				{"gpt-5.2", "1470"},
+			},
+			want: map[string]float64{"GPT-5.2": 1470},
+		},
+		{
+			name: "no rating column",
+			rows: [][]string{
+				{"Model", "Votes"},
+				{"claude-opus-4-6", "9000"},
+			},
+			want: map[string]float64{},
+		},
+		{
+			name: "short rows",
+			rows: [][]string{
+				{"Model", "Org", "Score"},
+				{"claude-opus-4-6", "Anthropic"},
+				{"gpt-5.2", "OpenAI", "1470"},
+			},
+			want: map[string]float64{"GPT-5.2": 1470},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string]float64{}
+			for _, s := range parseArenaTable(tt.rows, "Overall", models) {
+				got[s.ModelName] = s.Score
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for model, score := range tt.want {
+				if got[model] != score {
+					t.Errorf("%s = %v, want %v", model, got[model], score)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package parsers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/benchmarks"
+)
+
+// OpenRouterParser reads model API prices from the OpenRouter model list.
+// It contributes no benchmark scores; the Scraper picks up its pricing
+// through the benchmarks.PricingParser interface.
+type OpenRouterParser struct {
+	models []benchmarks.ModelConfig
+	url    string
+}
+
+const openRouterModelsURL = "https://openrouter.ai/api/v1/models"
+
+// NewOpenRouterParser creates a pricing parser for OpenRouter.
+func NewOpenRouterParser(models []benchmarks.ModelConfig) *OpenRouterParser {
+	return &OpenRouterParser{models: models, url: openRouterModelsURL}
+}
+
+func (p *OpenRouterParser) Name() string { return "openrouter.ai" }
+
+func (p *OpenRouterParser) Parse(ctx context.Context, client *http.Client) ([]benchmarks.BenchmarkScore, error) {
+	return nil, nil
+}
+
+// openRouterModels is the /api/v1/models response. Prices are USD per token,
+// encoded as strings.
+type openRouterModels struct {
+	Data []struct {
+		ID      string `json:"id"`
+		Name    string `json:"name"`
+		Pricing struct {
+			Prompt     string `json:"prompt"`
+			Completion string `json:"completion"`
+		} `json:"pricing"`
+	} `json:"data"`
+}
+
+func (p *OpenRouterParser) ParsePricing(ctx context.Context, client *http.Client) ([]benchmarks.ModelPricing, error) {
+	body, err := benchmarks.FetchURL(ctx, client, p.url)
+	if err != nil {
+		return nil, err
+	}
+	var resp openRouterModels
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		return nil, fmt.Errorf("decode openrouter models: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var pricing []benchmarks.ModelPricing
+	for _, m := range resp.Data {
+		// "openai/gpt-4o:free", "…:extended" — variants priced differently
+		if strings.Contains(m.ID, ":") {
+			continue
+		}
+		input, err1 := strconv.ParseFloat(m.Pricing.Prompt, 64)
+		output, err2 := strconv.ParseFloat(m.Pricing.Completion, 64)
+		if err1 != nil || err2 != nil || input <= 0 || output <= 0 {
+			continue
+		}
+
+		// "Anthropic: Claude Opus 4.6" → "Claude Opus 4.6"
+		name := m.Name
+		if _, after, ok := strings.Cut(name, ": "); ok {
+			name = after
+		}
+		model, found := matchModel(p.Name(), name, p.models)
+		if !found {
+			continue
+		}
+		if seen[model.Name] {
+			continue
+		}
+		seen[model.Name] = true
+		pricing = append(pricing, benchmarks.ModelPricing{
+			ModelName:   model.Name,
+			InputPrice:  input * 1e6,
+			OutputPrice: output * 1e6,
+			SourceURL:   "https://openrouter.ai/" + m.ID,
+		})
+	}
+	return pricing, nil
+}
@@ -0,0 +1,69 @@
+package parsers
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/benchmarks"
+)
+
+func TestOpenRouterParser(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/openrouter_models.json")
+	}))
+	defer srv.Close()
+	p := NewOpenRouterParser(benchmarks.DefaultModels)
+	p.url = srv.URL
+
+	pricing, err := p.ParsePricing(context.Background(), srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Variants (":extended", ":free"), free and unpriced models are left
+	// out, and the first entry of a model wins
+	want := []benchmarks.ModelPricing{
+		{ModelName: "Opus 4.6", InputPrice: 5, OutputPrice: 25, SourceURL: "https://openrouter.ai/anthropic/claude-opus-4.6"},
+		{ModelName: "GPT-5.2", InputPrice: 1.75, OutputPrice: 14, SourceURL: "https://openrouter.ai/openai/gpt-5.2"},
+		{ModelName: "DeepSeek-V3", InputPrice: 0.3, OutputPrice: 0.88, SourceURL: "https://openrouter.ai/deepseek/deepseek-chat-v3"},
+	}
+	if len(pricing) != len(want) {
+		t.Fatalf("got %+v, want %+v", pricing, want)
+	}
+	for i, w := range want {
+		got := pricing[i]
+		if got.ModelName != w.ModelName || got.SourceURL != w.SourceURL ||
+			math.Abs(got.InputPrice-w.InputPrice) > 1e-9 || math.Abs(got.OutputPrice-w.OutputPrice) > 1e-9 {
+			t.Errorf("pricing[%d] = %+v, want %+v", i, got, w)
+		}
+	}
+
+	// Prices only; no benchmark scores
+	if scores, err := p.Parse(context.Background(), srv.Client()); err != nil || len(scores) != 0 {
+		t.Errorf("Parse = %v, %v", scores, err)
+	}
+}
+
+func TestOpenRouterParserErrors(t *testing.T) {
+	for name, handler := range map[string]http.HandlerFunc{
+		"error status": func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		},
+		"not JSON": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<html>Cloudflare</html>"))
+		},
+	} {
+		srv := httptest.NewServer(handler)
+		p := NewOpenRouterParser(benchmarks.DefaultModels)
+		p.url = srv.URL
+		if _, err := p.ParsePricing(context.Background(), srv.Client()); err == nil {
+			t.Errorf("%s: expected an error", name)
+		} else if name == "not JSON" && !strings.Contains(err.Error(), "decode openrouter models") {
+			t.Errorf("%s: %v", name, err)
+		}
+		srv.Close()
+	}
+}
@@ -60,7 +60,7 @@ func MatchModelName(rawName string, knownModels []benchmarks.ModelConfig) (*benc
 	rawLower := strings.ToLower(cleanModelName(rawName))
 
 	// Check alias table first
-	if aliasedName, ok := modelAliases[rawLower]; ok {
+	if aliasedName, ok := benchmarks.ModelAlias(rawLower); ok {
 		rawLower = strings.ToLower(aliasedName)
 	}
 
@@ -95,38 +95,24 @@ func MatchModelName(rawName string, knownModels []benchmarks.ModelConfig) (*benc
 	return nil, false
 }
 
-// modelAliases maps real-world model names (from LLM leaderboards)
-// to the tracked model names in our DefaultModels/FallbackModels.
-var modelAliases = map[string]string{
-	// Google models
-	"gemini 2.5 pro":   "Gemini 2.5 Pro",
-	"gemini 2.5 flash": "Gemini 2.0 Flash",
-	"gemini-2.5-pro":   "Gemini 2.5 Pro",
-	"gemini-2.5-flash": "Gemini 2.0 Flash",
-	// OpenAI models
-	"o3":                "o3-mini",
-	"o3-mini":           "o3-mini",
-	"gpt-5 mini":        "GPT-4o",
-	"gpt-4o":            "GPT-4o",
-	"chatgpt-4o latest": "GPT-4o",
-	"o1":                "o1",
-	// Anthropic models
-	"claude sonnet 4.5": "Claude 3.5 Sonnet",
-	"claude 3.7 sonnet": "Claude 3.5 Sonnet",
-	"claude opus 4.6":   "Claude 3 Opus",
-	"claude 3 opus":     "Claude 3 Opus",
-	"claude 3.5 sonnet": "Claude 3.5 Sonnet",
-	// DeepSeek
-	"deepseek-v3.2": "DeepSeek-V3",
-	"deepseek-v3":   "DeepSeek-V3",
-	"deepseek-r2":   "DeepSeek-R2",
-	// Alibaba
-	"qwen3-235b":  "Qwen3-235B",
-	"qwen-3-235b": "Qwen3-235B",
-	"qwen2.5-max": "Qwen2.5-Max",
-	// MiniMax
-	"minimax-m2.5": "MiniMax-M2.5",
-	"minimax-m1":   "MiniMax-M1",
+// matchModel is MatchModelName that records names it cannot match under
+// source, for the unmatched-models report.
+func matchModel(source, rawName string, knownModels []benchmarks.ModelConfig) (*benchmarks.ModelConfig, bool) {
+	model, found := MatchModelName(rawName, knownModels)
+	if !found {
+		benchmarks.NoteUnmatched(source, cleanModelName(rawName))
+	}
+	return model, found
+}
+
+// matchScoredModel is matchModel for a row that carries a score: unmatched
+// names are recorded with it, so new releases scoring well can be flagged.
+func matchScoredModel(source, rawName string, knownModels []benchmarks.ModelConfig, benchmarkID, variant string, score float64) (*benchmarks.ModelConfig, bool) {
+	model, found := MatchModelName(rawName, knownModels)
+	if !found {
+		benchmarks.NoteUnmatchedScore(source, cleanModelName(rawName), benchmarkID, variant, score)
+	}
+	return model, found
 }
 
 // cleanModelName removes common suffixes/prefixes from model names.
@@ -0,0 +1,158 @@
+package benchmarks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// ModelPricing holds a model's API price and output speed.
+type ModelPricing struct {
+	ModelName    string  `json:"model_name" yaml:"-"`
+	InputPrice   float64 `json:"input_price" yaml:"input"`   // USD per 1M input tokens
+	OutputPrice  float64 `json:"output_price" yaml:"output"` // USD per 1M output tokens
+	TokensPerSec float64 `json:"tokens_per_sec" yaml:"tokens_per_sec"`
+	SourceURL    string  `json:"source_url,omitempty" yaml:"source_url,omitempty"`
+}
+
+// BlendedPrice is the USD cost of 1M tokens at a 3:1 input:output ratio,
+// or zero when either price is unknown.
+func (p ModelPricing) BlendedPrice() float64 {
+	if p.InputPrice <= 0 || p.OutputPrice <= 0 {
+		return 0
+	}
+	return (3*p.InputPrice + p.OutputPrice) / 4
+}
+
+// merge overlays the non-zero fields of o on p.
+func (p ModelPricing) merge(o ModelPricing) ModelPricing {
+	if o.InputPrice > 0 {
+		p.InputPrice = o.InputPrice
+	}
+	if o.OutputPrice > 0 {
+		p.OutputPrice = o.OutputPrice
+	}
+	if o.TokensPerSec > 0 {
+		p.TokensPerSec = o.TokensPerSec
+	}
+	if o.SourceURL != "" {
+		p.SourceURL = o.SourceURL
+	}
+	return p
+}
+
+// PricingParser is implemented by parsers that also collect model pricing.
+// The Scraper stores what it returns alongside the parser's scores.
+type PricingParser interface {
+	ParsePricing(ctx context.Context, client *http.Client) ([]ModelPricing, error)
+}
+
+// SummaryRow is a computed per-model row (cost, composite score, ...) drawn
+// below or above the benchmark rows by the renderers.
+type SummaryRow struct {
+	Label string
+	Cells map[string]SummaryCell // modelName → cell
+	Best  string                 // model to highlight, if any
+}
+
+// SummaryCell is one formatted value in a SummaryRow.
+type SummaryCell struct {
+	Value float64 // for sorting
+	Text  string
+}
+
+// CostCategory styles the cost and speed section of rendered reports.
+var CostCategory = CategoryMeta{"cost", "Cost & Speed", "💰", "#66bb6a"}
+
+// AverageScore returns a model's mean normalized score (0–100) across every
+// benchmark it has a score for.
+func (r *BenchmarkReport) AverageScore(modelName string) (float64, bool) {
+	var sum float64
+	var n int
+	for _, b := range r.Benchmarks {
+		variants := b.Variants
+		if len(variants) == 0 {
+			variants = []string{""}
+		}
+		for _, v := range variants {
+			if value, ok := normalizedScore(r, b, v, modelName); ok {
+				sum += value
+				n++
+			}
+		}
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return sum / float64(n), true
+}
+
+// IntelligencePerDollar returns a model's average score per blended USD per
+// 1M tokens, and whether both are known.
+func (r *BenchmarkReport) IntelligencePerDollar(modelName string) (float64, bool) {
+	price := r.Pricing[modelName].BlendedPrice()
+	avg, ok := r.AverageScore(modelName)
+	if !ok || price <= 0 {
+		return 0, false
+	}
+	return avg / price, true
+}
+
+// CostRows returns the input price, output price, speed and "intelligence per
+// dollar" rows for the report's models, or nil when no model has pricing.
+func (r *BenchmarkReport) CostRows() []SummaryRow {
+	input := SummaryRow{Label: "Input $/1M", Cells: map[string]SummaryCell{}}
+	output := SummaryRow{Label: "Output $/1M", Cells: map[string]SummaryCell{}}
+	speed := SummaryRow{Label: "Tokens/sec", Cells: map[string]SummaryCell{}}
+	value := SummaryRow{Label: "Score per $", Cells: map[string]SummaryCell{}}
+
+	type ranked struct {
+		model string
+		ipd   float64
+	}
+	var ranking []ranked
+	for _, m := range r.Models {
+		p, ok := r.Pricing[m.Name]
+		if !ok {
+			continue
+		}
+		if p.InputPrice > 0 {
+			input.Cells[m.Name] = SummaryCell{p.InputPrice, fmt.Sprintf("$%.2f", p.InputPrice)}
+			if best, ok := input.Cells[input.Best]; !ok || p.InputPrice < best.Value {
+				input.Best = m.Name
+			}
+		}
+		if p.OutputPrice > 0 {
+			output.Cells[m.Name] = SummaryCell{p.OutputPrice, fmt.Sprintf("$%.2f", p.OutputPrice)}
+			if best, ok := output.Cells[output.Best]; !ok || p.OutputPrice < best.Value {
+				output.Best = m.Name
+			}
+		}
+		if p.TokensPerSec > 0 {
+			speed.Cells[m.Name] = SummaryCell{p.TokensPerSec, fmt.Sprintf("%.0f", p.TokensPerSec)}
+			if best, ok := speed.Cells[speed.Best]; !ok || p.TokensPerSec > best.Value {
+				speed.Best = m.Name
+			}
+		}
+		if ipd, ok := r.IntelligencePerDollar(m.Name); ok {
+			ranking = append(ranking, ranked{m.Name, ipd})
+		}
+	}
+
+	sort.SliceStable(ranking, func(i, j int) bool { return ranking[i].ipd > ranking[j].ipd })
+	for i, rk := range ranking {
+		value.Cells[rk.model] = SummaryCell{rk.ipd, fmt.Sprintf("%.1f (#%d)", rk.ipd, i+1)}
+	}
+	if len(ranking) > 0 {
+		value.Best = ranking[0].model
+	}
+
+	var rows []SummaryRow
+	for _, row := range []SummaryRow{input, output, speed, value} {
+		if len(row.Cells) > 0 {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
@@ -0,0 +1,68 @@
+package benchmarks
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestStorePricing(t *testing.T) {
+	db := openTestDB(t)
+
+	store, err := NewStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := store.BulkUpsert(ctx, []BenchmarkScore{
+		{BenchmarkID: "gpqa_diamond", ModelName: "Opus 4.6", ModelProvider: "anthropic", Score: 90},
+		{BenchmarkID: "gpqa_diamond", ModelName: "GPT-5.2", ModelProvider: "openai", Score: 80},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.UpsertPricing(ctx, []ModelPricing{
+		{ModelName: "Opus 4.6", InputPrice: 5, OutputPrice: 25, TokensPerSec: 55},
+		{ModelName: "GPT-5.2", InputPrice: 2, OutputPrice: 8},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// A later source without speed must not erase the stored speed
+	if err := store.UpsertPricing(ctx, []ModelPricing{{ModelName: "Opus 4.6", InputPrice: 6, OutputPrice: 30}}); err != nil {
+		t.Fatal(err)
+	}
+
+	models := []ModelConfig{
+		{Name: "Opus 4.6", Provider: "anthropic", DisplayOrder: 1},
+		{Name: "GPT-5.2", Provider: "openai", DisplayOrder: 2, Pricing: &ModelPricing{TokensPerSec: 120}},
+	}
+	report, err := store.GetScoresForReport(ctx, models, "2026-02-20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := report.Pricing["Opus 4.6"]; p.InputPrice != 6 || p.OutputPrice != 30 || p.TokensPerSec != 55 {
+		t.Errorf("Opus pricing = %+v", p)
+	}
+	if p := report.Pricing["GPT-5.2"]; p.InputPrice != 2 || p.TokensPerSec != 120 {
+		t.Errorf("GPT pricing = %+v, want config speed merged", p)
+	}
+
+	// Opus: 90 / 12 = 7.5 per $; GPT: 80 / 3.5 ≈ 22.9 per $
+	rows := report.CostRows()
+	if len(rows) != 4 {
+		t.Fatalf("got %d cost rows, want 4", len(rows))
+	}
+	value := rows[3]
+	if value.Best != "GPT-5.2" || value.Cells["Opus 4.6"].Text != "7.5 (#2)" {
+		t.Errorf("score per $ row = %+v", value)
+	}
+	if rows[2].Best != "GPT-5.2" || rows[0].Best != "GPT-5.2" {
+		t.Errorf("best speed/input = %q/%q, want GPT-5.2", rows[2].Best, rows[0].Best)
+	}
+
+	if html := NewHTMLRenderer().RenderHTML(report); !strings.Contains(html, "Cost & Speed") || !strings.Contains(html, "$30.00") {
+		t.Error("HTML missing cost section")
+	}
+	if err := NewImageRenderer().RenderPNG(report, t.TempDir()+"/cost.png"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -24,11 +24,20 @@ func (r *HTMLRenderer) RenderHTML(report *BenchmarkReport) string {
 	sb.WriteString(`<td style="padding:10px 12px;color:#6666aa;font-weight:700;border-bottom:2px solid #1a1a3e;">Benchmark</td>`)
 	for _, m := range report.Models {
 		provColor := ProviderColor(m.Provider)
-		sb.WriteString(fmt.Sprintf(`<td style="padding:10px 8px;text-align:center;color:#aaaacc;font-weight:600;border-bottom:2px solid #1a1a3e;"><span style="display:inline-block;width:8px;height:8px;border-radius:50%%;background:%s;margin-right:4px;"></span>%s</td>`,
-			provColor, html.EscapeString(m.Name)))
+		baseline := ""
+		if prev := report.Baselines[m.Name]; prev != "" {
+			baseline = fmt.Sprintf(`<br><span style="color:#555570;font-size:10px;font-weight:400;">vs %s</span>`, html.EscapeString(prev))
+		}
+		sb.WriteString(fmt.Sprintf(`<td style="padding:10px 8px;text-align:center;color:#aaaacc;font-weight:600;border-bottom:2px solid #1a1a3e;"><span style="display:inline-block;width:8px;height:8px;border-radius:50%%;background:%s;margin-right:4px;"></span>%s%s</td>`,
+			provColor, html.EscapeString(m.Name), baseline))
 	}
 	sb.WriteString(`</tr>`)
 
+	// Composite ranking
+	if row, ok := report.CompositeRow(CategoryWeights); ok {
+		r.writeSummaryRow(&sb, report, row, CompositeCategory.Color)
+	}
+
 	// Rows by category
 	for _, cat := range Categories {
 		benchmarks := benchmarksForCategory(cat.ID)
@@ -51,6 +60,18 @@ func (r *HTMLRenderer) RenderHTML(report *BenchmarkReport) string {
 		}
 	}
 
+	// Cost & speed
+	if rows := report.CostRows(); len(rows) > 0 {
+		cat := CostCategory
+		sb.WriteString(fmt.Sprintf(`<tr><td colspan="%d" style="padding:10px 12px;background:#0d0d1f;color:%s;font-weight:700;font-size:14px;border-left:4px solid %s;">%s %s</td></tr>`,
+			len(report.Models)+1, cat.Color, cat.Color, cat.Emoji, cat.Label))
+		for _, row := range rows {
+			r.writeSummaryRow(&sb, report, row, cat.Color)
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf(`<tr><td colspan="%d" style="padding:8px 12px;color:#555570;font-size:11px;">%s</td></tr>`,
+		len(report.Models)+1, html.EscapeString(ProvenanceLegend)))
 	sb.WriteString(`</table>`)
 	return sb.String()
 }
@@ -74,17 +95,43 @@ func (r *HTMLRenderer) writeScoreRow(sb *strings.Builder, report *BenchmarkRepor
 		if !exists {
 			sb.WriteString(`<td style="padding:8px;text-align:center;color:#404050;border-bottom:1px solid rgba(255,255,255,0.04);">—</td>`)
 		} else {
-			scoreStr := htmlFormatScore(score, bench.Unit)
+			scoreStr := htmlFormatScore(score, bench.Unit) + htmlProvenance(report, bench, variant, m.Name) +
+				htmlTrend(report, bench, variant, m.Name) + htmlGenDelta(report, bench, variant, m.Name)
 			if isTop {
 				sb.WriteString(fmt.Sprintf(`<td style="padding:8px;text-align:center;border-bottom:1px solid rgba(255,255,255,0.04);"><span style="background:rgba(255,45,85,0.15);color:#ff4757;font-weight:700;padding:2px 8px;border-radius:4px;">%s</span></td>`, scoreStr))
 			} else {
-				sb.WriteString(fmt.Sprintf(`<td style="padding:8px;text-align:center;color:#e0e0e0;border-bottom:1px solid rgba(255,255,255,0.04);">%s</td>`, scoreStr))
+				// Self-reported and extracted numbers are dimmed next to independent ones
+				textColor := "#e0e0e0"
+				if report.ProvenanceMark(bench.ID, variant, m.Name) != "" {
+					textColor = "#9a9ab0"
+				}
+				sb.WriteString(fmt.Sprintf(`<td style="padding:8px;text-align:center;color:%s;border-bottom:1px solid rgba(255,255,255,0.04);">%s</td>`, textColor, scoreStr))
 			}
 		}
 	}
 	sb.WriteString(`</tr>`)
 }
 
+// writeSummaryRow writes a computed row, highlighting its best cell in accent.
+func (r *HTMLRenderer) writeSummaryRow(sb *strings.Builder, report *BenchmarkReport, row SummaryRow, accent string) {
+	sb.WriteString(fmt.Sprintf(`<tr><td style="padding:8px 12px;color:#c0c0d0;border-bottom:1px solid rgba(255,255,255,0.04);">%s</td>`,
+		html.EscapeString(row.Label)))
+	for _, m := range report.Models {
+		cell, ok := row.Cells[m.Name]
+		switch {
+		case !ok:
+			sb.WriteString(`<td style="padding:8px;text-align:center;color:#404050;border-bottom:1px solid rgba(255,255,255,0.04);">—</td>`)
+		case m.Name == row.Best:
+			sb.WriteString(fmt.Sprintf(`<td style="padding:8px;text-align:center;color:%s;font-weight:700;border-bottom:1px solid rgba(255,255,255,0.04);">%s</td>`,
+				accent, html.EscapeString(cell.Text)))
+		default:
+			sb.WriteString(fmt.Sprintf(`<td style="padding:8px;text-align:center;color:#e0e0e0;border-bottom:1px solid rgba(255,255,255,0.04);">%s</td>`,
+				html.EscapeString(cell.Text)))
+		}
+	}
+	sb.WriteString(`</tr>`)
+}
+
 func benchmarksForCategory(catID string) []BenchmarkDef {
 	var result []BenchmarkDef
 	for _, b := range AllBenchmarks {
@@ -104,3 +151,51 @@ func htmlFormatScore(score float64, unit string) string {
 	}
 	return fmt.Sprintf("%.1f%%", score)
 }
+
+// htmlProvenance renders the provenance marker with a tooltip naming the
+// source, or nothing for independent and unknown sources.
+func htmlProvenance(report *BenchmarkReport, bench BenchmarkDef, variant, modelName string) string {
+	mark := report.ProvenanceMark(bench.ID, variant, modelName)
+	if mark == "" {
+		return ""
+	}
+	p := report.GetProvenance(bench.ID, variant, modelName)
+	title := "Vendor-reported"
+	if p.SourceType == SourceLLMExtracted {
+		title = fmt.Sprintf("LLM-extracted (confidence %.0f%%)", p.Confidence*100)
+	}
+	if p.Notes != "" {
+		title += " · " + p.Notes
+	}
+	return fmt.Sprintf(`<sup style="color:#8888aa;font-size:10px;" title="%s">%s</sup>`, html.EscapeString(title), mark)
+}
+
+// ProvenanceLegend explains the provenance markers used in rendered reports.
+const ProvenanceLegend = "* vendor-reported · ~ LLM-extracted · unmarked = independent leaderboard"
+
+// htmlTrend renders the change since the previous scrape plus a sparkline of
+// recent scores, or nothing when there is no history.
+func htmlTrend(report *BenchmarkReport, bench BenchmarkDef, variant, modelName string) string {
+	delta, ok := report.Delta(bench.ID, variant, modelName)
+	if !ok {
+		return ""
+	}
+	spark := Sparkline(report.Trend(bench.ID, variant, modelName))
+	if delta == 0 {
+		return fmt.Sprintf(`<br><span style="color:#555570;font-size:10px;">%s</span>`, spark)
+	}
+	color, arrow := "#2ecc71", "▲"
+	if delta < 0 {
+		color, arrow = "#e74c3c", "▼"
+	}
+	return fmt.Sprintf(`<br><span style="color:%s;font-size:10px;">%s%s</span> <span style="color:#555570;font-size:10px;">%s</span>`,
+		color, arrow, formatDelta(math.Abs(delta), bench.Unit), spark)
+}
+
+// formatDelta formats a score change in the benchmark's unit.
+func formatDelta(delta float64, unit string) string {
+	if unit == "Elo" {
+		return fmt.Sprintf("%d", int(math.Round(delta)))
+	}
+	return fmt.Sprintf("%.1f", delta)
+}
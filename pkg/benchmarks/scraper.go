@@ -2,8 +2,10 @@ package benchmarks
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"strings"
 	"time"
@@ -14,6 +16,8 @@ type Scraper struct {
 	store   *Store
 	client  *http.Client
 	parsers []Parser
+	// ParserTimeout bounds each parser's run; zero means no limit.
+	ParserTimeout time.Duration
 }
 
 // Parser extracts benchmark scores from a data source.
@@ -25,36 +29,208 @@ type Parser interface {
 // NewScraper creates a scraper with the given store and parsers.
 func NewScraper(store *Store, parsers ...Parser) *Scraper {
 	return &Scraper{
-		store:   store,
-		client:  &http.Client{Timeout: 30 * time.Second},
-		parsers: parsers,
+		store:         store,
+		client:        &http.Client{Timeout: 30 * time.Second},
+		parsers:       parsers,
+		ParserTimeout: 5 * time.Minute,
 	}
 }
 
+// ParserStats is the outcome of one parser in a scrape.
+type ParserStats struct {
+	Parser      string
+	Scores      int // scores stored
+	Added       int // stored scores that were new
+	Updated     int // stored scores that replaced a different value
+	Quarantined int // scores held back by ValidateScore
+	Pricing     int // pricing entries stored
+	Duration    time.Duration
+	Err         error // fetch, parse, store, timeout or panic error
+}
+
+// ScrapeRecord is one parser's entry in the scrape history (see
+// Store.RecordScrape).
+type ScrapeRecord struct {
+	Parser      string
+	StartedAt   time.Time
+	Duration    time.Duration
+	Added       int
+	Updated     int
+	Quarantined int
+	Error       string // empty on success
+}
+
+// ScrapeSummary aggregates a scrape's per-parser stats, in parser order.
+type ScrapeSummary struct {
+	Parsers  []ParserStats
+	Duration time.Duration
+	// NewCandidates are unmatched models first flagged in this scrape.
+	NewCandidates []ModelCandidate
+}
+
+// Total returns the number of scores stored across parsers.
+func (s ScrapeSummary) Total() int {
+	total := 0
+	for _, p := range s.Parsers {
+		total += p.Scores
+	}
+	return total
+}
+
+// Err joins the parsers' errors, or returns nil if all succeeded.
+func (s ScrapeSummary) Err() error {
+	var msgs []string
+	for _, p := range s.Parsers {
+		if p.Err != nil {
+			msgs = append(msgs, fmt.Sprintf("%s: %v", p.Parser, p.Err))
+		}
+	}
+	if len(msgs) > 0 {
+		return fmt.Errorf("scrape errors (%d scores saved): %s", s.Total(), strings.Join(msgs, "; "))
+	}
+	return nil
+}
+
+// parserResult is what a parser returned, before it is stored.
+type parserResult struct {
+	scores     []BenchmarkScore
+	pricing    []ModelPricing
+	pricingErr error
+	unmatched  []string
+	noted      map[string][]CandidateScore
+	err        error
+	duration   time.Duration
+}
+
 // ScrapeAll runs all parsers and stores the results. Returns total new/updated scores.
 func (s *Scraper) ScrapeAll(ctx context.Context) (int, error) {
-	total := 0
-	var errors []string
+	summary := s.Scrape(ctx)
+	return summary.Total(), summary.Err()
+}
 
-	for _, p := range s.parsers {
-		scores, err := p.Parse(ctx, s.client)
-		if err != nil {
-			errors = append(errors, fmt.Sprintf("%s: %v", p.Name(), err))
-			continue
+// Scrape runs all parsers concurrently, each under ParserTimeout and isolated
+// from the others' panics, then stores their results one parser at a time.
+func (s *Scraper) Scrape(ctx context.Context) ScrapeSummary {
+	start := time.Now()
+	results := make([]chan parserResult, len(s.parsers))
+	for i, p := range s.parsers {
+		results[i] = make(chan parserResult, 1)
+		go func(p Parser, out chan<- parserResult) {
+			out <- s.runParser(ctx, p)
+		}(p, results[i])
+	}
+
+	// Scores are validated against what is stored, including what earlier
+	// parsers in this scrape stored
+	stored := make(map[string]float64)
+	current, err := s.store.GetAllScores(ctx)
+	if err != nil {
+		log.Printf("[benchmark-scraper] Loading stored scores for validation: %v", err)
+	}
+	for _, sc := range current {
+		stored[ScoreKey(sc.BenchmarkID, sc.Variant)+"|"+sc.ModelName] = sc.Score
+	}
+
+	summary := ScrapeSummary{Parsers: make([]ParserStats, len(s.parsers))}
+	for i, p := range s.parsers {
+		res := <-results[i]
+		stats := ParserStats{Parser: p.Name(), Duration: res.duration, Err: res.err}
+
+		if res.pricingErr != nil {
+			stats.Err = errors.Join(stats.Err, fmt.Errorf("pricing: %w", res.pricingErr))
+		} else if len(res.pricing) > 0 {
+			if err := s.store.UpsertPricing(ctx, res.pricing); err != nil {
+				stats.Err = errors.Join(stats.Err, fmt.Errorf("pricing store: %w", err))
+			} else {
+				stats.Pricing = len(res.pricing)
+			}
 		}
-		if len(scores) > 0 {
-			if err := s.store.BulkUpsert(ctx, scores); err != nil {
-				errors = append(errors, fmt.Sprintf("%s store: %v", p.Name(), err))
-				continue
+
+		if res.err == nil {
+			// A failed run may have seen only part of the leaderboard, so only a
+			// successful one replaces the stored unmatched names
+			if err := s.store.ReplaceUnmatched(ctx, p.Name(), res.unmatched); err != nil {
+				stats.Err = errors.Join(stats.Err, fmt.Errorf("unmatched store: %w", err))
+			}
+			if candidates := findCandidates(p.Name(), res.noted, stored); len(candidates) > 0 {
+				added, err := s.store.UpsertCandidates(ctx, candidates)
+				if err != nil {
+					stats.Err = errors.Join(stats.Err, fmt.Errorf("candidate store: %w", err))
+				}
+				summary.NewCandidates = append(summary.NewCandidates, added...)
+			}
+			valid, quarantined := validateScores(res.scores, stored)
+			if len(quarantined) > 0 {
+				if err := s.store.QuarantineScores(ctx, quarantined); err != nil {
+					stats.Err = errors.Join(stats.Err, fmt.Errorf("quarantine store: %w", err))
+				} else {
+					stats.Quarantined = len(quarantined)
+				}
 			}
-			total += len(scores)
+			if len(valid) > 0 {
+				if err := s.store.BulkUpsert(ctx, valid); err != nil {
+					stats.Err = errors.Join(stats.Err, fmt.Errorf("store: %w", err))
+				} else {
+					stats.Scores = len(valid)
+					for _, sc := range valid {
+						key := ScoreKey(sc.BenchmarkID, sc.Variant) + "|" + sc.ModelName
+						if prev, ok := stored[key]; !ok {
+							stats.Added++
+						} else if prev != sc.Score {
+							stats.Updated++
+						}
+						stored[key] = sc.Score
+					}
+				}
+			}
+		}
+		summary.Parsers[i] = stats
+		if err := s.store.RecordScrape(ctx, start, stats); err != nil {
+			log.Printf("[benchmark-scraper] Recording %s scrape: %v", p.Name(), err)
 		}
 	}
+	summary.Duration = time.Since(start)
+	return summary
+}
+
+// runParser fetches one parser's scores and pricing under ParserTimeout. A
+// parser that ignores its context is abandoned when the timeout expires.
+func (s *Scraper) runParser(ctx context.Context, p Parser) parserResult {
+	start := time.Now()
+	pctx := ctx
+	if s.ParserTimeout > 0 {
+		var cancel context.CancelFunc
+		pctx, cancel = context.WithTimeout(ctx, s.ParserTimeout)
+		defer cancel()
+	}
 
-	if len(errors) > 0 {
-		return total, fmt.Errorf("scrape errors (%d scores saved): %s", total, strings.Join(errors, "; "))
+	done := make(chan parserResult, 1)
+	go func() {
+		var res parserResult
+		defer func() {
+			if r := recover(); r != nil {
+				res.err = fmt.Errorf("panic: %v", r)
+			}
+			res.unmatched, res.noted = takeUnmatched(p.Name())
+			done <- res
+		}()
+		if pp, ok := p.(PricingParser); ok {
+			res.pricing, res.pricingErr = pp.ParsePricing(pctx, s.client)
+		}
+		res.scores, res.err = p.Parse(pctx, s.client)
+	}()
+
+	var res parserResult
+	select {
+	case res = <-done:
+	case <-pctx.Done():
+		res = parserResult{err: fmt.Errorf("timed out after %v: %w", s.ParserTimeout, pctx.Err())}
+		if ctx.Err() != nil {
+			res.err = ctx.Err()
+		}
 	}
-	return total, nil
+	res.duration = time.Since(start)
+	return res
 }
 
 // FetchURL is a helper that fetches a URL and returns the body.
@@ -228,6 +404,8 @@ func SeedFromScreenshot() []BenchmarkScore {
 			Variant:       d.variant,
 			Score:         d.score,
 			SourceURL:     "https://deepmind.google/models/evals-methodology/gemini-3-1-pro",
+			SourceType:    SourceVendor,
+			Confidence:    1,
 		}
 	}
 	return scores
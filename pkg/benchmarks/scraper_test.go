@@ -0,0 +1,122 @@
+package benchmarks
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// funcParser adapts a function to Parser for scraper tests.
+type funcParser struct {
+	name  string
+	parse func(ctx context.Context) ([]BenchmarkScore, error)
+}
+
+func (p funcParser) Name() string { return p.name }
+
+func (p funcParser) Parse(ctx context.Context, client *http.Client) ([]BenchmarkScore, error) {
+	return p.parse(ctx)
+}
+
+func TestScrapeIsolation(t *testing.T) {
+	db := openTestDB(t)
+	store, err := NewStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewScraper(store,
+		funcParser{"panics", func(context.Context) ([]BenchmarkScore, error) { panic("boom") }},
+		funcParser{"stuck", func(context.Context) ([]BenchmarkScore, error) {
+			time.Sleep(time.Second) // ignores its context
+			return nil, nil
+		}},
+		NewManualParser([]BenchmarkScore{{BenchmarkID: "gpqa_diamond", ModelName: "Opus 4.6", ModelProvider: "anthropic", Score: 91.3}}),
+	)
+	s.ParserTimeout = 50 * time.Millisecond
+
+	summary := s.Scrape(context.Background())
+	if summary.Duration > 500*time.Millisecond {
+		t.Errorf("scrape took %v; the stuck parser should be abandoned", summary.Duration)
+	}
+	if len(summary.Parsers) != 3 {
+		t.Fatalf("got %d parser stats, want 3", len(summary.Parsers))
+	}
+	if err := summary.Parsers[0].Err; err == nil || !strings.Contains(err.Error(), "panic: boom") {
+		t.Errorf("panicking parser error = %v", err)
+	}
+	if err := summary.Parsers[1].Err; err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("stuck parser error = %v", err)
+	}
+	if ps := summary.Parsers[2]; ps.Err != nil || ps.Scores != 1 {
+		t.Errorf("manual parser stats = %+v", ps)
+	}
+	if summary.Total() != 1 || summary.Err() == nil {
+		t.Errorf("total = %d, err = %v", summary.Total(), summary.Err())
+	}
+	if n, _ := store.ScoreCount(context.Background()); n != 1 {
+		t.Errorf("stored %d scores, want 1", n)
+	}
+	if ps := summary.Parsers[2]; ps.Added != 1 || ps.Updated != 0 {
+		t.Errorf("manual parser added %d, updated %d", ps.Added, ps.Updated)
+	}
+
+	history, err := store.GetScrapeHistory(context.Background(), 10)
+	if err != nil || len(history) != 3 {
+		t.Fatalf("history = %+v, %v", history, err)
+	}
+	if history[0].Added != 1 || history[0].Error != "" || !strings.Contains(history[2].Error, "panic: boom") {
+		t.Errorf("history = %+v", history)
+	}
+	lastOK, err := store.LastSuccessfulScrapes(context.Background())
+	if err != nil || len(lastOK) != 1 {
+		t.Errorf("last successful scrapes = %v, %v", lastOK, err)
+	}
+}
+
+func TestModelCandidates(t *testing.T) {
+	db := openTestDB(t)
+	store, err := NewStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leaderboard := funcParser{"leaderboard", func(context.Context) ([]BenchmarkScore, error) {
+		NoteUnmatchedScore("leaderboard", "GPT-9", "gpqa_diamond", "", 93)
+		NoteUnmatchedScore("leaderboard", "GPT-9", "mmmlu", "", 92)
+		NoteUnmatchedScore("leaderboard", "Tiny-1B", "gpqa_diamond", "", 40)
+		NoteUnmatchedScore("leaderboard", "Tiny-1B", "mmmlu", "", 50)
+		NoteUnmatchedScore("leaderboard", "One-Trick", "gpqa_diamond", "", 95)
+		NoteUnmatchedScore("leaderboard", "One-Trick", "gpqa_diamond", "", 95)
+		return nil, nil
+	}}
+	s := NewScraper(store,
+		NewManualParser([]BenchmarkScore{
+			{BenchmarkID: "gpqa_diamond", ModelName: "Opus 4.6", ModelProvider: "anthropic", Score: 91.3},
+			{BenchmarkID: "gpqa_diamond", ModelName: "Gemini 3.1 Pro", ModelProvider: "google", Score: 90},
+			{BenchmarkID: "mmmlu", ModelName: "Opus 4.6", ModelProvider: "anthropic", Score: 91.1},
+			{BenchmarkID: "mmmlu", ModelName: "Gemini 3.1 Pro", ModelProvider: "google", Score: 89},
+		}),
+		leaderboard,
+	)
+
+	ctx := context.Background()
+	summary := s.Scrape(ctx)
+	if len(summary.NewCandidates) != 1 || summary.NewCandidates[0].RawName != "GPT-9" {
+		t.Fatalf("new candidates = %+v", summary.NewCandidates)
+	}
+	if c := summary.NewCandidates[0]; c.HighScores != 2 || !strings.Contains(c.Summary, "GPQA Diamond 93") {
+		t.Errorf("candidate = %+v", c)
+	}
+
+	// Already flagged: no second alert
+	if summary := s.Scrape(ctx); len(summary.NewCandidates) != 0 {
+		t.Errorf("re-flagged candidates %+v", summary.NewCandidates)
+	}
+	candidates, err := store.GetCandidates(ctx)
+	if err != nil || len(candidates) != 1 || candidates[0].Source != "leaderboard" {
+		t.Fatalf("candidates = %+v, %v", candidates, err)
+	}
+}
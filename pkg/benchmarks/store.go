@@ -4,97 +4,68 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
 )
 
-// Store manages benchmark data in SQLite.
+// Store manages benchmark data in SQLite or PostgreSQL.
 type Store struct {
-	db *sql.DB
+	db *storage.DB
 }
 
 // NewStore creates a benchmark store using the given database connection.
-func NewStore(db *sql.DB) (*Store, error) {
-	s := &Store{db: db}
-	if err := s.initTables(); err != nil {
-		return nil, fmt.Errorf("init benchmark tables: %w", err)
-	}
-	return s, nil
-}
-
-func (s *Store) initTables() error {
-	queries := []string{
-		`CREATE TABLE IF NOT EXISTS benchmark_scores (
-			id              INTEGER PRIMARY KEY,
-			benchmark_id    TEXT NOT NULL,
-			model_name      TEXT NOT NULL,
-			model_provider  TEXT NOT NULL,
-			variant         TEXT DEFAULT '',
-			score           REAL NOT NULL,
-			source_url      TEXT DEFAULT '',
-			scraped_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
-			UNIQUE(benchmark_id, model_name, variant)
-		)`,
-		`CREATE TABLE IF NOT EXISTS benchmark_models (
-			name          TEXT PRIMARY KEY,
-			provider      TEXT NOT NULL,
-			thinking      TEXT DEFAULT '',
-			gen           TEXT DEFAULT 'latest',
-			display_order INTEGER DEFAULT 0
-		)`,
-	}
-	for _, q := range queries {
-		if _, err := s.db.Exec(q); err != nil {
-			return err
-		}
+// The benchmark tables are created by the storage migrations, so db must be
+// migrated (storage.DB.Migrate) first.
+func NewStore(db *storage.DB) (*Store, error) {
+	// Fail here rather than on first use if the database is not migrated
+	if _, err := db.Exec(`SELECT source_type FROM benchmark_scores WHERE 1 = 0`); err != nil {
+		return nil, fmt.Errorf("benchmark tables missing, run migrate: %w", err)
 	}
-	return nil
+	return &Store{db: db}, nil
 }
 
-// UpsertScore inserts or updates a benchmark score.
+// UpsertScore inserts or updates a benchmark score and records it in history.
 func (s *Store) UpsertScore(ctx context.Context, score BenchmarkScore) error {
-	_, err := s.db.ExecContext(ctx, `
-		INSERT INTO benchmark_scores (benchmark_id, model_name, model_provider, variant, score, source_url, scraped_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(benchmark_id, model_name, variant) DO UPDATE SET
-			score = excluded.score,
-			source_url = excluded.source_url,
-			scraped_at = excluded.scraped_at,
-			model_provider = excluded.model_provider
-	`, score.BenchmarkID, score.ModelName, score.ModelProvider,
-		score.Variant, score.Score, score.SourceURL, time.Now())
-	return err
+	return s.BulkUpsert(ctx, []BenchmarkScore{score})
 }
 
-// BulkUpsert inserts multiple scores efficiently.
+// BulkUpsert inserts multiple scores efficiently. Each score is also recorded
+// in history under today's date; a later scrape on the same day replaces it.
 func (s *Store) BulkUpsert(ctx context.Context, scores []BenchmarkScore) error {
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
-	stmt, err := tx.PrepareContext(ctx, `
-		INSERT INTO benchmark_scores (benchmark_id, model_name, model_provider, variant, score, source_url, scraped_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(benchmark_id, model_name, variant) DO UPDATE SET
-			score = excluded.score,
-			source_url = excluded.source_url,
-			scraped_at = excluded.scraped_at,
-			model_provider = excluded.model_provider
-	`)
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
-
 	now := time.Now()
-	for _, sc := range scores {
-		if _, err := stmt.ExecContext(ctx, sc.BenchmarkID, sc.ModelName, sc.ModelProvider,
-			sc.Variant, sc.Score, sc.SourceURL, now); err != nil {
-			return fmt.Errorf("upsert %s/%s: %w", sc.BenchmarkID, sc.ModelName, err)
+	date := now.Format("2006-01-02")
+	return s.db.Transaction(ctx, func(tx *storage.Tx) error {
+		for _, sc := range scores {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO benchmark_scores (benchmark_id, model_name, model_provider, variant, score, source_url, scraped_at,
+					source_type, confidence, notes)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+				ON CONFLICT(benchmark_id, model_name, variant) DO UPDATE SET
+					score = excluded.score,
+					source_url = excluded.source_url,
+					scraped_at = excluded.scraped_at,
+					model_provider = excluded.model_provider,
+					source_type = excluded.source_type,
+					confidence = excluded.confidence,
+					notes = excluded.notes`,
+				sc.BenchmarkID, sc.ModelName, sc.ModelProvider,
+				sc.Variant, sc.Score, sc.SourceURL, now, sc.SourceType, sc.Confidence, sc.Notes); err != nil {
+				return fmt.Errorf("upsert %s/%s: %w", sc.BenchmarkID, sc.ModelName, err)
+			}
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO benchmark_score_history (benchmark_id, model_name, variant, scrape_date, score, source_url)
+				VALUES (?, ?, ?, ?, ?, ?)
+				ON CONFLICT(benchmark_id, model_name, variant, scrape_date) DO UPDATE SET
+					score = excluded.score,
+					source_url = excluded.source_url`,
+				sc.BenchmarkID, sc.ModelName, sc.Variant, date, sc.Score, sc.SourceURL); err != nil {
+				return fmt.Errorf("record history %s/%s: %w", sc.BenchmarkID, sc.ModelName, err)
+			}
 		}
-	}
-	return tx.Commit()
+		return nil
+	})
 }
 
 // GetScoresForReport loads all scores for the given models into a BenchmarkReport.
@@ -113,7 +84,7 @@ func (s *Store) GetScoresForReport(ctx context.Context, models []ModelConfig, da
 	}
 
 	query := fmt.Sprintf(`
-		SELECT benchmark_id, model_name, variant, score
+		SELECT benchmark_id, model_name, variant, score, source_url, source_type, confidence, notes, scraped_at
 		FROM benchmark_scores
 		WHERE model_name IN (%s)
 		ORDER BY benchmark_id, variant, model_name
@@ -128,18 +99,162 @@ func (s *Store) GetScoresForReport(ctx context.Context, models []ModelConfig, da
 	for rows.Next() {
 		var benchID, modelName, variant string
 		var score float64
-		if err := rows.Scan(&benchID, &modelName, &variant, &score); err != nil {
+		var p Provenance
+		if err := rows.Scan(&benchID, &modelName, &variant, &score, &p.SourceURL, &p.SourceType, &p.Confidence, &p.Notes, &p.ScrapedAt); err != nil {
 			return nil, err
 		}
 		report.SetScore(benchID, variant, modelName, score)
+		report.SetProvenance(benchID, variant, modelName, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadHistory(ctx, report, modelNames); err != nil {
+		return nil, err
+	}
+	if err := s.loadPricing(ctx, report); err != nil {
+		return nil, err
+	}
+	// Configured pricing wins over scraped pricing
+	for _, m := range models {
+		if m.Pricing != nil {
+			p := report.Pricing[m.Name].merge(*m.Pricing)
+			p.ModelName = m.Name
+			report.Pricing[m.Name] = p
+		}
+	}
+	return report, nil
+}
+
+// UpsertPricing stores scraped model pricing. Zero fields keep the stored value,
+// so a source that only knows prices does not erase a known speed.
+func (s *Store) UpsertPricing(ctx context.Context, pricing []ModelPricing) error {
+	now := time.Now()
+	return s.db.Transaction(ctx, func(tx *storage.Tx) error {
+		for _, p := range pricing {
+			// The stored values are qualified with the table name, which
+			// PostgreSQL requires next to excluded
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO benchmark_model_pricing (model_name, input_price, output_price, tokens_per_sec, source_url, updated_at)
+				VALUES (?, ?, ?, ?, ?, ?)
+				ON CONFLICT(model_name) DO UPDATE SET
+					input_price = CASE WHEN excluded.input_price > 0 THEN excluded.input_price ELSE benchmark_model_pricing.input_price END,
+					output_price = CASE WHEN excluded.output_price > 0 THEN excluded.output_price ELSE benchmark_model_pricing.output_price END,
+					tokens_per_sec = CASE WHEN excluded.tokens_per_sec > 0 THEN excluded.tokens_per_sec ELSE benchmark_model_pricing.tokens_per_sec END,
+					source_url = CASE WHEN excluded.source_url != '' THEN excluded.source_url ELSE benchmark_model_pricing.source_url END,
+					updated_at = excluded.updated_at`,
+				p.ModelName, p.InputPrice, p.OutputPrice, p.TokensPerSec, p.SourceURL, now); err != nil {
+				return fmt.Errorf("upsert pricing %s: %w", p.ModelName, err)
+			}
+		}
+		return nil
+	})
+}
+
+// loadPricing fills report.Pricing with every stored model's pricing, so
+// fallback models added later by FilterEmptyModels have it too.
+func (s *Store) loadPricing(ctx context.Context, report *BenchmarkReport) error {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT model_name, input_price, output_price, tokens_per_sec, source_url
+		FROM benchmark_model_pricing
+	`)
+	if err != nil {
+		return fmt.Errorf("load pricing: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p ModelPricing
+		if err := rows.Scan(&p.ModelName, &p.InputPrice, &p.OutputPrice, &p.TokensPerSec, &p.SourceURL); err != nil {
+			return err
+		}
+		report.Pricing[p.ModelName] = p
+	}
+	return rows.Err()
+}
+
+// loadHistory fills report.History with the last HistoryPoints daily scores
+// per benchmark and model, oldest first.
+func (s *Store) loadHistory(ctx context.Context, report *BenchmarkReport, modelNames []interface{}) error {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(modelNames)), ",")
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT benchmark_id, model_name, variant, score
+		FROM benchmark_score_history
+		WHERE model_name IN (%s)
+		ORDER BY benchmark_id, variant, model_name, scrape_date
+	`, placeholders), modelNames...)
+	if err != nil {
+		return fmt.Errorf("load history: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var benchID, modelName, variant string
+		var score float64
+		if err := rows.Scan(&benchID, &modelName, &variant, &score); err != nil {
+			return err
+		}
+		report.AddHistory(benchID, variant, modelName, score)
+	}
+	return rows.Err()
+}
+
+// Trend returns the daily score history of a benchmark variant for the
+// named models, or for every model with history when none are named. Names
+// match regardless of case.
+func (s *Store) Trend(ctx context.Context, benchmarkID, variant string, modelNames ...string) (*TrendReport, error) {
+	report := &TrendReport{BenchmarkID: benchmarkID, Variant: variant}
+	if b := FindBenchmark(benchmarkID); b != nil {
+		report.Benchmark, report.Unit = b.Name, b.Unit
+	}
+
+	query := `
+		SELECT h.model_name, COALESCE(s.model_provider, ''), h.scrape_date, h.score
+		FROM benchmark_score_history h
+		LEFT JOIN benchmark_scores s
+			ON s.benchmark_id = h.benchmark_id AND s.model_name = h.model_name AND s.variant = h.variant
+		WHERE h.benchmark_id = ? AND h.variant = ?`
+	args := []interface{}{benchmarkID, variant}
+	if len(modelNames) > 0 {
+		query += fmt.Sprintf(` AND LOWER(h.model_name) IN (%s)`,
+			strings.TrimSuffix(strings.Repeat("?,", len(modelNames)), ","))
+		for _, name := range modelNames {
+			args = append(args, strings.ToLower(name))
+		}
+	}
+	query += ` ORDER BY h.model_name, h.scrape_date`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("load trend: %w", err)
 	}
-	return report, rows.Err()
+	defer rows.Close()
+
+	for rows.Next() {
+		var model, provider string
+		var p TrendPoint
+		if err := rows.Scan(&model, &provider, &p.Date, &p.Score); err != nil {
+			return nil, err
+		}
+		if n := len(report.Series); n == 0 || report.Series[n-1].Model != model {
+			report.Series = append(report.Series, TrendSeries{Model: model, Provider: provider})
+		}
+		series := &report.Series[len(report.Series)-1]
+		series.Points = append(series.Points, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	report.sortSeries()
+	return report, nil
 }
 
 // GetAllScores returns all stored scores.
 func (s *Store) GetAllScores(ctx context.Context) ([]BenchmarkScore, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, benchmark_id, model_name, model_provider, variant, score, source_url, scraped_at
+		SELECT id, benchmark_id, model_name, model_provider, variant, score, source_url, scraped_at,
+			source_type, confidence, notes
 		FROM benchmark_scores ORDER BY benchmark_id, model_name
 	`)
 	if err != nil {
@@ -151,7 +266,7 @@ func (s *Store) GetAllScores(ctx context.Context) ([]BenchmarkScore, error) {
 	for rows.Next() {
 		var sc BenchmarkScore
 		if err := rows.Scan(&sc.ID, &sc.BenchmarkID, &sc.ModelName, &sc.ModelProvider,
-			&sc.Variant, &sc.Score, &sc.SourceURL, &sc.ScrapedAt); err != nil {
+			&sc.Variant, &sc.Score, &sc.SourceURL, &sc.ScrapedAt, &sc.SourceType, &sc.Confidence, &sc.Notes); err != nil {
 			return nil, err
 		}
 		scores = append(scores, sc)
@@ -166,34 +281,285 @@ func (s *Store) ScoreCount(ctx context.Context) (int, error) {
 	return count, err
 }
 
-// SaveModels persists the model configuration.
-func (s *Store) SaveModels(ctx context.Context, models []ModelConfig) error {
-	tx, err := s.db.BeginTx(ctx, nil)
+// RecordRun logs a completed scrape with the number of scores processed and
+// the number that were new or changed.
+func (s *Store) RecordRun(ctx context.Context, scores, changes int) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO benchmark_runs (finished_at, scores, changes) VALUES (?, ?, ?)`,
+		time.Now(), scores, changes)
+	return err
+}
+
+// LastRun returns when the most recent scrape finished, or the zero time if
+// none was recorded.
+func (s *Store) LastRun(ctx context.Context) (time.Time, error) {
+	var t time.Time
+	err := s.db.QueryRowContext(ctx,
+		`SELECT finished_at FROM benchmark_runs ORDER BY id DESC LIMIT 1`).Scan(&t)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	return t, err
+}
+
+// RecordScrape adds one parser's outcome from the scrape started at startedAt
+// to the scrape history.
+func (s *Store) RecordScrape(ctx context.Context, startedAt time.Time, stats ParserStats) error {
+	errText := ""
+	if stats.Err != nil {
+		errText = stats.Err.Error()
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO benchmark_scrapes (parser, started_at, duration_ms, scores_added, scores_updated, quarantined, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		stats.Parser, startedAt, stats.Duration.Milliseconds(), stats.Added, stats.Updated, stats.Quarantined, errText)
+	return err
+}
+
+// GetScrapeHistory returns the most recent scrape history entries, newest
+// first.
+func (s *Store) GetScrapeHistory(ctx context.Context, limit int) ([]ScrapeRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT parser, started_at, duration_ms, scores_added, scores_updated, quarantined, error
+		FROM benchmark_scrapes ORDER BY id DESC LIMIT ?`, limit)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer tx.Rollback()
+	defer rows.Close()
 
-	// Clear and re-insert
-	if _, err := tx.ExecContext(ctx, `DELETE FROM benchmark_models`); err != nil {
-		return err
+	var result []ScrapeRecord
+	for rows.Next() {
+		var r ScrapeRecord
+		var ms int64
+		if err := rows.Scan(&r.Parser, &r.StartedAt, &ms, &r.Added, &r.Updated, &r.Quarantined, &r.Error); err != nil {
+			return nil, err
+		}
+		r.Duration = time.Duration(ms) * time.Millisecond
+		result = append(result, r)
 	}
+	return result, rows.Err()
+}
 
-	stmt, err := tx.PrepareContext(ctx, `
-		INSERT INTO benchmark_models (name, provider, thinking, gen, display_order)
-		VALUES (?, ?, ?, ?, ?)
-	`)
+// LastSuccessfulScrapes returns when each parser last completed without
+// error, for spotting stale data and broken parsers.
+func (s *Store) LastSuccessfulScrapes(ctx context.Context) (map[string]time.Time, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT parser, started_at FROM benchmark_scrapes
+		WHERE error = '' ORDER BY id DESC`)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer stmt.Close()
+	defer rows.Close()
 
-	for _, m := range models {
-		if _, err := stmt.ExecContext(ctx, m.Name, m.Provider, m.Thinking, m.Gen, m.DisplayOrder); err != nil {
+	result := make(map[string]time.Time)
+	for rows.Next() {
+		var parser string
+		var t time.Time
+		if err := rows.Scan(&parser, &t); err != nil {
+			return nil, err
+		}
+		if _, ok := result[parser]; !ok {
+			result[parser] = t
+		}
+	}
+	return result, rows.Err()
+}
+
+// ReplaceUnmatched replaces the model names source failed to match with
+// those from its latest run.
+func (s *Store) ReplaceUnmatched(ctx context.Context, source string, names []string) error {
+	return s.db.Transaction(ctx, func(tx *storage.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM benchmark_unmatched_models WHERE source = ?`, source); err != nil {
 			return err
 		}
+		now := time.Now()
+		for _, name := range names {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO benchmark_unmatched_models (source, raw_name, last_seen) VALUES (?, ?, ?)`,
+				source, name, now); err != nil {
+				return fmt.Errorf("insert unmatched %s: %w", name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetUnmatched returns the stored unmatched model names by source and name.
+func (s *Store) GetUnmatched(ctx context.Context) ([]UnmatchedModel, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT source, raw_name, last_seen FROM benchmark_unmatched_models
+		ORDER BY source, LOWER(raw_name)
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []UnmatchedModel
+	for rows.Next() {
+		var u UnmatchedModel
+		if err := rows.Scan(&u.Source, &u.RawName, &u.LastSeen); err != nil {
+			return nil, err
+		}
+		result = append(result, u)
+	}
+	return result, rows.Err()
+}
+
+// UpsertCandidates records new-model candidates and returns those not seen
+// before, which are the ones to notify about.
+func (s *Store) UpsertCandidates(ctx context.Context, candidates []ModelCandidate) ([]ModelCandidate, error) {
+	now := time.Now()
+	var added []ModelCandidate
+	err := s.db.Transaction(ctx, func(tx *storage.Tx) error {
+		for _, c := range candidates {
+			res, err := tx.ExecContext(ctx, `
+				UPDATE benchmark_model_candidates SET high_scores = ?, summary = ?, last_seen = ?
+				WHERE source = ? AND raw_name = ?`,
+				c.HighScores, c.Summary, now, c.Source, c.RawName)
+			if err != nil {
+				return err
+			}
+			if n, _ := res.RowsAffected(); n > 0 {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO benchmark_model_candidates (source, raw_name, high_scores, summary, first_seen, last_seen)
+				VALUES (?, ?, ?, ?, ?, ?)`,
+				c.Source, c.RawName, c.HighScores, c.Summary, now, now); err != nil {
+				return fmt.Errorf("insert candidate %s: %w", c.RawName, err)
+			}
+			c.FirstSeen = now
+			added = append(added, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// GetCandidates returns the new-model candidates that still fail to match,
+// most high scores first. Candidates disappear once an alias or model makes
+// their name match.
+func (s *Store) GetCandidates(ctx context.Context) ([]ModelCandidate, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT c.source, c.raw_name, c.high_scores, c.summary, c.first_seen
+		FROM benchmark_model_candidates c
+		JOIN benchmark_unmatched_models u ON u.source = c.source AND u.raw_name = c.raw_name
+		ORDER BY c.high_scores DESC, c.raw_name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []ModelCandidate
+	for rows.Next() {
+		var c ModelCandidate
+		if err := rows.Scan(&c.Source, &c.RawName, &c.HighScores, &c.Summary, &c.FirstSeen); err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, rows.Err()
+}
+
+// QuarantineScores holds back implausible scores for review, replacing any
+// earlier quarantined value for the same score.
+func (s *Store) QuarantineScores(ctx context.Context, scores []QuarantinedScore) error {
+	now := time.Now()
+	return s.db.Transaction(ctx, func(tx *storage.Tx) error {
+		for _, q := range scores {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO benchmark_quarantine
+					(benchmark_id, model_name, model_provider, variant, score, previous, reason,
+					 source_url, source_type, confidence, notes, quarantined_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+				ON CONFLICT(benchmark_id, model_name, variant) DO UPDATE SET
+					model_provider = excluded.model_provider,
+					score = excluded.score,
+					previous = excluded.previous,
+					reason = excluded.reason,
+					source_url = excluded.source_url,
+					source_type = excluded.source_type,
+					confidence = excluded.confidence,
+					notes = excluded.notes,
+					quarantined_at = excluded.quarantined_at`,
+				q.BenchmarkID, q.ModelName, q.ModelProvider, q.Variant, q.Score,
+				q.Previous, q.Reason, q.SourceURL, q.SourceType, q.Confidence, q.Notes, now); err != nil {
+				return fmt.Errorf("quarantine %s/%s: %w", q.BenchmarkID, q.ModelName, err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetQuarantined returns the quarantined scores, newest first.
+func (s *Store) GetQuarantined(ctx context.Context) ([]QuarantinedScore, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT benchmark_id, model_name, model_provider, variant, score, previous, reason,
+		       source_url, source_type, confidence, notes, quarantined_at
+		FROM benchmark_quarantine
+		ORDER BY quarantined_at DESC, benchmark_id, model_name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []QuarantinedScore
+	for rows.Next() {
+		var q QuarantinedScore
+		if err := rows.Scan(&q.BenchmarkID, &q.ModelName, &q.ModelProvider, &q.Variant, &q.Score, &q.Previous,
+			&q.Reason, &q.SourceURL, &q.SourceType, &q.Confidence, &q.Notes, &q.ScrapedAt); err != nil {
+			return nil, err
+		}
+		result = append(result, q)
+	}
+	return result, rows.Err()
+}
+
+// ReleaseQuarantined stores a quarantined score after review and removes it
+// from quarantine. It reports false if no such score was quarantined.
+func (s *Store) ReleaseQuarantined(ctx context.Context, benchmarkID, variant, modelName string) (bool, error) {
+	quarantined, err := s.GetQuarantined(ctx)
+	if err != nil {
+		return false, err
 	}
-	return tx.Commit()
+	for _, q := range quarantined {
+		if q.BenchmarkID != benchmarkID || q.Variant != variant || q.ModelName != modelName {
+			continue
+		}
+		if err := s.BulkUpsert(ctx, []BenchmarkScore{q.BenchmarkScore}); err != nil {
+			return false, err
+		}
+		_, err := s.db.ExecContext(ctx,
+			`DELETE FROM benchmark_quarantine WHERE benchmark_id = ? AND variant = ? AND model_name = ?`,
+			benchmarkID, variant, modelName)
+		return true, err
+	}
+	return false, nil
+}
+
+// SaveModels persists the model configuration.
+func (s *Store) SaveModels(ctx context.Context, models []ModelConfig) error {
+	return s.db.Transaction(ctx, func(tx *storage.Tx) error {
+		// Clear and re-insert
+		if _, err := tx.ExecContext(ctx, `DELETE FROM benchmark_models`); err != nil {
+			return err
+		}
+		for _, m := range models {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO benchmark_models (name, provider, thinking, gen, display_order)
+				VALUES (?, ?, ?, ?, ?)`,
+				m.Name, m.Provider, m.Thinking, m.Gen, m.DisplayOrder); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 // LoadModels loads the model configuration from DB, falls back to defaults.
@@ -0,0 +1,135 @@
+package benchmarks
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
+)
+
+// openTestDB opens a migrated SQLite database that is removed with the test.
+func openTestDB(t *testing.T) *storage.DB {
+	t.Helper()
+	db := openEmptyDB(t)
+	if err := db.Migrate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// openEmptyDB opens an empty SQLite database that is removed with the test.
+func openEmptyDB(t *testing.T) *storage.DB {
+	t.Helper()
+	db, err := storage.Open(storage.Config{Driver: storage.SQLite, DSN: filepath.Join(t.TempDir(), "benchmarks.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestStoreHistoryDeltas(t *testing.T) {
+	db := openTestDB(t)
+
+	store, err := NewStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, row := range []struct {
+		date  string
+		score float64
+	}{{"2026-01-01", 60}, {"2026-01-08", 62.5}} {
+		if _, err := db.Exec(`INSERT INTO benchmark_score_history (benchmark_id, model_name, variant, scrape_date, score)
+			VALUES ('gpqa_diamond', 'Opus 4.6', '', ?, ?)`, row.date, row.score); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.UpsertScore(ctx, BenchmarkScore{BenchmarkID: "gpqa_diamond", ModelName: "Opus 4.6", ModelProvider: "anthropic", Score: 61}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := store.GetScoresForReport(ctx, DefaultModels, "2026-02-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := report.Trend("gpqa_diamond", "", "Opus 4.6"); len(got) != 3 || got[2] != 61 {
+		t.Fatalf("unexpected trend: %v", got)
+	}
+	if delta, ok := report.Delta("gpqa_diamond", "", "Opus 4.6"); !ok || delta != -1.5 {
+		t.Fatalf("expected delta -1.5, got %v (%v)", delta, ok)
+	}
+	if spark := Sparkline([]float64{60, 62.5, 61}); spark != "▁█▃" {
+		t.Fatalf("unexpected sparkline %q", spark)
+	}
+	if html := NewHTMLRenderer().RenderHTML(report); !strings.Contains(html, "▼1.5") {
+		t.Fatal("expected delta in HTML report")
+	}
+}
+
+func TestStoreProvenance(t *testing.T) {
+	db := openEmptyDB(t)
+	ctx := context.Background()
+	if _, err := NewStore(db); err == nil {
+		t.Error("NewStore succeeded on an unmigrated database")
+	}
+
+	// A table from before provenance tracking must be migrated in place
+	if _, err := db.Exec(`CREATE TABLE benchmark_scores (
+		id INTEGER PRIMARY KEY, benchmark_id TEXT NOT NULL, model_name TEXT NOT NULL,
+		model_provider TEXT NOT NULL, variant TEXT DEFAULT '', score REAL NOT NULL,
+		source_url TEXT DEFAULT '', scraped_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(benchmark_id, model_name, variant))`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO benchmark_scores (benchmark_id, model_name, model_provider, score)
+		VALUES ('mmmlu', 'Opus 4.6', 'anthropic', 91.1)`); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// The migration backfills history from the scores already stored
+	var history int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM benchmark_score_history WHERE benchmark_id = 'mmmlu'`).Scan(&history); err != nil || history != 1 {
+		t.Errorf("backfilled history rows = %d, %v; want 1", history, err)
+	}
+
+	store, err := NewStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.BulkUpsert(ctx, []BenchmarkScore{
+		{BenchmarkID: "gpqa_diamond", ModelName: "Opus 4.6", ModelProvider: "anthropic", Score: 91.3, SourceType: SourceVendor, Confidence: 1},
+		{BenchmarkID: "gpqa_diamond", ModelName: "GPT-5.2", ModelProvider: "openai", Score: 92.4, SourceType: SourceThirdParty, Confidence: 1},
+		{BenchmarkID: "hle", Variant: "No tools", ModelName: "GPT-5.2", ModelProvider: "openai", Score: 34.5,
+			SourceType: SourceLLMExtracted, Confidence: 0.6, Notes: "openai evaluation page"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := store.GetScoresForReport(ctx, DefaultModels, "2026-02-20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct{ bench, variant, model, mark string }{
+		{"gpqa_diamond", "", "Opus 4.6", "*"},
+		{"gpqa_diamond", "", "GPT-5.2", ""},
+		{"hle", "No tools", "GPT-5.2", "~"},
+		{"mmmlu", "", "Opus 4.6", ""},
+	} {
+		if got := report.ProvenanceMark(tc.bench, tc.variant, tc.model); got != tc.mark {
+			t.Errorf("%s/%s mark = %q, want %q", tc.bench, tc.model, got, tc.mark)
+		}
+	}
+
+	html := NewHTMLRenderer().RenderHTML(report)
+	if !strings.Contains(html, `title="LLM-extracted (confidence 60%) · openai evaluation page">~</sup>`) {
+		t.Error("HTML missing LLM-extracted marker")
+	}
+	if !strings.Contains(html, `title="Vendor-reported">*</sup>`) {
+		t.Error("HTML missing vendor marker")
+	}
+}
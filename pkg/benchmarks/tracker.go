@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
+	"sort"
 	"time"
 )
 
@@ -13,6 +15,64 @@ type Tracker struct {
 	scraper  *Scraper
 	interval time.Duration
 	OnUpdate func(report *BenchmarkReport) // called when new data is found
+	// OnChanges is called with the scores that are new or changed since the
+	// previous run, alongside OnUpdate.
+	OnChanges func(report *BenchmarkReport, changes []ScoreChange)
+	// OnReport is called with the full report after every run, changed or
+	// not, for scheduled delivery.
+	OnReport func(report *BenchmarkReport)
+	// OnCandidates is called with leaderboard models first flagged as
+	// likely new releases (see ModelCandidate).
+	OnCandidates func(candidates []ModelCandidate)
+}
+
+// ScoreChange is a score that appeared or moved between two scrapes.
+type ScoreChange struct {
+	BenchmarkID string
+	Variant     string
+	ModelName   string
+	Previous    float64 // zero when New
+	Current     float64
+	New         bool
+}
+
+// Delta returns Current - Previous (zero for new scores).
+func (c ScoreChange) Delta() float64 {
+	if c.New {
+		return 0
+	}
+	return c.Current - c.Previous
+}
+
+// DiffScores returns scores in after that are missing from or differ from
+// before, largest movements first and new scores last.
+func DiffScores(before, after []BenchmarkScore) []ScoreChange {
+	prev := make(map[string]float64, len(before))
+	for _, sc := range before {
+		prev[ScoreKey(sc.BenchmarkID, sc.Variant)+"|"+sc.ModelName] = sc.Score
+	}
+
+	var changes []ScoreChange
+	for _, sc := range after {
+		c := ScoreChange{BenchmarkID: sc.BenchmarkID, Variant: sc.Variant, ModelName: sc.ModelName, Current: sc.Score}
+		old, ok := prev[ScoreKey(sc.BenchmarkID, sc.Variant)+"|"+sc.ModelName]
+		switch {
+		case !ok:
+			c.New = true
+		case math.Abs(old-sc.Score) < 1e-9:
+			continue
+		default:
+			c.Previous = old
+		}
+		changes = append(changes, c)
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].New != changes[j].New {
+			return !changes[i].New
+		}
+		return math.Abs(changes[i].Delta()) > math.Abs(changes[j].Delta())
+	})
+	return changes
 }
 
 // NewTracker creates a new benchmark tracker with the given interval.
@@ -24,51 +84,76 @@ func NewTracker(store *Store, scraper *Scraper, interval time.Duration) *Tracker
 	}
 }
 
-// Run starts the periodic scraping loop. Blocks until context is cancelled.
-func (t *Tracker) Run(ctx context.Context, models []ModelConfig) {
-	log.Printf("[benchmark-tracker] Starting with interval %v", t.interval)
-
-	// Run once immediately
-	t.runOnce(ctx, models)
+// NextRun returns when the next scrape is due: one interval after the last
+// recorded run, or now if there is none, so restarts keep the schedule.
+func (t *Tracker) NextRun(ctx context.Context) (time.Time, error) {
+	last, err := t.store.LastRun(ctx)
+	if err != nil || last.IsZero() {
+		return time.Now(), err
+	}
+	return last.Add(t.interval), nil
+}
 
-	ticker := time.NewTicker(t.interval)
-	defer ticker.Stop()
+// RunOnce runs a single scrape cycle and triggers notification if scores
+// were added or changed. Failing parsers are logged, not returned; errors
+// reading the store are.
+func (t *Tracker) RunOnce(ctx context.Context, models []ModelConfig) error {
+	before, err := t.store.GetAllScores(ctx)
+	if err != nil {
+		return fmt.Errorf("snapshot scores: %w", err)
+	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			log.Println("[benchmark-tracker] Shutting down")
-			return
-		case <-ticker.C:
-			t.runOnce(ctx, models)
+	summary := t.scraper.Scrape(ctx)
+	for _, ps := range summary.Parsers {
+		if ps.Err != nil {
+			log.Printf("[benchmark-tracker] %s: %d scores (%d quarantined) in %v, error: %v",
+				ps.Parser, ps.Scores, ps.Quarantined, ps.Duration.Round(time.Millisecond), ps.Err)
+		} else {
+			log.Printf("[benchmark-tracker] %s: %d scores (%d quarantined) in %v",
+				ps.Parser, ps.Scores, ps.Quarantined, ps.Duration.Round(time.Millisecond))
+		}
+	}
+	n := summary.Total()
+	if len(summary.NewCandidates) > 0 {
+		log.Printf("[benchmark-tracker] %d new model candidates", len(summary.NewCandidates))
+		if t.OnCandidates != nil {
+			t.OnCandidates(summary.NewCandidates)
 		}
 	}
-}
-
-// RunOnce runs a single scrape cycle and triggers notification if new data found.
-func (t *Tracker) runOnce(ctx context.Context, models []ModelConfig) {
-	oldCount, _ := t.store.ScoreCount(ctx)
 
-	n, err := t.scraper.ScrapeAll(ctx)
+	after, err := t.store.GetAllScores(ctx)
 	if err != nil {
-		log.Printf("[benchmark-tracker] Scrape error: %v", err)
+		return fmt.Errorf("snapshot scores: %w", err)
 	}
+	changes := DiffScores(before, after)
 
-	newCount, _ := t.store.ScoreCount(ctx)
-	newScores := newCount - oldCount
-
-	log.Printf("[benchmark-tracker] Scrape complete: %d processed, %d new scores", n, newScores)
+	log.Printf("[benchmark-tracker] Scrape complete: %d processed, %d new or changed scores", n, len(changes))
+	if err := t.store.RecordRun(ctx, n, len(changes)); err != nil {
+		log.Printf("[benchmark-tracker] Record run error: %v", err)
+	}
 
-	if newScores > 0 && t.OnUpdate != nil {
-		date := time.Now().Format("2006-01-02")
-		report, err := t.store.GetScoresForReport(ctx, models, date)
-		if err != nil {
-			log.Printf("[benchmark-tracker] Report error: %v", err)
-			return
+	notifyChanges := len(changes) > 0 && (t.OnUpdate != nil || t.OnChanges != nil)
+	if !notifyChanges && t.OnReport == nil {
+		return nil
+	}
+	date := time.Now().Format("2006-01-02")
+	report, err := t.store.GetScoresForReport(ctx, models, date)
+	if err != nil {
+		return fmt.Errorf("build report: %w", err)
+	}
+	report.FilterEmptyModels(3, 10)
+	if notifyChanges {
+		if t.OnUpdate != nil {
+			t.OnUpdate(report)
+		}
+		if t.OnChanges != nil {
+			t.OnChanges(report, changes)
 		}
-		report.FilterEmptyModels(3, 10)
-		t.OnUpdate(report)
 	}
+	if t.OnReport != nil {
+		t.OnReport(report)
+	}
+	return nil
 }
 
 // QuickReport generates a benchmark report without scraping.
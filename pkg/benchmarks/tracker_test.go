@@ -0,0 +1,41 @@
+package benchmarks
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTrackerScheduledReport(t *testing.T) {
+	db := openTestDB(t)
+
+	store, err := NewStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if last, err := store.LastRun(ctx); err != nil || !last.IsZero() {
+		t.Fatalf("LastRun on empty store = %v, %v", last, err)
+	}
+
+	scraper := NewScraper(store, NewManualParser([]BenchmarkScore{
+		{BenchmarkID: "gpqa_diamond", ModelName: "Opus 4.6", ModelProvider: "anthropic", Score: 91.3},
+	}))
+	tracker := NewTracker(store, scraper, time.Hour)
+	var reports, changed int
+	tracker.OnReport = func(*BenchmarkReport) { reports++ }
+	tracker.OnChanges = func(_ *BenchmarkReport, changes []ScoreChange) { changed += len(changes) }
+
+	// The second run finds nothing new but still delivers the scheduled report
+	for i := 0; i < 2; i++ {
+		if err := tracker.RunOnce(ctx, DefaultModels); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if reports != 2 || changed != 1 {
+		t.Errorf("reports = %d, changes = %d; want 2 and 1", reports, changed)
+	}
+	if last, err := store.LastRun(ctx); err != nil || time.Since(last) > time.Minute {
+		t.Errorf("LastRun = %v, %v; want just now", last, err)
+	}
+}
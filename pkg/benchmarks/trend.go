@@ -0,0 +1,222 @@
+package benchmarks
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/fogleman/gg"
+)
+
+// TrendPoint is a model's score on one scrape day.
+type TrendPoint struct {
+	Date  string  `json:"date"` // YYYY-MM-DD
+	Score float64 `json:"score"`
+}
+
+// TrendSeries is one model's score history, oldest first.
+type TrendSeries struct {
+	Model    string       `json:"model"`
+	Provider string       `json:"provider"`
+	Points   []TrendPoint `json:"points"`
+}
+
+// Latest returns the most recent score.
+func (s TrendSeries) Latest() float64 {
+	if len(s.Points) == 0 {
+		return 0
+	}
+	return s.Points[len(s.Points)-1].Score
+}
+
+// TrendReport holds how models scored on one benchmark across scrapes, so
+// a new release's improvement shows as a step in its line. Series are
+// ordered by latest score, best first.
+type TrendReport struct {
+	BenchmarkID string        `json:"benchmark_id"`
+	Benchmark   string        `json:"benchmark"`
+	Variant     string        `json:"variant,omitempty"`
+	Unit        string        `json:"unit"`
+	Series      []TrendSeries `json:"series"`
+}
+
+// Title names the benchmark and variant, e.g. "Humanity's Last Exam · No
+// tools".
+func (t *TrendReport) Title() string {
+	name := t.Benchmark
+	if name == "" {
+		name = t.BenchmarkID
+	}
+	if t.Variant != "" {
+		name += " · " + t.Variant
+	}
+	return name
+}
+
+// sortSeries orders series by latest score, best first, then by name.
+func (t *TrendReport) sortSeries() {
+	sort.SliceStable(t.Series, func(i, j int) bool {
+		a, b := t.Series[i].Latest(), t.Series[j].Latest()
+		if a != b {
+			return a > b
+		}
+		return t.Series[i].Model < t.Series[j].Model
+	})
+}
+
+// RenderPNG draws the report as a 1600×900 line chart, one line per model
+// in its provider color, and writes it to w as PNG.
+func (t *TrendReport) RenderPNG(w io.Writer) error {
+	return NewChartRenderer().RenderTrend(t, w)
+}
+
+// RenderTrend draws a score-over-time line chart with one line per model.
+// A provider's second and later models are dashed so they stay apart. The
+// y axis spans the scores shown rather than 0–100, so small gains between
+// releases stay visible.
+func (c *ChartRenderer) RenderTrend(t *TrendReport, w io.Writer) error {
+	var first, last time.Time
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, s := range t.Series {
+		for _, p := range s.Points {
+			d, err := time.Parse("2006-01-02", p.Date)
+			if err != nil {
+				return fmt.Errorf("series %s: invalid date %q", s.Model, p.Date)
+			}
+			if first.IsZero() || d.Before(first) {
+				first = d
+			}
+			if d.After(last) {
+				last = d
+			}
+			lo, hi = math.Min(lo, p.Score), math.Max(hi, p.Score)
+		}
+	}
+	if first.IsZero() {
+		return fmt.Errorf("no score history for %s", t.Title())
+	}
+	lo, hi, step := trendAxis(lo, hi, t.Unit)
+	span := last.Sub(first)
+
+	dc := gg.NewContext(int(c.Width), int(c.Height))
+	c.inner.drawBackground(dc, c.Height)
+	c.drawTitle(dc, t.Title()+" · Score trend", "#4fc3f7")
+
+	left, right := c.Pad+20, c.Width-c.Pad
+	top, bottom := c.Pad+70, c.Height-c.Pad-70
+	x := func(date string) float64 {
+		if span == 0 {
+			return (left + right) / 2
+		}
+		d, _ := time.Parse("2006-01-02", date)
+		return left + (right-left)*float64(d.Sub(first))/float64(span)
+	}
+	y := func(score float64) float64 {
+		return bottom - (bottom-top)*(score-lo)/(hi-lo)
+	}
+
+	// Horizontal grid with score labels
+	c.inner.loadFont(dc, c.FontSize-4, false)
+	dc.SetLineWidth(1)
+	for v := lo; v <= hi+step/2; v += step {
+		dc.SetColor(hexColor("#1e1e3a"))
+		dc.DrawLine(left, y(v), right, y(v))
+		dc.Stroke()
+		dc.SetColor(hexColor("#555570"))
+		dc.DrawStringAnchored(formatScore(v, t.Unit), left-12, y(v), 1, 0.5)
+	}
+
+	// Date labels, at most one per day and per ~200px
+	dc.SetColor(hexColor("#555570"))
+	if span == 0 {
+		dc.DrawStringAnchored(first.Format("2006-01-02"), (left+right)/2, bottom+24, 0.5, 0.5)
+	} else {
+		ticks := max(1, min(int((right-left)/200), int(span.Hours()/24)))
+		for i := 0; i <= ticks; i++ {
+			d := first.Add(span * time.Duration(i) / time.Duration(ticks))
+			dc.DrawStringAnchored(d.Format("2006-01-02"), left+(right-left)*float64(i)/float64(ticks), bottom+24, 0.5, 0.5)
+		}
+	}
+
+	seen := make(map[string]int)
+	dashed := make([]bool, len(t.Series))
+	for i, s := range t.Series {
+		dashed[i] = seen[s.Provider] > 0
+		seen[s.Provider]++
+	}
+	for i, s := range t.Series {
+		dc.SetColor(hexColor(ProviderColor(s.Provider)))
+		dc.SetLineWidth(3)
+		if dashed[i] {
+			dc.SetDash(12, 8)
+		}
+		for _, p := range s.Points {
+			dc.LineTo(x(p.Date), y(p.Score))
+		}
+		dc.Stroke()
+		dc.SetDash()
+		for _, p := range s.Points {
+			dc.DrawCircle(x(p.Date), y(p.Score), 5)
+			dc.Fill()
+		}
+		if len(s.Points) > 0 {
+			end := s.Points[len(s.Points)-1]
+			c.inner.loadFont(dc, c.FontSize-6, false)
+			dc.SetColor(hexColor("#c0c0d0"))
+			dc.DrawStringAnchored(formatScore(end.Score, t.Unit), x(end.Date)+10, y(end.Score)-12, 0, 0.5)
+		}
+	}
+
+	c.drawTrendLegend(dc, t.Series, dashed, c.Height-30)
+	return dc.EncodePNG(w)
+}
+
+// trendAxis widens [lo, hi] to a round range with about five grid steps.
+// Percentages stay within 0–100.
+func trendAxis(lo, hi float64, unit string) (float64, float64, float64) {
+	if hi-lo < 1 {
+		lo, hi = lo-1, hi+1
+	}
+	step := niceStep((hi - lo) / 5)
+	lo = math.Floor(lo/step) * step
+	hi = math.Ceil(hi/step) * step
+	if unit == "%" {
+		lo, hi = math.Max(0, lo), math.Min(100, hi)
+	}
+	if hi <= lo {
+		hi = lo + step
+	}
+	return lo, hi, step
+}
+
+// niceStep rounds a grid step up to 1, 2 or 5 times a power of ten.
+func niceStep(raw float64) float64 {
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, m := range []float64{1, 2, 5} {
+		if raw <= m*mag {
+			return m * mag
+		}
+	}
+	return 10 * mag
+}
+
+func (c *ChartRenderer) drawTrendLegend(dc *gg.Context, series []TrendSeries, dashed []bool, y float64) {
+	c.inner.loadFont(dc, c.FontSize-4, false)
+	x := c.Pad
+	for i, s := range series {
+		dc.SetColor(hexColor(ProviderColor(s.Provider)))
+		dc.SetLineWidth(3)
+		if dashed[i] {
+			dc.SetDash(6, 4)
+		}
+		dc.DrawLine(x, y, x+28, y)
+		dc.Stroke()
+		dc.SetDash()
+		dc.SetColor(hexColor("#aaaacc"))
+		dc.DrawStringAnchored(s.Model, x+36, y, 0, 0.5)
+		w, _ := dc.MeasureString(s.Model)
+		x += w + 64
+	}
+}
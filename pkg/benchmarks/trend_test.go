@@ -0,0 +1,70 @@
+package benchmarks
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestTrend(t *testing.T) {
+	db := openTestDB(t)
+
+	store, err := NewStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, row := range []struct {
+		model, variant, date string
+		score                float64
+	}{
+		{"Opus 4.6", "No tools", "2026-01-01", 30},
+		{"Opus 4.6", "No tools", "2026-02-01", 36.5},
+		{"GPT-5.2", "No tools", "2026-01-15", 38},
+		{"GPT-5.2", "Search+Code", "2026-01-15", 45},
+	} {
+		if _, err := db.Exec(`INSERT INTO benchmark_score_history (benchmark_id, model_name, variant, scrape_date, score)
+			VALUES ('hle', ?, ?, ?, ?)`, row.model, row.variant, row.date, row.score); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.UpsertScore(ctx, BenchmarkScore{BenchmarkID: "hle", ModelName: "Opus 4.6", ModelProvider: "anthropic", Variant: "No tools", Score: 40}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := store.Trend(ctx, "hle", "No tools")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Benchmark != "Humanity's Last Exam" || report.Unit != "%" || len(report.Series) != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	// Best latest score first; today's upsert extends the history
+	opus := report.Series[0]
+	if opus.Model != "Opus 4.6" || opus.Provider != "anthropic" || len(opus.Points) != 3 || opus.Latest() != 40 {
+		t.Fatalf("unexpected series: %+v", opus)
+	}
+	if gpt := report.Series[1]; gpt.Model != "GPT-5.2" || len(gpt.Points) != 1 || gpt.Provider != "" {
+		t.Fatalf("unexpected series: %+v", gpt)
+	}
+
+	only, err := store.Trend(ctx, "hle", "No tools", "gpt-5.2")
+	if err != nil || len(only.Series) != 1 || only.Series[0].Model != "GPT-5.2" {
+		t.Fatalf("model filter: %+v, %v", only, err)
+	}
+	both, err := store.Trend(ctx, "hle", "No tools", "OPUS 4.6", "gpt-5.2", "Unknown")
+	if err != nil || len(both.Series) != 2 {
+		t.Fatalf("model filter ignoring case: %+v, %v", both, err)
+	}
+
+	var buf bytes.Buffer
+	if err := report.RenderPNG(&buf); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("\x89PNG")) {
+		t.Fatal("expected a PNG")
+	}
+	if err := (&TrendReport{BenchmarkID: "hle"}).RenderPNG(&buf); err == nil {
+		t.Fatal("expected an error without history")
+	}
+}
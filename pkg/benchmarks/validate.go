@@ -0,0 +1,79 @@
+package benchmarks
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Plausible score ranges and the largest change between two scrapes that is
+// accepted without review.
+const (
+	minElo         = 500
+	maxElo         = 3000
+	maxPercentJump = 20.0 // percentage points
+	maxEloJump     = 200.0
+)
+
+// ValidateScore checks a scraped score against its benchmark definition and
+// the currently stored score, if any. It returns why the score is implausible,
+// or "" if it may be stored.
+func ValidateScore(sc BenchmarkScore, previous float64, hasPrevious bool) string {
+	bench := FindBenchmark(sc.BenchmarkID)
+	if bench == nil {
+		return "unknown benchmark"
+	}
+	if len(bench.Variants) == 0 && sc.Variant != "" {
+		return fmt.Sprintf("%s has no variants, got %q", bench.ID, sc.Variant)
+	}
+	if len(bench.Variants) > 0 && !slices.Contains(bench.Variants, sc.Variant) {
+		return fmt.Sprintf("unknown variant %q", sc.Variant)
+	}
+	if math.IsNaN(sc.Score) || math.IsInf(sc.Score, 0) {
+		return "score is not a number"
+	}
+
+	switch bench.Unit {
+	case "Elo":
+		if sc.Score < minElo || sc.Score > maxElo {
+			return fmt.Sprintf("Elo %g outside %d–%d", sc.Score, minElo, maxElo)
+		}
+		if hasPrevious && math.Abs(sc.Score-previous) > maxEloJump {
+			return fmt.Sprintf("jump from %g to %g exceeds %g Elo", previous, sc.Score, maxEloJump)
+		}
+	default:
+		if sc.Score < 0 || sc.Score > 100 {
+			return fmt.Sprintf("%g%% outside 0–100", sc.Score)
+		}
+		// 0.91 where 91% was stored: a fraction, not a percentage
+		if hasPrevious && sc.Score > 0 && sc.Score <= 1 && previous > 5 {
+			return fmt.Sprintf("%g looks like a fraction (stored %g%%)", sc.Score, previous)
+		}
+		if hasPrevious && math.Abs(sc.Score-previous) > maxPercentJump {
+			return fmt.Sprintf("jump from %g%% to %g%% exceeds %g points", previous, sc.Score, maxPercentJump)
+		}
+	}
+	return ""
+}
+
+// QuarantinedScore is a scraped score held back by ValidateScore.
+type QuarantinedScore struct {
+	BenchmarkScore
+	Previous float64 // stored score at the time; zero if none
+	Reason   string
+}
+
+// validateScores splits scores into those that pass ValidateScore against
+// the stored scores and those to quarantine.
+func validateScores(scores []BenchmarkScore, stored map[string]float64) (valid []BenchmarkScore, quarantined []QuarantinedScore) {
+	for _, sc := range scores {
+		key := ScoreKey(sc.BenchmarkID, sc.Variant) + "|" + sc.ModelName
+		prev, ok := stored[key]
+		if reason := ValidateScore(sc, prev, ok); reason != "" {
+			quarantined = append(quarantined, QuarantinedScore{BenchmarkScore: sc, Previous: prev, Reason: reason})
+			continue
+		}
+		valid = append(valid, sc)
+	}
+	return valid, quarantined
+}
@@ -0,0 +1,66 @@
+package benchmarks
+
+import (
+	"context"
+	"testing"
+)
+
+func TestScoreValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		score   BenchmarkScore
+		prev    float64
+		hasPrev bool
+		valid   bool
+	}{
+		{"plausible", BenchmarkScore{BenchmarkID: "gpqa_diamond", Score: 91.3}, 90, true, true},
+		{"over 100%", BenchmarkScore{BenchmarkID: "gpqa_diamond", Score: 130}, 0, false, false},
+		{"fraction", BenchmarkScore{BenchmarkID: "gpqa_diamond", Score: 0.91}, 90, true, false},
+		{"jump", BenchmarkScore{BenchmarkID: "gpqa_diamond", Score: 60}, 90, true, false},
+		{"low Elo", BenchmarkScore{BenchmarkID: "lmarena", Variant: "Overall", Score: 91.3}, 0, false, false},
+		{"Elo", BenchmarkScore{BenchmarkID: "lmarena", Variant: "Overall", Score: 1450}, 1420, true, true},
+		{"unknown variant", BenchmarkScore{BenchmarkID: "hle", Variant: "Tools", Score: 40}, 0, false, false},
+		{"unknown benchmark", BenchmarkScore{BenchmarkID: "nope", Score: 40}, 0, false, false},
+	} {
+		reason := ValidateScore(tc.score, tc.prev, tc.hasPrev)
+		if (reason == "") != tc.valid {
+			t.Errorf("%s: reason = %q, want valid=%v", tc.name, reason, tc.valid)
+		}
+	}
+
+	db := openTestDB(t)
+	store, err := NewStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := store.UpsertScore(ctx, BenchmarkScore{BenchmarkID: "gpqa_diamond", ModelName: "Opus 4.6", ModelProvider: "anthropic", Score: 91.3}); err != nil {
+		t.Fatal(err)
+	}
+
+	summary := NewScraper(store, NewManualParser([]BenchmarkScore{
+		{BenchmarkID: "gpqa_diamond", ModelName: "Opus 4.6", ModelProvider: "anthropic", Score: 41.3},
+		{BenchmarkID: "gpqa_diamond", ModelName: "GPT-5.2", ModelProvider: "openai", Score: 92.4},
+	})).Scrape(ctx)
+	if ps := summary.Parsers[0]; ps.Scores != 1 || ps.Quarantined != 1 || ps.Err != nil {
+		t.Fatalf("stats = %+v", ps)
+	}
+	quarantined, err := store.GetQuarantined(ctx)
+	if err != nil || len(quarantined) != 1 || quarantined[0].Previous != 91.3 {
+		t.Fatalf("quarantined = %+v, %v", quarantined, err)
+	}
+
+	if ok, err := store.ReleaseQuarantined(ctx, "gpqa_diamond", "", "Opus 4.6"); !ok || err != nil {
+		t.Fatalf("release = %v, %v", ok, err)
+	}
+	report, err := store.GetScoresForReport(ctx, DefaultModels, "2026-02-20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score, _ := report.GetScore("gpqa_diamond", "", "Opus 4.6"); score != 41.3 {
+		t.Errorf("released score = %g, want 41.3", score)
+	}
+	if quarantined, _ := store.GetQuarantined(ctx); len(quarantined) != 0 {
+		t.Errorf("%d scores still quarantined", len(quarantined))
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -91,3 +92,85 @@ func TestLoadOrDefault_MissingFile(t *testing.T) {
 		t.Fatalf("expected empty name, got '%s'", cfg.Name)
 	}
 }
+
+func TestLoadSuite(t *testing.T) {
+	path := t.TempDir() + "/devkit-suite.yaml"
+	content := `
+llm:
+  provider: gemini
+  model: gemini-flash-latest
+smtp:
+  host: smtp.example.com
+  port: "465"
+watchbot:
+  backup_keep: 3
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("LLM_MODEL", "gemini-pro-latest")
+	t.Setenv("SMTP_HOST", "")
+	os.Unsetenv("SMTP_HOST")
+	t.Setenv("WATCHBOT_BACKUP_KEEP", "")
+	os.Unsetenv("WATCHBOT_BACKUP_KEEP")
+
+	s, err := LoadSuite(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("expected valid config, got: %v", err)
+	}
+	if s.LLM.Model != "gemini-pro-latest" {
+		t.Fatalf("expected env to override model, got '%s'", s.LLM.Model)
+	}
+
+	s.Export()
+	if got := os.Getenv("SMTP_HOST"); got != "smtp.example.com" {
+		t.Fatalf("expected SMTP_HOST exported from file, got '%s'", got)
+	}
+	if got := os.Getenv("WATCHBOT_BACKUP_KEEP"); got != "3" {
+		t.Fatalf("expected WATCHBOT_BACKUP_KEEP=3, got '%s'", got)
+	}
+	if got := os.Getenv("LLM_MODEL"); got != "gemini-pro-latest" {
+		t.Fatalf("expected env LLM_MODEL kept, got '%s'", got)
+	}
+}
+
+func TestLoadSuite_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	typo := dir + "/typo.yaml"
+	os.WriteFile(typo, []byte("llm:\n  modle: gpt-4o\n"), 0o600)
+	if _, err := LoadSuite(typo); err == nil {
+		t.Fatal("expected unknown key to be rejected")
+	}
+
+	bad := dir + "/bad.yaml"
+	os.WriteFile(bad, []byte(`
+llm:
+  provider: nope
+database:
+  driver: mysql
+watchbot:
+  benchmark_interval: weekly
+newsbot:
+  schedule: "0 25 * * *"
+telemetry:
+  endpoint: telemetry.example.com
+api:
+  rate_limit_free: lots
+`), 0o600)
+	for _, key := range []string{"LLM_PROVIDER", "WATCHBOT_DB_DRIVER", "BENCHMARK_INTERVAL", "NEWSBOT_SCHEDULE", "DEVKIT_TELEMETRY_ENDPOINT", "API_RATE_LIMIT_FREE"} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+	err := ValidateSuite(bad)
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, field := range []string{"llm.provider", "database.driver", "watchbot.benchmark_interval", "newsbot.schedule", "telemetry.endpoint", "api.rate_limit_free"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("expected an error for %s, got: %v", field, err)
+		}
+	}
+}
@@ -0,0 +1,369 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/jobs"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/llm"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/ratelimit"
+)
+
+// SuiteFileEnv names the env var that points at the suite config file.
+const SuiteFileEnv = "DEVKIT_CONFIG"
+
+// DefaultSuiteFile is the suite config read from the working directory when
+// SuiteFileEnv is unset.
+const DefaultSuiteFile = "devkit-suite.yaml"
+
+// Suite is the shared configuration of watchbot, newsbot, devkit and the API
+// server. Every field carries the env var the binaries already read, so an
+// exported env var always wins over the file.
+type Suite struct {
+	LLM        SuiteLLM        `yaml:"llm"`
+	SMTP       SuiteSMTP       `yaml:"smtp"`
+	Telegram   SuiteTelegram   `yaml:"telegram"`
+	Discord    SuiteDiscord    `yaml:"discord"`
+	WeChatWork SuiteWeChatWork `yaml:"wechatwork"`
+	Database   SuiteDatabase   `yaml:"database"`
+	API        SuiteAPI        `yaml:"api"`
+	WatchBot   SuiteWatchBot   `yaml:"watchbot"`
+	NewsBot    SuiteNewsBot    `yaml:"newsbot"`
+	Telemetry  SuiteTelemetry  `yaml:"telemetry"`
+	Metrics    SuiteMetrics    `yaml:"metrics"`
+}
+
+// SuiteLLM selects the LLM provider used by all bots.
+type SuiteLLM struct {
+	Provider string `yaml:"provider" env:"LLM_PROVIDER"`
+	Model    string `yaml:"model" env:"LLM_MODEL"`
+	APIKey   string `yaml:"api_key" env:"LLM_API_KEY"`
+	BaseURL  string `yaml:"base_url" env:"LLM_BASE_URL"`
+	// Deployment and APIVersion select an Azure OpenAI deployment
+	Deployment string `yaml:"deployment" env:"LLM_DEPLOYMENT"`
+	APIVersion string `yaml:"api_version" env:"LLM_API_VERSION"`
+	// Cache reuses responses to identical prompts: memory or db
+	Cache    string `yaml:"cache" env:"LLM_CACHE"`
+	CacheTTL string `yaml:"cache_ttl" env:"LLM_CACHE_TTL"`
+	// EmbeddingModel embeds articles to merge duplicate news coverage
+	EmbeddingModel string `yaml:"embedding_model" env:"LLM_EMBEDDING_MODEL"`
+}
+
+// SuiteSMTP is the outgoing mail server.
+type SuiteSMTP struct {
+	Host              string `yaml:"host" env:"SMTP_HOST"`
+	Port              string `yaml:"port" env:"SMTP_PORT"`
+	From              string `yaml:"from" env:"SMTP_FROM"`
+	Password          string `yaml:"password" env:"SMTP_PASSWORD"`
+	To                string `yaml:"to" env:"SMTP_TO"`
+	UnsubscribeMailto string `yaml:"unsubscribe_mailto" env:"SMTP_UNSUBSCRIBE_MAILTO"`
+}
+
+// SuiteTelegram is the Telegram channel notifications are posted to.
+type SuiteTelegram struct {
+	BotToken  string `yaml:"bot_token" env:"TELEGRAM_BOT_TOKEN"`
+	ChannelID string `yaml:"channel_id" env:"TELEGRAM_CHANNEL_ID"`
+}
+
+// SuiteDiscord is the Discord webhook notifications are posted to.
+type SuiteDiscord struct {
+	WebhookURL string `yaml:"webhook_url" env:"DISCORD_WEBHOOK_URL"`
+}
+
+// SuiteWeChatWork is the WeChat Work group robot notifications are posted to.
+type SuiteWeChatWork struct {
+	WebhookURL string `yaml:"webhook_url" env:"WECHATWORK_WEBHOOK_URL"` // robot URL or bare key
+	MsgType    string `yaml:"msg_type" env:"WECHATWORK_MSG_TYPE"`       // markdown or news
+}
+
+// SuiteDatabase is the database shared by watchbot and the API server.
+type SuiteDatabase struct {
+	Driver          string `yaml:"driver" env:"WATCHBOT_DB_DRIVER"` // sqlite or postgres
+	DSN             string `yaml:"dsn" env:"WATCHBOT_DB"`
+	MaxOpenConns    int    `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int    `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime string `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	BusyTimeout     string `yaml:"busy_timeout" env:"DB_BUSY_TIMEOUT"`
+}
+
+// SuiteAPI configures the REST API server.
+type SuiteAPI struct {
+	Port            string `yaml:"port" env:"API_PORT"`
+	JWTSecret       string `yaml:"jwt_secret" env:"JWT_SECRET"`
+	FrontendURL     string `yaml:"frontend_url" env:"FRONTEND_URL"`
+	AdminUserIDs    string `yaml:"admin_user_ids" env:"ADMIN_USER_IDS"` // comma-separated
+	ShutdownTimeout string `yaml:"shutdown_timeout" env:"API_SHUTDOWN_TIMEOUT"`
+	// Rate limits are "<n>/<s|m|h>[,<burst>]", e.g. "60/m", or "off"
+	RateLimitIP   string `yaml:"rate_limit_ip" env:"API_RATE_LIMIT_IP"`
+	RateLimitFree string `yaml:"rate_limit_free" env:"API_RATE_LIMIT_FREE"`
+	RateLimitPro  string `yaml:"rate_limit_pro" env:"API_RATE_LIMIT_PRO"`
+	TrustProxy    bool   `yaml:"trust_proxy" env:"API_TRUST_PROXY"` // take client IPs from X-Forwarded-For
+}
+
+// SuiteWatchBot holds the watchbot schedules.
+type SuiteWatchBot struct {
+	CheckSchedule     string `yaml:"check_schedule" env:"WATCHBOT_CHECK_SCHEDULE"` // cron or "@every <duration>"
+	CheckInterval     string `yaml:"check_interval" env:"WATCHBOT_CHECK_INTERVAL"` // default time between checks of a page
+	BenchmarkConfig   string `yaml:"benchmark_config" env:"BENCHMARK_CONFIG"`
+	BenchmarkInterval string `yaml:"benchmark_interval" env:"BENCHMARK_INTERVAL"`
+	BackupInterval    string `yaml:"backup_interval" env:"WATCHBOT_BACKUP_INTERVAL"`
+	BackupDir         string `yaml:"backup_dir" env:"WATCHBOT_BACKUP_DIR"`
+	BackupKeep        int    `yaml:"backup_keep" env:"WATCHBOT_BACKUP_KEEP"`
+	DrainTimeout      string `yaml:"drain_timeout" env:"WATCHBOT_DRAIN_TIMEOUT"`
+}
+
+// SuiteNewsBot configures newsbot and when "newsbot serve" runs the digest.
+type SuiteNewsBot struct {
+	DB       string `yaml:"db" env:"NEWSBOT_DB"`
+	Schedule string `yaml:"schedule" env:"NEWSBOT_SCHEDULE"` // cron or "@every <duration>"
+	Sources  string `yaml:"sources" env:"NEWSBOT_SOURCES"`   // sources.yaml path
+	// DedupThreshold is the embedding similarity at which articles are
+	// merged as one story, 0 to 1; 0 turns merging off
+	DedupThreshold string `yaml:"dedup_threshold" env:"NEWSBOT_DEDUP_THRESHOLD"`
+}
+
+// SuiteTelemetry configures where opted-in anonymous usage reports go.
+// Opting in is a per-user choice made with "telemetry enable".
+type SuiteTelemetry struct {
+	Endpoint string `yaml:"endpoint" env:"DEVKIT_TELEMETRY_ENDPOINT"`
+}
+
+// SuiteMetrics configures where Prometheus metrics are served.
+type SuiteMetrics struct {
+	Addr  string `yaml:"addr" env:"METRICS_ADDR"`   // listener for watchbot and newsbot serve
+	Token string `yaml:"token" env:"METRICS_TOKEN"` // bearer token for the API's /metrics
+}
+
+// SuitePath returns the suite config path from SuiteFileEnv, or
+// DefaultSuiteFile.
+func SuitePath() string {
+	if path := os.Getenv(SuiteFileEnv); path != "" {
+		return path
+	}
+	return DefaultSuiteFile
+}
+
+// LoadSuite reads a suite config file. Unlike Load it rejects unknown keys,
+// so typos are reported instead of silently ignored. Env vars override the
+// file's values.
+func LoadSuite(path string) (*Suite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config file %s: %w", path, err)
+	}
+
+	var s Suite
+	dec := yaml.NewDecoder(strings.NewReader(os.ExpandEnv(string(data))))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	applyEnvOverrides(&s)
+	return &s, nil
+}
+
+// Validate checks the config against the schema and returns every problem
+// found, joined.
+func (s *Suite) Validate() error {
+	var errs []error
+	fail := func(field, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	switch llm.Provider(s.LLM.Provider) {
+	case "", llm.OpenAI, llm.Gemini, llm.Claude, llm.Ollama, llm.MiniMax:
+	case llm.Azure:
+		if s.LLM.BaseURL == "" {
+			fail("llm.base_url", "required for the azure provider")
+		}
+	default:
+		fail("llm.provider", "unknown provider %q", s.LLM.Provider)
+	}
+	if s.LLM.BaseURL != "" {
+		if u, err := url.Parse(s.LLM.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fail("llm.base_url", "must be an http(s) URL, got %q", s.LLM.BaseURL)
+		}
+	}
+	switch s.LLM.Cache {
+	case "", "off", "memory", "db":
+	default:
+		fail("llm.cache", "must be off, memory or db, got %q", s.LLM.Cache)
+	}
+
+	if s.SMTP.Port != "" {
+		if port, err := strconv.Atoi(s.SMTP.Port); err != nil || port <= 0 || port > 65535 {
+			fail("smtp.port", "invalid port %q", s.SMTP.Port)
+		}
+	}
+	addrs := []struct{ field, value string }{
+		{"smtp.from", s.SMTP.From},
+		{"smtp.unsubscribe_mailto", s.SMTP.UnsubscribeMailto},
+	}
+	for _, a := range addrs {
+		if a.value == "" {
+			continue
+		}
+		if _, err := mail.ParseAddress(a.value); err != nil {
+			fail(a.field, "invalid email address %q", a.value)
+		}
+	}
+	if s.SMTP.Password != "" && s.SMTP.Host == "" {
+		fail("smtp.host", "required when smtp.password is set")
+	}
+
+	if (s.Telegram.BotToken == "") != (s.Telegram.ChannelID == "") {
+		fail("telegram", "bot_token and channel_id must be set together")
+	}
+	if s.Discord.WebhookURL != "" {
+		if u, err := url.Parse(s.Discord.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fail("discord.webhook_url", "must be an http(s) URL, got %q", s.Discord.WebhookURL)
+		}
+	}
+	switch s.WeChatWork.MsgType {
+	case "", "markdown", "news":
+	default:
+		fail("wechatwork.msg_type", "must be markdown or news, got %q", s.WeChatWork.MsgType)
+	}
+
+	switch s.Database.Driver {
+	case "", "sqlite", "postgres":
+	default:
+		fail("database.driver", "must be sqlite or postgres, got %q", s.Database.Driver)
+	}
+	if s.Database.MaxOpenConns < 0 {
+		fail("database.max_open_conns", "must not be negative")
+	}
+	if s.Database.MaxIdleConns < 0 {
+		fail("database.max_idle_conns", "must not be negative")
+	}
+
+	if s.API.Port != "" {
+		if port, err := strconv.Atoi(s.API.Port); err != nil || port <= 0 || port > 65535 {
+			fail("api.port", "invalid port %q", s.API.Port)
+		}
+	}
+	rateLimits := []struct{ field, value string }{
+		{"api.rate_limit_ip", s.API.RateLimitIP},
+		{"api.rate_limit_free", s.API.RateLimitFree},
+		{"api.rate_limit_pro", s.API.RateLimitPro},
+	}
+	for _, rl := range rateLimits {
+		if rl.value == "" {
+			continue
+		}
+		if _, err := ratelimit.ParseLimit(rl.value); err != nil {
+			fail(rl.field, "invalid rate limit %q (e.g. 60/m, 10/s,50 or off)", rl.value)
+		}
+	}
+	if s.Telemetry.Endpoint != "" {
+		if u, err := url.Parse(s.Telemetry.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fail("telemetry.endpoint", "must be an http(s) URL, got %q", s.Telemetry.Endpoint)
+		}
+	}
+	if s.NewsBot.DedupThreshold != "" {
+		if t, err := strconv.ParseFloat(s.NewsBot.DedupThreshold, 64); err != nil || t < 0 || t > 1 {
+			fail("newsbot.dedup_threshold", "must be a number from 0 to 1, got %q", s.NewsBot.DedupThreshold)
+		}
+	}
+	if s.WatchBot.BackupKeep < 0 {
+		fail("watchbot.backup_keep", "must not be negative")
+	}
+
+	durations := []struct{ field, value string }{
+		{"llm.cache_ttl", s.LLM.CacheTTL},
+		{"database.conn_max_lifetime", s.Database.ConnMaxLifetime},
+		{"database.busy_timeout", s.Database.BusyTimeout},
+		{"watchbot.check_interval", s.WatchBot.CheckInterval},
+		{"watchbot.benchmark_interval", s.WatchBot.BenchmarkInterval},
+		{"watchbot.backup_interval", s.WatchBot.BackupInterval},
+		{"watchbot.drain_timeout", s.WatchBot.DrainTimeout},
+		{"api.shutdown_timeout", s.API.ShutdownTimeout},
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+		if v, err := time.ParseDuration(d.value); err != nil || v < 0 {
+			fail(d.field, "invalid duration %q (e.g. 30m, 6h)", d.value)
+		}
+	}
+
+	schedules := []struct{ field, value string }{
+		{"watchbot.check_schedule", s.WatchBot.CheckSchedule},
+		{"newsbot.schedule", s.NewsBot.Schedule},
+	}
+	for _, sc := range schedules {
+		if sc.value == "" {
+			continue
+		}
+		if _, err := jobs.ParseSpec(sc.value); err != nil {
+			fail(sc.field, "invalid schedule %q (e.g. \"0 8 * * *\", \"@every 6h\")", sc.value)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Export sets the env var of every configured field that is not already set
+// in the environment, so code reading env vars sees the file's values.
+func (s *Suite) Export() {
+	exportEnv(reflect.ValueOf(s).Elem())
+}
+
+func exportEnv(val reflect.Value) {
+	t := val.Type()
+	for i := 0; i < t.NumField(); i++ {
+		fieldVal := val.Field(i)
+		if fieldVal.Kind() == reflect.Struct {
+			exportEnv(fieldVal)
+			continue
+		}
+		key := t.Field(i).Tag.Get("env")
+		if key == "" || fieldVal.IsZero() {
+			continue
+		}
+		if _, ok := os.LookupEnv(key); ok {
+			continue
+		}
+		os.Setenv(key, fmt.Sprint(fieldVal.Interface()))
+	}
+}
+
+// LoadSuiteEnv loads and validates the suite config at SuitePath and exports
+// it to the environment. A missing default file is not an error; a missing
+// file named by SuiteFileEnv is. Returns the path loaded, or "".
+func LoadSuiteEnv() (string, error) {
+	path := SuitePath()
+	if _, err := os.Stat(path); os.IsNotExist(err) && os.Getenv(SuiteFileEnv) == "" {
+		return "", nil
+	}
+	s, err := LoadSuite(path)
+	if err != nil {
+		return "", err
+	}
+	if err := s.Validate(); err != nil {
+		return "", fmt.Errorf("invalid config file %s:\n%w", path, err)
+	}
+	s.Export()
+	return path, nil
+}
+
+// ValidateSuite loads a suite config file and validates it, as seen with the
+// current env var overrides applied.
+func ValidateSuite(path string) error {
+	s, err := LoadSuite(path)
+	if err != nil {
+		return err
+	}
+	return s.Validate()
+}
@@ -13,12 +13,32 @@ type DiffResult struct {
 	Removed    []string `json:"removed"`
 	Unified    string   `json:"unified"`
 	Stats      Stats    `json:"stats"`
+
+	// Changes pairs removed lines with the added lines that edited them,
+	// with the words that changed. Lines without a close counterpart
+	// appear only in Added or Removed.
+	Changes []LineChange `json:"changes,omitempty"`
+
+	// Structure lists changes to headings, tables and lists, from
+	// StructureDiff. TextDiff leaves it empty; callers that keep page
+	// outlines fill it in.
+	Structure []StructuralChange `json:"structure,omitempty"`
+
+	// Moved lists text found in both versions at a different place, from
+	// SemanticDiff. TextDiff reports moved lines as removed and added.
+	Moved []Move `json:"moved,omitempty"`
+
+	// JSON lists the changed paths of a JSONDiff; the lines above spell
+	// them out as "path: value".
+	JSON []JSONChange `json:"json,omitempty"`
 }
 
 // Stats holds counts of changes.
 type Stats struct {
 	Additions int `json:"additions"`
 	Deletions int `json:"deletions"`
+	// Moves counts text moved to another section, from SemanticDiff.
+	Moves int `json:"moves,omitempty"`
 }
 
 // TextDiff computes a line-by-line diff between old and new text.
@@ -66,6 +86,7 @@ func TextDiff(oldText, newText string) DiffResult {
 		Added:      added,
 		Removed:    removed,
 		Unified:    sb.String(),
+		Changes:    pairLines(removed, added),
 		Stats: Stats{
 			Additions: len(added),
 			Deletions: len(removed),
@@ -78,5 +99,55 @@ func (d DiffResult) Summary() string {
 	if !d.HasChanges {
 		return "No changes detected"
 	}
+	if d.Stats.Moves > 0 {
+		return fmt.Sprintf("%d additions, %d deletions, %d moves", d.Stats.Additions, d.Stats.Deletions, d.Stats.Moves)
+	}
 	return fmt.Sprintf("%d additions, %d deletions", d.Stats.Additions, d.Stats.Deletions)
 }
+
+// UnifiedStats counts added and removed lines in a stored unified diff.
+func UnifiedStats(unified string) Stats {
+	var st Stats
+	for _, line := range strings.Split(unified, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			continue
+		case strings.HasPrefix(line, "+"):
+			st.Additions++
+		case strings.HasPrefix(line, "-"):
+			st.Deletions++
+		}
+	}
+	return st
+}
+
+// ParseUnified rebuilds a DiffResult from a unified diff stored by TextDiff,
+// pairing edited lines as TextDiff does, so stored diffs can be rendered
+// with RenderHTML.
+func ParseUnified(unified string) DiffResult {
+	var added, removed []string
+	for _, line := range strings.Split(unified, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			continue
+		case strings.HasPrefix(line, "+"):
+			added = append(added, line[1:])
+		case strings.HasPrefix(line, "-"):
+			removed = append(removed, line[1:])
+		}
+	}
+	if len(added) == 0 && len(removed) == 0 {
+		return DiffResult{HasChanges: false}
+	}
+	return DiffResult{
+		HasChanges: true,
+		Added:      added,
+		Removed:    removed,
+		Unified:    unified,
+		Changes:    pairLines(removed, added),
+		Stats: Stats{
+			Additions: len(added),
+			Deletions: len(removed),
+		},
+	}
+}
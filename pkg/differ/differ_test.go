@@ -1,6 +1,14 @@
 package differ
 
-import "testing"
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+	"testing"
+)
 
 func TestTextDiff_NoChanges(t *testing.T) {
 	result := TextDiff("hello\nworld", "hello\nworld")
@@ -40,3 +48,294 @@ func TestTextDiff_AllNew(t *testing.T) {
 		t.Fatal("expected added lines")
 	}
 }
+
+func TestUnifiedStats(t *testing.T) {
+	result := TextDiff("a\nb\nc", "a\nB\nc\nd")
+	stats := UnifiedStats(result.Unified)
+	if stats != result.Stats {
+		t.Fatalf("expected %+v, got %+v", result.Stats, stats)
+	}
+}
+
+func TestWordDiff(t *testing.T) {
+	segs := WordDiff("Pro plan $49/month", "Pro plan $59/month")
+	want := []Segment{
+		{OpEqual, "Pro plan $"},
+		{OpDelete, "49"},
+		{OpInsert, "59"},
+		{OpEqual, "/month"},
+	}
+	if len(segs) != len(want) {
+		t.Fatalf("got %+v, want %+v", segs, want)
+	}
+	for i := range want {
+		if segs[i] != want[i] {
+			t.Fatalf("segment %d: got %+v, want %+v", i, segs[i], want[i])
+		}
+	}
+
+	change := LineChange{Segments: WordDiff("标准版 每月 99 元", "专业版 每月 129 元")}
+	if got := change.Inline(); got != "[-标准-]{+专业+}版 每月 [-99-]{+129+} 元" {
+		t.Fatalf("unexpected inline diff: %s", got)
+	}
+	if got := (LineChange{Segments: CharDiff("v1.2.3", "v1.2.4")}).Inline(); got != "v1.2.[-3-]{+4+}" {
+		t.Fatalf("unexpected char diff: %s", got)
+	}
+}
+
+func TestTextDiff_Changes(t *testing.T) {
+	old := "Pricing\nStarter $19/month\nPro $49/month"
+	new := "Pricing\nStarter $19/month\nPro $59/month\nEnterprise: contact sales"
+	result := TextDiff(old, new)
+
+	if len(result.Changes) != 1 {
+		t.Fatalf("expected one paired change, got %+v", result.Changes)
+	}
+	c := result.Changes[0]
+	if c.Old != "Pro $49/month" || c.New != "Pro $59/month" {
+		t.Fatalf("wrong pairing: %+v", c)
+	}
+	if got := c.Inline(); got != "Pro $[-49-]{+59+}/month" {
+		t.Fatalf("unexpected inline diff: %s", got)
+	}
+}
+
+func TestStructureDiff(t *testing.T) {
+	oldHTML := `<h2>Pricing</h2>
+<table><tr><th>Plan</th><th>Price</th></tr>
+<tr><td>Starter</td><td>$19</td></tr>
+<tr><td>Pro</td><td>$49</td></tr></table>
+<h2>Features</h2><ul><li>API access</li><li>Email support</li></ul>
+<footer><ul><li>Privacy</li></ul></footer>`
+	newHTML := `<h2>Plans &amp; Pricing</h2>
+<table><tr><th>Plan</th><th>Price</th></tr>
+<tr><td>Starter</td><td>$19</td></tr>
+<tr><td>Pro</td><td>$59</td></tr>
+<tr><td>Enterprise</td><td>Contact sales</td></tr></table>
+<h2>Features</h2><ul><li>API access</li><li>SSO</li><li>Email support</li></ul>
+<footer><ul><li>Terms</li></ul></footer>`
+
+	oldBlocks, err := Outline(oldHTML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newBlocks, err := Outline(newHTML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, c := range StructureDiff(oldBlocks, newBlocks) {
+		got = append(got, c.Describe())
+	}
+	want := []string{
+		`Heading changed: "Pricing" → "Plans & Pricing"`,
+		`Row changed in table "Plans & Pricing": Pro | $49 → Pro | $59`,
+		`Row added to table "Plans & Pricing": Enterprise | Contact sales`,
+		`Item added to list "Features": SSO`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	if RenderHTML(TextDiff("same", "same"), nil) != "" {
+		t.Fatal("expected no output without changes")
+	}
+	diff := ParseUnified("--- old\n+++ new\n-Pro $49/month\n-<b>Beta</b>\n+Pro $59/month\n+Enterprise\n")
+	if len(diff.Changes) != 1 || diff.Stats != (Stats{Additions: 2, Deletions: 2}) {
+		t.Fatalf("unexpected parsed diff: %+v", diff)
+	}
+
+	out := RenderHTML(diff, nil)
+	for _, want := range []string{"<table", ">49</del>", ">59</ins>", "&lt;b&gt;Beta&lt;/b&gt;", "Enterprise"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("side-by-side output missing %q: %s", want, out)
+		}
+	}
+	if strings.Contains(out, "<b>") {
+		t.Fatalf("expected escaped text: %s", out)
+	}
+
+	out = RenderHTML(diff, &RenderOptions{Layout: LayoutInline, MaxRows: 2})
+	if strings.Contains(out, "<table") || !strings.Contains(out, "~ Pro $<del") {
+		t.Fatalf("unexpected inline output: %s", out)
+	}
+	if strings.Contains(out, "Enterprise") || !strings.Contains(out, "1 more lines") {
+		t.Fatalf("expected rows capped at 2: %s", out)
+	}
+}
+
+func TestSemanticDiff(t *testing.T) {
+	oldText := `# Pricing
+## Pro
+Pro costs $49 per month. Includes   API access.
+SSO included.
+## Enterprise
+Contact sales for a quote.
+- Priority support
+- Audit logs`
+	newText := `# Pricing
+
+## Pro
+Pro costs $59 per month.
+Includes API access.
+## Enterprise
+Contact sales for a quote. SSO included.
+- Audit logs
+- Priority support`
+
+	d := SemanticDiff(oldText, newText)
+	if !d.HasChanges {
+		t.Fatal("expected changes")
+	}
+	if len(d.Removed) != 1 || d.Removed[0] != "Pro costs $49 per month." {
+		t.Errorf("removed = %q", d.Removed)
+	}
+	if len(d.Added) != 1 || d.Added[0] != "Pro costs $59 per month." {
+		t.Errorf("added = %q", d.Added)
+	}
+	if len(d.Changes) != 1 || d.Changes[0].Inline() != "Pro costs $[-49-]{+59+} per month." {
+		t.Errorf("changes = %+v", d.Changes)
+	}
+
+	var got []string
+	for _, m := range d.Moved {
+		got = append(got, m.Describe())
+	}
+	want := []string{
+		`Moved from "Pro" to "Enterprise": SSO included.`,
+		`Reordered within "Enterprise": - Priority support`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("moves:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if d.Stats.Moves != 1 {
+		t.Errorf("moves = %d, want 1", d.Stats.Moves)
+	}
+}
+
+func TestSemanticDiff_IgnoresNoise(t *testing.T) {
+	oldText := "## Features\nFast builds.  Free tier.\n- A\n- B"
+	newText := "## Features\n\nFast builds.\nFree tier.\n- B\n- A\n"
+	d := SemanticDiff(oldText, newText)
+	if d.HasChanges {
+		t.Errorf("expected no changes, got %s", d.Unified)
+	}
+	if len(d.Moved) != 1 {
+		t.Errorf("expected the reordered item in Moved, got %+v", d.Moved)
+	}
+
+	// A renamed heading is a change, but its sentences did not move
+	d = SemanticDiff("## Plans\nPro costs $49.", "## Pricing\nPro costs $49.")
+	if !d.HasChanges || len(d.Moved) != 0 || len(d.Added) != 1 || d.Added[0] != "## Pricing" {
+		t.Errorf("renamed heading: %+v", d)
+	}
+}
+
+func TestJSONDiff(t *testing.T) {
+	old := `{
+  "object": "list",
+  "data": [
+    {"id": "gpt-4o", "price": {"input": 2.5, "output": 10}},
+    {"id": "gpt-4", "price": {"input": 30, "output": 60}}
+  ],
+  "regions": ["us", "eu"]
+}`
+	// Keys reordered, one model inserted first, one dropped, one repriced
+	next := `{"regions": ["eu", "us", "apac"], "data": [
+  {"id": "o3", "price": {"input": 2, "output": 8}},
+  {"price": {"output": 8, "input": 2.5}, "id": "gpt-4o"}
+], "object": "list"}`
+
+	result, err := JSONDiff([]byte(old), []byte(next))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range result.JSON {
+		got = append(got, c.Describe())
+	}
+	want := []string{
+		`Removed $.data[id=gpt-4]: {"id":"gpt-4","price":{"input":30,"output":60}}`,
+		`Added $.data[id=o3]: {"id":"o3","price":{"input":2,"output":8}}`,
+		`$.data[id=gpt-4o].price.output: 10 → 8`,
+		`Added $.regions[2]: "apac"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("changes:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if result.Stats.Additions != 3 || result.Stats.Deletions != 2 {
+		t.Errorf("stats = %+v", result.Stats)
+	}
+	if len(result.Changes) != 1 || result.Changes[0].Inline() != "$.data[id=gpt-4o].price.output: [-10-]{+8+}" {
+		t.Errorf("changes = %+v", result.Changes)
+	}
+	if !strings.Contains(result.Unified, "-$.data[id=gpt-4o].price.output: 10\n+$.data[id=gpt-4o].price.output: 8\n") {
+		t.Errorf("unified:\n%s", result.Unified)
+	}
+	if st := UnifiedStats(result.Unified); st != result.Stats {
+		t.Errorf("UnifiedStats = %+v, want %+v", st, result.Stats)
+	}
+
+	// Formatting and key order alone are not changes
+	if result, err := JSONDiff([]byte(`{"a": 1, "b": [1, 2]}`), []byte(`{"b":[2,1],"a":1}`)); err != nil || result.HasChanges {
+		t.Errorf("expected no changes, got %+v, %v", result.JSON, err)
+	}
+	if _, err := JSONDiff([]byte(`{"a": 1}`), []byte(`<html>`)); err == nil {
+		t.Error("expected an error for a document that is not JSON")
+	}
+}
+
+func TestVisualDiff(t *testing.T) {
+	page := func(h int, banner bool) []byte {
+		img := image.NewRGBA(image.Rect(0, 0, 320, h))
+		draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+		if banner {
+			draw.Draw(img, image.Rect(0, 160, 320, 200), image.Black, image.Point{}, draw.Src)
+		}
+		// one-pixel noise, as from anti-aliasing, must not count
+		img.Set(5, 5, color.Gray{Y: 200})
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+
+	same, err := VisualDiff(page(320, false), page(320, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if same.Changed(0.001) || len(same.Regions) != 0 {
+		t.Fatalf("expected no change, got %+v", same)
+	}
+
+	v, err := VisualDiff(page(320, false), page(320, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// rows 160-200 fall in cells 10-12: 3 of 20 rows
+	if v.Ratio != 3.0/20 || len(v.Regions) != 1 || v.Regions[0] != image.Rect(0, 160, 320, 208) {
+		t.Fatalf("unexpected result %+v", v)
+	}
+	if !v.Changed(0.1) || v.Changed(0.2) {
+		t.Errorf("unexpected thresholds for ratio %f", v.Ratio)
+	}
+	if got := v.Summary(); got != "15.0% of the page changed, at 160-208px" {
+		t.Errorf("unexpected summary %q", got)
+	}
+
+	grown, err := VisualDiff(page(320, false), page(480, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if grown.Ratio != 1.0/3 || len(grown.Regions) != 1 || grown.Regions[0] != image.Rect(0, 320, 320, 480) {
+		t.Errorf("expected the added height to count as changed, got %+v", grown)
+	}
+
+	if _, err := VisualDiff([]byte("not a png"), page(320, false)); err == nil {
+		t.Error("expected a decode error")
+	}
+}
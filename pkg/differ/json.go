@@ -0,0 +1,279 @@
+package differ
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// JSONChange is a value added, removed or changed at a path of a JSON
+// document, such as $.plans[name=Pro].price. Old and New are compact JSON.
+type JSONChange struct {
+	Path   string `json:"path"`
+	Action string `json:"action"`
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+}
+
+// Describe renders the change as one line, e.g. "$.plans[name=Pro].price:
+// 49 → 59".
+func (c JSONChange) Describe() string {
+	switch c.Action {
+	case ActionAdded:
+		return fmt.Sprintf("Added %s: %s", c.Path, clipJSON(c.New))
+	case ActionRemoved:
+		return fmt.Sprintf("Removed %s: %s", c.Path, clipJSON(c.Old))
+	default:
+		return fmt.Sprintf("%s: %s → %s", c.Path, clipJSON(c.Old), clipJSON(c.New))
+	}
+}
+
+// maxJSONValueRunes is how much of a value Describe and the unified diff
+// show; an added object can be a whole API record.
+const maxJSONValueRunes = 300
+
+// identityKeys are the fields, in order of preference, that arrays of
+// objects are matched by, so inserting an element does not shift the rest.
+var identityKeys = []string{"id", "key", "name", "slug", "model"}
+
+var plainKey = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$-]*$`)
+
+// JSONDiff compares two JSON documents value by value and reports each
+// added, removed and changed path, which is far less noisy than a line diff
+// of pretty-printed JSON. Object keys are compared regardless of order.
+// Arrays of objects that all carry a unique id, key, name, slug or model
+// are matched by it; other arrays are matched by value, and elements left
+// over on both sides are compared in order. Reordering an array is not a
+// change.
+//
+// Unified lists "-path: old" and "+path: new" lines and Changes pairs them
+// for changed values, so the result renders like a TextDiff.
+func JSONDiff(oldJSON, newJSON []byte) (DiffResult, error) {
+	oldValue, err := decodeJSON(oldJSON)
+	if err != nil {
+		return DiffResult{}, fmt.Errorf("old document: %w", err)
+	}
+	newValue, err := decodeJSON(newJSON)
+	if err != nil {
+		return DiffResult{}, fmt.Errorf("new document: %w", err)
+	}
+
+	var changes []JSONChange
+	diffJSONValues("$", oldValue, newValue, &changes)
+	if len(changes) == 0 {
+		return DiffResult{HasChanges: false}, nil
+	}
+
+	result := DiffResult{HasChanges: true, JSON: changes}
+	var sb strings.Builder
+	sb.WriteString("--- old\n+++ new\n")
+	for _, c := range changes {
+		removed := c.Path + ": " + clipJSON(c.Old)
+		added := c.Path + ": " + clipJSON(c.New)
+		switch c.Action {
+		case ActionAdded:
+			result.Added = append(result.Added, added)
+			sb.WriteString("+" + added + "\n")
+		case ActionRemoved:
+			result.Removed = append(result.Removed, removed)
+			sb.WriteString("-" + removed + "\n")
+		default:
+			result.Removed = append(result.Removed, removed)
+			result.Added = append(result.Added, added)
+			result.Changes = append(result.Changes, LineChange{Old: removed, New: added, Segments: WordDiff(removed, added)})
+			sb.WriteString("-" + removed + "\n+" + added + "\n")
+		}
+	}
+	result.Unified = sb.String()
+	result.Stats = Stats{Additions: len(result.Added), Deletions: len(result.Removed)}
+	return result, nil
+}
+
+// decodeJSON decodes a single JSON value, keeping numbers as json.Number.
+func decodeJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return v, nil
+}
+
+func diffJSONValues(path string, a, b any, changes *[]JSONChange) {
+	switch av := a.(type) {
+	case map[string]any:
+		if bv, ok := b.(map[string]any); ok {
+			diffJSONObjects(path, av, bv, changes)
+			return
+		}
+	case []any:
+		if bv, ok := b.([]any); ok {
+			diffJSONArrays(path, av, bv, changes)
+			return
+		}
+	}
+	if oldJSON, newJSON := compactJSON(a), compactJSON(b); oldJSON != newJSON {
+		*changes = append(*changes, JSONChange{Path: path, Action: ActionChanged, Old: oldJSON, New: newJSON})
+	}
+}
+
+func diffJSONObjects(path string, a, b map[string]any, changes *[]JSONChange) {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		av, inOld := a[k]
+		bv, inNew := b[k]
+		p := path + keySegment(k)
+		switch {
+		case !inNew:
+			*changes = append(*changes, JSONChange{Path: p, Action: ActionRemoved, Old: compactJSON(av)})
+		case !inOld:
+			*changes = append(*changes, JSONChange{Path: p, Action: ActionAdded, New: compactJSON(bv)})
+		default:
+			diffJSONValues(p, av, bv, changes)
+		}
+	}
+}
+
+func diffJSONArrays(path string, a, b []any, changes *[]JSONChange) {
+	if key := arrayIdentity(a, b); key != "" {
+		oldByID := make(map[string]any, len(a))
+		for _, v := range a {
+			oldByID[identityOf(v, key)] = v
+		}
+		newIDs := make(map[string]bool, len(b))
+		for _, v := range b {
+			newIDs[identityOf(v, key)] = true
+		}
+		for _, v := range a {
+			if id := identityOf(v, key); !newIDs[id] {
+				*changes = append(*changes, JSONChange{Path: path + "[" + key + "=" + id + "]", Action: ActionRemoved, Old: compactJSON(v)})
+			}
+		}
+		for _, v := range b {
+			id := identityOf(v, key)
+			p := path + "[" + key + "=" + id + "]"
+			if old, ok := oldByID[id]; ok {
+				diffJSONValues(p, old, v, changes)
+			} else {
+				*changes = append(*changes, JSONChange{Path: p, Action: ActionAdded, New: compactJSON(v)})
+			}
+		}
+		return
+	}
+
+	// Match equal elements, then compare what is left over in order
+	unmatched := make(map[string][]int)
+	for i, v := range a {
+		s := compactJSON(v)
+		unmatched[s] = append(unmatched[s], i)
+	}
+	var added []int
+	for j, v := range b {
+		s := compactJSON(v)
+		if idx := unmatched[s]; len(idx) > 0 {
+			unmatched[s] = idx[1:]
+		} else {
+			added = append(added, j)
+		}
+	}
+	var removed []int
+	for _, idx := range unmatched {
+		removed = append(removed, idx...)
+	}
+	sort.Ints(removed)
+
+	paired := min(len(removed), len(added))
+	for k := 0; k < paired; k++ {
+		diffJSONValues(path+"["+strconv.Itoa(added[k])+"]", a[removed[k]], b[added[k]], changes)
+	}
+	for _, i := range removed[paired:] {
+		*changes = append(*changes, JSONChange{Path: path + "[" + strconv.Itoa(i) + "]", Action: ActionRemoved, Old: compactJSON(a[i])})
+	}
+	for _, j := range added[paired:] {
+		*changes = append(*changes, JSONChange{Path: path + "[" + strconv.Itoa(j) + "]", Action: ActionAdded, New: compactJSON(b[j])})
+	}
+}
+
+// arrayIdentity returns the identity key every element of both arrays is
+// an object with, unique within each array, or "" if there is none.
+func arrayIdentity(a, b []any) string {
+	if len(a) == 0 || len(b) == 0 {
+		return ""
+	}
+	for _, key := range identityKeys {
+		if uniqueIdentity(a, key) && uniqueIdentity(b, key) {
+			return key
+		}
+	}
+	return ""
+}
+
+func uniqueIdentity(values []any, key string) bool {
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		id := identityOf(v, key)
+		if id == "" || seen[id] {
+			return false
+		}
+		seen[id] = true
+	}
+	return true
+}
+
+// identityOf returns the string or number at key of an object, or "".
+func identityOf(v any, key string) string {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return ""
+	}
+	switch id := obj[key].(type) {
+	case string:
+		return id
+	case json.Number:
+		return id.String()
+	}
+	return ""
+}
+
+func keySegment(key string) string {
+	if plainKey.MatchString(key) {
+		return "." + key
+	}
+	return "[" + strconv.Quote(key) + "]"
+}
+
+func compactJSON(v any) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func clipJSON(s string) string {
+	if utf8.RuneCountInString(s) <= maxJSONValueRunes {
+		return s
+	}
+	return string([]rune(s)[:maxJSONValueRunes]) + "…"
+}
@@ -0,0 +1,155 @@
+package differ
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Layout selects how RenderHTML lays out a diff.
+type Layout string
+
+const (
+	LayoutSideBySide Layout = "side-by-side" // old and new text in two columns
+	LayoutInline     Layout = "inline"       // one column, edits marked within each line
+)
+
+// RenderOptions configures RenderHTML.
+type RenderOptions struct {
+	Layout  Layout // defaults to LayoutSideBySide
+	MaxRows int    // rows rendered before a "… N more lines" note; 0 renders all
+}
+
+// Inline styles, so the output survives email clients that strip <style>.
+const (
+	styleContainer = "padding:8px 10px;background:#12121f;border-radius:6px;font-family:Menlo,Consolas,monospace;font-size:11px;line-height:1.5;color:#a0a0b8;"
+	styleCell      = "width:50%;vertical-align:top;padding:1px 6px;white-space:pre-wrap;word-break:break-all;"
+	styleLine      = "white-space:pre-wrap;word-break:break-all;"
+	styleRemoved   = "color:#ef9a9a;background:rgba(244,67,54,0.12);"
+	styleAdded     = "color:#81c784;background:rgba(76,175,80,0.12);"
+	styleDelWord   = "background:rgba(244,67,54,0.35);text-decoration:line-through;"
+	styleInsWord   = "background:rgba(76,175,80,0.35);text-decoration:none;"
+	styleMore      = "color:#707090;"
+)
+
+// RenderHTML renders a diff as a styled HTML fragment. Edited lines are
+// shown with the changed words highlighted; all text is escaped. opts may be
+// nil. Returns "" for a diff without changes.
+func RenderHTML(diff DiffResult, opts *RenderOptions) string {
+	if opts == nil {
+		opts = &RenderOptions{}
+	}
+	rows := renderRows(diff)
+	if len(rows) == 0 {
+		return ""
+	}
+	more := 0
+	if opts.MaxRows > 0 && len(rows) > opts.MaxRows {
+		rows, more = rows[:opts.MaxRows], len(rows)-opts.MaxRows
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`<div style="` + styleContainer + `">`)
+	if opts.Layout == LayoutInline {
+		for _, r := range rows {
+			writeInlineRow(&sb, r)
+		}
+	} else {
+		sb.WriteString(`<table role="presentation" width="100%" cellpadding="0" cellspacing="0" style="width:100%;border-collapse:collapse;table-layout:fixed;">`)
+		for _, r := range rows {
+			writeSideBySideRow(&sb, r)
+		}
+		sb.WriteString(`</table>`)
+	}
+	if more > 0 {
+		sb.WriteString(fmt.Sprintf(`<div style="%s">… %d more lines</div>`, styleMore, more))
+	}
+	sb.WriteString(`</div>`)
+	return sb.String()
+}
+
+// renderRow is a removed line, an added line, or an edit pairing the two.
+type renderRow struct {
+	old, new string
+	segments []Segment // set for edits
+}
+
+// renderRows orders a diff for display: removed lines in order, each edit
+// in place of its removed line, then the remaining added lines.
+func renderRows(diff DiffResult) []renderRow {
+	edits := make(map[string][]LineChange)
+	for _, c := range diff.Changes {
+		edits[c.Old] = append(edits[c.Old], c)
+	}
+	pairedNew := make(map[string]int)
+	var rows []renderRow
+	for _, line := range diff.Removed {
+		if cs := edits[line]; len(cs) > 0 {
+			edits[line] = cs[1:]
+			pairedNew[cs[0].New]++
+			rows = append(rows, renderRow{old: cs[0].Old, new: cs[0].New, segments: cs[0].Segments})
+			continue
+		}
+		rows = append(rows, renderRow{old: line})
+	}
+	for _, line := range diff.Added {
+		if pairedNew[line] > 0 {
+			pairedNew[line]--
+			continue
+		}
+		rows = append(rows, renderRow{new: line})
+	}
+	return rows
+}
+
+func writeSideBySideRow(sb *strings.Builder, r renderRow) {
+	sb.WriteString(`<tr>`)
+	switch {
+	case r.segments != nil:
+		writeCell(sb, styleRemoved, segmentsHTML(r.segments, OpDelete))
+		writeCell(sb, styleAdded, segmentsHTML(r.segments, OpInsert))
+	case r.new == "":
+		writeCell(sb, styleRemoved, html.EscapeString(r.old))
+		writeCell(sb, "", "")
+	default:
+		writeCell(sb, "", "")
+		writeCell(sb, styleAdded, html.EscapeString(r.new))
+	}
+	sb.WriteString(`</tr>`)
+}
+
+func writeCell(sb *strings.Builder, style, content string) {
+	sb.WriteString(`<td style="` + styleCell + style + `">` + content + `</td>`)
+}
+
+func writeInlineRow(sb *strings.Builder, r renderRow) {
+	switch {
+	case r.segments != nil:
+		sb.WriteString(`<div style="` + styleLine + `">~ ` + segmentsHTML(r.segments, "") + `</div>`)
+	case r.new == "":
+		sb.WriteString(`<div style="` + styleLine + styleRemoved + `">- ` + html.EscapeString(r.old) + `</div>`)
+	default:
+		sb.WriteString(`<div style="` + styleLine + styleAdded + `">+ ` + html.EscapeString(r.new) + `</div>`)
+	}
+}
+
+// segmentsHTML renders an edit's segments, highlighting deleted and inserted
+// words. With only set, it renders one side: OpDelete gives the old line,
+// OpInsert the new one.
+func segmentsHTML(segments []Segment, only Op) string {
+	var sb strings.Builder
+	for _, s := range segments {
+		text := html.EscapeString(s.Text)
+		switch {
+		case s.Op == OpEqual:
+			sb.WriteString(text)
+		case only != "" && s.Op != only:
+			// the other side's text
+		case s.Op == OpDelete:
+			sb.WriteString(`<del style="` + styleDelWord + `">` + text + `</del>`)
+		default:
+			sb.WriteString(`<ins style="` + styleInsWord + `">` + text + `</ins>`)
+		}
+	}
+	return sb.String()
+}
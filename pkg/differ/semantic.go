@@ -0,0 +1,260 @@
+package differ
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Move is a run of sentences found in both texts at a different place.
+type Move struct {
+	// Text is the moved sentences, joined with spaces.
+	Text string `json:"text"`
+	// FromSection and ToSection are the headings the text was under; they
+	// are equal when it was only reordered within its section.
+	FromSection string `json:"from_section,omitempty"`
+	ToSection   string `json:"to_section,omitempty"`
+}
+
+// Describe renders the move as one line of English, e.g.
+// `Moved from "Pro" to "Enterprise": SSO included.`
+func (m Move) Describe() string {
+	if m.FromSection == m.ToSection {
+		if m.FromSection == "" {
+			return "Reordered: " + m.Text
+		}
+		return fmt.Sprintf("Reordered within %q: %s", m.FromSection, m.Text)
+	}
+	return fmt.Sprintf("Moved from %q to %q: %s", m.FromSection, m.ToSection, m.Text)
+}
+
+// Crossed reports whether the text moved to another section, as opposed to
+// being reordered within one.
+func (m Move) Crossed() bool {
+	return m.FromSection != m.ToSection
+}
+
+// unit is a sentence or heading of a text, with whitespace collapsed.
+type unit struct {
+	text    string
+	section string // the heading above, or of, the unit
+	heading bool
+}
+
+// SemanticDiff compares two texts sentence by sentence rather than line by
+// line, so re-wrapped paragraphs and changed whitespace are not changes.
+// Headings in the "## Title" form ExtractText writes divide the text into
+// sections. Sentences found in both texts at a different place are reported
+// in Moved rather than as a removal and an addition; moving to another
+// section is a change, reordering within one is not.
+//
+// Added, Removed and Unified list whole sentences, and Changes pairs edited
+// sentences as TextDiff pairs lines, so the result renders like a TextDiff.
+func SemanticDiff(oldText, newText string) DiffResult {
+	if oldText == newText {
+		return DiffResult{HasChanges: false}
+	}
+	a, b := splitUnits(oldText), splitUnits(newText)
+
+	// Match equal sentences in order of occurrence; the rest were removed
+	// or added
+	pos := make(map[string][]int, len(b))
+	for j, u := range b {
+		pos[u.text] = append(pos[u.text], j)
+	}
+	matchedB := make([]bool, len(b))
+	var pairs [][2]int // indexes into a and b, in order of a
+	var removed, added []string
+	for i, u := range a {
+		if js := pos[u.text]; len(js) > 0 {
+			pairs = append(pairs, [2]int{i, js[0]})
+			matchedB[js[0]] = true
+			pos[u.text] = js[1:]
+		} else {
+			removed = append(removed, u.text)
+		}
+	}
+	for j, u := range b {
+		if !matchedB[j] {
+			added = append(added, u.text)
+		}
+	}
+
+	moved := findMoves(a, b, pairs)
+	crossed := 0
+	for _, m := range moved {
+		if m.Crossed() {
+			crossed++
+		}
+	}
+	if len(added) == 0 && len(removed) == 0 && crossed == 0 {
+		return DiffResult{HasChanges: false, Moved: moved}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("--- old\n+++ new\n")
+	for _, s := range removed {
+		sb.WriteString("-" + s + "\n")
+	}
+	for _, s := range added {
+		sb.WriteString("+" + s + "\n")
+	}
+	return DiffResult{
+		HasChanges: true,
+		Added:      added,
+		Removed:    removed,
+		Unified:    sb.String(),
+		Changes:    pairLines(removed, added),
+		Moved:      moved,
+		Stats: Stats{
+			Additions: len(added),
+			Deletions: len(removed),
+			Moves:     crossed,
+		},
+	}
+}
+
+// findMoves reports the matched sentences that are out of order, or under
+// a different section, grouped into runs that moved together. The longest
+// run of pairs in the same order in both texts stays put; the others moved.
+func findMoves(a, b []unit, pairs [][2]int) []Move {
+	inOrder := longestIncreasing(pairs)
+
+	// Sections present in both texts. A sentence under a renamed heading
+	// has a new section but did not move.
+	headings := func(us []unit) map[string]bool {
+		m := make(map[string]bool)
+		for _, u := range us {
+			if u.heading {
+				m[u.section] = true
+			}
+		}
+		return m
+	}
+	oldHeadings, newHeadings := headings(a), headings(b)
+	kept := func(section string) bool {
+		return section == "" || (oldHeadings[section] && newHeadings[section])
+	}
+
+	var movedPairs [][2]int
+	for k, p := range pairs {
+		from, to := a[p[0]].section, b[p[1]].section
+		crossed := from != to && kept(from) && kept(to)
+		if !inOrder[k] || crossed {
+			movedPairs = append(movedPairs, p)
+		}
+	}
+	sort.Slice(movedPairs, func(i, j int) bool { return movedPairs[i][1] < movedPairs[j][1] })
+
+	var moves []Move
+	var last [2]int
+	for k, p := range movedPairs {
+		from, to := a[p[0]].section, b[p[1]].section
+		if !kept(from) || !kept(to) {
+			from = to // under a renamed heading: reordered only
+		}
+		n := len(moves)
+		if k > 0 && p[0] == last[0]+1 && p[1] == last[1]+1 && moves[n-1].FromSection == from && moves[n-1].ToSection == to {
+			moves[n-1].Text += " " + b[p[1]].text
+		} else {
+			moves = append(moves, Move{Text: b[p[1]].text, FromSection: from, ToSection: to})
+		}
+		last = p
+	}
+	return moves
+}
+
+// longestIncreasing marks the pairs, sorted by their first index, that form
+// a longest run with increasing second indexes.
+func longestIncreasing(pairs [][2]int) []bool {
+	// tails[k] is the index of the pair ending the best run of length k+1
+	var tails []int
+	prev := make([]int, len(pairs))
+	for i, p := range pairs {
+		k := sort.Search(len(tails), func(k int) bool { return pairs[tails[k]][1] >= p[1] })
+		prev[i] = -1
+		if k > 0 {
+			prev[i] = tails[k-1]
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+	in := make([]bool, len(pairs))
+	if len(tails) > 0 {
+		for i := tails[len(tails)-1]; i >= 0; i = prev[i] {
+			in[i] = true
+		}
+	}
+	return in
+}
+
+// splitUnits splits text into headings and sentences. Blank lines, line
+// breaks within a paragraph and runs of whitespace are ignored.
+func splitUnits(text string) []unit {
+	var units []unit
+	section := ""
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			continue
+		}
+		if title, ok := headingTitle(line); ok {
+			section = title
+			units = append(units, unit{text: line, section: section, heading: true})
+			continue
+		}
+		for _, s := range splitSentences(line) {
+			units = append(units, unit{text: s, section: section})
+		}
+	}
+	return units
+}
+
+// headingTitle returns the title of a "## Title" heading line.
+func headingTitle(line string) (string, bool) {
+	title := strings.TrimLeft(line, "#")
+	if len(title) == len(line) || len(line)-len(title) > 6 || !strings.HasPrefix(title, " ") {
+		return "", false
+	}
+	title = strings.TrimSpace(title)
+	return title, title != ""
+}
+
+// splitSentences splits a line after sentence-ending punctuation. Latin
+// full stops end a sentence only before a space and a word that does not
+// start in lower case, so "$4.99" and "e.g. this" stay whole; CJK full stops
+// always end one.
+func splitSentences(line string) []string {
+	var out []string
+	start := 0
+	for i, r := range line {
+		end := -1
+		switch r {
+		case '。', '！', '？', '；':
+			end = i + utf8.RuneLen(r)
+		case '.', '!', '?':
+			rest := line[i+1:]
+			if strings.HasPrefix(rest, " ") {
+				next, _ := utf8.DecodeRuneInString(rest[1:])
+				if !unicode.IsLower(next) {
+					end = i + 1
+				}
+			}
+		}
+		if end > start {
+			if s := strings.TrimSpace(line[start:end]); s != "" {
+				out = append(out, s)
+			}
+			start = end
+		}
+	}
+	if s := strings.TrimSpace(line[start:]); s != "" {
+		out = append(out, s)
+	}
+	return out
+}
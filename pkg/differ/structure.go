@@ -0,0 +1,432 @@
+package differ
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// BlockKind is the kind of a structural element of a page.
+type BlockKind string
+
+const (
+	BlockHeading  BlockKind = "heading"
+	BlockTableRow BlockKind = "row"
+	BlockListItem BlockKind = "item"
+
+	// Whole tables and lists appear only in StructuralChange, when one is
+	// added or removed in full.
+	BlockTable BlockKind = "table"
+	BlockList  BlockKind = "list"
+)
+
+// Block is one heading, table row or list item of a page.
+type Block struct {
+	Kind BlockKind `json:"kind"`
+	// Section is the nearest heading above the block.
+	Section string `json:"section,omitempty"`
+	// Container names the table or list holding a row or item: its caption,
+	// or else its section, numbered when the section holds several.
+	Container string `json:"container,omitempty"`
+	// Text is the block's text with whitespace collapsed; table cells are
+	// joined with " | ".
+	Text string `json:"text"`
+}
+
+// Limits on outlines, which are stored with every snapshot.
+const (
+	maxOutlineBlocks = 5000
+	maxBlockRunes    = 300
+)
+
+// skipTags hold no content worth comparing. Navigation and footers change
+// with every site redesign and are left to the text diff.
+var skipTags = map[string]bool{
+	"head": true, "script": true, "style": true, "noscript": true, "template": true,
+	"svg": true, "nav": true, "footer": true,
+}
+
+// Outline extracts the headings, table rows and list items of an HTML page,
+// in document order.
+func Outline(rawHTML string) ([]Block, error) {
+	doc, err := html.Parse(strings.NewReader(rawHTML))
+	if err != nil {
+		return nil, fmt.Errorf("parse html: %w", err)
+	}
+	o := &outliner{labels: make(map[string]int)}
+	o.walk(doc)
+	return o.blocks, nil
+}
+
+type outliner struct {
+	blocks  []Block
+	section string
+	labels  map[string]int // containers per kind and label, for numbering
+}
+
+func (o *outliner) add(b Block) {
+	if len(o.blocks) < maxOutlineBlocks && b.Text != "" {
+		o.blocks = append(o.blocks, b)
+	}
+}
+
+func (o *outliner) walk(n *html.Node) {
+	if n.Type == html.ElementNode {
+		if skipTags[n.Data] {
+			return
+		}
+		switch n.Data {
+		case "h1", "h2", "h3", "h4", "h5", "h6":
+			o.section = blockText(n)
+			o.add(Block{Kind: BlockHeading, Text: o.section})
+			return
+		case "table":
+			o.table(n)
+			return
+		case "ul", "ol":
+			o.list(n)
+			return
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		o.walk(c)
+	}
+}
+
+// container returns the name of a new table or list labelled label.
+func (o *outliner) container(kind BlockKind, label string) string {
+	if label == "" {
+		label = o.section
+	}
+	if label == "" {
+		label = "untitled"
+	}
+	key := string(kind) + "\x00" + label
+	o.labels[key]++
+	if n := o.labels[key]; n > 1 {
+		return fmt.Sprintf("%s #%d", label, n)
+	}
+	return label
+}
+
+func (o *outliner) table(n *html.Node) {
+	var label string
+	if caption := findChild(n, "caption"); caption != nil {
+		label = blockText(caption)
+	}
+	name := o.container(BlockTable, label)
+	var rows func(*html.Node)
+	rows = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			switch c.Data {
+			case "thead", "tbody", "tfoot":
+				rows(c)
+			case "tr":
+				var cells []string
+				for td := c.FirstChild; td != nil; td = td.NextSibling {
+					if td.Type == html.ElementNode && (td.Data == "td" || td.Data == "th") {
+						cells = append(cells, blockText(td))
+					}
+				}
+				o.add(Block{Kind: BlockTableRow, Section: o.section, Container: name, Text: strings.Join(cells, " | ")})
+			}
+		}
+	}
+	rows(n)
+}
+
+func (o *outliner) list(n *html.Node) {
+	name := o.container(BlockList, "")
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.Data != "li" {
+			continue
+		}
+		// Nested lists become lists of their own, after their parent item
+		o.add(Block{Kind: BlockListItem, Section: o.section, Container: name, Text: blockText(c, "ul", "ol")})
+		for gc := c.FirstChild; gc != nil; gc = gc.NextSibling {
+			o.walk(gc)
+		}
+	}
+}
+
+// blockText returns the collapsed text of n, leaving out skipped elements
+// and any of the given tags, cut to maxBlockRunes.
+func blockText(n *html.Node, exclude ...string) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			sb.WriteString(n.Data)
+			sb.WriteByte(' ')
+			return
+		case html.ElementNode:
+			if skipTags[n.Data] {
+				return
+			}
+			for _, tag := range exclude {
+				if n.Data == tag {
+					return
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	text := strings.Join(strings.Fields(sb.String()), " ")
+	if r := []rune(text); len(r) > maxBlockRunes {
+		text = string(r[:maxBlockRunes]) + "…"
+	}
+	return text
+}
+
+func findChild(n *html.Node, tag string) *html.Node {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == tag {
+			return c
+		}
+	}
+	return nil
+}
+
+// Structural change actions.
+const (
+	ActionAdded   = "added"
+	ActionRemoved = "removed"
+	ActionChanged = "changed"
+)
+
+// StructuralChange is a change to a page's structure, such as a row added
+// to a pricing table or a renamed heading.
+type StructuralChange struct {
+	Kind      BlockKind `json:"kind"`
+	Action    string    `json:"action"`
+	Section   string    `json:"section,omitempty"`
+	Container string    `json:"container,omitempty"`
+	Old       string    `json:"old,omitempty"`
+	New       string    `json:"new,omitempty"`
+	// Count is the number of rows or items of a whole table or list.
+	Count int `json:"count,omitempty"`
+}
+
+// Describe renders the change as one line of English, e.g.
+// `Row added to table "Pricing": Enterprise | $99`.
+func (c StructuralChange) Describe() string {
+	switch c.Kind {
+	case BlockHeading:
+		if c.Action == ActionChanged {
+			return fmt.Sprintf("Heading changed: %q → %q", c.Old, c.New)
+		}
+		if c.Action == ActionRemoved {
+			return fmt.Sprintf("Heading removed: %q", c.Old)
+		}
+		return fmt.Sprintf("Heading added: %q", c.New)
+	case BlockTable, BlockList:
+		return fmt.Sprintf("%s %q %s (%d %s)", kindNoun(c.Kind), c.Container, c.Action, c.Count, pluralNoun(c.Kind, c.Count))
+	}
+	noun := "Item"
+	container := fmt.Sprintf("list %q", c.Container)
+	if c.Kind == BlockTableRow {
+		noun = "Row"
+		container = fmt.Sprintf("table %q", c.Container)
+	}
+	switch c.Action {
+	case ActionAdded:
+		return fmt.Sprintf("%s added to %s: %s", noun, container, c.New)
+	case ActionRemoved:
+		return fmt.Sprintf("%s removed from %s: %s", noun, container, c.Old)
+	default:
+		return fmt.Sprintf("%s changed in %s: %s → %s", noun, container, c.Old, c.New)
+	}
+}
+
+func kindNoun(kind BlockKind) string {
+	if kind == BlockTable {
+		return "Table"
+	}
+	return "List"
+}
+
+func pluralNoun(kind BlockKind, n int) string {
+	noun := "item"
+	if kind == BlockTable {
+		noun = "row"
+	}
+	if n != 1 {
+		noun += "s"
+	}
+	return noun
+}
+
+// minContainerOverlap is the share of rows or items an unmatched old table
+// or list must share with a new one to count as the same, renamed one.
+const minContainerOverlap = 0.5
+
+// StructureDiff compares two outlines. Headings are compared as a whole;
+// tables and lists are matched by name, or failing that by content, and
+// compared row by row. Edited rows and items are reported as changed.
+func StructureDiff(oldBlocks, newBlocks []Block) []StructuralChange {
+	var changes []StructuralChange
+
+	oldHeadings, oldGroups, oldOrder := groupBlocks(oldBlocks)
+	newHeadings, newGroups, newOrder := groupBlocks(newBlocks)
+	changes = append(changes, diffItems(BlockHeading, "", "", oldHeadings, newHeadings)...)
+
+	// Match containers by kind and name, then the rest by content
+	matched := make(map[string]string) // new key → old key
+	used := make(map[string]bool)      // old keys
+	for _, key := range newOrder {
+		if oldGroups[key] != nil {
+			matched[key], used[key] = key, true
+		}
+	}
+	for _, key := range newOrder {
+		if _, ok := matched[key]; ok {
+			continue
+		}
+		best, bestOverlap := "", 0.0
+		for _, oldKey := range oldOrder {
+			if used[oldKey] || oldGroups[oldKey].kind != newGroups[key].kind {
+				continue
+			}
+			if ov := overlap(oldGroups[oldKey].texts, newGroups[key].texts); ov >= minContainerOverlap && ov > bestOverlap {
+				best, bestOverlap = oldKey, ov
+			}
+		}
+		if best != "" {
+			matched[key], used[best] = best, true
+		}
+	}
+
+	for _, key := range newOrder {
+		g := newGroups[key]
+		oldKey, ok := matched[key]
+		if !ok {
+			changes = append(changes, StructuralChange{
+				Kind: g.containerKind(), Action: ActionAdded, Section: g.section, Container: g.name, Count: len(g.texts),
+			})
+			continue
+		}
+		changes = append(changes, diffItems(g.kind, g.section, g.name, oldGroups[oldKey].texts, g.texts)...)
+	}
+	for _, key := range oldOrder {
+		if g := oldGroups[key]; !used[key] {
+			changes = append(changes, StructuralChange{
+				Kind: g.containerKind(), Action: ActionRemoved, Section: g.section, Container: g.name, Count: len(g.texts),
+			})
+		}
+	}
+	return changes
+}
+
+// blockGroup is the rows of one table or the items of one list.
+type blockGroup struct {
+	kind    BlockKind
+	section string
+	name    string
+	texts   []string
+}
+
+func (g *blockGroup) containerKind() BlockKind {
+	if g.kind == BlockTableRow {
+		return BlockTable
+	}
+	return BlockList
+}
+
+// groupBlocks splits an outline into its headings and its containers, keyed
+// by kind and name, with the keys in document order.
+func groupBlocks(blocks []Block) (headings []string, groups map[string]*blockGroup, order []string) {
+	groups = make(map[string]*blockGroup)
+	for _, b := range blocks {
+		if b.Kind == BlockHeading {
+			headings = append(headings, b.Text)
+			continue
+		}
+		key := string(b.Kind) + "\x00" + b.Container
+		g := groups[key]
+		if g == nil {
+			g = &blockGroup{kind: b.Kind, section: b.Section, name: b.Container}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.texts = append(g.texts, b.Text)
+	}
+	return headings, groups, order
+}
+
+// diffItems compares the rows, items or headings of one container as
+// multisets, pairing removed and added ones that are edits of each other.
+func diffItems(kind BlockKind, section, container string, oldTexts, newTexts []string) []StructuralChange {
+	counts := make(map[string]int, len(oldTexts))
+	for _, t := range oldTexts {
+		counts[t]++
+	}
+	var added []string
+	for _, t := range newTexts {
+		if counts[t] > 0 {
+			counts[t]--
+		} else {
+			added = append(added, t)
+		}
+	}
+	var removed []string
+	for _, t := range oldTexts {
+		if counts[t] > 0 {
+			counts[t]--
+			removed = append(removed, t)
+		}
+	}
+
+	var changes []StructuralChange
+	pairedOld := make(map[string]int)
+	pairedNew := make(map[string]int)
+	for _, p := range pairLines(removed, added) {
+		pairedOld[p.Old]++
+		pairedNew[p.New]++
+		changes = append(changes, StructuralChange{
+			Kind: kind, Action: ActionChanged, Section: section, Container: container, Old: p.Old, New: p.New,
+		})
+	}
+	for _, t := range removed {
+		if pairedOld[t] > 0 {
+			pairedOld[t]--
+			continue
+		}
+		changes = append(changes, StructuralChange{Kind: kind, Action: ActionRemoved, Section: section, Container: container, Old: t})
+	}
+	for _, t := range added {
+		if pairedNew[t] > 0 {
+			pairedNew[t]--
+			continue
+		}
+		changes = append(changes, StructuralChange{Kind: kind, Action: ActionAdded, Section: section, Container: container, New: t})
+	}
+	return changes
+}
+
+// overlap returns the share of texts two containers have in common,
+// relative to the larger one.
+func overlap(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	counts := make(map[string]int, len(a))
+	for _, t := range a {
+		counts[t]++
+	}
+	common := 0
+	for _, t := range b {
+		if counts[t] > 0 {
+			counts[t]--
+			common++
+		}
+	}
+	return float64(common) / float64(max(len(a), len(b)))
+}
@@ -0,0 +1,134 @@
+package differ
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"strings"
+)
+
+// Screenshots are compared in square cells of visualCell pixels. A pixel
+// differs when its luminance moves by more than visualTolerance out of 255,
+// which absorbs anti-aliasing and compression noise, and a cell has changed
+// when more than visualCellShare of its pixels differ.
+const (
+	visualCell      = 16
+	visualTolerance = 24
+	visualCellShare = 0.05
+)
+
+// VisualResult is the difference between two screenshots of a page.
+type VisualResult struct {
+	// Ratio is the share of the page's cells that changed, from 0 to 1.
+	// Area only one of the screenshots covers, as when the page grew,
+	// counts as changed.
+	Ratio float64
+	// Regions are the changed areas, top to bottom, in pixels of the larger
+	// screenshot.
+	Regions []image.Rectangle
+}
+
+// Changed reports whether at least threshold of the page changed.
+func (v *VisualResult) Changed(threshold float64) bool {
+	return v != nil && v.Ratio > 0 && v.Ratio >= threshold
+}
+
+// Summary describes the change, e.g. "4.2% of the page changed, at 820-1460px".
+func (v *VisualResult) Summary() string {
+	if v == nil || v.Ratio == 0 {
+		return "No visual changes detected"
+	}
+	spans := make([]string, 0, min(len(v.Regions), 3))
+	for _, r := range v.Regions[:min(len(v.Regions), 3)] {
+		spans = append(spans, fmt.Sprintf("%d-%dpx", r.Min.Y, r.Max.Y))
+	}
+	if len(v.Regions) > 3 {
+		spans = append(spans, fmt.Sprintf("%d more", len(v.Regions)-3))
+	}
+	return fmt.Sprintf("%.1f%% of the page changed, at %s", v.Ratio*100, strings.Join(spans, ", "))
+}
+
+// VisualDiff compares two PNG screenshots of a page pixel by pixel. It finds
+// layout and styling changes that leave the page's text as it was.
+func VisualDiff(before, after []byte) (*VisualResult, error) {
+	a, err := png.Decode(bytes.NewReader(before))
+	if err != nil {
+		return nil, fmt.Errorf("decode old screenshot: %w", err)
+	}
+	b, err := png.Decode(bytes.NewReader(after))
+	if err != nil {
+		return nil, fmt.Errorf("decode new screenshot: %w", err)
+	}
+	return compareImages(a, b), nil
+}
+
+func compareImages(a, b image.Image) *VisualResult {
+	ab, bb := a.Bounds(), b.Bounds()
+	w, h := max(ab.Dx(), bb.Dx()), max(ab.Dy(), bb.Dy())
+	cols, rows := (w+visualCell-1)/visualCell, (h+visualCell-1)/visualCell
+	if cols == 0 || rows == 0 {
+		return &VisualResult{}
+	}
+
+	result := &VisualResult{}
+	changedCells := 0
+	var band image.Rectangle // changed cells of consecutive rows, merged
+	for row := 0; row < rows; row++ {
+		rowSpan := image.Rectangle{}
+		for col := 0; col < cols; col++ {
+			cell := image.Rect(col*visualCell, row*visualCell, min((col+1)*visualCell, w), min((row+1)*visualCell, h))
+			if !cellChanged(a, b, cell) {
+				continue
+			}
+			changedCells++
+			rowSpan = rowSpan.Union(cell)
+		}
+		switch {
+		case !rowSpan.Empty() && !band.Empty():
+			band = band.Union(rowSpan)
+		case !rowSpan.Empty():
+			band = rowSpan
+		case !band.Empty():
+			result.Regions = append(result.Regions, band)
+			band = image.Rectangle{}
+		}
+	}
+	if !band.Empty() {
+		result.Regions = append(result.Regions, band)
+	}
+	result.Ratio = float64(changedCells) / float64(cols*rows)
+	return result
+}
+
+// cellChanged compares the pixels of cell, in coordinates relative to each
+// image's top-left corner.
+func cellChanged(a, b image.Image, cell image.Rectangle) bool {
+	ab, bb := a.Bounds(), b.Bounds()
+	differ, limit := 0, int(visualCellShare*float64(cell.Dx()*cell.Dy()))
+	for y := cell.Min.Y; y < cell.Max.Y; y++ {
+		for x := cell.Min.X; x < cell.Max.X; x++ {
+			pa := image.Pt(ab.Min.X+x, ab.Min.Y+y)
+			pb := image.Pt(bb.Min.X+x, bb.Min.Y+y)
+			inA, inB := pa.In(ab), pb.In(bb)
+			switch {
+			case inA != inB:
+				differ++
+			case inA:
+				if d := luma(a, pa) - luma(b, pb); d > visualTolerance || d < -visualTolerance {
+					differ++
+				}
+			}
+			if differ > limit {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// luma returns the pixel's luminance from 0 to 255 (ITU-R BT.601).
+func luma(img image.Image, p image.Point) int {
+	r, g, b, _ := img.At(p.X, p.Y).RGBA()
+	return int((299*r + 587*g + 114*b) / 1000 >> 8)
+}
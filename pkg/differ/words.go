@@ -0,0 +1,227 @@
+package differ
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Op is the kind of an intra-line diff segment.
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpDelete Op = "delete" // only in the old line
+	OpInsert Op = "insert" // only in the new line
+)
+
+// Segment is a run of text within a changed line.
+type Segment struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// LineChange pairs a removed line with the added line that replaced it.
+// Segments spell out both: equal and delete segments joined give Old,
+// equal and insert segments give New.
+type LineChange struct {
+	Old      string    `json:"old"`
+	New      string    `json:"new"`
+	Segments []Segment `json:"segments"`
+}
+
+// Inline renders the change in one line, with deleted text as [-old-] and
+// inserted text as {+new+}, e.g. "Pro plan $[-49-]{+59+}/month".
+func (c LineChange) Inline() string {
+	var sb strings.Builder
+	for _, s := range c.Segments {
+		switch s.Op {
+		case OpDelete:
+			sb.WriteString("[-" + s.Text + "-]")
+		case OpInsert:
+			sb.WriteString("{+" + s.Text + "+}")
+		default:
+			sb.WriteString(s.Text)
+		}
+	}
+	return sb.String()
+}
+
+// WordDiff diffs two lines word by word. Runs of letters and digits are
+// words; punctuation, whitespace and CJK characters are compared one at a
+// time, so "$49" and "$59" differ only in "49" → "59".
+func WordDiff(oldLine, newLine string) []Segment {
+	return diffTokens(tokenize(oldLine), tokenize(newLine))
+}
+
+// CharDiff diffs two lines character by character.
+func CharDiff(oldLine, newLine string) []Segment {
+	return diffTokens(splitRunes(oldLine), splitRunes(newLine))
+}
+
+// Similarity returns the share of text two diffed lines have in common,
+// from 0 (nothing) to 1 (identical).
+func Similarity(segments []Segment) float64 {
+	var equal, total int
+	for _, s := range segments {
+		n := utf8.RuneCountInString(s.Text)
+		if s.Op == OpEqual {
+			equal += n
+			total += 2 * n
+		} else {
+			total += n
+		}
+	}
+	if total == 0 {
+		return 1
+	}
+	return float64(2*equal) / float64(total)
+}
+
+// Limits on pairing lines for refinement, which diffs every removed line
+// against every added one.
+const (
+	maxPairings       = 10000 // removed × added lines
+	maxTokenCells     = 250000
+	minPairSimilarity = 0.5
+)
+
+// pairLines matches each removed line with the most similar unmatched added
+// line, when they share at least half their text.
+func pairLines(removed, added []string) []LineChange {
+	if len(removed) == 0 || len(added) == 0 || len(removed)*len(added) > maxPairings {
+		return nil
+	}
+	used := make([]bool, len(added))
+	var changes []LineChange
+	for _, old := range removed {
+		best, bestSim := -1, 0.0
+		var bestSegs []Segment
+		for j, cand := range added {
+			if used[j] {
+				continue
+			}
+			segs := WordDiff(old, cand)
+			if sim := Similarity(segs); sim >= minPairSimilarity && sim > bestSim {
+				best, bestSim, bestSegs = j, sim, segs
+			}
+		}
+		if best >= 0 {
+			used[best] = true
+			changes = append(changes, LineChange{Old: old, New: added[best], Segments: bestSegs})
+		}
+	}
+	return changes
+}
+
+// diffTokens returns the segments of a longest-common-subsequence diff of
+// two token lists. Within a changed region deletions come before insertions.
+func diffTokens(a, b []string) []Segment {
+	// Trim the common prefix and suffix, which is most of a typical line
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+	var segs []Segment
+	add := func(op Op, text string) {
+		if text == "" {
+			return
+		}
+		if n := len(segs); n > 0 && segs[n-1].Op == op {
+			segs[n-1].Text += text
+			return
+		}
+		segs = append(segs, Segment{Op: op, Text: text})
+	}
+
+	add(OpEqual, strings.Join(a[:pre], ""))
+	midA, midB := a[pre:len(a)-suf], b[pre:len(b)-suf]
+	if len(midA)*len(midB) > maxTokenCells {
+		add(OpDelete, strings.Join(midA, ""))
+		add(OpInsert, strings.Join(midB, ""))
+	} else {
+		// lcs[i][j] is the LCS length of midA[i:] and midB[j:]
+		lcs := make([][]int, len(midA)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(midB)+1)
+		}
+		for i := len(midA) - 1; i >= 0; i-- {
+			for j := len(midB) - 1; j >= 0; j-- {
+				if midA[i] == midB[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+		var del, ins strings.Builder
+		flush := func() {
+			add(OpDelete, del.String())
+			add(OpInsert, ins.String())
+			del.Reset()
+			ins.Reset()
+		}
+		i, j := 0, 0
+		for i < len(midA) || j < len(midB) {
+			switch {
+			case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+				flush()
+				add(OpEqual, midA[i])
+				i++
+				j++
+			case j == len(midB) || (i < len(midA) && lcs[i+1][j] >= lcs[i][j+1]):
+				del.WriteString(midA[i])
+				i++
+			default:
+				ins.WriteString(midB[j])
+				j++
+			}
+		}
+		flush()
+	}
+	add(OpEqual, strings.Join(a[len(a)-suf:], ""))
+	return segs
+}
+
+// tokenize splits a line into words (runs of letters and digits, except
+// CJK) and single other characters.
+func tokenize(s string) []string {
+	var tokens []string
+	start := -1
+	for i, r := range s {
+		if isWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, s[start:i])
+			start = -1
+		}
+		tokens = append(tokens, string(r))
+	}
+	if start >= 0 {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+func isWordRune(r rune) bool {
+	if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+		return false
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+func splitRunes(s string) []string {
+	tokens := make([]string, 0, len(s))
+	for _, r := range s {
+		tokens = append(tokens, string(r))
+	}
+	return tokens
+}
@@ -0,0 +1,253 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Catalogs live in locales/<lang>.json, one flat object per language:
+//
+//	{
+//	  "@name": "Deutsch",          // display name, see LanguageName
+//	  "@fallback": ["en"],         // languages tried before English for missing keys
+//	  "@plural": "one_other",      // plural rule, see pluralRules
+//	  "watch.no_changes": "Keine Änderungen erkannt",
+//	  "watch.pages_changed": {"one": "%d Seitenänderung", "other": "%d Seitenänderungen"}
+//	}
+//
+// Adding a language means adding a catalog file; any key it leaves out
+// falls back along @fallback and then to English.
+//
+//go:embed locales/*.json
+var localeFS embed.FS
+
+// catalog is one parsed locale file.
+type catalog struct {
+	name     string
+	fallback []Language
+	plural   string
+	messages map[string]message
+}
+
+// message is a catalog entry: a single string, or one form per plural
+// category.
+type message struct {
+	text  string
+	forms map[string]string
+}
+
+func (m *message) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '{' {
+		return json.Unmarshal(data, &m.forms)
+	}
+	return json.Unmarshal(data, &m.text)
+}
+
+// pluralRules map a catalog's @plural rule to the category of a count.
+var pluralRules = map[string]func(n int) string{
+	// No grammatical number (Chinese, Japanese, Korean).
+	"other": func(int) string { return "other" },
+	// Singular for exactly one (English, German, Spanish).
+	"one_other": func(n int) string {
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	},
+	// Slavic languages.
+	"one_few_many": func(n int) string {
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	},
+}
+
+var catalogs = loadCatalogs()
+
+func loadCatalogs() map[Language]*catalog {
+	files, err := localeFS.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: read locales: %v", err))
+	}
+	cats := make(map[Language]*catalog, len(files))
+	for _, f := range files {
+		lang := Language(strings.TrimSuffix(f.Name(), path.Ext(f.Name())))
+		data, err := localeFS.ReadFile("locales/" + f.Name())
+		if err != nil {
+			panic(fmt.Sprintf("i18n: read %s: %v", f.Name(), err))
+		}
+		cat, err := parseCatalog(data)
+		if err != nil {
+			panic(fmt.Sprintf("i18n: parse %s: %v", f.Name(), err))
+		}
+		cats[lang] = cat
+	}
+	if cats[LangEN] == nil {
+		panic("i18n: missing English catalog")
+	}
+	return cats
+}
+
+func parseCatalog(data []byte) (*catalog, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	cat := &catalog{plural: "one_other", messages: make(map[string]message, len(raw))}
+	for key, val := range raw {
+		var err error
+		switch key {
+		case "@name":
+			err = json.Unmarshal(val, &cat.name)
+		case "@fallback":
+			err = json.Unmarshal(val, &cat.fallback)
+		case "@plural":
+			err = json.Unmarshal(val, &cat.plural)
+		default:
+			var m message
+			err = json.Unmarshal(val, &m)
+			cat.messages[key] = m
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	if pluralRules[cat.plural] == nil {
+		return nil, fmt.Errorf("unknown plural rule %q", cat.plural)
+	}
+	return cat, nil
+}
+
+// chain returns the catalogs to search for lang, in order: the language
+// itself, its fallbacks, then English.
+func chain(lang Language) []*catalog {
+	var out []*catalog
+	seen := map[Language]bool{}
+	var add func(Language)
+	add = func(l Language) {
+		cat := catalogs[l]
+		if cat == nil || seen[l] {
+			return
+		}
+		seen[l] = true
+		out = append(out, cat)
+		for _, fb := range cat.fallback {
+			add(fb)
+		}
+	}
+	add(lang)
+	add(LangEN)
+	return out
+}
+
+// Lookup returns the message for key in lang, following the fallback
+// chain. For plural entries it returns the "other" form.
+func Lookup(lang Language, key string) (string, bool) {
+	for _, cat := range chain(lang) {
+		if m, ok := cat.messages[key]; ok {
+			if m.forms != nil {
+				return m.forms["other"], true
+			}
+			return m.text, true
+		}
+	}
+	return "", false
+}
+
+// T returns the message for key in lang, formatted with args if any are
+// given. A missing key is returned as is.
+func T(lang Language, key string, args ...any) string {
+	msg, ok := Lookup(lang, key)
+	if !ok {
+		msg = key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Plural returns the unformatted form of key that matches count n under
+// lang's plural rule, falling back to the "other" form.
+func Plural(lang Language, key string, n int) string {
+	for _, cat := range chain(lang) {
+		m, ok := cat.messages[key]
+		if !ok {
+			continue
+		}
+		if m.forms == nil {
+			return m.text
+		}
+		if form, ok := m.forms[pluralRules[cat.plural](n)]; ok {
+			return form
+		}
+		return m.forms["other"]
+	}
+	return key
+}
+
+// MatchLanguage picks the best supported language from an Accept-Language
+// header value, e.g. "de-CH,de;q=0.9,en;q=0.8". Returns false if none
+// matches.
+func MatchLanguage(header string) (Language, bool) {
+	type candidate struct {
+		lang Language
+		q    float64
+	}
+	var cands []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if _, err := fmt.Sscanf(v, "%g", &q); err != nil {
+				continue
+			}
+		}
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if IsValidLanguage(base) && q > 0 {
+			cands = append(cands, candidate{Language(base), q})
+		}
+	}
+	if len(cands) == 0 {
+		return "", false
+	}
+	sort.SliceStable(cands, func(i, j int) bool { return cands[i].q > cands[j].q })
+	return cands[0].lang, true
+}
+
+// catalogLanguages returns the languages with a catalog: the built-in ones
+// first, then any others sorted by code.
+func catalogLanguages() []Language {
+	builtin := []Language{LangZH, LangEN, LangJA, LangKO, LangDE, LangES}
+	var langs, extra []Language
+	for _, l := range builtin {
+		if catalogs[l] != nil {
+			langs = append(langs, l)
+		}
+	}
+	for l := range catalogs {
+		if !contains(builtin, l) {
+			extra = append(extra, l)
+		}
+	}
+	sort.Slice(extra, func(i, j int) bool { return extra[i] < extra[j] })
+	return append(langs, extra...)
+}
+
+func contains(langs []Language, lang Language) bool {
+	for _, l := range langs {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
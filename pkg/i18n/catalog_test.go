@@ -0,0 +1,173 @@
+package i18n
+
+import "testing"
+
+func TestPluralRules(t *testing.T) {
+	tests := []struct {
+		rule string
+		n    int
+		want string
+	}{
+		{"other", 0, "other"},
+		{"other", 1, "other"},
+		{"other", 2, "other"},
+		{"one_other", 0, "other"},
+		{"one_other", 1, "one"},
+		{"one_other", 2, "other"},
+		{"one_other", 21, "other"},
+		{"one_few_many", 0, "many"},
+		{"one_few_many", 1, "one"},
+		{"one_few_many", 2, "few"},
+		{"one_few_many", 4, "few"},
+		{"one_few_many", 5, "many"},
+		{"one_few_many", 11, "many"},
+		{"one_few_many", 12, "many"},
+		{"one_few_many", 14, "many"},
+		{"one_few_many", 21, "one"},
+		{"one_few_many", 22, "few"},
+		{"one_few_many", 111, "many"},
+		{"one_few_many", 112, "many"},
+		{"one_few_many", 122, "few"},
+	}
+	for _, tt := range tests {
+		if got := pluralRules[tt.rule](tt.n); got != tt.want {
+			t.Errorf("%s(%d) = %q, want %q", tt.rule, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestPlural(t *testing.T) {
+	tests := []struct {
+		lang Language
+		key  string
+		n    int
+		want string
+	}{
+		{LangEN, "watch.pages_changed", 0, "%d page changes"},
+		{LangEN, "watch.pages_changed", 1, "%d page change"},
+		{LangEN, "watch.pages_changed", 2, "%d page changes"},
+		{LangDE, "watch.pages_changed", 1, "%d Seitenänderung"},
+		{LangDE, "watch.pages_changed", 7, "%d Seitenänderungen"},
+		// A plain string serves every count
+		{LangZH, "watch.pages_changed", 1, "%d 个页面变化"},
+		{LangZH, "watch.pages_changed", 5, "%d 个页面变化"},
+		{LangDE, "watch.subject_changed", 1, "%d Wettbewerber geändert"},
+		{LangEN, "no.such.key", 1, "no.such.key"},
+	}
+	for _, tt := range tests {
+		if got := Plural(tt.lang, tt.key, tt.n); got != tt.want {
+			t.Errorf("Plural(%s, %s, %d) = %q, want %q", tt.lang, tt.key, tt.n, got, tt.want)
+		}
+	}
+}
+
+// useCatalogs replaces the embedded catalogs with ones parsed from data
+// for the rest of the test.
+func useCatalogs(t *testing.T, data map[Language]string) {
+	t.Helper()
+	cats := make(map[Language]*catalog, len(data))
+	for lang, src := range data {
+		cat, err := parseCatalog([]byte(src))
+		if err != nil {
+			t.Fatalf("parse %s: %v", lang, err)
+		}
+		cats[lang] = cat
+	}
+	saved := catalogs
+	catalogs = cats
+	t.Cleanup(func() { catalogs = saved })
+}
+
+func TestFallbackChain(t *testing.T) {
+	// A Traditional Chinese catalog only needs the keys that differ from zh
+	useCatalogs(t, map[Language]string{
+		"zh-TW": `{"@fallback": ["zh"], "@plural": "other", "greeting": "歡迎"}`,
+		"zh": `{"@fallback": ["en", "zh-TW"], "@plural": "other",
+			"greeting": "欢迎", "farewell": "再见",
+			"items": "%d 个项目"}`,
+		"en": `{"greeting": "Welcome", "farewell": "Goodbye", "help": "Help",
+			"items": {"one": "%d item", "other": "%d items"},
+			"pages": {"one": "%d page", "other": "%d pages"},
+			"rows": {"other": "%d rows"}}`,
+	})
+
+	tests := []struct {
+		lang Language
+		key  string
+		want string
+		ok   bool
+	}{
+		{"zh-TW", "greeting", "歡迎", true},
+		{"zh-TW", "farewell", "再见", true},
+		{"zh-TW", "help", "Help", true},
+		{"zh-TW", "missing", "", false},
+		{"zh", "greeting", "欢迎", true},
+		{"zh", "help", "Help", true},
+		{"en", "farewell", "Goodbye", true},
+		{"en", "pages", "%d pages", true},   // plural entries look up as "other"
+		{"fr", "greeting", "Welcome", true}, // no catalog goes straight to English
+	}
+	for _, tt := range tests {
+		got, ok := Lookup(tt.lang, tt.key)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Lookup(%s, %s) = %q, %v; want %q, %v", tt.lang, tt.key, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	// zh-TW lists zh, which lists zh-TW again: each catalog is searched once
+	if n := len(chain("zh-TW")); n != 3 {
+		t.Errorf("zh-TW chain has %d catalogs, want zh-TW, zh, en", n)
+	}
+	if got := T("zh-TW", "missing.key"); got != "missing.key" {
+		t.Errorf("T of a missing key = %q, want the key", got)
+	}
+	if got := T("zh-TW", "items", 3); got != "3 个项目" {
+		t.Errorf("T(zh-TW, items) = %q", got)
+	}
+
+	// Plural forms found further down the chain use that catalog's rule
+	plurals := []struct {
+		lang Language
+		key  string
+		n    int
+		want string
+	}{
+		{"zh-TW", "items", 1, "%d 个项目"},
+		{"zh-TW", "pages", 1, "%d page"},
+		{"zh-TW", "pages", 2, "%d pages"},
+		{"en", "rows", 1, "%d rows"}, // no "one" form, so "other"
+	}
+	for _, tt := range plurals {
+		if got := Plural(tt.lang, tt.key, tt.n); got != tt.want {
+			t.Errorf("Plural(%s, %s, %d) = %q, want %q", tt.lang, tt.key, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestParseCatalog(t *testing.T) {
+	tests := []struct {
+		name, data string
+		wantErr    bool
+	}{
+		{name: "defaults", data: `{"a": "A"}`},
+		{name: "all metadata", data: `{"@name": "Polski", "@fallback": ["en"], "@plural": "one_few_many", "a": {"one": "x", "few": "y", "many": "z"}}`},
+		{name: "unknown plural rule", data: `{"@plural": "dual"}`, wantErr: true},
+		{name: "fallback not a list", data: `{"@fallback": "en"}`, wantErr: true},
+		{name: "message not a string", data: `{"a": 1}`, wantErr: true},
+		{name: "plural form not a string", data: `{"a": {"one": 1}}`, wantErr: true},
+		{name: "not an object", data: `["a"]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		_, err := parseCatalog([]byte(tt.data))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: parseCatalog error = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	// Every embedded catalog parsed at init; each names its language
+	for _, lang := range catalogLanguages() {
+		if catalogs[lang].name == "" {
+			t.Errorf("catalog %s has no @name", lang)
+		}
+	}
+}
@@ -16,27 +16,16 @@ const (
 	LangES Language = "es" // Spanish
 )
 
-// AllLanguages is the list of all supported languages.
-var AllLanguages = []Language{LangZH, LangEN, LangJA, LangKO, LangDE, LangES}
+// AllLanguages is the list of all supported languages: every language with
+// a catalog in locales/.
+var AllLanguages = catalogLanguages()
 
 // LanguageName returns the human-readable display name of a language.
 func LanguageName(lang Language) string {
-	switch lang {
-	case LangZH:
-		return "中文"
-	case LangEN:
-		return "English"
-	case LangJA:
-		return "日本語"
-	case LangKO:
-		return "한국어"
-	case LangDE:
-		return "Deutsch"
-	case LangES:
-		return "Español"
-	default:
-		return string(lang)
+	if cat := catalogs[lang]; cat != nil && cat.name != "" {
+		return cat.name
 	}
+	return string(lang)
 }
 
 // IsValidLanguage checks if a language code is supported.
@@ -15,100 +15,48 @@ type WatchLabels struct {
 	GeneratedBy    string
 	BenchmarkTitle string // "📊 AI Benchmark Update"
 	BenchmarkDesc  string // description text
+
+	// Digest email / Telegram templates
+	CompetitorsChanged string // "%d competitors, %d pages changed" (uses fmt.Sprintf)
+	SubjectChanged     string // "%d competitors changed" (uses fmt.Sprintf)
+	PagesChanged       string // "%d page changes" (uses fmt.Sprintf)
+	DiffLines          string // "+%d / -%d lines" (uses fmt.Sprintf)
+	ViewDiff           string // collapsible inline diff toggle
+	Tagline            string // footer tagline
+
+	// Weekly heartbeat (no changes for 7 days)
+	HeartbeatTitle string // "WatchBot Weekly — %s" (uses fmt.Sprintf with date)
+	HeartbeatBody  string // body template: date from, date to, competitor list
+	DateFormat     string // short date layout for heartbeat ranges
+	ListSeparator  string // joins competitor names
 }
 
-// GetWatchLabels returns the WatchBot UI labels for a given language.
+// GetWatchLabels returns the WatchBot UI labels for a given language, read
+// from its catalog. Plural entries yield their "other" form; use Plural to
+// pick the form for a count.
 func GetWatchLabels(lang Language) WatchLabels {
-	labels, ok := watchLabelMap[lang]
-	if !ok {
-		return watchLabelMap[LangEN]
+	return WatchLabels{
+		DigestTitle:        T(lang, "watch.digest_title"),
+		ChangesFound:       T(lang, "watch.changes_found"),
+		NoChanges:          T(lang, "watch.no_changes"),
+		Severity:           T(lang, "watch.severity"),
+		Critical:           T(lang, "watch.critical"),
+		Important:          T(lang, "watch.important"),
+		Minor:              T(lang, "watch.minor"),
+		ViewPage:           T(lang, "watch.view_page"),
+		Unchanged:          T(lang, "watch.unchanged"),
+		GeneratedBy:        T(lang, "watch.generated_by"),
+		BenchmarkTitle:     T(lang, "watch.benchmark_title"),
+		BenchmarkDesc:      T(lang, "watch.benchmark_desc"),
+		CompetitorsChanged: T(lang, "watch.competitors_changed"),
+		SubjectChanged:     T(lang, "watch.subject_changed"),
+		PagesChanged:       T(lang, "watch.pages_changed"),
+		DiffLines:          T(lang, "watch.diff_lines"),
+		ViewDiff:           T(lang, "watch.view_diff"),
+		Tagline:            T(lang, "watch.tagline"),
+		HeartbeatTitle:     T(lang, "watch.heartbeat_title"),
+		HeartbeatBody:      T(lang, "watch.heartbeat_body"),
+		DateFormat:         T(lang, "watch.date_format"),
+		ListSeparator:      T(lang, "watch.list_separator"),
 	}
-	return labels
-}
-
-var watchLabelMap = map[Language]WatchLabels{
-	LangZH: {
-		DigestTitle:    "竞品监控报告",
-		ChangesFound:   "检测到 %d 个页面发生变化",
-		NoChanges:      "未发生变化",
-		Severity:       "严重程度",
-		Critical:       "严重",
-		Important:      "重要",
-		Minor:          "次要",
-		ViewPage:       "查看原页面 →",
-		Unchanged:      "未变化",
-		GeneratedBy:    "由 WatchBot 自动生成",
-		BenchmarkTitle: "📊 AI Benchmark 更新",
-		BenchmarkDesc:  "以下模型在本周有新评测数据",
-	},
-	LangEN: {
-		DigestTitle:    "Competitor Watch Report",
-		ChangesFound:   "%d page changes detected",
-		NoChanges:      "No changes detected",
-		Severity:       "Severity",
-		Critical:       "Critical",
-		Important:      "Important",
-		Minor:          "Minor",
-		ViewPage:       "View Page →",
-		Unchanged:      "Unchanged",
-		GeneratedBy:    "Auto-generated by WatchBot",
-		BenchmarkTitle: "📊 AI Benchmark Update",
-		BenchmarkDesc:  "New benchmark data available this week",
-	},
-	LangJA: {
-		DigestTitle:    "競合モニタリングレポート",
-		ChangesFound:   "%d ページの変更を検出",
-		NoChanges:      "変更なし",
-		Severity:       "重要度",
-		Critical:       "重大",
-		Important:      "重要",
-		Minor:          "軽微",
-		ViewPage:       "元ページを見る →",
-		Unchanged:      "変更なし",
-		GeneratedBy:    "WatchBot により自動生成",
-		BenchmarkTitle: "📊 AI ベンチマーク更新",
-		BenchmarkDesc:  "今週の新しいベンチマークデータ",
-	},
-	LangKO: {
-		DigestTitle:    "경쟁사 모니터링 리포트",
-		ChangesFound:   "%d 페이지 변경 감지",
-		NoChanges:      "변경 없음",
-		Severity:       "심각도",
-		Critical:       "심각",
-		Important:      "중요",
-		Minor:          "경미",
-		ViewPage:       "원본 페이지 보기 →",
-		Unchanged:      "변경 없음",
-		GeneratedBy:    "WatchBot 자동 생성",
-		BenchmarkTitle: "📊 AI 벤치마크 업데이트",
-		BenchmarkDesc:  "이번 주 새로운 벤치마크 데이터",
-	},
-	LangDE: {
-		DigestTitle:    "Wettbewerber-Überwachungsbericht",
-		ChangesFound:   "%d Seitenänderungen erkannt",
-		NoChanges:      "Keine Änderungen festgestellt",
-		Severity:       "Schweregrad",
-		Critical:       "Kritisch",
-		Important:      "Wichtig",
-		Minor:          "Geringfügig",
-		ViewPage:       "Seite ansehen →",
-		Unchanged:      "Unverändert",
-		GeneratedBy:    "Automatisch erstellt von WatchBot",
-		BenchmarkTitle: "📊 AI-Benchmark-Update",
-		BenchmarkDesc:  "Neue Benchmark-Daten diese Woche",
-	},
-	LangES: {
-		DigestTitle:    "Informe de Monitoreo de Competidores",
-		ChangesFound:   "%d cambios de página detectados",
-		NoChanges:      "Sin cambios detectados",
-		Severity:       "Severidad",
-		Critical:       "Crítico",
-		Important:      "Importante",
-		Minor:          "Menor",
-		ViewPage:       "Ver Página →",
-		Unchanged:      "Sin cambios",
-		GeneratedBy:    "Generado automáticamente por WatchBot",
-		BenchmarkTitle: "📊 Actualización de Benchmark AI",
-		BenchmarkDesc:  "Nuevos datos de benchmark esta semana",
-	},
 }
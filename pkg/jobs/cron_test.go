@@ -0,0 +1,77 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSpec(t *testing.T) {
+	at := func(s string) time.Time {
+		t.Helper()
+		v, err := time.Parse("2006-01-02 15:04 Mon", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	from := at("2026-03-14 10:07 Sat")
+	tests := []struct {
+		spec string
+		want string // next run after from
+	}{
+		{"@every 5m", "2026-03-14 10:12 Sat"},
+		{"@every 90s", "2026-03-14 10:08 Sat"}, // truncated to the minute below
+		{"@hourly", "2026-03-14 11:00 Sat"},
+		{"@daily", "2026-03-15 00:00 Sun"},
+		{"@midnight", "2026-03-15 00:00 Sun"},
+		{"@weekly", "2026-03-15 00:00 Sun"},
+		{"@monthly", "2026-04-01 00:00 Wed"},
+		{"*/15 * * * *", "2026-03-14 10:15 Sat"},
+		{"0 8-18/2 * * *", "2026-03-14 12:00 Sat"},
+		{"30 9 * * 1-5", "2026-03-16 09:30 Mon"},
+		{"0 0 * * 7", "2026-03-15 00:00 Sun"}, // 7 is Sunday too
+		{"0 12 1,15 * *", "2026-03-15 12:00 Sun"},
+		{"0 0 13 * 5", "2026-03-20 00:00 Fri"}, // day of month or week
+		{"0 0 29 2 *", "2028-02-29 00:00 Tue"},
+		{" 5 4 * * * ", "2026-03-15 04:05 Sun"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			spec, err := ParseSpec(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := spec.Next(from)
+			if tt.spec == "@every 90s" {
+				got = got.Truncate(time.Minute)
+			}
+			if want := at(tt.want); !got.Equal(want) {
+				t.Errorf("Next = %s, want %s", got.Format("2006-01-02 15:04 Mon"), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSpecInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"@every",
+		"@every 500ms",
+		"@every soon",
+		"@yearly",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	} {
+		if _, err := ParseSpec(spec); err == nil {
+			t.Errorf("ParseSpec(%q) succeeded, want an error", spec)
+		}
+	}
+}
@@ -0,0 +1,507 @@
+// Package jobs is a small persistent job queue on the suite database. Jobs
+// survive restarts, failed jobs are retried with backoff, each kind runs on
+// its own worker pool, and recurring jobs are enqueued from cron-style
+// schedules. Several processes may share one database; each claims only the
+// kinds it has handlers for.
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
+)
+
+// Job statuses.
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusDone    = "done"
+	StatusFailed  = "failed" // gave up after MaxAttempts
+)
+
+// timeLayout is how times are stored, in UTC.
+const timeLayout = "2006-01-02 15:04:05"
+
+// Job is a unit of work.
+type Job struct {
+	ID          int64
+	Kind        string
+	Payload     string // usually JSON; handlers decode it
+	Status      string
+	Attempts    int // including the current one while running
+	MaxAttempts int
+	RunAt       time.Time
+	LastError   string
+	Schedule    string // name of the schedule that enqueued it, if any
+	CreatedAt   time.Time
+}
+
+// Handler runs a job. Returning an error retries the job with backoff until
+// its attempts are used up. ctx is cancelled when the queue shuts down;
+// a job that returns an error after that is put back without using an
+// attempt.
+type Handler func(ctx context.Context, job Job) error
+
+// HandlerOptions configure how a kind of job runs.
+type HandlerOptions struct {
+	Workers     int           // jobs of this kind run at once; default 1
+	Timeout     time.Duration // per attempt, also the lease after which a crashed worker's job is retried; default 10m
+	MaxAttempts int           // default for jobs enqueued without one; default 5
+	// Backoff is the delay before retrying after a failed attempt; default
+	// Backoff.
+	Backoff func(attempt int) time.Duration
+}
+
+// Recurring is a schedule that enqueues a job each time it comes due.
+type Recurring struct {
+	Name       string // unique; re-registering a name updates it
+	Spec       string // see Spec
+	Kind       string
+	Payload    string
+	RunOnStart bool // also run as soon as the queue starts
+}
+
+type handler struct {
+	fn   Handler
+	opts HandlerOptions
+	sem  chan struct{}
+}
+
+// Queue claims due jobs and runs them on per-kind worker pools.
+type Queue struct {
+	db       *storage.DB
+	handlers map[string]*handler
+	logger   *slog.Logger
+
+	poll      time.Duration
+	retention time.Duration // done jobs are deleted after this long
+
+	wg sync.WaitGroup
+}
+
+// New creates a queue on db. The jobs tables come from db.Migrate.
+func New(db *storage.DB) *Queue {
+	return &Queue{
+		db:        db,
+		handlers:  make(map[string]*handler),
+		logger:    slog.Default(),
+		poll:      2 * time.Second,
+		retention: 7 * 24 * time.Hour,
+	}
+}
+
+// Handle registers the handler for a kind of job. It must be called before
+// Run.
+func (q *Queue) Handle(kind string, opts HandlerOptions, fn Handler) {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Minute
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.Backoff == nil {
+		opts.Backoff = Backoff
+	}
+	q.handlers[kind] = &handler{fn: fn, opts: opts, sem: make(chan struct{}, opts.Workers)}
+}
+
+// Enqueue adds a job. A zero RunAt runs it now; a zero MaxAttempts uses the
+// kind's default, or 5 if this process has no handler for it. LastError
+// records why work handed over after failing elsewhere is queued.
+func (q *Queue) Enqueue(ctx context.Context, job Job) (int64, error) {
+	if job.Kind == "" {
+		return 0, errors.New("job kind is required")
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = time.Now()
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = 5
+		if h := q.handlers[job.Kind]; h != nil {
+			job.MaxAttempts = h.opts.MaxAttempts
+		}
+	}
+	return q.db.InsertID(ctx,
+		`INSERT INTO jobs (kind, payload, status, max_attempts, run_at, schedule, last_error) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		job.Kind, job.Payload, StatusPending, job.MaxAttempts, job.RunAt.UTC().Format(timeLayout), job.Schedule,
+		sql.NullString{String: job.LastError, Valid: job.LastError != ""})
+}
+
+// Schedule registers or updates a recurring job. A new schedule, or one
+// whose spec changed, first runs at the spec's next time; an unchanged one
+// keeps its next run across restarts.
+func (q *Queue) Schedule(ctx context.Context, r Recurring) error {
+	spec, err := ParseSpec(r.Spec)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	next := spec.Next(now)
+	if r.RunOnStart {
+		next = now
+	}
+
+	var oldSpec string
+	var oldNext time.Time
+	err = q.db.QueryRowContext(ctx,
+		`SELECT spec, next_run_at FROM job_schedules WHERE name = ?`, r.Name).Scan(&oldSpec, &oldNext)
+	switch {
+	case err == sql.ErrNoRows:
+		_, err = q.db.ExecContext(ctx,
+			`INSERT INTO job_schedules (name, kind, spec, payload, next_run_at) VALUES (?, ?, ?, ?, ?)`,
+			r.Name, r.Kind, r.Spec, r.Payload, next.UTC().Format(timeLayout))
+		return err
+	case err != nil:
+		return err
+	}
+	if oldSpec == r.Spec && !r.RunOnStart {
+		next = oldNext
+	}
+	_, err = q.db.ExecContext(ctx,
+		`UPDATE job_schedules SET kind = ?, spec = ?, payload = ?, next_run_at = ? WHERE name = ?`,
+		r.Kind, r.Spec, r.Payload, next.UTC().Format(timeLayout), r.Name)
+	return err
+}
+
+// Unschedule removes a recurring job. Jobs it already enqueued still run.
+func (q *Queue) Unschedule(ctx context.Context, name string) error {
+	_, err := q.db.ExecContext(ctx, `DELETE FROM job_schedules WHERE name = ?`, name)
+	return err
+}
+
+// Run enqueues due schedules and runs due jobs until ctx is done, then
+// waits for running jobs to return.
+func (q *Queue) Run(ctx context.Context) {
+	kinds := make([]string, 0, len(q.handlers))
+	for kind := range q.handlers {
+		kinds = append(kinds, kind)
+	}
+	q.logger.Info("job queue started", "kinds", kinds)
+
+	ticker := time.NewTicker(q.poll)
+	defer ticker.Stop()
+	lastPrune := time.Time{}
+	for {
+		q.enqueueDue(ctx)
+		q.claimAll(ctx)
+		if time.Since(lastPrune) > time.Hour {
+			q.prune(ctx)
+			lastPrune = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			q.logger.Info("job queue stopping, waiting for running jobs")
+			q.wg.Wait()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// enqueueDue enqueues a job for every schedule that is due and moves the
+// schedule to its next run. The conditional update makes sure only one
+// process enqueues each run.
+func (q *Queue) enqueueDue(ctx context.Context) {
+	now := time.Now()
+	rows, err := q.db.QueryContext(ctx,
+		`SELECT name, kind, spec, payload, next_run_at FROM job_schedules WHERE next_run_at <= ?`,
+		now.UTC().Format(timeLayout))
+	if err != nil {
+		if ctx.Err() == nil {
+			q.logger.Error("load job schedules", "error", err)
+		}
+		return
+	}
+	var due []Recurring
+	var dueAt []time.Time
+	for rows.Next() {
+		var r Recurring
+		var at time.Time
+		if err := rows.Scan(&r.Name, &r.Kind, &r.Spec, &r.Payload, &at); err != nil {
+			q.logger.Error("scan job schedule", "error", err)
+			continue
+		}
+		due = append(due, r)
+		dueAt = append(dueAt, at)
+	}
+	rows.Close()
+
+	for i, r := range due {
+		spec, err := ParseSpec(r.Spec)
+		if err != nil {
+			q.logger.Error("invalid job schedule", "name", r.Name, "error", err)
+			continue
+		}
+		err = q.db.Transaction(ctx, func(tx *storage.Tx) error {
+			res, err := tx.ExecContext(ctx,
+				`UPDATE job_schedules SET next_run_at = ?, last_run_at = ? WHERE name = ? AND next_run_at = ?`,
+				spec.Next(now).UTC().Format(timeLayout), now.UTC().Format(timeLayout),
+				r.Name, dueAt[i].UTC().Format(timeLayout))
+			if err != nil {
+				return err
+			}
+			if n, _ := res.RowsAffected(); n == 0 {
+				return nil // another process took this run
+			}
+			// A run still waiting covers this one too, so a schedule shorter
+			// than its job's run time does not pile up runs
+			var waiting int
+			if err := tx.QueryRowContext(ctx,
+				`SELECT COUNT(*) FROM jobs WHERE schedule = ? AND status = ?`, r.Name, StatusPending).Scan(&waiting); err != nil {
+				return err
+			}
+			if waiting > 0 {
+				return nil
+			}
+			maxAttempts := 5
+			if h := q.handlers[r.Kind]; h != nil {
+				maxAttempts = h.opts.MaxAttempts
+			}
+			_, err = tx.ExecContext(ctx,
+				`INSERT INTO jobs (kind, payload, status, max_attempts, run_at, schedule) VALUES (?, ?, ?, ?, ?, ?)`,
+				r.Kind, r.Payload, StatusPending, maxAttempts, now.UTC().Format(timeLayout), r.Name)
+			return err
+		})
+		if err != nil {
+			q.logger.Error("enqueue scheduled job", "name", r.Name, "error", err)
+		}
+	}
+}
+
+// claimAll starts due jobs while their kinds have free workers.
+func (q *Queue) claimAll(ctx context.Context) {
+	for kind, h := range q.handlers {
+		for ctx.Err() == nil && q.startOne(ctx, kind, h) {
+		}
+	}
+}
+
+// startOne claims and starts one job of kind if a worker is free, reporting
+// whether it did.
+func (q *Queue) startOne(ctx context.Context, kind string, h *handler) bool {
+	select {
+	case h.sem <- struct{}{}:
+	default:
+		return false // pool busy
+	}
+	job, err := q.claim(ctx, kind, h.opts.Timeout)
+	if job == nil {
+		<-h.sem
+		if err != nil && ctx.Err() == nil {
+			q.logger.Error("claim job", "kind", kind, "error", err)
+		}
+		return false
+	}
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		defer func() { <-h.sem }()
+		q.run(ctx, h, *job)
+	}()
+	return true
+}
+
+// claim marks the oldest due job of a kind as running. Jobs left running
+// past their lease by a crashed process are claimed again.
+func (q *Queue) claim(ctx context.Context, kind string, lease time.Duration) (*Job, error) {
+	now := time.Now().UTC()
+	nowStr := now.Format(timeLayout)
+	var job Job
+	var lastErr, schedule sql.NullString
+	err := q.db.QueryRowContext(ctx,
+		`UPDATE jobs SET status = ?, attempts = attempts + 1, locked_until = ?
+		 WHERE id = (
+			SELECT id FROM jobs
+			WHERE kind = ? AND ((status = ? AND run_at <= ?) OR (status = ? AND locked_until < ?))
+			ORDER BY run_at, id LIMIT 1
+		 ) AND ((status = ? AND run_at <= ?) OR (status = ? AND locked_until < ?))
+		 RETURNING id, kind, payload, attempts, max_attempts, run_at, last_error, schedule, created_at`,
+		StatusRunning, now.Add(lease).Format(timeLayout),
+		kind, StatusPending, nowStr, StatusRunning, nowStr,
+		StatusPending, nowStr, StatusRunning, nowStr,
+	).Scan(&job.ID, &job.Kind, &job.Payload, &job.Attempts, &job.MaxAttempts, &job.RunAt, &lastErr, &schedule, &job.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	job.Status = StatusRunning
+	job.LastError = lastErr.String
+	job.Schedule = schedule.String
+	return &job, nil
+}
+
+// run executes one attempt and records its outcome. Outcomes are written
+// with a context that survives shutdown, so a drained job is not re-run.
+func (q *Queue) run(ctx context.Context, h *handler, job Job) {
+	jobCtx, cancel := context.WithTimeout(ctx, h.opts.Timeout)
+	defer cancel()
+	start := time.Now()
+	err := safeCall(jobCtx, h.fn, job)
+	persist := context.WithoutCancel(ctx)
+
+	switch {
+	case err == nil:
+		q.logger.Info("job done", "kind", job.Kind, "id", job.ID, "duration", time.Since(start))
+		q.finish(persist, job.ID,
+			`UPDATE jobs SET status = ?, locked_until = NULL, finished_at = ? WHERE id = ?`,
+			StatusDone, time.Now().UTC().Format(timeLayout), job.ID)
+	case ctx.Err() != nil:
+		q.logger.Warn("job interrupted by shutdown, requeued", "kind", job.Kind, "id", job.ID, "error", err)
+		q.finish(persist, job.ID,
+			`UPDATE jobs SET status = ?, attempts = attempts - 1, locked_until = NULL, last_error = ? WHERE id = ?`,
+			StatusPending, err.Error(), job.ID)
+	case job.Attempts >= job.MaxAttempts:
+		q.logger.Error("job failed, giving up", "kind", job.Kind, "id", job.ID, "attempts", job.Attempts, "error", err)
+		q.finish(persist, job.ID,
+			`UPDATE jobs SET status = ?, locked_until = NULL, last_error = ?, finished_at = ? WHERE id = ?`,
+			StatusFailed, err.Error(), time.Now().UTC().Format(timeLayout), job.ID)
+	default:
+		retryAt := time.Now().Add(h.opts.Backoff(job.Attempts))
+		q.logger.Warn("job failed, will retry", "kind", job.Kind, "id", job.ID, "attempt", job.Attempts, "retry_at", retryAt, "error", err)
+		q.finish(persist, job.ID,
+			`UPDATE jobs SET status = ?, locked_until = NULL, last_error = ?, run_at = ? WHERE id = ?`,
+			StatusPending, err.Error(), retryAt.UTC().Format(timeLayout), job.ID)
+	}
+}
+
+func (q *Queue) finish(ctx context.Context, id int64, query string, args ...any) {
+	if _, err := q.db.ExecContext(ctx, query, args...); err != nil {
+		q.logger.Error("record job result", "id", id, "error", err)
+	}
+}
+
+// safeCall turns a handler panic into an error so one bad job cannot take
+// the process down.
+func safeCall(ctx context.Context, fn Handler, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx, job)
+}
+
+// Backoff is the delay before retrying after the given failed attempt:
+// 30s doubling each time, capped at one hour.
+func Backoff(attempt int) time.Duration {
+	d := 30 * time.Second
+	for i := 1; i < attempt && d < time.Hour; i++ {
+		d *= 2
+	}
+	return min(d, time.Hour)
+}
+
+// prune deletes finished jobs older than the retention period. Failed
+// jobs are kept for inspection.
+func (q *Queue) prune(ctx context.Context) {
+	cutoff := time.Now().Add(-q.retention).UTC().Format(timeLayout)
+	if _, err := q.db.ExecContext(ctx,
+		`DELETE FROM jobs WHERE status = ? AND finished_at < ?`, StatusDone, cutoff); err != nil && ctx.Err() == nil {
+		q.logger.Warn("prune jobs", "error", err)
+	}
+}
+
+// ScheduleInfo is a recurring job as stored.
+type ScheduleInfo struct {
+	Name      string
+	Kind      string
+	Spec      string
+	NextRunAt time.Time
+	LastRunAt time.Time // zero if it never ran
+}
+
+// Schedules lists the recurring jobs by name.
+func (q *Queue) Schedules(ctx context.Context) ([]ScheduleInfo, error) {
+	rows, err := q.db.QueryContext(ctx,
+		`SELECT name, kind, spec, next_run_at, last_run_at FROM job_schedules ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []ScheduleInfo
+	for rows.Next() {
+		var s ScheduleInfo
+		var last sql.NullTime
+		if err := rows.Scan(&s.Name, &s.Kind, &s.Spec, &s.NextRunAt, &last); err != nil {
+			return nil, err
+		}
+		s.LastRunAt = last.Time
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// Recent lists the newest jobs, optionally only those with a status.
+func (q *Queue) Recent(ctx context.Context, status string, limit int) ([]Job, error) {
+	return q.RecentKinds(ctx, "", status, limit)
+}
+
+// RecentKinds lists the newest jobs whose kind starts with prefix, such as
+// "notify.", optionally only those with a status.
+func (q *Queue) RecentKinds(ctx context.Context, prefix, status string, limit int) ([]Job, error) {
+	query := `SELECT id, kind, payload, status, attempts, max_attempts, run_at, last_error, schedule, created_at FROM jobs
+		WHERE substr(kind, 1, ?) = ?`
+	args := []any{len(prefix), prefix}
+	if status != "" {
+		query += ` AND status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Job
+	for rows.Next() {
+		var j Job
+		var lastErr, schedule sql.NullString
+		if err := rows.Scan(&j.ID, &j.Kind, &j.Payload, &j.Status, &j.Attempts, &j.MaxAttempts, &j.RunAt, &lastErr, &schedule, &j.CreatedAt); err != nil {
+			return nil, err
+		}
+		j.LastError = lastErr.String
+		j.Schedule = schedule.String
+		out = append(out, j)
+	}
+	return out, rows.Err()
+}
+
+// Retry puts a failed job back in the queue with fresh attempts.
+func (q *Queue) Retry(ctx context.Context, id int64) error {
+	res, err := q.db.ExecContext(ctx,
+		`UPDATE jobs SET status = ?, attempts = 0, run_at = ?, finished_at = NULL WHERE id = ? AND status = ?`,
+		StatusPending, time.Now().UTC().Format(timeLayout), id, StatusFailed)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("job %d not found or not failed", id)
+	}
+	return nil
+}
+
+// RetryKinds puts every failed job whose kind starts with prefix back in the
+// queue with fresh attempts and returns how many there were.
+func (q *Queue) RetryKinds(ctx context.Context, prefix string) (int64, error) {
+	res, err := q.db.ExecContext(ctx,
+		`UPDATE jobs SET status = ?, attempts = 0, run_at = ?, finished_at = NULL WHERE substr(kind, 1, ?) = ? AND status = ?`,
+		StatusPending, time.Now().UTC().Format(timeLayout), len(prefix), prefix, StatusFailed)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,294 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
+)
+
+// openTestDB opens a migrated in-memory SQLite database shared by the
+// pool's connections, removed when the test ends.
+func openTestDB(t *testing.T) *storage.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
+	db, err := storage.Open(storage.Config{Driver: storage.SQLite, DSN: dsn})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// getJob reads a job's stored state.
+func getJob(t *testing.T, db *storage.DB, id int64) Job {
+	t.Helper()
+	j := Job{ID: id}
+	var lastErr sql.NullString
+	err := db.QueryRowContext(context.Background(),
+		`SELECT kind, status, attempts, max_attempts, run_at, last_error FROM jobs WHERE id = ?`, id).
+		Scan(&j.Kind, &j.Status, &j.Attempts, &j.MaxAttempts, &j.RunAt, &lastErr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.LastError = lastErr.String
+	return j
+}
+
+func TestClaimExclusive(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	const n = 30
+	q := New(db)
+	for i := 0; i < n; i++ {
+		if _, err := q.Enqueue(ctx, Job{Kind: "k"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := q.Enqueue(ctx, Job{Kind: "other"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two processes, four workers each, claim until nothing is left
+	var mu sync.Mutex
+	claimed := make(map[int64]int)
+	var wg sync.WaitGroup
+	for _, q := range []*Queue{New(db), New(db)} {
+		for w := 0; w < 4; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					job, err := q.claim(ctx, "k", time.Minute)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					if job == nil {
+						return
+					}
+					if job.Kind != "k" || job.Status != StatusRunning || job.Attempts != 1 {
+						t.Errorf("claimed %+v", job)
+					}
+					mu.Lock()
+					claimed[job.ID]++
+					mu.Unlock()
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	if len(claimed) != n {
+		t.Fatalf("claimed %d jobs, want %d", len(claimed), n)
+	}
+	for id, times := range claimed {
+		if times != 1 {
+			t.Errorf("job %d claimed %d times", id, times)
+		}
+	}
+}
+
+func TestRunRetriesWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	q := New(db)
+	q.Handle("k", HandlerOptions{MaxAttempts: 3, Backoff: func(attempt int) time.Duration {
+		return time.Duration(attempt) * time.Hour
+	}}, func(context.Context, Job) error {
+		return errors.New("boom")
+	})
+	id, err := q.Enqueue(ctx, Job{Kind: "k"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		if _, err := db.ExecContext(ctx, `UPDATE jobs SET run_at = ? WHERE id = ?`, "2000-01-01 00:00:00", id); err != nil {
+			t.Fatal(err)
+		}
+		job, err := q.claim(ctx, "k", time.Minute)
+		if err != nil || job == nil {
+			t.Fatalf("attempt %d: claim = %v, %v", attempt, job, err)
+		}
+		q.run(ctx, q.handlers["k"], *job)
+
+		got := getJob(t, db, id)
+		if got.Attempts != attempt || got.LastError != "boom" {
+			t.Fatalf("attempt %d: job = %+v", attempt, got)
+		}
+		if attempt < 3 {
+			want := time.Now().Add(time.Duration(attempt) * time.Hour)
+			if got.Status != StatusPending || got.RunAt.Sub(want).Abs() > 5*time.Second {
+				t.Fatalf("attempt %d: job = %+v, want pending until about %s", attempt, got, want.UTC())
+			}
+		} else if got.Status != StatusFailed {
+			t.Fatalf("after the last attempt: job = %+v, want failed", got)
+		}
+	}
+
+	// A retried job starts over
+	if err := q.Retry(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if got := getJob(t, db, id); got.Status != StatusPending || got.Attempts != 0 {
+		t.Fatalf("retried job = %+v", got)
+	}
+	if err := q.Retry(ctx, id); err == nil {
+		t.Error("expected retrying a pending job to fail")
+	}
+}
+
+func TestRunShutdownKeepsAttempt(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	db := openTestDB(t)
+	q := New(db)
+	q.Handle("k", HandlerOptions{}, func(ctx context.Context, _ Job) error {
+		cancel()
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	id, err := q.Enqueue(ctx, Job{Kind: "k"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err := q.claim(ctx, "k", time.Minute)
+	if err != nil || job == nil {
+		t.Fatalf("claim = %v, %v", job, err)
+	}
+	q.run(ctx, q.handlers["k"], *job)
+	if got := getJob(t, db, id); got.Status != StatusPending || got.Attempts != 0 {
+		t.Fatalf("interrupted job = %+v, want pending with no attempt used", got)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt, want := range map[int]time.Duration{1: 30 * time.Second, 2: time.Minute, 4: 4 * time.Minute, 8: time.Hour, 20: time.Hour} {
+		if got := Backoff(attempt); got != want {
+			t.Errorf("Backoff(%d) = %s, want %s", attempt, got, want)
+		}
+	}
+}
+
+func TestClaimLeaseExpiry(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	q := New(db)
+	id, err := q.Enqueue(ctx, Job{Kind: "k"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job, err := q.claim(ctx, "k", time.Minute); err != nil || job == nil || job.ID != id {
+		t.Fatalf("claim = %v, %v", job, err)
+	}
+
+	// Running within its lease, the job is not claimed again
+	if job, err := q.claim(ctx, "k", time.Minute); err != nil || job != nil {
+		t.Fatalf("claim during the lease = %v, %v", job, err)
+	}
+
+	// Its worker crashed: once the lease runs out another worker takes it
+	if _, err := db.ExecContext(ctx, `UPDATE jobs SET locked_until = ? WHERE id = ?`, "2000-01-01 00:00:00", id); err != nil {
+		t.Fatal(err)
+	}
+	job, err := q.claim(ctx, "k", time.Minute)
+	if err != nil || job == nil || job.ID != id || job.Attempts != 2 {
+		t.Fatalf("claim after the lease = %+v, %v; want job %d on attempt 2", job, err, id)
+	}
+}
+
+func TestScheduleKeepsNextRun(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	nextRun := func() time.Time {
+		t.Helper()
+		var next time.Time
+		if err := db.QueryRowContext(ctx, `SELECT next_run_at FROM job_schedules WHERE name = ?`, "digest").Scan(&next); err != nil {
+			t.Fatal(err)
+		}
+		return next
+	}
+	r := Recurring{Name: "digest", Spec: "@every 1h", Kind: "k"}
+
+	if err := New(db).Schedule(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := nextRun(), time.Now().Add(time.Hour); got.Sub(want).Abs() > 5*time.Second {
+		t.Fatalf("first next run = %s, want about %s", got, want.UTC())
+	}
+
+	// A restart re-registers the unchanged schedule and keeps its next run
+	kept := time.Date(2030, 1, 2, 3, 4, 0, 0, time.UTC)
+	if _, err := db.ExecContext(ctx, `UPDATE job_schedules SET next_run_at = ?`, kept.Format(timeLayout)); err != nil {
+		t.Fatal(err)
+	}
+	if err := New(db).Schedule(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	if got := nextRun(); !got.Equal(kept) {
+		t.Fatalf("next run after restart = %s, want %s", got, kept)
+	}
+
+	// A changed spec starts over from it
+	r.Spec = "@every 2h"
+	if err := New(db).Schedule(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := nextRun(), time.Now().Add(2*time.Hour); got.Sub(want).Abs() > 5*time.Second {
+		t.Fatalf("next run after a spec change = %s, want about %s", got, want.UTC())
+	}
+
+	// RunOnStart runs now however the schedule was left
+	r.RunOnStart = true
+	if err := New(db).Schedule(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	if got := nextRun(); time.Until(got) > 5*time.Second {
+		t.Fatalf("next run with RunOnStart = %s, want now", got)
+	}
+
+	if err := New(db).Schedule(ctx, Recurring{Name: "bad", Spec: "every hour", Kind: "k"}); err == nil {
+		t.Error("expected an invalid spec to be rejected")
+	}
+}
+
+func TestEnqueueDueOncePerRun(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	q := New(db)
+	if err := q.Schedule(ctx, Recurring{Name: "digest", Spec: "@every 1h", Kind: "k", RunOnStart: true}); err != nil {
+		t.Fatal(err)
+	}
+	pending := func() int {
+		t.Helper()
+		jobs, err := q.Recent(ctx, StatusPending, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(jobs)
+	}
+
+	q.enqueueDue(ctx)
+	q.enqueueDue(ctx) // not due again until the next run
+	if n := pending(); n != 1 {
+		t.Fatalf("pending after the first run = %d, want 1", n)
+	}
+
+	// Come due again while the last run still waits, the schedule does not
+	// pile up runs
+	if _, err := db.ExecContext(ctx, `UPDATE job_schedules SET next_run_at = ?`, "2000-01-01 00:00:00"); err != nil {
+		t.Fatal(err)
+	}
+	q.enqueueDue(ctx)
+	if n := pending(); n != 1 {
+		t.Fatalf("pending after a second run = %d, want 1", n)
+	}
+}
@@ -0,0 +1,54 @@
+package llm
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultAzureAPIVersion is the Azure OpenAI API version used when
+// Config.APIVersion is empty.
+const DefaultAzureAPIVersion = "2024-10-21"
+
+// newAzureClient creates a client for an Azure OpenAI deployment. Azure
+// serves the OpenAI chat completions API under a per-deployment URL,
+// authenticated with an api-key header:
+//
+//	{BaseURL}/openai/deployments/{Deployment}/chat/completions?api-version={APIVersion}
+//
+// BaseURL is the resource endpoint, e.g. https://my-resource.openai.azure.com.
+// Deployment defaults to Model, since deployments are often named after the
+// model they serve.
+func newAzureClient(cfg Config) (Client, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("Azure OpenAI API key is required")
+	}
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("Azure OpenAI endpoint (base URL) is required")
+	}
+	deployment := cfg.Deployment
+	if deployment == "" {
+		deployment = cfg.Model
+	}
+	if deployment == "" {
+		return nil, fmt.Errorf("Azure OpenAI deployment is required")
+	}
+	version := cfg.APIVersion
+	if version == "" {
+		version = DefaultAzureAPIVersion
+	}
+
+	base := strings.TrimRight(cfg.BaseURL, "/") + "/openai/deployments/" + url.PathEscape(deployment)
+	client := &openaiClient{
+		cfg:      cfg,
+		apiKey:   cfg.APIKey,
+		base:     base,
+		endpoint: base + "/chat/completions?" + url.Values{"api-version": {version}}.Encode(),
+		provider: Azure,
+		http: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+	}
+	return wrapWithRetry(client, cfg.MaxRetries), nil
+}
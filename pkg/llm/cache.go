@@ -0,0 +1,220 @@
+package llm
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/metrics"
+	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
+)
+
+// DefaultCacheTTL is how long cached responses are reused by default.
+const DefaultCacheTTL = 24 * time.Hour
+
+// Cache stores LLM responses by CacheKey. Implementations treat their own
+// failures as misses: a broken cache must never fail an LLM call.
+type Cache interface {
+	Get(ctx context.Context, key string) (*Response, bool)
+	Set(ctx context.Context, key string, resp *Response)
+}
+
+// CacheKey identifies a request to a provider and model: a SHA-256 of the
+// provider, model, system prompt, messages and generation parameters.
+func CacheKey(provider Provider, model string, req *Request) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", provider, model)
+	json.NewEncoder(h).Encode(req)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheClient answers repeated requests from a cache instead of the provider.
+type cacheClient struct {
+	inner Client
+	cache Cache
+	model string
+}
+
+// wrapWithCache wraps a client so that identical requests for the same model
+// are answered from cache. A nil cache returns client unchanged.
+func wrapWithCache(client Client, cache Cache, model string) Client {
+	if cache == nil {
+		return client
+	}
+	return &cacheClient{inner: client, cache: cache, model: model}
+}
+
+func (c *cacheClient) Generate(ctx context.Context, req *Request) (*Response, error) {
+	key := CacheKey(c.inner.Provider(), c.model, req)
+	if resp, ok := c.cache.Get(ctx, key); ok {
+		metrics.LLMCacheRequests.Inc("hit")
+		resp.Cached, resp.Cost, resp.LatencyMs = true, 0, 0
+		return resp, nil
+	}
+	metrics.LLMCacheRequests.Inc("miss")
+
+	resp, err := c.inner.Generate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Content != "" {
+		stored := *resp
+		c.cache.Set(context.WithoutCancel(ctx), key, &stored)
+	}
+	return resp, nil
+}
+
+func (c *cacheClient) GenerateJSON(ctx context.Context, req *Request, out any) error {
+	req.JSONMode = true
+	resp, err := c.Generate(ctx, req)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(resp.Content), out); err != nil {
+		return fmt.Errorf("failed to unmarshal JSON response: %w", err)
+	}
+	return nil
+}
+
+func (c *cacheClient) Provider() Provider {
+	return c.inner.Provider()
+}
+
+func (c *cacheClient) Close() error {
+	return c.inner.Close()
+}
+
+// MemoryCache is an in-process Cache that evicts the least recently used
+// response once it holds maxEntries.
+type MemoryCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	order   *list.List // of *memoryEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key     string
+	resp    Response
+	expires time.Time
+}
+
+// NewMemoryCache creates a MemoryCache holding up to maxEntries responses
+// (1000 if maxEntries <= 0) for ttl (DefaultCacheTTL if ttl <= 0).
+func NewMemoryCache(maxEntries int, ttl time.Duration) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = 1000
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &MemoryCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns a copy of the cached response for key.
+func (m *MemoryCache) Get(_ context.Context, key string) (*Response, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*memoryEntry)
+	if time.Now().After(e.expires) {
+		m.order.Remove(el)
+		delete(m.entries, key)
+		return nil, false
+	}
+	m.order.MoveToFront(el)
+	resp := e.resp
+	return &resp, true
+}
+
+// Set caches resp under key.
+func (m *MemoryCache) Set(_ context.Context, key string, resp *Response) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := &memoryEntry{key: key, resp: *resp, expires: time.Now().Add(m.ttl)}
+	if el, ok := m.entries[key]; ok {
+		el.Value = e
+		m.order.MoveToFront(el)
+		return
+	}
+	m.entries[key] = m.order.PushFront(e)
+	for m.order.Len() > m.maxEntries {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryEntry).key)
+	}
+}
+
+// SQLCache is a Cache in the llm_cache table, so responses survive across
+// runs, such as the daily newsbot digest and benchmark scrape.
+type SQLCache struct {
+	db  *storage.DB
+	ttl time.Duration
+}
+
+// NewSQLCache creates a SQLCache keeping responses for ttl
+// (DefaultCacheTTL if ttl <= 0). The database must be migrated.
+func NewSQLCache(db *storage.DB, ttl time.Duration) *SQLCache {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &SQLCache{db: db, ttl: ttl}
+}
+
+// Get returns the cached response for key unless it has expired.
+func (s *SQLCache) Get(ctx context.Context, key string) (*Response, bool) {
+	var resp Response
+	err := s.db.QueryRowContext(ctx,
+		`SELECT content, finish_reason, model, tokens_in, tokens_out FROM llm_cache
+		 WHERE cache_key = ? AND expires_at > ?`, key, time.Now().UTC()).
+		Scan(&resp.Content, &resp.FinishReason, &resp.Model, &resp.TokensIn, &resp.TokensOut)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.Warn("LLM cache lookup failed", "error", err)
+		}
+		return nil, false
+	}
+	return &resp, true
+}
+
+// Set caches resp under key, replacing any earlier response.
+func (s *SQLCache) Set(ctx context.Context, key string, resp *Response) {
+	now := time.Now().UTC()
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO llm_cache (cache_key, content, finish_reason, model, tokens_in, tokens_out, created_at, expires_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(cache_key) DO UPDATE SET content = excluded.content, finish_reason = excluded.finish_reason,
+		 model = excluded.model, tokens_in = excluded.tokens_in, tokens_out = excluded.tokens_out,
+		 created_at = excluded.created_at, expires_at = excluded.expires_at`,
+		key, resp.Content, resp.FinishReason, resp.Model, resp.TokensIn, resp.TokensOut, now, now.Add(s.ttl))
+	if err != nil {
+		slog.Warn("LLM cache store failed", "error", err)
+	}
+}
+
+// Prune deletes expired responses and returns how many there were.
+func (s *SQLCache) Prune(ctx context.Context) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM llm_cache WHERE expires_at <= ?`, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("prune LLM cache: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -1,8 +1,9 @@
 // Package llm provides a unified interface for interacting with multiple LLM providers.
-// It supports OpenAI, Gemini, Claude, and Ollama with automatic retries and cost tracking.
+// It supports OpenAI, Azure OpenAI, Gemini, Claude, and Ollama with automatic retries and cost tracking.
 package llm
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"time"
@@ -17,6 +18,7 @@ const (
 	Claude  Provider = "claude"
 	Ollama  Provider = "ollama"
 	MiniMax Provider = "minimax"
+	Azure   Provider = "azure"
 )
 
 // Config holds configuration for an LLM client.
@@ -29,6 +31,19 @@ type Config struct {
 	Timeout     time.Duration `yaml:"timeout" json:"timeout"`
 	MaxTokens   int           `yaml:"max_tokens" json:"max_tokens"`
 	Temperature float64       `yaml:"temperature" json:"temperature"`
+
+	// Deployment and APIVersion select the Azure OpenAI deployment, whose
+	// resource endpoint is BaseURL. Deployment defaults to Model and
+	// APIVersion to DefaultAzureAPIVersion.
+	Deployment string `yaml:"deployment,omitempty" json:"deployment,omitempty"`
+	APIVersion string `yaml:"api_version,omitempty" json:"api_version,omitempty"`
+
+	// EmbeddingModel is the model NewEmbedder uses, DefaultEmbeddingModel
+	// if empty. For Azure it is the embedding deployment.
+	EmbeddingModel string `yaml:"embedding_model,omitempty" json:"embedding_model,omitempty"`
+
+	// Cache, if set, answers identical requests without calling the provider.
+	Cache Cache `yaml:"-" json:"-"`
 }
 
 // DefaultConfig returns a Config with sensible defaults.
@@ -82,6 +97,7 @@ type Response struct {
 	Cost         float64 `json:"cost"`
 	Model        string  `json:"model"`
 	LatencyMs    int64   `json:"latency_ms"`
+	Cached       bool    `json:"cached,omitempty"` // answered from Config.Cache, at no cost
 }
 
 // NewClient creates a new LLM client based on the provided config.
@@ -93,23 +109,31 @@ func NewClient(cfg Config) (Client, error) {
 		cfg.Timeout = 30 * time.Second
 	}
 
+	var client Client
+	var err error
 	switch cfg.Provider {
 	case OpenAI:
-		return newOpenAIClient(cfg)
+		client, err = newOpenAIClient(cfg)
 	case Gemini:
-		return newGeminiClient(cfg)
+		client, err = newGeminiClient(cfg)
 	case Claude:
-		return newClaudeClient(cfg)
+		client, err = newClaudeClient(cfg)
 	case Ollama:
-		return newOllamaClient(cfg)
+		client, err = newOllamaClient(cfg)
 	case MiniMax:
 		if cfg.BaseURL == "" {
 			cfg.BaseURL = "https://api.minimax.io/v1"
 		}
-		return newOpenAIClient(cfg)
+		client, err = newOpenAIClient(cfg)
+	case Azure:
+		client, err = newAzureClient(cfg)
 	default:
 		return nil, fmt.Errorf("unsupported LLM provider: %s", cfg.Provider)
 	}
+	if err != nil {
+		return nil, err
+	}
+	return wrapWithCache(client, cfg.Cache, cmp.Or(cfg.Deployment, cfg.Model)), nil
 }
 
 // SimpleGenerate is a convenience function for quick one-shot generation.
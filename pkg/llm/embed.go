@@ -0,0 +1,329 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrEmbeddingsUnsupported is returned by NewEmbedder for providers without
+// an embeddings API, such as Claude and MiniMax.
+var ErrEmbeddingsUnsupported = errors.New("provider has no embeddings API")
+
+// Embedder turns texts into embedding vectors, whose cosine similarity
+// measures how alike the texts are.
+type Embedder interface {
+	// Embed returns one vector per text, in order.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+
+	// Provider returns the name of the provider.
+	Provider() Provider
+}
+
+// DefaultEmbeddingModel returns the embedding model used for a provider
+// when Config.EmbeddingModel is empty.
+func DefaultEmbeddingModel(p Provider) string {
+	switch p {
+	case OpenAI, Azure:
+		return "text-embedding-3-small"
+	case Gemini:
+		return "gemini-embedding-001"
+	case Ollama:
+		return "nomic-embed-text"
+	}
+	return ""
+}
+
+// NewEmbedder creates an Embedder for cfg's provider, using
+// cfg.EmbeddingModel or the provider's DefaultEmbeddingModel. For Azure,
+// the embedding model is also the deployment name. Providers without an
+// embeddings API return ErrEmbeddingsUnsupported.
+func NewEmbedder(cfg Config) (Embedder, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	model := cfg.EmbeddingModel
+	if model == "" {
+		model = DefaultEmbeddingModel(cfg.Provider)
+	}
+	httpClient := &http.Client{Timeout: cfg.Timeout}
+
+	var e Embedder
+	switch cfg.Provider {
+	case OpenAI:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("OpenAI API key is required")
+		}
+		base := "https://api.openai.com/v1"
+		if cfg.BaseURL != "" {
+			base = cfg.BaseURL
+		}
+		e = &openaiEmbedder{http: httpClient, apiKey: cfg.APIKey, model: model, endpoint: base + "/embeddings", provider: OpenAI}
+	case Azure:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("Azure OpenAI API key is required")
+		}
+		if cfg.BaseURL == "" {
+			return nil, fmt.Errorf("Azure OpenAI endpoint (base URL) is required")
+		}
+		version := cfg.APIVersion
+		if version == "" {
+			version = DefaultAzureAPIVersion
+		}
+		endpoint := strings.TrimRight(cfg.BaseURL, "/") + "/openai/deployments/" + url.PathEscape(model) +
+			"/embeddings?" + url.Values{"api-version": {version}}.Encode()
+		e = &openaiEmbedder{http: httpClient, apiKey: cfg.APIKey, model: model, endpoint: endpoint, provider: Azure}
+	case Gemini:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("Gemini API key is required")
+		}
+		base := "https://generativelanguage.googleapis.com/v1beta"
+		if cfg.BaseURL != "" {
+			base = cfg.BaseURL
+		}
+		e = &geminiEmbedder{http: httpClient, apiKey: cfg.APIKey, model: model, base: base}
+	case Ollama:
+		base := "http://localhost:11434"
+		if cfg.BaseURL != "" {
+			base = cfg.BaseURL
+		}
+		// No retry for local models
+		return &ollamaEmbedder{http: httpClient, model: model, base: base}, nil
+	case Claude, MiniMax:
+		return nil, fmt.Errorf("%s: %w", cfg.Provider, ErrEmbeddingsUnsupported)
+	default:
+		return nil, fmt.Errorf("unsupported LLM provider: %s", cfg.Provider)
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 3
+	}
+	return &retryEmbedder{inner: e, maxRetries: cfg.MaxRetries}, nil
+}
+
+// CosineSimilarity returns the cosine of the angle between a and b: 1 for
+// the same direction, 0 for unrelated. It is 0 if either is a zero vector
+// or their lengths differ.
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		x, y := float64(a[i]), float64(b[i])
+		dot += x * y
+		na += x * x
+		nb += y * y
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// embedBatchSize is the most texts sent in one request; Gemini's batch
+// endpoint accepts at most 100.
+const embedBatchSize = 100
+
+// embedBatches calls embed on texts in batches of embedBatchSize and checks
+// that each batch returns one vector per text.
+func embedBatches(texts []string, embed func(batch []string) ([][]float32, error)) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += embedBatchSize {
+		batch := texts[start:min(start+embedBatchSize, len(texts))]
+		vecs, err := embed(batch)
+		if err != nil {
+			return nil, err
+		}
+		if len(vecs) != len(batch) {
+			return nil, fmt.Errorf("got %d embeddings for %d texts", len(vecs), len(batch))
+		}
+		vectors = append(vectors, vecs...)
+	}
+	return vectors, nil
+}
+
+// postJSON posts body as JSON and decodes a 200 response into out. Other
+// statuses become errors carrying the status code, so retries see 429/5xx.
+func postJSON(ctx context.Context, client *http.Client, endpoint string, header http.Header, name string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header = header
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		// OpenAI and Gemini both report {"error": {"message": ...}}
+		var errResp struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(respBody, &errResp) == nil && errResp.Error.Message != "" {
+			return fmt.Errorf("%s embeddings error (%d): %s", name, resp.StatusCode, errResp.Error.Message)
+		}
+		return fmt.Errorf("%s embeddings error (%d): %s", name, resp.StatusCode, string(respBody))
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// openaiEmbedder calls the OpenAI embeddings API, or an Azure deployment
+// of it.
+type openaiEmbedder struct {
+	http     *http.Client
+	apiKey   string
+	model    string
+	endpoint string
+	provider Provider
+}
+
+func (e *openaiEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	header := http.Header{}
+	if e.provider == Azure {
+		header.Set("api-key", e.apiKey)
+	} else {
+		header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+	return embedBatches(texts, func(batch []string) ([][]float32, error) {
+		var resp struct {
+			Data []struct {
+				Index     int       `json:"index"`
+				Embedding []float32 `json:"embedding"`
+			} `json:"data"`
+		}
+		body := map[string]any{"model": e.model, "input": batch}
+		if err := postJSON(ctx, e.http, e.endpoint, header, "OpenAI", body, &resp); err != nil {
+			return nil, err
+		}
+		vecs := make([][]float32, len(batch))
+		for _, d := range resp.Data {
+			if d.Index >= 0 && d.Index < len(vecs) {
+				vecs[d.Index] = d.Embedding
+			}
+		}
+		for i, v := range vecs {
+			if v == nil {
+				return nil, fmt.Errorf("no embedding for input %d", i)
+			}
+		}
+		return vecs, nil
+	})
+}
+
+func (e *openaiEmbedder) Provider() Provider { return e.provider }
+
+// geminiEmbedder calls Gemini's batchEmbedContents.
+type geminiEmbedder struct {
+	http   *http.Client
+	apiKey string
+	model  string
+	base   string
+}
+
+func (e *geminiEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	type request struct {
+		Model   string        `json:"model"`
+		Content geminiContent `json:"content"`
+	}
+	endpoint := fmt.Sprintf("%s/models/%s:batchEmbedContents?key=%s", e.base, e.model, e.apiKey)
+	return embedBatches(texts, func(batch []string) ([][]float32, error) {
+		reqs := make([]request, len(batch))
+		for i, text := range batch {
+			reqs[i] = request{Model: "models/" + e.model, Content: geminiContent{Parts: []geminiPart{{Text: text}}}}
+		}
+		var resp struct {
+			Embeddings []struct {
+				Values []float32 `json:"values"`
+			} `json:"embeddings"`
+		}
+		if err := postJSON(ctx, e.http, endpoint, http.Header{}, "Gemini", map[string]any{"requests": reqs}, &resp); err != nil {
+			return nil, err
+		}
+		vecs := make([][]float32, len(resp.Embeddings))
+		for i, emb := range resp.Embeddings {
+			vecs[i] = emb.Values
+		}
+		return vecs, nil
+	})
+}
+
+func (e *geminiEmbedder) Provider() Provider { return Gemini }
+
+// ollamaEmbedder calls a local Ollama's /api/embed.
+type ollamaEmbedder struct {
+	http  *http.Client
+	model string
+	base  string
+}
+
+func (e *ollamaEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return embedBatches(texts, func(batch []string) ([][]float32, error) {
+		var resp struct {
+			Embeddings [][]float32 `json:"embeddings"`
+		}
+		body := map[string]any{"model": e.model, "input": batch}
+		if err := postJSON(ctx, e.http, e.base+"/api/embed", http.Header{}, "Ollama", body, &resp); err != nil {
+			return nil, err
+		}
+		return resp.Embeddings, nil
+	})
+}
+
+func (e *ollamaEmbedder) Provider() Provider { return Ollama }
+
+// retryEmbedder retries failed embedding requests with the same backoff
+// and retryable errors as retryClient.
+type retryEmbedder struct {
+	inner      Embedder
+	maxRetries int
+}
+
+func (r *retryEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	backoff := retryClient{baseDelay: 1 * time.Second}
+	var lastErr error
+	for attempt := 0; attempt < r.maxRetries; attempt++ {
+		vecs, err := r.inner.Embed(ctx, texts)
+		if err == nil {
+			return vecs, nil
+		}
+		lastErr = err
+		if !isRetryableError(err) {
+			return nil, err
+		}
+		delay := backoff.backoffDelay(attempt)
+		slog.Warn("embedding request failed, retrying", "attempt", attempt+1, "backoff", delay.Round(time.Millisecond), "error", err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+	return nil, fmt.Errorf("max retries (%d) exceeded: %w", r.maxRetries, lastErr)
+}
+
+func (r *retryEmbedder) Provider() Provider { return r.inner.Provider() }
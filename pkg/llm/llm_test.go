@@ -2,7 +2,17 @@ package llm
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/storage"
+	_ "modernc.org/sqlite"
 )
 
 func TestNewClient_InvalidProvider(t *testing.T) {
@@ -139,3 +149,270 @@ func TestStripThinkTags(t *testing.T) {
 		})
 	}
 }
+
+type memoryLedger struct {
+	usage []Usage
+	over  map[int]bool
+}
+
+func (l *memoryLedger) CheckQuota(ctx context.Context, userID int) error {
+	if l.over[userID] {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+func (l *memoryLedger) RecordUsage(ctx context.Context, u Usage) error {
+	l.usage = append(l.usage, u)
+	return nil
+}
+
+func TestWithUsage(t *testing.T) {
+	calls := 0
+	mock := &mockClient{
+		generateFn: func(ctx context.Context, req *Request) (*Response, error) {
+			calls++
+			return &Response{Content: "{}", Model: "gpt-4o-mini", TokensIn: 1000, TokensOut: 500}, nil
+		},
+	}
+	ledger := &memoryLedger{over: map[int]bool{2: true}}
+	client := WithUsage(mock, ledger)
+
+	ctx := ForUser(context.Background(), 1, "watchbot.analysis")
+	var out map[string]any
+	if err := client.GenerateJSON(ctx, &Request{}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Generate(context.Background(), &Request{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(ledger.usage) != 2 {
+		t.Fatalf("expected 2 recorded calls, got %d", len(ledger.usage))
+	}
+	u := ledger.usage[0]
+	if u.UserID != 1 || u.Feature != "watchbot.analysis" || u.Provider != "mock" || u.TokensIn != 1000 || u.Cost <= 0 {
+		t.Fatalf("unexpected usage record: %+v", u)
+	}
+	if ledger.usage[1].UserID != 0 {
+		t.Fatalf("expected unattributed call to record user 0, got %d", ledger.usage[1].UserID)
+	}
+
+	_, err := client.Generate(ForUser(context.Background(), 2, "watchbot.resolve"), &Request{})
+	if !errors.Is(err, ErrQuotaExceeded) || calls != 2 {
+		t.Fatalf("expected over-quota call to fail before the provider, got %v after %d calls", err, calls)
+	}
+}
+
+func TestNewClient_Azure(t *testing.T) {
+	if _, err := NewClient(Config{Provider: Azure, APIKey: "k", Model: "gpt-4o"}); err == nil {
+		t.Fatal("expected error without an endpoint")
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/deployments/prod-4o/chat/completions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if v := r.URL.Query().Get("api-version"); v != DefaultAzureAPIVersion {
+			t.Errorf("api-version = %q", v)
+		}
+		if r.Header.Get("api-key") != "azure-key" || r.Header.Get("Authorization") != "" {
+			t.Errorf("auth headers = %v", r.Header)
+		}
+		w.Write([]byte(`{"model":"gpt-4o-2024-08-06","choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}],"usage":{"prompt_tokens":3,"completion_tokens":1}}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(Config{Provider: Azure, APIKey: "azure-key", BaseURL: srv.URL + "/", Model: "gpt-4o", Deployment: "prod-4o"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer client.Close()
+	if client.Provider() != Azure {
+		t.Fatalf("expected Azure provider, got %s", client.Provider())
+	}
+	resp, err := client.Generate(context.Background(), &Request{Messages: []Message{{Role: "user", Content: "hello"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Content != "hi" || resp.TokensIn != 3 {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
+
+func TestCacheClient(t *testing.T) {
+	calls := 0
+	mock := &mockClient{
+		generateFn: func(ctx context.Context, req *Request) (*Response, error) {
+			calls++
+			return &Response{Content: "{\"ok\":true}", Model: "gpt-4o-mini", TokensIn: 1000, TokensOut: 500}, nil
+		},
+	}
+	ledger := &memoryLedger{}
+	client := WithUsage(wrapWithCache(mock, NewMemoryCache(10, time.Hour), "gpt-4o-mini"), ledger)
+
+	req := func() *Request { return &Request{Messages: []Message{{Role: "user", Content: "translate"}}} }
+	first, err := client.Generate(context.Background(), req())
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := client.Generate(context.Background(), req())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 || first.Cached || !second.Cached || second.Content != first.Content || second.Cost != 0 {
+		t.Fatalf("expected the repeat to be served from cache: calls=%d first=%+v second=%+v", calls, first, second)
+	}
+	if len(ledger.usage) != 1 {
+		t.Fatalf("expected only the provider call to be recorded, got %d", len(ledger.usage))
+	}
+
+	// JSON mode and other models are different requests
+	var out map[string]any
+	if err := client.GenerateJSON(context.Background(), req(), &out); err != nil || out["ok"] != true {
+		t.Fatalf("GenerateJSON = %v, %v", out, err)
+	}
+	if CacheKey(OpenAI, "gpt-4o-mini", req()) == CacheKey(OpenAI, "gpt-4o", req()) {
+		t.Fatal("expected the model to be part of the cache key")
+	}
+	if calls != 2 {
+		t.Fatalf("expected a JSON-mode request to miss the cache, got %d calls", calls)
+	}
+}
+
+func TestMemoryCache_Eviction(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(2, time.Hour)
+	c.Set(ctx, "a", &Response{Content: "a"})
+	c.Set(ctx, "b", &Response{Content: "b"})
+	c.Get(ctx, "a") // b is now least recently used
+	c.Set(ctx, "c", &Response{Content: "c"})
+	if _, ok := c.Get(ctx, "b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	if resp, ok := c.Get(ctx, "a"); !ok || resp.Content != "a" {
+		t.Errorf("expected a to be kept, got %v", resp)
+	}
+
+	expired := NewMemoryCache(2, time.Nanosecond)
+	expired.Set(ctx, "a", &Response{Content: "a"})
+	time.Sleep(time.Millisecond)
+	if _, ok := expired.Get(ctx, "a"); ok {
+		t.Error("expected an expired entry to miss")
+	}
+}
+
+func TestSQLCache(t *testing.T) {
+	ctx := context.Background()
+	db, err := storage.Open(storage.Config{Driver: storage.SQLite, DSN: filepath.Join(t.TempDir(), "cache.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewSQLCache(db, time.Hour)
+	if _, ok := c.Get(ctx, "k"); ok {
+		t.Fatal("expected a miss on an empty cache")
+	}
+	c.Set(ctx, "k", &Response{Content: "first", Model: "m", TokensIn: 3})
+	c.Set(ctx, "k", &Response{Content: "second", Model: "m", TokensIn: 4, TokensOut: 2})
+	resp, ok := c.Get(ctx, "k")
+	if !ok || resp.Content != "second" || resp.TokensIn != 4 || resp.TokensOut != 2 {
+		t.Fatalf("Get = %+v, %v", resp, ok)
+	}
+
+	stale := NewSQLCache(db, time.Nanosecond)
+	stale.Set(ctx, "old", &Response{Content: "old"})
+	time.Sleep(time.Millisecond)
+	if _, ok := c.Get(ctx, "old"); ok {
+		t.Error("expected an expired entry to miss")
+	}
+	if n, err := c.Prune(ctx); err != nil || n != 1 {
+		t.Errorf("Prune = %d, %v; want 1 expired entry", n, err)
+	}
+}
+
+func TestNewEmbedder_OpenAI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embeddings" || r.Header.Get("Authorization") != "Bearer k" {
+			t.Errorf("request %s %v", r.URL.Path, r.Header)
+		}
+		var req struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Model != "text-embedding-3-small" || len(req.Input) != 2 {
+			t.Errorf("unexpected request %+v", req)
+		}
+		// data may come back out of order; index says which input it is
+		w.Write([]byte(`{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}]}`))
+	}))
+	defer srv.Close()
+
+	e, err := NewEmbedder(Config{Provider: OpenAI, APIKey: "k", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vecs, err := e.Embed(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vecs) != 2 || vecs[0][0] != 1 || vecs[1][1] != 1 {
+		t.Fatalf("vectors = %v", vecs)
+	}
+}
+
+func TestNewEmbedder_Gemini(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/gemini-embedding-001:batchEmbedContents" || r.URL.Query().Get("key") != "k" {
+			t.Errorf("request %s", r.URL)
+		}
+		w.Write([]byte(`{"embeddings":[{"values":[0.5,0.5]}]}`))
+	}))
+	defer srv.Close()
+
+	e, err := NewEmbedder(Config{Provider: Gemini, APIKey: "k", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vecs, err := e.Embed(context.Background(), []string{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vecs) != 1 || len(vecs[0]) != 2 {
+		t.Fatalf("vectors = %v", vecs)
+	}
+
+	// A provider returning fewer vectors than texts is an error
+	if _, err := e.Embed(context.Background(), []string{"a", "b"}); err == nil {
+		t.Fatal("expected error for a missing embedding")
+	}
+}
+
+func TestNewEmbedder_Unsupported(t *testing.T) {
+	_, err := NewEmbedder(Config{Provider: Claude, APIKey: "k"})
+	if !errors.Is(err, ErrEmbeddingsUnsupported) {
+		t.Fatalf("expected ErrEmbeddingsUnsupported, got %v", err)
+	}
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b []float32
+		want float64
+	}{
+		{[]float32{1, 0}, []float32{2, 0}, 1},
+		{[]float32{1, 0}, []float32{0, 3}, 0},
+		{[]float32{1, 1}, []float32{-1, -1}, -1},
+		{[]float32{0, 0}, []float32{1, 0}, 0},
+		{[]float32{1}, []float32{1, 0}, 0},
+	}
+	for _, tt := range tests {
+		if got := CosineSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("CosineSimilarity(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -14,10 +14,12 @@ import (
 
 // openaiClient implements the Client interface for OpenAI-compatible APIs.
 type openaiClient struct {
-	cfg    Config
-	http   *http.Client
-	apiKey string
-	base   string
+	cfg      Config
+	http     *http.Client
+	apiKey   string
+	base     string
+	endpoint string   // chat completions URL
+	provider Provider // Azure authenticates with an api-key header
 }
 
 func newOpenAIClient(cfg Config) (Client, error) {
@@ -29,9 +31,11 @@ func newOpenAIClient(cfg Config) (Client, error) {
 		base = cfg.BaseURL
 	}
 	client := &openaiClient{
-		cfg:    cfg,
-		apiKey: cfg.APIKey,
-		base:   base,
+		cfg:      cfg,
+		apiKey:   cfg.APIKey,
+		base:     base,
+		endpoint: base + "/chat/completions",
+		provider: OpenAI,
 		http: &http.Client{
 			Timeout: cfg.Timeout,
 		},
@@ -118,12 +122,16 @@ func (c *openaiClient) Generate(ctx context.Context, req *Request) (*Response, e
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.base+"/chat/completions", bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if c.provider == Azure {
+		httpReq.Header.Set("api-key", c.apiKey)
+	} else {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
 
 	httpResp, err := c.http.Do(httpReq)
 	if err != nil {
@@ -179,7 +187,7 @@ func (c *openaiClient) GenerateJSON(ctx context.Context, req *Request, out any)
 }
 
 func (c *openaiClient) Provider() Provider {
-	return OpenAI
+	return c.provider
 }
 
 func (c *openaiClient) Close() error {
@@ -39,6 +39,9 @@ const (
 //	LLM_API_KEY     — API key
 //	LLM_MODEL       — fast tier model name (default)
 //	LLM_MODEL_PRO   — pro tier model name (falls back to LLM_MODEL)
+//	LLM_BASE_URL    — API base URL; the resource endpoint for azure
+//	LLM_DEPLOYMENT  — azure deployment name (falls back to the model)
+//	LLM_API_VERSION — azure API version
 func TierConfig(tier ModelTier) Config {
 	provider := Provider(getEnvDefault("LLM_PROVIDER", "gemini"))
 	apiKey := os.Getenv("LLM_API_KEY")
@@ -56,13 +59,16 @@ func TierConfig(tier ModelTier) Config {
 		Provider:    provider,
 		Model:       model,
 		APIKey:      apiKey,
+		BaseURL:     os.Getenv("LLM_BASE_URL"),
 		MaxRetries:  5,
 		Timeout:     90 * time.Second,
 		Temperature: 0.3,
+		Deployment:  os.Getenv("LLM_DEPLOYMENT"),
+		APIVersion:  os.Getenv("LLM_API_VERSION"),
 	}
 
 	// Provider-specific defaults
-	if provider == MiniMax {
+	if provider == MiniMax && cfg.BaseURL == "" {
 		cfg.BaseURL = "https://api.minimax.io/v1"
 	}
 
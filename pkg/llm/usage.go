@@ -0,0 +1,128 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/metrics"
+)
+
+// ErrQuotaExceeded is returned, wrapped, for calls on behalf of a user whose
+// plan allowance is used up.
+var ErrQuotaExceeded = errors.New("LLM usage quota exceeded")
+
+// Usage is the accounting record of one LLM call.
+type Usage struct {
+	UserID    int    // 0 for calls not made on behalf of a user
+	Feature   string // what the call was for, e.g. "watchbot.analysis"
+	Provider  Provider
+	Model     string
+	TokensIn  int
+	TokensOut int
+	Cost      float64 // USD, see EstimateCost
+	Time      time.Time
+}
+
+// UsageLedger stores usage and enforces per-user quotas.
+type UsageLedger interface {
+	// CheckQuota returns an error wrapping ErrQuotaExceeded when the user
+	// may not make more calls.
+	CheckQuota(ctx context.Context, userID int) error
+	RecordUsage(ctx context.Context, u Usage) error
+}
+
+type attributionKey struct{}
+
+type attribution struct {
+	userID  int
+	feature string
+}
+
+// ForUser returns a context that attributes the LLM calls made with it to a
+// user and feature. A userID of 0 attributes them to the system.
+func ForUser(ctx context.Context, userID int, feature string) context.Context {
+	return context.WithValue(ctx, attributionKey{}, attribution{userID: userID, feature: feature})
+}
+
+// usageClient records every call of the wrapped client in a ledger and
+// refuses calls for users over quota.
+type usageClient struct {
+	inner  Client
+	ledger UsageLedger
+}
+
+// WithUsage wraps client so that every call is recorded in ledger under the
+// user and feature set with ForUser, and in the LLM token and cost metrics.
+// Responses answered from cache are not recorded.
+// Calls for a user over quota fail with ErrQuotaExceeded before reaching the
+// provider. A nil ledger returns client unchanged.
+func WithUsage(client Client, ledger UsageLedger) Client {
+	if client == nil || ledger == nil {
+		return client
+	}
+	return &usageClient{inner: client, ledger: ledger}
+}
+
+func (c *usageClient) Generate(ctx context.Context, req *Request) (*Response, error) {
+	a, _ := ctx.Value(attributionKey{}).(attribution)
+	if a.userID != 0 {
+		if err := c.ledger.CheckQuota(ctx, a.userID); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := c.inner.Generate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Cached {
+		return resp, nil // nothing was spent
+	}
+	cost := resp.Cost
+	if cost == 0 {
+		cost = EstimateCost(resp.Model, resp.TokensIn, resp.TokensOut)
+	}
+	provider := string(c.inner.Provider())
+	metrics.LLMTokens.Add(float64(resp.TokensIn), provider, resp.Model, "input")
+	metrics.LLMTokens.Add(float64(resp.TokensOut), provider, resp.Model, "output")
+	metrics.LLMCost.Add(cost, provider, resp.Model)
+	u := Usage{
+		UserID:    a.userID,
+		Feature:   a.feature,
+		Provider:  c.inner.Provider(),
+		Model:     resp.Model,
+		TokensIn:  resp.TokensIn,
+		TokensOut: resp.TokensOut,
+		Cost:      cost,
+		Time:      time.Now().UTC(),
+	}
+	// Record even if the caller gave up meanwhile: the tokens were spent
+	if err := c.ledger.RecordUsage(context.WithoutCancel(ctx), u); err != nil {
+		slog.Warn("record LLM usage failed", "user", a.userID, "feature", a.feature, "error", err)
+	}
+	return resp, nil
+}
+
+func (c *usageClient) GenerateJSON(ctx context.Context, req *Request, out any) error {
+	req.JSONMode = true
+	resp, err := c.Generate(ctx, req)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(resp.Content), out); err != nil {
+		return fmt.Errorf("failed to unmarshal JSON response: %w", err)
+	}
+	return nil
+}
+
+func (c *usageClient) Provider() Provider {
+	return c.inner.Provider()
+}
+
+func (c *usageClient) Close() error {
+	return c.inner.Close()
+}
@@ -0,0 +1,140 @@
+package mcpserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Token scopes for the HTTP transport. A token may hold several.
+const (
+	ScopeAll       = "*"          // everything
+	ScopeMCP       = "mcp"        // the /mcp endpoint, except tool calls
+	ScopeToolsList = "tools:list" // GET /api/tools
+	ScopeToolsCall = "tools:call" // call any tool, over /mcp or /api/tools/{name}
+)
+
+// ToolScope is the scope that allows calling only the named tool.
+func ToolScope(name string) string { return ScopeToolsCall + ":" + name }
+
+// authToken is a configured bearer token, stored as its SHA-256 so tokens of
+// any length compare in constant time.
+type authToken struct {
+	hash   [sha256.Size]byte
+	scopes []string
+}
+
+// SetHTTPAuthToken requires a Bearer token on the HTTP transport's /mcp and
+// /api/tools endpoints and grants it every scope. /health stays open.
+func (s *Server) SetHTTPAuthToken(token string) {
+	s.AddHTTPAuthToken(token, ScopeAll)
+}
+
+// AddHTTPAuthToken accepts an additional Bearer token limited to scopes.
+func (s *Server) AddHTTPAuthToken(token string, scopes ...string) {
+	s.authMu.Lock()
+	defer s.authMu.Unlock()
+	s.authTokens = append(s.authTokens, authToken{hash: sha256.Sum256([]byte(token)), scopes: scopes})
+}
+
+// authScopes returns the scopes of the request's Bearer token. ok is false
+// if tokens are configured and the request has none or an unknown one.
+// Without configured tokens every request has ScopeAll.
+func (s *Server) authScopes(r *http.Request) (scopes []string, ok bool) {
+	s.authMu.RLock()
+	defer s.authMu.RUnlock()
+	if len(s.authTokens) == 0 {
+		return []string{ScopeAll}, true
+	}
+
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || token == "" {
+		return nil, false
+	}
+	hash := sha256.Sum256([]byte(token))
+	// Compare against every token so timing doesn't reveal which matched
+	for _, t := range s.authTokens {
+		if subtle.ConstantTimeCompare(hash[:], t.hash[:]) == 1 {
+			scopes, ok = t.scopes, true
+		}
+	}
+	return scopes, ok
+}
+
+func (s *Server) authRequired() bool {
+	s.authMu.RLock()
+	defer s.authMu.RUnlock()
+	return len(s.authTokens) > 0
+}
+
+func fixedScope(scope string) func(*http.Request) string {
+	return func(*http.Request) string { return scope }
+}
+
+// hasScope reports whether scopes grant want. The tools:call scope also
+// grants every per-tool scope.
+func hasScope(scopes []string, want string) bool {
+	if slices.Contains(scopes, ScopeAll) || slices.Contains(scopes, want) {
+		return true
+	}
+	return strings.HasPrefix(want, ScopeToolsCall+":") && slices.Contains(scopes, ScopeToolsCall)
+}
+
+// authMiddleware rejects requests without a valid token (401) or without
+// the scope the route requires (403).
+func (hs *HTTPServer) authMiddleware(scope func(r *http.Request) string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		scopes, ok := hs.server.authScopes(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mcp"`)
+			writeAuthError(w, http.StatusUnauthorized, "invalid or missing bearer token")
+			return
+		}
+		if want := scope(r); !hasScope(scopes, want) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mcp", error="insufficient_scope", scope="`+want+`"`)
+			writeAuthError(w, http.StatusForbidden, "token lacks scope "+want)
+			return
+		}
+		next(w, r.WithContext(withScopes(r.Context(), scopes)))
+	}
+}
+
+func writeAuthError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+type scopesKey struct{}
+
+func withScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, scopesKey{}, scopes)
+}
+
+// scopesFromContext returns the scopes authMiddleware attached, or ScopeAll
+// for requests that didn't pass through it.
+func scopesFromContext(ctx context.Context) []string {
+	if scopes, ok := ctx.Value(scopesKey{}).([]string); ok {
+		return scopes
+	}
+	return []string{ScopeAll}
+}
+
+// deniedTool returns the first tool a batch calls that scopes don't allow.
+func deniedTool(reqs []JSONRPCRequest, scopes []string) (string, bool) {
+	for _, req := range reqs {
+		if req.Method != "tools/call" {
+			continue
+		}
+		params, _ := req.Params.(map[string]any)
+		name, _ := params["name"].(string)
+		if !hasScope(scopes, ToolScope(name)) {
+			return name, true
+		}
+	}
+	return "", false
+}
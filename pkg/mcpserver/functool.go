@@ -0,0 +1,177 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RegisterFunc registers fn as a tool whose arguments are the struct T. The
+// input schema is derived from T's fields (see SchemaFor) and arguments are
+// unmarshaled into T before fn runs.
+func RegisterFunc[T any](s *Server, name, description string, fn func(ctx context.Context, args T) (*ToolCallResult, error)) {
+	s.RegisterTool(NewFuncTool(name, description, fn))
+}
+
+// FuncTool is a ToolHandler backed by a typed function; see RegisterFunc.
+type FuncTool[T any] struct {
+	BaseTool
+	required []string
+	fn       func(ctx context.Context, args T) (*ToolCallResult, error)
+}
+
+// NewFuncTool creates a typed tool without registering it, e.g. to set
+// BaseTool.ToolTimeout first. It panics if T is not a struct, as that is a
+// programming error.
+func NewFuncTool[T any](name, description string, fn func(ctx context.Context, args T) (*ToolCallResult, error)) *FuncTool[T] {
+	schema := SchemaFor[T]()
+	required, _ := schema["required"].([]string)
+	return &FuncTool[T]{
+		BaseTool: BaseTool{ToolName: name, ToolDescription: description, ToolSchema: schema},
+		required: required,
+		fn:       fn,
+	}
+}
+
+func (t *FuncTool[T]) Execute(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
+	for _, name := range t.required {
+		if _, ok := args[name]; !ok {
+			return nil, fmt.Errorf("missing required argument: %s", name)
+		}
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("encode arguments: %w", err)
+	}
+	var typed T
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	return t.fn(ctx, typed)
+}
+
+// SchemaFor derives a JSON Schema object from the struct T:
+//
+//   - property names come from the json tag, and fields tagged "-" are skipped;
+//   - a field is required unless it is a pointer or tagged omitempty;
+//   - `description:"..."` documents a field;
+//   - `jsonschema:"enum=a|b,default=a,minimum=0,maximum=10"` adds constraints,
+//     and `jsonschema:"required"` or `jsonschema:"optional"` overrides the
+//     required rule.
+func SchemaFor[T any]() map[string]any {
+	t := reflect.TypeFor[T]()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("mcpserver: tool arguments must be a struct, got %s", t))
+	}
+	return structSchema(t)
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+func structSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := typeSchema(f.Type)
+		if desc := f.Tag.Get("description"); desc != "" {
+			prop["description"] = desc
+		}
+		isRequired := f.Type.Kind() != reflect.Pointer && !strings.Contains(opts, "omitempty")
+		for _, rule := range strings.Split(f.Tag.Get("jsonschema"), ",") {
+			key, value, _ := strings.Cut(strings.TrimSpace(rule), "=")
+			switch key {
+			case "required":
+				isRequired = true
+			case "optional":
+				isRequired = false
+			case "enum":
+				prop["enum"] = strings.Split(value, "|")
+			case "default":
+				prop["default"] = schemaValue(prop["type"], value)
+			case "minimum", "maximum":
+				if n, err := strconv.ParseFloat(value, 64); err == nil {
+					prop[key] = n
+				}
+			}
+		}
+		properties[name] = prop
+		if isRequired {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func typeSchema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		return structSchema(t)
+	default:
+		return map[string]any{}
+	}
+}
+
+// schemaValue converts a tag's default value to the property's type.
+func schemaValue(typ any, value string) any {
+	switch typ {
+	case "integer":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
@@ -1,20 +1,25 @@
 package mcpserver
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
-// HTTPServer wraps the MCP Server to serve over HTTP with SSE support.
+// HTTPServer wraps the MCP Server to serve the Streamable HTTP transport.
 type HTTPServer struct {
-	server    *Server
-	addr      string
-	authToken string
-	logger    *slog.Logger
+	server *Server
+	addr   string
+	logger *slog.Logger
 }
 
 // RunHTTP starts the MCP server on an HTTP endpoint.
@@ -24,38 +29,52 @@ func (s *Server) RunHTTP(addr string) error {
 		addr:   addr,
 		logger: s.logger,
 	}
+	s.StartSessionJanitor(context.Background(), SessionJanitorInterval)
 	return hs.ListenAndServe()
 }
 
-// SetHTTPAuthToken sets a Bearer token for HTTP authentication.
-func (s *Server) SetHTTPAuthToken(token string) {
-	// Will be used by RunHTTP
+// HTTPHandler returns the HTTP transport as a handler, for mounting on an
+// existing server. Unlike RunHTTP it does not start the session janitor.
+func (s *Server) HTTPHandler() http.Handler {
+	hs := &HTTPServer{server: s, logger: s.logger}
+	return hs.Handler()
 }
 
 // ListenAndServe starts the HTTP server.
 func (hs *HTTPServer) ListenAndServe() error {
+	hs.logger.Info("starting HTTP server", "addr", hs.addr, "tools", len(hs.server.tools))
+	if !hs.server.authRequired() {
+		hs.logger.Warn("HTTP server has no auth token; anyone who can reach it can call tools")
+	}
+
+	return http.ListenAndServe(hs.addr, hs.Handler())
+}
+
+// Handler builds the transport's routes.
+func (hs *HTTPServer) Handler() http.Handler {
 	mux := http.NewServeMux()
 
 	// MCP protocol endpoint (JSON-RPC 2.0)
-	mux.HandleFunc("/mcp", hs.handleMCPRequest)
+	mux.HandleFunc("/mcp", hs.authMiddleware(fixedScope(ScopeMCP), hs.handleMCPRequest))
 
 	// RESTful endpoints
-	mux.HandleFunc("/api/tools", hs.handleToolsList)
-	mux.HandleFunc("/api/tools/", hs.handleToolCall)
+	mux.HandleFunc("/api/tools", hs.authMiddleware(fixedScope(ScopeToolsList), hs.handleToolsList))
+	mux.HandleFunc("/api/tools/", hs.authMiddleware(func(r *http.Request) string {
+		return ToolScope(strings.TrimPrefix(r.URL.Path, "/api/tools/"))
+	}, hs.handleToolCall))
 
 	// Health check
 	mux.HandleFunc("/health", hs.handleHealth)
 
-	hs.logger.Info("starting HTTP server", "addr", hs.addr, "tools", len(hs.server.tools))
-
-	return http.ListenAndServe(hs.addr, hs.corsMiddleware(mux))
+	return hs.corsMiddleware(mux)
 }
 
 func (hs *HTTPServer) corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Mcp-Session-Id")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Mcp-Session-Id, Mcp-Protocol-Version, Last-Event-ID")
+		w.Header().Set("Access-Control-Expose-Headers", "Mcp-Session-Id")
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
 			return
@@ -64,41 +83,130 @@ func (hs *HTTPServer) corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// handleMCPRequest implements the Streamable HTTP transport on one endpoint:
+// POST carries client messages, GET opens the session's event stream for
+// server-initiated messages, and DELETE ends the session.
 func (hs *HTTPServer) handleMCPRequest(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
+	if v := r.Header.Get("Mcp-Protocol-Version"); v != "" && !slices.Contains(SupportedProtocolVersions, v) {
+		http.Error(w, "Unsupported protocol version", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		hs.handleMCPPost(w, r)
+	case http.MethodGet:
+		hs.handleMCPStream(w, r)
+	case http.MethodDelete:
+		hs.handleMCPDelete(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (hs *HTTPServer) handleMCPPost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		hs.writeError(w, -32700, "Parse error")
 		return
 	}
 
-	var req JSONRPCRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	// A body is one message or a batch (JSON array) of them
+	var reqs []JSONRPCRequest
+	batch := len(bytes.TrimSpace(body)) > 0 && bytes.TrimSpace(body)[0] == '['
+	if batch {
+		err = json.Unmarshal(body, &reqs)
+	} else {
+		var req JSONRPCRequest
+		err = json.Unmarshal(body, &req)
+		reqs = []JSONRPCRequest{req}
+	}
+	if err != nil {
 		hs.writeError(w, -32700, "Parse error")
 		return
 	}
+	if len(reqs) == 0 {
+		hs.writeError(w, -32600, "Invalid Request")
+		return
+	}
+
+	if name, denied := deniedTool(reqs, scopesFromContext(r.Context())); denied {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="mcp", error="insufficient_scope", scope="`+ToolScope(name)+`"`)
+		writeAuthError(w, http.StatusForbidden, "token lacks scope "+ToolScope(name))
+		return
+	}
 
-	// Validate session for non-initialize requests
-	if req.Method != "initialize" {
-		sessionID := r.Header.Get("Mcp-Session-Id")
-		if sessionID == "" || !hs.server.CheckSession(sessionID) {
-			http.Error(w, "Session not found", http.StatusNotFound)
+	// Every message except a lone initialize needs a live session
+	initialize := !batch && reqs[0].Method == "initialize"
+	sessionID := r.Header.Get("Mcp-Session-Id")
+	if !initialize {
+		if sessionID == "" {
+			http.Error(w, "Mcp-Session-Id header required", http.StatusBadRequest)
+			return
+		}
+		if state := hs.server.LookupSession(sessionID); state != SessionValid {
+			hs.writeSessionError(w, reqs[0].ID, state)
 			return
 		}
 	}
 
-	resp := hs.server.HandleRequest(&req)
+	// Notifications raised while handling (e.g. progress) stream ahead of the
+	// responses when the client accepts SSE, else go to the session's stream
+	ctx := r.Context()
+	if sessionID != "" {
+		ctx = WithSessionID(ctx, sessionID)
+	}
+	var sw *sseWriter
+	if flusher, ok := w.(http.Flusher); ok && strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		sw = &sseWriter{w: w, flusher: flusher}
+		defer sw.close()
+		ctx = withNotifier(ctx, func(method string, params any) {
+			sw.send(JSONRPCRequest{JSONRPC: "2.0", Method: method, Params: params})
+		})
+	} else if sessionID != "" {
+		ctx = withNotifier(ctx, func(method string, params any) {
+			if err := hs.server.Notify(sessionID, method, params); err != nil {
+				hs.logger.Error("send notification", "method", method, "error", err)
+			}
+		})
+	}
+
+	var resps []*JSONRPCResponse
+	for i := range reqs {
+		// Client responses to server requests carry no method and need no reply
+		if reqs[i].Method == "" {
+			continue
+		}
+		resp := hs.server.HandleRequest(reqs[i].WithContext(ctx))
+		if resp == nil || reqs[i].ID == nil {
+			continue // notification
+		}
+		resps = append(resps, resp)
+	}
 
 	// Set session ID header for initialize response
-	if req.Method == "initialize" && resp != nil && resp.Error == nil {
-		if result, ok := resp.Result.(*InitializeResult); ok && result.SessionID != "" {
+	if initialize && len(resps) == 1 && resps[0].Error == nil {
+		if result, ok := resps[0].Result.(*InitializeResult); ok && result.SessionID != "" {
 			w.Header().Set("Mcp-Session-Id", result.SessionID)
 		}
 	}
 
+	if len(resps) == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
 	// Choose response format based on Accept header
-	if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
-		hs.sendSSE(w, resp)
+	if sw != nil {
+		for _, resp := range resps {
+			sw.send(resp)
+		}
+	} else if batch {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resps)
 	} else {
-		hs.sendJSON(w, resp)
+		hs.sendJSON(w, resps[0])
 	}
 }
 
@@ -107,23 +215,126 @@ func (hs *HTTPServer) sendJSON(w http.ResponseWriter, resp *JSONRPCResponse) {
 	json.NewEncoder(w).Encode(resp)
 }
 
-func (hs *HTTPServer) sendSSE(w http.ResponseWriter, resp *JSONRPCResponse) {
+// sseWriter streams a POST's messages as server-sent events. It may be
+// called from tools still running after the response ended, so it drops
+// messages once closed.
+type sseWriter struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	flusher http.Flusher
+	started bool
+	closed  bool
+}
+
+func (sw *sseWriter) send(msg any) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	if sw.closed {
+		return
+	}
+	if !sw.started {
+		setSSEHeaders(sw.w)
+		sw.started = true
+	}
+	fmt.Fprintf(sw.w, "event: message\ndata: %s\n\n", data)
+	sw.flusher.Flush()
+}
+
+func (sw *sseWriter) close() {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	sw.closed = true
+}
+
+// sseKeepAlive is how often an idle event stream sends a comment, which also
+// keeps its session from expiring.
+const sseKeepAlive = 30 * time.Second
+
+// handleMCPStream serves the session's event stream. A client that lost its
+// stream reconnects with Last-Event-ID to receive the messages it missed.
+func (hs *HTTPServer) handleMCPStream(w http.ResponseWriter, r *http.Request) {
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		http.Error(w, "Accept must include text/event-stream", http.StatusNotAcceptable)
+		return
+	}
+	sessionID := r.Header.Get("Mcp-Session-Id")
+	if sessionID == "" {
+		http.Error(w, "Mcp-Session-Id header required", http.StatusBadRequest)
+		return
+	}
+	if state := hs.server.LookupSession(sessionID); state != SessionValid {
+		hs.writeSessionError(w, nil, state)
+		return
+	}
 	flusher, ok := w.(http.Flusher)
 	if !ok {
-		hs.sendJSON(w, resp)
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
 		return
 	}
+	es, ok := hs.server.stream(sessionID)
+	if !ok {
+		hs.writeSessionError(w, nil, SessionExpired)
+		return
+	}
+
+	lastID, err := strconv.ParseUint(r.Header.Get("Last-Event-ID"), 10, 64)
+	events, replay := es.subscribe(lastID, err == nil)
+	defer es.unsubscribe(events)
+
+	setSSEHeaders(w)
+	w.WriteHeader(http.StatusOK)
+	for _, ev := range replay {
+		writeEvent(w, ev)
+	}
+	flusher.Flush()
 
+	ticker := time.NewTicker(sseKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return // session ended or a newer stream took over
+			}
+			writeEvent(w, ev)
+			flusher.Flush()
+		case <-ticker.C:
+			if !hs.server.CheckSession(sessionID) {
+				return
+			}
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func (hs *HTTPServer) handleMCPDelete(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.Header.Get("Mcp-Session-Id")
+	if sessionID == "" {
+		http.Error(w, "Mcp-Session-Id header required", http.StatusBadRequest)
+		return
+	}
+	if !hs.server.TerminateSession(sessionID) {
+		hs.writeSessionError(w, nil, SessionUnknown)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func setSSEHeaders(w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
+}
 
-	fmt.Fprintf(w, "event: endpoint\ndata: %s\n\n", "/mcp")
-	flusher.Flush()
-
-	respBytes, _ := json.Marshal(resp)
-	fmt.Fprintf(w, "data: %s\n\n", string(respBytes))
-	flusher.Flush()
+func writeEvent(w io.Writer, ev sseEvent) {
+	fmt.Fprintf(w, "id: %d\nevent: message\ndata: %s\n\n", ev.ID, ev.Data)
 }
 
 func (hs *HTTPServer) handleToolsList(w http.ResponseWriter, r *http.Request) {
@@ -158,7 +369,7 @@ func (hs *HTTPServer) handleToolCall(w http.ResponseWriter, r *http.Request) {
 		"name":      toolName,
 		"arguments": args,
 	}
-	result := hs.server.handleToolCall(callParams)
+	result := hs.server.handleToolCall(r.Context(), callParams)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(result)
 }
@@ -181,3 +392,31 @@ func (hs *HTTPServer) writeError(w http.ResponseWriter, code int, message string
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
+
+// ErrCodeSessionExpired is the JSON-RPC error code sent with a 404 when a
+// request names a session that expired or was evicted.
+const ErrCodeSessionExpired = -32001
+
+// writeSessionError answers a request for a dead session with 404. For
+// expired sessions the body carries a hint to re-initialize, since clients
+// otherwise can't tell a timeout from a bad ID.
+func (hs *HTTPServer) writeSessionError(w http.ResponseWriter, id any, state SessionState) {
+	message := "Session not found"
+	if state == SessionExpired {
+		message = "Session expired"
+	}
+	resp := JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error: &RPCError{
+			Code:    ErrCodeSessionExpired,
+			Message: message,
+			Data: map[string]any{
+				"hint": "send an initialize request without Mcp-Session-Id to start a new session",
+			},
+		},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -1,7 +1,16 @@
 package mcpserver_test
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/RobinCoderZhao/devkit-suite/pkg/mcpserver"
 )
@@ -30,7 +39,7 @@ func NewEchoTool() *EchoTool {
 	}
 }
 
-func (t *EchoTool) Execute(args map[string]any) (*mcpserver.ToolCallResult, error) {
+func (t *EchoTool) Execute(ctx context.Context, args map[string]any) (*mcpserver.ToolCallResult, error) {
 	msg, _ := args["message"].(string)
 	return mcpserver.TextResult("Echo: " + msg), nil
 }
@@ -180,6 +189,87 @@ func TestServer_Middleware(t *testing.T) {
 	}
 }
 
+// PanicTool panics when called.
+type PanicTool struct{ mcpserver.BaseTool }
+
+func (t *PanicTool) Execute(ctx context.Context, args map[string]any) (*mcpserver.ToolCallResult, error) {
+	panic("boom")
+}
+
+func TestMiddleware_RateLimit(t *testing.T) {
+	s := mcpserver.New("test-server", "1.0.0")
+	s.Use(mcpserver.RateLimitMiddleware(0.001, 2))
+
+	call := func(session string, id int) *mcpserver.JSONRPCResponse {
+		req := &mcpserver.JSONRPCRequest{JSONRPC: "2.0", ID: id, Method: "tools/list"}
+		return s.HandleRequest(req.WithContext(mcpserver.WithSessionID(context.Background(), session)))
+	}
+	for i := 1; i <= 2; i++ {
+		if resp := call("a", i); resp.Error != nil {
+			t.Fatalf("request %d within burst limited: %+v", i, resp.Error)
+		}
+	}
+	resp := call("a", 3)
+	if resp.Error == nil || resp.Error.Code != mcpserver.ErrCodeRateLimited {
+		t.Fatalf("third request = %+v, want rate limited", resp)
+	}
+	if data, _ := resp.Error.Data.(map[string]any); data["retryAfter"].(float64) < 1 {
+		t.Errorf("retryAfter = %v", resp.Error.Data)
+	}
+	if resp := call("b", 4); resp.Error != nil {
+		t.Errorf("other session limited: %+v", resp.Error)
+	}
+	// Initialize is never limited
+	init := &mcpserver.JSONRPCRequest{JSONRPC: "2.0", ID: 5, Method: "initialize"}
+	if resp := s.HandleRequest(init.WithContext(mcpserver.WithSessionID(context.Background(), "a"))); resp.Error != nil {
+		t.Errorf("initialize limited: %+v", resp.Error)
+	}
+}
+
+func TestMiddleware_LoggingAndRecovery(t *testing.T) {
+	var logs strings.Builder
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	s := mcpserver.New("test-server", "1.0.0")
+	s.Use(mcpserver.RecoveryMiddleware())
+	s.Use(mcpserver.LoggingMiddleware(logger))
+	s.RegisterTool(NewEchoTool())
+	s.RegisterTool(&PanicTool{mcpserver.BaseTool{ToolName: "panic", ToolSchema: map[string]any{"type": "object"}}})
+
+	req := &mcpserver.JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "tools/call",
+		Params: map[string]any{"name": "echo", "arguments": map[string]any{"message": "hi"}}}
+	s.HandleRequest(req.WithContext(mcpserver.WithSessionID(context.Background(), "sess-1")))
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(strings.SplitN(logs.String(), "\n", 2)[0]), &entry); err != nil {
+		t.Fatalf("log line: %v\n%s", err, logs.String())
+	}
+	if entry["msg"] != "mcp request" || entry["tool"] != "echo" || entry["session"] != "sess-1" || entry["duration"] == nil {
+		t.Errorf("log entry = %v", entry)
+	}
+
+	// A panicking tool fails the call instead of crashing the server
+	logs.Reset()
+	resp := s.HandleRequest(&mcpserver.JSONRPCRequest{JSONRPC: "2.0", ID: 2, Method: "tools/call",
+		Params: map[string]any{"name": "panic"}})
+	result, ok := resp.Result.(*mcpserver.ToolCallResult)
+	if !ok || !result.IsError || !strings.Contains(result.Content[0].Text, "panicked: boom") {
+		t.Fatalf("panic result = %+v", resp)
+	}
+	if !strings.Contains(logs.String(), `"msg":"mcp tool error"`) {
+		t.Errorf("tool error not logged:\n%s", logs.String())
+	}
+
+	// Panics elsewhere in the chain become internal errors
+	s.Use(func(next mcpserver.HandlerFunc) mcpserver.HandlerFunc {
+		return func(req *mcpserver.JSONRPCRequest) *mcpserver.JSONRPCResponse { panic("middleware") }
+	})
+	resp = s.HandleRequest(&mcpserver.JSONRPCRequest{JSONRPC: "2.0", ID: 3, Method: "tools/list"})
+	if resp.Error == nil || resp.Error.Code != -32603 {
+		t.Errorf("recovered response = %+v", resp)
+	}
+}
+
 func TestServer_Session(t *testing.T) {
 	s := mcpserver.New("test-server", "1.0.0")
 
@@ -197,3 +287,389 @@ func TestServer_Session(t *testing.T) {
 		t.Fatal("expected invalid session to fail")
 	}
 }
+
+func TestServer_SessionExpiry(t *testing.T) {
+	s := mcpserver.New("test-server", "1.0.0")
+	s.SetSessionTTL(20 * time.Millisecond)
+	var expired []string
+	s.OnSessionExpired(func(id string) { expired = append(expired, id) })
+
+	resp := s.HandleRequest(&mcpserver.JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "initialize"})
+	id := resp.Result.(*mcpserver.InitializeResult).SessionID
+
+	// Use keeps the session alive past its original deadline
+	for i := 0; i < 3; i++ {
+		time.Sleep(10 * time.Millisecond)
+		if !s.CheckSession(id) {
+			t.Fatalf("session expired despite use (round %d)", i)
+		}
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if n := s.CleanupSessions(); n != 1 {
+		t.Fatalf("cleaned up %d sessions, want 1", n)
+	}
+	if state := s.LookupSession(id); state != mcpserver.SessionExpired {
+		t.Fatalf("state = %v, want expired", state)
+	}
+	if len(expired) != 1 || expired[0] != id {
+		t.Fatalf("expiry hook got %v", expired)
+	}
+}
+
+func TestServer_MaxSessions(t *testing.T) {
+	s := mcpserver.New("test-server", "1.0.0")
+	s.SetMaxSessions(2)
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		resp := s.HandleRequest(&mcpserver.JSONRPCRequest{JSONRPC: "2.0", ID: i, Method: "initialize"})
+		ids = append(ids, resp.Result.(*mcpserver.InitializeResult).SessionID)
+		time.Sleep(time.Millisecond)
+	}
+	if n := s.SessionCount(); n != 2 {
+		t.Fatalf("session count = %d, want 2", n)
+	}
+	if s.LookupSession(ids[0]) != mcpserver.SessionExpired || !s.CheckSession(ids[2]) {
+		t.Fatal("expected the least recently used session to be evicted")
+	}
+}
+
+func TestHTTP_ExpiredSessionHint(t *testing.T) {
+	s := mcpserver.New("test-server", "1.0.0")
+	s.SetSessionTTL(time.Millisecond)
+	resp := s.HandleRequest(&mcpserver.JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "initialize"})
+	id := resp.Result.(*mcpserver.InitializeResult).SessionID
+	time.Sleep(5 * time.Millisecond)
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`))
+	req.Header.Set("Mcp-Session-Id", id)
+	rec := httptest.NewRecorder()
+	s.HTTPHandler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rec.Code)
+	}
+	var body mcpserver.JSONRPCResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Error == nil || body.Error.Code != mcpserver.ErrCodeSessionExpired || !strings.Contains(rec.Body.String(), "initialize") {
+		t.Fatalf("body = %s", rec.Body.String())
+	}
+}
+
+func TestHTTP_StreamableTransport(t *testing.T) {
+	s := mcpserver.New("test-server", "1.0.0")
+	s.RegisterTool(NewEchoTool())
+	ts := httptest.NewServer(s.HTTPHandler())
+	defer ts.Close()
+
+	post := func(sessionID, body string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, ts.URL+"/mcp", strings.NewReader(body))
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if sessionID != "" {
+			req.Header.Set("Mcp-Session-Id", sessionID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	resp := post("", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`)
+	id := resp.Header.Get("Mcp-Session-Id")
+	if id == "" {
+		t.Fatal("initialize returned no Mcp-Session-Id")
+	}
+	if resp := post(id, `{"jsonrpc":"2.0","method":"notifications/initialized"}`); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("notification status = %d, want 202", resp.StatusCode)
+	}
+	if resp := post("", `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("missing session status = %d, want 400", resp.StatusCode)
+	}
+
+	// Sent before the client listens: replayed on a resuming GET
+	if err := s.Notify(id, "notifications/message", map[string]any{"data": "queued"}); err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/mcp", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Mcp-Session-Id", id)
+	req.Header.Set("Last-Event-ID", "0")
+	stream, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Body.Close()
+	if ct := stream.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("stream content type = %q", ct)
+	}
+	events := bufio.NewScanner(stream.Body)
+	readData := func() string {
+		for events.Scan() {
+			if data, ok := strings.CutPrefix(events.Text(), "data: "); ok {
+				return data
+			}
+		}
+		t.Fatal("stream ended")
+		return ""
+	}
+	if data := readData(); !strings.Contains(data, "queued") {
+		t.Fatalf("replayed event = %s", data)
+	}
+	if err := s.Notify(id, "notifications/message", map[string]any{"data": "live"}); err != nil {
+		t.Fatal(err)
+	}
+	if data := readData(); !strings.Contains(data, "live") {
+		t.Fatalf("live event = %s", data)
+	}
+
+	del, _ := http.NewRequest(http.MethodDelete, ts.URL+"/mcp", nil)
+	del.Header.Set("Mcp-Session-Id", id)
+	if resp, err := http.DefaultClient.Do(del); err != nil || resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("delete = %v, %v", resp, err)
+	}
+	if resp := post(id, `{"jsonrpc":"2.0","id":3,"method":"tools/list"}`); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("terminated session status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestServer_Prompts(t *testing.T) {
+	s := mcpserver.New("test-server", "1.0.0")
+	review, err := mcpserver.NewTemplatePrompt("review", "Review a diff",
+		[]mcpserver.PromptArgument{{Name: "diff", Required: true}, {Name: "focus"}},
+		"Review this diff{{if .focus}} for {{.focus}}{{end}}:\n{{.diff}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.RegisterPrompt(review)
+
+	initResp := s.HandleRequest(&mcpserver.JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "initialize"})
+	if initResp.Result.(*mcpserver.InitializeResult).Capabilities.Prompts == nil {
+		t.Fatal("prompts capability not advertised")
+	}
+
+	resp := s.HandleRequest(&mcpserver.JSONRPCRequest{JSONRPC: "2.0", ID: 2, Method: "prompts/list"})
+	list, ok := resp.Result.(*mcpserver.PromptsListResult)
+	if !ok || len(list.Prompts) != 1 || len(list.Prompts[0].Arguments) != 2 {
+		t.Fatalf("prompts/list = %+v", resp.Result)
+	}
+
+	resp = s.HandleRequest(&mcpserver.JSONRPCRequest{JSONRPC: "2.0", ID: 3, Method: "prompts/get",
+		Params: map[string]any{"name": "review", "arguments": map[string]any{"diff": "+x", "focus": "security"}}})
+	got, ok := resp.Result.(*mcpserver.GetPromptResult)
+	if !ok || len(got.Messages) != 1 || got.Messages[0].Content.Text != "Review this diff for security:\n+x" {
+		t.Fatalf("prompts/get = %+v, %+v", resp.Result, resp.Error)
+	}
+
+	resp = s.HandleRequest(&mcpserver.JSONRPCRequest{JSONRPC: "2.0", ID: 4, Method: "prompts/get",
+		Params: map[string]any{"name": "review"}})
+	if resp.Error == nil || resp.Error.Code != -32602 {
+		t.Fatalf("missing argument: error = %+v", resp.Error)
+	}
+}
+
+// SlowTool blocks until its context ends, or forever if it ignores it.
+type SlowTool struct {
+	mcpserver.BaseTool
+	ignoreContext bool
+}
+
+func (t *SlowTool) Execute(ctx context.Context, args map[string]any) (*mcpserver.ToolCallResult, error) {
+	if t.ignoreContext {
+		select {}
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// LegacyEchoTool implements the pre-context Execute signature.
+type LegacyEchoTool struct {
+	mcpserver.BaseTool
+}
+
+func (t *LegacyEchoTool) Execute(args map[string]any) (*mcpserver.ToolCallResult, error) {
+	msg, _ := args["message"].(string)
+	return mcpserver.TextResult("Legacy: " + msg), nil
+}
+
+func TestServer_ToolContext(t *testing.T) {
+	s := mcpserver.New("test-server", "1.0.0")
+	s.SetToolTimeout(time.Hour)
+	s.RegisterTools(
+		&SlowTool{BaseTool: mcpserver.BaseTool{ToolName: "slow", ToolTimeout: 20 * time.Millisecond}},
+		&SlowTool{BaseTool: mcpserver.BaseTool{ToolName: "stuck"}, ignoreContext: true},
+		mcpserver.AdaptLegacyTool(&LegacyEchoTool{BaseTool: mcpserver.BaseTool{ToolName: "legacy"}}),
+	)
+	call := func(ctx context.Context, name string) *mcpserver.ToolCallResult {
+		req := &mcpserver.JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "tools/call",
+			Params: map[string]any{"name": name, "arguments": map[string]any{"message": "hi"}}}
+		return s.HandleRequest(req.WithContext(ctx)).Result.(*mcpserver.ToolCallResult)
+	}
+
+	// Per-tool timeout overrides the server default
+	if r := call(context.Background(), "slow"); !r.IsError || !strings.Contains(r.Content[0].Text, "timed out") {
+		t.Fatalf("slow tool result = %+v", r)
+	}
+
+	// Cancelling the request abandons a tool that ignores its context
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if r := call(ctx, "stuck"); !r.IsError || !strings.Contains(r.Content[0].Text, "cancelled") {
+		t.Fatalf("stuck tool result = %+v", r)
+	}
+
+	if r := call(context.Background(), "legacy"); r.IsError || r.Content[0].Text != "Legacy: hi" {
+		t.Fatalf("legacy tool result = %+v", r)
+	}
+}
+
+func TestHTTP_BearerAuth(t *testing.T) {
+	s := mcpserver.New("test-server", "1.0.0")
+	s.RegisterTools(NewEchoTool(), &SlowTool{BaseTool: mcpserver.BaseTool{ToolName: "slow"}})
+	s.SetHTTPAuthToken("admin-secret")
+	s.AddHTTPAuthToken("echo-only", mcpserver.ScopeMCP, mcpserver.ToolScope("echo"))
+	handler := s.HTTPHandler()
+
+	do := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodGet, "/health", "", ""); rec.Code != http.StatusOK {
+		t.Errorf("health without token = %d, want 200", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/api/tools", "", ""); rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+		t.Errorf("no token = %d, want 401 with WWW-Authenticate", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/api/tools", "wrong", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong token = %d, want 401", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/api/tools", "admin-secret", ""); rec.Code != http.StatusOK {
+		t.Errorf("admin token = %d, want 200", rec.Code)
+	}
+
+	// Scoped token: echo only, over REST and /mcp
+	if rec := do(http.MethodGet, "/api/tools", "echo-only", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("scoped token listing tools = %d, want 403", rec.Code)
+	}
+	if rec := do(http.MethodPost, "/api/tools/echo", "echo-only", `{"message":"hi"}`); rec.Code != http.StatusOK {
+		t.Errorf("scoped token calling echo = %d, want 200", rec.Code)
+	}
+	if rec := do(http.MethodPost, "/api/tools/slow", "echo-only", `{}`); rec.Code != http.StatusForbidden {
+		t.Errorf("scoped token calling slow = %d, want 403", rec.Code)
+	}
+	rec := do(http.MethodPost, "/mcp", "echo-only", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow"}}`)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("scoped token calling slow over /mcp = %d, want 403", rec.Code)
+	}
+}
+
+// StepsTool reports progress for each of its steps.
+type StepsTool struct {
+	mcpserver.BaseTool
+}
+
+func (t *StepsTool) Execute(ctx context.Context, args map[string]any) (*mcpserver.ToolCallResult, error) {
+	for i := 1; i <= 2; i++ {
+		mcpserver.ReportProgress(ctx, float64(i), 2, "step")
+	}
+	return mcpserver.TextResult("done"), nil
+}
+
+func TestHTTP_ProgressNotifications(t *testing.T) {
+	s := mcpserver.New("test-server", "1.0.0")
+	s.RegisterTool(&StepsTool{BaseTool: mcpserver.BaseTool{ToolName: "steps"}})
+	ts := httptest.NewServer(s.HTTPHandler())
+	defer ts.Close()
+
+	resp := s.HandleRequest(&mcpserver.JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "initialize"})
+	id := resp.Result.(*mcpserver.InitializeResult).SessionID
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/mcp", strings.NewReader(
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"steps","_meta":{"progressToken":"tok"}}}`))
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	req.Header.Set("Mcp-Session-Id", id)
+	httpResp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer httpResp.Body.Close()
+
+	var messages []map[string]any
+	events := bufio.NewScanner(httpResp.Body)
+	for events.Scan() {
+		if data, ok := strings.CutPrefix(events.Text(), "data: "); ok {
+			var msg map[string]any
+			if err := json.Unmarshal([]byte(data), &msg); err != nil {
+				t.Fatal(err)
+			}
+			messages = append(messages, msg)
+		}
+	}
+	if len(messages) != 3 {
+		t.Fatalf("got %d messages, want 2 progress + 1 result: %v", len(messages), messages)
+	}
+	for i, msg := range messages[:2] {
+		params, _ := msg["params"].(map[string]any)
+		if msg["method"] != "notifications/progress" || params["progressToken"] != "tok" || params["progress"] != float64(i+1) {
+			t.Errorf("message %d = %v", i, msg)
+		}
+	}
+	if messages[2]["result"] == nil {
+		t.Errorf("last message = %v, want the tool result", messages[2])
+	}
+}
+
+type searchArgs struct {
+	Query string   `json:"query" description:"Search terms"`
+	Lang  string   `json:"lang,omitempty" jsonschema:"enum=en|zh,default=en"`
+	Limit int      `json:"limit,omitempty" jsonschema:"minimum=1,maximum=50,default=10"`
+	Tags  []string `json:"tags,omitempty"`
+	Debug *bool    `json:"debug"`
+}
+
+func TestRegisterFunc(t *testing.T) {
+	s := mcpserver.New("test-server", "1.0.0")
+	mcpserver.RegisterFunc(s, "search", "Search articles", func(ctx context.Context, args searchArgs) (*mcpserver.ToolCallResult, error) {
+		return mcpserver.TextResult(fmt.Sprintf("%s/%s/%d/%v", args.Query, args.Lang, args.Limit, args.Tags)), nil
+	})
+
+	schema := mcpserver.SchemaFor[searchArgs]()
+	props := schema["properties"].(map[string]any)
+	if req, _ := schema["required"].([]string); len(req) != 1 || req[0] != "query" {
+		t.Errorf("required = %v, want [query]", schema["required"])
+	}
+	if q := props["query"].(map[string]any); q["type"] != "string" || q["description"] != "Search terms" {
+		t.Errorf("query schema = %v", q)
+	}
+	if l := props["limit"].(map[string]any); l["type"] != "integer" || l["maximum"] != 50.0 || l["default"] != int64(10) {
+		t.Errorf("limit schema = %v", l)
+	}
+	if tags := props["tags"].(map[string]any); tags["type"] != "array" {
+		t.Errorf("tags schema = %v", tags)
+	}
+
+	call := func(args map[string]any) *mcpserver.ToolCallResult {
+		resp := s.HandleRequest(&mcpserver.JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "tools/call",
+			Params: map[string]any{"name": "search", "arguments": args}})
+		return resp.Result.(*mcpserver.ToolCallResult)
+	}
+	if r := call(map[string]any{"query": "gpt", "lang": "zh", "limit": 3, "tags": []any{"ai"}}); r.IsError || r.Content[0].Text != "gpt/zh/3/[ai]" {
+		t.Errorf("typed call = %+v", r)
+	}
+	if r := call(map[string]any{"lang": "zh"}); !r.IsError || !strings.Contains(r.Content[0].Text, "query") {
+		t.Errorf("missing query = %+v", r)
+	}
+	if r := call(map[string]any{"query": "gpt", "limit": "many"}); !r.IsError {
+		t.Errorf("wrong type = %+v", r)
+	}
+}
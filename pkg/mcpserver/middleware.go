@@ -1,28 +1,70 @@
 package mcpserver
 
-import "log/slog"
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"runtime/debug"
+	"sync"
+	"time"
+)
 
-// LoggingMiddleware logs all incoming requests and their results.
+// LoggingMiddleware logs every request once it completes, with its duration,
+// session, and for tools/call the tool name. Failed requests and tool errors
+// are logged at error and warn level. A nil logger uses slog.Default.
 func LoggingMiddleware(logger *slog.Logger) Middleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
 	return func(next HandlerFunc) HandlerFunc {
 		return func(req *JSONRPCRequest) *JSONRPCResponse {
-			logger.Info("mcp request", "method", req.Method, "id", req.ID)
+			start := time.Now()
 			resp := next(req)
-			if resp != nil && resp.Error != nil {
-				logger.Error("mcp error", "method", req.Method, "code", resp.Error.Code, "message", resp.Error.Message)
+
+			attrs := []any{"method", req.Method, "duration", time.Since(start)}
+			if req.ID != nil {
+				attrs = append(attrs, "id", req.ID)
+			}
+			if id := SessionIDFromContext(req.Context()); id != "" {
+				attrs = append(attrs, "session", id)
+			}
+			if req.Method == "tools/call" {
+				if params, ok := req.Params.(map[string]any); ok {
+					if name, ok := params["name"].(string); ok {
+						attrs = append(attrs, "tool", name)
+					}
+				}
+			}
+
+			var result *ToolCallResult
+			if resp != nil {
+				result, _ = resp.Result.(*ToolCallResult)
+			}
+			switch {
+			case resp != nil && resp.Error != nil:
+				logger.Error("mcp error", append(attrs, "code", resp.Error.Code, "message", resp.Error.Message)...)
+			case result != nil && result.IsError:
+				message := ""
+				if len(result.Content) > 0 {
+					message = result.Content[0].Text
+				}
+				logger.Warn("mcp tool error", append(attrs, "message", message)...)
+			default:
+				logger.Info("mcp request", attrs...)
 			}
 			return resp
 		}
 	}
 }
 
-// RecoveryMiddleware catches panics and returns a JSON-RPC error.
+// RecoveryMiddleware catches panics and returns a JSON-RPC error. Panics in
+// tools are recovered by the server itself and reported as tool errors.
 func RecoveryMiddleware() Middleware {
 	return func(next HandlerFunc) HandlerFunc {
 		return func(req *JSONRPCRequest) (resp *JSONRPCResponse) {
 			defer func() {
 				if r := recover(); r != nil {
-					slog.Error("panic in MCP handler", "method", req.Method, "panic", r)
+					slog.Error("panic in MCP handler", "method", req.Method, "panic", r, "stack", string(debug.Stack()))
 					resp = &JSONRPCResponse{
 						JSONRPC: "2.0",
 						ID:      req.ID,
@@ -37,3 +79,83 @@ func RecoveryMiddleware() Middleware {
 		}
 	}
 }
+
+// ErrCodeRateLimited is the JSON-RPC error code RateLimitMiddleware returns,
+// after HTTP 429. The error data carries retryAfter in seconds.
+const ErrCodeRateLimited = -32029
+
+// RateLimitMiddleware limits each session to perSecond requests on average,
+// with bursts of up to burst. Stdio, having a single client, shares one
+// limit. Initialize and notifications are never limited. It panics if
+// perSecond is not positive or burst is less than 1.
+func RateLimitMiddleware(perSecond float64, burst int) Middleware {
+	if perSecond <= 0 || burst < 1 {
+		panic(fmt.Sprintf("mcpserver: invalid rate limit %g/s, burst %d", perSecond, burst))
+	}
+	l := &rateLimiter{rate: perSecond, burst: float64(burst), buckets: make(map[string]*tokenBucket)}
+	return func(next HandlerFunc) HandlerFunc {
+		return func(req *JSONRPCRequest) *JSONRPCResponse {
+			if req.ID == nil || req.Method == "initialize" {
+				return next(req)
+			}
+			if wait, ok := l.allow(SessionIDFromContext(req.Context()), time.Now()); !ok {
+				return &JSONRPCResponse{
+					JSONRPC: "2.0",
+					ID:      req.ID,
+					Error: &RPCError{
+						Code:    ErrCodeRateLimited,
+						Message: "Rate limit exceeded",
+						Data:    map[string]any{"retryAfter": math.Ceil(wait.Seconds())},
+					},
+				}
+			}
+			return next(req)
+		}
+	}
+}
+
+// rateLimiter keeps a token bucket per session.
+type rateLimiter struct {
+	rate    float64 // tokens per second
+	burst   float64
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	swept   time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// allow takes a token from the session's bucket, or reports how long until
+// one is available.
+func (l *rateLimiter) allow(session string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Buckets idle long enough to refill are the same as new ones, so
+	// dropping them bounds memory as sessions come and go
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	if now.Sub(l.swept) > max(refill, time.Minute) {
+		for id, b := range l.buckets {
+			if now.Sub(b.last) >= refill {
+				delete(l.buckets, id)
+			}
+		}
+		l.swept = now
+	}
+
+	b, ok := l.buckets[session]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[session] = b
+	}
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / l.rate * float64(time.Second)), false
+	}
+	b.tokens--
+	return 0, true
+}
@@ -0,0 +1,47 @@
+package mcpserver
+
+import "context"
+
+// notifyFunc sends a notification to the client that made the current
+// request, over whichever channel its transport offers.
+type notifyFunc func(method string, params any)
+
+type notifierKey struct{}
+
+func withNotifier(ctx context.Context, notify notifyFunc) context.Context {
+	return context.WithValue(ctx, notifierKey{}, notify)
+}
+
+type progressKey struct{}
+
+// progressReporter delivers a tool call's progress under the client's token.
+type progressReporter struct {
+	token  any
+	notify notifyFunc
+}
+
+// withProgress enables ReportProgress for a tool call if the client sent a
+// progress token and the transport can deliver notifications.
+func withProgress(ctx context.Context, token any) context.Context {
+	notify, ok := ctx.Value(notifierKey{}).(notifyFunc)
+	if token == nil || !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, &progressReporter{token: token, notify: notify})
+}
+
+// ReportProgress sends a notifications/progress message for the tool call
+// running under ctx, so long-running tools don't appear hung. total is 0 when
+// unknown. It does nothing if the client didn't ask for progress.
+func ReportProgress(ctx context.Context, progress, total float64, message string) {
+	p, ok := ctx.Value(progressKey{}).(*progressReporter)
+	if !ok {
+		return
+	}
+	p.notify("notifications/progress", ProgressNotification{
+		ProgressToken: p.token,
+		Progress:      progress,
+		Total:         total,
+		Message:       message,
+	})
+}
@@ -0,0 +1,63 @@
+package mcpserver
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// PromptHandler is the interface for MCP prompts: reusable templates that
+// clients offer to users, e.g. as slash commands.
+type PromptHandler interface {
+	// Name returns the unique prompt name.
+	Name() string
+
+	// Description returns a human-readable description.
+	Description() string
+
+	// Arguments describes the arguments Get accepts.
+	Arguments() []PromptArgument
+
+	// Get renders the prompt with the given arguments.
+	Get(args map[string]string) (*GetPromptResult, error)
+}
+
+// TemplatePrompt is a PromptHandler that renders a text/template into a
+// single user message. Arguments are available as {{.name}}.
+type TemplatePrompt struct {
+	name        string
+	description string
+	arguments   []PromptArgument
+	tmpl        *template.Template
+}
+
+// NewTemplatePrompt parses text as the template for a prompt.
+func NewTemplatePrompt(name, description string, arguments []PromptArgument, text string) (*TemplatePrompt, error) {
+	tmpl, err := template.New(name).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse prompt %s: %w", name, err)
+	}
+	return &TemplatePrompt{name: name, description: description, arguments: arguments, tmpl: tmpl}, nil
+}
+
+func (p *TemplatePrompt) Name() string                { return p.name }
+func (p *TemplatePrompt) Description() string         { return p.description }
+func (p *TemplatePrompt) Arguments() []PromptArgument { return p.arguments }
+
+func (p *TemplatePrompt) Get(args map[string]string) (*GetPromptResult, error) {
+	for _, a := range p.arguments {
+		if a.Required && args[a.Name] == "" {
+			return nil, fmt.Errorf("missing required argument: %s", a.Name)
+		}
+	}
+	var sb strings.Builder
+	if err := p.tmpl.Execute(&sb, args); err != nil {
+		return nil, fmt.Errorf("render prompt %s: %w", p.name, err)
+	}
+	return &GetPromptResult{
+		Description: p.description,
+		Messages: []PromptMessage{
+			{Role: "user", Content: Content{Type: "text", Text: sb.String()}},
+		},
+	}, nil
+}
@@ -1,6 +1,9 @@
 package mcpserver
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+)
 
 // JSON-RPC 2.0 protocol types
 
@@ -10,6 +13,24 @@ type JSONRPCRequest struct {
 	ID      any    `json:"id,omitempty"`
 	Method  string `json:"method"`
 	Params  any    `json:"params,omitempty"`
+
+	ctx context.Context
+}
+
+// Context returns the request's context: the HTTP request's, or the stdio
+// server's, which ends at shutdown. It is never nil.
+func (r *JSONRPCRequest) Context() context.Context {
+	if r.ctx != nil {
+		return r.ctx
+	}
+	return context.Background()
+}
+
+// WithContext returns a shallow copy of r with its context changed to ctx.
+func (r *JSONRPCRequest) WithContext(ctx context.Context) *JSONRPCRequest {
+	r2 := *r
+	r2.ctx = ctx
+	return &r2
 }
 
 // JSONRPCResponse represents a JSON-RPC 2.0 response.
@@ -29,6 +50,13 @@ type RPCError struct {
 
 // MCP protocol types
 
+// Protocol versions the server can speak, newest first. The Streamable HTTP
+// transport was introduced in 2025-03-26.
+var SupportedProtocolVersions = []string{LatestProtocolVersion, "2024-11-05"}
+
+// LatestProtocolVersion is offered to clients that request an unsupported version.
+const LatestProtocolVersion = "2025-03-26"
+
 // InitializeResult is the response to an initialize request.
 type InitializeResult struct {
 	ProtocolVersion string             `json:"protocolVersion"`
@@ -39,7 +67,8 @@ type InitializeResult struct {
 
 // ServerCapabilities describes the server's supported features.
 type ServerCapabilities struct {
-	Tools ToolsCapability `json:"tools"`
+	Tools   ToolsCapability    `json:"tools"`
+	Prompts *PromptsCapability `json:"prompts,omitempty"` // set when prompts are registered
 }
 
 // ToolsCapability describes the tools capability.
@@ -47,6 +76,11 @@ type ToolsCapability struct {
 	ListChanged bool `json:"listChanged"`
 }
 
+// PromptsCapability describes the prompts capability.
+type PromptsCapability struct {
+	ListChanged bool `json:"listChanged"`
+}
+
 // ServerInfo describes the server.
 type ServerInfo struct {
 	Name    string `json:"name"`
@@ -65,6 +99,45 @@ type ToolsListResult struct {
 	Tools []ToolDef `json:"tools"`
 }
 
+// PromptDef represents a prompt definition for listing.
+type PromptDef struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+// PromptArgument describes one argument a prompt accepts.
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// PromptsListResult is the result of a prompts/list request.
+type PromptsListResult struct {
+	Prompts []PromptDef `json:"prompts"`
+}
+
+// GetPromptResult is the result of a prompts/get request.
+type GetPromptResult struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// PromptMessage is one message of a rendered prompt.
+type PromptMessage struct {
+	Role    string  `json:"role"` // "user" or "assistant"
+	Content Content `json:"content"`
+}
+
+// ProgressNotification is the params of a notifications/progress message.
+type ProgressNotification struct {
+	ProgressToken any     `json:"progressToken"`
+	Progress      float64 `json:"progress"`
+	Total         float64 `json:"total,omitempty"`
+	Message       string  `json:"message,omitempty"`
+}
+
 // ToolCallResult is the standard result from executing a tool.
 type ToolCallResult struct {
 	Content []Content `json:"content"`
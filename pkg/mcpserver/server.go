@@ -1,36 +1,61 @@
 // Package mcpserver provides a reusable MCP (Model Context Protocol) server framework.
 //
 // It extracts the core patterns from npinterface-mcp into a generic, embeddable package
-// that supports stdio and HTTP/SSE transports, JSON-RPC 2.0, session management,
+// that supports stdio and Streamable HTTP transports, JSON-RPC 2.0, session management,
 // middleware chains, and a clean tool registration interface.
 //
 // Quick Start:
 //
 //	server := mcpserver.New("my-server", "1.0.0")
 //	server.RegisterTool(&MyTool{})
+//	mcpserver.RegisterFunc(server, "greet", "Say hello", func(ctx context.Context, args struct {
+//		Name string `json:"name" description:"Who to greet"`
+//	}) (*mcpserver.ToolCallResult, error) {
+//		return mcpserver.TextResult("Hello, " + args.Name), nil
+//	})
 //	server.RunStdio() // or server.RunHTTP(":8080")
 package mcpserver
 
 import (
-	"crypto/rand"
-	"encoding/hex"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"os/signal"
+	"runtime/debug"
+	"slices"
+	"sort"
 	"sync"
+	"syscall"
 	"time"
 )
 
+// DefaultToolTimeout limits tool calls unless changed with SetToolTimeout or
+// overridden per tool (see ToolTimeout).
+const DefaultToolTimeout = 5 * time.Minute
+
 // Server is the core MCP server that manages tools and handles JSON-RPC requests.
 type Server struct {
 	name            string
 	version         string
 	protocolVersion string
 	tools           map[string]ToolHandler
-	sessions        map[string]time.Time
-	sessionMu       sync.RWMutex
+	prompts         map[string]PromptHandler
+	sessions        map[string]time.Time // session ID → last use
+	expired         map[string]time.Time // recently expired IDs → expiry time
+	sessionMu       sync.Mutex
+	toolTimeout     time.Duration
+	sessionTTL      time.Duration
+	maxSessions     int
+	onExpire        func(id string)
+	streams         map[string]*eventStream
+	streamMu        sync.Mutex
+	stdioEnc        *json.Encoder // set while RunStdio runs, for Notify
+	stdioMu         sync.Mutex
+	authTokens      []authToken
+	authMu          sync.RWMutex
 	middleware      []Middleware
 	logger          *slog.Logger
 }
@@ -40,9 +65,15 @@ func New(name, version string) *Server {
 	return &Server{
 		name:            name,
 		version:         version,
-		protocolVersion: "2024-11-05",
+		protocolVersion: LatestProtocolVersion,
 		tools:           make(map[string]ToolHandler),
+		prompts:         make(map[string]PromptHandler),
 		sessions:        make(map[string]time.Time),
+		expired:         make(map[string]time.Time),
+		toolTimeout:     DefaultToolTimeout,
+		sessionTTL:      DefaultSessionTTL,
+		maxSessions:     DefaultMaxSessions,
+		streams:         make(map[string]*eventStream),
 		logger:          slog.Default(),
 	}
 }
@@ -60,17 +91,58 @@ func (s *Server) RegisterTools(tools ...ToolHandler) {
 	}
 }
 
-// Use adds middleware to the server's processing chain.
+// RegisterPrompt adds a prompt to the server.
+func (s *Server) RegisterPrompt(prompt PromptHandler) {
+	s.prompts[prompt.Name()] = prompt
+	s.logger.Info("registered prompt", "name", prompt.Name())
+}
+
+// RegisterPrompts adds multiple prompts to the server.
+func (s *Server) RegisterPrompts(prompts ...PromptHandler) {
+	for _, prompt := range prompts {
+		s.RegisterPrompt(prompt)
+	}
+}
+
+// SetToolTimeout sets the default time limit for tool calls. Zero disables it.
+func (s *Server) SetToolTimeout(d time.Duration) {
+	s.toolTimeout = d
+}
+
+// Use adds middleware to the server's processing chain. Middleware added
+// first runs outermost, so add RecoveryMiddleware first.
 func (s *Server) Use(mw Middleware) {
 	s.middleware = append(s.middleware, mw)
 }
 
-// RunStdio starts the server using stdin/stdout (stdio transport).
+// RunStdio starts the server using stdin/stdout (stdio transport). Tool
+// calls are cancelled on SIGINT or SIGTERM.
 func (s *Server) RunStdio() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return s.RunStdioContext(ctx)
+}
+
+// RunStdioContext is RunStdio with requests run under ctx. It returns when
+// stdin is closed.
+func (s *Server) RunStdioContext(ctx context.Context) error {
 	s.logger.Info("starting MCP server (stdio)", "name", s.name, "version", s.version, "tools", len(s.tools))
 
 	decoder := json.NewDecoder(os.Stdin)
 	encoder := json.NewEncoder(os.Stdout)
+	s.stdioMu.Lock()
+	s.stdioEnc = encoder
+	s.stdioMu.Unlock()
+	defer func() {
+		s.stdioMu.Lock()
+		s.stdioEnc = nil
+		s.stdioMu.Unlock()
+	}()
+	reqCtx := withNotifier(ctx, func(method string, params any) {
+		if err := s.Notify("", method, params); err != nil {
+			s.logger.Error("send notification", "method", method, "error", err)
+		}
+	})
 
 	for {
 		var req JSONRPCRequest
@@ -81,12 +153,15 @@ func (s *Server) RunStdio() error {
 			return fmt.Errorf("decode request: %w", err)
 		}
 
-		resp := s.HandleRequest(&req)
+		resp := s.HandleRequest(req.WithContext(reqCtx))
 		if resp == nil {
 			continue // Notification, no response needed
 		}
 
-		if err := encoder.Encode(resp); err != nil {
+		s.stdioMu.Lock()
+		err := encoder.Encode(resp)
+		s.stdioMu.Unlock()
+		if err != nil {
 			return fmt.Errorf("encode response: %w", err)
 		}
 	}
@@ -118,7 +193,16 @@ func (s *Server) coreHandler(req *JSONRPCRequest) *JSONRPCResponse {
 	case "tools/list":
 		resp.Result = s.handleToolsList()
 	case "tools/call":
-		resp.Result = s.handleToolCall(req.Params)
+		resp.Result = s.handleToolCall(req.Context(), req.Params)
+	case "prompts/list":
+		resp.Result = s.handlePromptsList()
+	case "prompts/get":
+		result, err := s.handlePromptGet(req.Params)
+		if err != nil {
+			resp.Error = &RPCError{Code: -32602, Message: err.Error()}
+		} else {
+			resp.Result = result
+		}
 	default:
 		resp.Error = &RPCError{
 			Code:    -32601,
@@ -130,11 +214,22 @@ func (s *Server) coreHandler(req *JSONRPCRequest) *JSONRPCResponse {
 }
 
 func (s *Server) handleInitialize(params any) *InitializeResult {
+	// Use the client's protocol version if supported, else offer ours
+	version := s.protocolVersion
+	if p, ok := params.(map[string]any); ok {
+		if v, _ := p["protocolVersion"].(string); slices.Contains(SupportedProtocolVersions, v) {
+			version = v
+		}
+	}
+	capabilities := ServerCapabilities{
+		Tools: ToolsCapability{ListChanged: false},
+	}
+	if len(s.prompts) > 0 {
+		capabilities.Prompts = &PromptsCapability{ListChanged: false}
+	}
 	return &InitializeResult{
-		ProtocolVersion: s.protocolVersion,
-		Capabilities: ServerCapabilities{
-			Tools: ToolsCapability{ListChanged: false},
-		},
+		ProtocolVersion: version,
+		Capabilities:    capabilities,
 		ServerInfo: ServerInfo{
 			Name:    s.name,
 			Version: s.version,
@@ -155,7 +250,7 @@ func (s *Server) handleToolsList() *ToolsListResult {
 	return &ToolsListResult{Tools: tools}
 }
 
-func (s *Server) handleToolCall(params any) any {
+func (s *Server) handleToolCall(ctx context.Context, params any) any {
 	paramsBytes, err := json.Marshal(params)
 	if err != nil {
 		return ErrorResult(fmt.Errorf("parse params: %w", err))
@@ -164,6 +259,9 @@ func (s *Server) handleToolCall(params any) any {
 	var callParams struct {
 		Name      string         `json:"name"`
 		Arguments map[string]any `json:"arguments"`
+		Meta      struct {
+			ProgressToken any `json:"progressToken"`
+		} `json:"_meta"`
 	}
 	if err := json.Unmarshal(paramsBytes, &callParams); err != nil {
 		return ErrorResult(fmt.Errorf("unmarshal params: %w", err))
@@ -174,35 +272,88 @@ func (s *Server) handleToolCall(params any) any {
 		return ErrorResult(fmt.Errorf("tool not found: %s", callParams.Name))
 	}
 
-	result, err := tool.Execute(callParams.Arguments)
+	ctx = withProgress(ctx, callParams.Meta.ProgressToken)
+	result, err := s.executeTool(ctx, tool, callParams.Arguments)
 	if err != nil {
 		return ErrorResult(err)
 	}
 	return result
 }
 
-// Session management
+// executeTool runs a tool under its timeout. A tool that ignores its context
+// is abandoned when the context ends, and keeps running in the background.
+func (s *Server) executeTool(ctx context.Context, tool ToolHandler, args map[string]any) (*ToolCallResult, error) {
+	timeout := s.toolTimeout
+	if tt, ok := tool.(ToolTimeout); ok && tt.Timeout() >= 0 {
+		timeout = tt.Timeout()
+	}
+	parent := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		result *ToolCallResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		// The call runs outside the middleware chain, so RecoveryMiddleware can't catch this
+		defer func() {
+			if r := recover(); r != nil {
+				s.logger.Error("panic in MCP tool", "tool", tool.Name(), "panic", r, "stack", string(debug.Stack()))
+				done <- outcome{nil, fmt.Errorf("tool %s panicked: %v", tool.Name(), r)}
+			}
+		}()
+		result, err := tool.Execute(ctx, args)
+		done <- outcome{result, err}
+	}()
 
-func (s *Server) createSession() string {
-	id := generateSessionID()
-	s.sessionMu.Lock()
-	defer s.sessionMu.Unlock()
-	s.sessions[id] = time.Now()
-	return id
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-ctx.Done():
+		if parent.Err() != nil {
+			return nil, fmt.Errorf("tool %s cancelled: %w", tool.Name(), parent.Err())
+		}
+		return nil, fmt.Errorf("tool %s timed out after %v", tool.Name(), timeout)
+	}
 }
 
-// CheckSession verifies if a session ID is valid.
-func (s *Server) CheckSession(id string) bool {
-	s.sessionMu.RLock()
-	defer s.sessionMu.RUnlock()
-	_, ok := s.sessions[id]
-	return ok
+func (s *Server) handlePromptsList() *PromptsListResult {
+	prompts := make([]PromptDef, 0, len(s.prompts))
+	for _, p := range s.prompts {
+		prompts = append(prompts, PromptDef{
+			Name:        p.Name(),
+			Description: p.Description(),
+			Arguments:   p.Arguments(),
+		})
+	}
+	sort.Slice(prompts, func(i, j int) bool { return prompts[i].Name < prompts[j].Name })
+	return &PromptsListResult{Prompts: prompts}
 }
 
-func generateSessionID() string {
-	b := make([]byte, 16)
-	if _, err := rand.Read(b); err != nil {
-		return fmt.Sprintf("sess-%d", time.Now().UnixNano())
+// handlePromptGet renders a prompt. Unknown prompts and bad arguments are
+// JSON-RPC errors (invalid params), unlike tool failures.
+func (s *Server) handlePromptGet(params any) (*GetPromptResult, error) {
+	paramsBytes, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("parse params: %w", err)
+	}
+
+	var getParams struct {
+		Name      string            `json:"name"`
+		Arguments map[string]string `json:"arguments"`
+	}
+	if err := json.Unmarshal(paramsBytes, &getParams); err != nil {
+		return nil, fmt.Errorf("unmarshal params: %w", err)
+	}
+
+	prompt, ok := s.prompts[getParams.Name]
+	if !ok {
+		return nil, fmt.Errorf("prompt not found: %s", getParams.Name)
 	}
-	return hex.EncodeToString(b)
+	return prompt.Get(getParams.Arguments)
 }
@@ -0,0 +1,221 @@
+package mcpserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Session defaults; override with SetSessionTTL and SetMaxSessions.
+// RunHTTP sweeps idle sessions every SessionJanitorInterval.
+const (
+	DefaultSessionTTL  = time.Hour
+	DefaultMaxSessions = 1000
+
+	SessionJanitorInterval = time.Minute
+)
+
+// SessionState is the result of looking up a session ID.
+type SessionState int
+
+const (
+	SessionUnknown SessionState = iota // never issued, or forgotten
+	SessionValid
+	SessionExpired // expired or evicted recently; the client should re-initialize
+)
+
+type sessionKey struct{}
+
+// WithSessionID returns ctx carrying the session a request belongs to. The
+// HTTP transport sets it; custom transports calling HandleRequest may too.
+func WithSessionID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, sessionKey{}, id)
+}
+
+// SessionIDFromContext returns the request's session ID, or "" for stdio and
+// session-less requests such as initialize.
+func SessionIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(sessionKey{}).(string)
+	return id
+}
+
+// SetSessionTTL sets how long a session may stay idle before it expires.
+// Zero disables expiry.
+func (s *Server) SetSessionTTL(ttl time.Duration) {
+	s.sessionMu.Lock()
+	defer s.sessionMu.Unlock()
+	s.sessionTTL = ttl
+}
+
+// SetMaxSessions caps the number of live sessions. When the cap is reached,
+// the least recently used session is evicted. Zero means no cap.
+func (s *Server) SetMaxSessions(n int) {
+	s.sessionMu.Lock()
+	defer s.sessionMu.Unlock()
+	s.maxSessions = n
+}
+
+// OnSessionExpired registers a hook called (outside the session lock) with
+// each session ID that expires or is evicted.
+func (s *Server) OnSessionExpired(fn func(id string)) {
+	s.sessionMu.Lock()
+	defer s.sessionMu.Unlock()
+	s.onExpire = fn
+}
+
+func (s *Server) createSession() string {
+	id := generateSessionID()
+	now := time.Now()
+
+	s.sessionMu.Lock()
+	gone := s.expireLocked(now)
+	if s.maxSessions > 0 && len(s.sessions) >= s.maxSessions {
+		oldest, oldestUse := "", now
+		for sid, used := range s.sessions {
+			if oldest == "" || used.Before(oldestUse) {
+				oldest, oldestUse = sid, used
+			}
+		}
+		s.forgetLocked(oldest, now)
+		gone = append(gone, oldest)
+		s.logger.Warn("session limit reached, evicted least recently used", "max", s.maxSessions)
+	}
+	s.sessions[id] = now
+	hook := s.onExpire
+	s.sessionMu.Unlock()
+
+	s.sessionsGone(hook, gone)
+	return id
+}
+
+// CheckSession verifies if a session ID is valid, and marks it as used.
+func (s *Server) CheckSession(id string) bool {
+	return s.LookupSession(id) == SessionValid
+}
+
+// LookupSession reports whether id is a live session, marking it as used if
+// so. Expired IDs are remembered for one TTL so transports can tell clients
+// to re-initialize rather than report an unknown session.
+func (s *Server) LookupSession(id string) SessionState {
+	now := time.Now()
+
+	s.sessionMu.Lock()
+	state := SessionUnknown
+	var gone []string
+	if used, ok := s.sessions[id]; ok {
+		if s.sessionTTL > 0 && now.Sub(used) > s.sessionTTL {
+			s.forgetLocked(id, now)
+			gone = append(gone, id)
+			state = SessionExpired
+		} else {
+			s.sessions[id] = now
+			state = SessionValid
+		}
+	} else if _, ok := s.expired[id]; ok {
+		state = SessionExpired
+	}
+	hook := s.onExpire
+	s.sessionMu.Unlock()
+
+	s.sessionsGone(hook, gone)
+	return state
+}
+
+// SessionCount returns the number of live sessions.
+func (s *Server) SessionCount() int {
+	s.sessionMu.Lock()
+	defer s.sessionMu.Unlock()
+	return len(s.sessions)
+}
+
+// CleanupSessions removes sessions idle for longer than the TTL and returns
+// how many were removed.
+func (s *Server) CleanupSessions() int {
+	s.sessionMu.Lock()
+	gone := s.expireLocked(time.Now())
+	hook := s.onExpire
+	s.sessionMu.Unlock()
+
+	s.sessionsGone(hook, gone)
+	return len(gone)
+}
+
+// StartSessionJanitor runs CleanupSessions every interval until ctx is
+// cancelled.
+func (s *Server) StartSessionJanitor(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if n := s.CleanupSessions(); n > 0 {
+					s.logger.Info("expired idle sessions", "count", n)
+				}
+			}
+		}
+	}()
+}
+
+// expireLocked removes idle sessions and forgets expired IDs older than one
+// TTL (DefaultSessionTTL when expiry is disabled, so evicted IDs don't pile
+// up). The caller holds sessionMu.
+func (s *Server) expireLocked(now time.Time) []string {
+	var gone []string
+	if s.sessionTTL > 0 {
+		for id, used := range s.sessions {
+			if now.Sub(used) > s.sessionTTL {
+				s.forgetLocked(id, now)
+				gone = append(gone, id)
+			}
+		}
+	}
+	retain := s.sessionTTL
+	if retain <= 0 {
+		retain = DefaultSessionTTL
+	}
+	for id, at := range s.expired {
+		if now.Sub(at) > retain {
+			delete(s.expired, id)
+		}
+	}
+	return gone
+}
+
+func (s *Server) forgetLocked(id string, now time.Time) {
+	delete(s.sessions, id)
+	s.expired[id] = now
+}
+
+// sessionsGone closes the event streams of expired sessions and calls hook.
+func (s *Server) sessionsGone(hook func(id string), ids []string) {
+	for _, id := range ids {
+		s.closeStream(id)
+		if hook != nil {
+			hook(id)
+		}
+	}
+}
+
+// TerminateSession ends a session at the client's request (HTTP DELETE). It
+// reports whether the session existed.
+func (s *Server) TerminateSession(id string) bool {
+	s.sessionMu.Lock()
+	_, ok := s.sessions[id]
+	delete(s.sessions, id)
+	s.sessionMu.Unlock()
+	s.closeStream(id)
+	return ok
+}
+
+func generateSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("sess-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,169 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// maxBufferedEvents is how many server-initiated messages each session keeps
+// for clients resuming a dropped stream with Last-Event-ID.
+const maxBufferedEvents = 256
+
+// ErrSessionNotFound is returned by Notify for an unknown or expired session.
+var ErrSessionNotFound = errors.New("session not found")
+
+// sseEvent is one server-initiated message on a session's event stream.
+type sseEvent struct {
+	ID   uint64
+	Data []byte
+}
+
+// eventStream holds a session's server-initiated messages: a replay buffer
+// and the channel of the GET stream currently listening, if any.
+type eventStream struct {
+	mu     sync.Mutex
+	nextID uint64
+	buf    []sseEvent
+	sub    chan sseEvent
+}
+
+// publish buffers an event and hands it to the listening stream, if any. A
+// listener that has fallen behind misses the live copy and can catch up by
+// reconnecting with Last-Event-ID.
+func (es *eventStream) publish(data []byte) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	es.nextID++
+	ev := sseEvent{ID: es.nextID, Data: data}
+	es.buf = append(es.buf, ev)
+	if len(es.buf) > maxBufferedEvents {
+		es.buf = es.buf[len(es.buf)-maxBufferedEvents:]
+	}
+	if es.sub != nil {
+		select {
+		case es.sub <- ev:
+		default:
+		}
+	}
+}
+
+// subscribe makes a new listener the session's only one, ending any previous
+// stream, and returns the buffered events after lastID to replay. With
+// resume false, nothing is replayed.
+func (es *eventStream) subscribe(lastID uint64, resume bool) (<-chan sseEvent, []sseEvent) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if es.sub != nil {
+		close(es.sub)
+	}
+	es.sub = make(chan sseEvent, 64)
+
+	var replay []sseEvent
+	if resume {
+		for _, ev := range es.buf {
+			if ev.ID > lastID {
+				replay = append(replay, ev)
+			}
+		}
+	}
+	return es.sub, replay
+}
+
+// unsubscribe ends the listener ch if it is still the current one.
+func (es *eventStream) unsubscribe(ch <-chan sseEvent) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if es.sub != nil && (<-chan sseEvent)(es.sub) == ch {
+		close(es.sub)
+		es.sub = nil
+	}
+}
+
+// close ends the listening stream when the session goes away.
+func (es *eventStream) close() {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if es.sub != nil {
+		close(es.sub)
+		es.sub = nil
+	}
+}
+
+// stream returns the event stream of a live session, creating it on first use.
+func (s *Server) stream(sessionID string) (*eventStream, bool) {
+	if !s.CheckSession(sessionID) {
+		return nil, false
+	}
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+	es, ok := s.streams[sessionID]
+	if !ok {
+		es = &eventStream{}
+		s.streams[sessionID] = es
+	}
+	return es, true
+}
+
+func (s *Server) closeStream(sessionID string) {
+	s.streamMu.Lock()
+	es, ok := s.streams[sessionID]
+	delete(s.streams, sessionID)
+	s.streamMu.Unlock()
+	if ok {
+		es.close()
+	}
+}
+
+// Notify sends a server-initiated JSON-RPC notification. Over HTTP it goes
+// to the session's GET event stream, buffered for resumption if no client is
+// listening; over stdio it is written to stdout and sessionID is ignored.
+func (s *Server) Notify(sessionID, method string, params any) error {
+	msg := JSONRPCRequest{JSONRPC: "2.0", Method: method, Params: params}
+
+	s.stdioMu.Lock()
+	enc := s.stdioEnc
+	if enc != nil {
+		defer s.stdioMu.Unlock()
+		return enc.Encode(msg)
+	}
+	s.stdioMu.Unlock()
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	es, ok := s.stream(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+	es.publish(data)
+	return nil
+}
+
+// Broadcast sends a notification to every live session, e.g.
+// notifications/tools/list_changed.
+func (s *Server) Broadcast(method string, params any) {
+	s.stdioMu.Lock()
+	stdio := s.stdioEnc != nil
+	s.stdioMu.Unlock()
+	if stdio {
+		if err := s.Notify("", method, params); err != nil {
+			s.logger.Error("broadcast notification", "method", method, "error", err)
+		}
+		return
+	}
+
+	s.sessionMu.Lock()
+	ids := make([]string, 0, len(s.sessions))
+	for id := range s.sessions {
+		ids = append(ids, id)
+	}
+	s.sessionMu.Unlock()
+
+	for _, id := range ids {
+		if err := s.Notify(id, method, params); err != nil && !errors.Is(err, ErrSessionNotFound) {
+			s.logger.Error("broadcast notification", "method", method, "error", err)
+		}
+	}
+}
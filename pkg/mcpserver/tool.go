@@ -1,5 +1,10 @@
 package mcpserver
 
+import (
+	"context"
+	"time"
+)
+
 // ToolHandler is the interface for MCP tools.
 // This is the generic version of npinterface-mcp's tools.ToolHandler,
 // decoupled from any specific API client.
@@ -13,16 +18,59 @@ type ToolHandler interface {
 	// InputSchema returns the JSON Schema for the tool's input.
 	InputSchema() map[string]any
 
-	// Execute runs the tool with the given arguments.
+	// Execute runs the tool with the given arguments. ctx is cancelled when
+	// the client goes away, the server shuts down or the tool times out.
+	Execute(ctx context.Context, args map[string]any) (*ToolCallResult, error)
+}
+
+// ToolTimeout is implemented by tools that need a time limit other than the
+// server's default (see Server.SetToolTimeout). A negative value means the
+// default applies; zero means no limit.
+type ToolTimeout interface {
+	Timeout() time.Duration
+}
+
+// LegacyToolHandler is the tool interface from before Execute took a
+// context. Register such tools with AdaptLegacyTool.
+type LegacyToolHandler interface {
+	Name() string
+	Description() string
+	InputSchema() map[string]any
 	Execute(args map[string]any) (*ToolCallResult, error)
 }
 
+// AdaptLegacyTool wraps a LegacyToolHandler as a ToolHandler. The tool can't
+// observe cancellation, but the server still stops waiting for it when the
+// context ends.
+func AdaptLegacyTool(t LegacyToolHandler) ToolHandler {
+	return legacyTool{t}
+}
+
+type legacyTool struct{ tool LegacyToolHandler }
+
+func (t legacyTool) Name() string                { return t.tool.Name() }
+func (t legacyTool) Description() string         { return t.tool.Description() }
+func (t legacyTool) InputSchema() map[string]any { return t.tool.InputSchema() }
+
+func (t legacyTool) Execute(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
+	return t.tool.Execute(args)
+}
+
+func (t legacyTool) Timeout() time.Duration {
+	if tt, ok := t.tool.(ToolTimeout); ok {
+		return tt.Timeout()
+	}
+	return -1
+}
+
 // BaseTool provides a base implementation for common tool fields.
 // Embed this in your tool structs and implement Execute().
 type BaseTool struct {
 	ToolName        string
 	ToolDescription string
 	ToolSchema      map[string]any
+	// ToolTimeout overrides the server's default tool timeout when non-zero.
+	ToolTimeout time.Duration
 
 	// Metadata for tool discovery / recommendation
 	Category string
@@ -34,8 +82,17 @@ func (t *BaseTool) Name() string                { return t.ToolName }
 func (t *BaseTool) Description() string         { return t.ToolDescription }
 func (t *BaseTool) InputSchema() map[string]any { return t.ToolSchema }
 
+// Timeout returns ToolTimeout, or -1 (use the server default) when unset.
+func (t *BaseTool) Timeout() time.Duration {
+	if t.ToolTimeout == 0 {
+		return -1
+	}
+	return t.ToolTimeout
+}
+
 // Middleware is a function that wraps a request handler.
 type Middleware func(next HandlerFunc) HandlerFunc
 
-// HandlerFunc is a function that handles a JSON-RPC request.
+// HandlerFunc is a function that handles a JSON-RPC request. The request's
+// Context carries the transport's cancellation.
 type HandlerFunc func(req *JSONRPCRequest) *JSONRPCResponse
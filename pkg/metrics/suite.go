@@ -0,0 +1,43 @@
+package metrics
+
+// The suite's metrics, in the Default registry.
+var (
+	// PagesChecked counts watchbot page checks by result: "changed",
+	// "unchanged" or "error".
+	PagesChecked = Default.NewCounterVec("devkit_watchbot_pages_checked_total",
+		"Pages checked by watchbot, by result.", "result")
+
+	// FetchDuration is the time watchbot takes to fetch a page, by fetcher
+	// ("http" or "browser") and result ("ok" or "error").
+	FetchDuration = Default.NewHistogramVec("devkit_watchbot_fetch_duration_seconds",
+		"Time to fetch a page, in seconds.", DefBuckets, "fetcher", "result")
+
+	// LLMTokens counts LLM tokens by provider, model and direction ("input"
+	// or "output").
+	LLMTokens = Default.NewCounterVec("devkit_llm_tokens_total",
+		"LLM tokens used.", "provider", "model", "direction")
+
+	// LLMCost is the estimated LLM spend in US dollars.
+	LLMCost = Default.NewCounterVec("devkit_llm_cost_usd_total",
+		"Estimated LLM cost, in US dollars.", "provider", "model")
+
+	// LLMCacheRequests counts LLM cache lookups by result ("hit" or
+	// "miss").
+	LLMCacheRequests = Default.NewCounterVec("devkit_llm_cache_requests_total",
+		"LLM response cache lookups, by result.", "result")
+
+	// Notifications counts notification sends by channel and result
+	// ("success" or "failure").
+	Notifications = Default.NewCounterVec("devkit_notifications_total",
+		"Notifications sent, by channel and result.", "channel", "result")
+
+	// HTTPRequestDuration is the API's request latency by route pattern and
+	// status code.
+	HTTPRequestDuration = Default.NewHistogramVec("devkit_http_request_duration_seconds",
+		"API request duration, in seconds.", DefBuckets, "route", "code")
+
+	// RateLimited counts API requests rejected with 429 by route pattern
+	// and limit ("ip", or the plan of the signed-in user).
+	RateLimited = Default.NewCounterVec("devkit_http_rate_limited_total",
+		"API requests rejected by rate limits, by route and limit.", "route", "limit")
+)
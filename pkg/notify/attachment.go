@@ -0,0 +1,36 @@
+package notify
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// Attachment is a file delivered alongside a message. Email sends all
+// attachments and Telegram sends images as photos; other channels ignore them.
+type Attachment struct {
+	Filename    string // name shown to the recipient
+	ContentType string // MIME type, e.g. "image/png"
+	Data        []byte
+}
+
+// NewAttachment builds an attachment from in-memory data. The content type is
+// derived from the filename extension, falling back to content sniffing.
+func NewAttachment(filename string, data []byte) Attachment {
+	ct := mime.TypeByExtension(filepath.Ext(filename))
+	if ct == "" {
+		ct = http.DetectContentType(data)
+	}
+	return Attachment{Filename: filename, ContentType: ct, Data: data}
+}
+
+// AttachFile reads a file from disk into an attachment.
+func AttachFile(path string) (Attachment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Attachment{}, fmt.Errorf("read attachment %s: %w", path, err)
+	}
+	return NewAttachment(filepath.Base(path), data), nil
+}
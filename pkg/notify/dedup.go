@@ -0,0 +1,40 @@
+package notify
+
+import "context"
+
+// DeliveryLog records which routes a keyed message has reached, so a digest
+// that is dispatched again (e.g. retried after a partial failure) is not
+// delivered twice on the channels that already succeeded.
+type DeliveryLog interface {
+	WasDelivered(ctx context.Context, key string, route Route) (bool, error)
+	MarkDelivered(ctx context.Context, key string, route Route) error
+}
+
+// SetDeliveryLog enables cross-channel deduplication for messages that carry
+// an IdempotencyKey.
+func (d *Dispatcher) SetDeliveryLog(l DeliveryLog) {
+	d.deliveries = l
+}
+
+// alreadyDelivered reports whether route already received the message keyed by key.
+// Lookup errors are logged and treated as "not delivered".
+func (d *Dispatcher) alreadyDelivered(ctx context.Context, key string, route Route) bool {
+	if key == "" || d.deliveries == nil {
+		return false
+	}
+	done, err := d.deliveries.WasDelivered(ctx, key, route)
+	if err != nil {
+		d.logger.Warn("delivery log lookup failed", "channel", route.Channel, "key", key, "error", err)
+		return false
+	}
+	return done
+}
+
+func (d *Dispatcher) markDelivered(ctx context.Context, key string, route Route) {
+	if key == "" || d.deliveries == nil {
+		return
+	}
+	if err := d.deliveries.MarkDelivered(ctx, key, route); err != nil {
+		d.logger.Warn("failed to record delivery", "channel", route.Channel, "key", key, "error", err)
+	}
+}
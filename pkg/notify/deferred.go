@@ -0,0 +1,120 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Delivery is one message bound for one route, in a form that can be stored
+// and sent later.
+type Delivery struct {
+	Recipient      string         `json:"recipient"`
+	Route          Route          `json:"route"`
+	Title          string         `json:"title"`
+	Body           string         `json:"body"`
+	HTMLBody       string         `json:"html_body,omitempty"`
+	Format         string         `json:"format"`
+	URL            string         `json:"url,omitempty"`
+	Payload        string         `json:"payload,omitempty"`
+	Embeds         []DiscordEmbed `json:"embeds,omitempty"`
+	IdempotencyKey string         `json:"idempotency_key,omitempty"`
+	Bulk           bool           `json:"bulk,omitempty"`
+	UnsubscribeURL string         `json:"unsubscribe_url,omitempty"`
+	Attachments    []Attachment   `json:"attachments,omitempty"`
+}
+
+// NewDelivery captures msg for route.
+func NewDelivery(recipient string, route Route, msg Message) Delivery {
+	return Delivery{
+		Recipient:      recipient,
+		Route:          route,
+		Title:          msg.Title,
+		Body:           msg.Body,
+		HTMLBody:       msg.HTMLBody,
+		Format:         msg.Format,
+		URL:            msg.URL,
+		Payload:        msg.Payload,
+		Embeds:         msg.Embeds,
+		IdempotencyKey: msg.IdempotencyKey,
+		Bulk:           msg.Bulk,
+		UnsubscribeURL: msg.UnsubscribeURL,
+		Attachments:    msg.Attachments,
+	}
+}
+
+// Message rebuilds the message to send.
+func (dl Delivery) Message() Message {
+	return Message{
+		Title:          dl.Title,
+		Body:           dl.Body,
+		HTMLBody:       dl.HTMLBody,
+		Format:         dl.Format,
+		URL:            dl.URL,
+		Payload:        dl.Payload,
+		Embeds:         dl.Embeds,
+		IdempotencyKey: dl.IdempotencyKey,
+		Bulk:           dl.Bulk,
+		UnsubscribeURL: dl.UnsubscribeURL,
+		Attachments:    dl.Attachments,
+	}
+}
+
+// DeferFunc hands a delivery to a queue instead of sending it inline. The
+// queue sends it later with Dispatcher.Deliver, retrying on failure.
+type DeferFunc func(ctx context.Context, dl Delivery) error
+
+// SetDeferred makes DispatchTo queue deliveries on ch through f rather than
+// sending them, so a slow or failing endpoint is retried in the background
+// without holding up the other routes.
+func (d *Dispatcher) SetDeferred(ch Channel, f DeferFunc) {
+	if d.deferred == nil {
+		d.deferred = make(map[Channel]DeferFunc)
+	}
+	d.deferred[ch] = f
+}
+
+// RetryFunc queues a delivery whose send failed with err, to be sent again
+// later with Dispatcher.Deliver.
+type RetryFunc func(ctx context.Context, dl Delivery, err error) error
+
+// SetRetry makes DispatchTo hand sends that fail to f, so a transient SMTP
+// or Telegram failure delays a message instead of dropping it. A route whose
+// delivery was queued counts as handled, like a deferred one.
+func (d *Dispatcher) SetRetry(f RetryFunc) {
+	d.retry = f
+}
+
+// RetryBackoff is the delay before resending after the given failed
+// attempt: one minute doubling each time, capped at six hours.
+func RetryBackoff(attempt int) time.Duration {
+	d := time.Minute
+	for i := 1; i < attempt && d < 6*time.Hour; i++ {
+		d *= 2
+	}
+	return min(d, 6*time.Hour)
+}
+
+// Deliver sends a delivery now. Like DispatchTo it skips routes the delivery
+// log says were already reached and records successful sends.
+func (d *Dispatcher) Deliver(ctx context.Context, dl Delivery) error {
+	return d.deliver(ctx, dl.Recipient, dl.Route, dl.Message())
+}
+
+// deliver sends msg to one route of recipient, as Deliver does.
+func (d *Dispatcher) deliver(ctx context.Context, recipient string, route Route, msg Message) error {
+	notifier, ok := d.notifierFor(route)
+	if !ok {
+		return fmt.Errorf("notifier not registered: %s", route.Channel)
+	}
+	if d.alreadyDelivered(ctx, msg.IdempotencyKey, route) {
+		d.logger.Info("notification already delivered", "channel", route.Channel, "recipient", recipient, "key", msg.IdempotencyKey)
+		return nil
+	}
+	if err := d.send(ctx, notifier, route.Channel, msg); err != nil {
+		return fmt.Errorf("%s: %w", route.Channel, err)
+	}
+	d.logger.Info("notification sent", "channel", route.Channel, "recipient", recipient, "title", msg.Title)
+	d.markDelivered(ctx, msg.IdempotencyKey, route)
+	return nil
+}
@@ -0,0 +1,212 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DiscordConfig holds Discord webhook configuration.
+type DiscordConfig struct {
+	WebhookURL string `yaml:"webhook_url" json:"webhook_url"`
+	Username   string `yaml:"username" json:"username"`     // overrides the webhook's name
+	AvatarURL  string `yaml:"avatar_url" json:"avatar_url"` // overrides the webhook's avatar
+}
+
+// DiscordEmbed is a Discord rich embed, as rendered by the Discord
+// formatters into Message.Embeds.
+type DiscordEmbed struct {
+	Title       string              `json:"title,omitempty"`
+	Description string              `json:"description,omitempty"`
+	URL         string              `json:"url,omitempty"`
+	Color       int                 `json:"color,omitempty"`
+	Fields      []DiscordEmbedField `json:"fields,omitempty"`
+	Footer      *DiscordEmbedFooter `json:"footer,omitempty"`
+}
+
+// DiscordEmbedField is a titled block inside an embed.
+type DiscordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// DiscordEmbedFooter is the small text under an embed.
+type DiscordEmbedFooter struct {
+	Text string `json:"text"`
+}
+
+// Discord webhook limits, in characters.
+const (
+	discordMaxContent     = 2000
+	discordMaxEmbeds      = 10
+	discordMaxTitle       = 256
+	discordMaxDescription = 4096
+	discordMaxFields      = 25
+	discordMaxFieldName   = 256
+	discordMaxFieldValue  = 1024
+	discordMaxFooter      = 2048
+	discordMaxEmbedTotal  = 6000 // all titles, descriptions, fields and footers
+)
+
+// SeverityColor returns the embed color for a severity or importance level,
+// matching ImportanceBadgeHTML.
+func SeverityColor(level string) int {
+	switch level {
+	case "high", "critical":
+		return 0xe53935
+	case "medium", "important":
+		return 0xff9800
+	case "low", "minor":
+		return 0x4caf50
+	default:
+		return 0x607d8b
+	}
+}
+
+// DiscordNotifier posts messages to a Discord channel through a webhook.
+type DiscordNotifier struct {
+	config DiscordConfig
+	http   *http.Client
+}
+
+// NewDiscordNotifier creates a new Discord notifier.
+func NewDiscordNotifier(cfg DiscordConfig) *DiscordNotifier {
+	return &DiscordNotifier{
+		config: cfg,
+		http:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (d *DiscordNotifier) Channel() Channel { return ChannelDiscord }
+
+type discordPayload struct {
+	Content   string         `json:"content,omitempty"`
+	Username  string         `json:"username,omitempty"`
+	AvatarURL string         `json:"avatar_url,omitempty"`
+	Embeds    []DiscordEmbed `json:"embeds,omitempty"`
+}
+
+// Send posts a message. Pre-rendered msg.Embeds are sent under the title;
+// otherwise the title, body and URL become a single embed. Image
+// attachments are uploaded with the message; other attachments are skipped.
+func (d *DiscordNotifier) Send(ctx context.Context, msg Message) error {
+	payload := discordPayload{
+		Username:  d.config.Username,
+		AvatarURL: d.config.AvatarURL,
+	}
+	if len(msg.Embeds) > 0 {
+		payload.Content = truncateRunes(msg.Title, discordMaxContent)
+		payload.Embeds = FitDiscordEmbeds(msg.Embeds)
+	} else {
+		payload.Embeds = FitDiscordEmbeds([]DiscordEmbed{{
+			Title:       msg.Title,
+			Description: msg.Body,
+			URL:         msg.URL,
+		}})
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	contentType := "application/json"
+
+	var images []Attachment
+	for _, a := range msg.Attachments {
+		if strings.HasPrefix(a.ContentType, "image/") {
+			images = append(images, a)
+		}
+	}
+	if len(images) > 0 {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		if err := mw.WriteField("payload_json", string(body)); err != nil {
+			return err
+		}
+		for i, a := range images[:min(len(images), discordMaxEmbeds)] {
+			part, err := mw.CreateFormFile(fmt.Sprintf("files[%d]", i), a.Filename)
+			if err != nil {
+				return err
+			}
+			if _, err := part.Write(a.Data); err != nil {
+				return err
+			}
+		}
+		if err := mw.Close(); err != nil {
+			return err
+		}
+		body, contentType = buf.Bytes(), mw.FormDataContentType()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", d.config.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := d.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("send discord message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("discord API error (%d): %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// FitDiscordEmbeds trims embeds to Discord's limits: at most 10 embeds of 25
+// fields each, every text within its own limit and 6000 characters in all.
+// Text over a limit is cut with "…"; fields and embeds past the total are
+// dropped.
+func FitDiscordEmbeds(embeds []DiscordEmbed) []DiscordEmbed {
+	left := discordMaxEmbedTotal
+	take := func(s string, max int) string {
+		if left <= 0 {
+			return ""
+		}
+		s = truncateRunes(s, min(max, left))
+		left -= utf8.RuneCountInString(s)
+		return s
+	}
+
+	out := make([]DiscordEmbed, 0, min(len(embeds), discordMaxEmbeds))
+	for _, e := range embeds[:min(len(embeds), discordMaxEmbeds)] {
+		if left <= 0 {
+			break
+		}
+		fit := DiscordEmbed{URL: e.URL, Color: e.Color}
+		fit.Title = take(e.Title, discordMaxTitle)
+		fit.Description = take(e.Description, discordMaxDescription)
+		for _, f := range e.Fields[:min(len(e.Fields), discordMaxFields)] {
+			// a field needs both a name and a value
+			if left < 2 {
+				break
+			}
+			name := take(f.Name, discordMaxFieldName)
+			if name == "" {
+				name = "​"
+			}
+			value := take(f.Value, discordMaxFieldValue)
+			if value == "" {
+				value = "​"
+			}
+			fit.Fields = append(fit.Fields, DiscordEmbedField{Name: name, Value: value, Inline: f.Inline})
+		}
+		if e.Footer != nil && left > 0 {
+			fit.Footer = &DiscordEmbedFooter{Text: take(e.Footer.Text, discordMaxFooter)}
+		}
+		out = append(out, fit)
+	}
+	return out
+}
@@ -2,9 +2,14 @@ package notify
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
 	"crypto/tls"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"mime"
+	"mime/quotedprintable"
 	"net"
 	"net/smtp"
 	"strings"
@@ -18,6 +23,11 @@ type EmailConfig struct {
 	From     string // sender email
 	Password string // SMTP password or app-specific password
 	To       string // comma-separated recipient emails
+
+	// UnsubscribeMailto is the address for mailto List-Unsubscribe requests
+	// on bulk mail. Empty leaves the mailto out: the sender's inbox is not a
+	// place where unsubscribe requests get processed.
+	UnsubscribeMailto string
 }
 
 type emailNotifier struct {
@@ -46,7 +56,7 @@ func (e *emailNotifier) Send(ctx context.Context, msg Message) error {
 		recipients[i] = strings.TrimSpace(recipients[i])
 	}
 
-	body := buildEmailBody(e.cfg.From, recipients, msg)
+	body := buildEmailBody(e.cfg, recipients, msg)
 
 	var client *smtp.Client
 	var err error
@@ -134,24 +144,149 @@ func encodeRFC2047(s string) string {
 	return "=?UTF-8?B?" + base64.StdEncoding.EncodeToString([]byte(s)) + "?="
 }
 
-func buildEmailBody(from string, to []string, msg Message) string {
+func buildEmailBody(cfg EmailConfig, to []string, msg Message) string {
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("From: =?UTF-8?B?%s?= <%s>\r\n",
-		base64.StdEncoding.EncodeToString([]byte("DevKit NewsBot")), from))
+		base64.StdEncoding.EncodeToString([]byte("DevKit NewsBot")), cfg.From))
 	sb.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(to, ", ")))
 	sb.WriteString(fmt.Sprintf("Subject: %s\r\n", encodeRFC2047(msg.Title)))
+	sb.WriteString(fmt.Sprintf("Date: %s\r\n", time.Now().Format(time.RFC1123Z)))
+	sb.WriteString(fmt.Sprintf("Message-ID: %s\r\n", messageID(cfg.From, msg.IdempotencyKey)))
 	sb.WriteString("MIME-Version: 1.0\r\n")
-	sb.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
-	sb.WriteString("Content-Transfer-Encoding: base64\r\n")
-	sb.WriteString("\r\n")
 
-	// Use pre-rendered HTML if available, otherwise plain text
+	// Bulk-mail headers: without these, digests are increasingly filed as
+	// spam. Transactional mail such as team invites goes without them.
+	if msg.Bulk || msg.UnsubscribeURL != "" {
+		sb.WriteString("Precedence: bulk\r\n")
+		sb.WriteString("Auto-Submitted: auto-generated\r\n")
+		sb.WriteString(listUnsubscribeHeaders(cfg, msg))
+	}
+
+	// Plain-text mode sends the text body as the only part, for mail gateways
+	// that strip HTML. Otherwise use pre-rendered HTML if available.
+	plain := msg.Format == "plain"
 	htmlContent := msg.HTMLBody
 	if htmlContent == "" {
 		htmlContent = "<pre>" + msg.Body + "</pre>"
 	}
-	sb.WriteString(base64.StdEncoding.EncodeToString([]byte(htmlContent)))
 
+	if len(msg.Attachments) == 0 {
+		if plain {
+			sb.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+			sb.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+			sb.WriteString("\r\n")
+			sb.WriteString(quotedPrintable(msg.Body))
+			return sb.String()
+		}
+		sb.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+		sb.WriteString("Content-Transfer-Encoding: base64\r\n")
+		sb.WriteString("\r\n")
+		sb.WriteString(base64.StdEncoding.EncodeToString([]byte(htmlContent)))
+		return sb.String()
+	}
+
+	// multipart/mixed: message body followed by one part per attachment
+	boundary := mimeBoundary()
+	sb.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=%q\r\n", boundary))
+	sb.WriteString("\r\n")
+
+	sb.WriteString("--" + boundary + "\r\n")
+	if plain {
+		sb.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+		sb.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+		sb.WriteString("\r\n")
+		sb.WriteString(quotedPrintable(msg.Body) + "\r\n")
+	} else {
+		sb.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+		sb.WriteString("Content-Transfer-Encoding: base64\r\n")
+		sb.WriteString("\r\n")
+		sb.WriteString(wrapBase64([]byte(htmlContent)))
+	}
+
+	for _, a := range msg.Attachments {
+		ct := a.ContentType
+		if ct == "" {
+			ct = "application/octet-stream"
+		}
+		name := mime.QEncoding.Encode("UTF-8", a.Filename)
+		sb.WriteString("--" + boundary + "\r\n")
+		sb.WriteString(fmt.Sprintf("Content-Type: %s; name=%q\r\n", ct, name))
+		sb.WriteString("Content-Transfer-Encoding: base64\r\n")
+		sb.WriteString(fmt.Sprintf("Content-Disposition: attachment; filename=%q\r\n", name))
+		sb.WriteString("\r\n")
+		sb.WriteString(wrapBase64(a.Data))
+	}
+	sb.WriteString("--" + boundary + "--\r\n")
+
+	return sb.String()
+}
+
+// listUnsubscribeHeaders renders List-Unsubscribe (RFC 2369) with the
+// configured mailto address and, when the message carries one, a one-click
+// URL (RFC 8058).
+func listUnsubscribeHeaders(cfg EmailConfig, msg Message) string {
+	var targets []string
+	if cfg.UnsubscribeMailto != "" {
+		targets = append(targets, fmt.Sprintf("<mailto:%s?subject=unsubscribe>", cfg.UnsubscribeMailto))
+	}
+	if msg.UnsubscribeURL != "" {
+		targets = append(targets, "<"+msg.UnsubscribeURL+">")
+	}
+	if len(targets) == 0 {
+		return ""
+	}
+	h := fmt.Sprintf("List-Unsubscribe: %s\r\n", strings.Join(targets, ", "))
+	if msg.UnsubscribeURL != "" {
+		h += "List-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n"
+	}
+	return h
+}
+
+// messageID returns a unique Message-ID using the sender's domain. With an
+// idempotency key the ID is stable, so mail systems can drop resent copies.
+func messageID(from, key string) string {
+	domain := "localhost"
+	if _, d, ok := strings.Cut(from, "@"); ok && d != "" {
+		domain = d
+	}
+	if key != "" {
+		sum := sha256.Sum256([]byte(key))
+		return fmt.Sprintf("<%s@%s>", hex.EncodeToString(sum[:12]), domain)
+	}
+	var buf [12]byte
+	_, _ = rand.Read(buf[:])
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(buf[:]), domain)
+}
+
+// quotedPrintable encodes text as quoted-printable (RFC 2045) with CRLF line
+// breaks and soft-wrapped 76-character lines.
+func quotedPrintable(text string) string {
+	var buf strings.Builder
+	w := quotedprintable.NewWriter(&buf)
+	_, _ = w.Write([]byte(strings.ReplaceAll(text, "\r\n", "\n")))
+	_ = w.Close()
+	return buf.String()
+}
+
+// mimeBoundary returns a random multipart boundary.
+func mimeBoundary() string {
+	var buf [16]byte
+	_, _ = rand.Read(buf[:])
+	return "devkit-" + hex.EncodeToString(buf[:])
+}
+
+// wrapBase64 encodes data as base64 split into 76-character lines (RFC 2045).
+func wrapBase64(data []byte) string {
+	const lineLen = 76
+	enc := base64.StdEncoding.EncodeToString(data)
+	var sb strings.Builder
+	for len(enc) > lineLen {
+		sb.WriteString(enc[:lineLen])
+		sb.WriteString("\r\n")
+		enc = enc[lineLen:]
+	}
+	sb.WriteString(enc)
+	sb.WriteString("\r\n")
 	return sb.String()
 }
@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// SeverityOff disables a channel in an EscalationPolicy.
+const SeverityOff = "off"
+
+// EscalationPolicy sets the minimum severity ("minor", "important", "critical")
+// at which each channel is used. Channels not listed are used for critical
+// notifications only; SeverityOff disables a channel entirely.
+type EscalationPolicy map[Channel]string
+
+// DefaultEscalation delivers minor changes by email, adds Telegram for important
+// ones, and uses every channel (SMS, webhooks, push, ...) for critical ones.
+var DefaultEscalation = EscalationPolicy{
+	ChannelEmail:    "minor",
+	ChannelTelegram: "important",
+}
+
+// Allows reports whether a notification of the given severity goes to ch.
+func (p EscalationPolicy) Allows(ch Channel, severity string) bool {
+	min, ok := p[ch]
+	if !ok {
+		min = "critical"
+	}
+	if min == SeverityOff {
+		return false
+	}
+	return SeverityAtLeast(severity, min)
+}
+
+// SeverityAtLeast reports whether severity is min or higher. Unknown levels
+// rank below "minor".
+func SeverityAtLeast(severity, min string) bool {
+	return severityRank(severity) >= severityRank(min)
+}
+
+// Filter returns the recipient with only the routes allowed at severity.
+func (p EscalationPolicy) Filter(r Recipient, severity string) Recipient {
+	filtered := Recipient{ID: r.ID, Escalation: r.Escalation}
+	for _, route := range r.Routes {
+		if p.Allows(route.Channel, severity) {
+			filtered.Routes = append(filtered.Routes, route)
+		}
+	}
+	return filtered
+}
+
+// ParseEscalationPolicy parses a policy stored as JSON, e.g.
+// {"email":"minor","telegram":"important","webhook":"critical","sms":"off"}.
+func ParseEscalationPolicy(s string) (EscalationPolicy, error) {
+	var p EscalationPolicy
+	if err := json.Unmarshal([]byte(s), &p); err != nil {
+		return nil, fmt.Errorf("parse escalation policy: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Validate reports whether every channel's level is a severity or SeverityOff.
+func (p EscalationPolicy) Validate() error {
+	for ch, sev := range p {
+		if sev != SeverityOff && severityRank(sev) == 0 {
+			return fmt.Errorf("invalid severity %q for channel %s", sev, ch)
+		}
+	}
+	return nil
+}
+
+// SetEscalation sets the policy used by DispatchSeverity for recipients
+// without their own. A nil policy sends every severity on every route.
+func (d *Dispatcher) SetEscalation(p EscalationPolicy) {
+	d.escalation = p
+}
+
+// DispatchSeverity sends a message along the recipient's routes that the
+// escalation policy allows for the given severity. The recipient's own policy
+// takes precedence over the dispatcher default.
+func (d *Dispatcher) DispatchSeverity(ctx context.Context, r Recipient, severity string, msg Message) error {
+	if msg.Severity == "" {
+		msg.Severity = severity
+	}
+	policy := r.Escalation
+	if policy == nil {
+		policy = d.escalation
+	}
+	if policy != nil {
+		r = policy.Filter(r, severity)
+	}
+	if len(r.Routes) == 0 {
+		d.logger.Info("no channels for severity", "recipient", r.ID, "severity", severity)
+		return nil
+	}
+	return d.DispatchTo(ctx, r, msg)
+}
+
+// MaxSeverity returns the highest of the given severity levels.
+func MaxSeverity(levels ...string) string {
+	max := ""
+	for _, s := range levels {
+		if max == "" || severityRank(s) > severityRank(max) {
+			max = s
+		}
+	}
+	return max
+}
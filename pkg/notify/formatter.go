@@ -15,6 +15,8 @@ import (
 	"html"
 	"regexp"
 	"strings"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/differ"
 )
 
 // Message is the shared output type for all formatters.
@@ -172,6 +174,26 @@ func DiffStatsHTML(additions, deletions int) string {
 		additions, deletions)
 }
 
+// InlineDiffMaxLines caps the diff lines rendered by DiffHTML to keep emails small.
+const InlineDiffMaxLines = 80
+
+// DiffHTML renders a unified diff as a collapsed <details> block with added
+// lines in green, removed lines in red and the changed words of edited lines
+// highlighted. Clients without <details> support show the diff expanded.
+// Returns "" for an empty diff.
+func DiffHTML(unified, summary string) string {
+	rendered := differ.RenderHTML(differ.ParseUnified(unified), &differ.RenderOptions{
+		Layout:  differ.LayoutInline, // two columns are too narrow in a 600px email
+		MaxRows: InlineDiffMaxLines,
+	})
+	if rendered == "" {
+		return ""
+	}
+	return fmt.Sprintf(`<details style="margin-top:8px;"><summary style="cursor:pointer;color:#ff9800;font-size:12px;">%s</summary>`+
+		`<div style="margin-top:6px;">%s</div></details>`,
+		html.EscapeString(summary), rendered)
+}
+
 // TagsHTML renders a list of tags as styled pills.
 func TagsHTML(tags []string) string {
 	if len(tags) == 0 {
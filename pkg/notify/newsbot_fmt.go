@@ -253,3 +253,65 @@ func (f *NewsTelegramFormatter) Format(data NewsDigestData) Message {
 		Format: "markdown",
 	}
 }
+
+// ---- NewsBot Discord Formatter ----
+
+// NewsDiscordFormatter produces Discord embeds for NewsBot: an overview embed
+// followed by one embed per headline, colored by importance.
+type NewsDiscordFormatter struct{}
+
+func NewNewsDiscordFormatter() *NewsDiscordFormatter { return &NewsDiscordFormatter{} }
+
+func (f *NewsDiscordFormatter) Format(data NewsDigestData) Message {
+	overview := DiscordEmbed{
+		Title:       fmt.Sprintf("🤖 %s — %s", data.Labels.DailyTitle, data.Date),
+		Description: data.Summary,
+		Color:       0x667eea,
+		Footer:      &DiscordEmbedFooter{Text: data.Labels.GeneratedBy},
+	}
+	embeds := []DiscordEmbed{overview}
+
+	for i, h := range data.Headlines {
+		// Discord allows 10 embeds; the last one links whatever is left
+		if len(embeds) == discordMaxEmbeds-1 && len(data.Headlines)-i > 1 {
+			var lines []string
+			for j, rest := range data.Headlines[i:] {
+				line := fmt.Sprintf("%s %d. %s", ImportanceEmoji(rest.Importance), i+j+1, rest.Title)
+				if rest.URL != "" {
+					line = fmt.Sprintf("%s %d. [%s](%s)", ImportanceEmoji(rest.Importance), i+j+1, rest.Title, rest.URL)
+				}
+				lines = append(lines, line)
+			}
+			embeds = append(embeds, DiscordEmbed{
+				Description: strings.Join(lines, "\n"),
+				Color:       SeverityColor(""),
+			})
+			break
+		}
+
+		embed := DiscordEmbed{
+			Title:       fmt.Sprintf("%s %d. %s", ImportanceEmoji(h.Importance), i+1, h.Title),
+			Description: truncateRunes(h.Summary, 400),
+			URL:         h.URL,
+			Color:       SeverityColor(h.Importance),
+		}
+		var footer []string
+		if h.Source != "" {
+			footer = append(footer, data.Labels.Source+": "+h.Source)
+		}
+		if len(h.Tags) > 0 {
+			footer = append(footer, "#"+strings.Join(h.Tags, " #"))
+		}
+		if len(footer) > 0 {
+			embed.Footer = &DiscordEmbedFooter{Text: strings.Join(footer, " · ")}
+		}
+		embeds = append(embeds, embed)
+	}
+
+	return Message{
+		Title:  fmt.Sprintf("🤖 **%s** — %s", data.Labels.DailyTitle, data.Date),
+		Body:   data.Summary,
+		Format: "markdown",
+		Embeds: embeds,
+	}
+}
@@ -1,21 +1,28 @@
 // Package notify provides a unified notification dispatch system
-// supporting Telegram, Email, Slack, and Webhook channels.
+// supporting Telegram, Email, Slack, Discord, WeChat Work, Webhook, SMS, and push (ntfy/Pushover) channels.
 package notify
 
 import (
 	"context"
 	"fmt"
 	"log/slog"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/metrics"
 )
 
 // Channel represents a notification channel type.
 type Channel string
 
 const (
-	ChannelTelegram Channel = "telegram"
-	ChannelEmail    Channel = "email"
-	ChannelSlack    Channel = "slack"
-	ChannelWebhook  Channel = "webhook"
+	ChannelTelegram   Channel = "telegram"
+	ChannelEmail      Channel = "email"
+	ChannelSlack      Channel = "slack"
+	ChannelDiscord    Channel = "discord"
+	ChannelWeChatWork Channel = "wechatwork"
+	ChannelWebhook    Channel = "webhook"
+	ChannelSMS        Channel = "sms"
+	ChannelNtfy       Channel = "ntfy"
+	ChannelPushover   Channel = "pushover"
 )
 
 // Message represents a notification message.
@@ -23,8 +30,35 @@ type Message struct {
 	Title    string `json:"title"`
 	Body     string `json:"body"`
 	HTMLBody string `json:"html_body,omitempty"` // Rich HTML for email
-	Format   string `json:"format"`              // "markdown", "html", "plain"
+	Format   string `json:"format"`              // "markdown", "html", "plain", "json"
 	URL      string `json:"url,omitempty"`
+
+	// Attachments are delivered by email, and images also by Telegram.
+	Attachments []Attachment `json:"-"`
+	// UnsubscribeURL is the recipient's one-click unsubscribe link (email only).
+	UnsubscribeURL string `json:"-"`
+	// Bulk marks list mail such as digests and reports, which email sends
+	// with Precedence: bulk, Auto-Submitted and List-Unsubscribe headers.
+	// Messages with an UnsubscribeURL are always bulk.
+	Bulk bool `json:"-"`
+	// Payload is a pre-rendered JSON body (see TemplateFormatter); when set,
+	// the webhook channel posts it verbatim instead of the default payload.
+	Payload string `json:"-"`
+	// Embeds are pre-rendered Discord embeds (see WatchDiscordFormatter);
+	// when set, the Discord channel sends them instead of Title and Body.
+	Embeds []DiscordEmbed `json:"-"`
+	// WeChatWork is pre-rendered WeChat Work content (see
+	// WatchWeChatWorkFormatter); when set, the WeChat Work channel sends it
+	// instead of Title and Body.
+	WeChatWork *WeChatWorkContent `json:"-"`
+	// Severity is the level of what the message reports ("minor",
+	// "important", "critical"); ntfy and Pushover map it to their priority.
+	// DispatchSeverity sets it when empty.
+	Severity string `json:"-"`
+	// IdempotencyKey identifies one logical notification (e.g. a digest ID).
+	// The dispatcher uses it to skip routes that already received the message,
+	// and notifiers forward it to APIs that deduplicate requests.
+	IdempotencyKey string `json:"-"`
 }
 
 // Notifier defines the interface for sending notifications.
@@ -33,24 +67,54 @@ type Notifier interface {
 	Channel() Channel
 }
 
+// NotifierFactory builds a notifier bound to a recipient-specific target,
+// e.g. an email address, a Telegram chat ID, or a webhook URL.
+type NotifierFactory func(target string) Notifier
+
+// Route binds a channel to the address a recipient wants it delivered to.
+// An empty Target uses the globally registered notifier for the channel.
+type Route struct {
+	Channel Channel `json:"channel"`
+	Target  string  `json:"target,omitempty"`
+}
+
+// Recipient is a per-user delivery profile: one user may get email+Telegram,
+// another only a webhook.
+type Recipient struct {
+	ID     string  `json:"id"`
+	Routes []Route `json:"routes"`
+	// Escalation overrides the dispatcher's default policy for this recipient.
+	Escalation EscalationPolicy `json:"escalation,omitempty"`
+}
+
 // Dispatcher routes messages to the appropriate notification channels.
 type Dispatcher struct {
-	notifiers map[Channel]Notifier
-	emailCfg  EmailConfig
-	logger    *slog.Logger
+	notifiers  map[Channel]Notifier
+	factories  map[Channel]NotifierFactory
+	emailCfg   EmailConfig
+	escalation EscalationPolicy      // default severity → channel rules; nil sends everywhere
+	deliveries DeliveryLog           // skips routes already reached for an idempotency key
+	deferred   map[Channel]DeferFunc // channels queued for background delivery, see SetDeferred
+	retry      RetryFunc             // queues failed sends, see SetRetry
+	logger     *slog.Logger
 }
 
 // NewDispatcher creates a new notification dispatcher.
 func NewDispatcher() *Dispatcher {
 	return &Dispatcher{
 		notifiers: make(map[Channel]Notifier),
+		factories: make(map[Channel]NotifierFactory),
 		logger:    slog.Default(),
 	}
 }
 
-// SetEmailConfig stores the email configuration for per-recipient dispatch.
+// SetEmailConfig stores the email configuration for per-recipient dispatch
+// and registers the email factory used for recipient-specific routes.
 func (d *Dispatcher) SetEmailConfig(cfg EmailConfig) {
 	d.emailCfg = cfg
+	d.RegisterFactory(ChannelEmail, func(to string) Notifier {
+		return NewEmailNotifierForRecipient(cfg, to)
+	})
 }
 
 // EmailConfig returns the stored email configuration.
@@ -63,6 +127,86 @@ func (d *Dispatcher) Register(n Notifier) {
 	d.notifiers[n.Channel()] = n
 }
 
+// RegisterFactory adds a factory for building recipient-specific notifiers.
+func (d *Dispatcher) RegisterFactory(ch Channel, f NotifierFactory) {
+	d.factories[ch] = f
+}
+
+// HasChannel reports whether the channel can be delivered to, either through a
+// registered notifier or a recipient factory.
+func (d *Dispatcher) HasChannel(ch Channel) bool {
+	_, ok := d.notifiers[ch]
+	if !ok {
+		_, ok = d.factories[ch]
+	}
+	return ok
+}
+
+// Channels returns all channels with a registered notifier.
+func (d *Dispatcher) Channels() []Channel {
+	channels := make([]Channel, 0, len(d.notifiers))
+	for ch := range d.notifiers {
+		channels = append(channels, ch)
+	}
+	return channels
+}
+
+// notifierFor resolves the notifier for a route. Targeted routes prefer the
+// channel factory; untargeted routes use the registered notifier.
+func (d *Dispatcher) notifierFor(r Route) (Notifier, bool) {
+	if r.Target != "" {
+		if f, ok := d.factories[r.Channel]; ok {
+			return f(r.Target), true
+		}
+	}
+	n, ok := d.notifiers[r.Channel]
+	return n, ok
+}
+
+// DispatchTo sends a message along each of the recipient's routes.
+func (d *Dispatcher) DispatchTo(ctx context.Context, r Recipient, msg Message) error {
+	var errs []error
+	for _, route := range r.Routes {
+		notifier, ok := d.notifierFor(route)
+		if !ok {
+			d.logger.Warn("notifier not registered", "channel", route.Channel, "recipient", r.ID)
+			continue
+		}
+		if d.alreadyDelivered(ctx, msg.IdempotencyKey, route) {
+			d.logger.Info("notification already delivered", "channel", route.Channel, "recipient", r.ID, "key", msg.IdempotencyKey)
+			continue
+		}
+		if queue, ok := d.deferred[route.Channel]; ok {
+			if err := queue(ctx, NewDelivery(r.ID, route, msg)); err != nil {
+				d.logger.Error("queue notification failed", "channel", route.Channel, "recipient", r.ID, "error", err)
+				errs = append(errs, fmt.Errorf("%s: %w", route.Channel, err))
+			} else {
+				d.logger.Info("notification queued", "channel", route.Channel, "recipient", r.ID, "title", msg.Title)
+			}
+			continue
+		}
+		if err := d.send(ctx, notifier, route.Channel, msg); err != nil {
+			if d.retry != nil {
+				qErr := d.retry(ctx, NewDelivery(r.ID, route, msg), err)
+				if qErr == nil {
+					d.logger.Warn("notification failed, queued for retry", "channel", route.Channel, "recipient", r.ID, "error", err)
+					continue
+				}
+				d.logger.Error("queue failed notification", "channel", route.Channel, "recipient", r.ID, "error", qErr)
+			}
+			d.logger.Error("notification failed", "channel", route.Channel, "recipient", r.ID, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", route.Channel, err))
+		} else {
+			d.logger.Info("notification sent", "channel", route.Channel, "recipient", r.ID, "title", msg.Title)
+			d.markDelivered(ctx, msg.IdempotencyKey, route)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to send %d/%d notifications to %s", len(errs), len(r.Routes), r.ID)
+	}
+	return nil
+}
+
 // Dispatch sends a message to the specified channels.
 func (d *Dispatcher) Dispatch(ctx context.Context, channels []Channel, msg Message) error {
 	var errs []error
@@ -72,7 +216,7 @@ func (d *Dispatcher) Dispatch(ctx context.Context, channels []Channel, msg Messa
 			d.logger.Warn("notifier not registered", "channel", ch)
 			continue
 		}
-		if err := notifier.Send(ctx, msg); err != nil {
+		if err := d.send(ctx, notifier, ch, msg); err != nil {
 			d.logger.Error("notification failed", "channel", ch, "error", err)
 			errs = append(errs, fmt.Errorf("%s: %w", ch, err))
 		} else {
@@ -85,11 +229,19 @@ func (d *Dispatcher) Dispatch(ctx context.Context, channels []Channel, msg Messa
 	return nil
 }
 
+// send sends msg with notifier and counts the outcome in the
+// notifications metric.
+func (d *Dispatcher) send(ctx context.Context, notifier Notifier, ch Channel, msg Message) error {
+	err := notifier.Send(ctx, msg)
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	metrics.Notifications.Inc(string(ch), result)
+	return err
+}
+
 // SendAll sends a message to all registered channels.
 func (d *Dispatcher) SendAll(ctx context.Context, msg Message) error {
-	channels := make([]Channel, 0, len(d.notifiers))
-	for ch := range d.notifiers {
-		channels = append(channels, ch)
-	}
-	return d.Dispatch(ctx, channels, msg)
+	return d.Dispatch(ctx, d.Channels(), msg)
 }
@@ -0,0 +1,936 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RobinCoderZhao/devkit-suite/pkg/benchmarks"
+)
+
+func TestBuildEmailBody_SinglePart(t *testing.T) {
+	body := buildEmailBody(EmailConfig{From: "bot@example.com"}, []string{"a@example.com"}, Message{Title: "Hi", HTMLBody: "<p>x</p>"})
+	if !strings.Contains(body, "Content-Type: text/html; charset=UTF-8") {
+		t.Fatalf("expected single html part, got:\n%s", body)
+	}
+	if strings.Contains(body, "multipart/mixed") {
+		t.Fatal("did not expect multipart without attachments")
+	}
+}
+
+func TestBuildEmailBody_PlainText(t *testing.T) {
+	msg := Message{Title: "t", Body: "价格变化 = " + strings.Repeat("x", 100), HTMLBody: "<p>x</p>", Format: "plain"}
+	body := buildEmailBody(EmailConfig{From: "a@example.com"}, []string{"b@example.com"}, msg)
+	if !strings.Contains(body, "Content-Type: text/plain; charset=UTF-8") ||
+		!strings.Contains(body, "Content-Transfer-Encoding: quoted-printable") {
+		t.Fatalf("expected quoted-printable text part: %s", body)
+	}
+	if strings.Contains(body, "text/html") {
+		t.Fatal("plain-text mode must not include an HTML part")
+	}
+	if !strings.Contains(body, "=E4=BB=B7") || !strings.Contains(body, " =3D ") || !strings.Contains(body, "=\r\n") {
+		t.Fatalf("expected QP-encoded UTF-8, escaped '=' and soft line breaks: %s", body)
+	}
+}
+
+func TestBuildEmailBody_Attachments(t *testing.T) {
+	msg := Message{
+		Title:       "Report",
+		HTMLBody:    "<p>see attached</p>",
+		Attachments: []Attachment{NewAttachment("report.csv", []byte("a,b\n1,2\n"))},
+	}
+	body := buildEmailBody(EmailConfig{From: "bot@example.com"}, []string{"a@example.com"}, msg)
+
+	if !strings.Contains(body, "Content-Type: multipart/mixed; boundary=") {
+		t.Fatalf("expected multipart/mixed header, got:\n%s", body)
+	}
+	if !strings.Contains(body, `Content-Disposition: attachment; filename="report.csv"`) {
+		t.Fatalf("expected attachment disposition, got:\n%s", body)
+	}
+	for _, line := range strings.Split(body, "\r\n") {
+		if len(line) > 998 {
+			t.Fatalf("line exceeds SMTP limit: %d chars", len(line))
+		}
+	}
+}
+
+func TestBuildEmailBody_ListUnsubscribe(t *testing.T) {
+	msg := Message{Title: "Digest", UnsubscribeURL: "https://example.com/api/unsubscribe?token=abc"}
+	cfg := EmailConfig{From: "bot@example.com", UnsubscribeMailto: "unsubscribe@example.com"}
+	body := buildEmailBody(cfg, []string{"a@example.com"}, msg)
+
+	for _, want := range []string{
+		"List-Unsubscribe: <mailto:unsubscribe@example.com?subject=unsubscribe>, <https://example.com/api/unsubscribe?token=abc>",
+		"List-Unsubscribe-Post: List-Unsubscribe=One-Click",
+		"Precedence: bulk",
+		"@example.com>\r\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in headers, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestBuildEmailBody_BulkHeaders(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  EmailConfig
+		msg  Message
+		want []string // bulk headers expected; nil expects none
+	}{
+		{
+			name: "transactional",
+			cfg:  EmailConfig{From: "bot@example.com", UnsubscribeMailto: "unsubscribe@example.com"},
+			msg:  Message{Title: "You're invited"},
+		},
+		{
+			name: "digest without unsubscribe link",
+			cfg:  EmailConfig{From: "bot@example.com", UnsubscribeMailto: "unsubscribe@example.com"},
+			msg:  Message{Title: "Digest", Bulk: true},
+			want: []string{"Precedence: bulk", "Auto-Submitted: auto-generated", "List-Unsubscribe: <mailto:unsubscribe@example.com?subject=unsubscribe>\r\n"},
+		},
+		{
+			name: "no mailto configured",
+			cfg:  EmailConfig{From: "bot@example.com"},
+			msg:  Message{Title: "Digest", Bulk: true},
+			want: []string{"Precedence: bulk", "Auto-Submitted: auto-generated"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := buildEmailBody(tt.cfg, []string{"a@example.com"}, tt.msg)
+			for _, want := range tt.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("expected %q in headers, got:\n%s", want, body)
+				}
+			}
+			if tt.want == nil && (strings.Contains(body, "Precedence:") || strings.Contains(body, "Auto-Submitted:")) {
+				t.Errorf("unexpected bulk headers:\n%s", body)
+			}
+			if strings.Contains(body, "mailto:bot@example.com") {
+				t.Errorf("List-Unsubscribe must not default to From:\n%s", body)
+			}
+			if tt.cfg.UnsubscribeMailto == "" && strings.Contains(body, "List-Unsubscribe") {
+				t.Errorf("unexpected List-Unsubscribe without a target:\n%s", body)
+			}
+		})
+	}
+}
+
+func TestUnsubscribeToken(t *testing.T) {
+	secret := []byte("s3cret")
+	token := SignUnsubscribeToken(secret, 42, "watchbot")
+
+	userID, list, err := VerifyUnsubscribeToken(secret, token)
+	if err != nil || userID != 42 || list != "watchbot" {
+		t.Fatalf("unexpected verify result: %d %q %v", userID, list, err)
+	}
+	if _, _, err := VerifyUnsubscribeToken([]byte("other"), token); err == nil {
+		t.Fatal("expected signature mismatch with a different secret")
+	}
+}
+
+func TestTracker_Instrument(t *testing.T) {
+	secret := []byte("s3cret")
+	tr := NewTracker("https://example.com/", secret)
+	msg := Message{HTMLBody: `<html><body><a href="https://a.com/x?y=1&amp;z=2">x</a>` +
+		`<a href="https://example.com/api/unsubscribe?token=abc">u</a></body></html>`}
+
+	out := tr.Instrument(msg, "d1", 7).HTMLBody
+	if !strings.Contains(out, "https://example.com/api/t/click?t=") {
+		t.Fatalf("expected rewritten click link: %s", out)
+	}
+	if !strings.Contains(out, `href="https://example.com/api/unsubscribe?token=abc"`) {
+		t.Fatalf("unsubscribe link must not be rewritten: %s", out)
+	}
+	if !strings.Contains(out, "/api/t/open?t=") || !strings.HasSuffix(out, "</body></html>") {
+		t.Fatalf("expected pixel before </body>: %s", out)
+	}
+
+	ev, err := ParseTrackingToken(secret, "click", tr.clickToken("d1", 7, "https://a.com/x?y=1&z=2"))
+	if err != nil || ev.DigestID != "d1" || ev.UserID != 7 || ev.URL != "https://a.com/x?y=1&z=2" {
+		t.Fatalf("unexpected click event: %+v %v", ev, err)
+	}
+	if _, err := ParseTrackingToken(secret, "open", tr.clickToken("d1", 7, "https://a.com")); err == nil {
+		t.Fatal("click token must not verify as an open token")
+	}
+}
+
+func TestTemplateFormatter(t *testing.T) {
+	f, err := LoadTemplateFormatter[WatchDigestData]("../../config/webhook_pagerduty.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := WatchDigestData{
+		ChangeCount: 2,
+		Date:        "2026-03-01",
+		Groups: GroupChanges([]WatchChangeItem{
+			{CompetitorName: "Acme", PageType: "pricing", Severity: "minor", Analysis: `new "Pro" tier`},
+			{CompetitorName: "Globex", PageType: "docs", Severity: "critical"},
+		}),
+	}
+
+	msg := f.Format(data)
+	var payload struct {
+		Payload struct {
+			Severity string `json:"severity"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal([]byte(msg.Payload), &payload); err != nil {
+		t.Fatalf("invalid payload: %v\n%s", err, msg.Payload)
+	}
+	if payload.Payload.Severity != "critical" {
+		t.Fatalf("expected critical severity, got %q", payload.Payload.Severity)
+	}
+
+	bad, err := NewTemplateFormatter[WatchDigestData](`{"count": {{.ChangeCount}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg := bad.Format(data); msg.Payload != "" {
+		t.Fatalf("expected no payload for invalid JSON, got %q", msg.Payload)
+	}
+}
+
+func TestEscalationPolicy_Filter(t *testing.T) {
+	r := Recipient{ID: "u", Routes: []Route{
+		{Channel: ChannelEmail}, {Channel: ChannelTelegram}, {Channel: ChannelWebhook}, {Channel: ChannelSMS},
+	}}
+	tests := map[string]int{"minor": 1, "important": 2, "critical": 4}
+	for severity, want := range tests {
+		if got := len(DefaultEscalation.Filter(r, severity).Routes); got != want {
+			t.Errorf("%s: expected %d routes, got %d", severity, want, got)
+		}
+	}
+
+	p, err := ParseEscalationPolicy(`{"webhook":"minor","sms":"off"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Allows(ChannelWebhook, "minor") || p.Allows(ChannelSMS, "critical") || p.Allows(ChannelEmail, "important") {
+		t.Fatalf("unexpected policy decisions: %v", p)
+	}
+	if _, err := ParseEscalationPolicy(`{"email":"sometimes"}`); err == nil {
+		t.Fatal("expected error for unknown severity")
+	}
+}
+
+func TestDiffHTML(t *testing.T) {
+	if DiffHTML("", "View diff") != "" {
+		t.Fatal("expected no output for an empty diff")
+	}
+	out := DiffHTML("--- a\n+++ b\n@@ -1 +1 @@\n-<old>\n+new\n", "View diff")
+	if !strings.Contains(out, "<details") || !strings.Contains(out, "View diff") {
+		t.Fatalf("expected collapsed section: %s", out)
+	}
+	if !strings.Contains(out, "&lt;old&gt;") {
+		t.Fatalf("expected escaped diff lines: %s", out)
+	}
+	if !strings.Contains(out, "color:#81c784") || !strings.Contains(out, "color:#ef9a9a") {
+		t.Fatalf("expected added/removed coloring: %s", out)
+	}
+}
+
+type countingNotifier struct{ sent int }
+
+func (n *countingNotifier) Send(ctx context.Context, msg Message) error { n.sent++; return nil }
+func (n *countingNotifier) Channel() Channel                            { return ChannelTelegram }
+
+type memoryDeliveryLog map[string]bool
+
+func (l memoryDeliveryLog) WasDelivered(ctx context.Context, key string, r Route) (bool, error) {
+	return l[key+"|"+string(r.Channel)+"|"+r.Target], nil
+}
+
+func (l memoryDeliveryLog) MarkDelivered(ctx context.Context, key string, r Route) error {
+	l[key+"|"+string(r.Channel)+"|"+r.Target] = true
+	return nil
+}
+
+func TestDispatchTo_Idempotent(t *testing.T) {
+	n := &countingNotifier{}
+	d := NewDispatcher()
+	d.Register(n)
+	d.SetDeliveryLog(memoryDeliveryLog{})
+
+	r := Recipient{ID: "u", Routes: []Route{{Channel: ChannelTelegram}}}
+	msg := Message{Title: "digest", IdempotencyKey: "d1"}
+	for i := 0; i < 2; i++ {
+		if err := d.DispatchTo(context.Background(), r, msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n.sent != 1 {
+		t.Fatalf("expected 1 send for a repeated key, got %d", n.sent)
+	}
+
+	msg.IdempotencyKey = ""
+	_ = d.DispatchTo(context.Background(), r, msg)
+	if n.sent != 2 {
+		t.Fatalf("expected unkeyed message to be sent, got %d sends", n.sent)
+	}
+}
+
+// targetNotifier records the targets it sends to on its channel and fails
+// for the target in fail.
+type targetNotifier struct {
+	ch   Channel
+	to   string
+	fail string
+	sent *[]string
+}
+
+func (n *targetNotifier) Channel() Channel { return n.ch }
+
+func (n *targetNotifier) Send(ctx context.Context, msg Message) error {
+	if n.to != "" && n.to == n.fail {
+		return errors.New("rejected")
+	}
+	*n.sent = append(*n.sent, string(n.ch)+":"+n.to)
+	return nil
+}
+
+func TestDispatchTo_Routes(t *testing.T) {
+	var sent []string
+	d := NewDispatcher()
+	d.Register(&targetNotifier{ch: ChannelTelegram, sent: &sent})
+	d.RegisterFactory(ChannelEmail, func(to string) Notifier {
+		return &targetNotifier{ch: ChannelEmail, to: to, fail: "bad@example.com", sent: &sent}
+	})
+
+	r := Recipient{ID: "u", Routes: []Route{
+		{Channel: ChannelTelegram},
+		{Channel: ChannelTelegram, Target: "12345"}, // no factory: the registered notifier
+		{Channel: ChannelEmail, Target: "a@example.com"},
+		{Channel: ChannelSlack}, // not registered: skipped
+		{Channel: ChannelEmail}, // untargeted and no registered notifier: skipped
+	}}
+	if err := d.DispatchTo(context.Background(), r, Message{Title: "digest"}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"telegram:", "telegram:", "email:a@example.com"}
+	if fmt.Sprint(sent) != fmt.Sprint(want) {
+		t.Fatalf("sent = %v, want %v", sent, want)
+	}
+
+	// A failed route does not stop the others and is counted in the error
+	sent = nil
+	r.Routes = []Route{
+		{Channel: ChannelEmail, Target: "bad@example.com"},
+		{Channel: ChannelEmail, Target: "b@example.com"},
+		{Channel: ChannelTelegram},
+	}
+	err := d.DispatchTo(context.Background(), r, Message{Title: "digest"})
+	if err == nil || !strings.Contains(err.Error(), "1/3") {
+		t.Fatalf("expected 1/3 failed, got %v", err)
+	}
+	want = []string{"email:b@example.com", "telegram:"}
+	if fmt.Sprint(sent) != fmt.Sprint(want) {
+		t.Fatalf("sent = %v, want %v", sent, want)
+	}
+
+	// Routes already delivered for the key are skipped; the failed one is retried
+	log := memoryDeliveryLog{}
+	d.SetDeliveryLog(log)
+	msg := Message{Title: "digest", IdempotencyKey: "d1"}
+	_ = d.DispatchTo(context.Background(), r, msg)
+	sent = nil
+	_ = d.DispatchTo(context.Background(), r, msg)
+	if len(sent) != 0 {
+		t.Fatalf("redelivered to %v", sent)
+	}
+	if len(log) != 2 {
+		t.Fatalf("expected 2 delivered routes, got %v", log)
+	}
+}
+
+func TestTruncateSMS(t *testing.T) {
+	tests := []struct {
+		name, text, want string
+	}{
+		{"short", "critical  change\n", "critical change"},
+		{"gsm7 fits", strings.Repeat("a", SMSMaxLength), strings.Repeat("a", SMSMaxLength)},
+		{"gsm7 cut", strings.Repeat("a", 200), strings.Repeat("a", SMSMaxLength-3) + "..."},
+		{"gsm7 extension", strings.Repeat("€", 100), strings.Repeat("€", 78) + "..."},
+		{"cjk fits", strings.Repeat("价", SMSMaxLengthUCS2), strings.Repeat("价", SMSMaxLengthUCS2)},
+		{"cjk cut", strings.Repeat("价格", 100), strings.Repeat("价格", 34) + "价…"},
+		{"cjk forces ucs2", "[WatchBot] 严重变化 1 处: " + strings.Repeat("a", 100), "[WatchBot] 严重变化 1 处: " + strings.Repeat("a", 48) + "…"},
+		{"emoji", strings.Repeat("🚨", 40), strings.Repeat("🚨", 34) + "…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateSMS(tt.text)
+			if got != tt.want {
+				t.Fatalf("TruncateSMS() = %q, want %q", got, tt.want)
+			}
+			if !smsFits(got) {
+				t.Fatalf("%q does not fit one segment", got)
+			}
+		})
+	}
+}
+
+func TestSMSNotifier_Send(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		bodies = append(bodies, r.PostForm.Get("Body"))
+		// Twilio has no idempotency header, so none is sent
+		if r.Header.Get("I-Twilio-Idempotency-Token") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	n := NewSMSNotifierForRecipient(TwilioConfig{AccountSID: "AC1", From: "+15550100"}, "+15550101")
+	n.baseURL = srv.URL
+	url := "https://example.com/c/1"
+	for _, title := range []string{"Critical change on Acme pricing", "Acme 价格页严重变化: 企业版价格上调, 新增按席位计费, 免费版取消, 年付折扣从 20% 降到 10%"} {
+		if err := n.Send(context.Background(), Message{Title: title, URL: url, IdempotencyKey: "d1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if bodies[0] != "Critical change on Acme pricing "+url {
+		t.Errorf("gsm7 body = %q, want the URL appended", bodies[0])
+	}
+	// The URL would push the CJK title past 70 UTF-16 code units
+	if strings.Contains(bodies[1], url) || !smsFits(bodies[1]) {
+		t.Errorf("ucs2 body = %q, want one segment without the URL", bodies[1])
+	}
+}
+
+func TestWatchEmailFormatter_Labels(t *testing.T) {
+	data := WatchDigestData{
+		Groups: GroupChanges([]WatchChangeItem{
+			{CompetitorName: "Acme", PageType: "pricing", PageURL: "https://acme.test/pricing", Severity: "critical"},
+		}),
+	}
+
+	zh := NewWatchEmailFormatter().Format(data)
+	if !strings.Contains(zh.Title, "竞品监控报告") {
+		t.Fatalf("expected default Chinese title, got %q", zh.Title)
+	}
+
+	data.Labels = WatchLabels{
+		DigestTitle:        "Competitor Watch Report",
+		CompetitorsChanged: "%d competitors, %d pages changed",
+		SubjectChanged:     "%d competitors changed",
+		PagesChanged:       "%d page changes",
+		DiffLines:          "+%d / -%d lines",
+		ViewPage:           "View Page →",
+		Critical:           "Critical",
+	}
+	en := NewWatchEmailFormatter().Format(data)
+	if en.Title != "🔍 Competitor Watch Report — 1 competitors changed" {
+		t.Fatalf("unexpected English title: %q", en.Title)
+	}
+	if strings.Contains(en.HTMLBody, "查看原页面") {
+		t.Fatal("expected no Chinese strings in English email")
+	}
+
+	data.Labels.Plural = func(key string, n int) string {
+		if key == "SubjectChanged" && n == 1 {
+			return "%d competitor changed"
+		}
+		return ""
+	}
+	en = NewWatchEmailFormatter().Format(data)
+	if en.Title != "🔍 Competitor Watch Report — 1 competitor changed" {
+		t.Fatalf("unexpected singular title: %q", en.Title)
+	}
+}
+
+func TestBenchmarkFormatters_Movement(t *testing.T) {
+	report := benchmarks.NewReport(benchmarks.DefaultModels[:3], "2026-02-20")
+	report.SetScore("gpqa_diamond", "", "Opus 4.6", 92)
+
+	before := []benchmarks.BenchmarkScore{
+		{BenchmarkID: "gpqa_diamond", ModelName: "Opus 4.6", Score: 91.3},
+		{BenchmarkID: "mmmlu", ModelName: "Opus 4.6", Score: 91.1},
+	}
+	after := []benchmarks.BenchmarkScore{
+		{BenchmarkID: "gpqa_diamond", ModelName: "Opus 4.6", Score: 92},
+		{BenchmarkID: "mmmlu", ModelName: "Opus 4.6", Score: 91.1},
+		{BenchmarkID: "hle", Variant: "No tools", ModelName: "Gemini 3.1 Pro", Score: 44.4},
+	}
+	changes := benchmarks.DiffScores(before, after)
+	if len(changes) != 2 || changes[0].New || !changes[1].New {
+		t.Fatalf("changes = %+v", changes)
+	}
+
+	data := BenchmarkDigestData{Report: report, Date: "2026-02-20", Changes: changes}
+	email := NewBenchmarkEmailFormatter().Format(data)
+	if !strings.HasPrefix(email.Title, "📈 Benchmark Movement") {
+		t.Errorf("email title = %q", email.Title)
+	}
+	for _, want := range []string{"GPQA Diamond · Opus 4.6: 91.3 → 92.0 (▲0.7)", "Humanity&#39;s Last Exam (No tools) · Gemini 3.1 Pro: new 44.4"} {
+		if !strings.Contains(email.HTMLBody, want) {
+			t.Errorf("email missing %q", want)
+		}
+	}
+
+	tg := NewBenchmarkTelegramFormatter().Format(data)
+	if !strings.Contains(tg.Body, `GPQA Diamond · Opus 4\.6: 91\.3 → 92\.0 \(▲0\.7\)`) {
+		t.Errorf("telegram body not escaped as expected:\n%s", tg.Body)
+	}
+}
+
+func TestBenchmarkFormatters_Candidates(t *testing.T) {
+	candidates := []benchmarks.ModelCandidate{
+		{Source: "llm-stats.com", RawName: "GPT-6", HighScores: 2, Summary: "GPQA Diamond 93.1%, MMMLU 92.0%"},
+	}
+	email := NewBenchmarkEmailFormatter().FormatCandidates(candidates)
+	if !strings.Contains(email.Body, `"GPT-6" on llm-stats.com: GPQA Diamond 93.1%`) {
+		t.Errorf("email body = %q", email.Body)
+	}
+	tg := NewBenchmarkTelegramFormatter().FormatCandidates(candidates)
+	if !strings.Contains(tg.Body, `"GPT\-6" on llm\-stats\.com`) {
+		t.Errorf("telegram body not escaped as expected:\n%s", tg.Body)
+	}
+}
+
+func TestDispatchTo_Deferred(t *testing.T) {
+	n := &countingNotifier{}
+	d := NewDispatcher()
+	d.Register(n)
+	d.SetDeliveryLog(memoryDeliveryLog{})
+	var queued []Delivery
+	d.SetDeferred(ChannelTelegram, func(ctx context.Context, dl Delivery) error {
+		queued = append(queued, dl)
+		return nil
+	})
+
+	r := Recipient{ID: "u", Routes: []Route{{Channel: ChannelTelegram}}}
+	if err := d.DispatchTo(context.Background(), r, Message{Title: "digest", IdempotencyKey: "d1"}); err != nil {
+		t.Fatal(err)
+	}
+	if n.sent != 0 || len(queued) != 1 || queued[0].Recipient != "u" || queued[0].Message().IdempotencyKey != "d1" {
+		t.Fatalf("expected one queued delivery and no send, got sent=%d queued=%+v", n.sent, queued)
+	}
+
+	// Retried deliveries go out once
+	for i := 0; i < 2; i++ {
+		if err := d.Deliver(context.Background(), queued[0]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n.sent != 1 {
+		t.Fatalf("expected 1 send for a redelivered key, got %d", n.sent)
+	}
+}
+
+func TestWatchDiscordFormatter(t *testing.T) {
+	data := WatchDigestData{
+		Groups: GroupChanges([]WatchChangeItem{
+			{CompetitorName: "Acme", PageType: "pricing", PageURL: "https://acme.test/pricing", Severity: "critical", Analysis: "Pro plan now $49", Additions: 3, Deletions: 1},
+			{CompetitorName: "Acme", PageType: "changelog", Severity: "minor"},
+			{CompetitorName: "Globex", PageType: "features", Severity: "important"},
+		}),
+		Unchanged: []string{"Initech"},
+	}
+	msg := NewWatchDiscordFormatter().Format(data)
+	if len(msg.Embeds) != 2 {
+		t.Fatalf("expected one embed per competitor, got %d", len(msg.Embeds))
+	}
+	acme := msg.Embeds[0]
+	if acme.Color != SeverityColor("critical") || msg.Embeds[1].Color != SeverityColor("important") {
+		t.Fatalf("unexpected colors %#x, %#x", acme.Color, msg.Embeds[1].Color)
+	}
+	if len(acme.Fields) != 2 || acme.Fields[0].Name != "📄 pricing · Critical" {
+		t.Fatalf("unexpected fields: %+v", acme.Fields)
+	}
+	if v := acme.Fields[0].Value; !strings.Contains(v, "Pro plan now $49") || !strings.Contains(v, "+3 / -1 行") || !strings.Contains(v, "(https://acme.test/pricing)") {
+		t.Fatalf("unexpected field value: %q", v)
+	}
+	if f := msg.Embeds[1].Footer; f == nil || !strings.Contains(f.Text, "Initech") {
+		t.Fatalf("expected unchanged competitors in the footer, got %+v", f)
+	}
+
+	// More competitors than Discord allows embeds fold into the last one
+	var items []WatchChangeItem
+	for i := 0; i < 12; i++ {
+		items = append(items, WatchChangeItem{CompetitorName: string(rune('A' + i)), PageType: "pricing", Severity: "minor"})
+	}
+	msg = NewWatchDiscordFormatter().Format(WatchDigestData{Groups: GroupChanges(items)})
+	if len(msg.Embeds) != 10 || strings.Count(msg.Embeds[9].Description, "\n") != 2 {
+		t.Fatalf("expected 10 embeds with 3 folded competitors, got %d: %q", len(msg.Embeds), msg.Embeds[len(msg.Embeds)-1].Description)
+	}
+}
+
+func TestNewsDiscordFormatter(t *testing.T) {
+	data := NewsDigestData{
+		Date:    "2026-10-16",
+		Summary: "Big day.",
+		Labels:  NewsLabels{DailyTitle: "AI Daily", Source: "Source", GeneratedBy: "Generated by NewsBot"},
+	}
+	for i := 0; i < 12; i++ {
+		data.Headlines = append(data.Headlines, NewsHeadline{Title: "headline", URL: "https://news.test", Source: "Wire", Importance: "high", Tags: []string{"llm"}})
+	}
+	msg := NewNewsDiscordFormatter().Format(data)
+	if len(msg.Embeds) != 10 {
+		t.Fatalf("expected 10 embeds, got %d", len(msg.Embeds))
+	}
+	if msg.Embeds[0].Description != "Big day." {
+		t.Fatalf("expected the overview first, got %+v", msg.Embeds[0])
+	}
+	h := msg.Embeds[1]
+	if h.Color != SeverityColor("high") || h.URL != "https://news.test" || h.Footer == nil || h.Footer.Text != "Source: Wire · #llm" {
+		t.Fatalf("unexpected headline embed: %+v", h)
+	}
+	if last := msg.Embeds[9].Description; !strings.Contains(last, "9. [headline]") || !strings.Contains(last, "12. [headline]") {
+		t.Fatalf("expected headlines 9-12 listed in the last embed, got %q", last)
+	}
+}
+
+func TestFitDiscordEmbeds(t *testing.T) {
+	long := strings.Repeat("x", 5000)
+	embeds := []DiscordEmbed{
+		{Title: long, Description: long},
+		{Description: long},
+	}
+	got := FitDiscordEmbeds(embeds)
+	total := 0
+	for _, e := range got {
+		total += len([]rune(e.Title)) + len([]rune(e.Description))
+	}
+	if len([]rune(got[0].Title)) != discordMaxTitle || len([]rune(got[0].Description)) != discordMaxDescription {
+		t.Fatalf("expected title and description cut to their limits")
+	}
+	if total > discordMaxEmbedTotal {
+		t.Fatalf("expected at most %d characters, got %d", discordMaxEmbedTotal, total)
+	}
+}
+
+func TestDiscordNotifier_Send(t *testing.T) {
+	var got discordPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	n := NewDiscordNotifier(DiscordConfig{WebhookURL: srv.URL, Username: "WatchBot"})
+	msg := Message{Title: "digest", Embeds: []DiscordEmbed{{Title: "Acme", Color: 0xe53935}}}
+	if err := n.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if got.Content != "digest" || got.Username != "WatchBot" || len(got.Embeds) != 1 || got.Embeds[0].Color != 0xe53935 {
+		t.Fatalf("unexpected payload: %+v", got)
+	}
+
+	// Without embeds the message becomes one
+	got = discordPayload{}
+	if err := n.Send(context.Background(), Message{Title: "hi", Body: "body"}); err != nil {
+		t.Fatal(err)
+	}
+	if got.Content != "" || len(got.Embeds) != 1 || got.Embeds[0].Title != "hi" || got.Embeds[0].Description != "body" {
+		t.Fatalf("unexpected payload: %+v", got)
+	}
+
+	rejected := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "You are being rate limited."}`, http.StatusTooManyRequests)
+	}))
+	defer rejected.Close()
+	n = NewDiscordNotifier(DiscordConfig{WebhookURL: rejected.URL})
+	if err := n.Send(context.Background(), msg); err == nil || !strings.Contains(err.Error(), "429") {
+		t.Fatalf("expected a 429 error, got %v", err)
+	}
+}
+
+func TestWatchWeChatWorkFormatter(t *testing.T) {
+	data := WatchDigestData{
+		Groups: GroupChanges([]WatchChangeItem{
+			{CompetitorName: "Acme", PageType: "pricing", PageURL: "https://acme.test/pricing", Severity: "critical", Analysis: "**Pro** plan now $49", Additions: 3, Deletions: 1},
+			{CompetitorName: "Globex", PageType: "features", Severity: "minor"},
+		}),
+		Unchanged: []string{"Initech"},
+	}
+	msg := NewWatchWeChatWorkFormatter().Format(data)
+	md := msg.WeChatWork.Markdown
+	for _, want := range []string{"## 🔴 Acme", `<font color="warning">Critical</font>`, "Pro plan now $49", "+3 / -1 行", "(https://acme.test/pricing)", "Globex", "Initech"} {
+		if !strings.Contains(md, want) {
+			t.Fatalf("expected %q in markdown:\n%s", want, md)
+		}
+	}
+	if a := msg.WeChatWork.Articles; len(a) != 1 || a[0].URL != "https://acme.test/pricing" || a[0].Description != "Pro plan now $49" {
+		t.Fatalf("expected a news card for the page with a URL, got %+v", a)
+	}
+
+	// Competitors past the markdown limit are listed by name
+	var items []WatchChangeItem
+	for i := 0; i < 200; i++ {
+		items = append(items, WatchChangeItem{CompetitorName: fmt.Sprintf("竞品%d", i), PageType: "pricing", Severity: "minor", Analysis: strings.Repeat("价格调整", 60)})
+	}
+	md = NewWatchWeChatWorkFormatter().Format(WatchDigestData{Groups: GroupChanges(items)}).WeChatWork.Markdown
+	if len(md) > wechatWorkMaxMarkdown || !strings.Contains(md, "· 1 个页面变化\n…") || !strings.HasSuffix(md, "竞品变化监控系统</font>") {
+		t.Fatalf("expected markdown within %d bytes naming the rest, got %d bytes", wechatWorkMaxMarkdown, len(md))
+	}
+}
+
+func TestWeChatWorkNotifier_Send(t *testing.T) {
+	var got wechatWorkPayload
+	var key string
+	errcode := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key = r.URL.Query().Get("key")
+		got = wechatWorkPayload{}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		fmt.Fprintf(w, `{"errcode": %d, "errmsg": "invalid webhook url"}`, errcode)
+	}))
+	defer srv.Close()
+
+	n := NewWeChatWorkNotifier(WeChatWorkConfig{WebhookURL: srv.URL + "?key=abc"})
+	msg := Message{Title: "digest", WeChatWork: &WeChatWorkContent{Markdown: "# 报告", Articles: []WeChatWorkArticle{{Title: "Acme", URL: "https://acme.test"}}}}
+	if err := n.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if key != "abc" || got.MsgType != "markdown" || got.Markdown == nil || got.Markdown.Content != "# 报告" {
+		t.Fatalf("unexpected payload: %+v", got)
+	}
+
+	n = NewWeChatWorkNotifier(WeChatWorkConfig{WebhookURL: srv.URL, MsgType: "news"})
+	if err := n.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if got.MsgType != "news" || got.News == nil || len(got.News.Articles) != 1 || got.News.Articles[0].URL != "https://acme.test" {
+		t.Fatalf("unexpected payload: %+v", got)
+	}
+	// Without a URL there is no card to send
+	if err := n.Send(context.Background(), Message{Title: "hi", Body: "body"}); err != nil {
+		t.Fatal(err)
+	}
+	if got.MsgType != "markdown" || got.Markdown.Content != "**hi**\nbody" {
+		t.Fatalf("unexpected payload: %+v", got)
+	}
+
+	errcode = 93000
+	if err := n.Send(context.Background(), msg); err == nil || !strings.Contains(err.Error(), "93000") {
+		t.Fatalf("expected the errcode to fail the send, got %v", err)
+	}
+
+	if n := NewWeChatWorkNotifier(WeChatWorkConfig{WebhookURL: "k+y"}); n.config.WebhookURL != wechatWorkSendURL+"k%2By" {
+		t.Fatalf("expected a bare key to become the robot URL, got %s", n.config.WebhookURL)
+	}
+	if s := truncateBytes("企业微信", 8); s != "企…" {
+		t.Fatalf("expected a cut at a rune boundary, got %q", s)
+	}
+}
+
+// failingNotifier fails as many sends as failures, then succeeds.
+type failingNotifier struct {
+	failures int
+	sent     []Message
+}
+
+func (n *failingNotifier) Send(ctx context.Context, msg Message) error {
+	if n.failures > 0 {
+		n.failures--
+		return errors.New("smtp: 421 service not available")
+	}
+	n.sent = append(n.sent, msg)
+	return nil
+}
+func (n *failingNotifier) Channel() Channel { return ChannelEmail }
+
+func TestDispatchTo_Retry(t *testing.T) {
+	ctx := context.Background()
+	n := &failingNotifier{failures: 1}
+	d := NewDispatcher()
+	d.Register(n)
+	d.SetDeliveryLog(memoryDeliveryLog{})
+	var queued []Delivery
+	d.SetRetry(func(ctx context.Context, dl Delivery, err error) error {
+		if !strings.Contains(err.Error(), "421") {
+			t.Errorf("retry got error %v", err)
+		}
+		queued = append(queued, dl)
+		return nil
+	})
+
+	// A failed send is queued, and the route counts as handled
+	r := Recipient{ID: "u", Routes: []Route{{Channel: ChannelEmail}}}
+	msg := Message{Title: "digest", IdempotencyKey: "d1", Bulk: true, UnsubscribeURL: "https://x/u", Attachments: []Attachment{NewAttachment("diff.txt", []byte("-a\n+b"))}}
+	if err := d.DispatchTo(ctx, r, msg); err != nil {
+		t.Fatalf("expected the failure to be queued, got %v", err)
+	}
+	if len(queued) != 1 || len(n.sent) != 0 {
+		t.Fatalf("queued %d, sent %d; want 1 queued", len(queued), len(n.sent))
+	}
+
+	// The stored delivery keeps the email-only parts
+	data, err := json.Marshal(queued[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dl Delivery
+	if err := json.Unmarshal(data, &dl); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Deliver(ctx, dl); err != nil {
+		t.Fatal(err)
+	}
+	if len(n.sent) != 1 || !n.sent[0].Bulk || n.sent[0].UnsubscribeURL != "https://x/u" || len(n.sent[0].Attachments) != 1 || string(n.sent[0].Attachments[0].Data) != "-a\n+b" {
+		t.Fatalf("retried message = %+v", n.sent)
+	}
+	// The delivery log now has the key, so neither a redelivery nor a new
+	// dispatch sends again
+	if err := d.Deliver(ctx, dl); err != nil || len(n.sent) != 1 {
+		t.Fatalf("expected the redelivered digest to be skipped, sent %d", len(n.sent))
+	}
+	if err := d.DispatchTo(ctx, r, msg); err != nil || len(n.sent) != 1 {
+		t.Fatalf("expected the delivered digest to be skipped, sent %d", len(n.sent))
+	}
+
+	// Without a retry queue, and when queueing fails, the failure is reported
+	n.failures = 2
+	d.SetRetry(func(context.Context, Delivery, error) error { return errors.New("queue down") })
+	if err := d.DispatchTo(ctx, r, Message{Title: "heartbeat"}); err == nil {
+		t.Error("expected an error when the retry cannot be queued")
+	}
+	d.SetRetry(nil)
+	if err := d.DispatchTo(ctx, r, Message{Title: "heartbeat"}); err == nil {
+		t.Error("expected an error without a retry queue")
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	for attempt, want := range map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 5: 16 * time.Minute, 9: 256 * time.Minute, 10: 6 * time.Hour, 30: 6 * time.Hour} {
+		if got := RetryBackoff(attempt); got != want {
+			t.Errorf("RetryBackoff(%d) = %s, want %s", attempt, got, want)
+		}
+	}
+}
+
+func TestNtfyNotifier_Send(t *testing.T) {
+	var payload map[string]any
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		payload = nil
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Error(err)
+		}
+		w.Write([]byte(`{"id":"sPs71M8A2T"}`))
+	}))
+	defer srv.Close()
+
+	n := NewNtfyNotifier(NtfyConfig{Server: srv.URL + "/", Topic: "acme-watch", Token: "tk_secret"})
+	msg := Message{Title: "Acme 价格页严重变化", Body: "**企业版**涨价", URL: "https://example.com/c/1", Severity: "critical"}
+	if err := n.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if header.Get("Content-Type") != "application/json" || header.Get("Authorization") != "Bearer tk_secret" {
+		t.Errorf("headers = %v", header)
+	}
+	if payload["topic"] != "acme-watch" || payload["title"] != msg.Title || payload["message"] != "企业版涨价" ||
+		payload["click"] != msg.URL || payload["priority"] != 5.0 {
+		t.Errorf("payload = %v", payload)
+	}
+
+	for severity, want := range map[string]any{"minor": 2.0, "important": 4.0, "": nil, "unknown": nil} {
+		if err := n.Send(context.Background(), Message{Title: "t", Severity: severity}); err != nil {
+			t.Fatal(err)
+		}
+		if payload["priority"] != want {
+			t.Errorf("severity %q: priority = %v, want %v", severity, payload["priority"], want)
+		}
+		if _, ok := payload["click"]; ok {
+			t.Error("click set without a URL")
+		}
+	}
+
+	// DispatchSeverity passes its severity on
+	d := NewDispatcher()
+	d.Register(n)
+	if err := d.DispatchSeverity(context.Background(), Recipient{Routes: []Route{{Channel: ChannelNtfy}}}, "important", Message{Title: "t"}); err != nil {
+		t.Fatal(err)
+	}
+	if payload["priority"] != 4.0 {
+		t.Errorf("dispatched priority = %v, want 4", payload["priority"])
+	}
+
+	// Public topics need no token
+	if err := NewNtfyNotifier(NtfyConfig{Server: srv.URL, Topic: "open"}).Send(context.Background(), Message{Title: "t"}); err != nil {
+		t.Fatal(err)
+	}
+	if auth := header.Get("Authorization"); auth != "" {
+		t.Errorf("Authorization = %q without a token", auth)
+	}
+
+	rejected := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"code":40301,"http":403,"error":"forbidden"}`, http.StatusForbidden)
+	}))
+	defer rejected.Close()
+	err := NewNtfyNotifier(NtfyConfig{Server: rejected.URL, Topic: "acme-watch"}).Send(context.Background(), msg)
+	if err == nil || !strings.Contains(err.Error(), "ntfy error (403)") || !strings.Contains(err.Error(), "forbidden") {
+		t.Fatalf("expected a 403 error, got %v", err)
+	}
+}
+
+func TestPushoverNotifier_Send(t *testing.T) {
+	var form url.Values
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		form = r.PostForm
+		w.Write([]byte(`{"status":1,"request":"647d2300-702c-4b38-8b2f-d56326ae460b"}`))
+	}))
+	defer srv.Close()
+
+	n := NewPushoverNotifier(PushoverConfig{AppToken: "app", UserKey: "user"})
+	n.baseURL = srv.URL
+	msg := Message{
+		Title:    strings.Repeat("价", 300),
+		Body:     "Acme raised **Enterprise** pricing",
+		URL:      "https://example.com/c/1",
+		Severity: "critical",
+	}
+	if err := n.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if contentType != "application/x-www-form-urlencoded" {
+		t.Errorf("Content-Type = %q", contentType)
+	}
+	if form.Get("token") != "app" || form.Get("user") != "user" || form.Get("message") != "Acme raised Enterprise pricing" ||
+		form.Get("url") != msg.URL || form.Get("priority") != "1" {
+		t.Errorf("form = %v", form)
+	}
+	if title := []rune(form.Get("title")); len(title) != 250 || title[249] != '…' {
+		t.Errorf("title cut to %d runes, want 250 ending in …", len(title))
+	}
+
+	for severity, want := range map[string]string{"minor": "-1", "important": "0", "": "", "unknown": ""} {
+		if err := n.Send(context.Background(), Message{Title: "t", Severity: severity}); err != nil {
+			t.Fatal(err)
+		}
+		if form.Get("priority") != want || form.Has("priority") != (want != "") {
+			t.Errorf("severity %q: priority = %v, want %q", severity, form["priority"], want)
+		}
+	}
+
+	rejected := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"user":"invalid","errors":["user identifier is not a valid user, group, or subscribed user key"],"status":0}`, http.StatusBadRequest)
+	}))
+	defer rejected.Close()
+	n.baseURL = rejected.URL
+	err := n.Send(context.Background(), msg)
+	if err == nil || !strings.Contains(err.Error(), "pushover API error (400)") || !strings.Contains(err.Error(), "not a valid user") {
+		t.Fatalf("expected a 400 error, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Formatter renders product-specific digest data into a Message.
+type Formatter[T any] interface {
+	Format(data T) Message
+}
+
+// RenderPreview formats data and writes the result to path without sending it.
+// See WritePreview for how the output format is chosen.
+func RenderPreview[T any](data T, formatter Formatter[T], path string) error {
+	return WritePreview(formatter.Format(data), path)
+}
+
+// WritePreview writes a rendered message to disk so templates can be checked
+// in a browser or mail client. The file extension selects the output:
+//
+//	.html/.htm — the HTML body (plain text wrapped in <pre> if there is none)
+//	.eml       — the full MIME message as the email notifier would send it
+//	otherwise  — the title and plain-text body
+func WritePreview(msg Message, path string) error {
+	var content string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		content = msg.HTMLBody
+		if content == "" {
+			content = "<pre>" + msg.Body + "</pre>"
+		}
+	case ".eml":
+		content = buildEmailBody(EmailConfig{From: "preview@localhost"}, []string{"recipient@localhost"}, msg)
+	default:
+		content = msg.Title + "\n\n" + msg.Body
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("create preview dir: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("write preview: %w", err)
+	}
+	return nil
+}
+
+// PreviewPath derives a per-recipient file name from a base path,
+// e.g. "out.html" + "a@b.com" → "out-a_b.com.html".
+func PreviewPath(base, recipient string) string {
+	if recipient == "" {
+		return base
+	}
+	ext := filepath.Ext(base)
+	safe := strings.NewReplacer("@", "_", "/", "_", "\\", "_", " ", "_").Replace(recipient)
+	return strings.TrimSuffix(base, ext) + "-" + safe + ext
+}
@@ -0,0 +1,168 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ---- ntfy ----
+
+// NtfyConfig holds ntfy.sh (or self-hosted ntfy) configuration.
+type NtfyConfig struct {
+	Server string `yaml:"server" json:"server"` // default "https://ntfy.sh"
+	Topic  string `yaml:"topic" json:"topic"`
+	Token  string `yaml:"token" json:"token"` // optional access token for protected topics
+}
+
+// NtfyNotifier publishes messages to an ntfy topic.
+type NtfyNotifier struct {
+	config NtfyConfig
+	http   *http.Client
+}
+
+// NewNtfyNotifier creates a new ntfy notifier.
+func NewNtfyNotifier(cfg NtfyConfig) *NtfyNotifier {
+	if cfg.Server == "" {
+		cfg.Server = "https://ntfy.sh"
+	}
+	cfg.Server = strings.TrimRight(cfg.Server, "/")
+	return &NtfyNotifier{
+		config: cfg,
+		http:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (n *NtfyNotifier) Channel() Channel { return ChannelNtfy }
+
+// ntfy priorities by severity, from 1 (min) to 5 (max); messages without a
+// severity get ntfy's default of 3.
+var ntfyPriority = map[string]int{
+	"minor":     2,
+	"important": 4,
+	"critical":  5,
+}
+
+// Send publishes a message using ntfy's JSON API, which handles UTF-8 titles.
+func (n *NtfyNotifier) Send(ctx context.Context, msg Message) error {
+	payload := map[string]interface{}{
+		"topic":   n.config.Topic,
+		"title":   msg.Title,
+		"message": truncateRunes(StripMarkdown(msg.Body), 4000),
+	}
+	if msg.URL != "" {
+		payload["click"] = msg.URL
+	}
+	if priority, ok := ntfyPriority[msg.Severity]; ok {
+		payload["priority"] = priority
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", n.config.Server, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.config.Token)
+	}
+
+	resp, err := n.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("send ntfy message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("ntfy error (%d): %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// ---- Pushover ----
+
+// PushoverConfig holds Pushover configuration.
+type PushoverConfig struct {
+	AppToken string `yaml:"app_token" json:"app_token"`
+	UserKey  string `yaml:"user_key" json:"user_key"`
+}
+
+// PushoverNotifier sends messages via the Pushover API.
+type PushoverNotifier struct {
+	config  PushoverConfig
+	baseURL string
+	http    *http.Client
+}
+
+// NewPushoverNotifier creates a new Pushover notifier.
+func NewPushoverNotifier(cfg PushoverConfig) *PushoverNotifier {
+	return &PushoverNotifier{
+		config:  cfg,
+		baseURL: "https://api.pushover.net/1/messages.json",
+		http:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *PushoverNotifier) Channel() Channel { return ChannelPushover }
+
+// Pushover priorities by severity. Critical is high (1), which bypasses the
+// user's quiet hours; emergency (2) needs acknowledging and is not used.
+// Messages without a severity get the normal priority of 0.
+var pushoverPriority = map[string]string{
+	"minor":     "-1",
+	"important": "0",
+	"critical":  "1",
+}
+
+// Send sends a message via Pushover. Title and body are truncated to API limits.
+func (p *PushoverNotifier) Send(ctx context.Context, msg Message) error {
+	form := url.Values{}
+	form.Set("token", p.config.AppToken)
+	form.Set("user", p.config.UserKey)
+	form.Set("title", truncateRunes(msg.Title, 250))
+	form.Set("message", truncateRunes(StripMarkdown(msg.Body), 1024))
+	if msg.URL != "" {
+		form.Set("url", msg.URL)
+	}
+	if priority, ok := pushoverPriority[msg.Severity]; ok {
+		form.Set("priority", priority)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("send pushover message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("pushover API error (%d): %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// truncateRunes cuts s to at most max runes, ending with "…" when cut.
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
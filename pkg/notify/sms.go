@@ -0,0 +1,169 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	// SMSMaxLength is the maximum length, in septets, of a single-segment SMS
+	// in the GSM 7-bit alphabet.
+	SMSMaxLength = 160
+	// SMSMaxLengthUCS2 is the maximum length, in UTF-16 code units, of a
+	// single-segment SMS with any character outside GSM 7-bit, such as CJK
+	// text or emoji.
+	SMSMaxLengthUCS2 = 70
+)
+
+// The GSM 03.38 alphabet: basic characters take one septet, extension
+// characters two (an escape and the character).
+const (
+	gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+		"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	gsm7Extension = "\f^{}\\[~]|€"
+)
+
+// TwilioConfig holds Twilio SMS configuration.
+type TwilioConfig struct {
+	AccountSID string `yaml:"account_sid" json:"account_sid"`
+	AuthToken  string `yaml:"auth_token" json:"auth_token"`
+	From       string `yaml:"from" json:"from"` // Twilio phone number, E.164
+	To         string `yaml:"to" json:"to"`     // recipient phone number, E.164
+}
+
+// SMSNotifier sends short text alerts via the Twilio Messages API.
+// It is intended for paging-level alerts only; messages are truncated to one segment.
+// Twilio does not deduplicate message requests, so an SMS is only kept from
+// being sent twice by the dispatcher's delivery log (see Dispatcher.SetDeliveryLog).
+type SMSNotifier struct {
+	config  TwilioConfig
+	baseURL string
+	http    *http.Client
+}
+
+// NewSMSNotifier creates a new Twilio SMS notifier.
+func NewSMSNotifier(cfg TwilioConfig) *SMSNotifier {
+	return &SMSNotifier{
+		config:  cfg,
+		baseURL: "https://api.twilio.com/2010-04-01",
+		http:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// NewSMSNotifierForRecipient creates an SMS notifier targeting a specific phone number.
+func NewSMSNotifierForRecipient(cfg TwilioConfig, to string) *SMSNotifier {
+	cfg.To = to
+	return NewSMSNotifier(cfg)
+}
+
+func (s *SMSNotifier) Channel() Channel { return ChannelSMS }
+
+// Send sends the message title (and URL, if it fits) as a single SMS.
+func (s *SMSNotifier) Send(ctx context.Context, msg Message) error {
+	if s.config.To == "" {
+		return fmt.Errorf("sms: no recipient phone number")
+	}
+
+	text := msg.Title
+	if text == "" {
+		text = msg.Body
+	}
+	if msg.URL != "" {
+		if withURL := text + " " + msg.URL; smsFits(withURL) {
+			text = withURL
+		}
+	}
+
+	form := url.Values{}
+	form.Set("To", s.config.To)
+	form.Set("From", s.config.From)
+	form.Set("Body", TruncateSMS(text))
+
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", s.baseURL, url.PathEscape(s.config.AccountSID))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(s.config.AccountSID, s.config.AuthToken)
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("send sms: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("twilio API error (%d): %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// TruncateSMS shortens text to a single SMS segment: SMSMaxLength septets
+// for GSM 7-bit text, ending with "..." when cut, or SMSMaxLengthUCS2 UTF-16
+// code units otherwise, ending with "…". The ASCII ellipsis keeps cut GSM
+// text from switching to UCS-2 and its much shorter limit.
+func TruncateSMS(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if smsFits(text) {
+		return text
+	}
+	gsm := isGSM7(text)
+	budget, ellipsis := SMSMaxLengthUCS2-1, "…"
+	if gsm {
+		budget, ellipsis = SMSMaxLength-3, "..."
+	}
+	var b strings.Builder
+	for _, r := range text {
+		cost := utf16.RuneLen(r)
+		if gsm {
+			cost = gsm7Len(r)
+		}
+		if budget -= cost; budget < 0 {
+			break
+		}
+		b.WriteRune(r)
+	}
+	return strings.TrimRight(b.String(), " ") + ellipsis
+}
+
+// smsFits reports whether text fits in a single SMS segment.
+func smsFits(text string) bool {
+	if isGSM7(text) {
+		n := 0
+		for _, r := range text {
+			n += gsm7Len(r)
+		}
+		return n <= SMSMaxLength
+	}
+	return len(utf16.Encode([]rune(text))) <= SMSMaxLengthUCS2
+}
+
+// isGSM7 reports whether text can be sent in the GSM 7-bit alphabet.
+func isGSM7(text string) bool {
+	for _, r := range text {
+		if gsm7Len(r) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// gsm7Len returns the septets r takes in the GSM 7-bit alphabet, or 0 if it
+// is not in it.
+func gsm7Len(r rune) int {
+	switch {
+	case strings.ContainsRune(gsm7Basic, r):
+		return 1
+	case strings.ContainsRune(gsm7Extension, r):
+		return 2
+	}
+	return 0
+}
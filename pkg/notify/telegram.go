@@ -6,7 +6,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -71,6 +73,53 @@ func (t *TelegramNotifier) Send(ctx context.Context, msg Message) error {
 		return fmt.Errorf("telegram API error (%d): %s", resp.StatusCode, string(respBody))
 	}
 
+	// Images follow the text as photos; other attachments are skipped
+	for _, a := range msg.Attachments {
+		if !strings.HasPrefix(a.ContentType, "image/") {
+			continue
+		}
+		if err := t.sendPhoto(ctx, a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendPhoto uploads an image attachment with sendPhoto.
+func (t *TelegramNotifier) sendPhoto(ctx context.Context, a Attachment) error {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if err := mw.WriteField("chat_id", t.config.ChannelID); err != nil {
+		return err
+	}
+	part, err := mw.CreateFormFile("photo", a.Filename)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(a.Data); err != nil {
+		return err
+	}
+	if err := mw.Close(); err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendPhoto", t.config.BotToken)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, &body)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	resp, err := t.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("send telegram photo: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("telegram API error (%d): %s", resp.StatusCode, string(respBody))
+	}
 	return nil
 }
 
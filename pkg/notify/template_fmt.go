@@ -0,0 +1,80 @@
+// Package notify — template_fmt.go provides a generic, template-driven formatter
+// for webhook payloads.
+//
+// Instead of adding a bespoke formatter per downstream system (PagerDuty,
+// Opsgenie, ...), the payload shape is described by a Go text/template that
+// receives the product's digest data (e.g. WatchDigestData).
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplateFormatter renders digest data through a user-supplied template into
+// a JSON payload. The result is stored in Message.Payload, which the webhook
+// channel posts verbatim.
+type TemplateFormatter[T any] struct {
+	tmpl *template.Template
+}
+
+// templateFuncs are available to payload templates. Use {{json .Field}} to
+// embed values as properly escaped JSON literals.
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join":  strings.Join,
+	"now":   func() string { return time.Now().UTC().Format(time.RFC3339) },
+}
+
+// NewTemplateFormatter parses a payload template.
+func NewTemplateFormatter[T any](text string) (*TemplateFormatter[T], error) {
+	tmpl, err := template.New("payload").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse payload template: %w", err)
+	}
+	return &TemplateFormatter[T]{tmpl: tmpl}, nil
+}
+
+// LoadTemplateFormatter reads and parses a payload template file.
+func LoadTemplateFormatter[T any](path string) (*TemplateFormatter[T], error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read payload template %s: %w", filepath.Base(path), err)
+	}
+	return NewTemplateFormatter[T](string(data))
+}
+
+// Render executes the template and checks that the output is valid JSON.
+func (f *TemplateFormatter[T]) Render(data T) (string, error) {
+	var buf bytes.Buffer
+	if err := f.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render payload template: %w", err)
+	}
+	out := bytes.TrimSpace(buf.Bytes())
+	if !json.Valid(out) {
+		return "", fmt.Errorf("payload template produced invalid JSON")
+	}
+	return string(out), nil
+}
+
+// Format implements Formatter. If rendering fails, the message carries the error
+// as plain text and no payload, so webhooks fall back to the default payload
+// rather than silently dropping the notification.
+func (f *TemplateFormatter[T]) Format(data T) Message {
+	payload, err := f.Render(data)
+	if err != nil {
+		return Message{Title: "webhook template error", Body: err.Error(), Format: "plain"}
+	}
+	return Message{Body: payload, Payload: payload, Format: "json"}
+}
@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidToken is returned when a signed token is malformed or its signature does not match.
+var ErrInvalidToken = errors.New("invalid token")
+
+// SignToken signs a payload for a specific purpose ("unsubscribe", "open", ...).
+// The payload is readable by anyone holding the token; only integrity is protected.
+func SignToken(secret []byte, purpose, payload string) string {
+	enc := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return enc + "." + base64.RawURLEncoding.EncodeToString(tokenMAC(secret, purpose, enc))
+}
+
+// VerifyToken checks a token's signature for the given purpose and returns its payload.
+func VerifyToken(secret []byte, purpose, token string) (string, error) {
+	enc, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", ErrInvalidToken
+	}
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, tokenMAC(secret, purpose, enc)) {
+		return "", ErrInvalidToken
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(enc)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	return string(raw), nil
+}
+
+func tokenMAC(secret []byte, purpose, payload string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(purpose + ":" + payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,103 @@
+package notify
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TrackingEvent identifies a recorded engagement event.
+type TrackingEvent struct {
+	Kind     string // "open" or "click"
+	DigestID string
+	UserID   int
+	URL      string // click target; empty for opens
+}
+
+// Tracker rewrites HTML email bodies for open (pixel) and click (redirect) tracking.
+// Tokens are signed so the tracking endpoints cannot be used as open redirects.
+type Tracker struct {
+	BaseURL string // public site URL serving /api/t/*
+	Secret  []byte
+}
+
+// NewTracker creates a tracker; an empty base URL or secret disables tracking.
+func NewTracker(baseURL string, secret []byte) *Tracker {
+	return &Tracker{BaseURL: strings.TrimRight(baseURL, "/"), Secret: secret}
+}
+
+// Enabled reports whether the tracker can produce links.
+func (t *Tracker) Enabled() bool {
+	return t != nil && t.BaseURL != "" && len(t.Secret) > 0
+}
+
+var hrefRe = regexp.MustCompile(`href="(https?://[^"]+)"`)
+
+// Instrument rewrites outbound links through the click endpoint and appends an
+// open-tracking pixel. Unsubscribe links are left untouched.
+func (t *Tracker) Instrument(msg Message, digestID string, userID int) Message {
+	if !t.Enabled() || msg.HTMLBody == "" {
+		return msg
+	}
+
+	body := hrefRe.ReplaceAllStringFunc(msg.HTMLBody, func(m string) string {
+		target := hrefRe.FindStringSubmatch(m)[1]
+		if strings.Contains(target, "/api/unsubscribe") {
+			return m
+		}
+		return fmt.Sprintf(`href="%s"`, t.ClickURL(digestID, userID, unescapeHTMLAttr(target)))
+	})
+
+	pixel := fmt.Sprintf(`<img src="%s" width="1" height="1" alt="" style="display:none;border:0;">`,
+		t.OpenURL(digestID, userID))
+	if i := strings.LastIndex(body, "</body>"); i >= 0 {
+		body = body[:i] + pixel + body[i:]
+	} else {
+		body += pixel
+	}
+
+	msg.HTMLBody = body
+	return msg
+}
+
+// OpenURL returns the tracking pixel URL for a digest delivery.
+func (t *Tracker) OpenURL(digestID string, userID int) string {
+	token := SignToken(t.Secret, "open", digestID+"|"+strconv.Itoa(userID))
+	return t.BaseURL + "/api/t/open?t=" + url.QueryEscape(token)
+}
+
+// ClickURL returns a redirect URL that records a click before forwarding to target.
+func (t *Tracker) ClickURL(digestID string, userID int, target string) string {
+	return t.BaseURL + "/api/t/click?t=" + url.QueryEscape(t.clickToken(digestID, userID, target))
+}
+
+func (t *Tracker) clickToken(digestID string, userID int, target string) string {
+	return SignToken(t.Secret, "click", digestID+"|"+strconv.Itoa(userID)+"|"+target)
+}
+
+// ParseTrackingToken verifies an open or click token and returns the event it encodes.
+func ParseTrackingToken(secret []byte, kind, token string) (TrackingEvent, error) {
+	payload, err := VerifyToken(secret, kind, token)
+	if err != nil {
+		return TrackingEvent{}, err
+	}
+	parts := strings.SplitN(payload, "|", 3)
+	if len(parts) < 2 || (kind == "click" && len(parts) != 3) {
+		return TrackingEvent{}, ErrInvalidToken
+	}
+	userID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return TrackingEvent{}, ErrInvalidToken
+	}
+	ev := TrackingEvent{Kind: kind, DigestID: parts[0], UserID: userID}
+	if kind == "click" {
+		ev.URL = parts[2]
+	}
+	return ev, nil
+}
+
+func unescapeHTMLAttr(s string) string {
+	return strings.NewReplacer("&amp;", "&", "&#34;", `"`, "&#39;", "'").Replace(s)
+}
@@ -0,0 +1,36 @@
+package notify
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SignUnsubscribeToken creates an opaque token identifying a user and mailing list
+// ("watchbot", "newsbot", ...). Tokens do not expire so old emails keep working.
+func SignUnsubscribeToken(secret []byte, userID int, list string) string {
+	return SignToken(secret, "unsubscribe", strconv.Itoa(userID)+":"+list)
+}
+
+// VerifyUnsubscribeToken checks a token's signature and returns its user ID and list.
+func VerifyUnsubscribeToken(secret []byte, token string) (int, string, error) {
+	payload, err := VerifyToken(secret, "unsubscribe", token)
+	if err != nil {
+		return 0, "", err
+	}
+	idStr, list, ok := strings.Cut(payload, ":")
+	if !ok {
+		return 0, "", ErrInvalidToken
+	}
+	userID, err := strconv.Atoi(idStr)
+	if err != nil {
+		return 0, "", ErrInvalidToken
+	}
+	return userID, list, nil
+}
+
+// UnsubscribeURL builds the one-click unsubscribe link served by the API.
+func UnsubscribeURL(baseURL, token string) string {
+	return fmt.Sprintf("%s/api/unsubscribe?token=%s", strings.TrimRight(baseURL, "/"), url.QueryEscape(token))
+}
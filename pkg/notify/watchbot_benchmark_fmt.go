@@ -4,6 +4,8 @@ package notify
 
 import (
 	"fmt"
+	"html"
+	"math"
 	"strings"
 
 	"github.com/RobinCoderZhao/devkit-suite/pkg/benchmarks"
@@ -17,6 +19,58 @@ type BenchmarkDigestData struct {
 	ScoreCount int    // total number of scores
 	NewScores  int    // newly scraped scores
 	Date       string
+	// Changes lists scores that are new or moved since the previous scrape.
+	// When set, the message is framed as a "benchmark movement" digest.
+	Changes []benchmarks.ScoreChange
+}
+
+// maxMovementLines caps the changes listed in a movement digest.
+const maxMovementLines = 15
+
+// movementLabel describes one change, e.g. "GPQA Diamond · Opus 4.6: 91.3 → 92.0 (▲0.7)".
+func movementLabel(c benchmarks.ScoreChange) string {
+	name, unit := c.BenchmarkID, "%"
+	if b := benchmarks.FindBenchmark(c.BenchmarkID); b != nil {
+		name, unit = b.Name, b.Unit
+	}
+	if c.Variant != "" {
+		name += " (" + c.Variant + ")"
+	}
+	if unit != "Elo" {
+		unit = ""
+	} else {
+		unit = " Elo"
+	}
+	if c.New {
+		return fmt.Sprintf("%s · %s: new %.1f%s", name, c.ModelName, c.Current, unit)
+	}
+	arrow := "▲"
+	if c.Delta() < 0 {
+		arrow = "▼"
+	}
+	return fmt.Sprintf("%s · %s: %.1f → %.1f%s (%s%.1f)", name, c.ModelName, c.Previous, c.Current, unit, arrow, math.Abs(c.Delta()))
+}
+
+// movementLines returns the labels to list, plus how many were left out.
+func movementLines(changes []benchmarks.ScoreChange) ([]string, int) {
+	var lines []string
+	for i, c := range changes {
+		if i == maxMovementLines {
+			return lines, len(changes) - i
+		}
+		lines = append(lines, movementLabel(c))
+	}
+	return lines, 0
+}
+
+// attachPNG attaches the rendered report, if any, to msg.
+func attachPNG(msg *Message, path string) {
+	if path == "" {
+		return
+	}
+	if a, err := AttachFile(path); err == nil {
+		msg.Attachments = append(msg.Attachments, a)
+	}
 }
 
 // BenchmarkEmailFormatter produces HTML email with benchmark comparison.
@@ -36,6 +90,23 @@ func (f *BenchmarkEmailFormatter) Format(data BenchmarkDigestData) Message {
 		"#4a9eff", "#6c5ce7",
 	))
 
+	// Movement since the previous scrape
+	if lines, more := movementLines(data.Changes); len(lines) > 0 {
+		sb.WriteString(`
+<tr><td style="padding:16px 40px;background-color:#1a1a2e;">
+  <p style="margin:0 0 8px;font-size:15px;font-weight:700;color:#e0e0e0;">📈 Benchmark movement</p>`)
+		for _, l := range lines {
+			sb.WriteString(fmt.Sprintf(`
+  <p style="margin:0;font-size:13px;color:#c0c0d0;">%s</p>`, html.EscapeString(l)))
+		}
+		if more > 0 {
+			sb.WriteString(fmt.Sprintf(`
+  <p style="margin:4px 0 0;font-size:12px;color:#808090;">… and %d more</p>`, more))
+		}
+		sb.WriteString(`
+</td></tr>`)
+	}
+
 	// HTML table body
 	if data.HTMLTable != "" {
 		sb.WriteString(fmt.Sprintf(`
@@ -56,12 +127,20 @@ func (f *BenchmarkEmailFormatter) Format(data BenchmarkDigestData) Message {
 	sb.WriteString(EmailFooter("WatchBot Benchmark Tracker", "AI Model Comparison System", "#4a9eff"))
 	sb.WriteString(EmailWrapperClose())
 
-	return Message{
-		Title:    fmt.Sprintf("📊 AI Benchmark Report — %s", data.Date),
+	title := fmt.Sprintf("📊 AI Benchmark Report — %s", data.Date)
+	if len(data.Changes) > 0 {
+		title = fmt.Sprintf("📈 Benchmark Movement — %s (%d changes)", data.Date, len(data.Changes))
+	}
+	msg := Message{
+		Title:    title,
 		Body:     f.formatPlainText(data),
 		HTMLBody: sb.String(),
 		Format:   "html",
 	}
+
+	// Attach the rendered PNG so the chart survives clients that block images
+	attachPNG(&msg, data.PNGPath)
+	return msg
 }
 
 func (f *BenchmarkEmailFormatter) formatPlainText(data BenchmarkDigestData) string {
@@ -70,6 +149,17 @@ func (f *BenchmarkEmailFormatter) formatPlainText(data BenchmarkDigestData) stri
 	sb.WriteString(fmt.Sprintf("%d benchmarks, %d models\n", len(benchmarks.AllBenchmarks), len(data.Report.Models)))
 	sb.WriteString(fmt.Sprintf("Scores: %d total, %d new\n\n", data.ScoreCount, data.NewScores))
 
+	if lines, more := movementLines(data.Changes); len(lines) > 0 {
+		sb.WriteString("📈 Benchmark movement\n")
+		for _, l := range lines {
+			sb.WriteString("  " + l + "\n")
+		}
+		if more > 0 {
+			sb.WriteString(fmt.Sprintf("  … and %d more\n", more))
+		}
+		sb.WriteString("\n")
+	}
+
 	// Top performers by category
 	for _, cat := range benchmarks.Categories {
 		sb.WriteString(fmt.Sprintf("%s %s\n", cat.Emoji, cat.Label))
@@ -111,6 +201,17 @@ func (f *BenchmarkTelegramFormatter) Format(data BenchmarkDigestData) Message {
 	sb.WriteString(fmt.Sprintf("📊 *AI Benchmark Report* — %s\n", data.Date))
 	sb.WriteString(fmt.Sprintf("%d benchmarks · %d models\n\n", len(benchmarks.AllBenchmarks), len(data.Report.Models)))
 
+	if lines, more := movementLines(data.Changes); len(lines) > 0 {
+		sb.WriteString("📈 *Movement:*\n")
+		for _, l := range lines {
+			sb.WriteString("  " + escapeMarkdown(l) + "\n")
+		}
+		if more > 0 {
+			sb.WriteString(escapeMarkdown(fmt.Sprintf("  … and %d more", more)) + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
 	// Highlight top 3 leaders across all benchmarks
 	leaders := make(map[string]int)
 	for _, modelName := range data.Report.HighestOf {
@@ -143,8 +244,50 @@ func (f *BenchmarkTelegramFormatter) Format(data BenchmarkDigestData) Message {
 
 	sb.WriteString(fmt.Sprintf("\n_%d scores · %d new_", data.ScoreCount, data.NewScores))
 
+	title := fmt.Sprintf("📊 Benchmark Report — %s", data.Date)
+	if len(data.Changes) > 0 {
+		title = fmt.Sprintf("📈 Benchmark Movement — %s", data.Date)
+	}
+	msg := Message{
+		Title:  title,
+		Body:   sb.String(),
+		Format: "markdown",
+	}
+	attachPNG(&msg, data.PNGPath)
+	return msg
+}
+
+// candidateLabel describes one new-model candidate, e.g.
+// `"gpt-6" on llm-stats.com: GPQA Diamond 93.1%, SWE-bench Verified 81.0%`.
+func candidateLabel(c benchmarks.ModelCandidate) string {
+	return fmt.Sprintf("%q on %s: %s", c.RawName, c.Source, c.Summary)
+}
+
+// FormatCandidates produces the operator alert for leaderboard models that
+// score well but match no tracked model.
+func (f *BenchmarkEmailFormatter) FormatCandidates(candidates []benchmarks.ModelCandidate) Message {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🆕 %d unmatched models score at or above the tracked median:\n\n", len(candidates)))
+	for _, c := range candidates {
+		sb.WriteString("  " + candidateLabel(c) + "\n")
+	}
+	sb.WriteString("\nAdd them to models or aliases in the benchmark config, then run `watchbot unmatched-models` to confirm.\n")
+	return Message{
+		Title:  fmt.Sprintf("🆕 New model candidates (%d)", len(candidates)),
+		Body:   sb.String(),
+		Format: "plain",
+	}
+}
+
+func (f *BenchmarkTelegramFormatter) FormatCandidates(candidates []benchmarks.ModelCandidate) Message {
+	var sb strings.Builder
+	sb.WriteString("🆕 *Unmatched models scoring at or above the tracked median:*\n")
+	for _, c := range candidates {
+		sb.WriteString("  " + escapeMarkdown(candidateLabel(c)) + "\n")
+	}
+	sb.WriteString("\n_" + escapeMarkdown("Add them to models or aliases in the benchmark config.") + "_")
 	return Message{
-		Title:  fmt.Sprintf("📊 Benchmark Report — %s", data.Date),
+		Title:  fmt.Sprintf("🆕 New model candidates (%d)", len(candidates)),
 		Body:   sb.String(),
 		Format: "markdown",
 	}
@@ -18,6 +18,80 @@ type WatchDigestData struct {
 	Groups      []CompetitorGroup // changes grouped by competitor
 	Unchanged   []string          // competitor names without changes
 	Date        string
+	// Labels (i18n); zero value renders the default Chinese template
+	Labels WatchLabels
+}
+
+// WatchLabels holds i18n labels for rendering WatchBot digests.
+type WatchLabels struct {
+	DigestTitle        string // "竞品监控报告"
+	CompetitorsChanged string // "检测到 %d 个竞品共 %d 个页面发生变化"
+	SubjectChanged     string // "%d 个竞品发生变化"
+	PagesChanged       string // "%d 个页面变化"
+	DiffLines          string // "+%d / -%d 行"
+	ViewDiff           string // "查看差异"
+	ViewPage           string // "查看原页面 →"
+	Unchanged          string // "未发生变化"
+	Tagline            string // "竞品变化监控系统"
+	Critical           string
+	Important          string
+	Minor              string
+	ListSeparator      string // "、"
+
+	// Plural, if set, returns the format of a count label ("CompetitorsChanged",
+	// "SubjectChanged" or "PagesChanged") that agrees with count n, for
+	// languages where "1 pages" reads wrong. Nil uses the fields as is.
+	Plural func(key string, n int) string
+}
+
+// defaultWatchLabels reproduces the original Chinese WatchBot template.
+var defaultWatchLabels = WatchLabels{
+	DigestTitle:        "竞品监控报告",
+	CompetitorsChanged: "检测到 %d 个竞品共 %d 个页面发生变化",
+	SubjectChanged:     "%d 个竞品发生变化",
+	PagesChanged:       "%d 个页面变化",
+	DiffLines:          "+%d / -%d 行",
+	ViewDiff:           "查看差异",
+	ViewPage:           "查看原页面 →",
+	Unchanged:          "未发生变化",
+	Tagline:            "竞品变化监控系统",
+	Critical:           "Critical",
+	Important:          "Important",
+	Minor:              "Minor",
+	ListSeparator:      "、",
+}
+
+// labels returns the digest labels, falling back to the defaults when unset.
+func (d WatchDigestData) labels() WatchLabels {
+	if d.Labels.DigestTitle == "" {
+		return defaultWatchLabels
+	}
+	return d.Labels
+}
+
+// count formats a count label, using the plural form for n when available.
+// Extra args follow n, e.g. the page total of CompetitorsChanged.
+func (l WatchLabels) count(key, format string, n int, extra ...any) string {
+	if l.Plural != nil {
+		if f := l.Plural(key, n); f != "" {
+			format = f
+		}
+	}
+	return fmt.Sprintf(format, append([]any{n}, extra...)...)
+}
+
+// SeverityLabel returns the localized label for a severity level.
+func (l WatchLabels) SeverityLabel(s string) string {
+	switch s {
+	case "critical":
+		return l.Critical
+	case "important":
+		return l.Important
+	case "minor":
+		return l.Minor
+	default:
+		return s
+	}
 }
 
 // CompetitorGroup holds all changes for a single competitor.
@@ -36,6 +110,7 @@ type WatchChangeItem struct {
 	Analysis       string // LLM analysis (may contain markdown)
 	Additions      int
 	Deletions      int
+	DiffUnified    string // raw unified diff; rendered only when inline diffs are enabled
 }
 
 // GroupChanges groups a flat list of changes by CompetitorName, preserving order.
@@ -81,15 +156,24 @@ func severityRank(s string) int {
 	}
 }
 
+// WatchFormatter renders a WatchBot digest for one channel.
+type WatchFormatter interface {
+	Format(data WatchDigestData) Message
+}
+
 // ---- WatchBot Email Formatter ----
 
 // WatchEmailFormatter produces rich HTML email for WatchBot digests.
-type WatchEmailFormatter struct{}
+type WatchEmailFormatter struct {
+	// InlineDiff adds a collapsed, colorized unified diff under each change.
+	InlineDiff bool
+}
 
 func NewWatchEmailFormatter() *WatchEmailFormatter { return &WatchEmailFormatter{} }
 
 func (f *WatchEmailFormatter) Format(data WatchDigestData) Message {
 	var sb strings.Builder
+	labels := data.labels()
 
 	// Count total page changes
 	totalPages := 0
@@ -99,8 +183,8 @@ func (f *WatchEmailFormatter) Format(data WatchDigestData) Message {
 
 	sb.WriteString(EmailWrapperOpen())
 	sb.WriteString(EmailHeader(
-		"🔍 竞品监控报告",
-		fmt.Sprintf("检测到 %d 个竞品共 %d 个页面发生变化", len(data.Groups), totalPages),
+		"🔍 "+labels.DigestTitle,
+		labels.count("CompetitorsChanged", labels.CompetitorsChanged, len(data.Groups), totalPages),
 		"#e65100", "#ff6d00",
 	))
 
@@ -114,7 +198,7 @@ func (f *WatchEmailFormatter) Format(data WatchDigestData) Message {
   <table role="presentation" width="100%%" cellpadding="0" cellspacing="0"><tr>
     <td style="vertical-align:middle;">
       <span style="font-size:20px;font-weight:800;color:#f0f0f0;letter-spacing:0.3px;">%s %s</span>
-      <span style="display:inline-block;margin-left:10px;padding:2px 10px;background:rgba(255,109,0,0.15);border-radius:10px;font-size:12px;color:#ff9800;font-weight:600;">%d 个页面变化</span>
+      <span style="display:inline-block;margin-left:10px;padding:2px 10px;background:rgba(255,109,0,0.15);border-radius:10px;font-size:12px;color:#ff9800;font-weight:600;">%s</span>
     </td>
   </tr></table>
 </td></tr>
@@ -122,15 +206,19 @@ func (f *WatchEmailFormatter) Format(data WatchDigestData) Message {
 			EmailRowBgColor(gi),
 			emoji,
 			html.EscapeString(group.CompetitorName),
-			len(group.Changes)))
+			html.EscapeString(labels.count("PagesChanged", labels.PagesChanged, len(group.Changes)))))
 
 		// ── Page changes under this competitor ──
 		for pi, c := range group.Changes {
 			severityEmoji := ImportanceEmoji(c.Severity)
-			label := severityLabel(c.Severity)
+			label := labels.SeverityLabel(c.Severity)
 			badge := ImportanceBadgeHTML(c.Severity, severityEmoji+" "+label)
 			analysisHTML := MarkdownToHTML(c.Analysis)
 			stats := DiffStatsHTML(c.Additions, c.Deletions)
+			diffHTML := ""
+			if f.InlineDiff {
+				diffHTML = DiffHTML(c.DiffUnified, labels.ViewDiff)
+			}
 
 			// Separator between pages (not before first)
 			borderTop := ""
@@ -151,8 +239,9 @@ func (f *WatchEmailFormatter) Format(data WatchDigestData) Message {
     <tr><td style="padding-top:8px;">
       <table role="presentation" cellpadding="0" cellspacing="0"><tr>
         <td style="padding-right:12px;">%s</td>
-        <td><a href="%s" style="color:#ff9800;font-size:12px;text-decoration:none;font-weight:500;">查看原页面 →</a></td>
+        <td><a href="%s" style="color:#ff9800;font-size:12px;text-decoration:none;font-weight:500;">%s</a></td>
       </tr></table>
+      %s
     </td></tr>
   </table>
 </td></tr>
@@ -161,7 +250,9 @@ func (f *WatchEmailFormatter) Format(data WatchDigestData) Message {
 				html.EscapeString(c.PageType),
 				analysisHTML,
 				stats,
-				html.EscapeString(c.PageURL)))
+				html.EscapeString(c.PageURL),
+				html.EscapeString(labels.ViewPage),
+				diffHTML))
 		}
 
 		// Bottom border after each competitor group
@@ -173,16 +264,16 @@ func (f *WatchEmailFormatter) Format(data WatchDigestData) Message {
 	if len(data.Unchanged) > 0 {
 		sb.WriteString(fmt.Sprintf(`
 <tr><td style="background-color:#1a1a2e;padding:16px 40px;border-bottom:1px solid rgba(255,255,255,0.04);">
-  <p style="margin:0;font-size:13px;color:#505070;">✅ 未发生变化：%s</p>
+  <p style="margin:0;font-size:13px;color:#505070;">✅ %s：%s</p>
 </td></tr>
-`, html.EscapeString(strings.Join(data.Unchanged, "、"))))
+`, html.EscapeString(labels.Unchanged), html.EscapeString(strings.Join(data.Unchanged, labels.ListSeparator))))
 	}
 
-	sb.WriteString(EmailFooter("WatchBot V2", "竞品变化监控系统", "#ff9800"))
+	sb.WriteString(EmailFooter("WatchBot V2", labels.Tagline, "#ff9800"))
 	sb.WriteString(EmailWrapperClose())
 
 	return Message{
-		Title:    fmt.Sprintf("🔍 竞品监控报告 — %d 个竞品发生变化", len(data.Groups)),
+		Title:    fmt.Sprintf("🔍 %s — %s", labels.DigestTitle, labels.count("SubjectChanged", labels.SubjectChanged, len(data.Groups))),
 		Body:     f.formatPlainText(data),
 		HTMLBody: sb.String(),
 		Format:   "html",
@@ -191,17 +282,18 @@ func (f *WatchEmailFormatter) Format(data WatchDigestData) Message {
 
 func (f *WatchEmailFormatter) formatPlainText(data WatchDigestData) string {
 	var sb strings.Builder
+	labels := data.labels()
 	totalPages := 0
 	for _, g := range data.Groups {
 		totalPages += len(g.Changes)
 	}
-	sb.WriteString(fmt.Sprintf("🔍 竞品监控报告 — %d 个竞品 %d 个页面发生变化\n\n", len(data.Groups), totalPages))
+	sb.WriteString(fmt.Sprintf("🔍 %s — %s\n\n", labels.DigestTitle, labels.count("CompetitorsChanged", labels.CompetitorsChanged, len(data.Groups), totalPages)))
 
 	for _, group := range data.Groups {
 		emoji := ImportanceEmoji(group.MaxSeverity)
-		sb.WriteString(fmt.Sprintf("━━ %s %s (%d 个页面) ━━\n", emoji, group.CompetitorName, len(group.Changes)))
+		sb.WriteString(fmt.Sprintf("━━ %s %s (%s) ━━\n", emoji, group.CompetitorName, labels.count("PagesChanged", labels.PagesChanged, len(group.Changes))))
 		for _, c := range group.Changes {
-			sb.WriteString(fmt.Sprintf("\n  📄 %s [%s]\n", c.PageType, severityLabel(c.Severity)))
+			sb.WriteString(fmt.Sprintf("\n  📄 %s [%s]\n", c.PageType, labels.SeverityLabel(c.Severity)))
 			if c.Analysis != "" {
 				// Indent analysis lines
 				for _, line := range strings.Split(StripMarkdown(c.Analysis), "\n") {
@@ -210,12 +302,12 @@ func (f *WatchEmailFormatter) formatPlainText(data WatchDigestData) string {
 					}
 				}
 			}
-			sb.WriteString(fmt.Sprintf("  📊 +%d / -%d 行 · 🔗 %s\n", c.Additions, c.Deletions, c.PageURL))
+			sb.WriteString(fmt.Sprintf("  📊 %s · 🔗 %s\n", fmt.Sprintf(labels.DiffLines, c.Additions, c.Deletions), c.PageURL))
 		}
 		sb.WriteString("\n")
 	}
 	if len(data.Unchanged) > 0 {
-		sb.WriteString("---\n✅ 未变化：" + strings.Join(data.Unchanged, "、") + "\n")
+		sb.WriteString("---\n✅ " + labels.Unchanged + "：" + strings.Join(data.Unchanged, labels.ListSeparator) + "\n")
 	}
 	return sb.String()
 }
@@ -229,17 +321,18 @@ func NewWatchTelegramFormatter() *WatchTelegramFormatter { return &WatchTelegram
 
 func (f *WatchTelegramFormatter) Format(data WatchDigestData) Message {
 	var sb strings.Builder
+	labels := data.labels()
 	totalPages := 0
 	for _, g := range data.Groups {
 		totalPages += len(g.Changes)
 	}
-	sb.WriteString(fmt.Sprintf("🔍 *竞品监控报告*\n%d 个竞品 %d 个页面变化\n\n", len(data.Groups), totalPages))
+	sb.WriteString(fmt.Sprintf("🔍 *%s*\n%s\n\n", labels.DigestTitle, labels.count("CompetitorsChanged", labels.CompetitorsChanged, len(data.Groups), totalPages)))
 
 	for _, group := range data.Groups {
 		emoji := ImportanceEmoji(group.MaxSeverity)
 		sb.WriteString(fmt.Sprintf("*%s %s*\n", emoji, group.CompetitorName))
 		for _, c := range group.Changes {
-			sb.WriteString(fmt.Sprintf("  📄 *%s* · %s\n", c.PageType, severityLabel(c.Severity)))
+			sb.WriteString(fmt.Sprintf("  📄 *%s* · %s\n", c.PageType, labels.SeverityLabel(c.Severity)))
 			if c.Analysis != "" {
 				analysis := c.Analysis
 				if len(analysis) > 500 {
@@ -247,32 +340,199 @@ func (f *WatchTelegramFormatter) Format(data WatchDigestData) Message {
 				}
 				sb.WriteString(analysis + "\n")
 			}
-			sb.WriteString(fmt.Sprintf("  📊 +%d / -%d · [查看原页面](%s)\n", c.Additions, c.Deletions, c.PageURL))
+			sb.WriteString(fmt.Sprintf("  📊 +%d / -%d · [%s](%s)\n", c.Additions, c.Deletions, strings.TrimSuffix(labels.ViewPage, " →"), c.PageURL))
 		}
 		sb.WriteString("\n")
 	}
 	if len(data.Unchanged) > 0 {
-		sb.WriteString("✅ 未变化：" + strings.Join(data.Unchanged, "、") + "\n")
+		sb.WriteString("✅ " + labels.Unchanged + "：" + strings.Join(data.Unchanged, labels.ListSeparator) + "\n")
 	}
 
 	return Message{
-		Title:  fmt.Sprintf("🔍 竞品监控 — %d 个竞品变化", len(data.Groups)),
+		Title:  fmt.Sprintf("🔍 %s — %s", labels.DigestTitle, labels.count("SubjectChanged", labels.SubjectChanged, len(data.Groups))),
 		Body:   sb.String(),
 		Format: "markdown",
 	}
 }
 
-// ---- helpers ----
+// ---- WatchBot Discord Formatter ----
 
-func severityLabel(s string) string {
-	switch s {
-	case "critical":
-		return "Critical"
-	case "important":
-		return "Important"
-	case "minor":
-		return "Minor"
-	default:
-		return s
+// WatchDiscordFormatter produces Discord embeds for WatchBot: one embed per
+// competitor, colored by its highest severity, with a field per changed page.
+type WatchDiscordFormatter struct{}
+
+func NewWatchDiscordFormatter() *WatchDiscordFormatter { return &WatchDiscordFormatter{} }
+
+func (f *WatchDiscordFormatter) Format(data WatchDigestData) Message {
+	labels := data.labels()
+	totalPages := 0
+	for _, g := range data.Groups {
+		totalPages += len(g.Changes)
+	}
+	summary := labels.count("CompetitorsChanged", labels.CompetitorsChanged, len(data.Groups), totalPages)
+
+	var embeds []DiscordEmbed
+	for i, group := range data.Groups {
+		// Discord allows 10 embeds; the last one lists whoever is left
+		if i == discordMaxEmbeds-1 && len(data.Groups) > discordMaxEmbeds {
+			var names []string
+			for _, g := range data.Groups[i:] {
+				names = append(names, fmt.Sprintf("%s %s · %s", ImportanceEmoji(g.MaxSeverity), g.CompetitorName, labels.count("PagesChanged", labels.PagesChanged, len(g.Changes))))
+			}
+			embeds = append(embeds, DiscordEmbed{
+				Description: strings.Join(names, "\n"),
+				Color:       SeverityColor(group.MaxSeverity),
+			})
+			break
+		}
+
+		embed := DiscordEmbed{
+			Title: ImportanceEmoji(group.MaxSeverity) + " " + group.CompetitorName,
+			Color: SeverityColor(group.MaxSeverity),
+		}
+		for _, c := range group.Changes {
+			var value strings.Builder
+			if c.Analysis != "" {
+				value.WriteString(truncateRunes(c.Analysis, 700) + "\n")
+			}
+			value.WriteString("📊 " + fmt.Sprintf(labels.DiffLines, c.Additions, c.Deletions))
+			if c.PageURL != "" {
+				value.WriteString(fmt.Sprintf(" · [%s](%s)", strings.TrimSuffix(labels.ViewPage, " →"), c.PageURL))
+			}
+			embed.Fields = append(embed.Fields, DiscordEmbedField{
+				Name:  fmt.Sprintf("📄 %s · %s", c.PageType, labels.SeverityLabel(c.Severity)),
+				Value: value.String(),
+			})
+		}
+		embeds = append(embeds, embed)
+	}
+	if len(embeds) == 0 {
+		embeds = append(embeds, DiscordEmbed{Description: summary, Color: SeverityColor("")})
+	}
+
+	footer := labels.Tagline
+	if len(data.Unchanged) > 0 {
+		footer = "✅ " + labels.Unchanged + "：" + strings.Join(data.Unchanged, labels.ListSeparator) + " · " + footer
+	}
+	embeds[len(embeds)-1].Footer = &DiscordEmbedFooter{Text: footer}
+
+	return Message{
+		Title:  fmt.Sprintf("🔍 **%s** — %s", labels.DigestTitle, summary),
+		Body:   summary,
+		Format: "markdown",
+		Embeds: embeds,
+	}
+}
+
+// ---- WatchBot WeChat Work Formatter ----
+
+// WatchWeChatWorkFormatter produces WeChat Work (企业微信) content for
+// WatchBot: a markdown digest with a section per competitor, and a news card
+// per changed page linking to it. Competitors that do not fit the 4096-byte
+// markdown limit are listed by name at the end.
+type WatchWeChatWorkFormatter struct{}
+
+func NewWatchWeChatWorkFormatter() *WatchWeChatWorkFormatter { return &WatchWeChatWorkFormatter{} }
+
+func (f *WatchWeChatWorkFormatter) Format(data WatchDigestData) Message {
+	labels := data.labels()
+	totalPages := 0
+	for _, g := range data.Groups {
+		totalPages += len(g.Changes)
+	}
+	summary := labels.count("CompetitorsChanged", labels.CompetitorsChanged, len(data.Groups), totalPages)
+
+	footer := labels.Tagline
+	if len(data.Unchanged) > 0 {
+		footer = "✅ " + labels.Unchanged + "：" + strings.Join(data.Unchanged, labels.ListSeparator) + " · " + footer
+	}
+	footer = `<font color="comment">` + footer + `</font>`
+
+	var md strings.Builder
+	md.WriteString("# 🔍 " + labels.DigestTitle + "\n" + summary + "\n")
+	if data.Date != "" {
+		md.WriteString(`<font color="comment">` + data.Date + "</font>\n")
+	}
+
+	for i, group := range data.Groups {
+		var section strings.Builder
+		section.WriteString("\n## " + ImportanceEmoji(group.MaxSeverity) + " " + group.CompetitorName + "\n")
+		for _, c := range group.Changes {
+			color := "comment"
+			if c.Severity == "critical" || c.Severity == "important" {
+				color = "warning"
+			}
+			section.WriteString(fmt.Sprintf("**📄 %s** · <font color=\"%s\">%s</font>\n", c.PageType, color, labels.SeverityLabel(c.Severity)))
+			if c.Analysis != "" {
+				section.WriteString(truncateRunes(StripMarkdown(c.Analysis), 300) + "\n")
+			}
+			section.WriteString("📊 " + fmt.Sprintf(labels.DiffLines, c.Additions, c.Deletions))
+			if c.PageURL != "" {
+				section.WriteString(fmt.Sprintf(" · [%s](%s)", strings.TrimSuffix(labels.ViewPage, " →"), c.PageURL))
+			}
+			section.WriteString("\n")
+		}
+
+		// Keep room for the footer and a few lines naming the rest
+		if md.Len()+section.Len()+len(footer)+200 > wechatWorkMaxMarkdown {
+			md.WriteString("\n")
+			for _, g := range data.Groups[i:] {
+				line := fmt.Sprintf("%s %s · %s\n", ImportanceEmoji(g.MaxSeverity), g.CompetitorName, labels.count("PagesChanged", labels.PagesChanged, len(g.Changes)))
+				if md.Len()+len(line)+len(footer)+len("…\n\n") > wechatWorkMaxMarkdown {
+					md.WriteString("…\n")
+					break
+				}
+				md.WriteString(line)
+			}
+			break
+		}
+		md.WriteString(section.String())
+	}
+	md.WriteString("\n" + footer)
+
+	var articles []WeChatWorkArticle
+	for _, group := range data.Groups {
+		for _, c := range group.Changes {
+			if c.PageURL != "" {
+				articles = append(articles, WeChatWorkArticle{
+					Title:       ImportanceEmoji(c.Severity) + " " + c.CompetitorName + " · " + c.PageType,
+					Description: StripMarkdown(c.Analysis),
+					URL:         c.PageURL,
+				})
+			}
+		}
+	}
+
+	return Message{
+		Title:      fmt.Sprintf("🔍 %s — %s", labels.DigestTitle, summary),
+		Body:       summary,
+		Format:     "markdown",
+		WeChatWork: &WeChatWorkContent{Markdown: md.String(), Articles: articles},
+	}
+}
+
+// ---- WatchBot SMS Formatter ----
+
+// WatchSMSFormatter produces a single-segment SMS for critical WatchBot changes.
+// Non-critical changes are dropped; an empty Message means nothing to page about.
+type WatchSMSFormatter struct{}
+
+func NewWatchSMSFormatter() *WatchSMSFormatter { return &WatchSMSFormatter{} }
+
+func (f *WatchSMSFormatter) Format(data WatchDigestData) Message {
+	var parts []string
+	for _, g := range data.Groups {
+		for _, c := range g.Changes {
+			if c.Severity == "critical" {
+				parts = append(parts, fmt.Sprintf("%s(%s)", c.CompetitorName, c.PageType))
+			}
+		}
+	}
+	if len(parts) == 0 {
+		return Message{}
+	}
+	return Message{
+		Title:  TruncateSMS(fmt.Sprintf("[WatchBot] 严重变化 %d 处: %s", len(parts), strings.Join(parts, ", "))),
+		Format: "plain",
 	}
 }
@@ -31,18 +31,23 @@ func NewWebhookNotifier(cfg WebhookConfig) *WebhookNotifier {
 
 func (w *WebhookNotifier) Channel() Channel { return ChannelWebhook }
 
-// Send sends a message to the webhook URL.
+// Send sends a message to the webhook URL. A pre-rendered msg.Payload is sent
+// as-is; otherwise the default title/body/format/url object is posted.
 func (w *WebhookNotifier) Send(ctx context.Context, msg Message) error {
-	payload := map[string]string{
-		"title":  msg.Title,
-		"body":   msg.Body,
-		"format": msg.Format,
-		"url":    msg.URL,
-	}
+	body := []byte(msg.Payload)
+	if len(body) == 0 {
+		payload := map[string]string{
+			"title":  msg.Title,
+			"body":   msg.Body,
+			"format": msg.Format,
+			"url":    msg.URL,
+		}
 
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("marshal payload: %w", err)
+		var err error
+		body, err = json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("marshal payload: %w", err)
+		}
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", w.config.URL, bytes.NewReader(body))
@@ -50,6 +55,9 @@ func (w *WebhookNotifier) Send(ctx context.Context, msg Message) error {
 		return fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if msg.IdempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", msg.IdempotencyKey)
+	}
 	for k, v := range w.config.Headers {
 		req.Header.Set(k, v)
 	}
@@ -0,0 +1,186 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// WeChatWorkConfig holds WeChat Work (企业微信) group robot configuration.
+type WeChatWorkConfig struct {
+	// WebhookURL is the robot's address,
+	// https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key=...; a bare key
+	// is accepted too.
+	WebhookURL string `yaml:"webhook_url" json:"webhook_url"`
+	// MsgType is "markdown" (default) or "news". News sends the message's
+	// articles as link cards, falling back to markdown when there are none.
+	MsgType string `yaml:"msg_type" json:"msg_type"`
+}
+
+// WeChatWorkContent is a message pre-rendered for WeChat Work, as produced
+// by WatchWeChatWorkFormatter into Message.WeChatWork.
+type WeChatWorkContent struct {
+	Markdown string              // WeChat Work markdown: headings, bold, links, quotes and <font color>
+	Articles []WeChatWorkArticle // news cards
+}
+
+// WeChatWorkArticle is one link card of a news message.
+type WeChatWorkArticle struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
+	PicURL      string `json:"picurl,omitempty"`
+}
+
+// WeChat Work group robot limits, in UTF-8 bytes.
+const (
+	wechatWorkMaxMarkdown    = 4096
+	wechatWorkMaxArticles    = 8
+	wechatWorkMaxTitle       = 128
+	wechatWorkMaxDescription = 512
+)
+
+// wechatWorkSendURL is the robot API endpoint a bare key is appended to.
+const wechatWorkSendURL = "https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key="
+
+// WeChatWorkNotifier posts messages to a WeChat Work group through a robot
+// webhook.
+type WeChatWorkNotifier struct {
+	config WeChatWorkConfig
+	http   *http.Client
+}
+
+// NewWeChatWorkNotifier creates a new WeChat Work notifier.
+func NewWeChatWorkNotifier(cfg WeChatWorkConfig) *WeChatWorkNotifier {
+	if cfg.WebhookURL != "" && !strings.Contains(cfg.WebhookURL, "://") {
+		cfg.WebhookURL = wechatWorkSendURL + url.QueryEscape(cfg.WebhookURL)
+	}
+	return &WeChatWorkNotifier{
+		config: cfg,
+		http:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (n *WeChatWorkNotifier) Channel() Channel { return ChannelWeChatWork }
+
+type wechatWorkPayload struct {
+	MsgType  string `json:"msgtype"`
+	Markdown *struct {
+		Content string `json:"content"`
+	} `json:"markdown,omitempty"`
+	News *struct {
+		Articles []WeChatWorkArticle `json:"articles"`
+	} `json:"news,omitempty"`
+}
+
+// Send posts a message. Pre-rendered msg.WeChatWork content is used when
+// set; otherwise the title, body and URL become a markdown message, or a
+// single news card when MsgType is "news" and the message has a URL.
+// Attachments are not supported by group robots and are skipped.
+func (n *WeChatWorkNotifier) Send(ctx context.Context, msg Message) error {
+	content := msg.WeChatWork
+	if content == nil {
+		content = &WeChatWorkContent{Markdown: wechatWorkMarkdown(msg)}
+		if msg.URL != "" {
+			content.Articles = []WeChatWorkArticle{{
+				Title:       msg.Title,
+				Description: StripMarkdown(msg.Body),
+				URL:         msg.URL,
+			}}
+		}
+	}
+
+	var payload wechatWorkPayload
+	if n.config.MsgType == "news" && len(content.Articles) > 0 {
+		payload.MsgType = "news"
+		payload.News = &struct {
+			Articles []WeChatWorkArticle `json:"articles"`
+		}{Articles: FitWeChatWorkArticles(content.Articles)}
+	} else {
+		payload.MsgType = "markdown"
+		payload.Markdown = &struct {
+			Content string `json:"content"`
+		}{Content: truncateBytes(content.Markdown, wechatWorkMaxMarkdown)}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", n.config.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("send wechat work message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("wechat work API error (%d): %s", resp.StatusCode, string(respBody))
+	}
+	// Failures, e.g. an invalid key or the 20 messages per minute limit,
+	// come back as 200 with a non-zero errcode
+	var result struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("decode wechat work response: %w", err)
+	}
+	if result.ErrCode != 0 {
+		return fmt.Errorf("wechat work API error (%d): %s", result.ErrCode, result.ErrMsg)
+	}
+	return nil
+}
+
+// wechatWorkMarkdown renders a plain message as WeChat Work markdown.
+func wechatWorkMarkdown(msg Message) string {
+	var sb strings.Builder
+	if msg.Title != "" {
+		sb.WriteString("**" + msg.Title + "**\n")
+	}
+	if msg.Body != "" {
+		sb.WriteString(msg.Body + "\n")
+	}
+	if msg.URL != "" {
+		sb.WriteString(fmt.Sprintf("[%s](%s)\n", msg.URL, msg.URL))
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// FitWeChatWorkArticles trims news cards to the robot's limits: at most 8
+// cards, titles of 128 bytes and descriptions of 512.
+func FitWeChatWorkArticles(articles []WeChatWorkArticle) []WeChatWorkArticle {
+	out := make([]WeChatWorkArticle, 0, min(len(articles), wechatWorkMaxArticles))
+	for _, a := range articles[:min(len(articles), wechatWorkMaxArticles)] {
+		a.Title = truncateBytes(a.Title, wechatWorkMaxTitle)
+		a.Description = truncateBytes(a.Description, wechatWorkMaxDescription)
+		out = append(out, a)
+	}
+	return out
+}
+
+// truncateBytes cuts s to at most max bytes at a rune boundary, ending with
+// "…" when cut.
+func truncateBytes(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := max - len("…")
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "…"
+}
@@ -0,0 +1,128 @@
+// Package ratelimit limits how often a key, such as a user or a client IP,
+// may make requests, with a token bucket per key.
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit allows Rate requests per second on average, in bursts of up to
+// Burst. The zero Limit is unlimited.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Unlimited reports whether l lets every request through.
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0
+}
+
+// String formats l as ParseLimit reads it, per second.
+func (l Limit) String() string {
+	if l.Unlimited() {
+		return "off"
+	}
+	return strconv.FormatFloat(l.Rate, 'f', -1, 64) + "/s," + strconv.Itoa(l.Burst)
+}
+
+// ParseLimit parses a limit written "<n>/<s|m|h>", optionally followed by
+// ",<burst>", e.g. "60/m" or "10/s,50". The burst defaults to n. "off" and
+// "0" are unlimited.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "off" || s == "0" {
+		return Limit{}, nil
+	}
+	rate, burstStr, hasBurst := strings.Cut(s, ",")
+	nStr, unit, ok := strings.Cut(rate, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q (e.g. 60/m or 10/s,50)", s)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(nStr))
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: count must be a positive integer", s)
+	}
+	var per time.Duration
+	switch strings.TrimSpace(unit) {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		return Limit{}, fmt.Errorf("invalid rate limit %q: period must be s, m or h", s)
+	}
+	l := Limit{Rate: float64(n) / per.Seconds(), Burst: n}
+	if hasBurst {
+		if l.Burst, err = strconv.Atoi(strings.TrimSpace(burstStr)); err != nil || l.Burst <= 0 {
+			return Limit{}, fmt.Errorf("invalid rate limit %q: burst must be a positive integer", s)
+		}
+	}
+	return l, nil
+}
+
+// Limiter keeps a token bucket per key. Each call passes the key's limit,
+// so keys can have different limits, and a changed limit applies at once.
+type Limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	refill time.Duration // time to refill from empty under the last limit
+}
+
+// New creates a Limiter.
+func New() *Limiter {
+	return &Limiter{buckets: make(map[string]*bucket)}
+}
+
+// Allow takes a token from key's bucket, or reports how long until one is
+// available.
+func (l *Limiter) Allow(key string, limit Limit) (time.Duration, bool) {
+	return l.allowAt(key, limit, time.Now())
+}
+
+func (l *Limiter) allowAt(key string, limit Limit, now time.Time) (time.Duration, bool) {
+	if limit.Unlimited() {
+		return 0, true
+	}
+	burst := float64(max(limit.Burst, 1))
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Buckets idle long enough to refill are the same as new ones, so
+	// dropping them bounds memory as keys come and go
+	if now.Sub(l.swept) > time.Minute {
+		for k, b := range l.buckets {
+			if now.Sub(b.last) >= b.refill {
+				delete(l.buckets, k)
+			}
+		}
+		l.swept = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = min(burst, b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+	b.refill = time.Duration(burst / limit.Rate * float64(time.Second))
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second)), false
+	}
+	b.tokens--
+	return 0, true
+}
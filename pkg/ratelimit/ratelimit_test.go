@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Limit
+	}{
+		{"60/m", Limit{Rate: 1, Burst: 60}},
+		{"10/s,50", Limit{Rate: 10, Burst: 50}},
+		{" 3600/h , 10 ", Limit{Rate: 1, Burst: 10}},
+		{"off", Limit{}},
+		{"0", Limit{}},
+	}
+	for _, tt := range tests {
+		got, err := ParseLimit(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseLimit(%q) = %+v, %v; want %+v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "60", "60/d", "-1/s", "10/s,0", "x/m"} {
+		if _, err := ParseLimit(bad); err == nil {
+			t.Errorf("ParseLimit(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	l := New()
+	limit := Limit{Rate: 1, Burst: 2}
+	now := time.Unix(1000, 0)
+
+	for i := 0; i < 2; i++ {
+		if _, ok := l.allowAt("a", limit, now); !ok {
+			t.Fatalf("request %d within the burst was limited", i+1)
+		}
+	}
+	wait, ok := l.allowAt("a", limit, now)
+	if ok || wait != time.Second {
+		t.Fatalf("third request: ok=%v wait=%v; want limited for 1s", ok, wait)
+	}
+	// Other keys have their own bucket
+	if _, ok := l.allowAt("b", limit, now); !ok {
+		t.Fatal("another key was limited")
+	}
+	if _, ok := l.allowAt("a", limit, now.Add(time.Second)); !ok {
+		t.Fatal("a token should refill after a second")
+	}
+	// A higher limit applies at once
+	if _, ok := l.allowAt("a", Limit{Rate: 100, Burst: 100}, now.Add(1100*time.Millisecond)); !ok {
+		t.Fatal("raised limit did not apply")
+	}
+	if _, ok := l.allowAt("a", Limit{}, now); !ok {
+		t.Fatal("the zero limit should be unlimited")
+	}
+
+	// Idle buckets are dropped
+	l.allowAt("c", limit, now.Add(time.Hour))
+	if len(l.buckets) != 1 {
+		t.Fatalf("expected idle buckets to be swept, have %d", len(l.buckets))
+	}
+}
@@ -0,0 +1,68 @@
+package scraper
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BatchOptions configures FetchAll.
+type BatchOptions struct {
+	Fetch       *FetchOptions // per-request options; nil uses the fetcher's defaults
+	Concurrency int           // maximum requests in flight; defaults to 4
+	Deadline    time.Duration // overall time limit for the batch; zero means none
+}
+
+// BatchResult is the outcome of fetching one URL in a batch.
+type BatchResult struct {
+	URL    string       `json:"url"`
+	Result *FetchResult `json:"result,omitempty"`
+	Err    error        `json:"-"`
+}
+
+// FetchAll fetches urls concurrently through f with a bounded worker pool.
+// Results are returned in input order, one per URL, each with its own error;
+// a failing URL never aborts the batch. URLs not fetched before the deadline
+// (or ctx cancellation) report the context error.
+func FetchAll(ctx context.Context, f Fetcher, urls []string, opts *BatchOptions) []BatchResult {
+	if opts == nil {
+		opts = &BatchOptions{}
+	}
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = 4
+	}
+	if workers > len(urls) {
+		workers = len(urls)
+	}
+	if opts.Deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Deadline)
+		defer cancel()
+	}
+
+	results := make([]BatchResult, len(urls))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i].Result, results[i].Err = f.Fetch(ctx, urls[i], opts.Fetch)
+			}
+		}()
+	}
+
+	for i, u := range urls {
+		results[i].URL = u
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
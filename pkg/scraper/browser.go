@@ -0,0 +1,293 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// Defaults for BrowserFetcher.
+const (
+	browserViewportWidth  = 1280
+	browserViewportHeight = 800
+	browserWaitTimeout    = 10 * time.Second
+	browserStartTimeout   = 15 * time.Second // browser start-up and connection
+	browserMaxShotHeight  = 6000             // screenshots stop this far down long pages
+)
+
+// BrowserFetcher renders pages in headless Chrome or Chromium, driven by
+// chromedp, before extracting their text, for JavaScript-heavy
+// single-page apps and for pages a third-party reader such as Jina cannot
+// see, like those behind a login. Each fetch runs in a fresh browser
+// context, so pages share nothing but the cookies in Jar.
+type BrowserFetcher struct {
+	// Endpoint is the DevTools WebSocket URL of a running browser, e.g.
+	// "ws://chrome:9222/devtools/browser/<id>". When empty a local browser
+	// is started for each fetch.
+	Endpoint string
+	Path     string // local browser binary; found on PATH when empty
+
+	// WaitSelector is a CSS selector that must match before the page is
+	// read, for apps that render their content after the load event.
+	// FetchOptions.WaitSelector overrides it per fetch.
+	WaitSelector string
+	WaitTimeout  time.Duration // how long to wait for the selector; defaults to 10s
+
+	ViewportWidth  int // defaults to 1280
+	ViewportHeight int // defaults to 800
+
+	// Jar supplies the cookies sent with the page and receives the ones it
+	// sets, e.g. the PersistentJar holding a logged-in session. Nil sends none.
+	Jar http.CookieJar
+}
+
+// Fetch loads rawURL in the browser, waits for the load event and the wait
+// selector, and extracts the rendered DOM. The load event is waited for at
+// most opts.Timeout; a page still loading after that is read as it is.
+func (f *BrowserFetcher) Fetch(ctx context.Context, rawURL string, opts *FetchOptions) (*FetchResult, error) {
+	if opts == nil {
+		opts = DefaultFetchOptions()
+	}
+	start := time.Now()
+	waitTimeout := f.WaitTimeout
+	if waitTimeout <= 0 {
+		waitTimeout = browserWaitTimeout
+	}
+	maxBody := opts.MaxBodySize
+	if maxBody <= 0 {
+		maxBody = DefaultMaxBodySize
+	}
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout+waitTimeout+browserStartTimeout)
+	defer cancel()
+
+	tab, closeTab, err := f.newTab(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer closeTab()
+
+	wait := opts.WaitSelector
+	if wait == "" {
+		wait = f.WaitSelector
+	}
+	html, status, err := render(tab, rawURL, opts, f.viewport(), wait, waitTimeout, f.Jar)
+	if err != nil {
+		return nil, fmt.Errorf("browser %s: %w", rawURL, err)
+	}
+
+	result := &FetchResult{
+		URL:          rawURL,
+		StatusCode:   status,
+		FetchedAt:    time.Now(),
+		DocumentType: DocumentHTML,
+	}
+	if opts.Screenshot {
+		if result.Screenshot, err = screenshot(tab, f.viewport()[0]); err != nil {
+			return nil, fmt.Errorf("browser %s: screenshot: %w", rawURL, err)
+		}
+	}
+	if int64(len(html)) > maxBody {
+		html = html[:maxBody]
+		result.Truncated = true
+	}
+	result.RawHTML = html
+	result.Title = extractTitle(html)
+	result.CleanText = ExtractTextMode(html, opts.ExtractMode)
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+func (f *BrowserFetcher) viewport() [2]int {
+	w, h := f.ViewportWidth, f.ViewportHeight
+	if w <= 0 {
+		w = browserViewportWidth
+	}
+	if h <= 0 {
+		h = browserViewportHeight
+	}
+	return [2]int{w, h}
+}
+
+// newTab opens a page for one fetch and returns its chromedp context and
+// the function that closes it. A local browser is started for the page
+// alone and removed with it; in a remote browser the page gets a browser
+// context of its own, keeping cookies and storage from leaking between
+// fetches.
+func (f *BrowserFetcher) newTab(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	// chromedp reports protocol events it cannot decode, common with
+	// browsers newer than it, as errors; they do not affect a fetch
+	quiet := chromedp.WithErrorf(func(format string, args ...any) {
+		slog.Debug("browser: " + fmt.Sprintf(format, args...))
+	})
+
+	if f.Endpoint != "" {
+		alloc, cancelAlloc := chromedp.NewRemoteAllocator(ctx, f.Endpoint)
+		browser, cancelBrowser := chromedp.NewContext(alloc, quiet)
+		if err := chromedp.Run(browser); err != nil {
+			cancelBrowser()
+			cancelAlloc()
+			return nil, nil, fmt.Errorf("browser: connect: %w", err)
+		}
+		tab, cancelTab := chromedp.NewContext(browser, chromedp.WithNewBrowserContext())
+		return tab, func() {
+			cancelTab()
+			cancelBrowser()
+			cancelAlloc()
+		}, nil
+	}
+
+	bin, err := (&BrowserReader{Path: f.Path}).binary()
+	if err != nil {
+		return nil, nil, err
+	}
+	vp := f.viewport()
+	alloc, cancelAlloc := chromedp.NewExecAllocator(ctx, append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.ExecPath(bin),
+		chromedp.NoSandbox,
+		chromedp.DisableGPU,
+		chromedp.WindowSize(vp[0], vp[1]),
+	)...)
+	tab, cancelTab := chromedp.NewContext(alloc, quiet)
+	if err := chromedp.Run(tab); err != nil {
+		cancelTab()
+		cancelAlloc()
+		return nil, nil, fmt.Errorf("headless browser: %w", err)
+	}
+	return tab, func() {
+		cancelTab()
+		cancelAlloc()
+	}, nil
+}
+
+// render navigates the tab to rawURL and returns the page's HTML, once it is
+// ready, and the HTTP status of its document.
+func render(tab context.Context, rawURL string, opts *FetchOptions, viewport [2]int, wait string, waitTimeout time.Duration, jar http.CookieJar) (string, int, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", 0, err
+	}
+	setup := chromedp.Tasks{
+		network.Enable(),
+		chromedp.EmulateViewport(int64(viewport[0]), int64(viewport[1])),
+	}
+	if opts.UserAgent != "" {
+		setup = append(setup, emulation.SetUserAgentOverride(opts.UserAgent))
+	}
+	if len(opts.Headers) > 0 {
+		headers := make(network.Headers, len(opts.Headers))
+		for k, v := range opts.Headers {
+			headers[k] = v
+		}
+		setup = append(setup, network.SetExtraHTTPHeaders(headers))
+	}
+	if jar != nil {
+		if cookies := jar.Cookies(u); len(cookies) > 0 {
+			params := make([]*network.CookieParam, 0, len(cookies))
+			for _, c := range cookies {
+				params = append(params, &network.CookieParam{Name: c.Name, Value: c.Value, URL: rawURL})
+			}
+			setup = append(setup, network.SetCookies(params))
+		}
+	}
+	if err := chromedp.Run(tab, setup); err != nil {
+		return "", 0, err
+	}
+
+	// The main frame shares the target's ID
+	var status atomic.Int64
+	frame := cdp.FrameID(chromedp.FromContext(tab).Target.TargetID)
+	chromedp.ListenTarget(tab, func(ev any) {
+		if ev, ok := ev.(*network.EventResponseReceived); ok && ev.Type == network.ResourceTypeDocument && ev.FrameID == frame {
+			status.Store(ev.Response.Status)
+		}
+	})
+
+	load, cancel := context.WithTimeout(tab, opts.Timeout)
+	err = chromedp.Run(load, chromedp.Navigate(rawURL))
+	cancel()
+	// long-polling and streaming pages may never finish loading
+	if err != nil && (!errors.Is(err, context.DeadlineExceeded) || tab.Err() != nil) {
+		return "", 0, err
+	}
+
+	if wait != "" {
+		ready, cancel := context.WithTimeout(tab, waitTimeout)
+		err := chromedp.Run(ready, chromedp.WaitReady(wait, chromedp.ByQuery))
+		cancel()
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) && tab.Err() == nil {
+				return "", 0, fmt.Errorf("wait for %s: no match after %s", wait, waitTimeout)
+			}
+			return "", 0, fmt.Errorf("wait for %s: %w", wait, err)
+		}
+	}
+
+	var html string
+	if err := chromedp.Run(tab, chromedp.Evaluate("document.documentElement.outerHTML", &html)); err != nil {
+		return "", 0, err
+	}
+	if jar != nil {
+		saveCookies(tab, u, jar)
+	}
+	return html, int(status.Load()), nil
+}
+
+// screenshot captures the rendered page as a PNG, from the top down to
+// browserMaxShotHeight.
+func screenshot(tab context.Context, width int) ([]byte, error) {
+	var shot []byte
+	err := chromedp.Run(tab, chromedp.ActionFunc(func(ctx context.Context) error {
+		_, _, contentSize, _, _, cssContentSize, err := page.GetLayoutMetrics().Do(ctx)
+		if err != nil {
+			return err
+		}
+		var height float64
+		if cssContentSize != nil {
+			height = cssContentSize.Height
+		} else if contentSize != nil { // before Chrome 92
+			height = contentSize.Height
+		}
+		height = min(max(height, 1), browserMaxShotHeight)
+		shot, err = page.CaptureScreenshot().
+			WithFormat(page.CaptureScreenshotFormatPng).
+			WithCaptureBeyondViewport(true).
+			WithClip(&page.Viewport{Width: float64(width), Height: height, Scale: 1}).
+			Do(ctx)
+		return err
+	}))
+	return shot, err
+}
+
+// saveCookies copies the page's cookies back to jar, so a session renewed
+// by the page is kept. Failures only cost the renewal and are ignored.
+func saveCookies(tab context.Context, u *url.URL, jar http.CookieJar) {
+	var cookies []*network.Cookie
+	err := chromedp.Run(tab, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		cookies, err = network.GetCookies().WithURLs([]string{u.String()}).Do(ctx)
+		return err
+	}))
+	if err != nil || len(cookies) == 0 {
+		return
+	}
+	saved := make([]*http.Cookie, 0, len(cookies))
+	for _, c := range cookies {
+		hc := &http.Cookie{Name: c.Name, Value: c.Value, Domain: c.Domain, Path: c.Path, Secure: c.Secure, HttpOnly: c.HTTPOnly}
+		if c.Expires > 0 { // -1 for session cookies
+			hc.Expires = time.Unix(int64(c.Expires), 0)
+		}
+		saved = append(saved, hc)
+	}
+	jar.SetCookies(u, saved)
+}
@@ -0,0 +1,192 @@
+package scraper
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CachingFetcher wraps a Fetcher with a disk cache. Within the TTL, results are
+// served from disk without any request. After it expires, the cached ETag and
+// Last-Modified validators are sent and a 304 response reuses the cached body.
+//
+// Layout under dir:
+//
+//	index/<sha256(url|mode)>.json — response metadata and validators
+//	objects/<sha256(body)>        — raw HTML, content-addressed and shared
+type CachingFetcher struct {
+	next Fetcher
+	dir  string
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+// cacheEntry is the on-disk index record for one URL.
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	StatusCode   int       `json:"status_code"`
+	Title        string    `json:"title"`
+	CleanText    string    `json:"clean_text"`
+	ContentHash  string    `json:"content_hash"`
+	DocumentType string    `json:"document_type,omitempty"`
+	Truncated    bool      `json:"truncated,omitempty"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	StoredAt     time.Time `json:"stored_at"`
+}
+
+// NewCachingFetcher creates a caching decorator around next. A zero TTL always
+// revalidates with the origin.
+func NewCachingFetcher(next Fetcher, dir string, ttl time.Duration) *CachingFetcher {
+	return &CachingFetcher{next: next, dir: dir, ttl: ttl, now: time.Now}
+}
+
+// Fetch returns a cached result when fresh, otherwise revalidates or refetches.
+// Only 200 responses are cached.
+func (c *CachingFetcher) Fetch(ctx context.Context, url string, opts *FetchOptions) (*FetchResult, error) {
+	if opts == nil {
+		opts = DefaultFetchOptions()
+	}
+	key := cacheKey(url, opts.ExtractMode)
+	entry, body, ok := c.load(key)
+
+	if ok && c.now().Sub(entry.StoredAt) < c.ttl {
+		return entry.result(body), nil
+	}
+
+	reqOpts := opts
+	if ok && (entry.ETag != "" || entry.LastModified != "") {
+		clone := *opts
+		clone.Headers = make(map[string]string, len(opts.Headers)+2)
+		for k, v := range opts.Headers {
+			clone.Headers[k] = v
+		}
+		if entry.ETag != "" {
+			clone.Headers["If-None-Match"] = entry.ETag
+		}
+		if entry.LastModified != "" {
+			clone.Headers["If-Modified-Since"] = entry.LastModified
+		}
+		reqOpts = &clone
+	}
+
+	result, err := c.next.Fetch(ctx, url, reqOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case result.StatusCode == http.StatusNotModified && ok:
+		entry.StoredAt = c.now()
+		if err := c.writeIndex(key, entry); err != nil {
+			return nil, err
+		}
+		return entry.result(body), nil
+	case result.StatusCode == http.StatusOK:
+		if err := c.store(key, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (e *cacheEntry) result(body []byte) *FetchResult {
+	return &FetchResult{
+		URL:          e.URL,
+		StatusCode:   e.StatusCode,
+		RawHTML:      string(body),
+		CleanText:    e.CleanText,
+		Title:        e.Title,
+		FetchedAt:    e.StoredAt,
+		DocumentType: e.DocumentType,
+		Truncated:    e.Truncated,
+		ETag:         e.ETag,
+		LastModified: e.LastModified,
+		FromCache:    true,
+	}
+}
+
+func (c *CachingFetcher) load(key string) (*cacheEntry, []byte, bool) {
+	data, err := os.ReadFile(filepath.Join(c.dir, "index", key+".json"))
+	if err != nil {
+		return nil, nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, nil, false
+	}
+	body, err := os.ReadFile(filepath.Join(c.dir, "objects", entry.ContentHash))
+	if err != nil {
+		return nil, nil, false
+	}
+	return &entry, body, true
+}
+
+func (c *CachingFetcher) store(key string, r *FetchResult) error {
+	sum := sha256.Sum256([]byte(r.RawHTML))
+	hash := hex.EncodeToString(sum[:])
+
+	objPath := filepath.Join(c.dir, "objects", hash)
+	if _, err := os.Stat(objPath); err != nil {
+		if err := writeFileAtomic(objPath, []byte(r.RawHTML)); err != nil {
+			return fmt.Errorf("cache store: %w", err)
+		}
+	}
+
+	return c.writeIndex(key, &cacheEntry{
+		URL:          r.URL,
+		StatusCode:   r.StatusCode,
+		Title:        r.Title,
+		CleanText:    r.CleanText,
+		ContentHash:  hash,
+		DocumentType: r.DocumentType,
+		Truncated:    r.Truncated,
+		ETag:         r.ETag,
+		LastModified: r.LastModified,
+		StoredAt:     c.now(),
+	})
+}
+
+func (c *CachingFetcher) writeIndex(key string, entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("cache index: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(c.dir, "index", key+".json"), data); err != nil {
+		return fmt.Errorf("cache index: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes via a temp file and rename so concurrent readers never
+// see a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func cacheKey(url string, mode ExtractMode) string {
+	sum := sha256.Sum256([]byte(url + "|" + string(mode)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,302 @@
+package scraper
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// CrawlResult is one URL discovered by Crawl.
+type CrawlResult struct {
+	URL   string `json:"url"`
+	Title string `json:"title,omitempty"` // empty for URLs that were not fetched
+	Depth int    `json:"depth"`           // 0 for the root
+}
+
+// Crawler discovers same-domain URLs from a root page and the site's sitemap,
+// honoring robots.txt.
+type Crawler struct {
+	Client    *http.Client
+	UserAgent string
+	MaxPages  int           // pages fetched at most; defaults to 100
+	Delay     time.Duration // pause between requests; raised to robots.txt's Crawl-delay
+}
+
+// Crawl discovers same-domain URLs up to depth links away from root with a
+// default Crawler. filter, if not nil, decides which URLs are kept and followed.
+func Crawl(ctx context.Context, root string, depth int, filter func(*url.URL) bool) ([]CrawlResult, error) {
+	return (&Crawler{}).Crawl(ctx, root, depth, filter)
+}
+
+// Crawl walks links breadth-first from root. URLs listed in the sitemap count
+// as depth 1. Only HTML pages are fetched; everything found within depth is
+// returned in discovery order.
+func (c *Crawler) Crawl(ctx context.Context, root string, depth int, filter func(*url.URL) bool) ([]CrawlResult, error) {
+	rootURL, err := url.Parse(root)
+	if err != nil || rootURL.Host == "" {
+		return nil, fmt.Errorf("crawl: invalid root %q", root)
+	}
+	if c.Client == nil {
+		c.Client = &http.Client{Timeout: 15 * time.Second}
+	}
+	if c.UserAgent == "" {
+		c.UserAgent = DefaultFetchOptions().UserAgent
+	}
+	maxPages := c.MaxPages
+	if maxPages <= 0 {
+		maxPages = 100
+	}
+
+	robots, sitemaps := c.fetchRobots(ctx, rootURL)
+	if len(sitemaps) == 0 {
+		sitemaps = []string{rootURL.Scheme + "://" + rootURL.Host + "/sitemap.xml"}
+	}
+	delay := max(c.Delay, min(robots.delay, maxCrawlDelay))
+
+	var results []CrawlResult
+	index := make(map[string]int) // normalized URL → position in results
+	var queue []int
+
+	add := func(raw string, base *url.URL, d int) {
+		u, err := base.Parse(strings.TrimSpace(raw))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || !strings.EqualFold(u.Hostname(), rootURL.Hostname()) {
+			return
+		}
+		u.Fragment = ""
+		key := u.String()
+		if _, ok := index[key]; ok || !robots.allowed(u.RequestURI()) {
+			return
+		}
+		if d > 0 && filter != nil && !filter(u) {
+			return
+		}
+		index[key] = len(results)
+		results = append(results, CrawlResult{URL: key, Depth: d})
+		if d < depth {
+			queue = append(queue, len(results)-1)
+		}
+	}
+
+	add(rootURL.String(), rootURL, 0)
+	if len(results) == 0 {
+		return nil, fmt.Errorf("crawl: %s is disallowed by robots.txt", root)
+	}
+	if depth > 0 {
+		for _, loc := range c.fetchSitemaps(ctx, sitemaps) {
+			add(loc, rootURL, 1)
+		}
+	}
+
+	for fetched := 0; len(queue) > 0 && fetched < maxPages; fetched++ {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		i := queue[0]
+		queue = queue[1:]
+		if fetched > 0 && delay > 0 {
+			time.Sleep(delay)
+		}
+
+		page, _ := url.Parse(results[i].URL)
+		doc, err := c.fetchHTML(ctx, page.String())
+		if err != nil {
+			continue
+		}
+		results[i].Title = findTitle(doc)
+		forEachElement(doc, func(n *html.Node) {
+			if n.Data == "a" && !strings.Contains(attr(n, "rel"), "nofollow") {
+				if href := attr(n, "href"); href != "" {
+					add(href, page, results[i].Depth+1)
+				}
+			}
+		})
+	}
+	return results, nil
+}
+
+func (c *Crawler) get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.UserAgent)
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s returned %d", rawURL, resp.StatusCode)
+	}
+	return resp, nil
+}
+
+func (c *Crawler) fetchHTML(ctx context.Context, rawURL string) (*html.Node, error) {
+	resp, err := c.get(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "html") {
+		return nil, fmt.Errorf("%s is not HTML (%s)", rawURL, ct)
+	}
+	return html.Parse(io.LimitReader(resp.Body, 5<<20))
+}
+
+// robotsRules holds the Allow/Disallow rules and Crawl-delay that apply to
+// the crawler.
+type robotsRules struct {
+	allow    []string
+	disallow []string
+	delay    time.Duration
+}
+
+// allowed applies the longest matching rule; Allow wins ties. A trailing "$"
+// anchors a rule and "*" matches any sequence.
+func (r robotsRules) allowed(path string) bool {
+	if path == "" {
+		path = "/"
+	}
+	best, allow := -1, true
+	for _, p := range r.disallow {
+		if len(p) > best && robotsMatch(p, path) {
+			best, allow = len(p), false
+		}
+	}
+	for _, p := range r.allow {
+		if len(p) >= best && robotsMatch(p, path) {
+			best, allow = len(p), true
+		}
+	}
+	return allow
+}
+
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(strings.TrimSuffix(pattern, "$")), `\*`, ".*")
+	if anchored {
+		expr += "$"
+	}
+	re, err := regexp.Compile(expr)
+	return err == nil && re.MatchString(path)
+}
+
+// fetchRobots reads robots.txt, returning the rules for our user agent (or
+// "*") and any Sitemap entries. A missing robots.txt allows everything.
+func (c *Crawler) fetchRobots(ctx context.Context, root *url.URL) (robotsRules, []string) {
+	resp, err := c.get(ctx, root.Scheme+"://"+root.Host+"/robots.txt")
+	if err != nil {
+		return robotsRules{}, nil
+	}
+	defer resp.Body.Close()
+	return parseRobots(resp.Body, c.UserAgent)
+}
+
+func parseRobots(r io.Reader, userAgent string) (robotsRules, []string) {
+	ua := strings.ToLower(userAgent)
+	var specific, wildcard robotsRules
+	var sitemaps []string
+	var groupAgents []string
+	inRules := false
+
+	sc := bufio.NewScanner(io.LimitReader(r, 512<<10))
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "sitemap":
+			sitemaps = append(sitemaps, value)
+		case "user-agent":
+			if inRules {
+				groupAgents = nil
+				inRules = false
+			}
+			groupAgents = append(groupAgents, strings.ToLower(value))
+		case "crawl-delay":
+			inRules = true
+			secs, err := strconv.ParseFloat(value, 64)
+			if err != nil || secs <= 0 {
+				continue
+			}
+			for _, agent := range groupAgents {
+				if agent == "*" {
+					wildcard.delay = time.Duration(secs * float64(time.Second))
+				} else if strings.Contains(ua, agent) {
+					specific.delay = time.Duration(secs * float64(time.Second))
+				}
+			}
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				continue
+			}
+			for _, agent := range groupAgents {
+				target := &wildcard
+				if agent != "*" {
+					if !strings.Contains(ua, agent) {
+						continue
+					}
+					target = &specific
+				}
+				if key == "allow" {
+					target.allow = append(target.allow, value)
+				} else {
+					target.disallow = append(target.disallow, value)
+				}
+			}
+		}
+	}
+	if len(specific.allow)+len(specific.disallow) > 0 || specific.delay > 0 {
+		return specific, sitemaps
+	}
+	return wildcard, sitemaps
+}
+
+// fetchSitemaps returns the page URLs listed in the sitemaps, following one
+// level of sitemap indexes.
+func (c *Crawler) fetchSitemaps(ctx context.Context, sitemaps []string) []string {
+	var locs []string
+	for _, sm := range sitemaps {
+		pages, children := c.fetchSitemap(ctx, sm)
+		locs = append(locs, pages...)
+		for _, child := range children {
+			pages, _ := c.fetchSitemap(ctx, child)
+			locs = append(locs, pages...)
+		}
+	}
+	return locs
+}
+
+func (c *Crawler) fetchSitemap(ctx context.Context, sitemapURL string) (pages, children []string) {
+	resp, err := c.get(ctx, sitemapURL)
+	if err != nil {
+		return nil, nil
+	}
+	defer resp.Body.Close()
+
+	var doc struct {
+		XMLName  xml.Name
+		URLs     []string `xml:"url>loc"`
+		Sitemaps []string `xml:"sitemap>loc"`
+	}
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, 10<<20)).Decode(&doc); err != nil {
+		return nil, nil
+	}
+	return doc.URLs, doc.Sitemaps
+}
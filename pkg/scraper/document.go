@@ -0,0 +1,202 @@
+package scraper
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// Document types detected by DetectDocumentType.
+const (
+	DocumentHTML = "html"
+	DocumentPDF  = "pdf"
+	DocumentDOCX = "docx"
+	DocumentJSON = "json"
+)
+
+const docxMIME = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+// DetectDocumentType classifies a response body from its Content-Type header,
+// falling back to sniffing the body for servers that send PDFs and DOCX files
+// as application/octet-stream. JSON is application/json or a +json type.
+func DetectDocumentType(body []byte, contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/pdf":
+		return DocumentPDF
+	case docxMIME:
+		return DocumentDOCX
+	case "application/json":
+		return DocumentJSON
+	}
+	if strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json") {
+		return DocumentJSON
+	}
+	if bytes.HasPrefix(body, []byte("%PDF-")) {
+		return DocumentPDF
+	}
+	if bytes.HasPrefix(body, []byte("PK\x03\x04")) && bytes.Contains(body, []byte("word/document.xml")) {
+		return DocumentDOCX
+	}
+	return DocumentHTML
+}
+
+// ExtractDocument extracts the title and text of a PDF or DOCX body, or
+// formats a JSON body with FormatJSON.
+func ExtractDocument(body []byte, docType string) (title, text string, err error) {
+	switch docType {
+	case DocumentPDF:
+		return ExtractPDFText(body)
+	case DocumentDOCX:
+		return ExtractDOCXText(body)
+	case DocumentJSON:
+		text, err = FormatJSON(body)
+		return "", text, err
+	default:
+		return "", "", fmt.Errorf("unsupported document type %q", docType)
+	}
+}
+
+// FormatJSON re-encodes a JSON body indented, with object keys sorted and
+// numbers kept as written, so responses that differ only in key order or
+// whitespace give the same text.
+func FormatJSON(body []byte) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return "", fmt.Errorf("parse json: %w", err)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return "", fmt.Errorf("format json: %w", err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// ExtractPDFText returns the document title (from the PDF Info dictionary) and
+// the plain text of every page.
+func ExtractPDFText(data []byte) (title, text string, err error) {
+	// The PDF parser panics on some malformed files
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("parse pdf: %v", r)
+		}
+	}()
+
+	r, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", "", fmt.Errorf("parse pdf: %w", err)
+	}
+	plain, err := r.GetPlainText()
+	if err != nil {
+		return "", "", fmt.Errorf("pdf text: %w", err)
+	}
+	body, err := io.ReadAll(plain)
+	if err != nil {
+		return "", "", fmt.Errorf("pdf text: %w", err)
+	}
+	title = strings.TrimSpace(r.Trailer().Key("Info").Key("Title").Text())
+	return title, strings.TrimSpace(string(body)), nil
+}
+
+// ExtractDOCXText returns the document title (from docProps/core.xml) and the
+// text of word/document.xml, one line per paragraph.
+func ExtractDOCXText(data []byte) (title, text string, err error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", "", fmt.Errorf("parse docx: %w", err)
+	}
+
+	var doc, core *zip.File
+	for _, f := range zr.File {
+		switch f.Name {
+		case "word/document.xml":
+			doc = f
+		case "docProps/core.xml":
+			core = f
+		}
+	}
+	if doc == nil {
+		return "", "", fmt.Errorf("parse docx: missing word/document.xml")
+	}
+
+	text, err = docxBodyText(doc)
+	if err != nil {
+		return "", "", err
+	}
+	if core != nil {
+		title, _ = docxTitle(core)
+	}
+	return title, text, nil
+}
+
+func docxBodyText(f *zip.File) (string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", fmt.Errorf("parse docx: %w", err)
+	}
+	defer rc.Close()
+
+	var sb strings.Builder
+	inText := false
+	dec := xml.NewDecoder(rc)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("parse docx: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				sb.WriteString("\t")
+			case "br", "cr":
+				sb.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				sb.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				sb.Write(t)
+			}
+		}
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+func docxTitle(f *zip.File) (string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	var props struct {
+		Title string `xml:"title"`
+	}
+	if err := xml.NewDecoder(rc).Decode(&props); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(props.Title), nil
+}
@@ -0,0 +1,58 @@
+package scraper
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"golang.org/x/net/html/charset"
+)
+
+// acceptEncoding is sent on every request. Setting it explicitly turns off
+// net/http's transparent gzip handling, so decompressBody handles all of them.
+const acceptEncoding = "gzip, deflate, br"
+
+// decompressBody wraps body in a reader for the response's Content-Encoding.
+func decompressBody(body io.Reader, contentEncoding string) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(contentEncoding)) {
+	case "", "identity":
+		return body, nil
+	case "gzip", "x-gzip":
+		return gzip.NewReader(body)
+	case "br":
+		return brotli.NewReader(body), nil
+	case "deflate":
+		// "deflate" should be zlib-wrapped, but some servers send raw DEFLATE
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		if zr, err := zlib.NewReader(bytes.NewReader(data)); err == nil {
+			return zr, nil
+		}
+		return flate.NewReader(bytes.NewReader(data)), nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", contentEncoding)
+	}
+}
+
+// toUTF8 converts a response body to UTF-8. The charset is taken from a BOM,
+// the Content-Type header or a <meta> declaration, in that order, so GBK,
+// Shift-JIS and other legacy pages are not mangled before diffing. It returns
+// the converted body and the detected charset name.
+func toUTF8(body []byte, contentType string) ([]byte, string) {
+	enc, name, _ := charset.DetermineEncoding(body, contentType)
+	if name == "utf-8" {
+		return body, name
+	}
+	out, err := enc.NewDecoder().Bytes(body)
+	if err != nil {
+		return body, name
+	}
+	return out, name
+}
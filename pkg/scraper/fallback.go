@@ -0,0 +1,214 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Reader renders a page through another route when a direct fetch returns
+// too little text, typically because the page is rendered by JavaScript.
+type Reader interface {
+	Name() string
+	Read(ctx context.Context, url string, opts *FetchOptions) (string, error)
+}
+
+// readerTimeout returns the reader's own timeout, or the fetch timeout plus
+// headroom for rendering.
+func readerTimeout(timeout time.Duration, opts *FetchOptions) time.Duration {
+	if timeout > 0 {
+		return timeout
+	}
+	return opts.Timeout + 15*time.Second
+}
+
+// JinaReader uses the Jina Reader API to render JS pages and extract content.
+// It works without a key at a lower rate limit. See: https://r.jina.ai
+type JinaReader struct {
+	APIKey  string
+	Timeout time.Duration
+}
+
+// Name returns "jina".
+func (r *JinaReader) Name() string { return "jina" }
+
+// Read returns the page as markdown.
+func (r *JinaReader) Read(ctx context.Context, targetURL string, opts *FetchOptions) (string, error) {
+	client := &http.Client{Timeout: readerTimeout(r.Timeout, opts)}
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://r.jina.ai/"+targetURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Return-Format", "markdown")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; WatchBot/2.0)")
+	if r.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+r.APIKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("jina fetch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("jina returned %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// BrowserReader renders pages with a local headless Chrome or Chromium.
+type BrowserReader struct {
+	Path    string // browser binary; found on PATH when empty
+	Timeout time.Duration
+}
+
+// browserBinaries are tried in order when BrowserReader.Path is empty.
+var browserBinaries = []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "chrome"}
+
+// Name returns "browser".
+func (r *BrowserReader) Name() string { return "browser" }
+
+// Read dumps the rendered DOM and extracts its text.
+func (r *BrowserReader) Read(ctx context.Context, targetURL string, opts *FetchOptions) (string, error) {
+	bin, err := r.binary()
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, readerTimeout(r.Timeout, opts))
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, bin,
+		"--headless", "--disable-gpu", "--no-sandbox",
+		"--user-agent="+opts.UserAgent,
+		"--dump-dom", targetURL,
+	).Output()
+	if err != nil {
+		return "", fmt.Errorf("headless browser: %w", err)
+	}
+	return ExtractTextMode(string(out), opts.ExtractMode), nil
+}
+
+func (r *BrowserReader) binary() (string, error) {
+	if r.Path != "" {
+		return r.Path, nil
+	}
+	for _, name := range browserBinaries {
+		if p, err := exec.LookPath(name); err == nil {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("headless browser: no chrome or chromium binary found")
+}
+
+// ServiceReader renders pages through a ScrapingBee-style scraping API that
+// takes the target URL and an API key as query parameters and returns HTML.
+type ServiceReader struct {
+	ServiceName string
+	Endpoint    string // e.g. "https://app.scrapingbee.com/api/v1/"
+	APIKey      string
+	KeyParam    string            // query parameter for the key; defaults to "api_key"
+	URLParam    string            // query parameter for the target; defaults to "url"
+	Params      map[string]string // extra parameters, e.g. render_js=true
+	Timeout     time.Duration
+}
+
+// Name returns the configured service name.
+func (r *ServiceReader) Name() string { return r.ServiceName }
+
+// Read fetches the rendered HTML from the service and extracts its text.
+func (r *ServiceReader) Read(ctx context.Context, targetURL string, opts *FetchOptions) (string, error) {
+	keyParam, urlParam := r.KeyParam, r.URLParam
+	if keyParam == "" {
+		keyParam = "api_key"
+	}
+	if urlParam == "" {
+		urlParam = "url"
+	}
+	q := url.Values{}
+	q.Set(keyParam, r.APIKey)
+	q.Set(urlParam, targetURL)
+	for k, v := range r.Params {
+		q.Set(k, v)
+	}
+
+	client := &http.Client{Timeout: readerTimeout(r.Timeout, opts)}
+	req, err := http.NewRequestWithContext(ctx, "GET", r.Endpoint+"?"+q.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%s fetch: %w", r.ServiceName, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("%s returned %d", r.ServiceName, resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return ExtractTextMode(string(body), opts.ExtractMode), nil
+}
+
+// FallbackConfig holds credentials for ParseFallbacks.
+type FallbackConfig struct {
+	JinaAPIKey        string
+	ScrapingBeeAPIKey string
+	BrowserPath       string
+}
+
+// ParseFallbacks builds a fallback chain from a comma-separated spec such as
+// "jina:30s,browser,scrapingbee:60s". Each entry may carry its own timeout.
+// "none" or an empty spec disables fallbacks.
+func ParseFallbacks(spec string, cfg FallbackConfig) ([]Reader, error) {
+	var readers []Reader
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" || part == "none" {
+			continue
+		}
+		name, timeoutStr, _ := strings.Cut(part, ":")
+		var timeout time.Duration
+		if timeoutStr != "" {
+			d, err := time.ParseDuration(timeoutStr)
+			if err != nil {
+				return nil, fmt.Errorf("fallback %s: invalid timeout: %w", name, err)
+			}
+			timeout = d
+		}
+
+		switch name {
+		case "jina":
+			readers = append(readers, &JinaReader{APIKey: cfg.JinaAPIKey, Timeout: timeout})
+		case "browser":
+			readers = append(readers, &BrowserReader{Path: cfg.BrowserPath, Timeout: timeout})
+		case "scrapingbee":
+			if cfg.ScrapingBeeAPIKey == "" {
+				return nil, fmt.Errorf("fallback scrapingbee: missing API key")
+			}
+			readers = append(readers, &ServiceReader{
+				ServiceName: "scrapingbee",
+				Endpoint:    "https://app.scrapingbee.com/api/v1/",
+				APIKey:      cfg.ScrapingBeeAPIKey,
+				Params:      map[string]string{"render_js": "true"},
+				Timeout:     timeout,
+			})
+		default:
+			return nil, fmt.Errorf("unknown fallback %q", name)
+		}
+	}
+	return readers, nil
+}
@@ -0,0 +1,203 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when a host's circuit breaker is rejecting requests.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// BreakerState is the state of a host's circuit breaker.
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"    // requests flow normally
+	BreakerOpen     BreakerState = "open"      // requests are rejected until the cooldown ends
+	BreakerHalfOpen BreakerState = "half-open" // one trial request decides whether to close
+)
+
+// HostState is a snapshot of one host's limiter and breaker state.
+type HostState struct {
+	Host       string       `json:"host"`
+	State      BreakerState `json:"state"`
+	Failures   int          `json:"failures"` // consecutive failures
+	LastStatus int          `json:"last_status,omitempty"`
+	Requests   int          `json:"requests"`
+	Rejected   int          `json:"rejected"`
+	OpenUntil  time.Time    `json:"open_until,omitempty"`
+}
+
+// HostLimiter is a Fetcher decorator that spaces out requests to the same host
+// and trips a circuit breaker after repeated 403/429/5xx responses or network
+// errors, so a blocking or failing host is left alone for a cooldown period.
+// It is safe for concurrent use and meant to be shared by all callers.
+type HostLimiter struct {
+	next      Fetcher
+	interval  time.Duration // minimum gap between requests to one host
+	threshold int           // consecutive failures that open the breaker
+	cooldown  time.Duration // how long an open breaker rejects requests
+
+	mu     sync.Mutex
+	hosts  map[string]*hostEntry
+	now    func() time.Time
+	logger *slog.Logger
+}
+
+type hostEntry struct {
+	HostState
+	nextSlot time.Time
+	trial    bool // a half-open trial request is in flight
+}
+
+// NewHostLimiter wraps next with per-host rate limiting and circuit breaking.
+func NewHostLimiter(next Fetcher, interval time.Duration, threshold int, cooldown time.Duration) *HostLimiter {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &HostLimiter{
+		next:      next,
+		interval:  interval,
+		threshold: threshold,
+		cooldown:  cooldown,
+		hosts:     make(map[string]*hostEntry),
+		now:       time.Now,
+		logger:    slog.Default(),
+	}
+}
+
+// Fetch waits for the host's next slot, then delegates to the wrapped fetcher.
+func (l *HostLimiter) Fetch(ctx context.Context, rawURL string, opts *FetchOptions) (*FetchResult, error) {
+	host := hostOf(rawURL)
+	wait, err := l.acquire(host)
+	if err != nil {
+		return nil, err
+	}
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			l.release(host, 0, ctx.Err())
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	result, err := l.next.Fetch(ctx, rawURL, opts)
+	status := 0
+	if result != nil {
+		status = result.StatusCode
+	}
+	l.release(host, status, err)
+	return result, err
+}
+
+// States returns a snapshot of every host seen so far, sorted by host.
+func (l *HostLimiter) States() []HostState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	states := make([]HostState, 0, len(l.hosts))
+	for _, h := range l.hosts {
+		s := h.HostState
+		if s.State == BreakerOpen && !l.now().Before(s.OpenUntil) {
+			s.State = BreakerHalfOpen
+		}
+		states = append(states, s)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Host < states[j].Host })
+	return states
+}
+
+// acquire checks the breaker and reserves the host's next request slot.
+func (l *HostLimiter) acquire(host string) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	h, ok := l.hosts[host]
+	if !ok {
+		h = &hostEntry{HostState: HostState{Host: host, State: BreakerClosed}}
+		l.hosts[host] = h
+	}
+
+	now := l.now()
+	switch h.State {
+	case BreakerOpen:
+		if now.Before(h.OpenUntil) {
+			h.Rejected++
+			return 0, fmt.Errorf("%w for %s until %s", ErrCircuitOpen, host, h.OpenUntil.Format(time.RFC3339))
+		}
+		h.State = BreakerHalfOpen
+		fallthrough
+	case BreakerHalfOpen:
+		if h.trial {
+			h.Rejected++
+			return 0, fmt.Errorf("%w for %s (trial request in flight)", ErrCircuitOpen, host)
+		}
+		h.trial = true
+	}
+
+	slot := h.nextSlot
+	if slot.Before(now) {
+		slot = now
+	}
+	h.nextSlot = slot.Add(l.interval)
+	h.Requests++
+	return slot.Sub(now), nil
+}
+
+// release records the outcome of a request and updates the breaker.
+func (l *HostLimiter) release(host string, status int, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	h := l.hosts[host]
+	h.trial = false
+	if status != 0 {
+		h.LastStatus = status
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return // the caller gave up; says nothing about the host
+	}
+	if errors.Is(err, ErrDisallowed) {
+		return // refused locally; the host was never asked
+	}
+	if err == nil && !isBlockingStatus(status) {
+		if h.State != BreakerClosed {
+			l.logger.Info("circuit closed", "host", host)
+		}
+		h.State = BreakerClosed
+		h.Failures = 0
+		return
+	}
+
+	h.Failures++
+	if h.State == BreakerHalfOpen || h.Failures >= l.threshold {
+		h.State = BreakerOpen
+		h.OpenUntil = l.now().Add(l.cooldown)
+		l.logger.Warn("circuit opened", "host", host, "failures", h.Failures, "status", status, "until", h.OpenUntil)
+	}
+}
+
+// isBlockingStatus reports responses that suggest the host is blocking us or failing.
+func isBlockingStatus(status int) bool {
+	return status == http.StatusForbidden || status == http.StatusTooManyRequests || status >= 500
+}
+
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	return strings.ToLower(u.Hostname())
+}
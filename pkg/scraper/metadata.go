@@ -0,0 +1,212 @@
+package scraper
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// Metadata holds structured data embedded in a page's <head> and JSON-LD blocks.
+type Metadata struct {
+	Title        string            `json:"title,omitempty"`
+	Description  string            `json:"description,omitempty"`
+	CanonicalURL string            `json:"canonical_url,omitempty"`
+	SiteName     string            `json:"site_name,omitempty"`
+	Type         string            `json:"type,omitempty"` // og:type, e.g. "article", "website"
+	Image        string            `json:"image,omitempty"`
+	Author       string            `json:"author,omitempty"`
+	PublishedAt  time.Time         `json:"published_at,omitempty"` // zero if unknown
+	OpenGraph    map[string]string `json:"open_graph,omitempty"`   // og:*, article:* and twitter:* tags
+	JSONLD       []map[string]any  `json:"json_ld,omitempty"`      // entities, with @graph flattened
+}
+
+// SchemaTypes returns the schema.org @type values of all JSON-LD entities,
+// e.g. ["Organization", "SoftwareApplication", "FAQPage"].
+func (m *Metadata) SchemaTypes() []string {
+	var types []string
+	for _, e := range m.JSONLD {
+		switch t := e["@type"].(type) {
+		case string:
+			types = append(types, t)
+		case []any:
+			for _, v := range t {
+				if s, ok := v.(string); ok {
+					types = append(types, s)
+				}
+			}
+		}
+	}
+	return types
+}
+
+// ExtractMetadata parses OpenGraph/Twitter meta tags, JSON-LD, the canonical
+// link and the publish date from HTML. Missing fields are left empty.
+func ExtractMetadata(htmlContent string) *Metadata {
+	md := &Metadata{OpenGraph: make(map[string]string)}
+	doc, err := html.Parse(strings.NewReader(htmlContent))
+	if err != nil {
+		return md
+	}
+
+	meta := make(map[string]string) // name/itemprop → content
+	var timeTag string
+	forEachElement(doc, func(n *html.Node) {
+		switch n.Data {
+		case "meta":
+			content := strings.TrimSpace(attr(n, "content"))
+			if p := attr(n, "property"); p != "" && content != "" {
+				if isOpenGraphKey(p) {
+					md.OpenGraph[p] = content
+				}
+			}
+			for _, k := range []string{"name", "itemprop"} {
+				if v := strings.ToLower(attr(n, k)); v != "" && content != "" {
+					if isOpenGraphKey(v) {
+						md.OpenGraph[v] = content
+					} else if _, ok := meta[v]; !ok {
+						meta[v] = content
+					}
+				}
+			}
+		case "link":
+			if strings.EqualFold(attr(n, "rel"), "canonical") && md.CanonicalURL == "" {
+				md.CanonicalURL = strings.TrimSpace(attr(n, "href"))
+			}
+		case "script":
+			if strings.EqualFold(strings.TrimSpace(attr(n, "type")), "application/ld+json") && n.FirstChild != nil {
+				md.JSONLD = append(md.JSONLD, parseJSONLD(n.FirstChild.Data)...)
+			}
+		case "time":
+			if timeTag == "" {
+				timeTag = attr(n, "datetime")
+			}
+		}
+	})
+
+	entity := primaryEntity(md.JSONLD)
+	md.Title = firstNonEmpty(md.OpenGraph["og:title"], md.OpenGraph["twitter:title"], ldString(entity, "headline"), ldString(entity, "name"), findTitle(doc))
+	md.Description = firstNonEmpty(md.OpenGraph["og:description"], md.OpenGraph["twitter:description"], meta["description"], ldString(entity, "description"))
+	md.CanonicalURL = firstNonEmpty(md.CanonicalURL, md.OpenGraph["og:url"], ldString(entity, "url"))
+	md.SiteName = firstNonEmpty(md.OpenGraph["og:site_name"], meta["application-name"])
+	md.Type = md.OpenGraph["og:type"]
+	md.Image = firstNonEmpty(md.OpenGraph["og:image"], md.OpenGraph["twitter:image"])
+	md.Author = firstNonEmpty(md.OpenGraph["article:author"], meta["author"], ldName(entity, "author"))
+
+	for _, s := range []string{
+		md.OpenGraph["article:published_time"],
+		ldString(entity, "datePublished"),
+		meta["datepublished"], meta["date"], meta["pubdate"], meta["publish_date"], meta["dc.date.issued"],
+		timeTag,
+	} {
+		if t, ok := parseDate(s); ok {
+			md.PublishedAt = t
+			break
+		}
+	}
+	return md
+}
+
+func isOpenGraphKey(k string) bool {
+	return strings.HasPrefix(k, "og:") || strings.HasPrefix(k, "article:") || strings.HasPrefix(k, "twitter:")
+}
+
+// parseJSONLD decodes a JSON-LD block, which may hold one entity, an array,
+// or an @graph of entities.
+func parseJSONLD(raw string) []map[string]any {
+	var v any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &v); err != nil {
+		return nil
+	}
+	var out []map[string]any
+	var collect func(any)
+	collect = func(v any) {
+		switch t := v.(type) {
+		case []any:
+			for _, e := range t {
+				collect(e)
+			}
+		case map[string]any:
+			if graph, ok := t["@graph"]; ok {
+				collect(graph)
+				return
+			}
+			out = append(out, t)
+		}
+	}
+	collect(v)
+	return out
+}
+
+// primaryEntity picks the JSON-LD entity describing the page content,
+// preferring articles and postings over site-wide entities.
+func primaryEntity(entities []map[string]any) map[string]any {
+	for _, e := range entities {
+		if t, _ := e["@type"].(string); strings.HasSuffix(t, "Article") || t == "BlogPosting" || t == "Report" {
+			return e
+		}
+	}
+	for _, e := range entities {
+		if _, ok := e["datePublished"]; ok {
+			return e
+		}
+	}
+	if len(entities) > 0 {
+		return entities[0]
+	}
+	return nil
+}
+
+func ldString(e map[string]any, key string) string {
+	s, _ := e[key].(string)
+	return strings.TrimSpace(s)
+}
+
+// ldName reads a field that may be a string, an object with "name", or a list of either.
+func ldName(e map[string]any, key string) string {
+	switch v := e[key].(type) {
+	case string:
+		return v
+	case map[string]any:
+		return ldString(v, "name")
+	case []any:
+		if len(v) > 0 {
+			return ldName(map[string]any{key: v[0]}, key)
+		}
+	}
+	return ""
+}
+
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+func parseDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
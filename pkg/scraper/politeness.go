@@ -0,0 +1,167 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrDisallowed is returned for URLs the site's robots.txt disallows.
+var ErrDisallowed = errors.New("disallowed by robots.txt")
+
+// DefaultRobotsTTL is how long robots.txt is cached when Politeness.RobotsTTL
+// is zero.
+const DefaultRobotsTTL = 24 * time.Hour
+
+// maxCrawlDelay caps Crawl-delay, so a site asking for an hour between
+// requests cannot stall every check of its pages.
+const maxCrawlDelay = time.Minute
+
+// robotsRetry is how long an unreachable robots.txt counts as allowing
+// everything before it is fetched again.
+const robotsRetry = 10 * time.Minute
+
+// Politeness configures how HTTPFetcher treats the sites it fetches. See
+// HTTPFetcher.SetPoliteness.
+type Politeness struct {
+	// IgnoreRobots fetches every URL without reading robots.txt, so neither
+	// Disallow rules nor Crawl-delay apply. Meant for sites the operator owns
+	// or has permission to monitor.
+	IgnoreRobots bool
+	// MaxPerHost caps concurrent requests to one host; zero means no cap.
+	MaxPerHost int
+	// RobotsTTL is how long a host's robots.txt is cached; zero uses
+	// DefaultRobotsTTL.
+	RobotsTTL time.Duration
+}
+
+// polite enforces Politeness for one HTTPFetcher. It is safe for concurrent use.
+type polite struct {
+	Politeness
+
+	mu    sync.Mutex
+	hosts map[string]*politeHost // keyed by scheme://host
+	now   func() time.Time
+}
+
+type politeHost struct {
+	slots    chan struct{} // concurrency semaphore; nil without MaxPerHost
+	nextSlot time.Time     // earliest start of the next request under Crawl-delay
+
+	robotsMu sync.Mutex // held while robots.txt is fetched, so it is fetched once
+	rules    robotsRules
+	expires  time.Time
+}
+
+func newPolite(p Politeness) *polite {
+	if p.RobotsTTL <= 0 {
+		p.RobotsTTL = DefaultRobotsTTL
+	}
+	return &polite{Politeness: p, hosts: make(map[string]*politeHost), now: time.Now}
+}
+
+// acquire checks rawURL against its host's robots.txt, then waits for a
+// concurrency slot and for the host's Crawl-delay to pass. The returned func
+// frees the slot once the request is done.
+func (p *polite) acquire(ctx context.Context, client *http.Client, rawURL, userAgent string) (func(), error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return func() {}, nil // fetchDirect reports the bad URL
+	}
+	origin := strings.ToLower(u.Scheme + "://" + u.Host)
+
+	p.mu.Lock()
+	h, ok := p.hosts[origin]
+	if !ok {
+		h = &politeHost{}
+		if p.MaxPerHost > 0 {
+			h.slots = make(chan struct{}, p.MaxPerHost)
+		}
+		p.hosts[origin] = h
+	}
+	p.mu.Unlock()
+
+	var delay time.Duration
+	if !p.IgnoreRobots {
+		rules := p.robots(ctx, client, h, origin, userAgent)
+		if !rules.allowed(u.RequestURI()) {
+			return nil, fmt.Errorf("fetch %s: %w", rawURL, ErrDisallowed)
+		}
+		delay = min(rules.delay, maxCrawlDelay)
+	}
+
+	release := func() {}
+	if h.slots != nil {
+		select {
+		case h.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		release = func() { <-h.slots }
+	}
+	if delay <= 0 {
+		return release, nil
+	}
+
+	p.mu.Lock()
+	now := p.now()
+	slot := h.nextSlot
+	if slot.Before(now) {
+		slot = now
+	}
+	h.nextSlot = slot.Add(delay)
+	p.mu.Unlock()
+
+	if wait := slot.Sub(now); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			release()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return release, nil
+}
+
+// robots returns the host's cached rules, fetching robots.txt when they have
+// expired. A robots.txt answering 4xx allows everything. One that is
+// unreachable or failing keeps the last rules, or allows everything if there
+// are none, and is retried after robotsRetry.
+func (p *polite) robots(ctx context.Context, client *http.Client, h *politeHost, origin, userAgent string) robotsRules {
+	h.robotsMu.Lock()
+	defer h.robotsMu.Unlock()
+	if p.now().Before(h.expires) {
+		return h.rules
+	}
+
+	h.expires = p.now().Add(robotsRetry)
+	req, err := http.NewRequestWithContext(ctx, "GET", origin+"/robots.txt", nil)
+	if err != nil {
+		return h.rules
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			h.expires = time.Time{} // the caller gave up; says nothing about the host
+		}
+		return h.rules
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		h.rules, _ = parseRobots(resp.Body, userAgent)
+		h.expires = p.now().Add(p.RobotsTTL)
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		h.rules, h.expires = robotsRules{}, p.now().Add(p.RobotsTTL)
+	}
+	return h.rules
+}
@@ -0,0 +1,258 @@
+package scraper
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ExtractMode selects how CleanText is extracted from HTML.
+type ExtractMode string
+
+const (
+	// ExtractModeTags keeps all text except scripts, navigation, header and footer.
+	ExtractModeTags ExtractMode = ""
+	// ExtractModeReadability keeps only the main content block, scored like
+	// Mozilla Readability, dropping cookie banners, sidebars and related links.
+	ExtractModeReadability ExtractMode = "readability"
+)
+
+// ExtractTextMode converts HTML to clean text using the given extraction mode.
+func ExtractTextMode(htmlContent string, mode ExtractMode) string {
+	if mode == ExtractModeReadability {
+		return ExtractReadable(htmlContent)
+	}
+	return ExtractText(htmlContent)
+}
+
+var (
+	// Boilerplate containers identified by class or id; always removed
+	boilerplate = regexp.MustCompile(`(?i)cookie|consent|gdpr|related|newsletter|share|social|popup|modal`)
+	// Likely boilerplate, unless the name also suggests content
+	unlikelyCandidates = regexp.MustCompile(`(?i)banner|breadcrumb|combx|comment|community|disqus|extra|menu|pager|pagination|promo|remark|rss|shoutbox|sidebar|skyscraper|sponsor|subscribe|tags|toolbar|widget|\bads?\b|advert`)
+	maybeCandidate     = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positiveWeight     = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story|changelog|pricing|release`)
+	negativeWeight     = regexp.MustCompile(`(?i)hidden|banner|combx|comment|com-|contact|cookie|consent|foot|footer|footnote|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|social|tags|tool|widget`)
+)
+
+// readabilitySkipTags are never part of the main content.
+var readabilitySkipTags = map[string]bool{
+	"script": true, "style": true, "nav": true, "footer": true, "header": true,
+	"noscript": true, "svg": true, "iframe": true, "form": true, "aside": true,
+	"button": true, "select": true, "input": true, "textarea": true,
+}
+
+// ExtractReadable extracts the main content of a page using a readability-style
+// algorithm: boilerplate containers are removed, paragraphs are scored by text
+// length and comma count, scores propagate to ancestors, and the best-scoring
+// block (plus related siblings) is rendered. Falls back to ExtractText when no
+// content block stands out.
+func ExtractReadable(htmlContent string) string {
+	doc, err := html.Parse(strings.NewReader(htmlContent))
+	if err != nil {
+		return htmlContent
+	}
+
+	body := findElement(doc, "body")
+	if body == nil {
+		body = doc
+	}
+	removeUnlikely(body)
+
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node
+	addScore := func(n *html.Node, s float64) {
+		if n == nil || n.Type != html.ElementNode {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			scores[n] = initialScore(n)
+			candidates = append(candidates, n)
+		}
+		scores[n] += s
+	}
+
+	forEachElement(body, func(n *html.Node) {
+		switch n.Data {
+		case "p", "pre", "td", "li", "blockquote":
+		default:
+			return
+		}
+		text := strings.TrimSpace(nodeText(n))
+		if len([]rune(text)) < 25 {
+			return
+		}
+		score := 1 + float64(strings.Count(text, ",")+strings.Count(text, "，"))
+		score += math.Min(float64(len([]rune(text)))/100, 3)
+
+		addScore(n.Parent, score)
+		if n.Parent != nil {
+			addScore(n.Parent.Parent, score/2)
+		}
+	})
+
+	var top *html.Node
+	for _, c := range candidates {
+		scores[c] *= 1 - linkDensity(c)
+		if top == nil || scores[c] > scores[top] {
+			top = c
+		}
+	}
+	if top == nil {
+		return ExtractText(htmlContent)
+	}
+
+	// Siblings that score close to the top candidate usually belong to the
+	// same article (e.g. content split across several <div>s).
+	threshold := math.Max(10, scores[top]*0.2)
+	var sb strings.Builder
+	parent := top.Parent
+	if parent == nil {
+		extractTextFromNode(top, &sb, readabilitySkipTags)
+	} else {
+		for s := parent.FirstChild; s != nil; s = s.NextSibling {
+			if s == top || includeSibling(s, scores, threshold) {
+				extractTextFromNode(s, &sb, readabilitySkipTags)
+			}
+		}
+	}
+
+	text := strings.TrimSpace(sb.String())
+	if text == "" {
+		return ExtractText(htmlContent)
+	}
+	return text
+}
+
+func includeSibling(n *html.Node, scores map[*html.Node]float64, threshold float64) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	if s, ok := scores[n]; ok && s >= threshold {
+		return true
+	}
+	if n.Data == "p" {
+		text := nodeText(n)
+		density := linkDensity(n)
+		l := len([]rune(strings.TrimSpace(text)))
+		return (l > 80 && density < 0.25) || (l > 0 && density == 0 && strings.ContainsAny(text, ".。"))
+	}
+	return false
+}
+
+// removeUnlikely detaches skipped tags and boilerplate containers in place.
+func removeUnlikely(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.ElementNode {
+			if readabilitySkipTags[c.Data] || isUnlikely(c) {
+				n.RemoveChild(c)
+				c = next
+				continue
+			}
+			removeUnlikely(c)
+		}
+		c = next
+	}
+}
+
+func isUnlikely(n *html.Node) bool {
+	if n.Data == "body" || n.Data == "article" || n.Data == "main" {
+		return false
+	}
+	if attr(n, "aria-hidden") == "true" || attr(n, "role") == "dialog" || attr(n, "role") == "complementary" {
+		return true
+	}
+	match := attr(n, "class") + " " + attr(n, "id")
+	if boilerplate.MatchString(match) {
+		return true
+	}
+	return unlikelyCandidates.MatchString(match) && !maybeCandidate.MatchString(match)
+}
+
+func initialScore(n *html.Node) float64 {
+	var score float64
+	switch n.Data {
+	case "div", "article", "main", "section":
+		score = 5
+	case "pre", "td", "blockquote":
+		score = 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li":
+		score = -3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score = -5
+	}
+	if n.Data == "article" || n.Data == "main" {
+		score += 10
+	}
+	match := attr(n, "class") + " " + attr(n, "id")
+	if negativeWeight.MatchString(match) {
+		score -= 25
+	}
+	if positiveWeight.MatchString(match) {
+		score += 25
+	}
+	return score
+}
+
+// linkDensity is the fraction of a node's text that sits inside links.
+func linkDensity(n *html.Node) float64 {
+	total := len([]rune(nodeText(n)))
+	if total == 0 {
+		return 0
+	}
+	linked := 0
+	forEachElement(n, func(a *html.Node) {
+		if a.Data == "a" {
+			linked += len([]rune(nodeText(a)))
+		}
+	})
+	return float64(linked) / float64(total)
+}
+
+func nodeText(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return sb.String()
+}
+
+func forEachElement(n *html.Node, fn func(*html.Node)) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode {
+			fn(c)
+		}
+		forEachElement(c, fn)
+	}
+}
+
+func findElement(n *html.Node, tag string) *html.Node {
+	if n.Type == html.ElementNode && n.Data == tag {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/cookiejar"
 	"strings"
 	"time"
 
@@ -18,8 +19,22 @@ type FetchOptions struct {
 	Timeout    time.Duration     `yaml:"timeout"`
 	RetryCount int               `yaml:"retry_count"`
 	Headers    map[string]string `yaml:"headers"`
+	// ExtractMode selects the CleanText extraction; empty keeps the tag-based default.
+	ExtractMode ExtractMode `yaml:"extract_mode"`
+	// MaxBodySize caps the decompressed body kept in memory; zero uses
+	// DefaultMaxBodySize. Larger bodies are cut off and marked Truncated.
+	MaxBodySize int64 `yaml:"max_body_size"`
+	// WaitSelector is a CSS selector BrowserFetcher waits for before reading
+	// the page. Other fetchers ignore it.
+	WaitSelector string `yaml:"wait_selector"`
+	// Screenshot asks BrowserFetcher for a PNG of the rendered page in
+	// FetchResult.Screenshot. Other fetchers ignore it.
+	Screenshot bool `yaml:"screenshot"`
 }
 
+// DefaultMaxBodySize is the body limit used when FetchOptions.MaxBodySize is zero.
+const DefaultMaxBodySize = 10 << 20
+
 // DefaultFetchOptions returns sensible defaults for fetching.
 func DefaultFetchOptions() *FetchOptions {
 	return &FetchOptions{
@@ -38,6 +53,23 @@ type FetchResult struct {
 	Title      string        `json:"title"`
 	FetchedAt  time.Time     `json:"fetched_at"`
 	Duration   time.Duration `json:"duration"`
+	Charset    string        `json:"charset,omitempty"`   // source charset before conversion to UTF-8
+	Fallback   string        `json:"fallback,omitempty"`  // reader that produced CleanText, if any
+	Truncated  bool          `json:"truncated,omitempty"` // body exceeded MaxBodySize and was cut off
+
+	// DocumentType is "html", "pdf", "docx" or "json". For documents,
+	// CleanText holds the extracted text, or the formatted JSON, and RawHTML
+	// is empty.
+	DocumentType string `json:"document_type,omitempty"`
+
+	// Screenshot is a PNG of the rendered page when FetchOptions.Screenshot
+	// was set and the fetcher supports it.
+	Screenshot []byte `json:"-"`
+
+	// Validators for conditional requests (see CachingFetcher)
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	FromCache    bool   `json:"from_cache,omitempty"`
 }
 
 // Fetcher defines the interface for fetching web content.
@@ -47,7 +79,10 @@ type Fetcher interface {
 
 // HTTPFetcher implements Fetcher using standard HTTP.
 type HTTPFetcher struct {
-	client *http.Client
+	client    *http.Client
+	auth      map[string]Authenticator // hostname → credentials
+	fallbacks []Reader                 // tried in order for JS-rendered pages
+	polite    *polite                  // nil unless SetPoliteness was called
 }
 
 // NewHTTPFetcher creates a new HTTP-based fetcher.
@@ -56,30 +91,79 @@ func NewHTTPFetcher() *HTTPFetcher {
 		client: &http.Client{
 			Timeout: 15 * time.Second,
 		},
+		fallbacks: []Reader{&JinaReader{}},
 	}
 }
 
+// SetCookieJar sets the jar used to store and send cookies.
+func (f *HTTPFetcher) SetCookieJar(jar http.CookieJar) {
+	f.client.Jar = jar
+}
+
+// SetFallbacks replaces the fallback chain used when a page returns too
+// little text. No readers disables fallbacks. The default chain is Jina Reader.
+func (f *HTTPFetcher) SetFallbacks(readers ...Reader) {
+	f.fallbacks = readers
+}
+
+// SetAuth registers credentials for a hostname. Requests to the host are
+// authenticated first and never fall back to Jina Reader, which would expose
+// gated content to a third party. An in-memory cookie jar is created if none
+// is set.
+func (f *HTTPFetcher) SetAuth(host string, a Authenticator) {
+	if f.auth == nil {
+		f.auth = make(map[string]Authenticator)
+	}
+	f.auth[strings.ToLower(host)] = a
+	if f.client.Jar == nil {
+		f.client.Jar, _ = cookiejar.New(nil)
+	}
+}
+
+// SetPoliteness makes the fetcher honor each host's robots.txt, including
+// Crawl-delay, and cap concurrent requests per host. robots.txt is fetched
+// once per host and cached for p.RobotsTTL; disallowed URLs fail with
+// ErrDisallowed and are never passed to a fallback reader.
+func (f *HTTPFetcher) SetPoliteness(p Politeness) {
+	f.polite = newPolite(p)
+}
+
 // Fetch retrieves a URL and extracts clean text from the HTML.
-// If the page is JS-rendered (returns very little content), falls back to Jina Reader.
+// If the page is JS-rendered (returns very little content), tries the fallback chain.
 func (f *HTTPFetcher) Fetch(ctx context.Context, url string, opts *FetchOptions) (*FetchResult, error) {
 	if opts == nil {
 		opts = DefaultFetchOptions()
 	}
-	f.client.Timeout = opts.Timeout
+	// A per-call copy, so concurrent fetches with different timeouts do not race
+	client := *f.client
+	client.Timeout = opts.Timeout
 
 	start := time.Now()
+	if f.polite != nil {
+		release, err := f.polite.acquire(ctx, &client, url, opts.UserAgent)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
 
-	result, err := f.fetchDirect(ctx, url, opts)
+	result, err := f.fetchDirect(ctx, &client, url, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	// If content is too small (likely JS-rendered SPA), try Jina Reader
-	if len(result.CleanText) < 500 {
-		jinaResult, jinaErr := f.fetchViaJina(ctx, url, opts.Timeout)
-		if jinaErr == nil && len(jinaResult) > len(result.CleanText) {
-			result.CleanText = jinaResult
-			result.Duration = time.Since(start)
+	// If content is too small (likely JS-rendered SPA), try the fallback chain
+	// in order until one returns more text. A 304 has no body by design, so
+	// there is nothing to fall back for.
+	if len(result.CleanText) < 500 && result.StatusCode != http.StatusNotModified &&
+		result.DocumentType == DocumentHTML && f.auth[hostOf(url)] == nil {
+		for _, r := range f.fallbacks {
+			text, err := r.Read(ctx, url, opts)
+			if err == nil && len(text) > len(result.CleanText) {
+				result.CleanText = text
+				result.Fallback = r.Name()
+				break
+			}
 		}
 	}
 
@@ -88,7 +172,7 @@ func (f *HTTPFetcher) Fetch(ctx context.Context, url string, opts *FetchOptions)
 }
 
 // fetchDirect performs a standard HTTP fetch.
-func (f *HTTPFetcher) fetchDirect(ctx context.Context, url string, opts *FetchOptions) (*FetchResult, error) {
+func (f *HTTPFetcher) fetchDirect(ctx context.Context, client *http.Client, url string, opts *FetchOptions) (*FetchResult, error) {
 	start := time.Now()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -98,74 +182,107 @@ func (f *HTTPFetcher) fetchDirect(ctx context.Context, url string, opts *FetchOp
 	req.Header.Set("User-Agent", opts.UserAgent)
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.9,zh-CN;q=0.8")
+	req.Header.Set("Accept-Encoding", acceptEncoding)
 	for k, v := range opts.Headers {
 		req.Header.Set(k, v)
 	}
 
-	var resp *http.Response
-	var lastErr error
-	for attempt := 0; attempt <= opts.RetryCount; attempt++ {
-		resp, lastErr = f.client.Do(req)
-		if lastErr == nil {
-			break
-		}
-		if attempt < opts.RetryCount {
-			time.Sleep(time.Duration(attempt+1) * time.Second)
+	auth := f.auth[hostOf(url)]
+	if auth != nil {
+		if err := auth.Authenticate(ctx, client, req); err != nil {
+			return nil, fmt.Errorf("authenticate %s: %w", url, err)
 		}
 	}
-	if lastErr != nil {
-		return nil, fmt.Errorf("fetch %s: %w", url, lastErr)
+
+	resp, err := do(client, req, opts.RetryCount)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", url, err)
+	}
+	// A rejected request usually means the session expired: log in again once
+	if r, ok := auth.(resetter); ok && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) {
+		resp.Body.Close()
+		r.Reset()
+		if err := auth.Authenticate(ctx, client, req); err != nil {
+			return nil, fmt.Errorf("authenticate %s: %w", url, err)
+		}
+		if resp, err = do(client, req, opts.RetryCount); err != nil {
+			return nil, fmt.Errorf("fetch %s: %w", url, err)
+		}
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	reader, err := decompressBody(resp.Body, resp.Header.Get("Content-Encoding"))
+	if err != nil {
+		return nil, fmt.Errorf("decompress %s: %w", url, err)
+	}
+	body, truncated, err := readLimited(reader, opts.MaxBodySize)
 	if err != nil {
 		return nil, fmt.Errorf("read body: %w", err)
 	}
+	result := &FetchResult{
+		URL:          url,
+		StatusCode:   resp.StatusCode,
+		FetchedAt:    time.Now(),
+		DocumentType: DetectDocumentType(body, resp.Header.Get("Content-Type")),
+		Truncated:    truncated,
+
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
 
-	rawHTML := string(body)
-	title := extractTitle(rawHTML)
-	cleanText := ExtractText(rawHTML)
+	// PDF and DOCX bodies are binary and JSON is not a page; RawHTML stays
+	// empty for them
+	if result.DocumentType != DocumentHTML {
+		if truncated {
+			return nil, fmt.Errorf("extract %s: %s document larger than %d bytes", url, result.DocumentType, len(body))
+		}
+		result.Title, result.CleanText, err = ExtractDocument(body, result.DocumentType)
+		if err != nil {
+			return nil, fmt.Errorf("extract %s: %w", url, err)
+		}
+		result.Duration = time.Since(start)
+		return result, nil
+	}
 
-	return &FetchResult{
-		URL:        url,
-		StatusCode: resp.StatusCode,
-		RawHTML:    rawHTML,
-		CleanText:  cleanText,
-		Title:      title,
-		FetchedAt:  time.Now(),
-		Duration:   time.Since(start),
-	}, nil
+	body, result.Charset = toUTF8(body, resp.Header.Get("Content-Type"))
+	result.RawHTML = string(body)
+	result.Title = extractTitle(result.RawHTML)
+	result.CleanText = ExtractTextMode(result.RawHTML, opts.ExtractMode)
+	result.Duration = time.Since(start)
+	return result, nil
 }
 
-// fetchViaJina uses Jina Reader API (free) to render JS pages and extract content.
-// See: https://r.jina.ai
-func (f *HTTPFetcher) fetchViaJina(ctx context.Context, targetURL string, timeout time.Duration) (string, error) {
-	jinaURL := "https://r.jina.ai/" + targetURL
-
-	client := &http.Client{Timeout: timeout + 15*time.Second} // Jina needs more time
-	req, err := http.NewRequestWithContext(ctx, "GET", jinaURL, nil)
-	if err != nil {
-		return "", err
+// readLimited reads at most limit bytes (DefaultMaxBodySize when zero) and
+// reports whether the body was longer. Reading stops at the limit, so an
+// oversized or decompression-bomb response never lands in memory whole.
+func readLimited(r io.Reader, limit int64) ([]byte, bool, error) {
+	if limit <= 0 {
+		limit = DefaultMaxBodySize
 	}
-	req.Header.Set("X-Return-Format", "markdown")
-	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; WatchBot/2.0)")
-
-	resp, err := client.Do(req)
+	body, err := io.ReadAll(io.LimitReader(r, limit+1))
 	if err != nil {
-		return "", fmt.Errorf("jina fetch: %w", err)
+		return nil, false, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("jina returned %d", resp.StatusCode)
+	if int64(len(body)) > limit {
+		return body[:limit], true, nil
 	}
+	return body, false, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
+// do sends req through client, retrying network errors with a linear backoff.
+func do(client *http.Client, req *http.Request, retries int) (*http.Response, error) {
+	var resp *http.Response
+	var lastErr error
+	for attempt := 0; attempt <= retries; attempt++ {
+		resp, lastErr = client.Do(req)
+		if lastErr == nil {
+			return resp, nil
+		}
+		if attempt < retries {
+			time.Sleep(time.Duration(attempt+1) * time.Second)
+		}
 	}
-	return strings.TrimSpace(string(body)), nil
+	return nil, lastErr
 }
 
 // ExtractText converts HTML to clean structured text, removing navigation/footer/scripts.
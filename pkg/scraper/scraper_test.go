@@ -1,8 +1,23 @@
 package scraper
 
 import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestExtractText_Simple(t *testing.T) {
@@ -54,3 +69,697 @@ func TestExtractTitle(t *testing.T) {
 		t.Errorf("expected 'My Page Title', got '%s'", title)
 	}
 }
+
+func TestExtractReadable(t *testing.T) {
+	html := `<html><body>
+<div class="cookie-banner"><p>We use cookies to improve your experience, please accept all cookies.</p></div>
+<div id="content" class="post">
+  <h1>Pricing update</h1>
+  <p>The Pro plan now costs $49 per month, up from $39, and includes unlimited projects.</p>
+  <p>Enterprise customers keep their current pricing until renewal, according to the announcement.</p>
+</div>
+<div class="related-articles"><p>Read more: ten tips for choosing a plan, and other stories you may like.</p></div>
+</body></html>`
+	text := ExtractReadable(html)
+	if !strings.Contains(text, "Pro plan now costs $49") || !strings.Contains(text, "Enterprise customers") {
+		t.Errorf("expected main content, got: %s", text)
+	}
+	if strings.Contains(text, "cookies") || strings.Contains(text, "Read more") {
+		t.Errorf("expected boilerplate to be removed, got: %s", text)
+	}
+	if ExtractTextMode(html, ExtractModeTags) != ExtractText(html) {
+		t.Error("expected default mode to match ExtractText")
+	}
+}
+
+type stubFetcher struct {
+	calls   int
+	headers map[string]string
+	status  int
+}
+
+func (s *stubFetcher) Fetch(ctx context.Context, url string, opts *FetchOptions) (*FetchResult, error) {
+	s.calls++
+	if opts != nil {
+		s.headers = opts.Headers
+	}
+	if s.status != 0 {
+		return &FetchResult{URL: url, StatusCode: s.status}, nil
+	}
+	return &FetchResult{URL: url, StatusCode: http.StatusOK, RawHTML: "<p>v1</p>", CleanText: "v1", ETag: `"abc"`}, nil
+}
+
+func TestCachingFetcher(t *testing.T) {
+	stub := &stubFetcher{}
+	c := NewCachingFetcher(stub, t.TempDir(), time.Hour)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	if _, err := c.Fetch(context.Background(), "https://example.com", nil); err != nil {
+		t.Fatal(err)
+	}
+	r, err := c.Fetch(context.Background(), "https://example.com", nil)
+	if err != nil || !r.FromCache || r.RawHTML != "<p>v1</p>" || stub.calls != 1 {
+		t.Fatalf("expected fresh cache hit, got %+v (calls=%d, err=%v)", r, stub.calls, err)
+	}
+
+	// After the TTL, the cached ETag is sent and a 304 reuses the body
+	now = now.Add(2 * time.Hour)
+	stub.status = http.StatusNotModified
+	r, err = c.Fetch(context.Background(), "https://example.com", nil)
+	if err != nil || stub.calls != 2 || stub.headers["If-None-Match"] != `"abc"` {
+		t.Fatalf("expected conditional revalidation, got calls=%d headers=%v err=%v", stub.calls, stub.headers, err)
+	}
+	if !r.FromCache || r.CleanText != "v1" {
+		t.Fatalf("expected cached content on 304, got %+v", r)
+	}
+}
+
+func TestHostLimiter_CircuitBreaker(t *testing.T) {
+	stub := &stubFetcher{status: http.StatusTooManyRequests}
+	l := NewHostLimiter(stub, 0, 2, time.Minute)
+	now := time.Now()
+	l.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := l.Fetch(ctx, "https://Example.com/a", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := l.Fetch(ctx, "https://example.com/b", nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected open circuit, got %v", err)
+	}
+	if stub.calls != 2 {
+		t.Fatalf("expected rejected request not to reach the host, got %d calls", stub.calls)
+	}
+	if st := l.States(); len(st) != 1 || st[0].State != BreakerOpen || st[0].Rejected != 1 {
+		t.Fatalf("unexpected state: %+v", st)
+	}
+
+	// After the cooldown one trial request is let through; success closes the breaker
+	now = now.Add(2 * time.Minute)
+	stub.status = 0
+	if _, err := l.Fetch(ctx, "https://example.com/c", nil); err != nil {
+		t.Fatalf("expected trial request to pass, got %v", err)
+	}
+	if st := l.States(); st[0].State != BreakerClosed || st[0].Failures != 0 {
+		t.Fatalf("expected closed breaker, got %+v", st[0])
+	}
+}
+
+func TestExtractMetadata(t *testing.T) {
+	page := `<html><head>
+<title>Fallback</title>
+<link rel="canonical" href="https://example.com/blog/launch">
+<meta property="og:title" content="We launched v2">
+<meta property="og:type" content="article">
+<meta name="description" content="Release notes for v2">
+<script type="application/ld+json">
+{"@context":"https://schema.org","@graph":[
+  {"@type":"Organization","name":"Example"},
+  {"@type":"BlogPosting","headline":"v2","datePublished":"2024-03-05T10:00:00Z","author":{"@type":"Person","name":"Ana"}}
+]}
+</script>
+</head><body><p>hi</p></body></html>`
+
+	md := ExtractMetadata(page)
+	if md.Title != "We launched v2" || md.Type != "article" || md.Description != "Release notes for v2" {
+		t.Fatalf("unexpected meta fields: %+v", md)
+	}
+	if md.CanonicalURL != "https://example.com/blog/launch" {
+		t.Fatalf("canonical = %q", md.CanonicalURL)
+	}
+	if md.Author != "Ana" {
+		t.Fatalf("author = %q", md.Author)
+	}
+	if want := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC); !md.PublishedAt.Equal(want) {
+		t.Fatalf("published = %v", md.PublishedAt)
+	}
+	if types := md.SchemaTypes(); len(types) != 2 || types[1] != "BlogPosting" {
+		t.Fatalf("types = %v", types)
+	}
+}
+
+func TestDecodeGzipGBK(t *testing.T) {
+	// "你好" in GBK, declared only via <meta charset>
+	page := append([]byte(`<html><head><meta charset="gbk"></head><body><p>`), 0xC4, 0xE3, 0xBA, 0xC3)
+	page = append(page, []byte(`</p></body></html>`)...)
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(page)
+	zw.Close()
+
+	r, err := decompressBody(&buf, "gzip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, name := toUTF8(body, "text/html")
+	if name != "gbk" || !strings.Contains(string(out), "你好") {
+		t.Fatalf("charset=%q body=%q", name, out)
+	}
+
+	// Header charset wins; UTF-8 bodies pass through untouched
+	if out, name := toUTF8([]byte("<p>héllo</p>"), "text/html; charset=utf-8"); name != "utf-8" || string(out) != "<p>héllo</p>" {
+		t.Fatalf("charset=%q body=%q", name, out)
+	}
+}
+
+type fetchFunc func(ctx context.Context, url string, opts *FetchOptions) (*FetchResult, error)
+
+func (f fetchFunc) Fetch(ctx context.Context, url string, opts *FetchOptions) (*FetchResult, error) {
+	return f(ctx, url, opts)
+}
+
+func TestFetchAll(t *testing.T) {
+	f := fetchFunc(func(ctx context.Context, url string, _ *FetchOptions) (*FetchResult, error) {
+		switch url {
+		case "bad":
+			return nil, errors.New("boom")
+		case "slow":
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		time.Sleep(time.Millisecond)
+		return &FetchResult{URL: url}, nil
+	})
+
+	urls := []string{"a", "bad", "b", "slow", "c"}
+	results := FetchAll(context.Background(), f, urls, &BatchOptions{Concurrency: 2, Deadline: 50 * time.Millisecond})
+	if len(results) != len(urls) {
+		t.Fatalf("expected %d results, got %d", len(urls), len(results))
+	}
+	for i, r := range results {
+		if r.URL != urls[i] {
+			t.Fatalf("result %d out of order: %s", i, r.URL)
+		}
+	}
+	if results[0].Err != nil || results[0].Result.URL != "a" || results[4].Err != nil {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if results[1].Err == nil {
+		t.Fatal("expected per-URL error for bad")
+	}
+	if !errors.Is(results[3].Err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline for slow, got %v", results[3].Err)
+	}
+}
+
+func TestHTTPFetcher_FormLoginWithPersistentJar(t *testing.T) {
+	logins := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			if r.FormValue("password") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			logins++
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "ok", Path: "/"})
+		case "/changelog":
+			if c, err := r.Cookie("session"); err != nil || c.Value != "ok" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Write([]byte("<html><body><p>v2 released</p></body></html>"))
+		}
+	}))
+	defer srv.Close()
+
+	cookieFile := filepath.Join(t.TempDir(), "cookies.json")
+	jar, err := NewPersistentJar(cookieFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := NewHTTPFetcher()
+	f.SetCookieJar(jar)
+	f.SetAuth(hostOf(srv.URL), &FormLogin{LoginURL: srv.URL + "/login", Fields: map[string]string{"password": "secret"}})
+
+	for i := 0; i < 2; i++ {
+		r, err := f.Fetch(context.Background(), srv.URL+"/changelog", nil)
+		if err != nil || r.StatusCode != http.StatusOK || !strings.Contains(r.CleanText, "v2 released") {
+			t.Fatalf("fetch %d: %+v, %v", i, r, err)
+		}
+	}
+	if logins != 1 {
+		t.Fatalf("expected the session to be reused, got %d logins", logins)
+	}
+
+	// Saved cookies are restored after a restart
+	reloaded, err := NewPersistentJar(cookieFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(srv.URL + "/changelog")
+	if cs := reloaded.Cookies(u); len(cs) != 1 || cs[0].Value != "ok" {
+		t.Fatalf("expected persisted session cookie, got %v", cs)
+	}
+}
+
+type stubReader struct {
+	name string
+	text string
+	err  error
+}
+
+func (r *stubReader) Name() string { return r.name }
+
+func (r *stubReader) Read(context.Context, string, *FetchOptions) (string, error) {
+	return r.text, r.err
+}
+
+func TestHTTPFetcher_FallbackChain(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><body><div id="root"></div></body></html>`))
+	}))
+	defer srv.Close()
+
+	f := NewHTTPFetcher()
+	f.SetFallbacks(
+		&stubReader{name: "jina", err: errors.New("rate limited")},
+		&stubReader{name: "browser", text: "rendered pricing table"},
+		&stubReader{name: "scrapingbee", text: "should not be reached, much longer text"},
+	)
+	r, err := f.Fetch(context.Background(), srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.CleanText != "rendered pricing table" || r.Fallback != "browser" {
+		t.Fatalf("unexpected fallback result: %q via %q", r.CleanText, r.Fallback)
+	}
+
+	if _, err := ParseFallbacks("jina:30s,browser", FallbackConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if readers, err := ParseFallbacks("none", FallbackConfig{}); err != nil || len(readers) != 0 {
+		t.Fatalf("expected no fallbacks, got %v, %v", readers, err)
+	}
+	if _, err := ParseFallbacks("scrapingbee", FallbackConfig{}); err == nil {
+		t.Fatal("expected missing key error")
+	}
+}
+
+func TestBrowserReader_Screenshot(t *testing.T) {
+	// A browser that exits at once fails the screenshot instead of hanging
+	bin := filepath.Join(t.TempDir(), "chrome")
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\necho 'no display' >&2\nexit 1\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	_, err := (&BrowserReader{Path: bin, Timeout: 10 * time.Second}).Screenshot(context.Background(), "https://example.com")
+	if err == nil {
+		t.Fatal("expected an error from a browser that does not start")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("took %s to fail", time.Since(start))
+	}
+}
+
+// minimalPDF builds a one-page PDF showing text, with a valid xref table.
+func minimalPDF(title, text string) []byte {
+	stream := fmt.Sprintf("BT /F1 12 Tf 72 720 Td (%s) Tj ET", text)
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		fmt.Sprintf("<< /Title (%s) >>", title),
+	}
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 6 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+func TestExtractDocuments(t *testing.T) {
+	pdfData := minimalPDF("Price List", "Pro plan 49 USD")
+	if got := DetectDocumentType(pdfData, "application/octet-stream"); got != DocumentPDF {
+		t.Fatalf("expected pdf, got %s", got)
+	}
+	title, text, err := ExtractDocument(pdfData, DocumentPDF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title != "Price List" || !strings.Contains(text, "Pro plan 49 USD") {
+		t.Fatalf("pdf: title=%q text=%q", title, text)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("word/document.xml")
+	w.Write([]byte(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:t>Release 2.0</w:t></w:r></w:p><w:p><w:r><w:t>New</w:t><w:tab/><w:t>SSO</w:t></w:r></w:p></w:body></w:document>`))
+	w, _ = zw.Create("docProps/core.xml")
+	w.Write([]byte(`<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>Changelog</dc:title></cp:coreProperties>`))
+	zw.Close()
+
+	if got := DetectDocumentType(buf.Bytes(), ""); got != DocumentDOCX {
+		t.Fatalf("expected docx, got %s", got)
+	}
+	title, text, err = ExtractDocument(buf.Bytes(), DocumentDOCX)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title != "Changelog" || text != "Release 2.0\nNew\tSSO" {
+		t.Fatalf("docx: title=%q text=%q", title, text)
+	}
+
+	if got := DetectDocumentType([]byte("<html></html>"), "text/html; charset=utf-8"); got != DocumentHTML {
+		t.Fatalf("expected html, got %s", got)
+	}
+
+	for _, ct := range []string{"application/json; charset=utf-8", "application/problem+json"} {
+		if got := DetectDocumentType([]byte(`{}`), ct); got != DocumentJSON {
+			t.Fatalf("%s: expected json, got %s", ct, got)
+		}
+	}
+	_, text, err = ExtractDocument([]byte(`{"b": 1.50, "a": ["<x>"]}`), DocumentJSON)
+	if err != nil || text != "{\n  \"a\": [\n    \"<x>\"\n  ],\n  \"b\": 1.50\n}" {
+		t.Fatalf("json: text=%q err=%v", text, err)
+	}
+}
+
+func TestCrawl(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			fmt.Fprintf(w, "User-agent: *\nDisallow: /private\nSitemap: %s/sitemap.xml\n", srv.URL)
+		case "/sitemap.xml":
+			fmt.Fprintf(w, `<urlset><url><loc>%s/pricing</loc></url><url><loc>%s/private/a</loc></url></urlset>`, srv.URL, srv.URL)
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<title>Home</title><a href="/changelog#v2">Changelog</a><a href="/private/b">x</a>` +
+				`<a href="https://other.example.com/">ext</a><a href="/blog/post" >blog</a><a rel="nofollow" href="/login">login</a>`))
+		case "/changelog":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<title>Changelog</title><a href="/changelog/v1">v1</a>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	noBlog := func(u *url.URL) bool { return !strings.HasPrefix(u.Path, "/blog") }
+	results, err := Crawl(context.Background(), srv.URL+"/", 1, noBlog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range results {
+		got = append(got, strings.TrimPrefix(r.URL, srv.URL))
+	}
+	want := []string{"/", "/pricing", "/changelog"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if results[0].Title != "Home" || results[2].Depth != 1 || results[2].Title != "" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+
+	rules, _ := parseRobots(strings.NewReader("User-agent: *\nDisallow: /\nAllow: /docs$\nDisallow: /*.pdf\n"), "bot")
+	for path, want := range map[string]bool{"/docs": true, "/docs/x": false, "/a.pdf": false} {
+		if rules.allowed(path) != want {
+			t.Errorf("allowed(%s) = %v, want %v", path, !want, want)
+		}
+	}
+}
+
+func TestHTTPFetcher_MaxBodySize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html><body><p>" + strings.Repeat("a", 4096) + "</p></body></html>"))
+	}))
+	defer srv.Close()
+
+	f := NewHTTPFetcher()
+	f.SetFallbacks()
+	opts := DefaultFetchOptions()
+	opts.MaxBodySize = 1024
+	r, err := f.Fetch(context.Background(), srv.URL, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Truncated || len(r.RawHTML) != 1024 {
+		t.Fatalf("expected truncated 1024-byte body, got truncated=%v len=%d", r.Truncated, len(r.RawHTML))
+	}
+
+	opts.MaxBodySize = 0
+	if r, err = f.Fetch(context.Background(), srv.URL, opts); err != nil || r.Truncated {
+		t.Fatalf("expected full body under the default limit, got %v, %v", r, err)
+	}
+}
+
+func TestHTTPFetcher_Politeness(t *testing.T) {
+	var mu sync.Mutex
+	var robotsHits, inFlight, peak int
+	var starts []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			mu.Lock()
+			robotsHits++
+			mu.Unlock()
+			w.Write([]byte("User-agent: *\nDisallow: /private\nCrawl-delay: 0.05\n"))
+			return
+		}
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		starts = append(starts, time.Now())
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		w.Write([]byte("<html><body><p>page</p></body></html>"))
+	}))
+	defer srv.Close()
+
+	f := NewHTTPFetcher()
+	f.SetFallbacks()
+	f.SetPoliteness(Politeness{MaxPerHost: 1})
+	ctx := context.Background()
+
+	if _, err := f.Fetch(ctx, srv.URL+"/private/a", nil); !errors.Is(err, ErrDisallowed) {
+		t.Fatalf("expected ErrDisallowed, got %v", err)
+	}
+	results := FetchAll(ctx, f, []string{srv.URL + "/a", srv.URL + "/b", srv.URL + "/c"}, nil)
+	for _, r := range results {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+	}
+	if robotsHits != 1 || peak != 1 || len(starts) != 3 {
+		t.Fatalf("expected one robots.txt fetch and serial requests, got robots=%d peak=%d requests=%d", robotsHits, peak, len(starts))
+	}
+	for i := 1; i < len(starts); i++ {
+		if gap := starts[i].Sub(starts[i-1]); gap < 45*time.Millisecond {
+			t.Fatalf("expected Crawl-delay between requests, got %v", gap)
+		}
+	}
+
+	// A disallowed URL does not count against the host's circuit breaker
+	l := NewHostLimiter(f, 0, 1, time.Minute)
+	if _, err := l.Fetch(ctx, srv.URL+"/private/b", nil); !errors.Is(err, ErrDisallowed) {
+		t.Fatalf("expected ErrDisallowed, got %v", err)
+	}
+	if st := l.States(); st[0].State != BreakerClosed {
+		t.Fatalf("expected closed breaker, got %+v", st[0])
+	}
+
+	f.SetPoliteness(Politeness{IgnoreRobots: true})
+	if _, err := f.Fetch(ctx, srv.URL+"/private/a", nil); err != nil {
+		t.Fatalf("expected IgnoreRobots to fetch disallowed URLs, got %v", err)
+	}
+	if robotsHits != 1 {
+		t.Fatalf("expected robots.txt not to be read with IgnoreRobots, got %d fetches", robotsHits)
+	}
+
+	rules, _ := parseRobots(strings.NewReader("User-agent: *\nCrawl-delay: 5\n\nUser-agent: devkit\nCrawl-delay: 2\n"), "DevkitSuite/1.0")
+	if rules.delay != 2*time.Second {
+		t.Fatalf("expected the agent-specific Crawl-delay, got %v", rules.delay)
+	}
+}
+
+// testBrowser returns the local Chrome or Chromium, at SCRAPER_BROWSER_PATH
+// or on PATH, skipping the test when there is none.
+func testBrowser(t *testing.T) string {
+	t.Helper()
+	path := os.Getenv("SCRAPER_BROWSER_PATH")
+	bin, err := (&BrowserReader{Path: path}).binary()
+	if err != nil {
+		t.Skip(err)
+	}
+	if _, err := os.Stat(bin); err != nil {
+		t.Skip(err)
+	}
+	return bin
+}
+
+func TestBrowserFetcher(t *testing.T) {
+	bin := testBrowser(t)
+	var mu sync.Mutex
+	var gotUA, gotHeader, gotCookie string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/app" {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		gotUA, gotHeader = r.UserAgent(), r.Header.Get("X-Test")
+		if c, err := r.Cookie("session"); err == nil {
+			gotCookie = c.Value
+		}
+		mu.Unlock()
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "renewed", Path: "/"})
+		// The plans render after the load event
+		fmt.Fprint(w, `<html><head><title>Pricing</title></head><body><main></main><script>
+			setTimeout(() => { document.querySelector("main").innerHTML = '<div id="plans"><h1>Plans</h1><p>Pro costs $49 per month.</p></div>' }, 300)
+		</script></body></html>`)
+	}))
+	defer srv.Close()
+
+	jar, _ := cookiejar.New(nil)
+	target, _ := url.Parse(srv.URL + "/app")
+	jar.SetCookies(target, []*http.Cookie{{Name: "session", Value: "old"}})
+
+	f := &BrowserFetcher{Path: bin, WaitSelector: "#plans", Jar: jar}
+	opts := DefaultFetchOptions()
+	opts.Headers = map[string]string{"X-Test": "yes"}
+	result, err := f.Fetch(context.Background(), target.String(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.StatusCode != 200 || result.Title != "Pricing" || !strings.Contains(result.CleanText, "Pro costs $49") {
+		t.Errorf("unexpected result: status %d, title %q, text %q", result.StatusCode, result.Title, result.CleanText)
+	}
+	mu.Lock()
+	if gotCookie != "old" || gotHeader != "yes" || gotUA != opts.UserAgent {
+		t.Errorf("request sent cookie %q, X-Test %q, user agent %q", gotCookie, gotHeader, gotUA)
+	}
+	mu.Unlock()
+	if c := jar.Cookies(target); len(c) != 1 || c[0].Value != "renewed" {
+		t.Errorf("expected the renewed cookie in the jar, got %v", c)
+	}
+	if result.Screenshot != nil {
+		t.Error("screenshot taken without FetchOptions.Screenshot")
+	}
+
+	opts.Screenshot = true
+	opts.WaitSelector = "main"
+	result, err = f.Fetch(context.Background(), srv.URL+"/missing", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.StatusCode != 404 || !bytes.HasPrefix(result.Screenshot, pngSignature) {
+		t.Errorf("expected status 404 and a PNG, got %d and %q", result.StatusCode, result.Screenshot[:min(len(result.Screenshot), 8)])
+	}
+
+	f.WaitTimeout = 500 * time.Millisecond
+	if _, err := f.Fetch(context.Background(), srv.URL+"/missing", nil); err == nil || !strings.Contains(err.Error(), "no match") {
+		t.Errorf("expected the wait selector to time out, got %v", err)
+	}
+
+	shot, err := (&BrowserReader{Path: bin}).Screenshot(context.Background(), target.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(shot, pngSignature) {
+		t.Errorf("unexpected screenshot %q", shot[:min(len(shot), 8)])
+	}
+}
+
+func TestScreenshotAPI(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query().Get("url")
+		if r.URL.Query().Get("format") != "png" {
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Write([]byte("\xff\xd8\xff\xe0 jpeg"))
+			return
+		}
+		w.Write(append(pngSignature, "rest"...))
+	}))
+	defer srv.Close()
+
+	api := &ScreenshotAPI{URL: srv.URL + "/take?key=k&url={url}&format=png"}
+	data, err := api.Screenshot(context.Background(), "https://example.com/pricing?plan=pro")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "https://example.com/pricing?plan=pro" || !bytes.HasPrefix(data, pngSignature) {
+		t.Errorf("unexpected request url %q or data %q", got, data)
+	}
+
+	api.URL = srv.URL + "/take?url={url}"
+	if _, err := api.Screenshot(context.Background(), "https://example.com/"); err == nil || !strings.Contains(err.Error(), "did not return a PNG") {
+		t.Errorf("expected an error for a JPEG, got %v", err)
+	}
+	api.URL = srv.URL + "/take"
+	if _, err := api.Screenshot(context.Background(), "https://example.com/"); err == nil {
+		t.Error("expected an error without a {url} placeholder")
+	}
+}
+
+func TestSelectHTML(t *testing.T) {
+	page := `<html><body><nav>Home</nav>
+<div class="pricing-table"><p>Pro $20</p><div class="pricing-table"><p>Team $50</p></div></div>
+<main><table><tr><td>Enterprise</td></tr></table></main>
+<footer class="legal">© 2026</footer></body></html>`
+
+	tests := []struct {
+		selector string
+		want     []string
+		notWant  []string
+	}{
+		{".pricing-table", []string{"Pro $20", "Team $50"}, []string{"Home", "Enterprise"}},
+		{"//main//table", []string{"Enterprise"}, []string{"Pro $20"}},
+		{"footer.legal", []string{"© 2026"}, []string{"Home"}},
+	}
+	for _, tt := range tests {
+		got, err := SelectHTML(page, tt.selector)
+		if err != nil {
+			t.Fatalf("SelectHTML(%q): %v", tt.selector, err)
+		}
+		text := ExtractText(got)
+		for _, w := range tt.want {
+			if !strings.Contains(text, w) {
+				t.Errorf("SelectHTML(%q) = %q, want %q", tt.selector, text, w)
+			}
+		}
+		for _, w := range tt.notWant {
+			if strings.Contains(text, w) {
+				t.Errorf("SelectHTML(%q) = %q, should not contain %q", tt.selector, text, w)
+			}
+		}
+		if n := strings.Count(text, "Team $50"); n > 1 {
+			t.Errorf("SelectHTML(%q) kept nested match %d times", tt.selector, n)
+		}
+	}
+
+	if _, err := SelectHTML(page, "#missing"); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("expected ErrNoMatch, got %v", err)
+	}
+	if _, err := ParseSelector("div[["); err == nil {
+		t.Error("expected error for invalid CSS selector")
+	}
+	if _, err := ParseSelector("//div[@"); err == nil {
+		t.Error("expected error for invalid XPath")
+	}
+}
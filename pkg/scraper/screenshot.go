@@ -0,0 +1,103 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Default viewport and time limit for screenshots.
+const (
+	screenshotWidth   = 1280
+	screenshotHeight  = 2000
+	screenshotTimeout = 45 * time.Second
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// FetchScreenshot renders url in a local headless Chrome or Chromium and
+// returns a PNG of the page, down to 6000px.
+func FetchScreenshot(ctx context.Context, url string) ([]byte, error) {
+	return (&BrowserReader{}).Screenshot(ctx, url)
+}
+
+// Screenshot renders url with the reader's browser, as BrowserFetcher does
+// with FetchOptions.Screenshot, and returns a PNG.
+func (r *BrowserReader) Screenshot(ctx context.Context, url string) ([]byte, error) {
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = screenshotTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	opts := DefaultFetchOptions()
+	opts.Timeout = timeout
+	opts.Screenshot = true
+	f := &BrowserFetcher{Path: r.Path, ViewportWidth: screenshotWidth, ViewportHeight: screenshotHeight}
+	result, err := f.Fetch(ctx, url, opts)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(result.Screenshot, pngSignature) {
+		return nil, fmt.Errorf("screenshot %s: browser did not produce a PNG", url)
+	}
+	return result.Screenshot, nil
+}
+
+// maxScreenshotSize caps the PNG read from a screenshot API.
+const maxScreenshotSize = 32 << 20
+
+// ScreenshotAPI takes screenshots with an external render service such as
+// ScreenshotOne, Urlbox or ScrapingBee, for hosts without a local browser.
+type ScreenshotAPI struct {
+	// URL is the request URL with {url} where the page URL goes, escaped,
+	// e.g. "https://api.screenshotone.com/take?access_key=KEY&url={url}&format=png&full_page=true".
+	// The service must answer with a PNG.
+	URL     string
+	Timeout time.Duration // defaults to 45s
+}
+
+// Screenshot asks the service to render pageURL and returns the PNG.
+func (a *ScreenshotAPI) Screenshot(ctx context.Context, pageURL string) ([]byte, error) {
+	if !strings.Contains(a.URL, "{url}") {
+		return nil, fmt.Errorf("screenshot API: URL has no {url} placeholder")
+	}
+	timeout := a.Timeout
+	if timeout <= 0 {
+		timeout = screenshotTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", strings.ReplaceAll(a.URL, "{url}", url.QueryEscape(pageURL)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("screenshot API: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("screenshot %s: %w", pageURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("screenshot %s: API returned %d: %s", pageURL, resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxScreenshotSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("screenshot %s: %w", pageURL, err)
+	}
+	if len(data) > maxScreenshotSize {
+		return nil, fmt.Errorf("screenshot %s: larger than %d MB", pageURL, maxScreenshotSize>>20)
+	}
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, fmt.Errorf("screenshot %s: API did not return a PNG (%s)", pageURL, http.DetectContentType(data))
+	}
+	return data, nil
+}
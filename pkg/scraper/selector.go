@@ -0,0 +1,116 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xpath"
+	"golang.org/x/net/html"
+)
+
+// ErrNoMatch is returned by SelectHTML when the selector matches nothing,
+// usually because the page was redesigned.
+var ErrNoMatch = errors.New("selector matched nothing")
+
+// Selector picks the parts of a page to keep. It is a CSS selector group
+// such as ".pricing-table, #plans", or an XPath expression when it starts
+// with "/" or "(", such as "//main//table".
+type Selector struct {
+	raw   string
+	css   cascadia.SelectorGroup
+	xpath *xpath.Expr
+}
+
+// ParseSelector compiles a CSS selector or XPath expression.
+func ParseSelector(s string) (*Selector, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, errors.New("empty selector")
+	}
+	sel := &Selector{raw: s}
+	var err error
+	if strings.HasPrefix(s, "/") || strings.HasPrefix(s, "(") {
+		sel.xpath, err = xpath.Compile(s)
+	} else {
+		sel.css, err = cascadia.ParseGroup(s)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector %q: %w", s, err)
+	}
+	return sel, nil
+}
+
+// String returns the selector as written.
+func (s *Selector) String() string { return s.raw }
+
+// Select returns the nodes under doc that match, in document order. Nodes
+// inside another match are dropped so no text is kept twice.
+func (s *Selector) Select(doc *html.Node) []*html.Node {
+	var nodes []*html.Node
+	if s.xpath != nil {
+		nodes = htmlquery.QuerySelectorAll(doc, s.xpath)
+	} else {
+		nodes = cascadia.QueryAll(doc, s.css)
+	}
+	matched := make(map[*html.Node]bool, len(nodes))
+	for _, n := range nodes {
+		matched[n] = true
+	}
+	kept := nodes[:0]
+	for _, n := range nodes {
+		nested := false
+		for p := n.Parent; p != nil; p = p.Parent {
+			if matched[p] {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
+
+// SelectHTML returns the HTML of the parts of htmlContent matching selector,
+// one after another, for extracting the text of just those parts. It
+// returns ErrNoMatch when nothing matches.
+func SelectHTML(htmlContent, selector string) (string, error) {
+	sel, err := ParseSelector(selector)
+	if err != nil {
+		return "", err
+	}
+	doc, err := html.Parse(strings.NewReader(htmlContent))
+	if err != nil {
+		return "", fmt.Errorf("parse html: %w", err)
+	}
+	nodes := sel.Select(doc)
+	if len(nodes) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrNoMatch, selector)
+	}
+	var sb strings.Builder
+	for _, n := range nodes {
+		// Text extraction drops page chrome; keep it when explicitly selected
+		if n.Type == html.ElementNode && chromeTags[n.Data] {
+			sb.WriteString("<div>")
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				if err := html.Render(&sb, c); err != nil {
+					return "", fmt.Errorf("render %s: %w", selector, err)
+				}
+			}
+			sb.WriteString("</div>\n")
+			continue
+		}
+		if err := html.Render(&sb, n); err != nil {
+			return "", fmt.Errorf("render %s: %w", selector, err)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+// chromeTags are containers ExtractText and ExtractReadable skip.
+var chromeTags = map[string]bool{"nav": true, "header": true, "footer": true, "aside": true, "form": true}
@@ -0,0 +1,241 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Authenticator prepares requests to a site behind a login. It is called
+// before every request to the site; client shares the fetcher's cookie jar,
+// so session cookies from a form login are reused by later requests.
+type Authenticator interface {
+	Authenticate(ctx context.Context, client *http.Client, req *http.Request) error
+}
+
+// resetter is implemented by authenticators holding session state that must
+// be discarded when the site rejects a request (401/403), e.g. an expired login.
+type resetter interface {
+	Reset()
+}
+
+// BasicAuth sends HTTP Basic credentials.
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// Authenticate sets the Authorization header.
+func (a *BasicAuth) Authenticate(_ context.Context, _ *http.Client, req *http.Request) error {
+	req.SetBasicAuth(a.Username, a.Password)
+	return nil
+}
+
+// BearerAuth sends a static bearer token.
+type BearerAuth struct {
+	Token string
+}
+
+// Authenticate sets the Authorization header.
+func (a *BearerAuth) Authenticate(_ context.Context, _ *http.Client, req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	return nil
+}
+
+// FormLogin posts a login form once and relies on the resulting session
+// cookies. The login is repeated after the site rejects a request.
+type FormLogin struct {
+	LoginURL string
+	Fields   map[string]string // form fields, e.g. username and password
+
+	mu       sync.Mutex
+	loggedIn bool
+}
+
+// Authenticate performs the login if there is no active session.
+func (a *FormLogin) Authenticate(ctx context.Context, client *http.Client, _ *http.Request) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.loggedIn {
+		return nil
+	}
+	if client.Jar == nil {
+		return fmt.Errorf("form login to %s: no cookie jar", a.LoginURL)
+	}
+
+	form := url.Values{}
+	for k, v := range a.Fields {
+		form.Set(k, v)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", a.LoginURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("form login: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("form login to %s: %w", a.LoginURL, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("form login to %s: status %d", a.LoginURL, resp.StatusCode)
+	}
+	a.loggedIn = true
+	return nil
+}
+
+// Reset forgets the session so the next request logs in again.
+func (a *FormLogin) Reset() {
+	a.mu.Lock()
+	a.loggedIn = false
+	a.mu.Unlock()
+}
+
+// authSite is one entry of an auth config file.
+type authSite struct {
+	Type     string            `yaml:"type"` // basic, bearer or form
+	Username string            `yaml:"username"`
+	Password string            `yaml:"password"`
+	Token    string            `yaml:"token"`
+	LoginURL string            `yaml:"login_url"`
+	Fields   map[string]string `yaml:"fields"`
+}
+
+// LoadAuthConfig reads a YAML file mapping hostnames to credentials:
+//
+//	sites:
+//	  partners.example.com:
+//	    type: form
+//	    login_url: https://partners.example.com/login
+//	    fields: {email: bot@example.com, password: ${PARTNER_PASSWORD}}
+//	  docs.example.com:
+//	    type: bearer
+//	    token: ${DOCS_TOKEN}
+//
+// ${VAR} references are expanded from the environment so secrets can stay
+// out of the file.
+func LoadAuthConfig(path string) (map[string]Authenticator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read auth config: %w", err)
+	}
+	var cfg struct {
+		Sites map[string]authSite `yaml:"sites"`
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse auth config: %w", err)
+	}
+
+	auths := make(map[string]Authenticator, len(cfg.Sites))
+	for host, s := range cfg.Sites {
+		host = strings.ToLower(host)
+		switch s.Type {
+		case "basic":
+			auths[host] = &BasicAuth{Username: os.ExpandEnv(s.Username), Password: os.ExpandEnv(s.Password)}
+		case "bearer":
+			auths[host] = &BearerAuth{Token: os.ExpandEnv(s.Token)}
+		case "form":
+			if s.LoginURL == "" {
+				return nil, fmt.Errorf("auth config %s: form login needs login_url", host)
+			}
+			fields := make(map[string]string, len(s.Fields))
+			for k, v := range s.Fields {
+				fields[k] = os.ExpandEnv(v)
+			}
+			auths[host] = &FormLogin{LoginURL: s.LoginURL, Fields: fields}
+		default:
+			return nil, fmt.Errorf("auth config %s: unknown type %q", host, s.Type)
+		}
+	}
+	return auths, nil
+}
+
+// PersistentJar is a cookie jar that saves cookies to a JSON file, so login
+// sessions survive restarts.
+type PersistentJar struct {
+	jar  *cookiejar.Jar
+	path string
+
+	mu      sync.Mutex
+	cookies map[string][]*http.Cookie // origin → cookies as set by the server
+}
+
+// NewPersistentJar creates a jar backed by path, loading any saved cookies.
+// A missing file starts an empty jar.
+func NewPersistentJar(path string) (*PersistentJar, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	j := &PersistentJar{jar: jar, path: path, cookies: make(map[string][]*http.Cookie)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return j, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cookies: %w", err)
+	}
+	if err := json.Unmarshal(data, &j.cookies); err != nil {
+		return nil, fmt.Errorf("parse cookies: %w", err)
+	}
+	now := time.Now()
+	for origin, cookies := range j.cookies {
+		u, err := url.Parse(origin)
+		if err != nil {
+			continue
+		}
+		live := cookies[:0]
+		for _, c := range cookies {
+			if c.Expires.IsZero() || c.Expires.After(now) {
+				live = append(live, c)
+			}
+		}
+		j.cookies[origin] = live
+		jar.SetCookies(u, live)
+	}
+	return j, nil
+}
+
+// SetCookies stores cookies in memory and on disk.
+func (j *PersistentJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.jar.SetCookies(u, cookies)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	origin := u.Scheme + "://" + u.Host
+	existing := j.cookies[origin]
+	for _, c := range cookies {
+		replaced := false
+		for i, e := range existing {
+			if e.Name == c.Name && e.Path == c.Path && e.Domain == c.Domain {
+				existing[i] = c
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			existing = append(existing, c)
+		}
+	}
+	j.cookies[origin] = existing
+
+	// A failed save only costs a re-login after restart
+	if data, err := json.Marshal(j.cookies); err == nil {
+		_ = writeFileAtomic(j.path, data)
+	}
+}
+
+// Cookies returns the cookies to send to u.
+func (j *PersistentJar) Cookies(u *url.URL) []*http.Cookie {
+	return j.jar.Cookies(u)
+}
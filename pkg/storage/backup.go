@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"modernc.org/sqlite"
+)
+
+// backupStepPages is how many pages each backup step copies. Between steps
+// other connections may write; SQLite restarts the copy if they do.
+const backupStepPages = 1024
+
+// sqliteBackuper is implemented by modernc.org/sqlite connections.
+type sqliteBackuper interface {
+	NewBackup(dstURI string) (*sqlite.Backup, error)
+	NewRestore(srcURI string) (*sqlite.Backup, error)
+}
+
+// Backup writes a consistent snapshot of a SQLite database to path with the
+// SQLite online backup API, so it is safe while the database is in use.
+// An existing file at path is overwritten. PostgreSQL databases are backed
+// up with pg_dump instead.
+func (db *DB) Backup(ctx context.Context, path string) error {
+	return db.withBackuper(ctx, "backup", func(b sqliteBackuper) (*sqlite.Backup, error) {
+		return b.NewBackup(path)
+	})
+}
+
+// Restore replaces the contents of a SQLite database with the database
+// file at path, such as one written by Backup.
+func (db *DB) Restore(ctx context.Context, path string) error {
+	return db.withBackuper(ctx, "restore", func(b sqliteBackuper) (*sqlite.Backup, error) {
+		return b.NewRestore(path)
+	})
+}
+
+func (db *DB) withBackuper(ctx context.Context, op string, start func(sqliteBackuper) (*sqlite.Backup, error)) error {
+	if db.driver != SQLite {
+		return fmt.Errorf("%s: not supported for %s databases", op, db.driver)
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn any) error {
+		b, ok := driverConn.(sqliteBackuper)
+		if !ok {
+			return errors.New("driver does not support the SQLite backup API")
+		}
+		bk, err := start(b)
+		if err != nil {
+			return err
+		}
+		for {
+			more, err := bk.Step(backupStepPages)
+			if err == nil && more {
+				err = ctx.Err()
+			}
+			if err != nil || !more {
+				return errors.Join(err, bk.Finish())
+			}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
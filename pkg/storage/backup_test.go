@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackupRestore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	db, err := Open(Config{Driver: SQLite, DSN: filepath.Join(dir, "live.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	count := func() int {
+		t.Helper()
+		var n int
+		if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM notes`).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	if _, err := db.ExecContext(ctx, `CREATE TABLE notes (body TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO notes (body) VALUES ('kept'), ('also kept')`); err != nil {
+		t.Fatal(err)
+	}
+
+	backup := filepath.Join(dir, "backup.db")
+	if err := db.Backup(ctx, backup); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, `DELETE FROM notes`); err != nil {
+		t.Fatal(err)
+	}
+	if n := count(); n != 0 {
+		t.Fatalf("%d notes after delete", n)
+	}
+
+	if err := db.Restore(ctx, backup); err != nil {
+		t.Fatal(err)
+	}
+	if n := count(); n != 2 {
+		t.Errorf("%d notes after restore, want 2", n)
+	}
+	if err := db.Restore(ctx, filepath.Join(dir, "missing", "x.db")); err == nil {
+		t.Error("restore from a missing file succeeded")
+	}
+}
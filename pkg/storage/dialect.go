@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// Queries are written for SQLite, with ? placeholders. On PostgreSQL the
+// methods below rewrite them to $1, $2, ... before they reach the driver,
+// so stores can share one set of SQL. Helpers cover the few constructs that
+// have no common spelling.
+
+// Rebind rewrites the ? placeholders in query for the database's driver.
+// Question marks inside quoted strings, quoted identifiers and comments are
+// left alone.
+func (db *DB) Rebind(query string) string {
+	return rebind(db.driver, query)
+}
+
+func rebind(driver Driver, query string) string {
+	if driver != Postgres || !strings.Contains(query, "?") {
+		return query
+	}
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(query[i+1:], c)
+			if end < 0 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			b.WriteString(query[i : i+end+2])
+			i += end + 1
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			b.WriteString(query[i : i+end+1])
+			i += end
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			b.WriteString(query[i : i+end+4])
+			i += end + 3
+		case c == '?':
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// GroupConcat returns an aggregate joining expr over a group with sep:
+// GROUP_CONCAT on SQLite, string_agg on PostgreSQL. sep is embedded as a
+// string literal and must not contain quotes.
+func (db *DB) GroupConcat(expr, sep string) string {
+	if db.driver == Postgres {
+		return "string_agg(CAST(" + expr + " AS TEXT), '" + sep + "')"
+	}
+	return "GROUP_CONCAT(" + expr + ", '" + sep + "')"
+}
+
+// ExecContext executes a query without returning rows, rebinding its
+// placeholders for the driver.
+func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return db.DB.ExecContext(ctx, db.Rebind(query), args...)
+}
+
+// QueryContext executes a query that returns rows, rebinding its
+// placeholders for the driver.
+func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return db.DB.QueryContext(ctx, db.Rebind(query), args...)
+}
+
+// QueryRowContext executes a query that returns at most one row, rebinding
+// its placeholders for the driver.
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return db.DB.QueryRowContext(ctx, db.Rebind(query), args...)
+}
+
+// InsertID runs an INSERT and returns the id column of the inserted row,
+// using RETURNING, which both drivers support. It returns sql.ErrNoRows
+// when nothing was inserted, as with ON CONFLICT DO NOTHING.
+func (db *DB) InsertID(ctx context.Context, query string, args ...any) (int64, error) {
+	var id int64
+	err := db.QueryRowContext(ctx, query+" RETURNING id", args...).Scan(&id)
+	return id, err
+}
+
+// Tx is a transaction whose queries are rebound like those on DB.
+type Tx struct {
+	*sql.Tx
+	driver Driver
+}
+
+// ExecContext executes a query without returning rows in the transaction.
+func (tx *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return tx.Tx.ExecContext(ctx, rebind(tx.driver, query), args...)
+}
+
+// QueryContext executes a query that returns rows in the transaction.
+func (tx *Tx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return tx.Tx.QueryContext(ctx, rebind(tx.driver, query), args...)
+}
+
+// QueryRowContext executes a query that returns at most one row in the
+// transaction.
+func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return tx.Tx.QueryRowContext(ctx, rebind(tx.driver, query), args...)
+}
+
+// InsertID runs an INSERT in the transaction and returns the id column of
+// the inserted row, like DB.InsertID.
+func (tx *Tx) InsertID(ctx context.Context, query string, args ...any) (int64, error) {
+	var id int64
+	err := tx.QueryRowContext(ctx, query+" RETURNING id", args...).Scan(&id)
+	return id, err
+}
@@ -0,0 +1,50 @@
+package storage
+
+import "testing"
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name, query, want string
+	}{
+		{"no placeholders", `SELECT 1`, `SELECT 1`},
+		{"placeholders", `SELECT * FROM t WHERE a = ? AND b IN (?, ?)`, `SELECT * FROM t WHERE a = $1 AND b IN ($2, $3)`},
+		{"string literal", `SELECT '?' , ? FROM t`, `SELECT '?' , $1 FROM t`},
+		{"doubled quote", `SELECT 'it''s ?' WHERE a = ?`, `SELECT 'it''s ?' WHERE a = $1`},
+		{"quoted identifier", `SELECT "a?b" FROM t WHERE c = ?`, `SELECT "a?b" FROM t WHERE c = $1`},
+		{"line comment", "SELECT ? -- why?\nFROM t WHERE a = ?", "SELECT $1 -- why?\nFROM t WHERE a = $2"},
+		{"trailing line comment", `SELECT ? -- what?`, `SELECT $1 -- what?`},
+		{"block comment", `SELECT /* a? b? */ ? FROM t`, `SELECT /* a? b? */ $1 FROM t`},
+		{"unterminated string", `SELECT ? WHERE a = 'x?`, `SELECT $1 WHERE a = 'x?`},
+		{"unterminated block comment", `SELECT ? /* x?`, `SELECT $1 /* x?`},
+		{"minus is not a comment", `SELECT a - ? FROM t`, `SELECT a - $1 FROM t`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rebind(Postgres, tt.query); got != tt.want {
+				t.Errorf("rebind(Postgres, %q) = %q, want %q", tt.query, got, tt.want)
+			}
+			if got := rebind(SQLite, tt.query); got != tt.query {
+				t.Errorf("rebind(SQLite, %q) = %q, want it unchanged", tt.query, got)
+			}
+		})
+	}
+}
+
+func TestGroupConcat(t *testing.T) {
+	tests := []struct {
+		driver    Driver
+		expr, sep string
+		want      string
+	}{
+		{SQLite, "name", ",", "GROUP_CONCAT(name, ',')"},
+		{SQLite, "c.name", " | ", "GROUP_CONCAT(c.name, ' | ')"},
+		{Postgres, "name", ",", "string_agg(CAST(name AS TEXT), ',')"},
+		{Postgres, "p.id", ";", "string_agg(CAST(p.id AS TEXT), ';')"},
+	}
+	for _, tt := range tests {
+		db := &DB{driver: tt.driver}
+		if got := db.GroupConcat(tt.expr, tt.sep); got != tt.want {
+			t.Errorf("%s GroupConcat(%q, %q) = %q, want %q", tt.driver, tt.expr, tt.sep, got, tt.want)
+		}
+	}
+}
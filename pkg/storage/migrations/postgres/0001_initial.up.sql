@@ -0,0 +1,176 @@
+-- 1. Users & Organizations (Multi-tenant Foundation)
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    email TEXT UNIQUE NOT NULL,
+    password_hash TEXT NOT NULL,
+    name TEXT DEFAULT '',
+    phone TEXT DEFAULT '',
+    company TEXT DEFAULT '',
+    plan TEXT DEFAULT 'free', -- 'free', 'starter', 'pro'
+    plan_status TEXT DEFAULT 'active', -- 'active', 'canceled', 'expired'
+    current_period_end TIMESTAMP,
+    stripe_customer_id TEXT,
+    stripe_subscription_id TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Per-user preferences as key/value pairs ('language', ...)
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(user_id, key),
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS verification_codes (
+    email TEXT NOT NULL,
+    code TEXT NOT NULL,
+    purpose TEXT NOT NULL, -- 'register' or 'reset_password'
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(email, purpose)
+);
+
+CREATE TABLE IF NOT EXISTS order_logs (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    gateway TEXT NOT NULL,           -- 'stripe' | 'alipay'
+    trade_no TEXT UNIQUE NOT NULL,   -- External transaction ID
+    amount INTEGER NOT NULL,         -- Amount in cents
+    status TEXT NOT NULL,            -- 'pending', 'paid', 'failed'
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    paid_at TIMESTAMP,
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+
+-- 2. WatchBot: Competitors & Pages
+CREATE TABLE IF NOT EXISTS competitors (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    domain TEXT NOT NULL,
+    status TEXT DEFAULT 'active', -- 'active', 'frozen'
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE(user_id, domain)
+);
+
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    competitor_id INTEGER, -- Optional: if NULL, applies to all competitors
+    rule_type TEXT NOT NULL, -- 'severity', 'keyword'
+    rule_value TEXT NOT NULL, -- 'high', 'pricing'
+    action TEXT NOT NULL, -- 'email', 'webhook'
+    target_type TEXT DEFAULT 'email',
+    target_id TEXT DEFAULT '',
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY(competitor_id) REFERENCES competitors(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS notification_routes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    channel TEXT NOT NULL, -- 'email', 'telegram', 'webhook', ...
+    target TEXT DEFAULT '', -- address for the channel; '' uses the global default
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE(user_id, channel, target)
+);
+
+CREATE TABLE IF NOT EXISTS pages (
+    id SERIAL PRIMARY KEY,
+    competitor_id INTEGER NOT NULL,
+    url TEXT NOT NULL,
+    page_type TEXT NOT NULL, -- 'pricing', 'features', 'changelog'
+    last_checked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(competitor_id) REFERENCES competitors(id) ON DELETE CASCADE
+);
+
+-- 3. WatchBot: Snapshots & Analyses
+CREATE TABLE IF NOT EXISTS snapshots (
+    id SERIAL PRIMARY KEY,
+    page_id INTEGER NOT NULL,
+    checksum TEXT NOT NULL,
+    content TEXT NOT NULL,
+    captured_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(page_id) REFERENCES pages(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS analyses (
+    id SERIAL PRIMARY KEY,
+    page_id INTEGER NOT NULL,
+    old_snapshot_id INTEGER,
+    new_snapshot_id INTEGER NOT NULL,
+    severity TEXT, -- 'critical', 'important', 'minor', 'none'
+    summary TEXT,
+    raw_diff TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(page_id) REFERENCES pages(id) ON DELETE CASCADE,
+    FOREIGN KEY(old_snapshot_id) REFERENCES snapshots(id) ON DELETE SET NULL,
+    FOREIGN KEY(new_snapshot_id) REFERENCES snapshots(id) ON DELETE CASCADE
+);
+
+-- 4. NewsBot: Global Digestion
+CREATE TABLE IF NOT EXISTS news_articles (
+    id TEXT PRIMARY KEY, -- SHA256 of URL
+    url TEXT UNIQUE NOT NULL,
+    title TEXT NOT NULL,
+    source TEXT NOT NULL,
+    summary TEXT,
+    published_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS news_digests (
+    id SERIAL PRIMARY KEY,
+    date TEXT UNIQUE NOT NULL, -- e.g. "2026-02-20"
+    content_json TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 5. WatchBot: Delivery & Engagement Tracking
+CREATE TABLE IF NOT EXISTS digest_deliveries (
+    id TEXT PRIMARY KEY, -- random digest ID embedded in tracking tokens
+    user_id INTEGER NOT NULL,
+    changes INTEGER NOT NULL DEFAULT 0,
+    sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS engagement_events (
+    id SERIAL PRIMARY KEY,
+    digest_id TEXT NOT NULL,
+    user_id INTEGER NOT NULL,
+    kind TEXT NOT NULL, -- 'open', 'click'
+    url TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_engagement_user ON engagement_events(user_id, kind);
+
+-- Routes that already received a keyed notification (cross-channel dedup)
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    idempotency_key TEXT NOT NULL, -- digest ID
+    channel TEXT NOT NULL,
+    target TEXT NOT NULL DEFAULT '',
+    delivered_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(idempotency_key, channel, target)
+);
+
+-- Non-critical changes held during a user's quiet hours
+CREATE TABLE IF NOT EXISTS held_changes (
+    user_id INTEGER NOT NULL,
+    analysis_id INTEGER NOT NULL,
+    release_at TIMESTAMP NOT NULL, -- start of the user's next delivery window (UTC)
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(user_id, analysis_id),
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY(analysis_id) REFERENCES analyses(id) ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS job_schedules;
+DROP INDEX IF EXISTS idx_jobs_due;
+DROP TABLE IF EXISTS jobs;
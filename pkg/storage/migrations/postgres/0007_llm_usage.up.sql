@@ -0,0 +1,15 @@
+-- LLM usage: one row per call, for per-user accounting and plan quotas.
+-- day is the UTC date, so daily aggregates group the same on every driver.
+CREATE TABLE IF NOT EXISTS llm_usage (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER, -- NULL for system calls, e.g. newsbot digests
+    feature TEXT NOT NULL DEFAULT '',
+    provider TEXT NOT NULL DEFAULT '',
+    model TEXT NOT NULL DEFAULT '',
+    tokens_in INTEGER NOT NULL DEFAULT 0,
+    tokens_out INTEGER NOT NULL DEFAULT 0,
+    cost DOUBLE PRECISION NOT NULL DEFAULT 0,
+    day TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_llm_usage_user_day ON llm_usage(user_id, day);
@@ -0,0 +1,2 @@
+ALTER TABLE pages DROP COLUMN wait_selector;
+ALTER TABLE pages DROP COLUMN browser;
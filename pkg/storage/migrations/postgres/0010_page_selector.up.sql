@@ -0,0 +1,5 @@
+-- The part of a page to monitor, as a CSS selector or XPath expression
+-- ('' monitors the whole page). Snapshots record the selector they were
+-- taken with, so changing it starts a new baseline instead of a diff.
+ALTER TABLE pages ADD COLUMN selector TEXT NOT NULL DEFAULT '';
+ALTER TABLE snapshots ADD COLUMN selector TEXT NOT NULL DEFAULT '';
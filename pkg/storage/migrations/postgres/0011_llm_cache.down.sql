@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_llm_cache_expires;
+DROP TABLE IF EXISTS llm_cache;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_analyses_search;
+DROP INDEX IF EXISTS idx_snapshots_search;
@@ -0,0 +1,5 @@
+-- Full-text search over snapshot content and change analyses, with the
+-- 'simple' configuration so no language's stemming is assumed. The
+-- expressions must match the ones watchbot.Store.Search queries.
+CREATE INDEX IF NOT EXISTS idx_snapshots_search ON snapshots USING GIN (to_tsvector('simple', content));
+CREATE INDEX IF NOT EXISTS idx_analyses_search ON analyses USING GIN (to_tsvector('simple', coalesce(summary, '') || ' ' || coalesce(raw_diff, '')));
@@ -0,0 +1,3 @@
+-- Monthly LLM spending cap in USD a user sets for themselves; 0 is none.
+-- Once the month's cost reaches it, LLM calls on their behalf are refused.
+ALTER TABLE users ADD COLUMN llm_budget DOUBLE PRECISION NOT NULL DEFAULT 0;
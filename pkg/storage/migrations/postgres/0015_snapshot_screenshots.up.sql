@@ -0,0 +1,8 @@
+-- Rendered PNG of the page at each snapshot, for visual diffs. Only the
+-- latest few snapshots of a page keep theirs (see Store.SaveScreenshot).
+CREATE TABLE IF NOT EXISTS snapshot_screenshots (
+    snapshot_id INTEGER PRIMARY KEY,
+    png BYTEA NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(snapshot_id) REFERENCES snapshots(id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+-- The digest a held change was first sent in, when its delivery failed and
+-- it waits for a retry; empty for changes never sent. A retry reuses the ID
+-- so channels that already received the digest skip it.
+ALTER TABLE held_changes ADD COLUMN digest_id TEXT NOT NULL DEFAULT '';
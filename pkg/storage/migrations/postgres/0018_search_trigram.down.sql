@@ -0,0 +1,5 @@
+-- Back to the tsvector indexes of 0012; pg_trgm stays installed.
+DROP INDEX IF EXISTS idx_analyses_search;
+DROP INDEX IF EXISTS idx_snapshots_search;
+CREATE INDEX IF NOT EXISTS idx_snapshots_search ON snapshots USING GIN (to_tsvector('simple', content));
+CREATE INDEX IF NOT EXISTS idx_analyses_search ON analyses USING GIN (to_tsvector('simple', coalesce(summary, '') || ' ' || coalesce(raw_diff, '')));
@@ -0,0 +1,9 @@
+-- Replace the tsvector indexes of 0012 with pg_trgm indexes for ILIKE, which
+-- find text without spaces such as Chinese or Japanese that the 'simple'
+-- configuration cannot split into words. The expressions must match the
+-- ones watchbot.Store.Search queries.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+DROP INDEX IF EXISTS idx_analyses_search;
+DROP INDEX IF EXISTS idx_snapshots_search;
+CREATE INDEX IF NOT EXISTS idx_snapshots_search ON snapshots USING GIN (content gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_analyses_search ON analyses USING GIN ((coalesce(summary, '') || ' ' || coalesce(raw_diff, '')) gin_trgm_ops);
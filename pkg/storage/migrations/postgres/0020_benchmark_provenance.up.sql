@@ -0,0 +1,5 @@
+-- Where each benchmark score came from (benchmarks.SourceType), how sure the
+-- extraction was and free-form notes, e.g. the vendor page it was read from
+ALTER TABLE benchmark_scores ADD COLUMN source_type TEXT DEFAULT '';
+ALTER TABLE benchmark_scores ADD COLUMN confidence REAL DEFAULT 0;
+ALTER TABLE benchmark_scores ADD COLUMN notes TEXT DEFAULT '';
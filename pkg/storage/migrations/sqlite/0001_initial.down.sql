@@ -0,0 +1,17 @@
+-- Drops everything 0001_initial.up.sql creates, dependents first.
+DROP TABLE IF EXISTS held_changes;
+DROP TABLE IF EXISTS notification_deliveries;
+DROP TABLE IF EXISTS engagement_events;
+DROP TABLE IF EXISTS digest_deliveries;
+DROP TABLE IF EXISTS news_digests;
+DROP TABLE IF EXISTS news_articles;
+DROP TABLE IF EXISTS analyses;
+DROP TABLE IF EXISTS snapshots;
+DROP TABLE IF EXISTS pages;
+DROP TABLE IF EXISTS notification_routes;
+DROP TABLE IF EXISTS alert_rules;
+DROP TABLE IF EXISTS competitors;
+DROP TABLE IF EXISTS order_logs;
+DROP TABLE IF EXISTS verification_codes;
+DROP TABLE IF EXISTS user_settings;
+DROP TABLE IF EXISTS users;
@@ -14,6 +14,15 @@ CREATE TABLE IF NOT EXISTS users (
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Per-user preferences as key/value pairs ('language', ...)
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(user_id, key),
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
 
 CREATE TABLE IF NOT EXISTS verification_codes (
     email TEXT NOT NULL,
@@ -64,6 +73,16 @@ CREATE TABLE IF NOT EXISTS alert_rules (
     FOREIGN KEY(competitor_id) REFERENCES competitors(id) ON DELETE CASCADE
 );
 
+CREATE TABLE IF NOT EXISTS notification_routes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    channel TEXT NOT NULL, -- 'email', 'telegram', 'webhook', ...
+    target TEXT DEFAULT '', -- address for the channel; '' uses the global default
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE(user_id, channel, target)
+);
+
 CREATE TABLE IF NOT EXISTS pages (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     competitor_id INTEGER NOT NULL,
@@ -115,3 +134,43 @@ CREATE TABLE IF NOT EXISTS news_digests (
     content_json TEXT NOT NULL,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
+
+-- 5. WatchBot: Delivery & Engagement Tracking
+CREATE TABLE IF NOT EXISTS digest_deliveries (
+    id TEXT PRIMARY KEY, -- random digest ID embedded in tracking tokens
+    user_id INTEGER NOT NULL,
+    changes INTEGER NOT NULL DEFAULT 0,
+    sent_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS engagement_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    digest_id TEXT NOT NULL,
+    user_id INTEGER NOT NULL,
+    kind TEXT NOT NULL, -- 'open', 'click'
+    url TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_engagement_user ON engagement_events(user_id, kind);
+
+-- Routes that already received a keyed notification (cross-channel dedup)
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    idempotency_key TEXT NOT NULL, -- digest ID
+    channel TEXT NOT NULL,
+    target TEXT NOT NULL DEFAULT '',
+    delivered_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(idempotency_key, channel, target)
+);
+
+-- Non-critical changes held during a user's quiet hours
+CREATE TABLE IF NOT EXISTS held_changes (
+    user_id INTEGER NOT NULL,
+    analysis_id INTEGER NOT NULL,
+    release_at DATETIME NOT NULL, -- start of the user's next delivery window (UTC)
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(user_id, analysis_id),
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY(analysis_id) REFERENCES analyses(id) ON DELETE CASCADE
+);
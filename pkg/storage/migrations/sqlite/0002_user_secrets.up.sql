@@ -0,0 +1,9 @@
+-- User-provided credentials (SMTP overrides, API tokens, ...), sealed by storage.SecretBox
+CREATE TABLE IF NOT EXISTS user_secrets (
+    user_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    ciphertext TEXT NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(user_id, name),
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+ALTER TABLE snapshots DROP COLUMN outline;
@@ -0,0 +1,2 @@
+-- Structural outline (differ.Outline as JSON) of the fetched HTML, for structure-aware diffs
+ALTER TABLE snapshots ADD COLUMN outline TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,6 @@
+INSERT INTO user_settings (user_id, key, value) SELECT id, 'language', language FROM users WHERE language != '';
+INSERT INTO user_settings (user_id, key, value) SELECT id, 'timezone', timezone FROM users WHERE timezone != '';
+
+ALTER TABLE users DROP COLUMN notification_email;
+ALTER TABLE users DROP COLUMN language;
+ALTER TABLE users DROP COLUMN timezone;
@@ -0,0 +1,11 @@
+-- Profile fields edited in account settings. Language and time zone move
+-- here from user_settings; notification_email overrides the login email
+-- as the address notifications are sent to.
+ALTER TABLE users ADD COLUMN timezone TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN language TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN notification_email TEXT NOT NULL DEFAULT '';
+
+UPDATE users SET
+    language = COALESCE((SELECT value FROM user_settings WHERE user_id = users.id AND key = 'language'), ''),
+    timezone = COALESCE((SELECT value FROM user_settings WHERE user_id = users.id AND key = 'timezone'), '');
+DELETE FROM user_settings WHERE key IN ('language', 'timezone');
@@ -0,0 +1,27 @@
+-- Jobs: persistent work queue (pkg/jobs). A job is pending until a worker
+-- claims it, running while leased until locked_until, then done or failed.
+CREATE TABLE IF NOT EXISTS jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,
+    payload TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending', -- 'pending', 'running', 'done', 'failed'
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 5,
+    run_at DATETIME NOT NULL,
+    locked_until DATETIME,
+    last_error TEXT,
+    schedule TEXT, -- job_schedules.name that enqueued it
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    finished_at DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_jobs_due ON jobs(kind, status, run_at);
+
+-- Recurring jobs, enqueued when next_run_at passes
+CREATE TABLE IF NOT EXISTS job_schedules (
+    name TEXT PRIMARY KEY,
+    kind TEXT NOT NULL,
+    spec TEXT NOT NULL,
+    payload TEXT NOT NULL DEFAULT '',
+    next_run_at DATETIME NOT NULL,
+    last_run_at DATETIME
+);
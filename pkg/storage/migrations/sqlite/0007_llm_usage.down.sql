@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_llm_usage_user_day;
+DROP TABLE IF EXISTS llm_usage;
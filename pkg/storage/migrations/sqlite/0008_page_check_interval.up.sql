@@ -0,0 +1,3 @@
+-- Seconds between checks of a page in watchbot serve; 0 uses the default
+-- interval (WATCHBOT_CHECK_INTERVAL)
+ALTER TABLE pages ADD COLUMN check_interval INTEGER NOT NULL DEFAULT 0;
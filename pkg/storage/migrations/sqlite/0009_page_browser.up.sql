@@ -0,0 +1,4 @@
+-- Pages rendered in a headless browser instead of fetched over HTTP, and
+-- the CSS selector to wait for before reading them ('' waits for the load event)
+ALTER TABLE pages ADD COLUMN browser BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE pages ADD COLUMN wait_selector TEXT NOT NULL DEFAULT '';
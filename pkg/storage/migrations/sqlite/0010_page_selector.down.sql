@@ -0,0 +1,2 @@
+ALTER TABLE snapshots DROP COLUMN selector;
+ALTER TABLE pages DROP COLUMN selector;
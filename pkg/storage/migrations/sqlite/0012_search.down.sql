@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS analyses_fts_update;
+DROP TRIGGER IF EXISTS analyses_fts_delete;
+DROP TRIGGER IF EXISTS analyses_fts_insert;
+DROP TRIGGER IF EXISTS snapshots_fts_update;
+DROP TRIGGER IF EXISTS snapshots_fts_delete;
+DROP TRIGGER IF EXISTS snapshots_fts_insert;
+DROP TABLE IF EXISTS analyses_fts;
+DROP TABLE IF EXISTS snapshots_fts;
@@ -0,0 +1,31 @@
+-- Full-text search over snapshot content and change analyses. The FTS5
+-- tables index the rows of snapshots and analyses without storing them
+-- again; triggers keep them in sync.
+CREATE VIRTUAL TABLE IF NOT EXISTS snapshots_fts USING fts5(content, content='snapshots', content_rowid='id');
+CREATE VIRTUAL TABLE IF NOT EXISTS analyses_fts USING fts5(summary, raw_diff, content='analyses', content_rowid='id');
+
+CREATE TRIGGER IF NOT EXISTS snapshots_fts_insert AFTER INSERT ON snapshots BEGIN
+    INSERT INTO snapshots_fts(rowid, content) VALUES (new.id, new.content);
+END;
+CREATE TRIGGER IF NOT EXISTS snapshots_fts_delete AFTER DELETE ON snapshots BEGIN
+    INSERT INTO snapshots_fts(snapshots_fts, rowid, content) VALUES ('delete', old.id, old.content);
+END;
+CREATE TRIGGER IF NOT EXISTS snapshots_fts_update AFTER UPDATE OF content ON snapshots BEGIN
+    INSERT INTO snapshots_fts(snapshots_fts, rowid, content) VALUES ('delete', old.id, old.content);
+    INSERT INTO snapshots_fts(rowid, content) VALUES (new.id, new.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS analyses_fts_insert AFTER INSERT ON analyses BEGIN
+    INSERT INTO analyses_fts(rowid, summary, raw_diff) VALUES (new.id, new.summary, new.raw_diff);
+END;
+CREATE TRIGGER IF NOT EXISTS analyses_fts_delete AFTER DELETE ON analyses BEGIN
+    INSERT INTO analyses_fts(analyses_fts, rowid, summary, raw_diff) VALUES ('delete', old.id, old.summary, old.raw_diff);
+END;
+CREATE TRIGGER IF NOT EXISTS analyses_fts_update AFTER UPDATE OF summary, raw_diff ON analyses BEGIN
+    INSERT INTO analyses_fts(analyses_fts, rowid, summary, raw_diff) VALUES ('delete', old.id, old.summary, old.raw_diff);
+    INSERT INTO analyses_fts(rowid, summary, raw_diff) VALUES (new.id, new.summary, new.raw_diff);
+END;
+
+-- Index the rows that already exist
+INSERT INTO snapshots_fts(snapshots_fts) VALUES ('rebuild');
+INSERT INTO analyses_fts(analyses_fts) VALUES ('rebuild');
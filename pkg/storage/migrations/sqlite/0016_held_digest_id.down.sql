@@ -0,0 +1 @@
+ALTER TABLE held_changes DROP COLUMN digest_id;
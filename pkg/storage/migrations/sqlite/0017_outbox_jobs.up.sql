@@ -0,0 +1,12 @@
+-- Failed notifications are retried as notify.retry jobs (pkg/jobs). Move the
+-- ones still waiting or given up on, whose payloads jobs decode as they are,
+-- and drop the outbox.
+INSERT INTO jobs (kind, payload, status, attempts, max_attempts, run_at, last_error, created_at)
+SELECT 'notify.retry', payload,
+       CASE status WHEN 'failed' THEN 'failed' ELSE 'pending' END,
+       attempts, max_attempts, next_attempt_at, last_error, created_at
+FROM notification_outbox
+WHERE status != 'sent';
+
+DROP INDEX IF EXISTS idx_notification_outbox_due;
+DROP TABLE IF EXISTS notification_outbox;
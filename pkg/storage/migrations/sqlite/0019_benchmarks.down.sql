@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS benchmark_runs;
+DROP TABLE IF EXISTS benchmark_scrapes;
+DROP TABLE IF EXISTS benchmark_model_candidates;
+DROP TABLE IF EXISTS benchmark_quarantine;
+DROP TABLE IF EXISTS benchmark_unmatched_models;
+DROP TABLE IF EXISTS benchmark_models;
+DROP TABLE IF EXISTS benchmark_model_pricing;
+DROP TABLE IF EXISTS benchmark_score_history;
+DROP TABLE IF EXISTS benchmark_scores;
@@ -0,0 +1,104 @@
+-- Benchmark tracker tables (pkg/benchmarks). benchmarks.NewStore created
+-- them before this migration, so existing databases may already have them.
+CREATE TABLE IF NOT EXISTS benchmark_scores (
+    id INTEGER PRIMARY KEY,
+    benchmark_id TEXT NOT NULL,
+    model_name TEXT NOT NULL,
+    model_provider TEXT NOT NULL,
+    variant TEXT DEFAULT '',
+    score REAL NOT NULL,
+    source_url TEXT DEFAULT '',
+    scraped_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(benchmark_id, model_name, variant)
+);
+
+-- One row per score per scrape day; benchmark_scores keeps only the latest
+CREATE TABLE IF NOT EXISTS benchmark_score_history (
+    benchmark_id TEXT NOT NULL,
+    model_name TEXT NOT NULL,
+    variant TEXT DEFAULT '',
+    scrape_date TEXT NOT NULL,
+    score REAL NOT NULL,
+    source_url TEXT DEFAULT '',
+    PRIMARY KEY (benchmark_id, model_name, variant, scrape_date)
+);
+
+-- Backfill history from scores stored before it existed. WHERE true keeps
+-- SQLite from reading ON CONFLICT as a join constraint.
+INSERT INTO benchmark_score_history (benchmark_id, model_name, variant, scrape_date, score, source_url)
+    SELECT benchmark_id, model_name, variant, substr(CAST(scraped_at AS TEXT), 1, 10), score, source_url
+    FROM benchmark_scores WHERE true
+    ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS benchmark_model_pricing (
+    model_name TEXT PRIMARY KEY,
+    input_price REAL DEFAULT 0,
+    output_price REAL DEFAULT 0,
+    tokens_per_sec REAL DEFAULT 0,
+    source_url TEXT DEFAULT '',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS benchmark_models (
+    name TEXT PRIMARY KEY,
+    provider TEXT NOT NULL,
+    thinking TEXT DEFAULT '',
+    gen TEXT DEFAULT 'latest',
+    display_order INTEGER DEFAULT 0
+);
+
+-- Leaderboard names no tracked model or alias matched
+CREATE TABLE IF NOT EXISTS benchmark_unmatched_models (
+    source TEXT NOT NULL,
+    raw_name TEXT NOT NULL,
+    last_seen DATETIME NOT NULL,
+    PRIMARY KEY (source, raw_name)
+);
+
+-- Scores held back by validation until an operator releases them
+CREATE TABLE IF NOT EXISTS benchmark_quarantine (
+    benchmark_id TEXT NOT NULL,
+    model_name TEXT NOT NULL,
+    model_provider TEXT NOT NULL,
+    variant TEXT DEFAULT '',
+    score REAL NOT NULL,
+    previous REAL DEFAULT 0,
+    reason TEXT NOT NULL,
+    source_url TEXT DEFAULT '',
+    source_type TEXT DEFAULT '',
+    confidence REAL DEFAULT 0,
+    notes TEXT DEFAULT '',
+    quarantined_at DATETIME NOT NULL,
+    PRIMARY KEY (benchmark_id, model_name, variant)
+);
+
+-- Unmatched models that score well, proposed as models to track
+CREATE TABLE IF NOT EXISTS benchmark_model_candidates (
+    source TEXT NOT NULL,
+    raw_name TEXT NOT NULL,
+    high_scores INTEGER NOT NULL,
+    summary TEXT DEFAULT '',
+    first_seen DATETIME NOT NULL,
+    last_seen DATETIME NOT NULL,
+    PRIMARY KEY (source, raw_name)
+);
+
+-- One row per parser per scrape
+CREATE TABLE IF NOT EXISTS benchmark_scrapes (
+    id INTEGER PRIMARY KEY,
+    parser TEXT NOT NULL,
+    started_at DATETIME NOT NULL,
+    duration_ms INTEGER DEFAULT 0,
+    scores_added INTEGER DEFAULT 0,
+    scores_updated INTEGER DEFAULT 0,
+    quarantined INTEGER DEFAULT 0,
+    error TEXT DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_benchmark_scrapes_parser ON benchmark_scrapes(parser, started_at);
+
+CREATE TABLE IF NOT EXISTS benchmark_runs (
+    id INTEGER PRIMARY KEY,
+    finished_at DATETIME NOT NULL,
+    scores INTEGER DEFAULT 0,
+    changes INTEGER DEFAULT 0
+);
@@ -0,0 +1,3 @@
+ALTER TABLE benchmark_scores DROP COLUMN notes;
+ALTER TABLE benchmark_scores DROP COLUMN confidence;
+ALTER TABLE benchmark_scores DROP COLUMN source_type;
@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SecretKeyEnv holds the base64-encoded 32-byte master key SecretBoxFromEnv
+// uses, e.g. the output of `openssl rand -base64 32`.
+const SecretKeyEnv = "DEVKIT_SECRET_KEY"
+
+// sealedPrefix marks values sealed by a SecretBox.
+const sealedPrefix = "enc:v1:"
+
+// ErrNoSecretBox is returned when a secret is stored or read without a
+// SecretBox configured.
+var ErrNoSecretBox = errors.New("secret encryption is not configured (set " + SecretKeyEnv + ")")
+
+// KeyWrapper encrypts and decrypts data keys with a master key. The local
+// AES implementation keeps the master key in memory; a KMS implementation
+// sends the data key to the KMS instead.
+type KeyWrapper interface {
+	// KeyID identifies the master key; it is stored with each secret so
+	// that rotated keys can still open older ones.
+	KeyID() string
+	WrapKey(ctx context.Context, dataKey []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// SecretBox encrypts secrets at rest with envelope encryption: each value
+// gets a fresh AES-256-GCM data key, which is itself encrypted by the master
+// key. Sealed values are printable strings, safe to store in TEXT columns.
+type SecretBox struct {
+	primary KeyWrapper
+	keys    map[string]KeyWrapper
+}
+
+// NewSecretBox seals with primary and opens values sealed with primary or
+// any of the retired keys.
+func NewSecretBox(primary KeyWrapper, retired ...KeyWrapper) *SecretBox {
+	b := &SecretBox{primary: primary, keys: map[string]KeyWrapper{primary.KeyID(): primary}}
+	for _, k := range retired {
+		b.keys[k.KeyID()] = k
+	}
+	return b
+}
+
+// SecretBoxFromEnv returns a SecretBox using the local master key in
+// DEVKIT_SECRET_KEY, or nil if the variable is unset.
+func SecretBoxFromEnv() (*SecretBox, error) {
+	encoded := strings.TrimSpace(os.Getenv(SecretKeyEnv))
+	if encoded == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", SecretKeyEnv, err)
+	}
+	kek, err := NewAESKeyWrapper(key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", SecretKeyEnv, err)
+	}
+	return NewSecretBox(kek), nil
+}
+
+// Seal encrypts plaintext.
+func (b *SecretBox) Seal(ctx context.Context, plaintext string) (string, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	wrapped, err := b.primary.WrapKey(ctx, dataKey)
+	if err != nil {
+		return "", fmt.Errorf("wrap data key: %w", err)
+	}
+	ciphertext, err := gcmSeal(dataKey, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	return sealedPrefix + b.primary.KeyID() + ":" + enc.EncodeToString(wrapped) + ":" + enc.EncodeToString(ciphertext), nil
+}
+
+// Open decrypts a value returned by Seal.
+func (b *SecretBox) Open(ctx context.Context, sealed string) (string, error) {
+	parts := strings.Split(strings.TrimPrefix(sealed, sealedPrefix), ":")
+	if !IsSealed(sealed) || len(parts) != 3 {
+		return "", errors.New("open secret: not a sealed value")
+	}
+	kek, ok := b.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("open secret: unknown master key %q", parts[0])
+	}
+	enc := base64.RawURLEncoding
+	wrapped, err := enc.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("open secret: %w", err)
+	}
+	ciphertext, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("open secret: %w", err)
+	}
+	dataKey, err := kek.UnwrapKey(ctx, wrapped)
+	if err != nil {
+		return "", fmt.Errorf("unwrap data key: %w", err)
+	}
+	plaintext, err := gcmOpen(dataKey, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("open secret: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// IsSealed reports whether s looks like a value returned by Seal, so that
+// stores can tell encrypted values from ones written before encryption.
+func IsSealed(s string) bool {
+	return strings.HasPrefix(s, sealedPrefix)
+}
+
+// aesKeyWrapper wraps data keys with a local AES-256-GCM master key.
+type aesKeyWrapper struct {
+	id  string
+	key []byte
+}
+
+// NewAESKeyWrapper returns a KeyWrapper for a 32-byte master key. Its ID is
+// derived from the key.
+func NewAESKeyWrapper(key []byte) (KeyWrapper, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("master key must be 32 bytes, got %d", len(key))
+	}
+	sum := sha256.Sum256(key)
+	return &aesKeyWrapper{id: hex.EncodeToString(sum[:4]), key: key}, nil
+}
+
+func (w *aesKeyWrapper) KeyID() string { return w.id }
+
+func (w *aesKeyWrapper) WrapKey(_ context.Context, dataKey []byte) ([]byte, error) {
+	return gcmSeal(w.key, dataKey)
+}
+
+func (w *aesKeyWrapper) UnwrapKey(_ context.Context, wrapped []byte) ([]byte, error) {
+	return gcmOpen(w.key, wrapped)
+}
+
+// gcmSeal encrypts with AES-GCM, prefixing the random nonce.
+func gcmSeal(key, plaintext []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func gcmOpen(key, data []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
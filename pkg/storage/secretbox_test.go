@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func testKey(t *testing.T, fill byte) KeyWrapper {
+	t.Helper()
+	kek, err := NewAESKeyWrapper(bytes.Repeat([]byte{fill}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return kek
+}
+
+func TestSecretBoxRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	box := NewSecretBox(testKey(t, 1))
+
+	for _, plaintext := range []string{"https://hooks.example.com/T000/B000/XXXX", "", "密钥 🔑"} {
+		sealed, err := box.Seal(ctx, plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !IsSealed(sealed) || strings.Contains(sealed, "hooks.example.com") {
+			t.Errorf("sealed value %q", sealed)
+		}
+		got, err := box.Open(ctx, sealed)
+		if err != nil || got != plaintext {
+			t.Errorf("Open(Seal(%q)) = %q, %v", plaintext, got, err)
+		}
+	}
+
+	// Each seal uses a fresh data key and nonce
+	a, _ := box.Seal(ctx, "same")
+	b, _ := box.Seal(ctx, "same")
+	if a == b {
+		t.Error("sealing the same value twice gave the same result")
+	}
+	if IsSealed("https://hooks.example.com") {
+		t.Error("a plain URL reported as sealed")
+	}
+}
+
+func TestSecretBoxTamper(t *testing.T) {
+	ctx := context.Background()
+	box := NewSecretBox(testKey(t, 1))
+	sealed, err := box.Seal(ctx, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(strings.TrimPrefix(sealed, sealedPrefix), ":")
+
+	// flip changes one byte of an encoded part
+	flip := func(part string) string {
+		data, err := base64.RawURLEncoding.DecodeString(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data[len(data)-1] ^= 1
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	join := func(keyID, wrapped, ciphertext string) string {
+		return sealedPrefix + keyID + ":" + wrapped + ":" + ciphertext
+	}
+	for name, value := range map[string]string{
+		"ciphertext":      join(parts[0], parts[1], flip(parts[2])),
+		"wrapped key":     join(parts[0], flip(parts[1]), parts[2]),
+		"swapped parts":   join(parts[0], parts[2], parts[1]),
+		"truncated":       join(parts[0], parts[1], parts[2][:8]),
+		"not base64":      join(parts[0], parts[1], "!!"+parts[2]),
+		"missing part":    sealedPrefix + parts[0] + ":" + parts[2],
+		"unsealed":        "secret",
+		"other version":   "enc:v2:" + strings.Join(parts, ":"),
+		"empty":           "",
+		"only the prefix": sealedPrefix,
+	} {
+		if got, err := box.Open(ctx, value); err == nil {
+			t.Errorf("%s: Open = %q, want an error", name, got)
+		}
+	}
+}
+
+func TestSecretBoxKeys(t *testing.T) {
+	ctx := context.Background()
+	oldKey, newKey := testKey(t, 1), testKey(t, 2)
+	if oldKey.KeyID() == newKey.KeyID() {
+		t.Fatal("different keys share an ID")
+	}
+	sealedOld, err := NewSecretBox(oldKey).Seal(ctx, "from before the rotation")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Unknown key: a box without the old key cannot open it
+	_, err = NewSecretBox(newKey).Open(ctx, sealedOld)
+	if err == nil || !strings.Contains(err.Error(), "unknown master key") {
+		t.Errorf("Open with an unknown key ID: %v", err)
+	}
+
+	// Retired key: opens old values, while new ones are sealed with the
+	// primary key
+	rotated := NewSecretBox(newKey, oldKey)
+	if got, err := rotated.Open(ctx, sealedOld); err != nil || got != "from before the rotation" {
+		t.Errorf("Open with the retired key = %q, %v", got, err)
+	}
+	sealedNew, err := rotated.Seal(ctx, "after")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sealedNew, sealedPrefix+newKey.KeyID()+":") {
+		t.Errorf("sealed with %q, want the primary key %s", sealedNew, newKey.KeyID())
+	}
+	if _, err := NewSecretBox(oldKey).Open(ctx, sealedNew); err == nil {
+		t.Error("the retired key opened a value sealed after the rotation")
+	}
+
+	// A key under the wrong ID fails authentication instead of opening
+	forged := strings.Replace(sealedOld, oldKey.KeyID(), newKey.KeyID(), 1)
+	if _, err := rotated.Open(ctx, forged); err == nil {
+		t.Error("opened a value under another key's ID")
+	}
+}
+
+func TestSecretBoxFromEnv(t *testing.T) {
+	t.Setenv(SecretKeyEnv, "")
+	if box, err := SecretBoxFromEnv(); box != nil || err != nil {
+		t.Errorf("unset: %v, %v; want no box", box, err)
+	}
+	t.Setenv(SecretKeyEnv, "not base64!")
+	if _, err := SecretBoxFromEnv(); err == nil {
+		t.Error("expected invalid base64 to be refused")
+	}
+	t.Setenv(SecretKeyEnv, base64.StdEncoding.EncodeToString(make([]byte, 16)))
+	if _, err := SecretBoxFromEnv(); err == nil {
+		t.Error("expected a 16-byte key to be refused")
+	}
+
+	t.Setenv(SecretKeyEnv, " "+base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))+"\n")
+	box, err := SecretBoxFromEnv()
+	if err != nil || box == nil {
+		t.Fatalf("valid key: %v, %v", box, err)
+	}
+	// The same key opens what a box built from it directly sealed
+	sealed, err := NewSecretBox(testKey(t, 1)).Seal(context.Background(), "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := box.Open(context.Background(), sealed); err != nil || got != "x" {
+		t.Errorf("Open = %q, %v", got, err)
+	}
+}
@@ -1,11 +1,20 @@
 // Package storage provides a database abstraction layer supporting SQLite and PostgreSQL.
+//
+// The SQLite driver (modernc.org/sqlite, registered as "sqlite") is linked
+// in for online backups. For PostgreSQL, binaries import a driver that
+// registers itself as "postgres", such as github.com/lib/pq.
 package storage
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -17,10 +26,51 @@ const (
 	Postgres Driver = "postgres"
 )
 
-// Config holds database configuration.
+// Config holds database configuration. Zero pool settings use the
+// defaults below.
 type Config struct {
 	Driver Driver `yaml:"driver" json:"driver"`
 	DSN    string `yaml:"dsn" json:"dsn"` // Data Source Name
+
+	MaxOpenConns    int           `yaml:"max_open_conns" json:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" json:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" json:"conn_max_lifetime"`
+
+	// BusyTimeout is how long a SQLite connection waits on a locked
+	// database before failing with "database is locked".
+	BusyTimeout time.Duration `yaml:"busy_timeout" json:"busy_timeout"`
+}
+
+// Pool defaults.
+const (
+	DefaultMaxOpenConns    = 25
+	DefaultMaxIdleConns    = 5
+	DefaultConnMaxLifetime = 5 * time.Minute
+	DefaultBusyTimeout     = 5 * time.Second
+)
+
+// WithPoolEnv returns cfg with unset pool settings read from env vars:
+//
+//	DB_MAX_OPEN_CONNS     — maximum open connections
+//	DB_MAX_IDLE_CONNS     — maximum idle connections
+//	DB_CONN_MAX_LIFETIME  — connection lifetime, e.g. 5m
+//	DB_BUSY_TIMEOUT       — SQLite lock wait, e.g. 5s
+//
+// Malformed values are ignored.
+func (cfg Config) WithPoolEnv() Config {
+	if cfg.MaxOpenConns == 0 {
+		cfg.MaxOpenConns, _ = strconv.Atoi(os.Getenv("DB_MAX_OPEN_CONNS"))
+	}
+	if cfg.MaxIdleConns == 0 {
+		cfg.MaxIdleConns, _ = strconv.Atoi(os.Getenv("DB_MAX_IDLE_CONNS"))
+	}
+	if cfg.ConnMaxLifetime == 0 {
+		cfg.ConnMaxLifetime, _ = time.ParseDuration(os.Getenv("DB_CONN_MAX_LIFETIME"))
+	}
+	if cfg.BusyTimeout == 0 {
+		cfg.BusyTimeout, _ = time.ParseDuration(os.Getenv("DB_BUSY_TIMEOUT"))
+	}
+	return cfg
 }
 
 // DB wraps a *sql.DB with additional utilities.
@@ -42,15 +92,19 @@ func Open(cfg Config) (*DB, error) {
 		return nil, fmt.Errorf("unsupported database driver: %s", cfg.Driver)
 	}
 
-	db, err := sql.Open(driverName, cfg.DSN)
+	dsn := cfg.DSN
+	if cfg.Driver == SQLite {
+		dsn = sqliteDSN(dsn, cmp.Or(cfg.BusyTimeout, DefaultBusyTimeout))
+	}
+	db, err := sql.Open(driverName, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("open database: %w", err)
 	}
 
 	// Set connection pool settings
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	db.SetMaxOpenConns(cmp.Or(cfg.MaxOpenConns, DefaultMaxOpenConns))
+	db.SetMaxIdleConns(cmp.Or(cfg.MaxIdleConns, DefaultMaxIdleConns))
+	db.SetConnMaxLifetime(cmp.Or(cfg.ConnMaxLifetime, DefaultConnMaxLifetime))
 
 	// Verify connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -67,27 +121,71 @@ func Open(cfg Config) (*DB, error) {
 	}, nil
 }
 
-// Driver returns the database driver type.
-func (db *DB) DriverType() Driver {
-	return db.driver
+// sqliteDSN adds the busy_timeout and foreign_keys pragmas to a SQLite DSN
+// unless it already sets them. Pragmas in the DSN apply to every
+// connection the pool opens.
+func sqliteDSN(dsn string, busyTimeout time.Duration) string {
+	_, query, _ := strings.Cut(dsn, "?")
+	params, _ := url.ParseQuery(query)
+	pragmas := strings.ToLower(strings.Join(params["_pragma"], ";"))
+
+	var add []string
+	if !strings.Contains(pragmas, "busy_timeout") {
+		add = append(add, fmt.Sprintf("_pragma=busy_timeout(%d)", busyTimeout.Milliseconds()))
+	}
+	if !strings.Contains(pragmas, "foreign_keys") {
+		add = append(add, "_pragma=foreign_keys(1)")
+	}
+	if len(add) == 0 {
+		return dsn
+	}
+	sep := "?"
+	if strings.Contains(dsn, "?") {
+		sep = "&"
+	}
+	return dsn + sep + strings.Join(add, "&")
 }
 
-// Migrate runs the given SQL schema on the database.
-func (db *DB) Migrate(ctx context.Context, schema string) error {
-	_, err := db.ExecContext(ctx, schema)
-	if err != nil {
-		return fmt.Errorf("migrate: %w", err)
+// PoolStats is a snapshot of the connection pool, for diagnosing
+// contention such as "database is locked" errors.
+type PoolStats struct {
+	MaxOpen           int           `json:"max_open"`
+	Open              int           `json:"open"`
+	InUse             int           `json:"in_use"`
+	Idle              int           `json:"idle"`
+	WaitCount         int64         `json:"wait_count"`          // waits for a free connection
+	WaitDuration      time.Duration `json:"wait_duration_ns"`    // total time spent waiting
+	MaxIdleClosed     int64         `json:"max_idle_closed"`     // closed by MaxIdleConns
+	MaxLifetimeClosed int64         `json:"max_lifetime_closed"` // closed by ConnMaxLifetime
+}
+
+// PoolStats returns the current connection pool statistics.
+func (db *DB) PoolStats() PoolStats {
+	s := db.Stats()
+	return PoolStats{
+		MaxOpen:           s.MaxOpenConnections,
+		Open:              s.OpenConnections,
+		InUse:             s.InUse,
+		Idle:              s.Idle,
+		WaitCount:         s.WaitCount,
+		WaitDuration:      s.WaitDuration,
+		MaxIdleClosed:     s.MaxIdleClosed,
+		MaxLifetimeClosed: s.MaxLifetimeClosed,
 	}
-	db.logger.Info("database migration completed")
-	return nil
+}
+
+// Driver returns the database driver type.
+func (db *DB) DriverType() Driver {
+	return db.driver
 }
 
 // Transaction wraps a function in a database transaction.
-func (db *DB) Transaction(ctx context.Context, fn func(tx *sql.Tx) error) error {
-	tx, err := db.BeginTx(ctx, nil)
+func (db *DB) Transaction(ctx context.Context, fn func(tx *Tx) error) error {
+	sqlTx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
 	}
+	tx := &Tx{Tx: sqlTx, driver: db.driver}
 
 	if err := fn(tx); err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
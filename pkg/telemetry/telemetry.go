@@ -0,0 +1,295 @@
+// Package telemetry reports coarse, anonymous usage of the DevKit Suite
+// binaries: which commands run, which LLM provider type is configured and
+// which class of error ended a command. It never sends arguments, URLs,
+// emails, prompts or any other content.
+//
+// Telemetry is off until the user opts in with "telemetry enable" (or
+// DEVKIT_TELEMETRY=1), and DO_NOT_TRACK or DEVKIT_TELEMETRY=0 always turn
+// it off. Events go to DEVKIT_TELEMETRY_ENDPOINT; with no endpoint nothing
+// is sent.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Env vars read by the package.
+const (
+	EnvEnabled  = "DEVKIT_TELEMETRY"          // 1/0: overrides the stored choice
+	EnvEndpoint = "DEVKIT_TELEMETRY_ENDPOINT" // where events are POSTed
+	EnvFile     = "DEVKIT_TELEMETRY_FILE"     // consent file, see StatePath
+	EnvDNT      = "DO_NOT_TRACK"              // any non-empty value other than 0 disables telemetry
+)
+
+// Event kinds. Every command run is reported once as KindCommand; a command
+// that fails is reported again as KindError with its ErrorClass.
+const (
+	KindCommand = "command"
+	KindError   = "error"
+)
+
+// Event is one usage report.
+type Event struct {
+	InstallID   string    `json:"install_id"` // random, not derived from the machine or user
+	Version     string    `json:"version"`
+	OS          string    `json:"os"`
+	Arch        string    `json:"arch"`
+	Kind        string    `json:"kind"`
+	Command     string    `json:"command"`                // command path, e.g. "watchbot check"
+	LLMProvider string    `json:"llm_provider,omitempty"` // provider type only, e.g. "openai"
+	ErrorClass  string    `json:"error_class,omitempty"`  // see ErrorClass
+	Time        time.Time `json:"time"`
+}
+
+// State is the stored consent.
+type State struct {
+	Enabled   bool      `json:"enabled"`
+	InstallID string    `json:"install_id,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// StatePath returns the consent file: EnvFile, or telemetry.json in the
+// user config dir.
+func StatePath() (string, error) {
+	if path := os.Getenv(EnvFile); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "devkit-suite", "telemetry.json"), nil
+}
+
+// LoadState reads the consent file. A missing file means not opted in.
+func LoadState() (State, error) {
+	var st State
+	path, err := StatePath()
+	if err != nil {
+		return st, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return st, err
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return st, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return st, nil
+}
+
+// SetEnabled records the user's choice. Enabling assigns a random install
+// ID if there is none; disabling drops it, so a later opt-in starts fresh.
+func SetEnabled(enabled bool) (State, error) {
+	st, err := LoadState()
+	if err != nil {
+		return st, err
+	}
+	st.Enabled = enabled
+	st.UpdatedAt = time.Now().UTC()
+	if !enabled {
+		st.InstallID = ""
+	} else if st.InstallID == "" {
+		st.InstallID = newInstallID()
+	}
+
+	path, err := StatePath()
+	if err != nil {
+		return st, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return st, err
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return st, err
+	}
+	return st, os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+func newInstallID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Status describes whether telemetry is on and why.
+type Status struct {
+	Enabled   bool
+	Reason    string // what decided Enabled
+	Endpoint  string
+	InstallID string
+}
+
+// CurrentStatus resolves the env vars and the consent file. DO_NOT_TRACK
+// wins, then DEVKIT_TELEMETRY, then the stored choice.
+func CurrentStatus() (Status, error) {
+	s := Status{Endpoint: os.Getenv(EnvEndpoint)}
+	st, err := LoadState()
+	s.InstallID = st.InstallID
+
+	if v := os.Getenv(EnvDNT); v != "" && v != "0" {
+		s.Reason = EnvDNT + " is set"
+		return s, err
+	}
+	if v := os.Getenv(EnvEnabled); v != "" {
+		on, perr := strconv.ParseBool(v)
+		if perr != nil {
+			return s, fmt.Errorf("%s: invalid value %q", EnvEnabled, v)
+		}
+		s.Enabled = on
+		s.Reason = EnvEnabled + "=" + v
+		if on && s.InstallID == "" {
+			// opted in by env only, e.g. in a container: per-process ID
+			s.InstallID = newInstallID()
+		}
+		return s, err
+	}
+	if err != nil {
+		s.Reason = "consent file unreadable"
+		return s, err
+	}
+	s.Enabled = st.Enabled
+	if st.Enabled {
+		s.Reason = "enabled with 'telemetry enable'"
+	} else if st.UpdatedAt.IsZero() {
+		s.Reason = "not enabled (opt-in)"
+	} else {
+		s.Reason = "disabled with 'telemetry disable'"
+	}
+	return s, nil
+}
+
+// ErrorClass maps an error to a coarse class without looking at its
+// message: "", "canceled", "timeout", "network", "filesystem" or "other".
+func ErrorClass(err error) string {
+	var netErr net.Error
+	var pathErr *fs.PathError
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.As(err, &pathErr):
+		// before net.Error: the syscall.Errno inside satisfies it too
+		return "filesystem"
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return "timeout"
+		}
+		return "network"
+	default:
+		return "other"
+	}
+}
+
+// Client sends events in the background. The zero value and a nil Client
+// drop every event.
+type Client struct {
+	endpoint  string
+	installID string
+	version   string
+	http      *http.Client
+	wg        sync.WaitGroup
+}
+
+// New returns a client for the current status, or nil when telemetry is off
+// or has no endpoint. Errors resolving the status also yield nil: telemetry
+// must never get in the way of a command.
+func New(version string) *Client {
+	s, err := CurrentStatus()
+	if err != nil || !s.Enabled || s.Endpoint == "" {
+		return nil
+	}
+	return &Client{
+		endpoint:  s.Endpoint,
+		installID: s.InstallID,
+		version:   version,
+		http:      &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// RecordCommand reports that a command ran.
+func (c *Client) RecordCommand(command string) {
+	c.record(KindCommand, command, nil)
+}
+
+// RecordError reports that a command failed. Only the ErrorClass of err is
+// sent.
+func (c *Client) RecordError(command string, err error) {
+	if err == nil {
+		return
+	}
+	c.record(KindError, command, err)
+}
+
+func (c *Client) record(kind, command string, cmdErr error) {
+	if c == nil {
+		return
+	}
+	ev := Event{
+		InstallID:   c.installID,
+		Version:     c.version,
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		Kind:        kind,
+		Command:     command,
+		LLMProvider: os.Getenv("LLM_PROVIDER"),
+		ErrorClass:  ErrorClass(cmdErr),
+		Time:        time.Now().UTC(),
+	}
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		_ = c.send(ev)
+	}()
+}
+
+func (c *Client) send(ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Post(c.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Flush waits up to timeout for pending events to be sent.
+func (c *Client) Flush(timeout time.Duration) {
+	if c == nil {
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+}
@@ -0,0 +1,220 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// collector is a telemetry endpoint that keeps the bodies it receives.
+type collector struct {
+	mu     sync.Mutex
+	bodies [][]byte
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	c.mu.Lock()
+	c.bodies = append(c.bodies, body)
+	c.mu.Unlock()
+}
+
+func (c *collector) received() [][]byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.bodies
+}
+
+// testEnv points telemetry at a fresh consent file and a collector, and
+// clears the env vars that decide whether it is on.
+func testEnv(t *testing.T) *collector {
+	t.Helper()
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	t.Cleanup(srv.Close)
+	t.Setenv(EnvFile, filepath.Join(t.TempDir(), "telemetry.json"))
+	t.Setenv(EnvEndpoint, srv.URL)
+	t.Setenv(EnvEnabled, "")
+	t.Setenv(EnvDNT, "")
+	t.Setenv("LLM_PROVIDER", "openai")
+	return c
+}
+
+func TestDisabledSendsNothing(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T)
+	}{
+		{"never opted in", func(t *testing.T) {}},
+		{"opted out", func(t *testing.T) {
+			mustSetEnabled(t, true)
+			mustSetEnabled(t, false)
+		}},
+		{"DEVKIT_TELEMETRY=0 overrides consent", func(t *testing.T) {
+			mustSetEnabled(t, true)
+			t.Setenv(EnvEnabled, "0")
+		}},
+		{"DO_NOT_TRACK overrides consent", func(t *testing.T) {
+			mustSetEnabled(t, true)
+			t.Setenv(EnvDNT, "1")
+		}},
+		{"DO_NOT_TRACK overrides DEVKIT_TELEMETRY=1", func(t *testing.T) {
+			t.Setenv(EnvEnabled, "1")
+			t.Setenv(EnvDNT, "true")
+		}},
+		{"no endpoint", func(t *testing.T) {
+			mustSetEnabled(t, true)
+			t.Setenv(EnvEndpoint, "")
+		}},
+		{"invalid DEVKIT_TELEMETRY", func(t *testing.T) {
+			mustSetEnabled(t, true)
+			t.Setenv(EnvEnabled, "yes please")
+		}},
+		{"unreadable consent file", func(t *testing.T) {
+			if err := os.WriteFile(os.Getenv(EnvFile), []byte("{not json"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := testEnv(t)
+			tt.setup(t)
+			client := New("v1.2.3")
+			if client != nil {
+				t.Error("New returned a client")
+			}
+			client.RecordCommand("watchbot check")
+			client.RecordError("watchbot check", context.Canceled)
+			client.Flush(time.Second)
+			if n := len(c.received()); n != 0 {
+				t.Errorf("%d events sent", n)
+			}
+		})
+	}
+}
+
+func mustSetEnabled(t *testing.T, enabled bool) {
+	t.Helper()
+	if _, err := SetEnabled(enabled); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEventPayload(t *testing.T) {
+	c := testEnv(t)
+	mustSetEnabled(t, true)
+	client := New("v1.2.3")
+	if client == nil {
+		t.Fatal("New returned nil after opting in")
+	}
+
+	// Everything personal here is in the error message, which is never sent
+	pathErr := &fs.PathError{Op: "open", Path: "/home/alice/acme-pricing.html", Err: fs.ErrPermission}
+	client.RecordCommand("watchbot check")
+	client.RecordError("watchbot check", fmt.Errorf("notify alice@example.com: %w", pathErr))
+	client.RecordError("watchbot check", nil) // success is not an error event
+	client.Flush(5 * time.Second)
+
+	bodies := c.received()
+	if len(bodies) != 2 {
+		t.Fatalf("%d events sent, want 2", len(bodies))
+	}
+	allowed := []string{"arch", "command", "error_class", "install_id", "kind", "llm_provider", "os", "time", "version"}
+	kinds := map[string]string{}
+	for _, body := range bodies {
+		for _, secret := range []string{"alice", "example.com", "/home", "acme"} {
+			if strings.Contains(string(body), secret) {
+				t.Errorf("payload %s contains %q", body, secret)
+			}
+		}
+		var fields map[string]any
+		if err := json.Unmarshal(body, &fields); err != nil {
+			t.Fatal(err)
+		}
+		for k := range fields {
+			if !contains(allowed, k) {
+				t.Errorf("payload has unexpected field %q", k)
+			}
+		}
+
+		var ev Event
+		if err := json.Unmarshal(body, &ev); err != nil {
+			t.Fatal(err)
+		}
+		if len(ev.InstallID) != 32 || ev.Version != "v1.2.3" || ev.Command != "watchbot check" || ev.LLMProvider != "openai" {
+			t.Errorf("event = %+v", ev)
+		}
+		kinds[ev.Kind] = ev.ErrorClass
+	}
+	if want := map[string]string{KindCommand: "", KindError: "filesystem"}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("kinds and error classes = %v, want %v", kinds, want)
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSetEnabled(t *testing.T) {
+	testEnv(t)
+	on, err := SetEnabled(true)
+	if err != nil || !on.Enabled || on.InstallID == "" {
+		t.Fatalf("SetEnabled(true) = %+v, %v", on, err)
+	}
+	if again, _ := SetEnabled(true); again.InstallID != on.InstallID {
+		t.Error("enabling again changed the install ID")
+	}
+	if off, _ := SetEnabled(false); off.Enabled || off.InstallID != "" {
+		t.Errorf("SetEnabled(false) = %+v, want the install ID dropped", off)
+	}
+	if st, _ := SetEnabled(true); st.InstallID == on.InstallID {
+		t.Error("opting in again reused the old install ID")
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{context.Canceled, "canceled"},
+		{fmt.Errorf("fetch: %w", context.DeadlineExceeded), "timeout"},
+		{&fs.PathError{Op: "open", Path: "x", Err: fs.ErrNotExist}, "filesystem"},
+		{&timeoutError{}, "timeout"},
+		{fmt.Errorf("dial: %w", &netError{}), "network"},
+		{fmt.Errorf("anything else"), "other"},
+	}
+	for _, tt := range tests {
+		if got := ErrorClass(tt.err); got != tt.want {
+			t.Errorf("ErrorClass(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+type netError struct{}
+
+func (*netError) Error() string   { return "connection refused" }
+func (*netError) Timeout() bool   { return false }
+func (*netError) Temporary() bool { return false }
+
+type timeoutError struct{ netError }
+
+func (*timeoutError) Timeout() bool { return true }